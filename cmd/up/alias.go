@@ -0,0 +1,95 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/upbound/up/internal/config"
+)
+
+// expandArgs expands a configured alias in the first argument, then adds the
+// configured default flags of the selected command that are not supplied on
+// the command line. The arguments are returned unchanged if they cannot be
+// parsed, so that parsing errors are reported for what the user typed.
+func expandArgs(k *kong.Kong, conf *config.Config, args []string) []string {
+	if conf == nil || len(args) == 0 {
+		return args
+	}
+	args = expandAlias(k, conf.Aliases, args)
+	if len(conf.CommandDefaults) == 0 {
+		return args
+	}
+
+	// Trace parses the arguments without running hooks or applying values.
+	ctx, err := kong.Trace(k, args)
+	if err != nil || ctx.Error != nil {
+		return args
+	}
+	supplied := map[string]bool{}
+	for _, p := range ctx.Path {
+		if p.Flag != nil {
+			supplied[p.Flag.Name] = true
+			if p.Flag.Short != 0 {
+				supplied[string(p.Flag.Short)] = true
+			}
+		}
+	}
+	defaults := []string{}
+	for _, d := range conf.CommandDefaults[commandName(ctx.Path)] {
+		name, _, _ := strings.Cut(strings.TrimLeft(d, "-"), "=")
+		if !supplied[name] {
+			defaults = append(defaults, d)
+		}
+	}
+	if len(defaults) == 0 {
+		return args
+	}
+
+	// Flags must precede a "--" terminator.
+	i := len(args)
+	for j, a := range args {
+		if a == "--" {
+			i = j
+			break
+		}
+	}
+	expanded := make([]string, 0, len(args)+len(defaults))
+	expanded = append(expanded, args[:i]...)
+	expanded = append(expanded, defaults...)
+	return append(expanded, args[i:]...)
+}
+
+// expandAlias replaces the first argument with its expansion if it is an
+// alias that does not shadow a command.
+func expandAlias(k *kong.Kong, aliases map[string]string, args []string) []string {
+	exp, ok := aliases[args[0]]
+	if !ok {
+		return args
+	}
+	for _, c := range k.Model.Children {
+		if c.Name == args[0] {
+			return args
+		}
+		for _, a := range c.Aliases {
+			if a == args[0] {
+				return args
+			}
+		}
+	}
+	return append(strings.Fields(exp), args[1:]...)
+}
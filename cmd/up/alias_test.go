@@ -0,0 +1,89 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/config"
+)
+
+type testListCmd struct {
+	Selector string `short:"l"`
+}
+
+type testCLI struct {
+	Format string
+
+	ControlPlane struct {
+		List testListCmd `cmd:""`
+	} `cmd:"" name:"controlplane" aliases:"ctp"`
+}
+
+func TestExpandArgs(t *testing.T) {
+	conf := &config.Config{
+		Aliases: map[string]string{
+			"ls":  "controlplane list",
+			"ctp": "configuration",
+		},
+		CommandDefaults: map[string][]string{
+			"controlplane list": {"--format=json", "--selector=env=prod"},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   []string
+		want   []string
+	}{
+		"Alias": {
+			reason: "An alias should be expanded and the defaults of the expanded command added.",
+			args:   []string{"ls"},
+			want:   []string{"controlplane", "list", "--format=json", "--selector=env=prod"},
+		},
+		"AliasShadowsCommand": {
+			reason: "An alias should not shadow a command or command alias.",
+			args:   []string{"ctp", "list"},
+			want:   []string{"ctp", "list", "--format=json", "--selector=env=prod"},
+		},
+		"SuppliedFlag": {
+			reason: "Defaults should not be added for flags supplied on the command line.",
+			args:   []string{"controlplane", "list", "-l", "env=dev"},
+			want:   []string{"controlplane", "list", "-l", "env=dev", "--format=json"},
+		},
+		"Terminator": {
+			reason: "Defaults should be added before a -- terminator.",
+			args:   []string{"controlplane", "list", "--"},
+			want:   []string{"controlplane", "list", "--format=json", "--selector=env=prod", "--"},
+		},
+		"ParseError": {
+			reason: "Arguments that cannot be parsed should be returned unchanged.",
+			args:   []string{"controlplane", "list", "--unknown"},
+			want:   []string{"controlplane", "list", "--unknown"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			k := kong.Must(&testCLI{})
+			got := expandArgs(k, conf, tc.args)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nexpandArgs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
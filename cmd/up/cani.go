@@ -0,0 +1,140 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	canIAllowed = "yes"
+	canIDenied  = "no"
+
+	errFmtCanINotSpace = "checking permissions requires a space profile, profile %q is of type %q"
+	errCanIReview      = "unable to review access"
+)
+
+// canIResourceAliases maps the names users know Space resources by to the
+// resources they are authorized as.
+var canIResourceAliases = map[string]schema.GroupResource{
+	"controlplane":  resources.ControlPlaneGVR.GroupResource(),
+	"controlplanes": resources.ControlPlaneGVR.GroupResource(),
+	"ctp":           resources.ControlPlaneGVR.GroupResource(),
+}
+
+// AfterApply constructs and binds an access review client for the Space of
+// the current profile.
+func (c *canICmd) AfterApply(kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags)
+	if err != nil {
+		return err
+	}
+	if upCtx.Profile.Type != config.SpaceProfileType {
+		return errors.Errorf(errFmtCanINotSpace, upCtx.ProfileName, upCtx.Profile.Type)
+	}
+	kubeconfig, err := kube.GetKubeConfigWithContext(upCtx.Profile.Kubeconfig, upCtx.Profile.KubeContext)
+	if err != nil {
+		return err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	kongCtx.BindTo(client.AuthorizationV1().SelfSubjectAccessReviews(), (*authv1client.SelfSubjectAccessReviewInterface)(nil))
+	return nil
+}
+
+// canICmd checks whether the identity of the current profile is allowed to
+// perform an action.
+type canICmd struct {
+	Verb     string `arg:"" required:"" help:"Verb of the action, such as get, create, or delete."`
+	Resource string `arg:"" required:"" help:"Resource of the action, such as controlplane, or RESOURCE.GROUP for any other resource."`
+	Name     string `arg:"" optional:"" help:"Name of the resource. Checks all resources of the type if omitted."`
+
+	Namespace string `short:"n" default:"default" help:"Namespace of the resource."`
+
+	// Common Upbound API configuration
+	Flags upbound.Flags `embed:""`
+}
+
+// Run executes the can-i command. It exits with a non-zero code if the action
+// is denied so that scripts can rely on its result.
+func (c *canICmd) Run(ctx context.Context, kongCtx *kong.Context, p pterm.TextPrinter, sar authv1client.SelfSubjectAccessReviewInterface) error {
+	allowed, reason, err := canI(ctx, sar, c.attributes())
+	if err != nil {
+		return err
+	}
+	res := canIAllowed
+	if !allowed {
+		res = canIDenied
+	}
+	if reason != "" {
+		res += " - " + reason
+	}
+	p.Println(res)
+	if !allowed {
+		kongCtx.Exit(1)
+	}
+	return nil
+}
+
+// attributes returns the resource attributes to review.
+func (c *canICmd) attributes() *authv1.ResourceAttributes {
+	gr, ok := canIResourceAliases[strings.ToLower(c.Resource)]
+	if !ok {
+		gr = schema.ParseGroupResource(c.Resource)
+	}
+	return &authv1.ResourceAttributes{
+		Namespace: c.Namespace,
+		Verb:      c.Verb,
+		Group:     gr.Group,
+		Resource:  gr.Resource,
+		Name:      c.Name,
+	}
+}
+
+// canI reviews whether the current identity is allowed the action described
+// by attrs, returning the reason for the decision if the authorizer gave one.
+func canI(ctx context.Context, sar authv1client.SelfSubjectAccessReviewInterface, attrs *authv1.ResourceAttributes) (bool, string, error) {
+	res, err := sar.Create(ctx, &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", errors.Wrap(err, errCanIReview)
+	}
+	reason := res.Status.Reason
+	if res.Status.EvaluationError != "" {
+		reason = strings.TrimSpace(reason + " " + res.Status.EvaluationError)
+	}
+	return res.Status.Allowed && !res.Status.Denied, reason, nil
+}
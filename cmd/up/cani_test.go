@@ -0,0 +1,129 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestCanIAttributes(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cmd    canICmd
+		want   *authv1.ResourceAttributes
+	}{
+		"ControlPlaneAlias": {
+			reason: "Control plane aliases should resolve to the Spaces API group.",
+			cmd:    canICmd{Verb: "delete", Resource: "ctp", Name: "my-ctp", Namespace: "default"},
+			want: &authv1.ResourceAttributes{
+				Namespace: "default",
+				Verb:      "delete",
+				Group:     "spaces.upbound.io",
+				Resource:  "controlplanes",
+				Name:      "my-ctp",
+			},
+		},
+		"ResourceWithGroup": {
+			reason: "Other resources should be parsed as RESOURCE.GROUP.",
+			cmd:    canICmd{Verb: "get", Resource: "secrets", Namespace: "team-a"},
+			want: &authv1.ResourceAttributes{
+				Namespace: "team-a",
+				Verb:      "get",
+				Resource:  "secrets",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.cmd.attributes()); diff != "" {
+				t.Errorf("\n%s\nattributes(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanI(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		allowed bool
+		reason  string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		status authv1.SubjectAccessReviewStatus
+		err    error
+		want   want
+	}{
+		"Allowed": {
+			reason: "An allowed review should be reported with its reason.",
+			status: authv1.SubjectAccessReviewStatus{Allowed: true, Reason: `RBAC: allowed by RoleBinding "admins"`},
+			want: want{
+				allowed: true,
+				reason:  `RBAC: allowed by RoleBinding "admins"`,
+			},
+		},
+		"Denied": {
+			reason: "An explicitly denied review should not be allowed.",
+			status: authv1.SubjectAccessReviewStatus{Allowed: true, Denied: true, Reason: "denied by webhook"},
+			want: want{
+				reason: "denied by webhook",
+			},
+		},
+		"EvaluationError": {
+			reason: "Evaluation errors should be included in the reason.",
+			status: authv1.SubjectAccessReviewStatus{EvaluationError: "role not found"},
+			want: want{
+				reason: "role not found",
+			},
+		},
+		"ErrCreate": {
+			reason: "An error creating the review should be returned.",
+			err:    errBoom,
+			want: want{
+				err: errors.Wrap(errBoom, errCanIReview),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("create", "selfsubjectaccessreviews", func(ktesting.Action) (bool, runtime.Object, error) {
+				return true, &authv1.SelfSubjectAccessReview{Status: tc.status}, tc.err
+			})
+
+			allowed, reason, err := canI(context.Background(), client.AuthorizationV1().SelfSubjectAccessReviews(), &authv1.ResourceAttributes{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncanI(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.allowed, allowed); diff != "" {
+				t.Errorf("\n%s\ncanI(...): -want allowed, +got allowed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, reason); diff != "" {
+				t.Errorf("\n%s\ncanI(...): -want reason, +got reason:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
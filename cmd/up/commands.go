@@ -0,0 +1,167 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/upbound/up/internal/feature"
+)
+
+// commandInfo describes a command, its arguments and flags, and its
+// subcommands.
+type commandInfo struct {
+	Name      string        `json:"name"`
+	Path      string        `json:"path"`
+	Help      string        `json:"help,omitempty"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Maturity  string        `json:"maturity,omitempty"`
+	Hidden    bool          `json:"hidden,omitempty"`
+	Arguments []valueInfo   `json:"arguments,omitempty"`
+	Flags     []valueInfo   `json:"flags,omitempty"`
+	Commands  []commandInfo `json:"commands,omitempty"`
+}
+
+// valueInfo describes a flag or a positional argument.
+type valueInfo struct {
+	Name     string   `json:"name"`
+	Short    string   `json:"short,omitempty"`
+	Help     string   `json:"help,omitempty"`
+	Type     string   `json:"type"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+	Env      []string `json:"env,omitempty"`
+	Hidden   bool     `json:"hidden,omitempty"`
+}
+
+// commandsCmd prints the command tree of up.
+type commandsCmd struct {
+	JSON   bool `name:"json" help:"Print the full command tree, including arguments and flags, as JSON."`
+	Hidden bool `help:"Include hidden commands and flags."`
+}
+
+// Help returns the help text for the commands command.
+func (c *commandsCmd) Help() string {
+	return `
+Lists every command of up. With --json, the names, help, types, defaults, and
+environment variables of all arguments and flags are included, so that other
+tools can generate documentation or user interfaces from the CLI itself.`
+}
+
+// Run executes the commands command.
+func (c *commandsCmd) Run(kongCtx *kong.Context) error {
+	root := describeCommand(kongCtx.Model.Node, c.Hidden)
+	if c.JSON {
+		e := json.NewEncoder(kongCtx.Stdout)
+		e.SetIndent("", "  ")
+		return e.Encode(root)
+	}
+	w := tabwriter.NewWriter(kongCtx.Stdout, 0, 4, 3, ' ', 0)
+	printCommands(w, root.Commands)
+	return w.Flush()
+}
+
+// printCommands prints the path and help of each command that can be run,
+// one per line.
+func printCommands(w io.Writer, cmds []commandInfo) {
+	for _, c := range cmds {
+		if len(c.Commands) == 0 {
+			fmt.Fprintf(w, "%s\t%s\n", c.Path, c.Help)
+			continue
+		}
+		printCommands(w, c.Commands)
+	}
+}
+
+// commandPath returns the path of the command of the node, such as up robot
+// list. Unlike kong's FullPath it omits aliases, so that it can be run as is.
+func commandPath(n *kong.Node) string {
+	parts := []string{}
+	for ; n != nil; n = n.Parent {
+		switch {
+		case n.Parent == nil, n.Type == kong.CommandNode:
+			parts = append([]string{n.Name}, parts...)
+		case n.Type == kong.ArgumentNode:
+			parts = append([]string{"<" + n.Name + ">"}, parts...)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeCommand describes the node and its descendants. Hidden commands,
+// arguments, and flags are only included if hidden is true.
+func describeCommand(n *kong.Node, hidden bool) commandInfo {
+	c := commandInfo{
+		Name:    n.Name,
+		Path:    commandPath(n),
+		Help:    n.Help,
+		Aliases: n.Aliases,
+		Hidden:  n.Hidden,
+	}
+	if m := feature.GetMaturity(n); m != feature.Stable {
+		c.Maturity = string(m)
+	}
+	for _, p := range n.Positional {
+		c.Arguments = append(c.Arguments, describeValue(p))
+	}
+	for _, f := range n.Flags {
+		if f.Hidden && !hidden {
+			continue
+		}
+		v := describeValue(f.Value)
+		if f.Short != 0 {
+			v.Short = string(f.Short)
+		}
+		v.Env, v.Hidden = f.Envs, f.Hidden
+		c.Flags = append(c.Flags, v)
+	}
+	for _, child := range n.Children {
+		if child.Hidden && !hidden {
+			continue
+		}
+		c.Commands = append(c.Commands, describeCommand(child, hidden))
+	}
+	return c
+}
+
+func describeValue(v *kong.Value) valueInfo {
+	i := valueInfo{
+		Name:     v.Name,
+		Help:     v.Help,
+		Default:  v.Default,
+		Required: v.Required,
+	}
+	// Types set with the type tag, such as existingfile or counter, are more
+	// descriptive than the Go type of the target.
+	switch {
+	case v.Tag != nil && v.Tag.Type != "":
+		i.Type = v.Tag.Type
+	case v.Target.IsValid():
+		i.Type = v.Target.Type().String()
+	}
+	if v.Enum != "" {
+		for _, e := range strings.Split(v.Enum, ",") {
+			i.Enum = append(i.Enum, strings.TrimSpace(e))
+		}
+	}
+	return i
+}
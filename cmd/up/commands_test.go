@@ -0,0 +1,107 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-cmp/cmp"
+)
+
+type catalogLeafCmd struct {
+	Name string `arg:"" help:"Name of the thing."`
+
+	Force  bool   `short:"f" help:"Force it."`
+	Mode   string `enum:"fast, slow" default:"fast" env:"MODE" help:"Mode to run in."`
+	Secret string `hidden:"" help:"Hidden flag."`
+}
+
+func (c *catalogLeafCmd) Run() error { return nil }
+
+type catalogGroupCmd struct {
+	Delete catalogLeafCmd `cmd:"" aliases:"rm" help:"Delete a thing."`
+	Old    catalogLeafCmd `cmd:"" hidden:"" help:"Hidden command."`
+}
+
+type catalogCLI struct {
+	Thing catalogGroupCmd `cmd:"" maturity:"alpha" help:"Manage things."`
+}
+
+func TestDescribeCommand(t *testing.T) {
+	leaf := func(name string, aliases []string, help string, hidden bool) commandInfo {
+		return commandInfo{
+			Name:    name,
+			Path:    "up thing " + name,
+			Help:    help,
+			Aliases: aliases,
+			Hidden:  hidden,
+			Arguments: []valueInfo{
+				{Name: "name", Help: "Name of the thing.", Type: "string", Required: true},
+			},
+			Flags: []valueInfo{
+				{Name: "force", Short: "f", Help: "Force it.", Type: "bool"},
+				{Name: "mode", Help: "Mode to run in.", Type: "string", Default: "fast", Enum: []string{"fast", "slow"}, Env: []string{"MODE"}},
+			},
+		}
+	}
+	withHidden := func(c commandInfo) commandInfo {
+		c.Flags = append(c.Flags, valueInfo{Name: "secret", Help: "Hidden flag.", Type: "string", Hidden: true})
+		return c
+	}
+	cases := map[string]struct {
+		reason string
+		hidden bool
+		want   []commandInfo
+	}{
+		"Visible": {
+			reason: "Hidden commands and flags should be omitted by default.",
+			want: []commandInfo{{
+				Name:     "thing",
+				Path:     "up thing",
+				Help:     "Manage things.",
+				Maturity: "alpha",
+				Commands: []commandInfo{leaf("delete", []string{"rm"}, "Delete a thing.", false)},
+			}},
+		},
+		"Hidden": {
+			reason: "Hidden commands and flags should be included if requested.",
+			hidden: true,
+			want: []commandInfo{{
+				Name:     "thing",
+				Path:     "up thing",
+				Help:     "Manage things.",
+				Maturity: "alpha",
+				Commands: []commandInfo{
+					withHidden(leaf("delete", []string{"rm"}, "Delete a thing.", false)),
+					withHidden(leaf("old", nil, "Hidden command.", true)),
+				},
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, err := kong.New(&catalogCLI{}, kong.Name("up"), kong.Writers(&bytes.Buffer{}, &bytes.Buffer{}))
+			if err != nil {
+				t.Fatalf("kong.New(...): %v", err)
+			}
+			got := describeCommand(k.Model.Node, tc.hidden)
+			if diff := cmp.Diff(tc.want, got.Commands); diff != "" {
+				t.Errorf("\n%s\ndescribeCommand(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
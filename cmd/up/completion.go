@@ -0,0 +1,211 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/config"
+)
+
+const (
+	shellBash       = "bash"
+	shellZsh        = "zsh"
+	shellFish       = "fish"
+	shellPowerShell = "powershell"
+
+	errFindExecutable  = "unable to find the path of the up executable"
+	errFindHome        = "unable to find the home directory"
+	errWriteCompletion = "unable to write completion script"
+	errUpdateRC        = "unable to update shell startup file"
+)
+
+// completionScripts are the completion scripts of each shell. Completions
+// are computed by the binary itself, which the scripts call with the command
+// line in COMP_LINE.
+var completionScripts = map[string]string{
+	shellBash: `complete -C '${bin}' ${cmd}
+`,
+	shellZsh: `autoload -U +X bashcompinit && bashcompinit
+complete -C '${bin}' ${cmd}
+`,
+	shellFish: `function __complete_${cmd}
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    '${bin}'
+end
+complete -f -c ${cmd} -a "(__complete_${cmd})"
+`,
+	shellPowerShell: `Register-ArgumentCompleter -Native -CommandName ${cmd} -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $env:COMP_LINE = $commandAst.ToString().PadRight($cursorPosition).Substring(0, $cursorPosition)
+    & '${bin}' | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+    Remove-Item Env:COMP_LINE
+}
+`,
+}
+
+// completionTarget is where the completion script of a shell is installed.
+// Shells that do not load completions from a well-known directory source the
+// script from their startup file.
+type completionTarget struct {
+	script string
+	rc     string
+	source string
+}
+
+// completionTargets returns the install locations of each shell for the
+// supplied home directory, honoring the shells' environment overrides.
+func completionTargets(goos, home string, getenv func(string) string) map[string]completionTarget {
+	data := getenv("XDG_DATA_HOME")
+	if data == "" {
+		data = filepath.Join(home, ".local", "share")
+	}
+	conf := getenv("XDG_CONFIG_HOME")
+	if conf == "" {
+		conf = filepath.Join(home, ".config")
+	}
+	zdot := getenv("ZDOTDIR")
+	if zdot == "" {
+		zdot = home
+	}
+	// PowerShell keeps its profile in the documents folder on Windows.
+	psProfile := filepath.Join(conf, "powershell", "Microsoft.PowerShell_profile.ps1")
+	if goos == "windows" {
+		psProfile = filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+	}
+	upDir := filepath.Join(home, config.ConfigDir)
+	zshScript := filepath.Join(upDir, "completion.zsh")
+	psScript := filepath.Join(upDir, "completion.ps1")
+	return map[string]completionTarget{
+		shellBash: {script: filepath.Join(data, "bash-completion", "completions", "up")},
+		shellZsh: {
+			script: zshScript,
+			rc:     filepath.Join(zdot, ".zshrc"),
+			source: fmt.Sprintf("source '%s'", zshScript),
+		},
+		shellFish: {script: filepath.Join(conf, "fish", "completions", "up.fish")},
+		shellPowerShell: {
+			script: psScript,
+			rc:     psProfile,
+			source: fmt.Sprintf(". '%s'", psScript),
+		},
+	}
+}
+
+// completionCmd prints or installs a shell completion script.
+type completionCmd struct {
+	Shell   string `arg:"" enum:"bash,zsh,fish,powershell" help:"Shell to generate the completion script for. One of bash, zsh, fish, or powershell."`
+	Install bool   `help:"Write the completion script to the standard location of the shell instead of printing it."`
+}
+
+// Help returns the help text for the completion command.
+func (c *completionCmd) Help() string {
+	return `
+Prints the completion script of the shell, for example to load it with
+
+    source <(up completion bash)
+
+With --install, the script is written to the location the shell loads
+completions from: bash-completion's completions directory for bash and the
+completions directory of fish. Scripts for zsh and PowerShell are written to
+~/.up and sourced from ~/.zshrc and the PowerShell profile respectively.`
+}
+
+// Run executes the completion command.
+func (c *completionCmd) Run(kongCtx *kong.Context, p pterm.TextPrinter) error {
+	bin, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, errFindExecutable)
+	}
+	if bin, err = filepath.Abs(bin); err != nil {
+		return errors.Wrap(err, errFindExecutable)
+	}
+	script := completionScript(c.Shell, kongCtx.Model.Name, bin)
+	if !c.Install {
+		_, err := fmt.Fprint(kongCtx.Stdout, script)
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, errFindHome)
+	}
+	t := completionTargets(runtime.GOOS, home, os.Getenv)[c.Shell]
+	if err := installCompletion(afero.NewOsFs(), t, script); err != nil {
+		return err
+	}
+	p.Printfln("Completions for %s installed to %s.", c.Shell, t.script)
+	if t.rc != "" {
+		p.Printfln("They are loaded by %s.", t.rc)
+	}
+	p.Println("Start a new shell to use them.")
+	return nil
+}
+
+// completionScript returns the completion script of the shell for the command
+// implemented by the binary.
+func completionScript(shell, cmd, bin string) string {
+	vars := map[string]string{"cmd": cmd, "bin": bin}
+	return os.Expand(completionScripts[shell], func(s string) string {
+		if v, ok := vars[s]; ok {
+			return v
+		}
+		return "$" + s
+	})
+}
+
+// installCompletion writes the script to the target and, if the shell needs
+// it, sources the script from the startup file. Installing is idempotent, so
+// the startup file is only changed once.
+func installCompletion(fs afero.Fs, t completionTarget, script string) error {
+	if err := fs.MkdirAll(filepath.Dir(t.script), 0755); err != nil {
+		return errors.Wrap(err, errWriteCompletion)
+	}
+	if err := afero.WriteFile(fs, t.script, []byte(script), 0644); err != nil {
+		return errors.Wrap(err, errWriteCompletion)
+	}
+	if t.rc == "" {
+		return nil
+	}
+	rc, err := afero.ReadFile(fs, t.rc)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, errUpdateRC)
+	}
+	for _, l := range strings.Split(string(rc), "\n") {
+		if strings.TrimSpace(l) == t.source {
+			return nil
+		}
+	}
+	if len(rc) > 0 && !strings.HasSuffix(string(rc), "\n") {
+		rc = append(rc, '\n')
+	}
+	rc = append(rc, []byte(t.source+"\n")...)
+	if err := fs.MkdirAll(filepath.Dir(t.rc), 0755); err != nil {
+		return errors.Wrap(err, errUpdateRC)
+	}
+	return errors.Wrap(afero.WriteFile(fs, t.rc, rc, 0644), errUpdateRC)
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestCompletionScript(t *testing.T) {
+	want := `function __complete_up
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    '/usr/local/bin/up'
+end
+complete -f -c up -a "(__complete_up)"
+`
+	if diff := cmp.Diff(want, completionScript(shellFish, "up", "/usr/local/bin/up")); diff != "" {
+		t.Errorf("completionScript(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestCompletionTargets(t *testing.T) {
+	env := map[string]string{"XDG_DATA_HOME": "/data", "ZDOTDIR": "/zdot"}
+	got := completionTargets("linux", "/home/u", func(k string) string { return env[k] })
+	want := map[string]completionTarget{
+		shellBash: {script: "/data/bash-completion/completions/up"},
+		shellZsh: {
+			script: "/home/u/.up/completion.zsh",
+			rc:     "/zdot/.zshrc",
+			source: "source '/home/u/.up/completion.zsh'",
+		},
+		shellFish: {script: "/home/u/.config/fish/completions/up.fish"},
+		shellPowerShell: {
+			script: "/home/u/.up/completion.ps1",
+			rc:     "/home/u/.config/powershell/Microsoft.PowerShell_profile.ps1",
+			source: ". '/home/u/.up/completion.ps1'",
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(completionTarget{})); diff != "" {
+		t.Errorf("completionTargets(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestInstallCompletion(t *testing.T) {
+	type want struct {
+		script string
+		rc     string
+	}
+	cases := map[string]struct {
+		reason string
+		target completionTarget
+		rc     string
+		want   want
+	}{
+		"NoStartupFile": {
+			reason: "Shells that load completions from a directory should only get the script.",
+			target: completionTarget{script: "/c/up"},
+			want: want{
+				script: "complete\n",
+			},
+		},
+		"AppendSource": {
+			reason: "The script should be sourced from the end of an existing startup file.",
+			target: completionTarget{script: "/c/up.zsh", rc: "/h/.zshrc", source: "source '/c/up.zsh'"},
+			rc:     "export PATH=/bin",
+			want: want{
+				script: "complete\n",
+				rc:     "export PATH=/bin\nsource '/c/up.zsh'\n",
+			},
+		},
+		"AlreadySourced": {
+			reason: "Installing again should not source the script twice.",
+			target: completionTarget{script: "/c/up.zsh", rc: "/h/.zshrc", source: "source '/c/up.zsh'"},
+			rc:     "source '/c/up.zsh'\nexport PATH=/bin\n",
+			want: want{
+				script: "complete\n",
+				rc:     "source '/c/up.zsh'\nexport PATH=/bin\n",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if tc.rc != "" {
+				_ = afero.WriteFile(fs, tc.target.rc, []byte(tc.rc), 0644)
+			}
+			if err := installCompletion(fs, tc.target, "complete\n"); err != nil {
+				t.Fatalf("\n%s\ninstallCompletion(...): %v", tc.reason, err)
+			}
+			script, _ := afero.ReadFile(fs, tc.target.script)
+			if diff := cmp.Diff(tc.want.script, string(script)); diff != "" {
+				t.Errorf("\n%s\ninstallCompletion(...): -want script, +got script:\n%s", tc.reason, diff)
+			}
+			if tc.target.rc == "" {
+				return
+			}
+			rc, _ := afero.ReadFile(fs, tc.target.rc)
+			if diff := cmp.Diff(tc.want.rc, string(rc)); diff != "" {
+				t.Errorf("\n%s\ninstallCompletion(...): -want rc, +got rc:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	upconfig "github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	errUpdateConfig = "unable to update config file"
+)
+
+// AfterApply constructs and binds Upbound-specific context to any subcommands
+// that have Run() methods that receive it.
+func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(upbound.Flags{})
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(upCtx)
+	return nil
+}
+
+// Cmd contains commands for reading and writing up configuration.
+type Cmd struct {
+	Set  setCmd  `cmd:"" help:"Set a configuration setting."`
+	Get  getCmd  `cmd:"" help:"Get the value of a configuration setting."`
+	View viewCmd `cmd:"" help:"View all configuration settings."`
+}
+
+// Help returns the help text for the config commands.
+func (c *Cmd) Help() string {
+	return `
+Settings are stored in ~/.up/config.json. Settings of a profile, such as the
+account and endpoints, apply to the default profile. Flags and environment
+variables supplied to a command take precedence over settings. Run 'up config
+view' to list all settings.`
+}
+
+type setCmd struct {
+	Key   string `arg:"" help:"Key of the setting."`
+	Value string `arg:"" optional:"" help:"Value of the setting. Restores the default if omitted."`
+}
+
+// Run executes the set command.
+func (c *setCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if err := upCtx.ModifyConfig(func(cfg *upconfig.Config) error {
+		return cfg.SetSetting(c.Key, c.Value)
+	}); err != nil {
+		return errors.Wrap(err, errUpdateConfig)
+	}
+	v, err := upCtx.Cfg.GetSetting(c.Key)
+	if err != nil {
+		return err
+	}
+	p.Printfln("%s set to %q", c.Key, v)
+	return nil
+}
+
+type getCmd struct {
+	Key string `arg:"" help:"Key of the setting."`
+}
+
+// Run executes the get command.
+func (c *getCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	v, err := upCtx.Cfg.GetSetting(c.Key)
+	if err != nil {
+		return err
+	}
+	p.Println(v)
+	return nil
+}
+
+// setting is a setting and its current value.
+type setting struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+var viewFieldNames = []string{"KEY", "VALUE", "DESCRIPTION"}
+
+type viewCmd struct{}
+
+// PrintedObjects returns the objects printed by the view command.
+func (c *viewCmd) PrintedObjects() []any {
+	return []any{setting{}}
+}
+
+// Run executes the view command.
+func (c *viewCmd) Run(printer upterm.ObjectPrinter, upCtx *upbound.Context) error {
+	all := upconfig.Settings()
+	settings := make([]setting, len(all))
+	for i, s := range all {
+		v, err := upCtx.Cfg.GetSetting(s.Key)
+		if err != nil {
+			return err
+		}
+		settings[i] = setting{Key: s.Key, Value: v, Description: s.Description}
+	}
+	return printer.Print(settings, viewFieldNames, extractFields)
+}
+
+func extractFields(obj any) []string {
+	s := obj.(setting)
+	return []string{s.Key, s.Value, s.Description}
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/upbound/up/internal/upterm"
+)
+
+// settingV1 is version v1 of the machine output of a configuration setting.
+type settingV1 struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(setting{}, upterm.Schema{
+		Kind:    "Setting",
+		Version: "v1",
+		Convert: func(obj any) any {
+			s := obj.(setting)
+			return settingV1{Key: s.Key, Value: s.Value, Description: s.Description}
+		},
+	})
+}
@@ -29,8 +29,8 @@ import (
 
 // AfterApply constructs and binds a configurations client to any subcommands
 // such as "list" or "get" that have Run() methods that receive it.
-func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
-	upCtx, err := upbound.NewFromFlags(c.Flags)
+func (c *Cmd) AfterApply(ctx context.Context, kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.DiscoverAccount(ctx))
 	if err != nil {
 		return err
 	}
@@ -47,6 +47,9 @@ func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
 
 func PredictConfigurations() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -61,7 +64,7 @@ func PredictConfigurations() complete.Predictor {
 			return nil
 		}
 
-		configs, err := cp.List(context.Background(), upCtx.Account)
+		configs, err := cp.List(ctx, upCtx.Account)
 		if err != nil {
 			return nil
 		}
@@ -34,18 +34,18 @@ func (c *deleteCmd) BeforeApply() error {
 }
 
 // AfterApply accepts user input by default to confirm the delete operation.
-func (c *deleteCmd) AfterApply(cc *configurations.Client, cpc *controlplanes.Client, p pterm.TextPrinter, upCtx *upbound.Context) error {
+func (c *deleteCmd) AfterApply(ctx context.Context, cc *configurations.Client, cpc *controlplanes.Client, p pterm.TextPrinter, upCtx *upbound.Context) error {
 	if c.Force {
 		return nil
 	}
 	// Deleting a configuration can orphan any control planes that have it deployed.
 	// While the API will eventually return a 400 status, we can show the user
 	// which control planes are using the configuration.
-	cfg, err := cc.Get(context.Background(), upCtx.Account, c.Name)
+	cfg, err := cc.Get(ctx, upCtx.Account, c.Name)
 	if err != nil {
 		return err
 	}
-	cpList, err := cpc.List(context.Background(), upCtx.Account, common.ListOption(controlplanes.WithConfiguration(cfg.ID)))
+	cpList, err := cpc.List(ctx, upCtx.Account, common.ListOption(controlplanes.WithConfiguration(cfg.ID)))
 	if err != nil {
 		return err
 	}
@@ -80,8 +80,8 @@ type deleteCmd struct {
 }
 
 // Run executes the delete command.
-func (c *deleteCmd) Run(p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
-	if err := cc.Delete(context.Background(), upCtx.Account, c.Name); err != nil {
+func (c *deleteCmd) Run(ctx context.Context, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
+	if err := cc.Delete(ctx, upCtx.Account, c.Name); err != nil {
 		return err
 	}
 	p.Printfln("%s deleted", c.Name)
@@ -27,11 +27,18 @@ import (
 // getCmd gets a single root configuration in an account on Upbound.
 type getCmd struct {
 	Name string `arg:"" required:"" name:"The name of the configuration." predictor:"configs"`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{configurations.ConfigurationResponse{}}
 }
 
 // Run executes the get command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
-	cfg, err := cc.Get(context.Background(), upCtx.Account, c.Name)
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
+	cfg, err := cc.Get(ctx, upCtx.Account, c.Name)
 	if err != nil {
 		return err
 	}
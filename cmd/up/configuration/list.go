@@ -27,11 +27,18 @@ import (
 var fieldNames = []string{"NAME", "TEMPLATE ID", "PROVIDER", "REPO", "BRANCH", "CREATED AT", "SYNCED AT"}
 
 // listCmd lists root configurations in an account on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{configurations.ConfigurationResponse{}}
+}
 
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
-	cfgList, err := cc.List(context.Background(), upCtx.Account)
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
+	cfgList, err := cc.List(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
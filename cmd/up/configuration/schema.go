@@ -0,0 +1,70 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/configurations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// configurationV1 is version v1 of the machine output of a configuration.
+type configurationV1 struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	LatestVersion string     `json:"latestVersion,omitempty"`
+	TemplateID    string     `json:"templateId,omitempty"`
+	Provider      string     `json:"provider,omitempty"`
+	Context       string     `json:"context,omitempty"`
+	Repo          string     `json:"repo,omitempty"`
+	Branch        string     `json:"branch,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+	SyncedAt      *time.Time `json:"syncedAt,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(configurations.ConfigurationResponse{}, upterm.Schema{
+		Kind:    "Configuration",
+		Version: "v1",
+		Convert: convertConfigurationV1,
+	})
+}
+
+func convertConfigurationV1(obj any) any {
+	c := obj.(configurations.ConfigurationResponse)
+	out := configurationV1{
+		ID:         c.ID,
+		TemplateID: c.TemplateID,
+		Provider:   string(c.Provider),
+		Context:    c.Context,
+		Repo:       c.Repo,
+		Branch:     c.Branch,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+		SyncedAt:   c.SyncedAt,
+	}
+	if c.Name != nil {
+		out.Name = *c.Name
+	}
+	if c.LatestVersion != nil {
+		out.LatestVersion = *c.LatestVersion
+	}
+	return out
+}
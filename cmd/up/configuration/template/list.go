@@ -27,11 +27,18 @@ import (
 var fieldNames = []string{"ID", "DESCRIPTION", "REPO"}
 
 // listCmd lists configuration templates on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{configurations.ConfigurationTemplateReponse{}}
+}
 
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
-	templateList, err := cc.ListTemplates(context.Background())
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *configurations.Client, upCtx *upbound.Context) error {
+	templateList, err := cc.ListTemplates(ctx)
 	if err != nil {
 		return err
 	}
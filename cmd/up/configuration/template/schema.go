@@ -0,0 +1,41 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"github.com/upbound/up-sdk-go/service/configurations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// templateV1 is version v1 of the machine output of a configuration
+// template.
+type templateV1 struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ImageURI string `json:"imageUri,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(configurations.ConfigurationTemplateReponse{}, upterm.Schema{
+		Kind:    "ConfigurationTemplate",
+		Version: "v1",
+		Convert: func(obj any) any {
+			t := obj.(configurations.ConfigurationTemplateReponse)
+			return templateV1{ID: t.ID, Name: t.Name, ImageURI: t.ImageURI, Repo: t.Repo}
+		},
+	})
+}
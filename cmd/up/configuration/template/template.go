@@ -45,6 +45,9 @@ type Cmd struct {
 
 func PredictTemplates() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -59,7 +62,7 @@ func PredictTemplates() complete.Predictor {
 			return nil
 		}
 
-		templates, err := cc.ListTemplates(context.Background())
+		templates, err := cc.ListTemplates(ctx)
 		if err != nil {
 			return nil
 		}
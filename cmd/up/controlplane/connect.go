@@ -17,15 +17,20 @@ package controlplane
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
 	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/yaml"
+	"k8s.io/client-go/rest"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
 	"github.com/upbound/up-sdk-go/service/tokens"
@@ -33,7 +38,9 @@ import (
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
 	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/secrets"
 	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
 )
 
 var (
@@ -45,27 +52,149 @@ const (
 
 	errReadParametersFile     = "unable to read parameters file"
 	errParseInstallParameters = "unable to parse install parameters"
+	errConnectorValues        = "unable to build MCP Connector values"
+	errCreateNamespace        = "unable to create MCP Connector namespace"
+	errRecordToken            = "unable to record the API token of the MCP Connector"
+
+	// tokenConfigMapName is the ConfigMap in the installation namespace in
+	// which connect records the API token it created, so that disconnect can
+	// revoke it.
+	tokenConfigMapName = connectorName + "-token"
+	tokenIDKey         = "id"
+	tokenNameKey       = "name"
+
+	errFmtInvalidQuantity   = "invalid quantity %q for %s"
+	errFmtInvalidToleration = "invalid toleration %q, must be key[=value]:effect"
+	errFmtInvalidEffect     = "invalid toleration effect %q, must be one of NoSchedule, PreferNoSchedule, or NoExecute"
 )
 
-// AfterApply sets default values in command after assignment and validation.
-func (c *connectCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
-	if c.ClusterName == "" {
-		c.ClusterName = c.Namespace
-	}
-	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+// connectorParams are the parameters used to locate the MCP Connector in the
+// current cluster.
+type connectorParams struct {
+	Kubeconfig            string `type:"existingfile" help:"Override the default kubeconfig path."`
+	InstallationNamespace string `short:"n" env:"MCP_CONNECTOR_NAMESPACE" default:"kube-system" help:"Kubernetes namespace for MCP Connector. Default is kube-system."`
+}
+
+// kubeconfig returns the REST config for the current cluster.
+func (p connectorParams) kubeconfig(upCtx *upbound.Context) (*rest.Config, error) {
+	kubeconfig, err := kube.GetKubeConfig(p.Kubeconfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if upCtx.WrapTransport != nil {
 		kubeconfig.Wrap(upCtx.WrapTransport)
 	}
+	return kubeconfig, nil
+}
 
-	mgr, err := helm.NewManager(kubeconfig,
+// manager returns an install.Manager for the MCP Connector release.
+func (p connectorParams) manager(kubeconfig *rest.Config, modifiers ...helm.InstallerModifierFn) (install.Manager, error) {
+	return helm.NewManager(kubeconfig,
 		connectorName,
 		mcpRepoURL,
-		helm.WithNamespace(c.InstallationNamespace),
-		helm.Wait(),
+		append([]helm.InstallerModifierFn{
+			helm.WithNamespace(p.InstallationNamespace),
+			helm.Wait(),
+		}, modifiers...)...,
 	)
+}
+
+// connectorValues are the parameters that customize how the MCP Connector is
+// run in the current cluster.
+type connectorValues struct {
+	ImageRepository string            `help:"Override the image repository of the MCP Connector."`
+	ImageTag        string            `help:"Override the image tag of the MCP Connector."`
+	Requests        map[string]string `help:"Resource requests of the MCP Connector, such as cpu=100m;memory=128Mi."`
+	Limits          map[string]string `help:"Resource limits of the MCP Connector, such as cpu=500m;memory=512Mi."`
+	NodeSelector    map[string]string `help:"Node labels the MCP Connector must be scheduled on, such as kubernetes.io/os=linux."`
+	Tolerations     []string          `name:"toleration" help:"Taint tolerated by the MCP Connector, as key[=value]:effect. Can be repeated."`
+}
+
+// values returns the helm values for the customizations that were set.
+func (v connectorValues) values() (map[string]any, error) {
+	vals := map[string]any{}
+	image := map[string]any{}
+	if v.ImageRepository != "" {
+		image["repository"] = v.ImageRepository
+	}
+	if v.ImageTag != "" {
+		image["tag"] = v.ImageTag
+	}
+	if len(image) > 0 {
+		vals["image"] = image
+	}
+	res := map[string]any{}
+	for name, q := range map[string]map[string]string{"requests": v.Requests, "limits": v.Limits} {
+		if len(q) == 0 {
+			continue
+		}
+		m := make(map[string]any, len(q))
+		for k, val := range q {
+			if _, err := resource.ParseQuantity(val); err != nil {
+				return nil, errors.Errorf(errFmtInvalidQuantity, val, k)
+			}
+			m[k] = val
+		}
+		res[name] = m
+	}
+	if len(res) > 0 {
+		vals["resources"] = res
+	}
+	if len(v.NodeSelector) > 0 {
+		ns := make(map[string]any, len(v.NodeSelector))
+		for k, val := range v.NodeSelector {
+			ns[k] = val
+		}
+		vals["nodeSelector"] = ns
+	}
+	if len(v.Tolerations) > 0 {
+		ts := make([]any, len(v.Tolerations))
+		for i, t := range v.Tolerations {
+			tol, err := parseToleration(t)
+			if err != nil {
+				return nil, err
+			}
+			ts[i] = tol
+		}
+		vals["tolerations"] = ts
+	}
+	return vals, nil
+}
+
+// parseToleration parses a toleration in the key[=value]:effect form used by
+// kubectl taint into its helm values.
+func parseToleration(s string) (map[string]any, error) {
+	kv, effect, ok := strings.Cut(s, ":")
+	if !ok || kv == "" {
+		return nil, errors.Errorf(errFmtInvalidToleration, s)
+	}
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return nil, errors.Errorf(errFmtInvalidEffect, effect)
+	}
+	tol := map[string]any{"effect": effect}
+	key, value, hasValue := strings.Cut(kv, "=")
+	tol["key"] = key
+	if hasValue {
+		tol["operator"] = string(corev1.TolerationOpEqual)
+		tol["value"] = value
+	} else {
+		tol["operator"] = string(corev1.TolerationOpExists)
+	}
+	return tol, nil
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *connectCmd) AfterApply(ctx context.Context, kongCtx *kong.Context, upCtx *upbound.Context) error {
+	if c.ClusterName == "" {
+		c.ClusterName = c.Namespace
+	}
+	kubeconfig, err := c.kubeconfig(upCtx)
+	if err != nil {
+		return err
+	}
+	mgr, err := c.manager(kubeconfig)
 	if err != nil {
 		return err
 	}
@@ -76,21 +205,19 @@ func (c *connectCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) e
 	}
 	c.kClient = client
 
-	base := map[string]any{}
-	if c.File != nil {
-		defer c.File.Close() //nolint:errcheck,gosec
-		b, err := io.ReadAll(c.File)
-		if err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := yaml.Unmarshal(b, &base); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := c.File.Close(); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
 	}
-	c.parser = helm.NewParser(base, c.Set)
+	secretParams, err := c.SecretParameters(ctx, secrets.NewResolver())
+	if err != nil {
+		return err
+	}
+	defaults, err := c.values()
+	if err != nil {
+		return errors.Wrap(err, errConnectorValues)
+	}
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithDefaults(defaults), helm.WithLiteralOverrides(secretParams))
 	return nil
 }
 
@@ -104,17 +231,18 @@ type connectCmd struct {
 	Name      string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
 	Namespace string `arg:"" required:"" help:"Namespace in the control plane where the claims of the cluster will be stored."`
 
-	Token                 string `help:"API token used to authenticate. If not provided, a new robot and a token will be created."`
-	ClusterName           string `help:"Name of the cluster connecting to the control plane. If not provided, the namespace argument value will be used."`
-	Kubeconfig            string `type:"existingfile" help:"Override the default kubeconfig path."`
-	InstallationNamespace string `short:"n" env:"MCP_CONNECTOR_NAMESPACE" default:"kube-system" help:"Kubernetes namespace for MCP Connector. Default is kube-system."`
+	Version     string `name:"connector-version" help:"MCP Connector version to install. If not provided, the latest version will be installed."`
+	Token       string `help:"API token used to authenticate. If not provided, a new token is created for the current user and revoked by 'up controlplane disconnect'."`
+	ClusterName string `help:"Name of the cluster connecting to the control plane. If not provided, the namespace argument value will be used."`
 
+	connectorParams
+	connectorValues
 	install.CommonParams
 }
 
 // Run executes the connect command.
-func (c *connectCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
-	token, err := c.getToken(p, upCtx)
+func (c *connectCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error {
+	token, tokenID, err := c.getToken(ctx, p, upCtx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get token")
 	}
@@ -129,8 +257,22 @@ func (c *connectCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 		"host":      fmt.Sprintf("%s://%s", upCtx.ProxyEndpoint.Scheme, upCtx.ProxyEndpoint.Host),
 		"token":     token,
 	}
-	p.Printfln("Installing %s to kube-system. This may take a few minutes.", connectorName)
-	if err = c.mgr.Install("", params); err != nil {
+	// Create namespace if it does not exist.
+	_, err = c.kClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.InstallationNamespace,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, errCreateNamespace)
+	}
+	if tokenID != uuid.Nil {
+		if err := recordToken(ctx, c.kClient, c.InstallationNamespace, tokenID, c.ClusterName); err != nil {
+			return errors.Wrap(err, errRecordToken)
+		}
+	}
+	p.Printfln("Installing %s to %s. This may take a few minutes.", connectorName, c.InstallationNamespace)
+	if err = c.mgr.Install(c.Version, params); err != nil {
 		return err
 	}
 
@@ -143,13 +285,15 @@ func (c *connectCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 	return nil
 }
 
-func (c *connectCmd) getToken(p pterm.TextPrinter, upCtx *upbound.Context) (string, error) {
+// getToken returns the API token supplied with --token, or creates one. The ID
+// of a created token is returned so that it can be revoked on disconnect.
+func (c *connectCmd) getToken(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) (string, uuid.UUID, error) {
 	if c.Token != "" {
-		return c.Token, nil
+		return c.Token, uuid.Nil, nil
 	}
 	cfg, err := upCtx.BuildSDKConfig()
 	if err != nil {
-		return "", errors.Wrap(err, "failed to build SDK config")
+		return "", uuid.Nil, errors.Wrap(err, "failed to build SDK config")
 	}
 	// NOTE(muvaf): We always use the querying user's account to create a token
 	// assuming it has enough privileges. The ideal is to create a robot and a
@@ -160,13 +304,13 @@ func (c *connectCmd) getToken(p pterm.TextPrinter, upCtx *upbound.Context) (stri
 	// This is why this command is currently under alpha because we need to be
 	// able to connect for organizations in a scalable way, i.e. every cluster
 	// should have its own robot account.
-	a, err := accounts.NewClient(cfg).Get(context.Background(), upCtx.Profile.ID)
+	a, err := accounts.NewClient(cfg).Get(ctx, upCtx.Profile.ID)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get account details")
+		return "", uuid.Nil, errors.Wrap(err, "failed to get account details")
 	}
 	p.Printfln("Creating an API token for the user %s. This token will be "+
 		"used to authenticate the cluster.", a.User.Username)
-	resp, err := tokens.NewClient(cfg).Create(context.Background(), &tokens.TokenCreateParameters{
+	resp, err := tokens.NewClient(cfg).Create(ctx, &tokens.TokenCreateParameters{
 		Attributes: tokens.TokenAttributes{
 			Name: c.ClusterName,
 		},
@@ -180,10 +324,24 @@ func (c *connectCmd) getToken(p pterm.TextPrinter, upCtx *upbound.Context) (stri
 		},
 	})
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create token")
+		return "", uuid.Nil, errors.Wrap(err, "failed to create token")
 	}
 	p.Printfln("Created a token named %s.", c.ClusterName)
-	return fmt.Sprint(resp.DataSet.Meta["jwt"]), nil
+	return fmt.Sprint(resp.DataSet.Meta["jwt"]), resp.DataSet.ID, nil
+}
+
+// recordToken records the ID and name of the API token created for the MCP
+// Connector in the installation namespace.
+func recordToken(ctx context.Context, client kubernetes.Interface, namespace string, id uuid.UUID, name string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: tokenConfigMapName, Namespace: namespace},
+		Data:       map[string]string{tokenIDKey: id.String(), tokenNameKey: name},
+	}
+	_, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = client.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return err
 }
 
 func urlMustParse(s string) *url.URL {
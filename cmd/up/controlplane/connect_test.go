@@ -0,0 +1,108 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConnectorValues(t *testing.T) {
+	type want struct {
+		values map[string]any
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		v      connectorValues
+		want   want
+	}{
+		"Empty": {
+			reason: "No values should be set if no customization is given.",
+			want: want{
+				values: map[string]any{},
+			},
+		},
+		"All": {
+			reason: "All customizations should be set in the chart values.",
+			v: connectorValues{
+				ImageRepository: "registry.example.com/mcp-connector",
+				ImageTag:        "v0.1.0",
+				Requests:        map[string]string{"cpu": "100m", "memory": "128Mi"},
+				Limits:          map[string]string{"memory": "512Mi"},
+				NodeSelector:    map[string]string{"kubernetes.io/os": "linux"},
+				Tolerations:     []string{"dedicated=infra:NoSchedule", "spot:NoExecute"},
+			},
+			want: want{
+				values: map[string]any{
+					"image": map[string]any{
+						"repository": "registry.example.com/mcp-connector",
+						"tag":        "v0.1.0",
+					},
+					"resources": map[string]any{
+						"requests": map[string]any{"cpu": "100m", "memory": "128Mi"},
+						"limits":   map[string]any{"memory": "512Mi"},
+					},
+					"nodeSelector": map[string]any{"kubernetes.io/os": "linux"},
+					"tolerations": []any{
+						map[string]any{"key": "dedicated", "operator": "Equal", "value": "infra", "effect": "NoSchedule"},
+						map[string]any{"key": "spot", "operator": "Exists", "effect": "NoExecute"},
+					},
+				},
+			},
+		},
+		"InvalidQuantity": {
+			reason: "Quantities that cannot be parsed should be rejected.",
+			v: connectorValues{
+				Limits: map[string]string{"cpu": "lots"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidQuantity, "lots", "cpu"),
+			},
+		},
+		"InvalidToleration": {
+			reason: "Tolerations without an effect should be rejected.",
+			v: connectorValues{
+				Tolerations: []string{"dedicated=infra"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidToleration, "dedicated=infra"),
+			},
+		},
+		"InvalidEffect": {
+			reason: "Tolerations with an unknown effect should be rejected.",
+			v: connectorValues{
+				Tolerations: []string{"dedicated:Sometimes"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidEffect, "Sometimes"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.v.values()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalues(): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.values, got); diff != "" {
+				t.Errorf("\n%s\nvalues(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/secrets"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	errParseUpgradeParameters = "unable to parse upgrade parameters"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *connectUpgradeCmd) AfterApply(ctx context.Context, upCtx *upbound.Context) error {
+	kubeconfig, err := c.kubeconfig(upCtx)
+	if err != nil {
+		return err
+	}
+	// The connection details and token were supplied on connect and are
+	// kept, so only the given parameters change.
+	mgr, err := c.manager(kubeconfig,
+		helm.WithChart(c.Bundle),
+		helm.RollbackOnError(c.Rollback),
+		helm.ReuseValues(),
+	)
+	if err != nil {
+		return err
+	}
+	c.mgr = mgr
+
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
+	}
+	secretParams, err := c.SecretParameters(ctx, secrets.NewResolver())
+	if err != nil {
+		return err
+	}
+	defaults, err := c.values()
+	if err != nil {
+		return errors.Wrap(err, errConnectorValues)
+	}
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithDefaults(defaults), helm.WithLiteralOverrides(secretParams))
+	return nil
+}
+
+// connectUpgradeCmd upgrades the MCP Connector in the current cluster.
+type connectUpgradeCmd struct {
+	mgr    install.Manager
+	parser install.ParameterParser
+
+	Version string `arg:"" optional:"" help:"MCP Connector version to upgrade to. If not provided, the latest version will be installed."`
+
+	Rollback bool `help:"Rollback to previously installed version on failed upgrade."`
+
+	connectorParams
+	connectorValues
+	install.CommonParams
+}
+
+// Run executes the connect-upgrade command.
+func (c *connectUpgradeCmd) Run(p pterm.TextPrinter) error {
+	prev, err := c.mgr.GetCurrentVersion()
+	if err != nil {
+		return errors.Wrap(err, errNotConnected)
+	}
+	params, err := c.parser.Parse()
+	if err != nil {
+		return errors.Wrap(err, errParseUpgradeParameters)
+	}
+	p.Printfln("Upgrading %s in %s. This may take a few minutes.", connectorName, c.InstallationNamespace)
+	if err := c.mgr.Upgrade(c.Version, params); err != nil {
+		return err
+	}
+	cur, err := c.mgr.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	p.Printfln("MCP Connector upgraded from %s to %s.", prev, cur)
+	return nil
+}
@@ -19,13 +19,18 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/posener/complete"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/upbound/up-sdk-go/service/configurations"
 	cp "github.com/upbound/up-sdk-go/service/controlplanes"
 	"github.com/upbound/up/cmd/up/controlplane/kubeconfig"
 	"github.com/upbound/up/cmd/up/controlplane/pkg"
 	"github.com/upbound/up/cmd/up/controlplane/pullsecret"
+	"github.com/upbound/up/cmd/up/controlplane/runtimeconfig"
+	"github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/feature"
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/spaces"
 	"github.com/upbound/up/internal/upbound"
 )
 
@@ -36,8 +41,8 @@ func (c *Cmd) BeforeReset(p *kong.Path, maturity feature.Maturity) error {
 
 // AfterApply constructs and binds a control plane client to any subcommands
 // that have Run() methods that receive it.
-func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
-	upCtx, err := upbound.NewFromFlags(c.Flags)
+func (c *Cmd) AfterApply(ctx context.Context, kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.DiscoverAccount(ctx))
 	if err != nil {
 		return err
 	}
@@ -48,11 +53,54 @@ func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
 	kongCtx.Bind(upCtx)
 	kongCtx.Bind(cp.NewClient(cfg))
 	kongCtx.Bind(configurations.NewClient(cfg))
+
+	// Control planes of space profiles are managed through the Kubernetes
+	// API of the Space cluster. Commands that support Spaces receive a nil
+	// client for other profiles.
+	if upCtx.Profile.Type != config.SpaceProfileType {
+		kongCtx.Bind((*spaces.ControlPlaneClient)(nil))
+		return nil
+	}
+	kubeconfig, err := kube.GetKubeConfigWithContext(upCtx.Profile.Kubeconfig, upCtx.Profile.KubeContext)
+	if err != nil {
+		return err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+	dc, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(spaces.NewControlPlaneClient(dc))
+	return nil
+}
+
+// BetaCmd contains the commands for interacting with control planes that are
+// in beta.
+type BetaCmd struct {
+	PortForward portForwardCmd `cmd:"" maturity:"beta" help:"Forward local ports to a pod or service in a control plane."`
+
+	// Common Upbound API configuration
+	Flags upbound.Flags `embed:""`
+}
+
+// AfterApply constructs and binds Upbound-specific context to any subcommands
+// that have Run() methods that receive it.
+func (c *BetaCmd) AfterApply(ctx context.Context, kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.DiscoverAccount(ctx))
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(upCtx)
 	return nil
 }
 
 func PredictControlPlanes() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -67,7 +115,7 @@ func PredictControlPlanes() complete.Predictor {
 			return nil
 		}
 
-		ctps, err := cp.List(context.Background(), upCtx.Account)
+		ctps, err := cp.List(ctx, upCtx.Account)
 		if err != nil {
 			return nil
 		}
@@ -86,18 +134,34 @@ func PredictControlPlanes() complete.Predictor {
 
 // Cmd contains commands for interacting with control planes.
 type Cmd struct {
-	Create createCmd `cmd:"" help:"Create a managed control plane."`
-	Delete deleteCmd `cmd:"" help:"Delete a control plane."`
-	List   listCmd   `cmd:"" help:"List control planes for the account."`
-	Get    getCmd    `cmd:"" help:"Get a single control plane."`
-
-	Connect connectCmd `cmd:"" help:"Connect an App Cluster to a managed control plane."`
+	Create  createCmd  `cmd:"" help:"Create a managed control plane."`
+	Delete  deleteCmd  `cmd:"" help:"Delete a control plane."`
+	Restore restoreCmd `cmd:"" help:"Cancel the scheduled deletion of a control plane."`
+	List    listCmd    `cmd:"" help:"List control planes for the account."`
+	Get     getCmd     `cmd:"" help:"Get a single control plane."`
+	Pause   pauseCmd   `cmd:"" help:"Pause reconciliation of control planes in a Space."`
+	Resume  resumeCmd  `cmd:"" help:"Resume reconciliation of paused control planes in a Space."`
+	Tag     tagCmd     `cmd:"" help:"Tag a control plane with the cost center its usage is charged to."`
+
+	Connect        connectCmd        `cmd:"" help:"Connect an App Cluster to a managed control plane."`
+	ConnectStatus  connectStatusCmd  `cmd:"" name:"connect-status" help:"Show the status of the MCP Connector in the current cluster."`
+	ConnectUpgrade connectUpgradeCmd `cmd:"" name:"connect-upgrade" help:"Upgrade the MCP Connector in the current cluster."`
+	Disconnect     disconnectCmd     `cmd:"" help:"Disconnect an App Cluster from a managed control plane."`
+
+	PortForward portForwardCmd `cmd:"" maturity:"beta" help:"Forward local ports to a pod or service in a control plane."`
+
+	GetResources getResourcesCmd `cmd:"" name:"get-resources" help:"List the claims and composite resources in a control plane."`
+	Trace        traceCmd        `cmd:"" help:"Show the resources composed by a claim or composite resource in a control plane."`
+	Events       eventsCmd       `cmd:"" help:"Stream the events of one or more control planes."`
+	Query        queryCmd        `cmd:"" help:"Find resources across control planes."`
 
 	Configuration pkg.Cmd `cmd:"" set:"package_type=Configuration" help:"Manage Configurations."`
 	Provider      pkg.Cmd `cmd:"" set:"package_type=Provider" help:"Manage Providers."`
 
 	PullSecret pullsecret.Cmd `cmd:"" help:"Manage package pull secrets."`
 
+	RuntimeConfig runtimeconfig.Cmd `cmd:"" name:"runtime-config" help:"Manage the runtime configuration of Providers."`
+
 	Kubeconfig kubeconfig.Cmd `cmd:"" name:"kubeconfig" help:"Manage control plane kubeconfig data."`
 
 	// Common Upbound API configuration
@@ -16,32 +16,211 @@ package controlplane
 
 import (
 	"context"
+	"io"
+	"os"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pterm/pterm"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	uerrors "github.com/upbound/up-sdk-go/errors"
 	"github.com/upbound/up-sdk-go/service/configurations"
 	cp "github.com/upbound/up-sdk-go/service/controlplanes"
 
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
 	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/xpkg/dep/resolver/image"
 )
 
+const (
+	errReadTemplate       = "unable to read control plane template"
+	errNoName             = "control plane name must be provided as an argument or in the template"
+	errNoConfigName       = "--configuration-name must be provided if not set in the template"
+	errParamsNoTemplate   = "--param can only be used with --file"
+	errInvalidLabels      = "invalid control plane labels"
+	errInvalidAnnotations = "invalid control plane annotations"
+	errUpdateConfig       = "unable to update config file"
+
+	errConfigurationSpaceOnly    = "--configuration is only supported for control planes in a Space, use --configuration-name instead"
+	errConstraintNoConfiguration = "--version-constraint can only be used with --configuration"
+	errConstraintTagged          = "--version-constraint cannot be used with a configuration reference that includes a tag or digest"
+	errResolveConfiguration      = "unable to resolve configuration version"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *createCmd) AfterApply(upCtx *upbound.Context) error { //nolint:gocyclo
+	tmpl := &controlPlaneTemplate{}
+	if c.File != nil {
+		defer c.File.Close() //nolint:errcheck,gosec
+		b, err := io.ReadAll(c.File)
+		if err != nil {
+			return errors.Wrap(err, errReadTemplate)
+		}
+		if tmpl, err = renderTemplate(b, c.Param); err != nil {
+			return err
+		}
+	} else if len(c.Param) > 0 {
+		return errors.New(errParamsNoTemplate)
+	}
+	// Values supplied on the command line take precedence over the template.
+	if c.Name == "" {
+		c.Name = tmpl.Name
+	}
+	if c.ConfigurationName == "" {
+		c.ConfigurationName = tmpl.Configuration
+	}
+	if c.Description == "" {
+		c.Description = tmpl.Description
+	}
+	if c.Configuration == "" {
+		c.Configuration = tmpl.ConfigurationPackage
+	}
+	if c.VersionConstraint == "" {
+		c.VersionConstraint = tmpl.VersionConstraint
+	}
+	for k, v := range tmpl.Labels {
+		if _, ok := c.Labels[k]; !ok {
+			if c.Labels == nil {
+				c.Labels = map[string]string{}
+			}
+			c.Labels[k] = v
+		}
+	}
+	for k, v := range tmpl.Annotations {
+		if _, ok := c.Annotations[k]; !ok {
+			if c.Annotations == nil {
+				c.Annotations = map[string]string{}
+			}
+			c.Annotations[k] = v
+		}
+	}
+	if c.CostCenter != "" {
+		if err := validateCostCenter(c.CostCenter); err != nil {
+			return err
+		}
+		if c.Labels == nil {
+			c.Labels = map[string]string{}
+		}
+		c.Labels[resources.CostCenterLabel] = c.CostCenter
+	}
+	if _, err := labels.ValidatedSelectorFromSet(c.Labels); err != nil {
+		return errors.Wrap(err, errInvalidLabels)
+	}
+	if err := validation.ValidateAnnotations(c.Annotations, field.NewPath("annotations")).ToAggregate(); err != nil {
+		return errors.Wrap(err, errInvalidAnnotations)
+	}
+	if c.Name == "" {
+		return errors.New(errNoName)
+	}
+	space := upCtx.Profile.Type == config.SpaceProfileType
+	if c.Configuration != "" && !space {
+		return errors.New(errConfigurationSpaceOnly)
+	}
+	if c.CostCenter != "" && !space {
+		return errors.New(errCostCenterSpaceOnly)
+	}
+	if c.VersionConstraint != "" && c.Configuration == "" {
+		return errors.New(errConstraintNoConfiguration)
+	}
+	// Control planes in a Space are not bootstrapped with a configuration.
+	if c.ConfigurationName == "" && !space {
+		return errors.New(errNoConfigName)
+	}
+	c.resolver = image.NewResolver()
+	c.registry = upCtx.RegistryEndpoint.Hostname()
+	return nil
+}
+
 // createCmd creates a control plane on Upbound.
 type createCmd struct {
-	Name string `arg:"" required:"" help:"Name of control plane."`
+	resolver *image.Resolver
+	registry string
+
+	Name string `arg:"" optional:"" help:"Name of control plane. Required unless set in the template."`
+
+	ConfigurationName string            `help:"The name of the Configuration. Required unless set in the template."`
+	Description       string            `short:"d" help:"Description for control plane."`
+	Labels            map[string]string `help:"Labels for the control plane in the form key=value. May be repeated."`
+	Annotations       map[string]string `help:"Annotations for the control plane in the form key=value. May be repeated."`
+	CostCenter        string            `help:"Cost center to which the usage of the control plane is charged. Sets the upbound.io/cost-center label. Only supported for control planes in a Space."`
+	Configuration     string            `help:"Configuration package to install in a control plane in a Space, e.g. xpkg.upbound.io/acme/platform. If no tag is given, the latest release matching --version-constraint is resolved from the registry."`
+	VersionConstraint string            `help:"Semantic version range of the configuration to resolve, e.g. '>=1.2, <2'. Defaults to the latest release."`
+
+	File  *os.File          `short:"f" help:"Path to a YAML template describing the control plane. Values may reference parameters as $${name}. Write $$$$ for a literal $$."`
+	Param map[string]string `help:"Value for a template parameter in the form name=value. May be repeated."`
 
-	ConfigurationName string `required:"" help:"The name of the Configuration."`
-	Description       string `short:"d" help:"Description for control plane."`
+	IfNotExists bool `help:"Succeed without creating a control plane if one with this name already exists, and print the existing control plane."`
+}
+
+// PrintedObjects returns the objects printed by the create command.
+func (c *createCmd) PrintedObjects() []any {
+	return []any{
+		cp.ControlPlaneResponse{},
+		resources.ControlPlane{},
+	}
 }
 
 // Run executes the create command.
-func (c *createCmd) Run(p pterm.TextPrinter, cc *cp.Client, cfc *configurations.Client, upCtx *upbound.Context) error {
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter, printer upterm.ObjectPrinter, cc *cp.Client, cfc *configurations.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error { //nolint:gocyclo
+	if sc != nil {
+		if c.IfNotExists {
+			ctp, err := sc.Get(ctx, c.Name)
+			if err == nil {
+				p.Printfln("%s already exists", c.Name)
+				return printer.Print(*ctp, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+			}
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+		o := spaces.ControlPlaneOptions{Labels: c.Labels, Annotations: c.Annotations}
+		if c.Configuration != "" {
+			pkg, err := resolveConfiguration(ctx, c.resolver, c.registry, c.Configuration, c.VersionConstraint)
+			if err != nil {
+				return err
+			}
+			p.Printfln("Using configuration %s", pkg)
+			o.ConfigurationPackage = pkg
+		}
+		if _, err := sc.Create(ctx, c.Name, o); err != nil {
+			// The control plane may have been created since it was looked
+			// up.
+			if c.IfNotExists && kerrors.IsAlreadyExists(err) {
+				p.Printfln("%s already exists", c.Name)
+				return nil
+			}
+			return err
+		}
+		p.Printfln("%s created", c.Name)
+		return nil
+	}
+
+	if c.IfNotExists {
+		ctp, err := cc.Get(ctx, upCtx.Account, c.Name)
+		if err == nil {
+			p.Printfln("%s already exists", c.Name)
+			return printer.Print(*ctp, fieldNames, extractFields)
+		}
+		if !uerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	// Get the UUID from the Configuration name, if it exists.
-	cfg, err := cfc.Get(context.Background(), upCtx.Account, c.ConfigurationName)
+	cfg, err := cfc.Get(ctx, upCtx.Account, c.ConfigurationName)
 	if err != nil {
 		return err
 	}
 
-	if _, err := cc.Create(context.Background(), upCtx.Account, &cp.ControlPlaneCreateParameters{
+	if _, err := cc.Create(ctx, upCtx.Account, &cp.ControlPlaneCreateParameters{
 		Name:            c.Name,
 		Description:     c.Description,
 		ConfigurationID: cfg.ID,
@@ -49,6 +228,44 @@ func (c *createCmd) Run(p pterm.TextPrinter, cc *cp.Client, cfc *configurations.
 		return err
 	}
 
+	if len(c.Labels) > 0 || len(c.Annotations) > 0 {
+		if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+			cfg.SetControlPlaneLabels(upCtx.Account, c.Name, c.Labels)
+			cfg.SetControlPlaneAnnotations(upCtx.Account, c.Name, c.Annotations)
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, errUpdateConfig)
+		}
+	}
+
 	p.Printfln("%s created", c.Name)
 	return nil
 }
+
+// resolveConfiguration returns a reference to the supplied configuration
+// package. References without a tag or digest are resolved to the newest
+// release in the registry that satisfies the supplied constraint, or the
+// newest release if the constraint is empty.
+func resolveConfiguration(ctx context.Context, r *image.Resolver, registry, pkg, constraint string) (string, error) {
+	repo, err := name.NewRepository(pkg, name.WithDefaultRegistry(registry))
+	if err != nil {
+		// The reference is not a bare repository, so it must include a tag
+		// or digest and is used as is.
+		ref, rerr := name.ParseReference(pkg, name.WithDefaultRegistry(registry))
+		if rerr != nil {
+			return "", rerr
+		}
+		if constraint != "" {
+			return "", errors.New(errConstraintTagged)
+		}
+		return ref.Name(), nil
+	}
+	tag, err := r.ResolveTag(ctx, v1beta1.Dependency{
+		Package:     repo.Name(),
+		Constraints: constraint,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, errResolveConfiguration)
+	}
+	return repo.Tag(tag).Name(), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/xpkg/dep/resolver/image"
+)
+
+func TestResolveConfiguration(t *testing.T) {
+	r := image.NewResolver(image.WithFetcher(image.NewMockFetcher(image.WithTags([]string{"v1.0.0", "v1.1.0", "v1.2.0", "v2.0.0-rc.1"}))))
+	type args struct {
+		pkg        string
+		constraint string
+	}
+	type want struct {
+		pkg string
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Latest": {
+			reason: "A reference without a tag should resolve to the latest release.",
+			args: args{
+				pkg: "acme/platform",
+			},
+			want: want{
+				pkg: "xpkg.upbound.io/acme/platform:v1.2.0",
+			},
+		},
+		"Constraint": {
+			reason: "A reference without a tag should resolve to the latest release matching the constraint.",
+			args: args{
+				pkg:        "xpkg.upbound.io/acme/platform",
+				constraint: "<1.2",
+			},
+			want: want{
+				pkg: "xpkg.upbound.io/acme/platform:v1.1.0",
+			},
+		},
+		"Tagged": {
+			reason: "A reference with a tag should be used as is.",
+			args: args{
+				pkg: "xpkg.upbound.io/acme/platform:v1.0.0",
+			},
+			want: want{
+				pkg: "xpkg.upbound.io/acme/platform:v1.0.0",
+			},
+		},
+		"TaggedWithConstraint": {
+			reason: "A constraint cannot be applied to a reference with a tag.",
+			args: args{
+				pkg:        "xpkg.upbound.io/acme/platform:v1.0.0",
+				constraint: "^1",
+			},
+			want: want{
+				err: errors.New(errConstraintTagged),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg, err := resolveConfiguration(context.Background(), r, "xpkg.upbound.io", tc.args.pkg, tc.args.constraint)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveConfiguration(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pkg, pkg); diff != "" {
+				t.Errorf("\n%s\nresolveConfiguration(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,23 +16,152 @@ package controlplane
 
 import (
 	"context"
+	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
 
 	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/journal"
+	"github.com/upbound/up/internal/spaces"
 	"github.com/upbound/up/internal/upbound"
 )
 
+const (
+	errNameOrExpired   = "either a control plane name or --expired must be provided"
+	errRetainExpired   = "--retain cannot be used with --expired"
+	errResumeExpired   = "--resume can only be used with --expired"
+	errFmtDeleteFailed = "failed to delete %d of %d control planes"
+)
+
+// AfterApply validates the delete command after assignment.
+func (c *deleteCmd) AfterApply() error {
+	if c.Expired == (c.Name != "") {
+		return errors.New(errNameOrExpired)
+	}
+	if c.Expired && c.Retain > 0 {
+		return errors.New(errRetainExpired)
+	}
+	if c.Resume != "" && !c.Expired {
+		return errors.New(errResumeExpired)
+	}
+	return nil
+}
+
 // deleteCmd deletes a control plane on Upbound.
 type deleteCmd struct {
-	Name string `arg:"" help:"Name of control plane." predictor:"ctps"`
+	Name string `arg:"" optional:"" help:"Name of control plane." predictor:"ctps"`
+
+	Retain  time.Duration `help:"Schedule the control plane for deletion after this duration, e.g. 24h, instead of deleting it immediately."`
+	Expired bool          `help:"Delete the control planes in the account whose scheduled deletion is due."`
+	Resume  string        `help:"ID of a partially failed --expired operation to resume. Control planes it already deleted are skipped."`
+}
+
+// Help returns the help text for the delete command.
+func (c *deleteCmd) Help() string {
+	return `
+The Upbound API does not support scheduled deletion, so control planes deleted
+with --retain are tracked in the local config file and are not deleted until
+'up controlplane delete --expired' is run after the retention period. Use
+'up controlplane restore' to cancel a scheduled deletion.`
 }
 
 // Run executes the delete command.
-func (c *deleteCmd) Run(p pterm.TextPrinter, cc *cp.Client, upCtx *upbound.Context) error {
-	if err := cc.Delete(context.Background(), upCtx.Account, c.Name); err != nil {
+func (c *deleteCmd) Run(ctx context.Context, p pterm.TextPrinter, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error {
+	switch {
+	case c.Retain > 0:
+		return c.schedule(ctx, p, cc, sc, upCtx)
+	case c.Expired:
+		return c.deleteExpired(ctx, p, cc, sc, upCtx)
+	}
+	if err := deleteControlPlane(ctx, cc, sc, upCtx, c.Name); err != nil {
 		return err
 	}
 	p.Printfln("%s deleted", c.Name)
 	return nil
 }
+
+// schedule records the control plane for deletion once the retention period
+// has passed.
+func (c *deleteCmd) schedule(ctx context.Context, p pterm.TextPrinter, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error {
+	// Ensure the control plane exists before scheduling its deletion.
+	if sc != nil {
+		if _, err := sc.Get(ctx, c.Name); err != nil {
+			return err
+		}
+	} else if _, err := cc.Get(ctx, upCtx.Account, c.Name); err != nil {
+		return err
+	}
+	at := time.Now().Add(c.Retain).Truncate(time.Second)
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		cfg.SetPendingDeletion(upCtx.Account, c.Name, at)
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, errUpdateConfig)
+	}
+	p.Printfln("%s scheduled for deletion at %s", c.Name, at.Format(time.RFC3339))
+	p.Printfln("Run 'up controlplane restore %s' to cancel, or 'up controlplane delete --expired' after that time to delete it.", c.Name)
+	return nil
+}
+
+// deleteExpired deletes the control planes whose scheduled deletion is due.
+func (c *deleteCmd) deleteExpired(ctx context.Context, p pterm.TextPrinter, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error {
+	store, err := journal.NewStore()
+	if err != nil {
+		return err
+	}
+	j, err := store.Start("controlplane-delete-expired/"+upCtx.Account, c.Resume)
+	if err != nil {
+		return err
+	}
+	due, pending := upCtx.Cfg.GetPendingDeletions(upCtx.Account, time.Now())
+	failed := 0
+	for _, name := range due {
+		if j.Done(name) {
+			p.Printfln("%s already deleted", name)
+			continue
+		}
+		err := deleteControlPlane(ctx, cc, sc, upCtx, name)
+		if jerr := j.Record(name, err); jerr != nil {
+			return jerr
+		}
+		if err != nil {
+			failed++
+			p.Printfln("%s could not be deleted: %s", name, err)
+			continue
+		}
+		p.Printfln("%s deleted", name)
+	}
+	for _, name := range pending {
+		at, _ := upCtx.Cfg.GetPendingDeletion(upCtx.Account, name)
+		p.Printfln("%s scheduled for deletion at %s", name, at.Format(time.RFC3339))
+	}
+	if failed > 0 {
+		p.Println(j.ResumeHint())
+		return errors.Errorf(errFmtDeleteFailed, failed, len(due))
+	}
+	return j.Finish()
+}
+
+// deleteControlPlane deletes the named control plane and any local state
+// stored for it.
+func deleteControlPlane(ctx context.Context, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context, name string) error {
+	if sc != nil {
+		if err := sc.Delete(ctx, name); err != nil {
+			return err
+		}
+	} else if err := cc.Delete(ctx, upCtx.Account, name); err != nil {
+		return err
+	}
+	_, pending := upCtx.Cfg.GetPendingDeletion(upCtx.Account, name)
+	if upCtx.Cfg.GetControlPlaneLabels(upCtx.Account, name) == nil && upCtx.Cfg.GetControlPlaneAnnotations(upCtx.Account, name) == nil && !pending {
+		return nil
+	}
+	return errors.Wrap(upCtx.ModifyConfig(func(cfg *config.Config) error {
+		cfg.RemoveControlPlaneLabels(upCtx.Account, name)
+		cfg.RemoveControlPlaneAnnotations(upCtx.Account, name)
+		cfg.RemovePendingDeletion(upCtx.Account, name)
+		return nil
+	}), errUpdateConfig)
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	uerrors "github.com/upbound/up-sdk-go/errors"
+	"github.com/upbound/up-sdk-go/service/tokens"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errDeleteSecrets   = "unable to delete MCP Connector secrets"
+	errGetTokenRecord  = "unable to read the recorded API token of the MCP Connector"
+	errDeleteRecord    = "unable to delete the recorded API token of the MCP Connector"
+	errFmtParseTokenID = "invalid ID recorded for the API token %s"
+	errFmtRevokeToken  = "unable to revoke the API token %s, delete it in the Upbound console instead"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *disconnectCmd) AfterApply(upCtx *upbound.Context) error {
+	kubeconfig, err := c.kubeconfig(upCtx)
+	if err != nil {
+		return err
+	}
+	mgr, err := c.manager(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.mgr = mgr
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.kClient = client
+	return nil
+}
+
+// disconnectCmd disconnects the current cluster from a control plane by
+// removing the MCP Connector.
+type disconnectCmd struct {
+	mgr     install.Manager
+	kClient kubernetes.Interface
+
+	connectorParams
+}
+
+// Help returns the help text for the disconnect command.
+func (c *disconnectCmd) Help() string {
+	return `
+Uninstalls the MCP Connector from the current cluster and removes the secrets
+it created. If 'up controlplane connect' created the API token of the
+connector, the token is revoked. Tokens supplied with --token are left alone.`
+}
+
+// Run executes the disconnect command.
+func (c *disconnectCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if _, err := c.mgr.GetCurrentVersion(); err != nil {
+		return errors.Wrap(err, errNotConnected)
+	}
+	p.Printfln("Uninstalling %s from %s.", connectorName, c.InstallationNamespace)
+	if err := c.mgr.Uninstall(); err != nil {
+		return err
+	}
+	// Secrets created by the connector at runtime are not owned by the Helm
+	// release and are left behind by the uninstall.
+	if err := c.kClient.CoreV1().Secrets(c.InstallationNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", connectorName),
+	}); err != nil {
+		return errors.Wrap(err, errDeleteSecrets)
+	}
+	name, err := revokeToken(ctx, c.kClient, c.InstallationNamespace, func(ctx context.Context, id uuid.UUID) error {
+		cfg, err := upCtx.BuildSDKConfig()
+		if err != nil {
+			return err
+		}
+		return tokens.NewClient(cfg).Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		p.Printfln("Revoked the API token named %s.", name)
+	}
+	p.Println("Disconnected from the control plane.")
+	return nil
+}
+
+// revokeToken revokes the API token that connect recorded in the supplied
+// namespace with the supplied delete function, and returns its name. Nothing
+// is revoked if no token was recorded, i.e. it was supplied with --token.
+func revokeToken(ctx context.Context, client kubernetes.Interface, namespace string, del func(context.Context, uuid.UUID) error) (string, error) {
+	cms := client.CoreV1().ConfigMaps(namespace)
+	cm, err := cms.Get(ctx, tokenConfigMapName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, errGetTokenRecord)
+	}
+	name := cm.Data[tokenNameKey]
+	id, err := uuid.Parse(cm.Data[tokenIDKey])
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtParseTokenID, name)
+	}
+	// The token may have been deleted in the Upbound console already.
+	if err := del(ctx, id); err != nil && !uerrors.IsNotFound(err) {
+		return "", errors.Wrapf(err, errFmtRevokeToken, name)
+	}
+	if err := cms.Delete(ctx, tokenConfigMapName, metav1.DeleteOptions{}); err != nil {
+		return "", errors.Wrap(err, errDeleteRecord)
+	}
+	return name, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	uerrors "github.com/upbound/up-sdk-go/errors"
+)
+
+func TestRevokeToken(t *testing.T) {
+	errBoom := errors.New("boom")
+	id := uuid.MustParse("6f1a9a9e-3c1e-4d3b-9e55-0a5e3b1c2d4f")
+	record := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: tokenConfigMapName, Namespace: "kube-system"},
+			Data:       map[string]string{tokenIDKey: id.String(), tokenNameKey: "prod"},
+		}
+	}
+
+	type want struct {
+		name    string
+		deleted []uuid.UUID
+		kept    bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		client *fake.Clientset
+		delErr error
+		want   want
+	}{
+		"NotRecorded": {
+			reason: "Nothing should be revoked if connect did not create the token.",
+			client: fake.NewSimpleClientset(),
+		},
+		"Revoked": {
+			reason: "The recorded token should be revoked and its record deleted.",
+			client: fake.NewSimpleClientset(record()),
+			want: want{
+				name:    "prod",
+				deleted: []uuid.UUID{id},
+			},
+		},
+		"AlreadyDeleted": {
+			reason: "A token that no longer exists should be treated as revoked.",
+			client: fake.NewSimpleClientset(record()),
+			delErr: &uerrors.Error{Status: http.StatusNotFound},
+			want: want{
+				name:    "prod",
+				deleted: []uuid.UUID{id},
+			},
+		},
+		"ErrRevoke": {
+			reason: "Errors revoking the token should name it, and its record should be kept.",
+			client: fake.NewSimpleClientset(record()),
+			delErr: errBoom,
+			want: want{
+				deleted: []uuid.UUID{id},
+				kept:    true,
+				err:     errors.Wrapf(errBoom, errFmtRevokeToken, "prod"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			deleted := []uuid.UUID{}
+			got, err := revokeToken(ctx, tc.client, "kube-system", func(_ context.Context, id uuid.UUID) error {
+				deleted = append(deleted, id)
+				return tc.delErr
+			})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrevokeToken(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nrevokeToken(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if tc.want.deleted == nil {
+				tc.want.deleted = []uuid.UUID{}
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nrevokeToken(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+			_, err = tc.client.CoreV1().ConfigMaps("kube-system").Get(ctx, tokenConfigMapName, metav1.GetOptions{})
+			if diff := cmp.Diff(tc.want.kept, !kerrors.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nrevokeToken(...): -want record kept, +got record kept:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
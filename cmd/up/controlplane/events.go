@@ -0,0 +1,244 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/upbound/up-sdk-go/service/common"
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errEventsNameOrAll  = "either control plane names or --all must be supplied"
+	errEventsNameAndAll = "control plane names cannot be combined with --all"
+	errFmtListEvents    = "unable to list events of control plane %s"
+	errFmtWatchEvents   = "stopped watching events of control plane %s"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *eventsCmd) AfterApply(kongCtx *kong.Context) error {
+	switch {
+	case c.All && len(c.Names) > 0:
+		return errors.New(errEventsNameAndAll)
+	case !c.All && len(c.Names) == 0:
+		return errors.New(errEventsNameOrAll)
+	}
+	c.stdin = os.Stdin
+	c.stdout = kongCtx.Stdout
+	return nil
+}
+
+// eventsCmd streams the events of one or more control planes.
+type eventsCmd struct {
+	stdin  io.Reader
+	stdout io.Writer
+
+	Names []string `arg:"" optional:"" name:"control-plane-name" help:"Names of the control planes to stream events from." predictor:"ctps"`
+
+	Token      string        `required:"" help:"API token used to authenticate. If '-' is given the value will be read from stdin."`
+	All        bool          `help:"Stream events from every control plane in the account."`
+	Crossplane bool          `help:"Only show events of Crossplane resources, such as packages, claims, composite resources, and managed resources."`
+	Since      time.Duration `default:"1h" help:"Show events that occurred this long before streaming starts. Zero shows only new events."`
+}
+
+// Help returns the help text for the events command.
+func (c *eventsCmd) Help() string {
+	return `
+Streams the events of every namespace in the control planes until interrupted.
+When events of more than one control plane are streamed, each event is prefixed
+with the name of its control plane. Events are considered to be of Crossplane
+resources if their object belongs to an API group defined by a custom resource,
+rather than a built-in Kubernetes API group.`
+}
+
+// sourcedEvent is an event and the control plane it occurred in.
+type sourcedEvent struct {
+	ctp   string
+	event *corev1.Event
+}
+
+// Unbounded returns true as the events command runs until interrupted.
+func (c *eventsCmd) Unbounded() bool {
+	return true
+}
+
+// Run executes the events command.
+func (c *eventsCmd) Run(ctx context.Context, cc *cp.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	token, err := readToken(c.stdin, c.Token)
+	if err != nil {
+		return err
+	}
+	names := c.Names
+	if c.All {
+		l, err := cc.List(ctx, upCtx.Account, common.WithSize(maxItems))
+		if err != nil {
+			return err
+		}
+		for _, ctp := range l.ControlPlanes {
+			names = append(names, ctp.ControlPlane.Name)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	events := make(chan sourcedEvent)
+	errs := make(chan error, len(names))
+	for _, name := range names {
+		cfg, err := controlPlaneConfig(upCtx, name, token)
+		if err != nil {
+			return err
+		}
+		kClient, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return err
+		}
+		go func(name string, ei typedcorev1.EventInterface) {
+			errs <- streamEvents(ctx, name, ei, time.Now().Add(-c.Since), events)
+		}(name, kClient.CoreV1().Events(metav1.NamespaceAll))
+	}
+
+	// Events are printed from a single goroutine so that the streams of
+	// control planes are not interleaved within a line.
+	running := len(names)
+	var failed error
+	for running > 0 {
+		select {
+		case e := <-events:
+			if c.Crossplane && !crossplaneRelated(e.event.InvolvedObject) {
+				continue
+			}
+			prefix := ""
+			if len(names) > 1 {
+				prefix = e.ctp
+			}
+			fmt.Fprintln(c.stdout, formatEvent(prefix, e.event)) //nolint:errcheck
+		case err := <-errs:
+			running--
+			if err != nil {
+				fmt.Fprintln(c.stdout, err) //nolint:errcheck
+				failed = err
+			}
+		}
+	}
+	return failed
+}
+
+// streamEvents sends the events of a control plane that occurred after the
+// supplied time, and then every new or updated event until the context is
+// done.
+func streamEvents(ctx context.Context, ctp string, ei typedcorev1.EventInterface, after time.Time, out chan<- sourcedEvent) error {
+	l, err := ei.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, errFmtListEvents, ctp)
+	}
+	sort.SliceStable(l.Items, func(i, j int) bool {
+		return kube.EventTime(l.Items[i]).Before(kube.EventTime(l.Items[j]))
+	})
+	for i := range l.Items {
+		if kube.EventTime(l.Items[i]).After(after) {
+			select {
+			case out <- sourcedEvent{ctp: ctp, event: &l.Items[i]}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+	// The retry watcher resumes watching from the last observed resource
+	// version if the connection to the control plane is interrupted.
+	w, err := watchtools.NewRetryWatcher(l.ResourceVersion, &cache.ListWatch{
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			return ei.Watch(ctx, o)
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, errFmtWatchEvents, ctp)
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return errors.Errorf(errFmtWatchEvents, ctp)
+			}
+			ev, ok := e.Object.(*corev1.Event)
+			if !ok || (e.Type != watch.Added && e.Type != watch.Modified) {
+				if e.Type == watch.Error {
+					return errors.Wrapf(apiError(e.Object), errFmtWatchEvents, ctp)
+				}
+				continue
+			}
+			select {
+			case out <- sourcedEvent{ctp: ctp, event: ev}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// apiError returns the error reported by a watch error event.
+func apiError(obj runtime.Object) error {
+	if s, ok := obj.(*metav1.Status); ok {
+		return errors.New(s.Message)
+	}
+	return errors.New("unknown error")
+}
+
+// crossplaneRelated returns true if the object belongs to an API group defined
+// by a custom resource. Built-in API groups either have no dot, such as apps,
+// or are subdomains of k8s.io.
+func crossplaneRelated(obj corev1.ObjectReference) bool {
+	group := schema.FromAPIVersionAndKind(obj.APIVersion, obj.Kind).Group
+	return strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io")
+}
+
+// formatEvent formats an event on a single line, prefixed with the control
+// plane it occurred in if a prefix is supplied.
+func formatEvent(prefix string, e *corev1.Event) string {
+	obj := strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name
+	if e.InvolvedObject.Namespace != "" {
+		obj = e.InvolvedObject.Namespace + "/" + obj
+	}
+	line := fmt.Sprintf("%s  %-7s  %s  %s: %s", kube.EventTime(*e).Format(time.RFC3339), e.Type, e.Reason, obj, e.Message)
+	if prefix != "" {
+		line = fmt.Sprintf("[%s] %s", prefix, line)
+	}
+	return line
+}
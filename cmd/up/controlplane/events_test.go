@@ -0,0 +1,96 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCrossplaneRelated(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		obj    corev1.ObjectReference
+		want   bool
+	}{
+		"Core": {
+			reason: "Objects in the core API group are not Crossplane resources.",
+			obj:    corev1.ObjectReference{APIVersion: "v1", Kind: "Pod"},
+		},
+		"BuiltIn": {
+			reason: "Objects in built-in API groups are not Crossplane resources.",
+			obj:    corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment"},
+		},
+		"KubernetesSubdomain": {
+			reason: "Objects in k8s.io API groups are not Crossplane resources.",
+			obj:    corev1.ObjectReference{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		},
+		"Package": {
+			reason: "Packages are Crossplane resources.",
+			obj:    corev1.ObjectReference{APIVersion: "pkg.crossplane.io/v1", Kind: "Provider"},
+			want:   true,
+		},
+		"Managed": {
+			reason: "Managed resources are Crossplane resources.",
+			obj:    corev1.ObjectReference{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket"},
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := crossplaneRelated(tc.obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncrossplaneRelated(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	e := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "default", Name: "data"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "ComposeResources",
+		Message:        "cannot compose resources",
+		LastTimestamp:  metav1.NewTime(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	cases := map[string]struct {
+		reason string
+		prefix string
+		want   string
+	}{
+		"NoPrefix": {
+			reason: "An event should be formatted without a prefix if none is supplied.",
+			want:   "2023-10-01T12:00:00Z  Warning  ComposeResources  default/bucket/data: cannot compose resources",
+		},
+		"Prefix": {
+			reason: "An event should be prefixed with its control plane.",
+			prefix: "prod",
+			want:   "[prod] 2023-10-01T12:00:00Z  Warning  ComposeResources  default/bucket/data: cannot compose resources",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := formatEvent(tc.prefix, e)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nformatEvent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
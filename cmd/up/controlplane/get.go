@@ -23,6 +23,8 @@ import (
 
 	cp "github.com/upbound/up-sdk-go/service/controlplanes"
 
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 )
@@ -38,11 +40,28 @@ func (c *getCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error
 // getCmd gets a single control plane in an account on Upbound.
 type getCmd struct {
 	Name string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{
+		cp.ControlPlaneResponse{},
+		resources.ControlPlane{},
+	}
 }
 
 // Run executes the get command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, cc *cp.Client, upCtx *upbound.Context) error {
-	ctp, err := cc.Get(context.Background(), upCtx.Account, c.Name)
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error {
+	if sc != nil {
+		ctp, err := sc.Get(ctx, c.Name)
+		if err != nil {
+			return err
+		}
+		return printer.Print(*ctp, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+	}
+	ctp, err := cc.Get(ctx, upCtx.Account, c.Name)
 	if err != nil {
 		return err
 	}
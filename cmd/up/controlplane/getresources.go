@@ -0,0 +1,139 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/alecthomas/kong"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/trace"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+var resourceFieldNames = []string{"NAME", "NAMESPACE", "SYNCED", "READY", "MESSAGE"}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *getResourcesCmd) AfterApply(kongCtx *kong.Context) error {
+	c.stdin = os.Stdin
+	c.stdout = kongCtx.Stdout
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// getResourcesCmd lists the claims and composite resources in a control
+// plane.
+type getResourcesCmd struct {
+	stdin  io.Reader
+	stdout io.Writer
+
+	Name  string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+	Token string `required:"" help:"API token used to authenticate. If '-' is given the value will be read from stdin."`
+	Tree  bool   `help:"Show the resources composed by each claim and by each composite resource that is not claimed."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the get-resources command.
+func (c *getResourcesCmd) Help() string {
+	return `
+Lists the claims and composite resources of every kind defined in the control
+plane along with their Synced and Ready conditions, and the message of any
+condition that is not true. With --tree, each claim is shown with its composite
+resource and the managed resources it composes, in the same way as the trace
+command of the Crossplane CLI. Composite resources that are bound to a claim
+are only shown as part of the tree of their claim.`
+}
+
+// PrintedObjects returns the objects printed by the get-resources command.
+func (c *getResourcesCmd) PrintedObjects() []any {
+	return []any{&trace.Resource{}}
+}
+
+// Run executes the get-resources command.
+func (c *getResourcesCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, upCtx *upbound.Context) error {
+	token, err := readToken(c.stdin, c.Token)
+	if err != nil {
+		return err
+	}
+	cfg, err := controlPlaneConfig(upCtx, c.Name, token)
+	if err != nil {
+		return err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	claims, xrs, err := trace.ListComposites(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(claims)+len(xrs) == 0 {
+		p.Printfln("No claims or composite resources found in %s", c.Name)
+		return nil
+	}
+
+	if !c.Tree {
+		items := make([]*trace.Resource, 0, len(claims)+len(xrs))
+		for i := range claims {
+			items = append(items, trace.NewResource(&claims[i]))
+		}
+		for i := range xrs {
+			items = append(items, trace.NewResource(&xrs[i]))
+		}
+		return printer.Print(items, resourceFieldNames, extractResourceFields)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	w := trace.NewWalker(client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)))
+	trees := make([]*trace.Resource, 0, len(claims)+len(xrs))
+	for i := range claims {
+		trees = append(trees, w.FromObject(ctx, &claims[i]))
+	}
+	for i := range xrs {
+		if trace.Claimed(xrs[i]) {
+			continue
+		}
+		trees = append(trees, w.FromObject(ctx, &xrs[i]))
+	}
+	// Trees are only printed as text by default, as they do not fit in a
+	// table.
+	if printer.Output.IsSet() || printer.Format != config.Default {
+		return printer.Print(trees, resourceFieldNames, extractResourceFields)
+	}
+	for _, t := range trees {
+		if err := trace.Print(c.stdout, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractResourceFields(obj any) []string {
+	r := obj.(*trace.Resource)
+	return []string{r.Ref.String(), r.Namespace, r.Synced, r.Ready, r.Message}
+}
@@ -15,20 +15,48 @@
 package kubeconfig
 
 import (
+	"context"
 	"io"
 	"os"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/upbound/up-sdk-go/service/common"
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
 
 	"github.com/upbound/up/internal/kube"
 	"github.com/upbound/up/internal/upbound"
 )
 
+const (
+	errNameOrAll   = "either a control plane name or --all must be supplied"
+	errNameAndAll  = "a control plane name cannot be combined with --all"
+	errReadOnlyAll = "--read-only cannot be combined with --all"
+
+	// minReadOnlyExpiry is the shortest expiry the TokenRequest API accepts.
+	minReadOnlyExpiry = 10 * time.Minute
+
+	errReadOnlyExpiry = "--read-only-expiry must be at least 10m"
+)
+
 // AfterApply sets default values in command after assignment and validation.
 func (c *getCmd) AfterApply(upCtx *upbound.Context) error {
 	c.stdin = os.Stdin
+	switch {
+	case c.All && c.Name != "":
+		return errors.New(errNameAndAll)
+	case !c.All && c.Name == "":
+		return errors.New(errNameOrAll)
+	case c.All && c.ReadOnly:
+		return errors.New(errReadOnlyAll)
+	case c.ReadOnly && c.ReadOnlyExpiry < minReadOnlyExpiry:
+		return errors.New(errReadOnlyExpiry)
+	}
 	return nil
 }
 
@@ -38,12 +66,46 @@ type getCmd struct {
 
 	File  string `type:"path" short:"f" help:"File to merge kubeconfig."`
 	Token string `required:"" help:"API token used to authenticate."`
+	All   bool   `help:"Get kubeconfig contexts for every control plane in the account."`
 
-	Name string `arg:"" name:"control-plane-name" required:"" help:"Name of control plane." predictor:"ctps"`
+	ReadOnly       bool          `help:"Provision a read-only identity in the control plane and use it in the kubeconfig instead. Only works where the API server of the control plane is directly reachable."`
+	ReadOnlyName   string        `default:"up-read-only" help:"Name of the ServiceAccount that is granted read-only access."`
+	ReadOnlyExpiry time.Duration `default:"24h" help:"How long the token of the read-only identity is valid."`
+	ReadOnlyServer string        `help:"URL of the API server of the control plane used with the read-only identity. Defaults to the server published in the cluster-info ConfigMap of the control plane."`
+	ReadOnlyCA     string        `name:"read-only-certificate-authority" type:"existingfile" help:"File of the certificate authority of the API server given with --read-only-server."`
+
+	Name string `arg:"" optional:"" name:"control-plane-name" help:"Name of control plane." predictor:"ctps"`
+}
+
+// Help returns the help text for the get command.
+func (c *getCmd) Help() string {
+	return `
+Contexts are named upbound-<account>-<control plane>. With --all, a context is
+added or refreshed for every control plane in the account and the current
+context is left unchanged. Unlike a single control plane, connectivity to each
+control plane is not verified.
+
+With --read-only, the token is only used to provision a ServiceAccount in the
+control plane that can read but not modify resources, and the kubeconfig uses a
+token of that ServiceAccount that expires after --read-only-expiry instead. Its
+context is named upbound-<account>-<control plane>-read-only. Use
+--read-only-name to provision separate identities, e.g. for auditors and
+dashboards. Access is revoked when the token expires, or by deleting the
+ServiceAccount from the kube-system namespace of the control plane. A
+ServiceAccount or ClusterRoleBinding of the same name that up did not create,
+i.e. that lacks the app.kubernetes.io/managed-by=up label, is never modified
+and the command fails instead.
+
+The Upbound proxy only accepts Upbound tokens, so the read-only context connects
+to the API server of the control plane directly. It therefore only works where
+that API server is reachable without the proxy, e.g. from within the network of
+a Space. Its URL and certificate authority are read from the cluster-info
+ConfigMap of the kube-public namespace unless the server is given with
+--read-only-server.`
 }
 
 // Run executes the get command.
-func (c *getCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+func (c *getCmd) Run(ctx context.Context, p pterm.TextPrinter, cc *cp.Client, upCtx *upbound.Context) error {
 	// TODO(hasheddan): consider implementing a custom decoder
 	if c.Token == "-" {
 		b, err := io.ReadAll(c.stdin)
@@ -52,12 +114,59 @@ func (c *getCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 		}
 		c.Token = strings.TrimSpace(string(b))
 	}
+	if c.All {
+		return c.getAll(ctx, p, cc, upCtx)
+	}
 	mcpConf := kube.BuildControlPlaneKubeconfig(upCtx.ProxyEndpoint, path.Join(upCtx.Account, c.Name), c.Token)
+	var expiry time.Time
+	if c.ReadOnly {
+		ro, exp, err := c.readOnlyKubeconfig(ctx, mcpConf, upCtx)
+		if err != nil {
+			return err
+		}
+		mcpConf, expiry = ro, exp
+	}
 	if err := kube.ApplyControlPlaneKubeconfig(mcpConf, c.File, upCtx.WrapTransport); err != nil {
 		return err
 	}
 	if c.File == "" {
 		p.Printfln("Current context set to %s", mcpConf.CurrentContext)
 	}
+	if c.ReadOnly {
+		p.Printfln("Read-only token expires at %s", expiry.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// getAll merges a context for every control plane in the account into the
+// kubeconfig.
+func (c *getCmd) getAll(ctx context.Context, p pterm.TextPrinter, cc *cp.Client, upCtx *upbound.Context) error {
+	l, err := cc.List(ctx, upCtx.Account, common.WithSize(maxItems))
+	if err != nil {
+		return err
+	}
+	if len(l.ControlPlanes) == 0 {
+		p.Printfln("No control planes found in %s", upCtx.Account)
+		return nil
+	}
+	po, conf, err := loadKubeconfig(c.File)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(l.ControlPlanes))
+	for _, ctp := range l.ControlPlanes {
+		mcpConf := kube.BuildControlPlaneKubeconfig(upCtx.ProxyEndpoint, path.Join(upCtx.Account, ctp.ControlPlane.Name), c.Token)
+		kube.MergeKubeconfig(conf, mcpConf)
+		names = append(names, mcpConf.CurrentContext)
+	}
+	if err := clientcmd.ModifyConfig(po, *conf, true); err != nil {
+		return err
+	}
+	for _, n := range names {
+		p.Printfln("%s updated", n)
+	}
+	if len(l.ControlPlanes) >= maxItems {
+		p.Printfln("Only the first %d control planes in %s were included", maxItems, upCtx.Account)
+	}
 	return nil
 }
@@ -27,5 +27,7 @@ func (c *Cmd) BeforeReset(p *kong.Path, maturity feature.Maturity) error {
 
 // Cmd contains commands for managing control plane kubeconfig data.
 type Cmd struct {
-	Get getCmd `cmd:"" help:"Get a kubeconfig for a control plane."`
+	Get   getCmd   `cmd:"" help:"Get a kubeconfig for a control plane."`
+	List  listCmd  `cmd:"" help:"List kubeconfig contexts of control planes."`
+	Prune pruneCmd `cmd:"" help:"Remove kubeconfig contexts of deleted control planes."`
 }
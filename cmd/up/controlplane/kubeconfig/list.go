@@ -0,0 +1,57 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pterm/pterm"
+
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// listCmd lists the kubeconfig contexts generated for control planes.
+type listCmd struct {
+	File string `type:"path" short:"f" help:"Kubeconfig file. Defaults to the same file as kubectl."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{controlPlaneContext{}}
+}
+
+// Run executes the list command.
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *cp.Client) error {
+	_, conf, err := loadKubeconfig(c.File)
+	if err != nil {
+		return err
+	}
+	ctxs := controlPlaneStatuses(ctx, cc, conf)
+	if len(ctxs) == 0 {
+		p.Println("No control plane contexts found")
+		return nil
+	}
+	return printer.Print(ctxs, listFieldNames, extractListFields)
+}
+
+func extractListFields(obj any) []string {
+	c := obj.(controlPlaneContext)
+	return []string{c.Context, c.Account, c.ControlPlane, strconv.FormatBool(c.Current), c.Status}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/clientcmd"
+
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/kube"
+)
+
+// pruneCmd removes kubeconfig contexts of deleted control planes.
+type pruneCmd struct {
+	File   string `type:"path" short:"f" help:"Kubeconfig file. Defaults to the same file as kubectl."`
+	DryRun bool   `help:"Show the contexts that would be removed without removing them."`
+}
+
+// Help returns the help text for the prune command.
+func (c *pruneCmd) Help() string {
+	return `
+Only contexts of control planes that no longer exist are removed. Contexts of
+control planes whose existence cannot be determined, for example because
+their account cannot be listed with the current profile, are kept. The
+clusters and users of removed contexts are also removed unless another
+context refers to them.`
+}
+
+// Run executes the prune command.
+func (c *pruneCmd) Run(ctx context.Context, p pterm.TextPrinter, cc *cp.Client) error {
+	po, conf, err := loadKubeconfig(c.File)
+	if err != nil {
+		return err
+	}
+	prune := []string{}
+	for _, cpc := range controlPlaneStatuses(ctx, cc, conf) {
+		if cpc.Status == statusDeleted {
+			prune = append(prune, cpc.Context)
+		}
+	}
+	if len(prune) == 0 {
+		p.Println("No contexts of deleted control planes found")
+		return nil
+	}
+	if c.DryRun {
+		for _, n := range prune {
+			p.Printfln("%s would be removed", n)
+		}
+		return nil
+	}
+	current := conf.CurrentContext
+	kube.RemoveContexts(conf, prune...)
+	if err := clientcmd.ModifyConfig(po, *conf, true); err != nil {
+		return err
+	}
+	for _, n := range prune {
+		p.Printfln("%s removed", n)
+	}
+	if conf.CurrentContext != current {
+		p.Printfln("Current context %s was removed and is now unset", current)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	readOnlySuffix = "-read-only"
+
+	errProvisionReadOnly = "unable to provision read-only access"
+	errReadOnlyServer    = "unable to find the API server of the control plane, supply it with --read-only-server"
+	errReadCA            = "unable to read certificate authority"
+)
+
+// readOnlyKubeconfig provisions a read-only identity in the control plane of
+// the supplied kubeconfig, and returns a kubeconfig that authenticates as that
+// identity to the API server of the control plane, along with the expiry of
+// its token.
+func (c *getCmd) readOnlyKubeconfig(ctx context.Context, mcpConf *api.Config, upCtx *upbound.Context) (*api.Config, time.Time, error) {
+	rc, err := clientcmd.NewDefaultClientConfig(*mcpConf, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, errProvisionReadOnly)
+	}
+	if upCtx.WrapTransport != nil {
+		rc.Wrap(upCtx.WrapTransport)
+	}
+	client, err := kubernetes.NewForConfig(rc)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, errProvisionReadOnly)
+	}
+	cluster, err := c.readOnlyCluster(ctx, client)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	token, err := kube.ProvisionReadOnlyToken(ctx, client, c.ReadOnlyName, c.ReadOnlyExpiry)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, errProvisionReadOnly)
+	}
+	return readOnlyContext(mcpConf, cluster, token.Token), token.Expiry, nil
+}
+
+// readOnlyCluster returns the API server of the control plane, either as
+// supplied by flags or as published by the control plane.
+func (c *getCmd) readOnlyCluster(ctx context.Context, client kubernetes.Interface) (*api.Cluster, error) {
+	if c.ReadOnlyServer == "" {
+		cluster, err := kube.ClusterInfo(ctx, client)
+		return cluster, errors.Wrap(err, errReadOnlyServer)
+	}
+	cluster := &api.Cluster{Server: c.ReadOnlyServer}
+	if c.ReadOnlyCA != "" {
+		ca, err := os.ReadFile(filepath.Clean(c.ReadOnlyCA))
+		if err != nil {
+			return nil, errors.Wrap(err, errReadCA)
+		}
+		cluster.CertificateAuthorityData = ca
+	}
+	return cluster, nil
+}
+
+// readOnlyContext returns a kubeconfig with a cluster, user and context named
+// after the current context of the supplied control plane kubeconfig with a
+// suffix, so that they are kept alongside those of the original kubeconfig.
+// The user authenticates to the supplied cluster with the supplied token.
+func readOnlyContext(mcpConf *api.Config, cluster *api.Cluster, token string) *api.Config {
+	roKey := mcpConf.CurrentContext + readOnlySuffix
+	conf := api.NewConfig()
+	conf.Clusters[roKey] = cluster
+	conf.AuthInfos[roKey] = &api.AuthInfo{Token: token}
+	conf.Contexts[roKey] = &api.Context{Cluster: roKey, AuthInfo: roKey}
+	conf.CurrentContext = roKey
+	return conf
+}
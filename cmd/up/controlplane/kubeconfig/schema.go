@@ -0,0 +1,40 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"github.com/upbound/up/internal/upterm"
+)
+
+// controlPlaneContextV1 is version v1 of the machine output of a kubeconfig
+// context of a control plane.
+type controlPlaneContextV1 struct {
+	Context      string `json:"context"`
+	Account      string `json:"account"`
+	ControlPlane string `json:"controlPlane"`
+	Current      bool   `json:"current"`
+	Status       string `json:"status"`
+}
+
+func init() {
+	upterm.RegisterSchema(controlPlaneContext{}, upterm.Schema{
+		Kind:    "ControlPlaneContext",
+		Version: "v1",
+		Convert: func(obj any) any {
+			c := obj.(controlPlaneContext)
+			return controlPlaneContextV1{Context: c.Context, Account: c.Account, ControlPlane: c.ControlPlane, Current: c.Current, Status: c.Status}
+		},
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/upbound/up-sdk-go/service/common"
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/kube"
+)
+
+const (
+	// maxItems is the maximum number of control planes listed per account.
+	maxItems = 100
+
+	statusExists  = "exists"
+	statusDeleted = "deleted"
+	statusUnknown = "unknown"
+)
+
+var listFieldNames = []string{"CONTEXT", "ACCOUNT", "CONTROL PLANE", "CURRENT", "STATUS"}
+
+// controlPlaneContext is a control plane context and whether its control
+// plane still exists.
+type controlPlaneContext struct {
+	kube.ControlPlaneContext
+	Status string `json:"status"`
+}
+
+// loadKubeconfig loads the kubeconfig at the supplied path, or the same
+// kubeconfig as kubectl if the path is empty.
+func loadKubeconfig(path string) (*clientcmd.PathOptions, *api.Config, error) {
+	po := clientcmd.NewDefaultPathOptions()
+	po.LoadingRules.ExplicitPath = path
+	conf, err := po.GetStartingConfig()
+	return po, conf, err
+}
+
+// controlPlaneStatuses returns the control plane contexts in the kubeconfig
+// and whether their control planes still exist. A control plane is only
+// reported as deleted if it is missing from a complete listing of its
+// account.
+func controlPlaneStatuses(ctx context.Context, cc *cp.Client, conf *api.Config) []controlPlaneContext {
+	existing := map[string]map[string]bool{}
+	ctxs := kube.ControlPlaneContexts(conf)
+	out := make([]controlPlaneContext, len(ctxs))
+	for i, c := range ctxs {
+		names, ok := existing[c.Account]
+		if !ok {
+			names = listControlPlanes(ctx, cc, c.Account)
+			existing[c.Account] = names
+		}
+		status := statusUnknown
+		switch {
+		case names == nil:
+		case names[c.ControlPlane]:
+			status = statusExists
+		default:
+			status = statusDeleted
+		}
+		out[i] = controlPlaneContext{ControlPlaneContext: c, Status: status}
+	}
+	return out
+}
+
+// listControlPlanes returns the names of the control planes in the account, or
+// nil if they could not be listed completely.
+func listControlPlanes(ctx context.Context, cc *cp.Client, account string) map[string]bool {
+	l, err := cc.List(ctx, account, common.WithSize(maxItems))
+	if err != nil || len(l.ControlPlanes) >= maxItems {
+		return nil
+	}
+	names := make(map[string]bool, len(l.ControlPlanes))
+	for _, c := range l.ControlPlanes {
+		names[c.ControlPlane.Name] = true
+	}
+	return names
+}
@@ -16,13 +16,20 @@ package controlplane
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/upbound/up-sdk-go/service/common"
 	cp "github.com/upbound/up-sdk-go/service/controlplanes"
 
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 )
@@ -33,33 +40,148 @@ const (
 
 const (
 	notAvailable = "n/a"
+
+	errParseSelector = "unable to parse label selector"
 )
 
-var fieldNames = []string{"NAME", "ID", "STATUS", "DEPLOYED CONFIGURATION", "CONFIGURATION STATUS"}
+var (
+	fieldNames     = []string{"NAME", "ID", "STATUS", "DEPLOYED CONFIGURATION", "CONFIGURATION STATUS"}
+	listFieldNames = []string{"NAME", "ID", "STATUS", "DEPLOYED CONFIGURATION", "CONFIGURATION STATUS", "LABELS"}
+)
 
 // AfterApply sets default values in command after assignment and validation.
 func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
 	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	sel, err := labels.Parse(c.Selector)
+	if err != nil {
+		return errors.Wrap(err, errParseSelector)
+	}
+	c.selector = sel
 	return nil
 }
 
+// listedControlPlane is a control plane on Upbound together with the labels
+// and annotations that are stored for it locally, so that they are included
+// in JSON and YAML output.
+type listedControlPlane struct {
+	cp.ControlPlaneResponse `yaml:",inline"`
+
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
 // listCmd list control planes in an account on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	selector labels.Selector
+
+	Selector string             `short:"l" help:"Only list control planes with labels matching this selector, e.g. env=prod,team!=payments."`
+	Output   upterm.OutputFlags `embed:""`
+
+	Watch         bool          `short:"w" help:"Watch for changes, printing the list again or emitting a change event for each control plane with JSON and YAML output."`
+	WatchInterval time.Duration `default:"5s" help:"Interval at which control planes are polled when watching."`
+}
+
+// Unbounded returns true if the list command runs until interrupted.
+func (c *listCmd) Unbounded() bool {
+	return c.Watch
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{
+		listedControlPlane{},
+		resources.ControlPlane{},
+	}
+}
 
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *cp.Client, upCtx *upbound.Context) error {
-	// TODO(hasheddan): we currently just max out single page size, but we
-	// may opt to support limiting page size and iterating through pages via
-	// flags in the future.
-	cpList, err := cc.List(context.Background(), upCtx.Account, common.WithSize(maxItems))
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *cp.Client, sc *spaces.ControlPlaneClient, upCtx *upbound.Context) error {
+	if sc != nil {
+		// Labels of control planes in a Space are stored on the control
+		// plane, so they are selected by the Space cluster.
+		list := func(ctx context.Context) (any, error) {
+			return sc.List(ctx, c.selector.String())
+		}
+		if c.Watch {
+			return printer.Watch(ctx, c.WatchInterval, list, spaceControlPlaneKey, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+		}
+		ctps, err := sc.List(ctx, c.selector.String())
+		if err != nil {
+			return err
+		}
+		if len(ctps) == 0 {
+			p.Printfln("No control planes found")
+			return nil
+		}
+		return printer.Print(ctps, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+	}
+	extract := func(obj any) []string {
+		ctp := obj.(listedControlPlane)
+		return append(extractFields(ctp.ControlPlaneResponse), formatLabels(ctp.Labels))
+	}
+	if c.Watch {
+		list := func(ctx context.Context) (any, error) {
+			return c.list(ctx, cc, upCtx)
+		}
+		return printer.Watch(ctx, c.WatchInterval, list, controlPlaneKey, listFieldNames, extract)
+	}
+	ctps, err := c.list(ctx, cc, upCtx)
 	if err != nil {
 		return err
 	}
-	if len(cpList.ControlPlanes) == 0 {
+	if len(ctps) == 0 {
 		p.Printfln("No control planes found in %s", upCtx.Account)
 		return nil
 	}
-	return printer.Print(cpList.ControlPlanes, fieldNames, extractFields)
+	return printer.Print(ctps, listFieldNames, extract)
+}
+
+// list returns the control planes in the account that match the selector.
+func (c *listCmd) list(ctx context.Context, cc *cp.Client, upCtx *upbound.Context) ([]listedControlPlane, error) {
+	// TODO(hasheddan): we currently just max out single page size, but we
+	// may opt to support limiting page size and iterating through pages via
+	// flags in the future.
+	cpList, err := cc.List(ctx, upCtx.Account, common.WithSize(maxItems))
+	if err != nil {
+		return nil, err
+	}
+	ctps := make([]listedControlPlane, 0, len(cpList.ControlPlanes))
+	for _, ctp := range cpList.ControlPlanes {
+		l := upCtx.Cfg.GetControlPlaneLabels(upCtx.Account, ctp.ControlPlane.Name)
+		if c.selector.Matches(labels.Set(l)) {
+			ctps = append(ctps, listedControlPlane{
+				ControlPlaneResponse: ctp,
+				Labels:               l,
+				Annotations:          upCtx.Cfg.GetControlPlaneAnnotations(upCtx.Account, ctp.ControlPlane.Name),
+			})
+		}
+	}
+	return ctps, nil
+}
+
+// controlPlaneKey identifies a control plane while watching.
+func controlPlaneKey(obj any) string {
+	return obj.(listedControlPlane).ControlPlane.Name
+}
+
+// spaceControlPlaneKey identifies a control plane in a Space while watching.
+func spaceControlPlaneKey(obj any) string {
+	ctp := obj.(resources.ControlPlane)
+	return ctp.GetNamespace() + "/" + ctp.GetName()
+}
+
+// formatLabels formats labels as a sorted, comma separated list of key=value
+// pairs.
+func formatLabels(l map[string]string) string {
+	if len(l) == 0 {
+		return notAvailable
+	}
+	pairs := make([]string, 0, len(l))
+	for k, v := range l {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
 }
 
 func extractFields(obj any) []string {
@@ -0,0 +1,54 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"io"
+	"path"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+// readToken returns the supplied API token, or reads it from stdin if it is
+// '-'.
+func readToken(stdin io.Reader, token string) (string, error) {
+	if token != "-" {
+		return token, nil
+	}
+	b, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// controlPlaneConfig returns the REST config for the control plane with the
+// supplied name in the current account, authenticating with the API token.
+func controlPlaneConfig(upCtx *upbound.Context, name, token string) (*rest.Config, error) {
+	mcpConf := kube.BuildControlPlaneKubeconfig(upCtx.ProxyEndpoint, path.Join(upCtx.Account, name), token)
+	cfg, err := clientcmd.NewDefaultClientConfig(*mcpConf, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if upCtx.WrapTransport != nil {
+		cfg.Wrap(upCtx.WrapTransport)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+)
+
+const (
+	errPauseUnsupported = "pausing control planes is only supported for control planes in a Space, the Upbound API does not support it"
+	errNameOrAll        = "exactly one of a control plane name or --all must be provided"
+	errSelectorWithName = "--selector can only be used with --all"
+	errFmtSetStateFail  = "failed to set the state of %d of %d control planes"
+)
+
+// stateParams select the control planes whose state is changed.
+type stateParams struct {
+	Name     string `arg:"" optional:"" help:"Name of control plane. Required unless --all is supplied." predictor:"ctps"`
+	All      bool   `help:"Apply to all control planes in the Space, or those matching --selector."`
+	Selector string `short:"l" help:"With --all, only apply to control planes with labels matching this selector, e.g. env=dev."`
+}
+
+// validate checks that the parameters select control planes.
+func (p *stateParams) validate() error {
+	if p.All == (p.Name != "") {
+		return errors.New(errNameOrAll)
+	}
+	if p.Selector != "" && !p.All {
+		return errors.New(errSelectorWithName)
+	}
+	if _, err := labels.Parse(p.Selector); err != nil {
+		return errors.Wrap(err, errParseSelector)
+	}
+	return nil
+}
+
+// setState sets the Crossplane state of the selected control planes. All
+// selected control planes are attempted even if some fail.
+func (p *stateParams) setState(ctx context.Context, tp pterm.TextPrinter, sc *spaces.ControlPlaneClient, state, verb string) error {
+	if sc == nil {
+		return errors.New(errPauseUnsupported)
+	}
+	names := []string{p.Name}
+	if p.All {
+		ctps, err := sc.List(ctx, p.Selector)
+		if err != nil {
+			return err
+		}
+		if len(ctps) == 0 {
+			tp.Printfln("No control planes found")
+			return nil
+		}
+		names = make([]string, 0, len(ctps))
+		for _, ctp := range ctps {
+			if ctp.GetCrossplaneState() == state {
+				tp.Printfln("%s already %s", ctp.GetName(), verb)
+				continue
+			}
+			names = append(names, ctp.GetName())
+		}
+	}
+	failed := 0
+	for _, name := range names {
+		if _, err := sc.SetCrossplaneState(ctx, name, state); err != nil {
+			if !p.All {
+				return err
+			}
+			failed++
+			tp.Printfln("%s could not be %s: %s", name, verb, err)
+			continue
+		}
+		tp.Printfln("%s %s", name, verb)
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtSetStateFail, failed, len(names))
+	}
+	return nil
+}
+
+// AfterApply validates the pause command after assignment.
+func (c *pauseCmd) AfterApply() error {
+	return c.validate()
+}
+
+// pauseCmd pauses control planes in a Space.
+type pauseCmd struct {
+	stateParams
+}
+
+// Help returns the help text for the pause command.
+func (c *pauseCmd) Help() string {
+	return `
+Pausing a control plane scales down its Crossplane and providers, so that
+nothing in it is reconciled until it is resumed with 'up controlplane resume'.
+Its resources are kept, and the API server stays available. Pausing is only
+supported for control planes in a Space.`
+}
+
+// Run executes the pause command.
+func (c *pauseCmd) Run(ctx context.Context, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	return c.setState(ctx, p, sc, resources.CrossplaneStatePaused, "paused")
+}
+
+// AfterApply validates the resume command after assignment.
+func (c *resumeCmd) AfterApply() error {
+	return c.validate()
+}
+
+// resumeCmd resumes paused control planes in a Space.
+type resumeCmd struct {
+	stateParams
+}
+
+// Run executes the resume command.
+func (c *resumeCmd) Run(ctx context.Context, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	return c.setState(ctx, p, sc, resources.CrossplaneStateRunning, "resumed")
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+)
+
+func TestStateParamsValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		params stateParams
+		err    error
+	}{
+		"Name": {
+			reason: "A single control plane should be selected by name.",
+			params: stateParams{Name: "dev"},
+		},
+		"AllWithSelector": {
+			reason: "All control planes matching a selector should be selectable.",
+			params: stateParams{All: true, Selector: "env=dev"},
+		},
+		"NameAndAll": {
+			reason: "A name cannot be combined with --all.",
+			params: stateParams{Name: "dev", All: true},
+			err:    errors.New(errNameOrAll),
+		},
+		"Neither": {
+			reason: "Control planes must be selected.",
+			err:    errors.New(errNameOrAll),
+		},
+		"SelectorWithName": {
+			reason: "A selector can only be used with --all.",
+			params: stateParams{Name: "dev", Selector: "env=dev"},
+			err:    errors.New(errSelectorWithName),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.params.validate()
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStateParamsSetState(t *testing.T) {
+	ctx := context.Background()
+	newClient := func() *spaces.ControlPlaneClient {
+		sc := spaces.NewControlPlaneClient(fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			resources.ControlPlaneGVR: "ControlPlaneList",
+		}))
+		for name, env := range map[string]string{"dev-a": "dev", "dev-b": "dev", "prod": "prod"} {
+			_, _ = sc.Create(ctx, name, spaces.ControlPlaneOptions{Labels: map[string]string{"env": env}})
+		}
+		return sc
+	}
+
+	cases := map[string]struct {
+		reason string
+		params stateParams
+		sc     *spaces.ControlPlaneClient
+		paused []string
+		err    error
+	}{
+		"NotSpace": {
+			reason: "Pausing should not be supported for control planes outside of a Space.",
+			params: stateParams{Name: "dev-a"},
+			err:    errors.New(errPauseUnsupported),
+		},
+		"Name": {
+			reason: "The named control plane should be paused.",
+			params: stateParams{Name: "dev-a"},
+			sc:     newClient(),
+			paused: []string{"dev-a"},
+		},
+		"AllWithSelector": {
+			reason: "All control planes matching the selector should be paused.",
+			params: stateParams{All: true, Selector: "env=dev"},
+			sc:     newClient(),
+			paused: []string{"dev-a", "dev-b"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tp := pterm.DefaultBasicText.WithWriter(&bytes.Buffer{})
+			err := tc.params.setState(ctx, tp, tc.sc, resources.CrossplaneStatePaused, "paused")
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nsetState(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.sc == nil {
+				return
+			}
+			ctps, _ := tc.sc.List(ctx, "")
+			paused := []string{}
+			for _, ctp := range ctps {
+				if ctp.GetCrossplaneState() == resources.CrossplaneStatePaused {
+					paused = append(paused, ctp.GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.paused, paused); diff != "" {
+				t.Errorf("\n%s\nsetState(...): -want paused, +got paused:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
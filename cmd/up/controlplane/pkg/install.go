@@ -17,26 +17,39 @@ package pkg
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pterm/pterm"
 	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
+	xpkgcmd "github.com/upbound/up/cmd/up/xpkg"
 	"github.com/upbound/up/internal/kube"
-	"github.com/upbound/up/internal/resources"
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 	"github.com/upbound/up/internal/xpkg"
 )
 
-const errUnknownPkgType = "provided package type is unknown"
+const (
+	errUnknownPkgType  = "provided package type is unknown"
+	errFileDigest      = "--file cannot be used with a package reference that includes a digest"
+	errLoadPackageFile = "unable to load package file"
+	errPushPackageFile = "unable to push package file"
+
+	// devTagPrefix is the prefix of tags generated for package files pushed
+	// without a tag. The digest of the package is appended so that each
+	// build is pushed to a different tag.
+	devTagPrefix = "v0.0.0-dev."
+)
 
 // Supported package kinds.
 const (
@@ -51,11 +64,23 @@ var (
 		Resource: "providers",
 	}
 
+	providerRevisionGVR = schema.GroupVersionResource{
+		Group:    "pkg.crossplane.io",
+		Version:  "v1",
+		Resource: "providerrevisions",
+	}
+
 	configurationGVR = schema.GroupVersionResource{
 		Group:    "pkg.crossplane.io",
 		Version:  "v1",
 		Resource: "configurations",
 	}
+
+	configurationRevisionGVR = schema.GroupVersionResource{
+		Group:    "pkg.crossplane.io",
+		Version:  "v1",
+		Resource: "configurationrevisions",
+	}
 )
 
 // AfterApply constructs and binds Upbound-specific context to any subcommands
@@ -64,9 +89,11 @@ func (c *installCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) e
 	switch kongCtx.Selected().Vars()["package_type"] {
 	case ProviderKind:
 		c.gvr = providerGVR
+		c.revGVR = providerRevisionGVR
 		c.kind = ProviderKind
 	case ConfigurationKind:
 		c.gvr = configurationGVR
+		c.revGVR = configurationRevisionGVR
 		c.kind = ConfigurationKind
 	default:
 		return errors.New(errUnknownPkgType)
@@ -85,31 +112,40 @@ func (c *installCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) e
 		return err
 	}
 	c.r = client.Resource(c.gvr)
+	c.rev = client.Resource(c.revGVR)
 	return nil
 }
 
 // installCmd installs a package.
 type installCmd struct {
-	gvr  schema.GroupVersionResource
-	kind string
+	gvr    schema.GroupVersionResource
+	revGVR schema.GroupVersionResource
+	kind   string
 
-	r dynamic.NamespaceableResourceInterface
+	r   dynamic.NamespaceableResourceInterface
+	rev dynamic.NamespaceableResourceInterface
 
 	Package string `arg:"" help:"Reference to the ${package_type}."`
+	File    string `short:"f" type:"existingfile" help:"Path to a locally built ${package_type} package (.xpkg) that is pushed to the repository of the package reference before it is installed. A development tag is generated unless the reference includes one."`
 
 	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
 	Kubeconfig         string        `type:"existingfile" help:"Override default kubeconfig path."`
 	Name               string        `help:"Name of ${package_type}."`
 	PackagePullSecrets []string      `help:"List of secrets used to pull ${package_type}."`
-	Wait               time.Duration `short:"w" help:"Wait duration for successful ${package_type} installation."`
+	Wait               time.Duration `short:"w" help:"Wait duration for successful ${package_type} installation. Condition changes of the ${package_type} and its revision are shown while waiting."`
 }
 
 // Run executes the install command.
-func (c *installCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+func (c *installCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error {
 	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(upCtx.RegistryEndpoint.Hostname()))
 	if err != nil {
 		return err
 	}
+	if c.File != "" {
+		if ref, err = c.push(ctx, p, upCtx, ref); err != nil {
+			return err
+		}
+	}
 	if c.Name == "" {
 		c.Name = xpkg.ToDNSLabel(ref.Context().RepositoryStr())
 	}
@@ -119,7 +155,7 @@ func (c *installCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 			Name: s,
 		}
 	}
-	if _, err := c.r.Create(context.Background(), &unstructured.Unstructured{Object: map[string]interface{}{
+	if _, err := c.r.Create(ctx, &unstructured.Unstructured{Object: map[string]interface{}{
 		"apiVersion": "pkg.crossplane.io/v1",
 		"kind":       c.kind,
 		"metadata": map[string]interface{}{
@@ -129,7 +165,7 @@ func (c *installCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 			"package":            ref.Name(),
 			"packagePullSecrets": packagePullSecrets,
 		},
-	}}, v1.CreateOptions{}); err != nil {
+	}}, metav1.CreateOptions{}); err != nil {
 		return err
 	}
 
@@ -139,25 +175,53 @@ func (c *installCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 		return nil
 	}
 
-	s, _ := upterm.CheckmarkSuccessSpinner.Start(fmt.Sprintf("%s installed. Waiting to become healthy...", c.Name))
+	waiting := fmt.Sprintf("%s installed. Waiting to become healthy...", c.Name)
+	s, _ := upterm.CheckmarkSuccessSpinner.Start(waiting)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithTimeout(ctx, c.Wait)
 	defer cancel()
-	t := int64(c.Wait.Seconds())
-	errC, err := kube.DynamicWatch(ctx, c.r, &t, func(u *unstructured.Unstructured) (bool, error) {
-		pkg := resources.Package{Unstructured: *u}
-		if pkg.GetInstalled() && pkg.GetHealthy() {
-			return true, nil
-		}
-		return false, nil
-	})
-	if err != nil {
-		return err
-	}
-	if err := <-errC; err != nil {
+	w := &waiter{pkgs: c.r, revs: c.rev, kind: c.kind}
+	if err := w.Wait(ctx, c.Name, func(cond condition) {
+		// Transitions are printed above the spinner, which is restarted
+		// so that it remains on the last line.
+		s.Info(cond.String())
+		s, _ = upterm.CheckmarkSuccessSpinner.Start(waiting)
+	}); err != nil {
+		_ = s.Stop()
 		return err
 	}
 
 	s.Success(fmt.Sprintf("%s installed and healthy", c.Name))
 	return nil
 }
+
+// push pushes the package file to the supplied reference, generating a tag
+// from the digest of the package if the reference does not include one.
+func (c *installCmd) push(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context, ref name.Reference) (name.Reference, error) {
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return nil, errors.New(errFileDigest)
+	}
+	img, err := tarball.ImageFromPath(c.File, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadPackageFile)
+	}
+	// Tags are defaulted to latest when parsing, so check whether one was
+	// supplied explicitly.
+	if !strings.HasSuffix(c.Package, ":"+tag.TagStr()) {
+		d, err := img.Digest()
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadPackageFile)
+		}
+		tag = tag.Tag(devTag(d))
+	}
+	if err := xpkgcmd.PushImages(ctx, p, upCtx, []v1.Image{img}, tag.String(), false, upCtx.ProfileName); err != nil {
+		return nil, errors.Wrap(err, errPushPackageFile)
+	}
+	return tag, nil
+}
+
+// devTag returns the development tag for a package with the supplied digest.
+func devTag(d v1.Hash) string {
+	return devTagPrefix + d.Hex[:12]
+}
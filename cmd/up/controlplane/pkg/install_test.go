@@ -0,0 +1,50 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/upbound"
+)
+
+func TestDevTag(t *testing.T) {
+	d := v1.Hash{Algorithm: "sha256", Hex: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+	if diff := cmp.Diff("v0.0.0-dev.0123456789ab", devTag(d)); diff != "" {
+		t.Errorf("\ndevTag(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestPushDigest(t *testing.T) {
+	pkg := "xpkg.upbound.io/acme/platform@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	ref, err := name.ParseReference(pkg)
+	if err != nil {
+		t.Fatalf("name.ParseReference(...): %v", err)
+	}
+	c := &installCmd{Package: pkg, File: "platform.xpkg"}
+	_, err = c.push(context.Background(), pterm.DefaultBasicText.WithWriter(io.Discard), &upbound.Context{}, ref)
+	if diff := cmp.Diff(errors.New(errFileDigest), err, test.EquateErrors()); diff != "" {
+		t.Errorf("\npush(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Masterminds/semver"
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/xpkg/dep/resolver/image"
+)
+
+const (
+	updateUnknown = "unknown"
+
+	errListPackages = "unable to list packages"
+	errNotTagged    = "package reference is not a tag"
+)
+
+var listFieldNames = []string{"NAME", "PACKAGE", "INSTALLED", "HEALTHY", "UPDATE"}
+
+// packageListItem is a package installed in a control plane.
+type packageListItem struct {
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	Installed bool   `json:"installed"`
+	Healthy   bool   `json:"healthy"`
+	// Update is the newest compatible version if it is newer than the
+	// installed version, or unknown if it could not be determined.
+	Update string `json:"update,omitempty"`
+}
+
+// AfterApply constructs and binds Upbound-specific context to any subcommands
+// that have Run() methods that receive it.
+func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
+	var gvr = providerGVR
+	switch kongCtx.Selected().Vars()["package_type"] {
+	case ProviderKind:
+	case ConfigurationKind:
+		gvr = configurationGVR
+	default:
+		return errors.New(errUnknownPkgType)
+	}
+
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+
+	client, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.r = client.Resource(gvr)
+	c.resolver = image.NewResolver()
+	c.registry = upCtx.RegistryEndpoint.Hostname()
+	return nil
+}
+
+// listCmd lists the packages installed in a control plane.
+type listCmd struct {
+	r        dynamic.NamespaceableResourceInterface
+	resolver *image.Resolver
+	registry string
+
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig string `type:"existingfile" help:"Override default kubeconfig path."`
+	Outdated   bool   `help:"Only list ${package_type}s for which a newer compatible version is available."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the list command.
+func (c *listCmd) Help() string {
+	return `
+The registry of each package is queried for newer versions. A version is
+compatible if it is not a pre-release and has the same major version as the
+installed version, or the same minor version for versions before v1.0.0.`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{packageListItem{}}
+}
+
+// Run executes the list command.
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter) error {
+	l, err := c.r.List(ctx, v1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, errListPackages)
+	}
+	items := make([]packageListItem, 0, len(l.Items))
+	for _, u := range l.Items {
+		pkg := resources.Package{Unstructured: u}
+		ref, _ := fieldpath.Pave(u.Object).GetString("spec.package")
+		update, err := newerCompatible(ctx, c.resolver, c.registry, ref)
+		if err != nil {
+			update = updateUnknown
+		}
+		if c.Outdated && (update == "" || update == updateUnknown) {
+			continue
+		}
+		items = append(items, packageListItem{
+			Name:      u.GetName(),
+			Package:   ref,
+			Installed: pkg.GetInstalled(),
+			Healthy:   pkg.GetHealthy(),
+			Update:    update,
+		})
+	}
+	return printer.Print(items, listFieldNames, extractListFields)
+}
+
+func extractListFields(obj any) []string {
+	p := obj.(packageListItem)
+	return []string{p.Name, p.Package, strconv.FormatBool(p.Installed), strconv.FormatBool(p.Healthy), p.Update}
+}
+
+// newerCompatible returns the newest version of the package that is
+// compatible with the referenced version, or an empty string if there is no
+// newer compatible version.
+func newerCompatible(ctx context.Context, r *image.Resolver, registry, pkg string) (string, error) {
+	ref, err := name.ParseReference(pkg, name.WithDefaultRegistry(registry))
+	if err != nil {
+		return "", err
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return "", errors.New(errNotTagged)
+	}
+	current, err := semver.NewVersion(tag.TagStr())
+	if err != nil {
+		return "", err
+	}
+	// Versions before v1.0.0 may break compatibility in minor versions, which
+	// the caret constraint of the semver library does not account for.
+	constraint := "^"
+	if current.Major() == 0 {
+		constraint = "~"
+	}
+	latest, err := r.ResolveTag(ctx, v1beta1.Dependency{
+		Package:     tag.Context().String(),
+		Constraints: constraint + tag.TagStr(),
+	})
+	if err != nil {
+		return "", err
+	}
+	lv, err := semver.NewVersion(latest)
+	if err != nil {
+		return "", err
+	}
+	if !lv.GreaterThan(current) {
+		return "", nil
+	}
+	return latest, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/xpkg/dep/resolver/image"
+)
+
+func TestNewerCompatible(t *testing.T) {
+	tags := []string{"v0.1.0", "v0.1.4", "v0.2.0", "v1.0.0", "v1.2.0", "v1.3.0-rc.1", "v2.0.0", "latest"}
+
+	type args struct {
+		pkg  string
+		opts []image.MockOption
+	}
+	type want struct {
+		update string
+		err    bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NewerMinor": {
+			reason: "The newest version with the same major version should be returned.",
+			args: args{
+				pkg:  "xpkg.upbound.io/upbound/provider-aws:v1.0.0",
+				opts: []image.MockOption{image.WithTags(tags)},
+			},
+			want: want{update: "v1.2.0"},
+		},
+		"PreV1": {
+			reason: "Versions before v1.0.0 should only be updated within the same minor version.",
+			args: args{
+				pkg:  "xpkg.upbound.io/upbound/provider-aws:v0.1.0",
+				opts: []image.MockOption{image.WithTags(tags)},
+			},
+			want: want{update: "v0.1.4"},
+		},
+		"UpToDate": {
+			reason: "No update should be returned if the installed version is the newest compatible version.",
+			args: args{
+				pkg:  "xpkg.upbound.io/upbound/provider-aws:v2.0.0",
+				opts: []image.MockOption{image.WithTags(tags)},
+			},
+			want: want{},
+		},
+		"NotSemver": {
+			reason: "Packages that are not tagged with a semantic version cannot be compared.",
+			args: args{
+				pkg:  "xpkg.upbound.io/upbound/provider-aws:latest",
+				opts: []image.MockOption{image.WithTags(tags)},
+			},
+			want: want{err: true},
+		},
+		"Digest": {
+			reason: "Packages referenced by digest cannot be compared.",
+			args: args{
+				pkg: "xpkg.upbound.io/upbound/provider-aws@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d87490e0b9a2f0e",
+			},
+			want: want{err: true},
+		},
+		"RegistryError": {
+			reason: "Errors fetching tags should be returned.",
+			args: args{
+				pkg:  "xpkg.upbound.io/upbound/provider-aws:v1.0.0",
+				opts: []image.MockOption{image.WithError(errors.New("boom"))},
+			},
+			want: want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := image.NewResolver(image.WithFetcher(image.NewMockFetcher(tc.args.opts...)))
+			update, err := newerCompatible(context.Background(), r, "xpkg.upbound.io", tc.args.pkg)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nnewerCompatible(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.update, update); diff != "" {
+				t.Errorf("\n%s\nnewerCompatible(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
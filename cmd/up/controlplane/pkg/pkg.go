@@ -28,4 +28,5 @@ func (c *Cmd) BeforeReset(ctx *kong.Context, p *kong.Path, maturity feature.Matu
 // Cmd contains commands for managing packages in a control plane.
 type Cmd struct {
 	Install installCmd `cmd:"" help:"Install a ${package_type}."`
+	List    listCmd    `cmd:"" help:"List installed ${package_type}s and available updates."`
 }
@@ -0,0 +1,40 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"github.com/upbound/up/internal/upterm"
+)
+
+// packageV1 is version v1 of the machine output of a package in a control
+// plane.
+type packageV1 struct {
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	Installed bool   `json:"installed"`
+	Healthy   bool   `json:"healthy"`
+	Update    string `json:"update,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(packageListItem{}, upterm.Schema{
+		Kind:    "Package",
+		Version: "v1",
+		Convert: func(obj any) any {
+			i := obj.(packageListItem)
+			return packageV1{Name: i.Name, Package: i.Package, Installed: i.Installed, Healthy: i.Healthy, Update: i.Update}
+		},
+	})
+}
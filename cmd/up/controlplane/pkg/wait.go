@@ -0,0 +1,148 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/resources"
+)
+
+const (
+	// pollInterval is how often package status is checked while waiting.
+	pollInterval = 2 * time.Second
+
+	errGetPackage         = "unable to get package"
+	errGetPackageRevision = "unable to get package revision"
+	errFmtWaitTimeout     = "timed out waiting for %s to become healthy"
+)
+
+// A condition is a condition of a package or package revision, keyed by the
+// kind of object and condition type.
+type condition struct {
+	Kind string
+	xpv1.Condition
+}
+
+func (c condition) key() string {
+	return c.Kind + "/" + string(c.Type)
+}
+
+// String returns a human readable description of the condition.
+func (c condition) String() string {
+	s := fmt.Sprintf("%s %s: %s", c.Kind, c.Type, c.Status)
+	if c.Reason != "" {
+		s += fmt.Sprintf(" (%s)", c.Reason)
+	}
+	if c.Message != "" {
+		s += ": " + c.Message
+	}
+	return s
+}
+
+// waiter polls a package and its current revision until the package is
+// healthy, reporting condition transitions as they are observed.
+type waiter struct {
+	pkgs dynamic.NamespaceableResourceInterface
+	revs dynamic.NamespaceableResourceInterface
+	kind string
+
+	// last observed conditions, keyed by kind and type.
+	last map[string]condition
+}
+
+// Wait blocks until the named package is installed and healthy or the context
+// is done. Each condition that changes is passed to report.
+func (w *waiter) Wait(ctx context.Context, name string, report func(condition)) error {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		healthy, conds, err := w.poll(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, c := range w.transitions(conds) {
+			report(c)
+		}
+		if healthy {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.Errorf(errFmtWaitTimeout, name)
+			}
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// poll returns whether the package is installed and healthy, and the current
+// conditions of the package and its current revision.
+func (w *waiter) poll(ctx context.Context, name string) (bool, []condition, error) {
+	u, err := w.pkgs.Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return false, nil, errors.Wrap(err, errGetPackage)
+	}
+	pkg := resources.Package{Unstructured: *u}
+	conds := make([]condition, 0, 3)
+	for _, c := range pkg.GetConditions() {
+		conds = append(conds, condition{Kind: w.kind, Condition: c})
+	}
+
+	if rev := pkg.GetCurrentRevision(); rev != "" {
+		ru, err := w.revs.Get(ctx, rev, v1.GetOptions{})
+		// The revision may not have been created yet.
+		if err != nil && !kerrors.IsNotFound(err) {
+			return false, nil, errors.Wrap(err, errGetPackageRevision)
+		}
+		if err == nil {
+			r := resources.Package{Unstructured: *ru}
+			for _, c := range r.GetConditions() {
+				conds = append(conds, condition{Kind: w.kind + "Revision", Condition: c})
+			}
+		}
+	}
+	return pkg.GetInstalled() && pkg.GetHealthy(), conds, nil
+}
+
+// transitions returns the conditions that differ from those last observed.
+// Transition times are ignored, as they do not change the state of the
+// package.
+func (w *waiter) transitions(conds []condition) []condition {
+	if w.last == nil {
+		w.last = map[string]condition{}
+	}
+	changed := []condition{}
+	for _, c := range conds {
+		prev, ok := w.last[c.key()]
+		if ok && prev.Status == c.Status && prev.Reason == c.Reason && strings.TrimSpace(prev.Message) == strings.TrimSpace(c.Message) {
+			continue
+		}
+		w.last[c.key()] = c
+		changed = append(changed, c)
+	}
+	return changed
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func cond(kind string, t xpv1.ConditionType, s corev1.ConditionStatus, reason string) condition {
+	return condition{Kind: kind, Condition: xpv1.Condition{Type: t, Status: s, Reason: xpv1.ConditionReason(reason)}}
+}
+
+func TestTransitions(t *testing.T) {
+	installed := cond("Provider", "Installed", corev1.ConditionTrue, "ActivePackageRevision")
+	unknown := cond("Provider", "Healthy", corev1.ConditionUnknown, "UnknownPackageRevisionHealth")
+	healthy := cond("Provider", "Healthy", corev1.ConditionTrue, "HealthyPackageRevision")
+
+	type args struct {
+		last  map[string]condition
+		conds []condition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []condition
+	}{
+		"FirstObservation": {
+			reason: "All conditions should be reported the first time they are observed.",
+			args: args{
+				conds: []condition{installed, unknown},
+			},
+			want: []condition{installed, unknown},
+		},
+		"Unchanged": {
+			reason: "Conditions that have not changed should not be reported.",
+			args: args{
+				last:  map[string]condition{installed.key(): installed, unknown.key(): unknown},
+				conds: []condition{installed, unknown},
+			},
+			want: []condition{},
+		},
+		"Changed": {
+			reason: "Only conditions that have changed should be reported.",
+			args: args{
+				last:  map[string]condition{installed.key(): installed, unknown.key(): unknown},
+				conds: []condition{installed, healthy},
+			},
+			want: []condition{healthy},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &waiter{last: tc.args.last}
+			got := w.transitions(tc.args.conds)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntransitions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	pkg := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "pkg.crossplane.io/v1",
+		"kind":       ProviderKind,
+		"metadata": map[string]any{
+			"name": "provider-aws",
+		},
+		"status": map[string]any{
+			"currentRevision": "provider-aws-1234",
+			"conditions": []any{
+				map[string]any{"type": "Installed", "status": "True", "reason": "ActivePackageRevision"},
+				map[string]any{"type": "Healthy", "status": "True", "reason": "HealthyPackageRevision"},
+			},
+		},
+	}}
+	rev := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "pkg.crossplane.io/v1",
+		"kind":       "ProviderRevision",
+		"metadata": map[string]any{
+			"name": "provider-aws-1234",
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Healthy", "status": "True", "reason": "HealthyPackageRevision"},
+			},
+		},
+	}}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), pkg, rev)
+	w := &waiter{
+		pkgs: client.Resource(providerGVR),
+		revs: client.Resource(providerRevisionGVR),
+		kind: ProviderKind,
+	}
+
+	got := []condition{}
+	err := w.Wait(context.Background(), "provider-aws", func(c condition) {
+		got = append(got, c)
+	})
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("Wait(...): -want err, +got err:\n%s", diff)
+	}
+	want := []condition{
+		cond(ProviderKind, "Installed", corev1.ConditionTrue, "ActivePackageRevision"),
+		cond(ProviderKind, "Healthy", corev1.ConditionTrue, "HealthyPackageRevision"),
+		cond("ProviderRevision", "Healthy", corev1.ConditionTrue, "HealthyPackageRevision"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Wait(...): -want, +got:\n%s", diff)
+	}
+}
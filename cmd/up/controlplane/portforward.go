@@ -0,0 +1,74 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *portForwardCmd) AfterApply() error {
+	c.stdin = os.Stdin
+	return nil
+}
+
+// portForwardCmd forwards local ports to a pod or service in a control plane.
+type portForwardCmd struct {
+	stdin io.Reader
+
+	Name   string   `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+	Target string   `arg:"" required:"" help:"Pod or service to forward to in the form [pod/|svc/]name. Bare names refer to services."`
+	Ports  []string `arg:"" required:"" help:"Ports to forward in the form [local:]remote."`
+
+	Token     string   `required:"" help:"API token used to authenticate. If '-' is given the value will be read from stdin."`
+	Namespace string   `short:"n" default:"default" help:"Namespace of the pod or service in the control plane."`
+	Address   []string `default:"localhost" help:"Addresses to listen on."`
+}
+
+// Unbounded returns true as the port-forward command runs until interrupted.
+func (c *portForwardCmd) Unbounded() bool {
+	return true
+}
+
+// Run executes the port-forward command.
+func (c *portForwardCmd) Run(ctx context.Context, kongCtx *kong.Context, upCtx *upbound.Context) error {
+	token, err := readToken(c.stdin, c.Token)
+	if err != nil {
+		return err
+	}
+	cfg, err := controlPlaneConfig(upCtx, c.Name, token)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return kube.PortForward(ctx, cfg, kube.PortForwardOptions{
+		Namespace: c.Namespace,
+		Target:    c.Target,
+		Ports:     c.Ports,
+		Addresses: c.Address,
+		Out:       kongCtx.Stdout,
+		ErrOut:    kongCtx.Stderr,
+	})
+}
@@ -0,0 +1,283 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/upbound/up-sdk-go/service/common"
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/trace"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	errQueryNameOrAll   = "either --control-plane or --all must be supplied"
+	errQueryNameAndAll  = "--control-plane cannot be combined with --all"
+	errFmtQueryTerm     = "invalid query term %q: must be of the form KEY=VALUE"
+	errFmtQueryKey      = "invalid query key %q: must be one of kind, group, name, or namespace"
+	errQueryKind        = "query must include a kind, such as kind=Bucket"
+	errFmtQueryFailed   = "failed to query %d of %d control planes"
+	errFmtListKind      = "unable to list %s"
+	errQueryConcurrency = "--concurrency must be at least 1"
+)
+
+var queryFieldNames = []string{"CONTROL PLANE", "NAME", "NAMESPACE", "SYNCED", "READY", "MESSAGE"}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *queryCmd) AfterApply(kongCtx *kong.Context) error {
+	switch {
+	case c.All && len(c.ControlPlanes) > 0:
+		return errors.New(errQueryNameAndAll)
+	case !c.All && len(c.ControlPlanes) == 0:
+		return errors.New(errQueryNameOrAll)
+	}
+	if c.Concurrency < 1 {
+		return errors.New(errQueryConcurrency)
+	}
+	q, err := parseQuery(c.Query)
+	if err != nil {
+		return err
+	}
+	c.query = q
+	c.stdin = os.Stdin
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// queryCmd finds resources across control planes.
+type queryCmd struct {
+	stdin io.Reader
+	query query
+
+	Query string `arg:"" help:"Resources to find, as comma-separated KEY=VALUE terms. Keys are kind (required), group, name, and namespace. Names may include * wildcards."`
+
+	ControlPlanes []string `name:"control-plane" help:"Name of a control plane to query. Can be repeated." predictor:"ctps"`
+	All           bool     `help:"Query every control plane in the account."`
+	Selector      string   `short:"l" help:"Label selector that resources must match, such as team=a."`
+	Token         string   `required:"" help:"API token used to authenticate. If '-' is given the value will be read from stdin."`
+	Concurrency   int      `default:"10" help:"Number of control planes to query at once."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the query command.
+func (c *queryCmd) Help() string {
+	return `
+Lists the resources of a kind in each control plane concurrently and shows them
+along with the control plane they were found in, for example:
+
+  up controlplane query --all 'kind=Bucket,name=logs-*'
+
+The kind is matched in every API group unless a group is supplied. Control
+planes that cannot be queried are reported after the results.`
+}
+
+// query identifies the resources to find.
+type query struct {
+	Kind      string
+	Group     string
+	Name      string
+	Namespace string
+}
+
+// parseQuery parses a query of comma-separated KEY=VALUE terms.
+func parseQuery(s string) (query, error) {
+	q := query{}
+	for _, term := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok || k == "" || v == "" {
+			return query{}, errors.Errorf(errFmtQueryTerm, term)
+		}
+		switch k {
+		case "kind":
+			q.Kind = v
+		case "group":
+			q.Group = v
+		case "name":
+			q.Name = v
+		case "namespace":
+			q.Namespace = v
+		default:
+			return query{}, errors.Errorf(errFmtQueryKey, k)
+		}
+	}
+	if q.Kind == "" {
+		return query{}, errors.New(errQueryKind)
+	}
+	return q, nil
+}
+
+// queryResult is a resource found in a control plane.
+type queryResult struct {
+	ControlPlane string `json:"controlPlane"`
+
+	trace.Resource
+}
+
+// PrintedObjects returns the objects printed by the query command.
+func (c *queryCmd) PrintedObjects() []any {
+	return []any{queryResult{}}
+}
+
+// Run executes the query command.
+func (c *queryCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, cc *cp.Client, upCtx *upbound.Context) error {
+	token, err := readToken(c.stdin, c.Token)
+	if err != nil {
+		return err
+	}
+	names := c.ControlPlanes
+	if c.All {
+		l, err := cc.List(ctx, upCtx.Account, common.WithSize(maxItems))
+		if err != nil {
+			return err
+		}
+		for _, ctp := range l.ControlPlanes {
+			names = append(names, ctp.ControlPlane.Name)
+		}
+	}
+
+	var mu sync.Mutex
+	results := []queryResult{}
+	failures := map[string]error{}
+	g := &errgroup.Group{}
+	g.SetLimit(c.Concurrency)
+	for _, name := range names {
+		name := name
+		g.Go(func() error {
+			rs, err := c.queryControlPlane(ctx, upCtx, name, token)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures[name] = err
+				return nil
+			}
+			for _, r := range rs {
+				results = append(results, queryResult{ControlPlane: name, Resource: *r})
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.ControlPlane != b.ControlPlane {
+			return a.ControlPlane < b.ControlPlane
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Ref.String() < b.Ref.String()
+	})
+	if len(results) == 0 {
+		p.Printfln("No matching resources found in %d control planes", len(names)-len(failures))
+	} else if err := printer.Print(results, queryFieldNames, extractQueryFields); err != nil {
+		return err
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	failed := make([]string, 0, len(failures))
+	for name := range failures {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		p.Printfln("Failed to query %s: %s", name, failures[name])
+	}
+	return errors.Errorf(errFmtQueryFailed, len(failures), len(names))
+}
+
+// queryControlPlane returns the resources that match the query in the control
+// plane with the supplied name.
+func (c *queryCmd) queryControlPlane(ctx context.Context, upCtx *upbound.Context, name, token string) ([]*trace.Resource, error) {
+	cfg, err := controlPlaneConfig(upCtx, name, token)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return find(ctx, client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)), c.query, c.Selector)
+}
+
+// find returns the resources in a control plane that match the query and
+// label selector. A control plane in which the kind is not defined has no
+// matching resources.
+func find(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, q query, selector string) ([]*trace.Resource, error) {
+	// Kinds are registered as singular resources, so that resources of a
+	// kind can be found in every group.
+	gvrs, err := mapper.ResourcesFor(schema.GroupVersionResource{Group: q.Group, Resource: strings.ToLower(q.Kind)})
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := map[schema.GroupResource]bool{}
+	out := []*trace.Resource{}
+	for _, gvr := range gvrs {
+		// Resources are returned in order of preference, so only the
+		// preferred version of each is listed.
+		if seen[gvr.GroupResource()] {
+			continue
+		}
+		seen[gvr.GroupResource()] = true
+		l, err := client.Resource(gvr).Namespace(q.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtListKind, gvr.GroupResource())
+		}
+		for i := range l.Items {
+			if q.Name != "" {
+				if ok, _ := path.Match(q.Name, l.Items[i].GetName()); !ok {
+					continue
+				}
+			}
+			out = append(out, trace.NewResource(&l.Items[i]))
+		}
+	}
+	return out, nil
+}
+
+func extractQueryFields(obj any) []string {
+	r := obj.(queryResult)
+	return []string{r.ControlPlane, r.Ref.String(), r.Namespace, r.Synced, r.Ready, r.Message}
+}
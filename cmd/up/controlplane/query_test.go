@@ -0,0 +1,174 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/upbound/up/internal/trace"
+)
+
+func TestParseQuery(t *testing.T) {
+	type want struct {
+		q   query
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Kind": {
+			reason: "A query of only a kind should be parsed.",
+			s:      "kind=Bucket",
+			want:   want{q: query{Kind: "Bucket"}},
+		},
+		"AllKeys": {
+			reason: "All keys should be parsed.",
+			s:      "kind=Bucket, group=s3.aws.upbound.io,name=logs-*,namespace=team-a",
+			want:   want{q: query{Kind: "Bucket", Group: "s3.aws.upbound.io", Name: "logs-*", Namespace: "team-a"}},
+		},
+		"NoKind": {
+			reason: "A query must include a kind.",
+			s:      "name=logs",
+			want:   want{err: errors.New(errQueryKind)},
+		},
+		"UnknownKey": {
+			reason: "Unknown keys should be rejected.",
+			s:      "kind=Bucket,region=us-east-1",
+			want:   want{err: errors.Errorf(errFmtQueryKey, "region")},
+		},
+		"InvalidTerm": {
+			reason: "Terms must be of the form KEY=VALUE.",
+			s:      "Bucket",
+			want:   want{err: errors.Errorf(errFmtQueryTerm, "Bucket")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q, err := parseQuery(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseQuery(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.q, q); diff != "" {
+				t.Errorf("\n%s\nparseQuery(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	obj := func(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		u.SetLabels(labels)
+		return u
+	}
+	ref := func(apiVersion, kind, namespace, name string) *trace.Resource {
+		return &trace.Resource{
+			Ref:    trace.Ref{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name},
+			Synced: "-",
+			Ready:  "-",
+		}
+	}
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "storage.gcp.upbound.io", Version: "v1beta1", Kind: "Bucket"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"}, meta.RESTScopeNamespace)
+
+	objs := []runtime.Object{
+		obj("s3.aws.upbound.io/v1beta1", "Bucket", "", "logs-a", map[string]string{"team": "a"}),
+		obj("s3.aws.upbound.io/v1beta1", "Bucket", "", "data", map[string]string{"team": "b"}),
+		obj("storage.gcp.upbound.io/v1beta1", "Bucket", "", "logs-b", map[string]string{"team": "b"}),
+		obj("example.org/v1", "Database", "team-a", "db", nil),
+		obj("example.org/v1", "Database", "team-b", "db", nil),
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"}:      "BucketList",
+		{Group: "storage.gcp.upbound.io", Version: "v1beta1", Resource: "buckets"}: "BucketList",
+		{Group: "example.org", Version: "v1", Resource: "databases"}:               "DatabaseList",
+	}
+
+	cases := map[string]struct {
+		reason   string
+		q        query
+		selector string
+		want     []*trace.Resource
+	}{
+		"KindInEveryGroup": {
+			reason: "Resources of the kind should be found in every group.",
+			q:      query{Kind: "Bucket", Name: "logs-*"},
+			want: []*trace.Resource{
+				ref("s3.aws.upbound.io/v1beta1", "Bucket", "", "logs-a"),
+				ref("storage.gcp.upbound.io/v1beta1", "Bucket", "", "logs-b"),
+			},
+		},
+		"Group": {
+			reason: "Only resources of the kind in the supplied group should be found.",
+			q:      query{Kind: "Bucket", Group: "storage.gcp.upbound.io"},
+			want: []*trace.Resource{
+				ref("storage.gcp.upbound.io/v1beta1", "Bucket", "", "logs-b"),
+			},
+		},
+		"Selector": {
+			reason:   "Only resources that match the label selector should be found.",
+			q:        query{Kind: "Bucket", Group: "s3.aws.upbound.io"},
+			selector: "team=b",
+			want: []*trace.Resource{
+				ref("s3.aws.upbound.io/v1beta1", "Bucket", "", "data"),
+			},
+		},
+		"Namespace": {
+			reason: "Only resources in the supplied namespace should be found.",
+			q:      query{Kind: "Database", Namespace: "team-b"},
+			want: []*trace.Resource{
+				ref("example.org/v1", "Database", "team-b", "db"),
+			},
+		},
+		"UnknownKind": {
+			reason: "No resources should be found if the kind is not defined.",
+			q:      query{Kind: "Table"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+			got, err := find(context.Background(), client, m, tc.q, tc.selector)
+			if err != nil {
+				t.Fatalf("\n%s\nfind(...): unexpected error: %s", tc.reason, err)
+			}
+			// The fake client does not list resources in a stable order.
+			sort.Slice(got, func(i, j int) bool { return got[i].Ref.String() < got[j].Ref.String() })
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nfind(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const errFmtNotScheduled = "control plane %s is not scheduled for deletion"
+
+// restoreCmd cancels the scheduled deletion of a control plane.
+type restoreCmd struct {
+	Name string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+}
+
+// Run executes the restore command.
+func (c *restoreCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if _, ok := upCtx.Cfg.GetPendingDeletion(upCtx.Account, c.Name); !ok {
+		return errors.Errorf(errFmtNotScheduled, c.Name)
+	}
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		cfg.RemovePendingDeletion(upCtx.Account, c.Name)
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, errUpdateConfig)
+	}
+	p.Printfln("%s restored", c.Name)
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errFmtNoRuntimeConfig = "runtime config %q does not exist"
+	errFmtAttach          = "failed to attach runtime config to provider %q"
+	errGetRuntimeConfig   = "failed to get runtime config"
+)
+
+// AfterApply constructs a dynamic client for the control plane.
+func (c *attachCmd) AfterApply(upCtx *upbound.Context) error {
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+	client, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.configs = client.Resource(gvr(c.ControllerConfig))
+	c.providers = client.Resource(providerGVR)
+	return nil
+}
+
+// attachCmd configures providers to use a runtime config.
+type attachCmd struct {
+	configs   dynamic.NamespaceableResourceInterface
+	providers dynamic.NamespaceableResourceInterface
+
+	Name      string   `arg:"" help:"Name of the runtime config."`
+	Providers []string `arg:"" help:"Names of the providers that use the runtime config."`
+
+	ControllerConfig bool `help:"Attach a ControllerConfig, for Crossplane versions before v1.14, instead of a DeploymentRuntimeConfig."`
+
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig string `type:"existingfile" help:"Override default kubeconfig path."`
+}
+
+// Run executes the attach command.
+func (c *attachCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	// Crossplane does not validate the reference, so make sure that the
+	// providers are not left waiting for a runtime config that does not
+	// exist.
+	if _, err := c.configs.Get(ctx, c.Name, metav1.GetOptions{}); err != nil {
+		if kerrors.IsNotFound(err) {
+			return errors.Errorf(errFmtNoRuntimeConfig, c.Name)
+		}
+		return errors.Wrap(err, errGetRuntimeConfig)
+	}
+	patch, err := attachPatch(c.Name, c.ControllerConfig)
+	if err != nil {
+		return err
+	}
+	for _, name := range c.Providers {
+		if _, err := c.providers.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return errors.Wrapf(err, errFmtAttach, name)
+		}
+		p.Printfln("Provider %s uses runtime config %s", name, c.Name)
+	}
+	return nil
+}
+
+// attachPatch returns a merge patch that refers a provider to the runtime
+// config with the supplied name.
+func attachPatch(name string, controllerConfig bool) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"spec": map[string]any{
+			ref(controllerConfig): map[string]any{"name": name},
+		},
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errCreateRuntimeConfig = "failed to create runtime config"
+)
+
+// AfterApply constructs a dynamic client for the control plane.
+func (c *createCmd) AfterApply(upCtx *upbound.Context) error {
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+	client, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.r = client.Resource(gvr(c.ControllerConfig))
+	return nil
+}
+
+// createCmd creates a runtime config for providers.
+type createCmd struct {
+	r dynamic.NamespaceableResourceInterface
+
+	Name string `arg:"" help:"Name of the runtime config."`
+
+	spec
+
+	ControllerConfig bool `help:"Create a ControllerConfig, for Crossplane versions before v1.14, instead of a DeploymentRuntimeConfig."`
+
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig string `type:"existingfile" help:"Override default kubeconfig path."`
+}
+
+// Help returns the help text for the create command.
+func (c *createCmd) Help() string {
+	return `
+Creates a DeploymentRuntimeConfig that sets the resources and environment
+variables of the provider container and the annotations of the provider's
+service account, such as those used for workload identity. Crossplane versions
+before v1.14 do not support DeploymentRuntimeConfigs, so a ControllerConfig can
+be created instead with --controller-config. ControllerConfigs cannot set
+service account annotations.
+
+Use the attach command to configure providers to use the runtime config.`
+}
+
+// Run executes the create command.
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	u, err := c.build(c.Name, c.ControllerConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := c.r.Create(ctx, u, metav1.CreateOptions{}); err != nil {
+		return errors.Wrap(err, errCreateRuntimeConfig)
+	}
+	p.Printfln("%s %s created", u.GetKind(), c.Name)
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"sort"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/up/internal/feature"
+	"github.com/upbound/up/internal/resources"
+)
+
+const (
+	// runtimeContainer is the name of the container that runs a provider in
+	// the deployment created for it by Crossplane.
+	runtimeContainer = "package-runtime"
+
+	kindDeploymentRuntimeConfig = "DeploymentRuntimeConfig"
+	kindControllerConfig        = "ControllerConfig"
+
+	errFmtInvalidQuantity     = "invalid quantity %q for resource %q"
+	errControllerConfigSAAnno = "service account annotations are not supported by ControllerConfigs"
+)
+
+var providerGVR = schema.GroupVersionResource{
+	Group:    "pkg.crossplane.io",
+	Version:  "v1",
+	Resource: "providers",
+}
+
+// BeforeReset is the first hook to run.
+func (c *Cmd) BeforeReset(p *kong.Path, maturity feature.Maturity) error {
+	return feature.HideMaturity(p, maturity)
+}
+
+// Cmd contains commands for managing the runtime configuration of providers
+// in a control plane.
+type Cmd struct {
+	Create createCmd `cmd:"" help:"Create a runtime config for providers."`
+	Attach attachCmd `cmd:"" help:"Configure providers to use a runtime config."`
+}
+
+// spec is the runtime configuration of a provider.
+type spec struct {
+	Requests                  map[string]string `help:"Resource requests of the provider container, such as cpu=100m;memory=256Mi."`
+	Limits                    map[string]string `help:"Resource limits of the provider container, such as cpu=1;memory=1Gi."`
+	Env                       map[string]string `help:"Environment variables of the provider container, such as AWS_REGION=us-east-1."`
+	ServiceAccountAnnotations map[string]string `name:"service-account-annotation" help:"Annotations of the service account of the provider, such as eks.amazonaws.com/role-arn=arn:aws:iam::123456789012:role/provider."`
+}
+
+// gvr returns the resource of the runtime config kind.
+func gvr(controllerConfig bool) schema.GroupVersionResource {
+	if controllerConfig {
+		return resources.ControllerConfigGRV
+	}
+	return resources.DeploymentRuntimeConfigGRV
+}
+
+// ref returns the field of a provider's spec that refers to a runtime config
+// of the kind.
+func ref(controllerConfig bool) string {
+	if controllerConfig {
+		return "controllerConfigRef"
+	}
+	return "runtimeConfigRef"
+}
+
+// build returns a runtime config with the supplied name that applies the spec.
+// A ControllerConfig is returned if controllerConfig is true, and otherwise a
+// DeploymentRuntimeConfig.
+func (s spec) build(name string, controllerConfig bool) (*unstructured.Unstructured, error) {
+	res := map[string]any{}
+	for field, q := range map[string]map[string]string{"requests": s.Requests, "limits": s.Limits} {
+		if len(q) == 0 {
+			continue
+		}
+		m := make(map[string]any, len(q))
+		for k, v := range q {
+			if _, err := resource.ParseQuantity(v); err != nil {
+				return nil, errors.Errorf(errFmtInvalidQuantity, v, k)
+			}
+			m[k] = v
+		}
+		res[field] = m
+	}
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]any, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, map[string]any{"name": k, "value": s.Env[k]})
+	}
+
+	gv := gvr(controllerConfig).GroupVersion().String()
+	if controllerConfig {
+		if len(s.ServiceAccountAnnotations) > 0 {
+			return nil, errors.New(errControllerConfigSAAnno)
+		}
+		cs := map[string]any{}
+		if len(res) > 0 {
+			cs["resources"] = res
+		}
+		if len(env) > 0 {
+			cs["env"] = env
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": gv,
+			"kind":       kindControllerConfig,
+			"metadata":   map[string]any{"name": name},
+			"spec":       cs,
+		}}, nil
+	}
+
+	container := map[string]any{"name": runtimeContainer}
+	if len(res) > 0 {
+		container["resources"] = res
+	}
+	if len(env) > 0 {
+		container["env"] = env
+	}
+	rs := map[string]any{
+		"deploymentTemplate": map[string]any{
+			"spec": map[string]any{
+				// The selector is required by the schema, and is defaulted
+				// by Crossplane when empty.
+				"selector": map[string]any{},
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{container},
+					},
+				},
+			},
+		},
+	}
+	if len(s.ServiceAccountAnnotations) > 0 {
+		annotations := make(map[string]any, len(s.ServiceAccountAnnotations))
+		for k, v := range s.ServiceAccountAnnotations {
+			annotations[k] = v
+		}
+		rs["serviceAccountTemplate"] = map[string]any{
+			"metadata": map[string]any{"annotations": annotations},
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": gv,
+		"kind":       kindDeploymentRuntimeConfig,
+		"metadata":   map[string]any{"name": name},
+		"spec":       rs,
+	}}, nil
+}
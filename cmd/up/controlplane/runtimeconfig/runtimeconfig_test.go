@@ -0,0 +1,184 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBuild(t *testing.T) {
+	type args struct {
+		spec             spec
+		controllerConfig bool
+	}
+	type want struct {
+		u   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DeploymentRuntimeConfig": {
+			reason: "The spec should be applied to the provider container and service account of a DeploymentRuntimeConfig.",
+			args: args{
+				spec: spec{
+					Requests:                  map[string]string{"cpu": "100m"},
+					Limits:                    map[string]string{"memory": "1Gi"},
+					Env:                       map[string]string{"B": "2", "A": "1"},
+					ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/role-arn": "arn"},
+				},
+			},
+			want: want{u: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "pkg.crossplane.io/v1beta1",
+				"kind":       "DeploymentRuntimeConfig",
+				"metadata":   map[string]any{"name": "cfg"},
+				"spec": map[string]any{
+					"deploymentTemplate": map[string]any{
+						"spec": map[string]any{
+							"selector": map[string]any{},
+							"template": map[string]any{
+								"spec": map[string]any{
+									"containers": []any{map[string]any{
+										"name": "package-runtime",
+										"resources": map[string]any{
+											"requests": map[string]any{"cpu": "100m"},
+											"limits":   map[string]any{"memory": "1Gi"},
+										},
+										"env": []any{
+											map[string]any{"name": "A", "value": "1"},
+											map[string]any{"name": "B", "value": "2"},
+										},
+									}},
+								},
+							},
+						},
+					},
+					"serviceAccountTemplate": map[string]any{
+						"metadata": map[string]any{
+							"annotations": map[string]any{"eks.amazonaws.com/role-arn": "arn"},
+						},
+					},
+				},
+			}}},
+		},
+		"EmptyDeploymentRuntimeConfig": {
+			reason: "A DeploymentRuntimeConfig without customizations should only name the provider container.",
+			want: want{u: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "pkg.crossplane.io/v1beta1",
+				"kind":       "DeploymentRuntimeConfig",
+				"metadata":   map[string]any{"name": "cfg"},
+				"spec": map[string]any{
+					"deploymentTemplate": map[string]any{
+						"spec": map[string]any{
+							"selector": map[string]any{},
+							"template": map[string]any{
+								"spec": map[string]any{
+									"containers": []any{map[string]any{"name": "package-runtime"}},
+								},
+							},
+						},
+					},
+				},
+			}}},
+		},
+		"ControllerConfig": {
+			reason: "The spec should be applied to the spec of a ControllerConfig.",
+			args: args{
+				spec: spec{
+					Requests: map[string]string{"memory": "256Mi"},
+					Env:      map[string]string{"A": "1"},
+				},
+				controllerConfig: true,
+			},
+			want: want{u: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "pkg.crossplane.io/v1alpha1",
+				"kind":       "ControllerConfig",
+				"metadata":   map[string]any{"name": "cfg"},
+				"spec": map[string]any{
+					"resources": map[string]any{
+						"requests": map[string]any{"memory": "256Mi"},
+					},
+					"env": []any{map[string]any{"name": "A", "value": "1"}},
+				},
+			}}},
+		},
+		"ControllerConfigServiceAccountAnnotations": {
+			reason: "ControllerConfigs cannot set service account annotations.",
+			args: args{
+				spec: spec{
+					ServiceAccountAnnotations: map[string]string{"a": "b"},
+				},
+				controllerConfig: true,
+			},
+			want: want{err: errors.New(errControllerConfigSAAnno)},
+		},
+		"InvalidQuantity": {
+			reason: "Invalid resource quantities should be rejected.",
+			args: args{
+				spec: spec{
+					Limits: map[string]string{"cpu": "lots"},
+				},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidQuantity, "lots", "cpu")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := tc.args.spec.build("cfg", tc.args.controllerConfig)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nbuild(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, u); diff != "" {
+				t.Errorf("\n%s\nbuild(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAttachPatch(t *testing.T) {
+	cases := map[string]struct {
+		reason           string
+		controllerConfig bool
+		want             string
+	}{
+		"DeploymentRuntimeConfig": {
+			reason: "A DeploymentRuntimeConfig should be referred to by runtimeConfigRef.",
+			want:   `{"spec":{"runtimeConfigRef":{"name":"cfg"}}}`,
+		},
+		"ControllerConfig": {
+			reason:           "A ControllerConfig should be referred to by controllerConfigRef.",
+			controllerConfig: true,
+			want:             `{"spec":{"controllerConfigRef":{"name":"cfg"}}}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := attachPatch("cfg", tc.controllerConfig)
+			if err != nil {
+				t.Fatalf("\n%s\nattachPatch(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nattachPatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
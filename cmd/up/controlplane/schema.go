@@ -0,0 +1,116 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/trace"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// controlPlaneV1 is version v1 of the machine output of a control plane on
+// Upbound.
+type controlPlaneV1 struct {
+	ID            uuid.UUID                    `json:"id"`
+	Name          string                       `json:"name"`
+	Description   string                       `json:"description,omitempty"`
+	Status        string                       `json:"status,omitempty"`
+	Configuration *controlPlaneConfigurationV1 `json:"configuration,omitempty"`
+	Labels        map[string]string            `json:"labels,omitempty"`
+	Annotations   map[string]string            `json:"annotations,omitempty"`
+	CreatedAt     *time.Time                   `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time                   `json:"updatedAt,omitempty"`
+}
+
+// controlPlaneConfigurationV1 is version v1 of the machine output of the
+// configuration deployed to a control plane on Upbound.
+type controlPlaneConfigurationV1 struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status,omitempty"`
+	CurrentVersion string    `json:"currentVersion,omitempty"`
+	DesiredVersion string    `json:"desiredVersion,omitempty"`
+}
+
+// queryResultV1 is version v1 of the machine output of a resource found by a
+// query across control planes.
+type queryResultV1 struct {
+	ControlPlane string `json:"controlPlane"`
+
+	trace.ResourceV1
+}
+
+func init() {
+	upterm.RegisterSchema(queryResult{}, upterm.Schema{
+		Kind:    "QueryResult",
+		Version: "v1",
+		Convert: func(obj any) any {
+			q := obj.(queryResult)
+			return queryResultV1{ControlPlane: q.ControlPlane, ResourceV1: trace.NewResourceV1(&q.Resource)}
+		},
+	})
+	upterm.RegisterSchema(cp.ControlPlaneResponse{}, upterm.Schema{
+		Kind:    "ControlPlane",
+		Version: "v1",
+		Convert: convertControlPlaneV1,
+	})
+	upterm.RegisterSchema(listedControlPlane{}, upterm.Schema{
+		Kind:    "ControlPlane",
+		Version: "v1",
+		Convert: convertListedControlPlaneV1,
+	})
+}
+
+func convertControlPlaneV1(obj any) any {
+	c := obj.(cp.ControlPlaneResponse)
+	out := controlPlaneV1{
+		ID:          c.ControlPlane.ID,
+		Name:        c.ControlPlane.Name,
+		Description: c.ControlPlane.Description,
+		Status:      string(c.Status),
+		CreatedAt:   c.ControlPlane.CreatedAt,
+		UpdatedAt:   c.ControlPlane.UpdatedAt,
+	}
+	if cfg := c.ControlPlane.Configuration; cfg.Name != nil && cfg != EmptyControlPlaneConfiguration() {
+		out.Configuration = &controlPlaneConfigurationV1{
+			ID:             cfg.ID,
+			Name:           *cfg.Name,
+			Status:         string(cfg.Status),
+			CurrentVersion: stringValue(cfg.CurrentVersion),
+			DesiredVersion: stringValue(cfg.DesiredVersion),
+		}
+	}
+	return out
+}
+
+func convertListedControlPlaneV1(obj any) any {
+	c := obj.(listedControlPlane)
+	out := convertControlPlaneV1(c.ControlPlaneResponse).(controlPlaneV1)
+	out.Labels = c.Labels
+	out.Annotations = c.Annotations
+	return out
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	cp "github.com/upbound/up-sdk-go/service/controlplanes"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+func TestControlPlaneSchemaV1(t *testing.T) {
+	created := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	cfg, version := "platform-ref-aws", "v0.6.0"
+	cases := map[string]struct {
+		reason string
+		obj    any
+		golden string
+	}{
+		"ControlPlane": {
+			reason: "A control plane should be printed with the fields of schema v1.",
+			obj: cp.ControlPlaneResponse{
+				ControlPlane: cp.ControlPlane{
+					ID:          uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+					Name:        "prod",
+					Description: "Production",
+					CreatorID:   1,
+					CreatedAt:   &created,
+					Configuration: cp.ControlPlaneConfiguration{
+						ID:             uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+						Name:           &cfg,
+						CurrentVersion: &version,
+						DesiredVersion: &version,
+						Status:         cp.ConfigurationReady,
+					},
+				},
+				Status:     cp.StatusReady,
+				Permission: cp.PermissionOwner,
+			},
+			golden: "controlplane-v1.golden",
+		},
+		"ControlPlaneList": {
+			reason: "Control planes without a configuration should be listed without one.",
+			obj: []cp.ControlPlaneResponse{{
+				ControlPlane: cp.ControlPlane{
+					ID:   uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+					Name: "dev",
+				},
+				Status: cp.StatusProvisioning,
+			}},
+			golden: "controlplanelist-v1.golden",
+		},
+		"ListedControlPlane": {
+			reason: "Listed control planes should include their locally stored labels and annotations.",
+			obj: []listedControlPlane{{
+				ControlPlaneResponse: cp.ControlPlaneResponse{
+					ControlPlane: cp.ControlPlane{
+						ID:   uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+						Name: "dev",
+					},
+					Status: cp.StatusProvisioning,
+				},
+				Labels:      map[string]string{"env": "dev"},
+				Annotations: map[string]string{"owner": "platform"},
+			}},
+			golden: "listedcontrolplane-v1.golden",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := upterm.MachineJSON(tc.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nMachineJSON(...): %v", tc.reason, err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", tc.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+				t.Errorf("\n%s\nMachineJSON(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
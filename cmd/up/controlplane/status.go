@@ -0,0 +1,155 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	conditionAvailable = "Available"
+	statusUnknown      = "Unknown"
+
+	errNotConnected    = "MCP Connector is not installed in the current cluster"
+	errGetDeployment   = "unable to get MCP Connector deployment"
+	errListAPIServices = "unable to list API services"
+)
+
+var apiServiceGVR = schema.GroupVersionResource{
+	Group:    "apiregistration.k8s.io",
+	Version:  "v1",
+	Resource: "apiservices",
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *connectStatusCmd) AfterApply(upCtx *upbound.Context) error {
+	kubeconfig, err := c.kubeconfig(upCtx)
+	if err != nil {
+		return err
+	}
+	mgr, err := c.manager(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.mgr = mgr
+	kClient, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.kClient = kClient
+	dClient, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.dClient = dClient
+	return nil
+}
+
+// connectStatusCmd shows the health of the MCP Connector in the current
+// cluster and the sync state of the APIs it serves.
+type connectStatusCmd struct {
+	mgr     install.Manager
+	kClient kubernetes.Interface
+	dClient dynamic.Interface
+
+	connectorParams
+}
+
+// Run executes the status command.
+func (c *connectStatusCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	version, err := c.mgr.GetCurrentVersion()
+	if err != nil {
+		return errors.Wrap(err, errNotConnected)
+	}
+	p.Printfln("MCP Connector version %s is installed in namespace %s.", version, c.InstallationNamespace)
+
+	d, err := c.kClient.AppsV1().Deployments(c.InstallationNamespace).Get(ctx, connectorName, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		p.Printfln("Deployment %s not found.", connectorName)
+	case err != nil:
+		return errors.Wrap(err, errGetDeployment)
+	default:
+		p.Printfln("Deployment: %d/%d replicas ready, available: %s", d.Status.ReadyReplicas, replicas(d), deploymentAvailable(d))
+	}
+
+	l, err := c.dClient.Resource(apiServiceGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, errListAPIServices)
+	}
+	synced := 0
+	for _, a := range l.Items {
+		if !servedByConnector(a, c.InstallationNamespace) {
+			continue
+		}
+		synced++
+		p.Printfln("API %s: available: %s", a.GetName(), apiServiceAvailable(a))
+	}
+	if synced == 0 {
+		p.Println("No APIs from the control plane are served in this cluster.")
+	}
+	return nil
+}
+
+func replicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
+func deploymentAvailable(d *appsv1.Deployment) string {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			return string(c.Status)
+		}
+	}
+	return statusUnknown
+}
+
+// servedByConnector returns true if the supplied APIService is backed by the
+// MCP Connector service in the supplied namespace.
+func servedByConnector(a unstructured.Unstructured, namespace string) bool {
+	name, _, _ := unstructured.NestedString(a.Object, "spec", "service", "name")
+	ns, _, _ := unstructured.NestedString(a.Object, "spec", "service", "namespace")
+	return name == connectorName && ns == namespace
+}
+
+func apiServiceAvailable(a unstructured.Unstructured) string {
+	conds, _, _ := unstructured.NestedSlice(a.Object, "status", "conditions")
+	for _, c := range conds {
+		m, ok := c.(map[string]any)
+		if !ok || m["type"] != conditionAvailable {
+			continue
+		}
+		if s, ok := m["status"].(string); ok {
+			return s
+		}
+	}
+	return statusUnknown
+}
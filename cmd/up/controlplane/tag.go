@@ -0,0 +1,85 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+)
+
+const (
+	errCostCenterOrRemove = "exactly one of --cost-center or --remove must be provided"
+	errFmtInvalidTag      = "invalid cost center %q"
+
+	errCostCenterSpaceOnly = "cost centers are only supported for control planes in a Space, as usage is only reported for Spaces"
+)
+
+// AfterApply validates the tag command after assignment.
+func (c *tagCmd) AfterApply() error {
+	if c.Remove == (c.CostCenter != "") {
+		return errors.New(errCostCenterOrRemove)
+	}
+	return validateCostCenter(c.CostCenter)
+}
+
+// tagCmd tags a control plane with the cost center its usage is charged to.
+type tagCmd struct {
+	Name       string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+	CostCenter string `help:"Cost center to which the usage of the control plane is charged."`
+	Remove     bool   `help:"Remove the cost center tag of the control plane."`
+}
+
+// Help returns the help text for the tag command.
+func (c *tagCmd) Help() string {
+	return `
+The cost center is stored in the upbound.io/cost-center label of the control
+plane. 'up usage report' groups the usage of control planes in a Space by this
+label to produce chargeback reports. Tagging is only supported for control
+planes in a Space, as usage is only exported from Spaces.`
+}
+
+// Run executes the tag command.
+func (c *tagCmd) Run(ctx context.Context, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	if sc == nil {
+		return errors.New(errCostCenterSpaceOnly)
+	}
+	if _, err := sc.SetLabel(ctx, c.Name, resources.CostCenterLabel, c.CostCenter); err != nil {
+		return err
+	}
+	c.print(p)
+	return nil
+}
+
+func (c *tagCmd) print(p pterm.TextPrinter) {
+	if c.Remove {
+		p.Printfln("%s untagged", c.Name)
+		return
+	}
+	p.Printfln("%s tagged with cost center %s", c.Name, c.CostCenter)
+}
+
+// validateCostCenter checks that a cost center is a valid label value.
+func validateCostCenter(cc string) error {
+	if errs := validation.IsValidLabelValue(cc); len(errs) > 0 {
+		return errors.Errorf(errFmtInvalidTag, cc)
+	}
+	return nil
+}
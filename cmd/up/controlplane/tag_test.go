@@ -0,0 +1,73 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestTagAfterApply(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cmd    tagCmd
+		want   error
+	}{
+		"CostCenter": {
+			reason: "A valid cost center should be accepted.",
+			cmd:    tagCmd{Name: "dev", CostCenter: "platform-eng"},
+		},
+		"Remove": {
+			reason: "Removing the cost center should be accepted.",
+			cmd:    tagCmd{Name: "dev", Remove: true},
+		},
+		"Neither": {
+			reason: "Either a cost center or --remove must be supplied.",
+			cmd:    tagCmd{Name: "dev"},
+			want:   errors.New(errCostCenterOrRemove),
+		},
+		"Both": {
+			reason: "A cost center cannot be supplied with --remove.",
+			cmd:    tagCmd{Name: "dev", CostCenter: "platform", Remove: true},
+			want:   errors.New(errCostCenterOrRemove),
+		},
+		"InvalidCostCenter": {
+			reason: "A cost center must be a valid label value.",
+			cmd:    tagCmd{Name: "dev", CostCenter: "platform eng"},
+			want:   errors.Errorf(errFmtInvalidTag, "platform eng"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cmd.AfterApply()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAfterApply(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTagRun(t *testing.T) {
+	c := &tagCmd{Name: "dev", CostCenter: "platform-eng"}
+	err := c.Run(context.Background(), &pterm.DefaultBasicText, nil)
+	if diff := cmp.Diff(errors.New(errCostCenterSpaceOnly), err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nTagging a control plane outside a Space should return an error.\nRun(...): -want error, +got error:\n%s", diff)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	errParseTemplate    = "unable to parse control plane template"
+	errMissingParamsFmt = "missing values for template parameters: %s"
+)
+
+// controlPlaneTemplate is a declarative description of a control plane.
+// String values may reference parameters as ${name}, and $$ is a literal $.
+type controlPlaneTemplate struct {
+	Name                 string            `json:"name,omitempty"`
+	Configuration        string            `json:"configuration,omitempty"`
+	ConfigurationPackage string            `json:"configurationPackage,omitempty"`
+	VersionConstraint    string            `json:"versionConstraint,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty"`
+}
+
+// renderTemplate substitutes the supplied parameters into the template and
+// parses the result. An error is returned if the template references a
+// parameter that is not supplied.
+func renderTemplate(tmpl []byte, params map[string]string) (*controlPlaneTemplate, error) {
+	missing := map[string]struct{}{}
+	rendered := expand(string(tmpl), func(key string) string {
+		v, ok := params[key]
+		if !ok {
+			missing[key] = struct{}{}
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		keys := make([]string, 0, len(missing))
+		for k := range missing {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return nil, errors.Errorf(errMissingParamsFmt, strings.Join(keys, ", "))
+	}
+	t := &controlPlaneTemplate{}
+	if err := yaml.UnmarshalStrict([]byte(rendered), t); err != nil {
+		return nil, errors.Wrap(err, errParseTemplate)
+	}
+	return t, nil
+}
+
+// expand replaces each ${name} in s with mapping(name), and each $$ with $.
+// Any other $ is left as is, so that values such as shell snippets or
+// password hashes survive rendering.
+func expand(s string, mapping func(string) string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end <= 0 {
+				b.WriteByte(s[i])
+				continue
+			}
+			b.WriteString(mapping(s[i+2 : i+2+end]))
+			i += end + 2
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
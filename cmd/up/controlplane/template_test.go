@@ -0,0 +1,90 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	type want struct {
+		tmpl *controlPlaneTemplate
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		tmpl   string
+		params map[string]string
+		want   want
+	}{
+		"MissingParams": {
+			reason: "Parameters referenced by the template but not supplied should be reported.",
+			tmpl:   "name: ctp-${env}-${region}\nconfiguration: ${config}\n",
+			params: map[string]string{"config": "platform-ref"},
+			want: want{
+				err: errors.Errorf(errMissingParamsFmt, "env, region"),
+			},
+		},
+		"Substituted": {
+			reason: "Parameters should be substituted into the template.",
+			tmpl:   "name: ctp-${env}\nconfiguration: platform-ref\ndescription: ${env} environment\n",
+			params: map[string]string{"env": "prod"},
+			want: want{
+				tmpl: &controlPlaneTemplate{
+					Name:          "ctp-prod",
+					Configuration: "platform-ref",
+					Description:   "prod environment",
+				},
+			},
+		},
+		"VersionConstraint": {
+			reason: "The configuration package and version constraint should be read from the template.",
+			tmpl:   "name: ctp\nconfigurationPackage: xpkg.upbound.io/acme/platform\nversionConstraint: '${range}'\n",
+			params: map[string]string{"range": ">=1.2, <2"},
+			want: want{
+				tmpl: &controlPlaneTemplate{
+					Name:                 "ctp",
+					ConfigurationPackage: "xpkg.upbound.io/acme/platform",
+					VersionConstraint:    ">=1.2, <2",
+				},
+			},
+		},
+		"Literal": {
+			reason: "Only ${name} should be substituted, $$ should be a literal $, and any other $ should be left as is.",
+			tmpl:   "name: ctp\ndescription: costs $$5, $HOME and $1 stay, {} too, ${} as well\n",
+			want: want{
+				tmpl: &controlPlaneTemplate{
+					Name:        "ctp",
+					Description: "costs $5, $HOME and $1 stay, {} too, ${} as well",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderTemplate([]byte(tc.tmpl), tc.params)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tmpl, got); diff != "" {
+				t.Errorf("\n%s\nrenderTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/alecthomas/kong"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/trace"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *traceCmd) AfterApply(kongCtx *kong.Context) error {
+	c.stdin = os.Stdin
+	c.stdout = kongCtx.Stdout
+	return nil
+}
+
+// traceCmd shows the tree of a claim or composite resource in a control
+// plane.
+type traceCmd struct {
+	stdin  io.Reader
+	stdout io.Writer
+
+	Name     string `arg:"" required:"" help:"Name of control plane." predictor:"ctps"`
+	Resource string `arg:"" required:"" help:"Claim or composite resource to trace, as TYPE[.GROUP]/NAME, such as bucket.example.org/data."`
+
+	Token     string `required:"" help:"API token used to authenticate. If '-' is given the value will be read from stdin."`
+	Namespace string `short:"n" default:"default" help:"Namespace of the claim."`
+}
+
+// Help returns the help text for the trace command.
+func (c *traceCmd) Help() string {
+	return `
+Walks from a claim to its composite resource, and from a composite resource to
+every resource it composes, including nested composite resources. Each resource
+is shown with its Synced and Ready conditions, the message of any condition that
+is not true, and the message of its most recent warning event. Use --format=json
+or --format=yaml to print the tree for tooling.`
+}
+
+// PrintedObjects returns the objects printed by the trace command.
+func (c *traceCmd) PrintedObjects() []any {
+	return []any{&trace.Resource{}}
+}
+
+// Run executes the trace command.
+func (c *traceCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, upCtx *upbound.Context) error {
+	token, err := readToken(c.stdin, c.Token)
+	if err != nil {
+		return err
+	}
+	cfg, err := controlPlaneConfig(upCtx, c.Name, token)
+	if err != nil {
+		return err
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	kClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	w := trace.NewWalker(client, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)), trace.WithEvents(kClient.CoreV1()))
+	ref, err := w.Resolve(c.Resource, c.Namespace)
+	if err != nil {
+		return err
+	}
+	tree, err := w.Walk(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if printer.Format != config.Default {
+		return printer.Print(tree, resourceFieldNames, extractResourceFields)
+	}
+	return trace.Print(c.stdout, tree)
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"github.com/upbound/up/internal/crash"
+	"github.com/upbound/up/internal/version"
+)
+
+// crashExitCode is the exit code of up when it panics, matching the Go
+// runtime.
+const crashExitCode = 2
+
+// reportPanic writes a crash report and exits if the command panicked. It must
+// be deferred.
+func reportPanic(command string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "up: panic: %v\n", r)
+	stack := debug.Stack()
+	if !writeCrashReport(os.Stderr, command, r, stack) {
+		// Without a report the stack must not be lost.
+		os.Stderr.Write(stack) // nolint:errcheck
+	}
+	os.Exit(crashExitCode)
+}
+
+// writeCrashReport writes a crash report of the error or panic value and
+// prints its path on w. It returns false if the report could not be written.
+func writeCrashReport(w io.Writer, command string, cause any, stack []byte) bool {
+	s, err := crash.NewStore()
+	if err != nil {
+		return false
+	}
+	p, err := s.Write(crash.NewReport(version.GetVersion(), command, os.Args[1:], cause, stack))
+	if err != nil {
+		return false
+	}
+	fmt.Fprintf(w, "A crash report was written to %s. Run 'up report send --endpoint=<url> %s' to send it to the maintainers of up.\n", p, p)
+	return true
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// Environment variables set for hooks.
+	envHookCommand    = "UP_COMMAND"
+	envHookResult     = "UP_RESULT"
+	envHookError      = "UP_ERROR"
+	envHookDurationMS = "UP_DURATION_MS"
+
+	hookSuccess = "success"
+	hookFailure = "failure"
+
+	errFmtRunHook = "hook %q failed"
+)
+
+// commandName returns the names of the commands in the supplied path, e.g.
+// "controlplane list".
+func commandName(path []*kong.Path) string {
+	cmds := []string{}
+	for _, p := range path {
+		if p.Command != nil {
+			cmds = append(cmds, p.Command.Name)
+		}
+	}
+	return strings.Join(cmds, " ")
+}
+
+// preHookEnv returns the environment of hooks that run before the command.
+func preHookEnv(command string) []string {
+	return []string{envHookCommand + "=" + command}
+}
+
+// postHookEnv returns the environment of hooks that run after the command,
+// which includes its result.
+func postHookEnv(command string, d time.Duration, cmdErr error) []string {
+	env := []string{
+		envHookCommand + "=" + command,
+		envHookDurationMS + "=" + strconv.FormatInt(d.Milliseconds(), 10),
+	}
+	if cmdErr != nil {
+		return append(env, envHookResult+"="+hookFailure, envHookError+"="+cmdErr.Error())
+	}
+	return append(env, envHookResult+"="+hookSuccess)
+}
+
+// runHooks runs the supplied shell commands in order with the supplied
+// variables added to their environment, stopping at the first that fails.
+// Output of hooks is written to w so that it does not mix with the output of
+// the command.
+func runHooks(ctx context.Context, hooks []string, env []string, w io.Writer) error {
+	for _, h := range hooks {
+		cmd := shellCommand(ctx, h)
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout, cmd.Stderr = w, w
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, errFmtRunHook, h)
+		}
+	}
+	return nil
+}
+
+// shellCommand returns a command that runs the supplied script with the shell
+// of the platform.
+func shellCommand(ctx context.Context, script string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", script)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", script)
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRunHooks(t *testing.T) {
+	type want struct {
+		out string
+		err bool
+	}
+	cases := map[string]struct {
+		reason string
+		hooks  []string
+		env    []string
+		want   want
+	}{
+		"Pre": {
+			reason: "Pre hooks should be run in order with the command in their environment.",
+			hooks:  []string{`echo "before $UP_COMMAND"`, "echo second"},
+			env:    preHookEnv("usage collect"),
+			want:   want{out: "before usage collect\nsecond\n"},
+		},
+		"PostFailure": {
+			reason: "Post hooks should be given the result of the command.",
+			hooks:  []string{`echo "$UP_RESULT: $UP_ERROR ($UP_DURATION_MS)"`},
+			env:    postHookEnv("space upgrade", 1500*time.Millisecond, errors.New("boom")),
+			want:   want{out: "failure: boom (1500)\n"},
+		},
+		"StopOnFailure": {
+			reason: "Hooks after a failing hook should not be run.",
+			hooks:  []string{"echo first", "exit 3", "echo never"},
+			want:   want{out: "first\n", err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := runHooks(context.Background(), tc.hooks, tc.env, out)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nrunHooks(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.out, out.String()); diff != "" {
+				t.Errorf("\n%s\nrunHooks(...): -want output, +got output:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,7 +17,6 @@ package main
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -66,17 +65,7 @@ func (c *loginCmd) AfterApply(kongCtx *kong.Context) error {
 	// TODO(hasheddan): we can't use the typical up-sdk-go client here because
 	// we need to read session cookie from body. We should add support in the
 	// SDK so that we can be consistent across all commands.
-	var tr http.RoundTripper = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: upCtx.InsecureSkipTLSVerify, //nolint:gosec
-		},
-	}
-	if upCtx.WrapTransport != nil {
-		tr = upCtx.WrapTransport(tr)
-	}
-	c.client = &http.Client{
-		Transport: tr,
-	}
+	c.client = upCtx.HTTPClient()
 	kongCtx.Bind(upCtx)
 	if c.Token != "" {
 		return nil
@@ -114,7 +103,7 @@ type loginCmd struct {
 }
 
 // Run executes the login command.
-func (c *loginCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { // nolint:gocyclo
+func (c *loginCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error { // nolint:gocyclo
 	if c.Token == "-" {
 		b, err := io.ReadAll(c.stdin)
 		if err != nil {
@@ -129,7 +118,7 @@ func (c *loginCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { // n
 		}
 		c.Password = strings.TrimSpace(string(b))
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	auth, profType, err := constructAuth(c.Username, c.Token, c.Password)
 	if err != nil {
@@ -181,13 +170,12 @@ func (c *loginCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { // n
 	upCtx.Profile.Type = profType
 	upCtx.Profile.Account = upCtx.Account
 
-	if err := upCtx.Cfg.AddOrUpdateUpboundProfile(upCtx.ProfileName, upCtx.Profile); err != nil {
-		return errors.Wrap(err, errLoginFailed)
-	}
-	if err := upCtx.Cfg.SetDefaultUpboundProfile(upCtx.ProfileName); err != nil {
-		return errors.Wrap(err, errLoginFailed)
-	}
-	if err := upCtx.CfgSrc.UpdateConfig(upCtx.Cfg); err != nil {
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		if err := cfg.AddOrUpdateUpboundProfile(upCtx.ProfileName, upCtx.Profile); err != nil {
+			return errors.Wrap(err, errLoginFailed)
+		}
+		return errors.Wrap(cfg.SetDefaultUpboundProfile(upCtx.ProfileName), errLoginFailed)
+	}); err != nil {
 		return errors.Wrap(err, errUpdateConfig)
 	}
 	p.Printfln("%s logged in", auth.ID)
@@ -24,6 +24,7 @@ import (
 
 	"github.com/upbound/up-sdk-go"
 
+	"github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/upbound"
 )
 
@@ -58,8 +59,8 @@ type logoutCmd struct {
 }
 
 // Run executes the logout command.
-func (c *logoutCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+func (c *logoutCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 	req, err := c.client.NewRequest(ctx, http.MethodPost, logoutPath, "", nil)
 	if err != nil {
@@ -70,10 +71,9 @@ func (c *logoutCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
 	}
 	// Logout is successful, remove token from config and update.
 	upCtx.Profile.Session = ""
-	if err := upCtx.Cfg.AddOrUpdateUpboundProfile(upCtx.ProfileName, upCtx.Profile); err != nil {
-		return errors.Wrap(err, errRemoveTokenFailed)
-	}
-	if err := upCtx.CfgSrc.UpdateConfig(upCtx.Cfg); err != nil {
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		return errors.Wrap(cfg.AddOrUpdateUpboundProfile(upCtx.ProfileName, upCtx.Profile), errRemoveTokenFailed)
+	}); err != nil {
 		return errors.Wrap(err, errUpdateConfig)
 	}
 
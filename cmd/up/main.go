@@ -15,23 +15,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/pterm/pterm"
 	"github.com/willabides/kongplete"
 
+	configcmd "github.com/upbound/up/cmd/up/config"
 	"github.com/upbound/up/cmd/up/configuration"
 	"github.com/upbound/up/cmd/up/configuration/template"
 	"github.com/upbound/up/cmd/up/controlplane"
 	"github.com/upbound/up/cmd/up/organization"
 	"github.com/upbound/up/cmd/up/profile"
+	"github.com/upbound/up/cmd/up/report"
 	"github.com/upbound/up/cmd/up/repository"
 	"github.com/upbound/up/cmd/up/robot"
 	"github.com/upbound/up/cmd/up/space"
+	"github.com/upbound/up/cmd/up/telemetry"
 	"github.com/upbound/up/cmd/up/upbound"
+	"github.com/upbound/up/cmd/up/usage"
 	"github.com/upbound/up/cmd/up/uxp"
 	"github.com/upbound/up/cmd/up/xpkg"
 	"github.com/upbound/up/cmd/up/xpls"
@@ -63,9 +69,19 @@ func (c *cli) AfterApply(ctx *kong.Context) error { //nolint:unparam
 		ctx.Stdout, ctx.Stderr = io.Discard, io.Discard
 	}
 	ctx.BindTo(pterm.DefaultBasicText.WithWriter(ctx.Stdout), (*pterm.TextPrinter)(nil))
+	// Configured output defaults apply unless overridden by flags.
+	if conf := loadConfig(); conf != nil {
+		if c.Format == config.Default {
+			c.Format = conf.OutputFormat()
+		}
+		c.NoColor = c.NoColor || !conf.ColorEnabled()
+	}
+	upterm.SetNoColor(c.NoColor)
 	// TODO(hasheddan): configure pretty print styling to match Upbound
 	// branding.
-	if !c.Pretty {
+	if c.Pretty {
+		upterm.EnableStyling()
+	} else {
 		// NOTE(hasheddan): enabling styling can make processing output with
 		// other tooling difficult.
 		pterm.DisableStyling()
@@ -78,12 +94,40 @@ func (c *cli) AfterApply(ctx *kong.Context) error { //nolint:unparam
 
 	ctx.Bind(printer)
 	ctx.Bind(c.Quiet)
+
+	// All API calls made by a command share a single context, which is
+	// bounded by the configured timeout if one is set, unless the command runs
+	// until interrupted.
+	cmdCtx, cancel := context.Background(), context.CancelFunc(func() {})
+	if c.Timeout > 0 && !unbounded(ctx) {
+		cmdCtx, cancel = context.WithTimeout(cmdCtx, c.Timeout)
+	}
+	c.cancel = cancel
+	// workaround interfaces not being bindable ref: https://github.com/alecthomas/kong/issues/48
+	ctx.BindTo(cmdCtx, (*context.Context)(nil))
 	return nil
 }
 
+// An unboundedCommand may run until it is interrupted, e.g. to watch or to
+// serve, and is therefore not bounded by --timeout when Unbounded returns true.
+type unboundedCommand interface {
+	Unbounded() bool
+}
+
+// unbounded returns true if the selected command runs until interrupted.
+func unbounded(ctx *kong.Context) bool {
+	n := ctx.Selected()
+	if n == nil || !n.Target.CanAddr() {
+		return false
+	}
+	u, ok := n.Target.Addr().Interface().(unboundedCommand)
+	return ok && u.Unbounded()
+}
+
 // BeforeReset runs before all other hooks. Default maturity level is stable.
 func (c *cli) BeforeReset(ctx *kong.Context, p *kong.Path) error {
 	ctx.Bind(feature.Stable)
+	feature.ShowMaturing(showMaturing())
 	// If no command is selected, we are emitting help and filter maturity.
 	if ctx.Selected() == nil {
 		return feature.HideMaturity(p, feature.Stable)
@@ -92,16 +136,25 @@ func (c *cli) BeforeReset(ctx *kong.Context, p *kong.Path) error {
 }
 
 type cli struct {
-	Format  config.Format    `name:"format" enum:"default,json,yaml" default:"default" help:"Format for get/list commands. Can be: json, yaml, default"`
+	cancel context.CancelFunc
+
+	Format  config.Format    `name:"format" enum:"default,json,yaml,machine" default:"default" help:"Format for get/list commands. Can be: json, yaml, machine, default"`
 	Version versionFlag      `short:"v" name:"version" help:"Print version and exit."`
 	Quiet   config.QuietFlag `short:"q" name:"quiet" help:"Suppress all output."`
 	Pretty  bool             `name:"pretty" help:"Pretty print output."`
+	NoColor bool             `name:"no-color" help:"Disable colored and animated output. Also disabled if NO_COLOR is set, TERM is dumb, or output is not a terminal."`
+	Timeout time.Duration    `name:"timeout" env:"UP_TIMEOUT" default:"0s" help:"Maximum duration of a command, including time spent at prompts. Commands that run until interrupted, such as watches and port forwards, are not bounded. Zero means no timeout."`
+	Crash   bool             `name:"report" help:"Write a crash report if the command fails. Reports are always written if up panics."`
 
-	License licenseCmd `cmd:"" help:"Print Up license information."`
+	License     licenseCmd `cmd:"" help:"Print Up license information."`
+	VersionInfo versionCmd `cmd:"" name:"version" help:"Print the versions of up and the components in the current cluster."`
 
 	Help               helpCmd                      `cmd:"" help:"Show help."`
+	Commands           commandsCmd                  `cmd:"" help:"List the commands of up, or print them with their flags as JSON."`
 	Login              loginCmd                     `cmd:"" help:"Login to Upbound."`
 	Logout             logoutCmd                    `cmd:"" help:"Logout of Upbound."`
+	CanI               canICmd                      `cmd:"" name:"can-i" help:"Check whether an action is allowed in the Space of the current profile."`
+	Config             configcmd.Cmd                `cmd:"" name:"config" help:"Read and write up configuration settings."`
 	Configuration      configuration.Cmd            `cmd:"" name:"configuration" aliases:"cfg" help:"Interact with configurations."`
 	ControlPlane       controlplane.Cmd             `cmd:"" name:"controlplane" aliases:"ctp" help:"Interact with control planes."`
 	Organization       organization.Cmd             `cmd:"" name:"organization" aliases:"org" help:"Interact with organizations."`
@@ -112,8 +165,14 @@ type cli struct {
 	XPKG               xpkg.Cmd                     `cmd:"" help:"Interact with UXP packages."`
 	XPLS               xpls.Cmd                     `cmd:"" help:"Start xpls language server."`
 	Alpha              alpha                        `cmd:"" help:"Alpha features. Commands may be removed in future releases."`
-	InstallCompletions kongplete.InstallCompletions `cmd:"" help:"Install shell completions"`
+	Beta               beta                         `cmd:"" help:"Beta features. Commands may change in future releases."`
+	Completion         completionCmd                `cmd:"" help:"Print or install shell completion scripts."`
+	InstallCompletions kongplete.InstallCompletions `cmd:"" hidden:"" help:"Install shell completions. Use completion instead."`
 	Space              space.Cmd                    `cmd:"" help:"Interact with spaces."`
+	Telemetry          telemetry.Cmd                `cmd:"" help:"Manage anonymous usage metrics."`
+	Report             report.Cmd                   `cmd:"" help:"Manage crash reports."`
+	Usage              usage.Cmd                    `cmd:"" help:"Collect usage data from Spaces storage."`
+	UpgradeCLI         upgradeCLICmd                `cmd:"" name:"upgrade-cli" help:"Upgrade up to the latest version."`
 }
 
 type helpCmd struct{}
@@ -138,6 +197,42 @@ type alpha struct {
 	XPKG         xpkg.Cmd         `cmd:"" maturity:"alpha" help:"Interact with UXP packages."`
 }
 
+// BeforeReset runs before all other hooks. If command has beta as an ancestor,
+// maturity level will be set to beta.
+func (b *beta) BeforeReset(ctx *kong.Context) error { //nolint:unparam
+	ctx.Bind(feature.Beta)
+	return nil
+}
+
+// beta contains only the commands that are in beta, grouped as they are in
+// the stable command tree.
+type beta struct {
+	ControlPlane controlplane.BetaCmd `cmd:"" maturity:"beta" name:"controlplane" aliases:"ctp" help:"Interact with control planes."`
+	Space        space.BetaCmd        `cmd:"" maturity:"beta" help:"Interact with spaces."`
+}
+
+// showMaturing returns true if the user has opted in to showing alpha and
+// beta commands alongside stable commands.
+func showMaturing() bool {
+	conf := loadConfig()
+	return conf != nil && conf.ShowMaturingCommands()
+}
+
+// loadConfig returns the up config, or nil if it cannot be read. Help and
+// global settings must be available without a valid config, so errors are
+// ignored.
+func loadConfig() *config.Config {
+	p, err := config.GetDefaultPath()
+	if err != nil {
+		return nil
+	}
+	conf, err := config.Extract(config.NewFSSource(config.WithPath(p)))
+	if err != nil {
+		return nil
+	}
+	return conf
+}
+
 func main() {
 	c := cli{}
 
@@ -173,7 +268,31 @@ func main() {
 		return
 	}
 
-	ctx, err := parser.Parse(os.Args[1:])
+	conf := loadConfig()
+	ctx, err := parser.Parse(expandArgs(parser, conf, os.Args[1:]))
 	parser.FatalIfErrorf(err)
-	ctx.FatalIfErrorf(ctx.Run())
+	defer reportPanic(ctx.Command())
+	command, hooks := commandName(ctx.Path), config.Hooks{}
+	if conf != nil {
+		hooks = conf.CommandHooks(command)
+	}
+	start := time.Now()
+	// The command is not run if a pre hook fails. Post hooks run whenever the
+	// command ran, but cannot change its result.
+	err = runHooks(context.Background(), hooks.Pre, preHookEnv(command), ctx.Stderr)
+	if err == nil {
+		err = ctx.Run()
+		if c.cancel != nil {
+			c.cancel()
+		}
+		if herr := runHooks(context.Background(), hooks.Post, postHookEnv(command, time.Since(start), err), ctx.Stderr); herr != nil {
+			pterm.Warning.WithWriter(ctx.Stderr).Println(herr.Error())
+		}
+	}
+	recordTelemetry(ctx.Command(), time.Since(start), err)
+	notifyUpgrade(ctx.Command(), ctx.Stderr)
+	if err != nil && c.Crash {
+		writeCrashReport(ctx.Stderr, ctx.Command(), err, nil)
+	}
+	ctx.FatalIfErrorf(upterm.Explain(err))
 }
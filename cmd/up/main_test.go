@@ -0,0 +1,126 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+type unboundedWatchCmd struct {
+	Watch bool `help:"Watch for changes."`
+}
+
+func (c *unboundedWatchCmd) Unbounded() bool { return c.Watch }
+
+func (c *unboundedWatchCmd) Run() error { return nil }
+
+type unboundedGetCmd struct{}
+
+func (c *unboundedGetCmd) Run() error { return nil }
+
+type unboundedCLI struct {
+	List unboundedWatchCmd `cmd:"" help:"List things."`
+	Get  unboundedGetCmd   `cmd:"" help:"Get a thing."`
+}
+
+func TestUnbounded(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		args   []string
+		want   bool
+	}{
+		"Watch": {
+			reason: "A command that watches should not be bounded by the timeout.",
+			args:   []string{"list", "--watch"},
+			want:   true,
+		},
+		"NoWatch": {
+			reason: "A command that may watch should be bounded when it does not.",
+			args:   []string{"list"},
+			want:   false,
+		},
+		"NotUnboundedCommand": {
+			reason: "A command that never runs until interrupted should be bounded.",
+			args:   []string{"get"},
+			want:   false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			parser, err := kong.New(&unboundedCLI{})
+			if err != nil {
+				t.Fatalf("kong.New(...): %v", err)
+			}
+			ctx, err := parser.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("Parse(...): %v", err)
+			}
+			if got := unbounded(ctx); got != tc.want {
+				t.Errorf("\n%s\nunbounded(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+// TestMachineOutput checks that every command that prints objects supports
+// --format=machine for each of them.
+func TestMachineOutput(t *testing.T) {
+	parser, err := kong.New(&cli{})
+	if err != nil {
+		t.Fatalf("kong.New(...): %v", err)
+	}
+	printerType := reflect.TypeOf(upterm.ObjectPrinter{})
+	var walk func(path string, n *kong.Node)
+	walk = func(path string, n *kong.Node) {
+		for _, c := range n.Children {
+			walk(strings.TrimSpace(path+" "+c.Name), c)
+		}
+		if n.Type != kong.CommandNode || !takesArg(n.Target.Addr(), "Run", printerType) {
+			return
+		}
+		t.Run(path, func(t *testing.T) {
+			pc, ok := n.Target.Addr().Interface().(upterm.PrintingCommand)
+			if !ok {
+				t.Fatalf("%s prints objects but does not implement upterm.PrintingCommand", path)
+			}
+			for _, obj := range pc.PrintedObjects() {
+				if !upterm.HasSchema(obj) {
+					t.Errorf("%s prints %T, which has no machine output schema", path, obj)
+				}
+			}
+		})
+	}
+	walk("", parser.Model.Node)
+}
+
+// takesArg returns true if the named method of v takes an argument of type t.
+func takesArg(v reflect.Value, method string, t reflect.Type) bool {
+	m := v.MethodByName(method)
+	if !m.IsValid() {
+		return false
+	}
+	for i := 0; i < m.Type().NumIn(); i++ {
+		if m.Type().In(i) == t {
+			return true
+		}
+	}
+	return false
+}
@@ -36,14 +36,21 @@ func (c *getCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error
 // getCmd gets a single organization on Upbound.
 type getCmd struct {
 	Name string `arg:"" required:"" help:"Name of organization." predictor:"orgs"`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{organizations.Organization{}}
 }
 
 // Run executes the get command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
 
 	// The get command accepts a name, but the get API call takes an ID
 	// Therefore we get all orgs and find the one the user requested
-	orgs, err := oc.List(context.Background())
+	orgs, err := oc.List(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,166 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invite
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/journal"
+)
+
+const (
+	permissionMember = "member"
+	permissionOwner  = "owner"
+
+	// csvHeaderEmail is the first column of an optional CSV header row.
+	csvHeaderEmail = "email"
+
+	errNoEmails        = "at least one email or --from-csv must be given"
+	errOpenCSV         = "unable to open CSV file"
+	errReadCSV         = "unable to read CSV file"
+	errFmtCSVColumns   = "line %d: expected email and an optional role, got %d columns"
+	errFmtCSVRole      = "line %d: role must be %q or %q, got %q"
+	errFmtCSVNoEmail   = "line %d: email must not be empty"
+	errFmtInviteFailed = "unable to invite %d of %d users"
+)
+
+// invitation is a user to invite with the role they are granted.
+type invitation struct {
+	Email      string
+	Permission organizations.OrganizationPermissionGroup
+}
+
+// createCmd invites one or more users to an organization.
+type createCmd struct {
+	OrgName string   `arg:"" required:"" help:"Name of the organization."`
+	Emails  []string `arg:"" optional:"" help:"Email addresses of the users to invite."`
+
+	Permission organizations.OrganizationPermissionGroup `short:"p" enum:"member,owner" default:"member" help:"Role of the invited users (owner or member). Roles in the CSV file take precedence."`
+	FromCSV    string                                    `name:"from-csv" type:"existingfile" help:"Path to a CSV file of users to invite, one per line as email[,role]. An optional header row starting with \"email\" is skipped."`
+	Resume     string                                    `help:"ID of a partially failed invitation to resume. Users it already invited are skipped."`
+}
+
+// Run executes the create invite command.
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter, oc *organizations.Client) error {
+	invs := make([]invitation, 0, len(c.Emails))
+	for _, e := range c.Emails {
+		invs = append(invs, invitation{Email: e, Permission: c.Permission})
+	}
+	if c.FromCSV != "" {
+		f, err := os.Open(c.FromCSV)
+		if err != nil {
+			return errors.Wrap(err, errOpenCSV)
+		}
+		defer f.Close() // nolint:errcheck
+		fromCSV, err := parseCSV(f, c.Permission)
+		if err != nil {
+			return errors.Wrap(err, errReadCSV)
+		}
+		invs = append(invs, fromCSV...)
+	}
+	if len(invs) == 0 {
+		return errors.New(errNoEmails)
+	}
+
+	orgID, err := oc.GetOrgID(ctx, c.OrgName)
+	if err != nil {
+		return err
+	}
+
+	store, err := journal.NewStore()
+	if err != nil {
+		return err
+	}
+	j, err := store.Start("organization-invite-create/"+c.OrgName, c.Resume)
+	if err != nil {
+		return err
+	}
+
+	// Invite everyone before reporting failures so that a single bad address
+	// does not require the whole batch to be retried.
+	failed := 0
+	for _, inv := range invs {
+		if j.Done(inv.Email) {
+			p.Printfln("%s already invited", inv.Email)
+			continue
+		}
+		err := oc.CreateInvite(ctx, orgID, &organizations.OrganizationInviteCreateParameters{
+			Email:      inv.Email,
+			Permission: inv.Permission,
+		})
+		if jerr := j.Record(inv.Email, err); jerr != nil {
+			return jerr
+		}
+		if err != nil {
+			failed++
+			pterm.Error.Printfln("Unable to invite %s: %s", inv.Email, err)
+			continue
+		}
+		p.Printfln("%s invited as %s", inv.Email, inv.Permission)
+	}
+	if failed > 0 {
+		p.Println(j.ResumeHint())
+		return errors.Errorf(errFmtInviteFailed, failed, len(invs))
+	}
+	return j.Finish()
+}
+
+// parseCSV reads invitations from r, one per line as email[,role]. Lines
+// without a role are granted def.
+func parseCSV(r io.Reader, def organizations.OrganizationPermissionGroup) ([]invitation, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	var invs []invitation
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return invs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), csvHeaderEmail) {
+			continue
+		}
+		if len(rec) > 2 {
+			return nil, errors.Errorf(errFmtCSVColumns, line, len(rec))
+		}
+		inv := invitation{Email: strings.TrimSpace(rec[0]), Permission: def}
+		if inv.Email == "" {
+			return nil, errors.Errorf(errFmtCSVNoEmail, line)
+		}
+		if len(rec) == 2 && strings.TrimSpace(rec[1]) != "" {
+			role := strings.ToLower(strings.TrimSpace(rec[1]))
+			if role != permissionMember && role != permissionOwner {
+				return nil, errors.Errorf(errFmtCSVRole, line, permissionMember, permissionOwner, rec[1])
+			}
+			inv.Permission = organizations.OrganizationPermissionGroup(role)
+		}
+		invs = append(invs, inv)
+	}
+}
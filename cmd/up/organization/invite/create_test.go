@@ -0,0 +1,94 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+)
+
+func TestParseCSV(t *testing.T) {
+	member := organizations.OrganizationPermissionGroup(permissionMember)
+	owner := organizations.OrganizationPermissionGroup(permissionOwner)
+
+	type want struct {
+		invs []invitation
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		csv    string
+		want   want
+	}{
+		"EmailsOnly": {
+			reason: "Lines without a role should be granted the default role.",
+			csv:    "a@example.com\nb@example.com\n",
+			want: want{
+				invs: []invitation{
+					{Email: "a@example.com", Permission: member},
+					{Email: "b@example.com", Permission: member},
+				},
+			},
+		},
+		"HeaderAndRoles": {
+			reason: "A header row should be skipped and roles should be honored regardless of case.",
+			csv:    "Email,Role\na@example.com, Owner\n# comment\nb@example.com,\n",
+			want: want{
+				invs: []invitation{
+					{Email: "a@example.com", Permission: owner},
+					{Email: "b@example.com", Permission: member},
+				},
+			},
+		},
+		"InvalidRole": {
+			reason: "An unknown role should be rejected with its line number.",
+			csv:    "a@example.com,member\nb@example.com,admin\n",
+			want: want{
+				err: errors.Errorf(errFmtCSVRole, 2, permissionMember, permissionOwner, "admin"),
+			},
+		},
+		"TooManyColumns": {
+			reason: "Lines with more than two columns should be rejected.",
+			csv:    "a@example.com,member,extra\n",
+			want: want{
+				err: errors.Errorf(errFmtCSVColumns, 1, 3),
+			},
+		},
+		"EmptyEmail": {
+			reason: "Lines without an email should be rejected.",
+			csv:    "a@example.com\n,owner\n",
+			want: want{
+				err: errors.Errorf(errFmtCSVNoEmail, 2),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			invs, err := parseCSV(strings.NewReader(tc.csv), member)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseCSV(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.invs, invs); diff != "" {
+				t.Errorf("\n%s\nparseCSV(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
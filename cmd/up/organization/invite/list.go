@@ -0,0 +1,73 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invite
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/alecthomas/kong"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+var listFieldNames = []string{"ID", "EMAIL", "PERMISSION"}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *listCmd) AfterApply(kongCtx *kong.Context) error {
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// listCmd lists the pending invitations of an organization.
+type listCmd struct {
+	OrgName string `arg:"" required:"" help:"Name of the organization."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{organizations.Invite{}}
+}
+
+// Run executes the list invites command.
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, oc *organizations.Client) error {
+	orgID, err := oc.GetOrgID(ctx, c.OrgName)
+	if err != nil {
+		return err
+	}
+	invites, err := oc.ListInvites(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if len(invites) == 0 {
+		p.Printfln("No pending invitations found in %s", c.OrgName)
+		return nil
+	}
+	sort.SliceStable(invites, func(i, j int) bool {
+		return invites[i].Email < invites[j].Email
+	})
+	return printer.Print(invites, listFieldNames, extractFields)
+}
+
+func extractFields(obj any) []string {
+	i := obj.(organizations.Invite)
+	return []string{strconv.FormatUint(uint64(i.ID), 10), i.Email, string(i.Permission)}
+}
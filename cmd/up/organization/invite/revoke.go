@@ -0,0 +1,87 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/input"
+)
+
+const (
+	errFmtNoInvite   = "no pending invitation for %q"
+	errOpCanceled    = "operation canceled"
+	revokeConfirmFmt = "Are you sure you want to revoke the invitation for %s? [y/n]"
+)
+
+// BeforeApply sets default values in revoke before assignment and validation.
+func (c *revokeCmd) BeforeApply() error {
+	c.prompter = input.NewPrompter()
+	return nil
+}
+
+// AfterApply asks for confirmation unless revocation is forced.
+func (c *revokeCmd) AfterApply() error {
+	if c.Force {
+		return nil
+	}
+	confirm, err := c.prompter.Prompt(fmt.Sprintf(revokeConfirmFmt, c.Email), false)
+	if err != nil {
+		return err
+	}
+	if input.InputYes(confirm) {
+		return nil
+	}
+	return errors.New(errOpCanceled)
+}
+
+// revokeCmd revokes a pending invitation to an organization.
+type revokeCmd struct {
+	prompter input.Prompter
+
+	OrgName string `arg:"" required:"" help:"Name of the organization."`
+	Email   string `arg:"" required:"" help:"Email address the invitation was sent to."`
+
+	Force bool `help:"Force revocation of the invitation." default:"false"`
+}
+
+// Run executes the revoke invite command.
+func (c *revokeCmd) Run(ctx context.Context, p pterm.TextPrinter, oc *organizations.Client) error {
+	orgID, err := oc.GetOrgID(ctx, c.OrgName)
+	if err != nil {
+		return err
+	}
+	invites, err := oc.ListInvites(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, i := range invites {
+		if i.Email != c.Email {
+			continue
+		}
+		if err := oc.DeleteInvite(ctx, orgID, i.ID); err != nil {
+			return err
+		}
+		p.Printfln("Invitation for %s revoked from %s", c.Email, c.OrgName)
+		return nil
+	}
+	return errors.Errorf(errFmtNoInvite, c.Email)
+}
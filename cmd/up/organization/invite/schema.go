@@ -0,0 +1,41 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invite
+
+import (
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// inviteV1 is version v1 of the machine output of an invite to an
+// organization.
+type inviteV1 struct {
+	ID         uint   `json:"id"`
+	Email      string `json:"email"`
+	Permission string `json:"permission"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(organizations.Invite{}, upterm.Schema{
+		Kind:    "OrganizationInvite",
+		Version: "v1",
+		Convert: func(obj any) any {
+			i := obj.(organizations.Invite)
+			return inviteV1{ID: i.ID, Email: i.Email, Permission: string(i.Permission), CreatedAt: i.CreatedAt}
+		},
+	})
+}
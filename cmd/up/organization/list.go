@@ -34,13 +34,20 @@ func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) erro
 }
 
 // listCmd lists organizations on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	Output upterm.OutputFlags `embed:""`
+}
 
 var fieldNames = []string{"ID", "NAME", "ROLE"}
 
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{organizations.Organization{}}
+}
+
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
-	orgs, err := oc.List(context.Background())
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
+	orgs, err := oc.List(ctx)
 	if err != nil {
 		return err
 	}
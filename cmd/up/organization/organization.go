@@ -22,6 +22,7 @@ import (
 
 	"github.com/upbound/up-sdk-go/service/organizations"
 
+	"github.com/upbound/up/cmd/up/organization/invite"
 	"github.com/upbound/up/cmd/up/organization/user"
 	"github.com/upbound/up/internal/upbound"
 )
@@ -44,6 +45,9 @@ func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
 
 func PredictOrgs() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -58,7 +62,7 @@ func PredictOrgs() complete.Predictor {
 			return nil
 		}
 
-		orgs, err := oc.List(context.Background())
+		orgs, err := oc.List(ctx)
 		if err != nil {
 			return nil
 		}
@@ -82,7 +86,8 @@ type Cmd struct {
 	List   listCmd   `cmd:"" help:"List organizations."`
 	Get    getCmd    `cmd:"" help:"Get an organization."`
 
-	User user.Cmd `cmd:"" help:"Manage organization users."`
+	User   user.Cmd   `cmd:"" help:"Manage organization users."`
+	Invite invite.Cmd `cmd:"" help:"Manage pending organization invitations."`
 
 	// Common Upbound API configuration
 	Flags upbound.Flags `embed:""`
@@ -0,0 +1,42 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package organization
+
+import (
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// organizationV1 is version v1 of the machine output of an organization.
+type organizationV1 struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Role        string `json:"role,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(organizations.Organization{}, upterm.Schema{
+		Kind:    "Organization",
+		Version: "v1",
+		Convert: convertOrganizationV1,
+	})
+}
+
+func convertOrganizationV1(obj any) any {
+	o := obj.(organizations.Organization)
+	return organizationV1{ID: o.ID, Name: o.Name, DisplayName: o.DisplayName, Role: string(o.Role)}
+}
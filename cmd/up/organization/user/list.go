@@ -48,19 +48,26 @@ func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) erro
 // It lists both members and invites.
 type listCmd struct {
 	OrgName string `arg:"" required:"" help:"Name of the organization."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{Member{}}
 }
 
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
-	orgID, err := oc.GetOrgID(context.Background(), c.OrgName)
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, oc *organizations.Client, upCtx *upbound.Context) error {
+	orgID, err := oc.GetOrgID(ctx, c.OrgName)
 	if err != nil {
 		return err
 	}
-	members, err := oc.ListMembers(context.Background(), orgID)
+	members, err := oc.ListMembers(ctx, orgID)
 	if err != nil {
 		return err
 	}
-	invites, err := oc.ListInvites(context.Background(), orgID)
+	invites, err := oc.ListInvites(ctx, orgID)
 	if err != nil {
 		return err
 	}
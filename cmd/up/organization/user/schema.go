@@ -0,0 +1,44 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"github.com/upbound/up/internal/upterm"
+)
+
+// memberV1 is version v1 of the machine output of a member of an
+// organization, or of a user invited to it.
+type memberV1 struct {
+	Username   string `json:"username,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Email      string `json:"email"`
+	Permission string `json:"permission"`
+	Status     string `json:"status"`
+}
+
+func init() {
+	upterm.RegisterSchema(Member{}, upterm.Schema{
+		Kind:    "OrganizationMember",
+		Version: "v1",
+		Convert: func(obj any) any {
+			m := obj.(Member)
+			if m.Member.User.Username != "" {
+				u := m.Member.User
+				return memberV1{Username: u.Username, Name: u.Name, Email: u.Email, Permission: string(m.Member.Permission), Status: statusActive}
+			}
+			return memberV1{Email: m.Invite.Email, Permission: string(m.Invite.Permission), Status: statusInvited}
+		},
+	})
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
+	upconfig "github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/upbound"
 )
 
@@ -44,25 +45,24 @@ func (c *setCmd) Run(upCtx *upbound.Context) error {
 		return err
 	}
 
-	profile, _, err := upCtx.Cfg.GetDefaultUpboundProfile()
-	if err != nil {
-		return err
-	}
-
-	cfg := map[string]any{
+	values := map[string]any{
 		c.Key: c.Value,
 	}
 	if c.File != nil {
-		cfg, err = mapFromFile(c.File)
+		var err error
+		values, err = mapFromFile(c.File)
 		if err != nil {
 			return err
 		}
 	}
 
-	if err := c.addConfigs(upCtx, profile, cfg); err != nil {
-		return err
-	}
-	return errors.Wrap(upCtx.CfgSrc.UpdateConfig(upCtx.Cfg), errUpdateConfig)
+	return errors.Wrap(upCtx.ModifyConfig(func(cfg *upconfig.Config) error {
+		profile, _, err := cfg.GetDefaultUpboundProfile()
+		if err != nil {
+			return err
+		}
+		return c.addConfigs(cfg, profile, values)
+	}), errUpdateConfig)
 }
 
 func (c *setCmd) validateInput() error {
@@ -90,9 +90,9 @@ func mapFromFile(f *os.File) (map[string]any, error) {
 	return cfg, nil
 }
 
-func (c *setCmd) addConfigs(upCtx *upbound.Context, profile string, config map[string]any) error {
-	for k, v := range config {
-		if err := upCtx.Cfg.AddToBaseConfig(profile, k, fmt.Sprintf("%v", v)); err != nil {
+func (c *setCmd) addConfigs(cfg *upconfig.Config, profile string, values map[string]any) error {
+	for k, v := range values {
+		if err := cfg.AddToBaseConfig(profile, k, fmt.Sprintf("%v", v)); err != nil {
 			return err
 		}
 	}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
+	upconfig "github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/upbound"
 )
 
@@ -33,25 +34,24 @@ func (c *unsetCmd) Run(upCtx *upbound.Context) error {
 		return err
 	}
 
-	profile, _, err := upCtx.Cfg.GetDefaultUpboundProfile()
-	if err != nil {
-		return err
-	}
-
-	cfg := map[string]any{
+	values := map[string]any{
 		c.Key: 0,
 	}
 	if c.File != nil {
-		cfg, err = mapFromFile(c.File)
+		var err error
+		values, err = mapFromFile(c.File)
 		if err != nil {
 			return err
 		}
 	}
 
-	if err := c.removeConfigs(upCtx, profile, cfg); err != nil {
-		return err
-	}
-	return errors.Wrap(upCtx.CfgSrc.UpdateConfig(upCtx.Cfg), errUpdateConfig)
+	return errors.Wrap(upCtx.ModifyConfig(func(cfg *upconfig.Config) error {
+		profile, _, err := cfg.GetDefaultUpboundProfile()
+		if err != nil {
+			return err
+		}
+		return c.removeConfigs(cfg, profile, values)
+	}), errUpdateConfig)
 }
 
 func (c *unsetCmd) validateInput() error {
@@ -65,9 +65,9 @@ func (c *unsetCmd) validateInput() error {
 	return errors.New(errOnlyKVFileXOR)
 }
 
-func (c *unsetCmd) removeConfigs(upCtx *upbound.Context, profile string, config map[string]any) error {
-	for k := range config {
-		if err := upCtx.Cfg.RemoveFromBaseConfig(profile, k); err != nil {
+func (c *unsetCmd) removeConfigs(cfg *upconfig.Config, profile string, values map[string]any) error {
+	for k := range values {
+		if err := cfg.RemoveFromBaseConfig(profile, k); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,72 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upbound"
+)
+
+// setCmd contains commands for creating or updating profiles.
+type setCmd struct {
+	Space setSpaceCmd `cmd:"" help:"Create or update a profile for a self-hosted Space."`
+}
+
+type setSpaceCmd struct {
+	Name string `arg:"" required:"" help:"Name of the Profile." predictor:"profiles"`
+
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig  string `type:"existingfile" help:"Kubeconfig of the Space cluster. Same defaults as kubectl are used if not provided."`
+	KubeContext string `help:"Kubeconfig context of the Space cluster. The current context is used if not provided."`
+	Use         bool   `help:"Use the Profile as the default Profile."`
+}
+
+// Help returns the help text for the set space command.
+func (c *setSpaceCmd) Help() string {
+	return `
+While a space profile is selected, 'up controlplane create', 'list', 'get', and
+'delete' manage ControlPlane resources in the Space cluster instead of using
+the Upbound API.`
+}
+
+// Run executes the set space command.
+func (c *setSpaceCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	// Ensure the supplied kubeconfig and context are valid.
+	if _, err := kube.GetKubeConfigWithContext(c.Kubeconfig, c.KubeContext); err != nil {
+		return err
+	}
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		if err := cfg.AddOrUpdateUpboundProfile(c.Name, config.Profile{
+			ID:          c.Name,
+			Type:        config.SpaceProfileType,
+			Kubeconfig:  c.Kubeconfig,
+			KubeContext: c.KubeContext,
+		}); err != nil {
+			return err
+		}
+		if c.Use {
+			return cfg.SetDefaultUpboundProfile(c.Name)
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, errUpdateProfile)
+	}
+	p.Printfln("Profile %s set", c.Name)
+	return nil
+}
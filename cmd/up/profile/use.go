@@ -17,6 +17,7 @@ package profile
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
+	"github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/upbound"
 )
 
@@ -30,9 +31,7 @@ type useCmd struct {
 
 // Run executes the Use command.
 func (c *useCmd) Run(upCtx *upbound.Context) error {
-	if err := upCtx.Cfg.SetDefaultUpboundProfile(c.Name); err != nil {
-		return err
-	}
-
-	return errors.Wrap(upCtx.CfgSrc.UpdateConfig(upCtx.Cfg), errUpdateProfile)
+	return errors.Wrap(upCtx.ModifyConfig(func(cfg *config.Config) error {
+		return cfg.SetDefaultUpboundProfile(c.Name)
+	}), errUpdateProfile)
 }
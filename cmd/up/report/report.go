@@ -0,0 +1,82 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/crash"
+)
+
+const (
+	errFmtEndpoint = "invalid crash report endpoint %q, must be an http or https URL"
+)
+
+// Cmd contains commands for managing crash reports.
+type Cmd struct {
+	Send sendCmd `cmd:"" help:"Send a crash report to the maintainers of up."`
+}
+
+// Help returns the help text for the report commands.
+func (c *Cmd) Help() string {
+	return `
+When up panics, or a command run with --report fails, a crash report is written
+to the crashes directory next to the up config file. Reports contain the error,
+the stack, the version of up and the names of the flags that were passed. The
+values of arguments and flags are not recorded, and tokens, secrets and home
+directories are redacted from the error. Reports are only sent when requested
+with the send command, to the endpoint given with --endpoint.`
+}
+
+// AfterApply validates the endpoint.
+func (c *sendCmd) AfterApply() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf(errFmtEndpoint, c.Endpoint)
+	}
+	return nil
+}
+
+type sendCmd struct {
+	Path     string `arg:"" optional:"" type:"existingfile" help:"Path of the crash report. Defaults to the most recent report."`
+	Endpoint string `required:"" env:"UP_REPORT_ENDPOINT" help:"URL to which the crash report is sent."`
+}
+
+// Run executes the send command.
+func (c *sendCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	s, err := crash.NewStore()
+	if err != nil {
+		return err
+	}
+	path := c.Path
+	if path == "" {
+		if path, err = s.Latest(); err != nil {
+			return err
+		}
+	}
+	r, err := s.Read(path)
+	if err != nil {
+		return err
+	}
+	if err := crash.NewHTTPSender(c.Endpoint).Send(ctx, r); err != nil {
+		return err
+	}
+	p.Printfln("Crash report %s sent. Thank you for helping improve up!", path)
+	return nil
+}
@@ -30,8 +30,8 @@ type createCmd struct {
 }
 
 // Run executes the create command.
-func (c *createCmd) Run(p pterm.TextPrinter, rc *repositories.Client, upCtx *upbound.Context) error {
-	if err := rc.CreateOrUpdate(context.Background(), upCtx.Account, c.Name); err != nil {
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter, rc *repositories.Client, upCtx *upbound.Context) error {
+	if err := rc.CreateOrUpdate(ctx, upCtx.Account, c.Name); err != nil {
 		return err
 	}
 	p.Printfln("%s/%s created", upCtx.Account, c.Name)
@@ -35,11 +35,18 @@ func (c *getCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error
 // getCmd gets a single repo.
 type getCmd struct {
 	Name string `arg:"" required:"" help:"Name of repo." predictor:"repos"`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{repos.Repository{}}
 }
 
 // Run executes the get command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, rc *repos.Client, upCtx *upbound.Context) error {
-	repo, err := rc.Get(context.Background(), upCtx.Account, c.Name)
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, rc *repos.Client, upCtx *upbound.Context) error {
+	repo, err := rc.Get(ctx, upCtx.Account, c.Name)
 	if err != nil {
 		return err
 	}
@@ -42,13 +42,20 @@ func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) erro
 }
 
 // listCmd lists repositories in an account on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	Output upterm.OutputFlags `embed:""`
+}
 
 var fieldNames = []string{"NAME", "TYPE", "PUBLIC", "UPDATED"}
 
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{repos.Repository{}}
+}
+
 // Run executes the list command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, rc *repositories.Client, upCtx *upbound.Context) error {
-	rList, err := rc.List(context.Background(), upCtx.Account, common.WithSize(maxItems))
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, rc *repositories.Client, upCtx *upbound.Context) error {
+	rList, err := rc.List(ctx, upCtx.Account, common.WithSize(maxItems))
 	if err != nil {
 		return err
 	}
@@ -28,8 +28,8 @@ import (
 
 // AfterApply constructs and binds a repositories client to any subcommands
 // that have Run() methods that receive it.
-func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
-	upCtx, err := upbound.NewFromFlags(c.Flags)
+func (c *Cmd) AfterApply(ctx context.Context, kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.DiscoverAccount(ctx))
 	if err != nil {
 		return err
 	}
@@ -44,6 +44,9 @@ func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
 
 func PredictRepos() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -58,7 +61,7 @@ func PredictRepos() complete.Predictor {
 			return nil
 		}
 
-		repos, err := rc.List(context.Background(), upCtx.Account, common.WithSize(maxItems))
+		repos, err := rc.List(ctx, upCtx.Account, common.WithSize(maxItems))
 		if err != nil {
 			return nil
 		}
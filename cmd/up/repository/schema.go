@@ -0,0 +1,54 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"time"
+
+	repos "github.com/upbound/up-sdk-go/service/repositories"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// repositoryV1 is version v1 of the machine output of a repository.
+type repositoryV1 struct {
+	Name           string     `json:"name"`
+	Type           string     `json:"type,omitempty"`
+	Public         bool       `json:"public"`
+	Official       bool       `json:"official"`
+	CurrentVersion string     `json:"currentVersion,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(repos.Repository{}, upterm.Schema{
+		Kind:    "Repository",
+		Version: "v1",
+		Convert: convertRepositoryV1,
+	})
+}
+
+func convertRepositoryV1(obj any) any {
+	r := obj.(repos.Repository)
+	out := repositoryV1{Name: r.Name, Public: r.Public, Official: r.Official, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+	if r.Type != nil {
+		out.Type = string(*r.Type)
+	}
+	if r.CurrentVersion != nil {
+		out.CurrentVersion = *r.CurrentVersion
+	}
+	return out
+}
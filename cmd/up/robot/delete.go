@@ -17,73 +17,121 @@ package robot
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/pterm/pterm"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
 	"github.com/upbound/up-sdk-go/service/organizations"
 	"github.com/upbound/up-sdk-go/service/robots"
 
 	"github.com/upbound/up/internal/input"
+	"github.com/upbound/up/internal/kube"
 	"github.com/upbound/up/internal/upbound"
 )
 
 const (
-	errMultipleRobotFmt = "found multiple robots with name %s in %s"
-	errFindRobotFmt     = "could not find robot %s in %s"
+	errMultipleRobotFmt      = "found multiple robots with name %s in %s"
+	errFindRobotFmt          = "could not find robot %s in %s"
+	errDependentsFmt         = "robot %s has %d dependents, use --force to delete it anyway"
+	errMultipleRobotIDFmt    = "found multiple robots with name %s in %s, use --id to select one"
+	errNameOrSelect          = "exactly one of a robot name, --id, or --interactive must be provided"
+	errFmtDeleteFailed       = "failed to delete %d of %d robots"
+	errFmtSelectedDependents = "%d of the selected robots have dependents, use --force to delete them anyway"
 )
 
 // BeforeApply sets default values for the delete command, before assignment and validation.
 func (c *deleteCmd) BeforeApply() error {
 	c.prompter = input.NewPrompter()
+	c.selector = input.NewSelector()
 	return nil
 }
 
-// AfterApply accepts user input by default to confirm the delete operation.
-func (c *deleteCmd) AfterApply(p pterm.TextPrinter, upCtx *upbound.Context) error {
-	if c.Force {
+// AfterApply constructs a Kubernetes client if a kubeconfig was supplied to
+// search for pull secrets.
+func (c *deleteCmd) AfterApply(upCtx *upbound.Context) error {
+	n := 0
+	for _, set := range []bool{c.Name != "", c.ID != uuid.Nil, c.Interactive} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New(errNameOrSelect)
+	}
+	if c.Kubeconfig == "" {
 		return nil
 	}
-
-	confirm, err := c.prompter.Prompt("Are you sure you want to delete this robot? [y/n]", false)
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
 	if err != nil {
 		return err
 	}
-
-	if input.InputYes(confirm) {
-		p.Printfln("Deleting robot %s/%s. This cannot be undone.", upCtx.Account, c.Name)
-		return nil
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
 	}
-
-	return fmt.Errorf("operation canceled")
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.kClient = client
+	return nil
 }
 
 // deleteCmd deletes a robot on Upbound.
 type deleteCmd struct {
 	prompter input.Prompter
+	selector input.Selector
+	kClient  kubernetes.Interface
 
-	Name string `arg:"" required:"" help:"Name of robot." predictor:"robots"`
+	Name        string    `arg:"" optional:"" help:"Name of robot. Required unless --id or --interactive is supplied." predictor:"robots"`
+	ID          uuid.UUID `name:"id" help:"ID of robot. Selects a robot that shares its name with others."`
+	Interactive bool      `help:"Select the robots to delete from a list of all robots in the organization."`
 
-	Force bool `help:"Force delete robot even if conflicts exist." default:"false"`
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig string `type:"existingfile" help:"Kubeconfig of a cluster to search for pull secrets that use the robot's tokens."`
+	DryRun     bool   `help:"Show the dependents of the robot without deleting it."`
+	Force      bool   `help:"Force delete robot even if conflicts or dependents exist." default:"false"`
+}
+
+// Help returns the help text for the delete command.
+func (c *deleteCmd) Help() string {
+	return `
+Before a robot is deleted its dependents are shown: the tokens of the robot
+and, if --kubeconfig is supplied, the image pull secrets in that cluster that
+authenticate with one of its tokens. Team memberships of the robot are not
+currently discoverable. A robot with dependents is only deleted if --force is
+supplied.
+
+With --interactive, the robots to delete are selected from a list and deleted
+after a single confirmation. Deletion continues if a robot cannot be deleted,
+and the robots that failed are listed at the end.`
 }
 
 // Run executes the delete command.
-func (c *deleteCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error { //nolint:gocyclo
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *deleteCmd) Run(ctx context.Context, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
+	if c.Interactive {
+		return c.deleteSelected(ctx, p, rc, upCtx.Account, rs)
+	}
+	ref := c.Name
+	if c.ID != uuid.Nil {
+		ref = c.ID.String()
+	}
 	if len(rs) == 0 {
-		return errors.Errorf(errFindRobotFmt, c.Name, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref, upCtx.Account)
 	}
 	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
 	// must guarantee that exactly one robot exists in the specified account
@@ -91,23 +139,156 @@ func (c *deleteCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 	// updated.
 	var id *uuid.UUID
 	for _, r := range rs {
-		if r.Name == c.Name {
+		if robotMatches(r, c.Name, c.ID) {
 			if id != nil && !c.Force {
-				return errors.Errorf(errMultipleRobotFmt, c.Name, upCtx.Account)
+				return errors.Errorf(errMultipleRobotIDFmt, c.Name, upCtx.Account)
 			}
 			// Pin range variable so that we can take address.
 			r := r
 			id = &r.ID
+			c.Name = r.Name
 		}
 	}
 
 	if id == nil {
-		return errors.Errorf(errFindRobotFmt, c.Name, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref, upCtx.Account)
 	}
 
-	if err := rc.Delete(context.Background(), *id); err != nil {
+	deps, err := c.dependents(ctx, rc, *id)
+	if err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		p.Printfln("Robot %s/%s has no known dependents.", upCtx.Account, c.Name)
+	} else {
+		p.Printfln("Robot %s/%s has the following dependents:", upCtx.Account, c.Name)
+		for _, d := range deps {
+			p.Printfln("  %s", d)
+		}
+	}
+	if c.DryRun {
+		return nil
+	}
+	if len(deps) > 0 && !c.Force {
+		return errors.Errorf(errDependentsFmt, c.Name, len(deps))
+	}
+	if !c.Force {
+		confirm, err := c.prompter.Prompt("Are you sure you want to delete this robot? [y/n]", false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return fmt.Errorf("operation canceled")
+		}
+		p.Printfln("Deleting robot %s/%s. This cannot be undone.", upCtx.Account, c.Name)
+	}
+
+	if err := rc.Delete(ctx, *id); err != nil {
 		return err
 	}
 	p.Printfln("%s/%s deleted", upCtx.Account, c.Name)
 	return nil
 }
+
+// deleteSelected deletes the robots that the user selects from the supplied
+// robots after a single confirmation.
+func (c *deleteCmd) deleteSelected(ctx context.Context, p pterm.TextPrinter, rc *robots.Client, account string, rs []organizations.Robot) error { //nolint:gocyclo
+	if len(rs) == 0 {
+		p.Printfln("No robots found in %s", account)
+		return nil
+	}
+	byOption := make(map[string]organizations.Robot, len(rs))
+	options := make([]string, 0, len(rs))
+	for _, r := range rs {
+		o := robotOption(r)
+		byOption[o] = r
+		options = append(options, o)
+	}
+	sort.Strings(options)
+	selected, err := c.selector.Select("Select robots to delete", options)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		p.Printfln("No robots selected.")
+		return nil
+	}
+
+	withDeps := 0
+	for _, o := range selected {
+		deps, err := c.dependents(ctx, rc, byOption[o].ID)
+		if err != nil {
+			return err
+		}
+		if len(deps) == 0 {
+			p.Printfln("Robot %s has no known dependents.", o)
+			continue
+		}
+		withDeps++
+		p.Printfln("Robot %s has the following dependents:", o)
+		for _, d := range deps {
+			p.Printfln("  %s", d)
+		}
+	}
+	if c.DryRun {
+		return nil
+	}
+	if withDeps > 0 && !c.Force {
+		return errors.Errorf(errFmtSelectedDependents, withDeps)
+	}
+	if !c.Force {
+		confirm, err := c.prompter.Prompt(fmt.Sprintf("Are you sure you want to delete %d robots? [y/n]", len(selected)), false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return fmt.Errorf("operation canceled")
+		}
+	}
+
+	failed := 0
+	for _, o := range selected {
+		if err := rc.Delete(ctx, byOption[o].ID); err != nil {
+			failed++
+			p.Printfln("Failed to delete %s: %s", o, err)
+			continue
+		}
+		p.Printfln("%s/%s deleted", account, byOption[o].Name)
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtDeleteFailed, failed, len(selected))
+	}
+	return nil
+}
+
+// robotOption returns the option by which a robot is presented for selection.
+// The ID is included because robot names are not unique.
+func robotOption(r organizations.Robot) string {
+	return fmt.Sprintf("%s (%s)", r.Name, r.ID)
+}
+
+// dependents returns descriptions of the tokens of the robot and of the pull
+// secrets that use them.
+func (c *deleteCmd) dependents(ctx context.Context, rc *robots.Client, id uuid.UUID) ([]string, error) {
+	ts, err := rc.ListTokens(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deps := make([]string, 0, len(ts.DataSet))
+	ids := make([]string, 0, len(ts.DataSet))
+	for _, t := range ts.DataSet {
+		deps = append(deps, fmt.Sprintf("token %s", t.AttributeSet["name"]))
+		ids = append(ids, t.ID.String())
+	}
+	if c.kClient == nil || len(ids) == 0 {
+		return deps, nil
+	}
+	secrets, err := kube.FindPullSecrets(ctx, c.kClient, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range secrets {
+		deps = append(deps, fmt.Sprintf("pull secret %s", s))
+	}
+	return deps, nil
+}
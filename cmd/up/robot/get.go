@@ -16,32 +16,85 @@ package robot
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
 	"github.com/pterm/pterm"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
 	"github.com/upbound/up-sdk-go/service/organizations"
+	"github.com/upbound/up-sdk-go/service/robots"
 
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 )
 
+const (
+	// relTeams is the relationship of a robot to the teams it is a member of.
+	relTeams = "teams"
+
+	notAvailable = "n/a"
+	noTeams      = "none"
+
+	errGetRobot       = "unable to get robot"
+	errListTokens     = "unable to list robot tokens"
+	errFmtRobotNoName = "no robot named %q"
+	errFmtRobotNoID   = "no robot with ID %s"
+	errGetNameOrID    = "exactly one of a robot name or --id must be provided"
+)
+
+// robotDetails is a robot along with its team memberships and the number of
+// its tokens.
+type robotDetails struct {
+	organizations.Robot
+
+	Teams      []string `json:"teams"`
+	TokenCount int      `json:"tokenCount"`
+
+	// hasTeams and hasTokens indicate which details were fetched.
+	hasTeams  bool
+	hasTokens bool
+}
+
 // AfterApply sets default values in command after assignment and validation.
 func (c *getCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
+	if (c.Name == "") == (c.ID == uuid.Nil) {
+		return errors.New(errGetNameOrID)
+	}
 	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
 	return nil
 }
 
 // getCmd gets a single robot in an account on Upbound.
 type getCmd struct {
-	Name string `arg:"" required:"" help:"Name of robot." predictor:"robots"`
+	Name string    `arg:"" optional:"" help:"Name of robot. Required unless --id is supplied." predictor:"robots"`
+	ID   uuid.UUID `name:"id" help:"ID of robot. Selects a robot that shares its name with others."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the get command.
+func (c *getCmd) Help() string {
+	return `
+The robot is shown along with the number of its tokens and the teams it is a
+member of. Teams are shown by their IDs, or as "none" if the robot is not a
+member of any team.`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{robotDetails{}}
 }
 
 // Run executes the get robot command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, ac *accounts.Client, oc *organizations.Client, upCtx *upbound.Context) error {
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error {
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
@@ -53,16 +106,100 @@ func (c *getCmd) Run(printer upterm.ObjectPrinter, ac *accounts.Client, oc *orga
 	// Therefore we get all robots and find the one the user requested
 	// The API doesn't guarantee uniqueness, but we just print the first
 	// one we find. If a user wants to list all of them, they can use
-	// the list command.
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	// the list command, and select one of them with --id.
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
 
 	for _, r := range rs {
-		if r.Name == c.Name {
-			return printer.Print(r, fieldNames, extractFields)
+		if robotMatches(r, c.Name, c.ID) {
+			d, err := details(ctx, rc, r, true, true)
+			if err != nil {
+				return err
+			}
+			fields, extract := detailsColumns(true, true)
+			return printer.Print(d, fields, extract)
 		}
 	}
-	return errors.New("no robot named \"" + c.Name + "\"")
+	if c.ID != uuid.Nil {
+		return errors.Errorf(errFmtRobotNoID, c.ID)
+	}
+	return errors.Errorf(errFmtRobotNoName, c.Name)
+}
+
+// details fetches the team memberships and tokens of the robot in parallel,
+// if requested.
+func details(ctx context.Context, rc *robots.Client, r organizations.Robot, teams, tokens bool) (robotDetails, error) {
+	d := robotDetails{Robot: r, hasTeams: teams, hasTokens: tokens}
+	g, gctx := errgroup.WithContext(ctx)
+	if teams {
+		g.Go(func() error {
+			res, err := rc.Get(gctx, r.ID)
+			if err != nil {
+				return errors.Wrap(err, errGetRobot)
+			}
+			d.Teams = relatedIDs(res.DataSet.RelationshipSet, relTeams)
+			return nil
+		})
+	}
+	if tokens {
+		g.Go(func() error {
+			ts, err := rc.ListTokens(gctx, r.ID)
+			if err != nil {
+				return errors.Wrap(err, errListTokens)
+			}
+			d.TokenCount = len(ts.DataSet)
+			return nil
+		})
+	}
+	return d, g.Wait()
+}
+
+// relatedIDs returns the sorted IDs of the resources of a to-many
+// relationship.
+func relatedIDs(rels map[string]any, name string) []string {
+	rel, _ := rels[name].(map[string]any)
+	data, _ := rel["data"].([]any)
+	ids := make([]string, 0, len(data))
+	for _, d := range data {
+		m, _ := d.(map[string]any)
+		if id, ok := m["id"]; ok && id != nil {
+			ids = append(ids, fmt.Sprint(id))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// detailsColumns returns the columns of a table of robots with the requested
+// details, and a function that extracts them.
+func detailsColumns(teams, tokens bool) ([]string, func(any) []string) {
+	fields := append([]string{}, fieldNames...)
+	if teams {
+		fields = append(fields, "TEAMS")
+	}
+	if tokens {
+		fields = append(fields, "TOKENS")
+	}
+	return fields, func(obj any) []string {
+		d := obj.(robotDetails)
+		row := extractFields(d.Robot)
+		if teams {
+			row = append(row, formatTeams(d.Teams))
+		}
+		if tokens {
+			row = append(row, strconv.Itoa(d.TokenCount))
+		}
+		return row
+	}
+}
+
+// formatTeams formats the IDs of the teams a robot is a member of. Teams are
+// shown by their IDs, as their names are not returned with the robot.
+func formatTeams(ids []string) string {
+	if len(ids) == 0 {
+		return noTeams
+	}
+	return strings.Join(ids, ",")
 }
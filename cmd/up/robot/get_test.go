@@ -0,0 +1,119 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+)
+
+func TestRelatedIDs(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rels   map[string]any
+		want   []string
+	}{
+		"NoRelationships": {
+			reason: "A robot without relationships is not a member of any teams.",
+			want:   []string{},
+		},
+		"NoTeams": {
+			reason: "A robot without a teams relationship is not a member of any teams.",
+			rels: map[string]any{
+				"owner": map[string]any{"data": map[string]any{"type": "organization", "id": "1"}},
+			},
+			want: []string{},
+		},
+		"Teams": {
+			reason: "The IDs of all teams should be returned in order.",
+			rels: map[string]any{
+				"teams": map[string]any{"data": []any{
+					map[string]any{"type": "team", "id": "b"},
+					map[string]any{"type": "team", "id": "a"},
+					map[string]any{"type": "team"},
+				}},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := relatedIDs(tc.rels, relTeams)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrelatedIDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDetailsColumns(t *testing.T) {
+	type want struct {
+		fields  []string
+		details []string
+	}
+	cases := map[string]struct {
+		reason string
+		teams  bool
+		tokens bool
+		d      robotDetails
+		want   want
+	}{
+		"TeamsAndTokens": {
+			reason: "The IDs of teams and the number of tokens should be shown.",
+			teams:  true,
+			tokens: true,
+			d:      robotDetails{Robot: organizations.Robot{Name: "ci"}, Teams: []string{"a", "b"}, TokenCount: 2},
+			want: want{
+				fields:  []string{"NAME", "ID", "DESCRIPTION", "CREATED", "TEAMS", "TOKENS"},
+				details: []string{"a,b", "2"},
+			},
+		},
+		"NoTeams": {
+			reason: "A robot that is not a member of any team should be shown as such.",
+			teams:  true,
+			d:      robotDetails{Robot: organizations.Robot{Name: "ci"}},
+			want: want{
+				fields:  []string{"NAME", "ID", "DESCRIPTION", "CREATED", "TEAMS"},
+				details: []string{"none"},
+			},
+		},
+		"TokensOnly": {
+			reason: "Only the requested details should be shown.",
+			tokens: true,
+			d:      robotDetails{Robot: organizations.Robot{Name: "ci"}, Teams: []string{"a"}, TokenCount: 1},
+			want: want{
+				fields:  []string{"NAME", "ID", "DESCRIPTION", "CREATED", "TOKENS"},
+				details: []string{"1"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fields, extract := detailsColumns(tc.teams, tc.tokens)
+			if diff := cmp.Diff(tc.want.fields, fields); diff != "" {
+				t.Errorf("\n%s\ndetailsColumns(...): -want fields, +got fields:\n%s", tc.reason, diff)
+			}
+			// The columns of the robot itself include its age, so only the
+			// details are compared.
+			row := extract(tc.d)
+			if diff := cmp.Diff(tc.want.details, row[len(fieldNames):]); diff != "" {
+				t.Errorf("\n%s\ndetailsColumns(...): -want details, +got details:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
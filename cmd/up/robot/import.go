@@ -0,0 +1,394 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/up-sdk-go/service/accounts"
+	"github.com/upbound/up-sdk-go/service/organizations"
+	"github.com/upbound/up-sdk-go/service/robots"
+	"github.com/upbound/up-sdk-go/service/tokens"
+
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	statusCreated = "created"
+	statusExists  = "exists"
+	statusFailed  = "failed"
+
+	errReadImportFile      = "unable to read import file"
+	errParseImportFile     = "unable to parse import file"
+	errMissingRobotName    = "robot at position %d has no name"
+	errMissingTokenName    = "token at position %d of robot %s has no name"
+	errOutputDirRequired   = "--output-dir is required to import robots with tokens"
+	errImportFailedFmt     = "failed to import %d of %d items"
+	errCSVHeader           = "CSV file must have a header row with a name column"
+	errUnknownCSVColumnFmt = "unknown CSV column %q"
+	errTeamsUnsupported    = "team memberships cannot be imported, add robots to teams in the Upbound console instead"
+)
+
+var importFieldNames = []string{"ROBOT", "TOKEN", "STATUS", "MESSAGE"}
+
+// robotList is the declarative format accepted by the import command.
+type robotList struct {
+	Robots []robotSpec `json:"robots"`
+}
+
+type robotSpec struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Tokens      []tokenSpec `json:"tokens,omitempty"`
+
+	// Teams are not supported, but are parsed so that they can be rejected
+	// with an explanation.
+	Teams []string `json:"teams,omitempty"`
+}
+
+type tokenSpec struct {
+	Name string `json:"name"`
+}
+
+// importResult is the outcome of importing a single robot or token.
+type importResult struct {
+	Robot   string `json:"robot"`
+	Token   string `json:"token,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *importCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
+	cfg, err := upCtx.BuildSDKConfig()
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(tokens.NewClient(cfg))
+	return nil
+}
+
+// importCmd creates robots and their tokens on Upbound from a file.
+type importCmd struct {
+	File      string `type:"existingfile" short:"f" required:"" help:"Path to a YAML or CSV file describing the robots to import."`
+	OutputDir string `type:"path" short:"o" help:"Directory to write JSON files containing the access ID and token of each created token."`
+}
+
+// Help returns the help text for the import command.
+func (c *importCmd) Help() string {
+	return `
+Robots are described in YAML:
+
+    robots:
+    - name: ci-1
+      description: CI runner
+      tokens:
+      - name: default
+
+or in CSV, with one robot or robot token per row:
+
+    name,description,token
+    ci-1,CI runner,default
+
+Robots and tokens that already exist are left unchanged, so an import can be
+safely re-run. Credentials for each created token are written to
+<output-dir>/<robot>-<token>.json.
+
+Team memberships cannot be imported, and files that set teams are rejected.
+Add the imported robots to teams in the Upbound console instead.`
+}
+
+// PrintedObjects returns the objects printed by the import command.
+func (c *importCmd) PrintedObjects() []any {
+	return []any{importResult{}}
+}
+
+// Run executes the import command.
+func (c *importCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	specs, err := readRobots(c.File)
+	if err != nil {
+		return err
+	}
+	if c.OutputDir == "" && hasTokens(specs) {
+		return errors.New(errOutputDirRequired)
+	}
+
+	a, err := ac.Get(ctx, upCtx.Account)
+	if err != nil {
+		return err
+	}
+	if a.Account.Type != accounts.AccountOrganization {
+		return errors.New(errUserAccount)
+	}
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
+	if err != nil {
+		return err
+	}
+	// The API does not guarantee robot name uniqueness, so all IDs are
+	// recorded to report duplicate names as failures rather than picking one.
+	existing := map[string][]uuid.UUID{}
+	for _, r := range rs {
+		existing[r.Name] = append(existing[r.Name], r.ID)
+	}
+
+	results := []importResult{}
+	failed := 0
+	fail := func(res importResult, err error) {
+		res.Status, res.Message = statusFailed, err.Error()
+		results = append(results, res)
+		failed++
+	}
+	for _, s := range specs {
+		res := importResult{Robot: s.Name, Status: statusExists}
+		var id uuid.UUID
+		switch ids := existing[s.Name]; len(ids) {
+		case 0:
+			r, err := rc.Create(ctx, &robots.RobotCreateParameters{
+				Attributes: robots.RobotAttributes{
+					Name:        s.Name,
+					Description: robotDescription(s.Description),
+				},
+				Relationships: robots.RobotRelationships{
+					Owner: robots.RobotOwner{
+						Data: robots.RobotOwnerData{
+							Type: robots.RobotOwnerOrganization,
+							ID:   strconv.FormatUint(uint64(a.Organization.ID), 10),
+						},
+					},
+				},
+			})
+			if err != nil {
+				fail(res, err)
+				continue
+			}
+			id, res.Status = r.ID, statusCreated
+		case 1:
+			id = ids[0]
+		default:
+			fail(res, errors.Errorf(errMultipleRobotFmt, s.Name, upCtx.Account))
+			continue
+		}
+		results = append(results, res)
+
+		if len(s.Tokens) == 0 {
+			continue
+		}
+		ts, err := rc.ListTokens(ctx, id)
+		if err != nil {
+			for _, t := range s.Tokens {
+				fail(importResult{Robot: s.Name, Token: t.Name}, err)
+			}
+			continue
+		}
+		tokenNames := map[string]bool{}
+		for _, t := range ts.DataSet {
+			tokenNames[fmt.Sprint(t.AttributeSet["name"])] = true
+		}
+		for _, t := range s.Tokens {
+			tres := importResult{Robot: s.Name, Token: t.Name, Status: statusExists}
+			if tokenNames[t.Name] {
+				results = append(results, tres)
+				continue
+			}
+			if err := c.createToken(ctx, tc, id, s.Name, t.Name); err != nil {
+				fail(tres, err)
+				continue
+			}
+			tres.Status = statusCreated
+			results = append(results, tres)
+		}
+	}
+
+	if err := printer.Print(results, importFieldNames, extractImportFields); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.Errorf(errImportFailedFmt, failed, len(results))
+	}
+	return nil
+}
+
+// createToken creates a token for the robot and writes its credentials to the
+// output directory.
+func (c *importCmd) createToken(ctx context.Context, tc *tokens.Client, id uuid.UUID, robot, name string) error {
+	res, err := tc.Create(ctx, &tokens.TokenCreateParameters{
+		Attributes: tokens.TokenAttributes{
+			Name: name,
+		},
+		Relationships: tokens.TokenRelationships{
+			Owner: tokens.TokenOwner{
+				Data: tokens.TokenOwnerData{
+					Type: tokens.TokenOwnerRobot,
+					ID:   id.String(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.OutputDir, 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(c.OutputDir, fmt.Sprintf("%s-%s.json", robot, name)), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck,gosec
+	return json.NewEncoder(f).Encode(&upbound.TokenFile{
+		AccessID: res.ID.String(),
+		Token:    fmt.Sprint(res.DataSet.Meta["jwt"]),
+	})
+}
+
+func extractImportFields(obj any) []string {
+	r := obj.(importResult)
+	return []string{r.Robot, r.Token, r.Status, r.Message}
+}
+
+// robotDescription defaults an empty description to a single space, as a
+// description is required by the API.
+func robotDescription(d string) string {
+	if d == "" {
+		return " "
+	}
+	return d
+}
+
+func hasTokens(specs []robotSpec) bool {
+	for _, s := range specs {
+		if len(s.Tokens) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// readRobots reads robot specs from a YAML or CSV file. The format is
+// determined by the file extension.
+func readRobots(path string) ([]robotSpec, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadImportFile)
+	}
+	defer f.Close() //nolint:errcheck,gosec
+
+	var specs []robotSpec
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		specs, err = parseRobotsCSV(f)
+	} else {
+		specs, err = parseRobotsYAML(f)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errParseImportFile)
+	}
+	return specs, validateRobots(specs)
+}
+
+func parseRobotsYAML(r io.Reader) ([]robotSpec, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	l := robotList{}
+	if err := yaml.UnmarshalStrict(b, &l); err != nil {
+		return nil, err
+	}
+	return l.Robots, nil
+}
+
+// parseRobotsCSV parses robots from CSV with a header row. Rows with the same
+// robot name are merged, so that a robot may be listed once per token.
+func parseRobotsCSV(r io.Reader) ([]robotSpec, error) { //nolint:gocyclo
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New(errCSVHeader)
+	}
+	cols := map[string]int{}
+	for i, h := range records[0] {
+		h = strings.ToLower(strings.TrimSpace(h))
+		switch h {
+		case "name", "description", "token":
+			cols[h] = i
+		case "team", "teams":
+			return nil, errors.New(errTeamsUnsupported)
+		default:
+			return nil, errors.Errorf(errUnknownCSVColumnFmt, h)
+		}
+	}
+	if _, ok := cols["name"]; !ok {
+		return nil, errors.New(errCSVHeader)
+	}
+	field := func(rec []string, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	specs := []robotSpec{}
+	index := map[string]int{}
+	for _, rec := range records[1:] {
+		name := field(rec, "name")
+		i, ok := index[name]
+		if !ok {
+			i = len(specs)
+			index[name] = i
+			specs = append(specs, robotSpec{Name: name})
+		}
+		if d := field(rec, "description"); d != "" {
+			specs[i].Description = d
+		}
+		if t := field(rec, "token"); t != "" {
+			specs[i].Tokens = append(specs[i].Tokens, tokenSpec{Name: t})
+		}
+	}
+	return specs, nil
+}
+
+func validateRobots(specs []robotSpec) error {
+	for i, s := range specs {
+		if s.Name == "" {
+			return errors.Errorf(errMissingRobotName, i+1)
+		}
+		if len(s.Teams) > 0 {
+			return errors.New(errTeamsUnsupported)
+		}
+		for j, t := range s.Tokens {
+			if t.Name == "" {
+				return errors.Errorf(errMissingTokenName, j+1, s.Name)
+			}
+		}
+	}
+	return nil
+}
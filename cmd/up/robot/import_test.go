@@ -0,0 +1,122 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRobotsCSV(t *testing.T) {
+	type want struct {
+		specs []robotSpec
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		csv    string
+		want   want
+	}{
+		"Empty": {
+			reason: "A file without a header row should return an error.",
+			csv:    "",
+			want: want{
+				err: errors.New(errCSVHeader),
+			},
+		},
+		"MissingName": {
+			reason: "A header row without a name column should return an error.",
+			csv:    "description,token\n",
+			want: want{
+				err: errors.New(errCSVHeader),
+			},
+		},
+		"UnknownColumn": {
+			reason: "A header row with an unknown column should return an error.",
+			csv:    "name,owner\n",
+			want: want{
+				err: errors.Errorf(errUnknownCSVColumnFmt, "owner"),
+			},
+		},
+		"Teams": {
+			reason: "A header row with a teams column should return an error, as team memberships cannot be imported.",
+			csv:    "name,teams\n",
+			want: want{
+				err: errors.New(errTeamsUnsupported),
+			},
+		},
+		"MergeRows": {
+			reason: "Rows for the same robot should be merged into a single robot with multiple tokens.",
+			csv:    "name,description,token\nci-1,CI runner,default\nci-1,,backup\nci-2,,\n",
+			want: want{
+				specs: []robotSpec{
+					{Name: "ci-1", Description: "CI runner", Tokens: []tokenSpec{{Name: "default"}, {Name: "backup"}}},
+					{Name: "ci-2"},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			specs, err := parseRobotsCSV(strings.NewReader(tc.csv))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseRobotsCSV(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.specs, specs); diff != "" {
+				t.Errorf("\n%s\nparseRobotsCSV(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateRobots(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		specs  []robotSpec
+		want   error
+	}{
+		"Valid": {
+			reason: "Robots and tokens with names should be valid.",
+			specs:  []robotSpec{{Name: "ci-1", Tokens: []tokenSpec{{Name: "default"}}}},
+		},
+		"MissingRobotName": {
+			reason: "A robot without a name should be invalid.",
+			specs:  []robotSpec{{Name: "ci-1"}, {}},
+			want:   errors.Errorf(errMissingRobotName, 2),
+		},
+		"MissingTokenName": {
+			reason: "A token without a name should be invalid.",
+			specs:  []robotSpec{{Name: "ci-1", Tokens: []tokenSpec{{}}}},
+			want:   errors.Errorf(errMissingTokenName, 1, "ci-1"),
+		},
+		"Teams": {
+			reason: "A robot with teams should be invalid, as team memberships cannot be imported.",
+			specs:  []robotSpec{{Name: "ci-1", Teams: []string{"platform"}}},
+			want:   errors.New(errTeamsUnsupported),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateRobots(tc.specs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateRobots(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -21,15 +21,21 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/util/duration"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
 	"github.com/upbound/up-sdk-go/service/organizations"
+	"github.com/upbound/up-sdk-go/service/robots"
 
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 )
 
+// maxConcurrentDetails is the maximum number of robots whose details are
+// fetched at the same time.
+const maxConcurrentDetails = 8
+
 var fieldNames = []string{"NAME", "ID", "DESCRIPTION", "CREATED"}
 
 // AfterApply sets default values in command after assignment and validation.
@@ -39,26 +45,103 @@ func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) erro
 }
 
 // listCmd creates a robot on Upbound.
-type listCmd struct{}
+type listCmd struct {
+	Output upterm.OutputFlags `embed:""`
+
+	Watch         bool          `short:"w" help:"Watch for changes, printing the list again or emitting a change event for each robot with JSON and YAML output."`
+	WatchInterval time.Duration `default:"5s" help:"Interval at which robots are polled when watching."`
+
+	ShowTokens bool `help:"Show the number of tokens of each robot."`
+	ShowTeams  bool `help:"Show the IDs of the teams each robot is a member of."`
+}
+
+// Unbounded returns true if the list command runs until interrupted.
+func (c *listCmd) Unbounded() bool {
+	return c.Watch
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{
+		organizations.Robot{},
+		robotDetails{},
+	}
+}
 
 // Run executes the list robots command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, upCtx *upbound.Context) error {
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error {
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	list := func(ctx context.Context) (any, error) {
+		return c.list(ctx, oc, rc, a.Organization.ID)
+	}
+	fields, extract := c.columns()
+	if c.Watch {
+		return printer.Watch(ctx, c.WatchInterval, list, robotKey, fields, extract)
+	}
+	rs, err := list(ctx)
 	if err != nil {
 		return err
 	}
-	if len(rs) == 0 {
+	if empty(rs) {
 		p.Printfln("No robots found in %s", upCtx.Account)
 		return nil
 	}
-	return printer.Print(rs, fieldNames, extractFields)
+	return printer.Print(rs, fields, extract)
+}
+
+// list lists the robots of the organization, along with their details if any
+// are requested.
+func (c *listCmd) list(ctx context.Context, oc *organizations.Client, rc *robots.Client, orgID uint) (any, error) {
+	rs, err := oc.ListRobots(ctx, orgID)
+	if err != nil || (!c.ShowTeams && !c.ShowTokens) {
+		return rs, err
+	}
+	ds := make([]robotDetails, len(rs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentDetails)
+	for i := range rs {
+		i := i
+		g.Go(func() error {
+			d, err := details(gctx, rc, rs[i], c.ShowTeams, c.ShowTokens)
+			ds[i] = d
+			return err
+		})
+	}
+	return ds, g.Wait()
+}
+
+// columns returns the columns of the table of robots and a function that
+// extracts them.
+func (c *listCmd) columns() ([]string, func(any) []string) {
+	if !c.ShowTeams && !c.ShowTokens {
+		return fieldNames, extractFields
+	}
+	return detailsColumns(c.ShowTeams, c.ShowTokens)
+}
+
+// empty indicates whether the list of robots is empty.
+func empty(rs any) bool {
+	switch l := rs.(type) {
+	case []organizations.Robot:
+		return len(l) == 0
+	case []robotDetails:
+		return len(l) == 0
+	}
+	return false
+}
+
+// robotKey identifies a robot while watching.
+func robotKey(obj any) string {
+	if d, ok := obj.(robotDetails); ok {
+		return d.ID.String()
+	}
+	return obj.(organizations.Robot).ID.String()
 }
 
 func extractFields(obj any) []string {
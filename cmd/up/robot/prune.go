@@ -0,0 +1,293 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/upbound/up-sdk-go/service/accounts"
+	"github.com/upbound/up-sdk-go/service/common"
+	"github.com/upbound/up-sdk-go/service/organizations"
+	"github.com/upbound/up-sdk-go/service/robots"
+
+	"github.com/upbound/up/cmd/up/robot/token"
+	"github.com/upbound/up/internal/input"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// Outcomes of pruning a robot.
+const (
+	pruneWouldDelete = "WouldDelete"
+	pruneDeleted     = "Deleted"
+	pruneFailed      = "Failed"
+)
+
+const (
+	errPruneCanceled = "prune canceled"
+	errWriteReport   = "unable to write prune report"
+)
+
+var pruneFieldNames = []string{"NAME", "ID", "CREATED", "TOKENS", "UNUSED FOR", "RESULT"}
+
+// A pruneEntry is a robot that is pruned, along with the outcome of deleting
+// it.
+type pruneEntry struct {
+	Name      string    `json:"name"`
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Tokens    int       `json:"tokens"`
+	// UnusedSince is the last time any token of the robot was used, or when
+	// the robot was created if it has no tokens. It is unset if the use of
+	// none of the tokens is reported.
+	UnusedSince *time.Time `json:"unusedSince,omitempty"`
+	Result      string     `json:"result"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// BeforeApply sets default values for the prune command, before assignment
+// and validation.
+func (c *pruneCmd) BeforeApply() error {
+	c.prompter = input.NewPrompter()
+	return nil
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *pruneCmd) AfterApply(kongCtx *kong.Context) error {
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// pruneCmd deletes robots whose tokens have not been used recently.
+type pruneCmd struct {
+	prompter input.Prompter
+
+	UnusedFor  token.Age `required:"" help:"Delete robots none of whose tokens have been used for this long, e.g. 60d."`
+	DryRun     bool      `help:"Show the robots that would be deleted without deleting them."`
+	Yes        bool      `short:"y" help:"Delete the robots without confirmation."`
+	ReportFile string    `type:"path" placeholder:"FILE" help:"Write a report of the pruned robots to this file, as CSV if it ends in .csv and as JSON otherwise."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the prune command.
+func (c *pruneCmd) Help() string {
+	return `
+Robots are pruned if none of their tokens have been used for at least
+--unused-for. Tokens that have never been used, or whose use is not reported,
+are judged by their creation time, and robots without tokens by their own
+creation time. The robots are listed and deleted after a single confirmation.
+Deletion continues if a robot cannot be deleted, and the outcome for each robot
+is written to the file given by --report-file.`
+}
+
+// PrintedObjects returns the objects printed by the prune command.
+func (c *pruneCmd) PrintedObjects() []any {
+	return []any{pruneEntry{}}
+}
+
+// Run executes the prune command.
+func (c *pruneCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
+	if err != nil {
+		return err
+	}
+	if a.Account.Type != accounts.AccountOrganization {
+		return errors.New(errUserAccount)
+	}
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
+	if err != nil {
+		return err
+	}
+	tokens, err := listTokens(ctx, rc, rs)
+	if err != nil {
+		return err
+	}
+	entries := pruneCandidates(rs, tokens, time.Duration(c.UnusedFor), time.Now())
+	if len(entries) == 0 {
+		p.Printfln("No robots in %s are unused for %s", upCtx.Account, c.age())
+		return c.writeReport(entries)
+	}
+	if c.DryRun {
+		if err := printer.Print(entries, pruneFieldNames, extractPruneFields); err != nil {
+			return err
+		}
+		return c.writeReport(entries)
+	}
+	if !c.Yes {
+		if err := printer.Print(entries, pruneFieldNames, extractPruneFields); err != nil {
+			return err
+		}
+		confirm, err := c.prompter.Prompt(fmt.Sprintf("Are you sure you want to delete %d robots? [y/n]", len(entries)), false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return errors.New(errPruneCanceled)
+		}
+	}
+
+	failed := 0
+	for i := range entries {
+		if err := rc.Delete(ctx, entries[i].ID); err != nil {
+			failed++
+			entries[i].Result, entries[i].Error = pruneFailed, err.Error()
+			p.Printfln("Failed to delete %s/%s: %s", upCtx.Account, entries[i].Name, err)
+			continue
+		}
+		entries[i].Result = pruneDeleted
+		p.Printfln("%s/%s deleted", upCtx.Account, entries[i].Name)
+	}
+	if err := c.writeReport(entries); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtDeleteFailed, failed, len(entries))
+	}
+	return nil
+}
+
+// age returns the unused duration in the form it is usually supplied in.
+func (c *pruneCmd) age() string {
+	d := time.Duration(c.UnusedFor)
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// listTokens lists the tokens of the robots concurrently.
+func listTokens(ctx context.Context, rc *robots.Client, rs []organizations.Robot) (map[uuid.UUID][]common.DataSet, error) {
+	ts := make([][]common.DataSet, len(rs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentDetails)
+	for i := range rs {
+		i := i
+		g.Go(func() error {
+			res, err := rc.ListTokens(gctx, rs[i].ID)
+			if err != nil {
+				return errors.Wrap(err, errListTokens)
+			}
+			ts[i] = res.DataSet
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	tokens := make(map[uuid.UUID][]common.DataSet, len(rs))
+	for i, r := range rs {
+		tokens[r.ID] = ts[i]
+	}
+	return tokens, nil
+}
+
+// pruneCandidates returns the robots none of whose tokens have been used for
+// at least the supplied duration before now, sorted by name.
+func pruneCandidates(rs []organizations.Robot, tokens map[uuid.UUID][]common.DataSet, d time.Duration, now time.Time) []pruneEntry {
+	entries := []pruneEntry{}
+	for _, r := range rs {
+		since := r.CreatedAt
+		if ts := tokens[r.ID]; len(ts) > 0 {
+			since = time.Time{}
+			for _, t := range ts {
+				// A token whose use is not reported cannot be shown to be in
+				// use.
+				if s, ok := token.UnusedSince(t); ok && s.After(since) {
+					since = s
+				}
+			}
+		}
+		if now.Sub(since) < d {
+			continue
+		}
+		e := pruneEntry{
+			Name:      r.Name,
+			ID:        r.ID,
+			CreatedAt: r.CreatedAt,
+			Tokens:    len(tokens[r.ID]),
+			Result:    pruneWouldDelete,
+		}
+		if !since.IsZero() {
+			since := since
+			e.UnusedSince = &since
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// writeReport writes the entries to the report file, if one was requested.
+func (c *pruneCmd) writeReport(entries []pruneEntry) error {
+	if c.ReportFile == "" {
+		return nil
+	}
+	var b []byte
+	if strings.EqualFold(filepath.Ext(c.ReportFile), ".csv") {
+		b = pruneCSV(entries)
+	} else {
+		var err error
+		if b, err = json.MarshalIndent(entries, "", "  "); err != nil {
+			return errors.Wrap(err, errWriteReport)
+		}
+		b = append(b, '\n')
+	}
+	return errors.Wrap(os.WriteFile(c.ReportFile, b, 0600), errWriteReport)
+}
+
+// pruneCSV returns the entries as CSV with a header row.
+func pruneCSV(entries []pruneEntry) []byte {
+	sb := &strings.Builder{}
+	w := csv.NewWriter(sb)
+	_ = w.Write([]string{"name", "id", "createdAt", "tokens", "unusedSince", "result", "error"})
+	for _, e := range entries {
+		_ = w.Write([]string{e.Name, e.ID.String(), e.CreatedAt.UTC().Format(time.RFC3339), strconv.Itoa(e.Tokens), timeOrEmpty(e.UnusedSince), e.Result, e.Error})
+	}
+	w.Flush()
+	return []byte(sb.String())
+}
+
+func timeOrEmpty(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func extractPruneFields(obj any) []string {
+	e := obj.(pruneEntry)
+	unused := notAvailable
+	if e.UnusedSince != nil {
+		unused = duration.HumanDuration(time.Since(*e.UnusedSince))
+	}
+	return []string{e.Name, e.ID.String(), duration.HumanDuration(time.Since(e.CreatedAt)), strconv.Itoa(e.Tokens), unused, e.Result}
+}
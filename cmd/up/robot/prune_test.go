@@ -0,0 +1,123 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/common"
+	"github.com/upbound/up-sdk-go/service/organizations"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tok := func(meta map[string]any) common.DataSet {
+		return common.DataSet{ID: uuid.New(), Meta: meta}
+	}
+	robot := func(name string, id uuid.UUID, c time.Time) organizations.Robot {
+		return organizations.Robot{Name: name, ID: id, CreatedAt: c}
+	}
+	unusedSince := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stale, active, fresh, empty, unknown := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	rs := []organizations.Robot{
+		robot("stale", stale, created),
+		robot("active", active, created),
+		robot("fresh", fresh, now.Add(-24*time.Hour)),
+		robot("empty", empty, created),
+		robot("unknown", unknown, created),
+	}
+	tokens := map[uuid.UUID][]common.DataSet{
+		stale: {
+			tok(map[string]any{"createdAt": "2023-01-01T00:00:00Z", "lastUsedAt": "2023-06-01T00:00:00Z"}),
+			tok(map[string]any{"createdAt": "2023-02-01T00:00:00Z"}),
+		},
+		active: {
+			tok(map[string]any{"createdAt": "2023-01-01T00:00:00Z", "lastUsedAt": "2023-06-01T00:00:00Z"}),
+			tok(map[string]any{"createdAt": "2023-01-01T00:00:00Z", "lastUsedAt": "2023-09-30T00:00:00Z"}),
+		},
+		unknown: {
+			tok(nil),
+		},
+	}
+	want := []pruneEntry{
+		{Name: "empty", ID: empty, CreatedAt: created, UnusedSince: &created, Result: pruneWouldDelete},
+		{Name: "stale", ID: stale, CreatedAt: created, Tokens: 2, UnusedSince: &unusedSince, Result: pruneWouldDelete},
+		{Name: "unknown", ID: unknown, CreatedAt: created, Tokens: 1, Result: pruneWouldDelete},
+	}
+	got := pruneCandidates(rs, tokens, 60*24*time.Hour, now)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nRobots whose tokens were not used for the duration should be pruned.\npruneCandidates(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	id := uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b")
+	entries := []pruneEntry{{
+		Name:      "ci",
+		ID:        id,
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Result:    pruneFailed,
+		Error:     "boom",
+	}}
+
+	cases := map[string]struct {
+		reason string
+		file   string
+		want   string
+	}{
+		"CSV": {
+			reason: "A report file ending in .csv should be written as CSV.",
+			file:   "report.csv",
+			want: "name,id,createdAt,tokens,unusedSince,result,error\n" +
+				"ci,0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b,2023-01-01T00:00:00Z,0,,Failed,boom\n",
+		},
+		"JSON": {
+			reason: "Other report files should be written as JSON.",
+			file:   "report.json",
+			want: `[
+  {
+    "name": "ci",
+    "id": "0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b",
+    "createdAt": "2023-01-01T00:00:00Z",
+    "tokens": 0,
+    "result": "Failed",
+    "error": "boom"
+  }
+]
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			c := &pruneCmd{ReportFile: path}
+			if err := c.writeReport(entries); err != nil {
+				t.Fatalf("\n%s\nwriteReport(...): unexpected error: %v", tc.reason, err)
+			}
+			got, _ := os.ReadFile(path)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nwriteReport(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
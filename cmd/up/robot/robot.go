@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/alecthomas/kong"
+	"github.com/google/uuid"
 	"github.com/posener/complete"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
@@ -34,8 +35,8 @@ const (
 
 // AfterApply constructs and binds a robots client to any subcommands
 // that have Run() methods that receive it.
-func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
-	upCtx, err := upbound.NewFromFlags(c.Flags)
+func (c *Cmd) AfterApply(ctx context.Context, kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.DiscoverAccount(ctx))
 	if err != nil {
 		return err
 	}
@@ -52,6 +53,9 @@ func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
 
 func PredictRobots() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) (prediction []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), upbound.PredictTimeout)
+		defer cancel()
+
 		upCtx, err := upbound.NewFromFlags(upbound.Flags{})
 		if err != nil {
 			return nil
@@ -71,14 +75,14 @@ func PredictRobots() complete.Predictor {
 			return nil
 		}
 
-		account, err := ac.Get(context.Background(), upCtx.Account)
+		account, err := ac.Get(ctx, upCtx.Account)
 		if err != nil {
 			return nil
 		}
 		if account.Account.Type != accounts.AccountOrganization {
 			return nil
 		}
-		rs, err := oc.ListRobots(context.Background(), account.Organization.ID)
+		rs, err := oc.ListRobots(ctx, account.Organization.ID)
 		if err != nil {
 			return nil
 		}
@@ -93,12 +97,24 @@ func PredictRobots() complete.Predictor {
 	})
 }
 
+// robotMatches reports whether a robot is the one identified by the supplied
+// ID or, if no ID is supplied, by the supplied name. Robot names are not
+// unique, so an ID is needed to identify a robot that shares its name.
+func robotMatches(r organizations.Robot, name string, id uuid.UUID) bool {
+	if id != uuid.Nil {
+		return r.ID == id
+	}
+	return r.Name == name
+}
+
 // Cmd contains commands for interacting with robots.
 type Cmd struct {
 	Create createCmd `cmd:"" help:"Create a robot."`
 	Delete deleteCmd `cmd:"" help:"Delete a robot."`
 	List   listCmd   `cmd:"" help:"List robots for the account."`
 	Get    getCmd    `cmd:"" help:"Get a robot for the account."`
+	Import importCmd `cmd:"" help:"Create robots and robot tokens from a YAML or CSV file."`
+	Prune  pruneCmd  `cmd:"" help:"Delete robots whose tokens have not been used recently."`
 	Token  token.Cmd `cmd:"" help:"Interact with robot tokens."`
 
 	// Common Upbound API configuration
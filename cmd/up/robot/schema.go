@@ -0,0 +1,103 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// robotV1 is version v1 of the machine output of a robot.
+type robotV1 struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Teams and TokenCount are only set if they were requested.
+	Teams      []string `json:"teams,omitempty"`
+	TokenCount *int     `json:"tokenCount,omitempty"`
+}
+
+// importResultV1 is version v1 of the machine output of the import of a robot
+// or token.
+type importResultV1 struct {
+	Robot   string `json:"robot"`
+	Token   string `json:"token,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// pruneResultV1 is version v1 of the machine output of a robot considered for
+// pruning.
+type pruneResultV1 struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Tokens      int        `json:"tokens"`
+	UnusedSince *time.Time `json:"unusedSince,omitempty"`
+	Result      string     `json:"result"`
+	Error       string     `json:"error,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(organizations.Robot{}, upterm.Schema{
+		Kind:    "Robot",
+		Version: "v1",
+		Convert: convertRobotV1,
+	})
+	upterm.RegisterSchema(robotDetails{}, upterm.Schema{
+		Kind:    "Robot",
+		Version: "v1",
+		Convert: convertRobotDetailsV1,
+	})
+	upterm.RegisterSchema(importResult{}, upterm.Schema{
+		Kind:    "RobotImportResult",
+		Version: "v1",
+		Convert: func(obj any) any {
+			r := obj.(importResult)
+			return importResultV1{Robot: r.Robot, Token: r.Token, Status: r.Status, Message: r.Message}
+		},
+	})
+	upterm.RegisterSchema(pruneEntry{}, upterm.Schema{
+		Kind:    "RobotPruneResult",
+		Version: "v1",
+		Convert: func(obj any) any {
+			e := obj.(pruneEntry)
+			return pruneResultV1{ID: e.ID, Name: e.Name, CreatedAt: e.CreatedAt.UTC(), Tokens: e.Tokens, UnusedSince: e.UnusedSince, Result: e.Result, Error: e.Error}
+		},
+	})
+}
+
+func convertRobotV1(obj any) any {
+	r := obj.(organizations.Robot)
+	return robotV1{ID: r.ID, Name: r.Name, Description: r.Description, CreatedAt: r.CreatedAt.UTC()}
+}
+
+func convertRobotDetailsV1(obj any) any {
+	d := obj.(robotDetails)
+	r := convertRobotV1(d.Robot).(robotV1)
+	if d.hasTeams {
+		r.Teams = d.Teams
+	}
+	if d.hasTokens {
+		r.TokenCount = &d.TokenCount
+	}
+	return r
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package robot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+func TestRobotSchemaV1(t *testing.T) {
+	rs := []organizations.Robot{{
+		ID:          uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+		Name:        "ci",
+		Description: "Robot used by CI",
+		TeamIDs:     []uuid.UUID{uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")},
+		CreatedAt:   time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+	}}
+	got, err := upterm.MachineJSON(rs)
+	if err != nil {
+		t.Fatalf("MachineJSON(...): %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "robotlist-v1.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+		t.Errorf("MachineJSON(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRobotDetailsSchemaV1(t *testing.T) {
+	d := robotDetails{
+		Robot: organizations.Robot{
+			ID:        uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+			Name:      "ci",
+			CreatedAt: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		Teams:     []string{"6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"},
+		hasTokens: true,
+	}
+	got, err := upterm.MachineJSON(d)
+	if err != nil {
+		t.Fatalf("MachineJSON(...): %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "robotdetails-v1.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+		t.Errorf("MachineJSON(...): -want, +got:\n%s", diff)
+	}
+}
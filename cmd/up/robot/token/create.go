@@ -20,42 +20,74 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/pterm/pterm"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
+	"github.com/upbound/up-sdk-go/service/common"
 	"github.com/upbound/up-sdk-go/service/organizations"
 	"github.com/upbound/up-sdk-go/service/robots"
 	"github.com/upbound/up-sdk-go/service/tokens"
 
+	"github.com/upbound/up/internal/kube"
 	"github.com/upbound/up/internal/upbound"
 )
 
+// AfterApply validates the create command after assignment.
+func (c *createCmd) AfterApply() error {
+	shiftArgs(&c.RobotName, &c.TokenName, c.RobotID)
+	if c.TokenName == "" {
+		return errors.New(errTokenName)
+	}
+	if err := validateRobot(c.RobotName, c.RobotID); err != nil {
+		return err
+	}
+	if c.Output == "" && c.Manifest == "" {
+		return errors.New(errOutputOrManifest)
+	}
+	if c.Container != "" && c.Deployment == "" {
+		return errors.New(errContainerDeployment)
+	}
+	return nil
+}
+
 // createCmd creates a robot on Upbound.
 type createCmd struct {
-	RobotName string `arg:"" required:"" help:"Name of robot."`
-	TokenName string `arg:"" required:"" help:"Name of token."`
+	RobotName string    `arg:"" optional:"" help:"Name of robot. Omitted if --robot-id is supplied."`
+	TokenName string    `arg:"" optional:"" help:"Name of token."`
+	RobotID   uuid.UUID `help:"ID of robot. Selects a robot that shares its name with others."`
+
+	Output string `type:"path" short:"o" help:"Path to write JSON file containing access ID and token."`
 
-	Output string `type:"path" short:"o" required:"" help:"Path to write JSON file containing access ID and token."`
+	Manifest       string `help:"Path to write Kubernetes manifests that supply the token to an in-cluster agent. '-' to write to stdout."`
+	Namespace      string `short:"n" default:"default" help:"Namespace of the objects in the manifests."`
+	SecretName     string `default:"up-robot-token" help:"Name of the Secret holding the token in the manifests."`
+	ServiceAccount string `help:"Name of a ServiceAccount for the agent to include in the manifests."`
+	Deployment     string `help:"Name of the Deployment of the agent to expose the token to as environment variables."`
+	Container      string `help:"Name of the container of the Deployment that reads the token. Defaults to the name of the Deployment."`
+
+	IfNotExists bool `xor:"existing" help:"Succeed without creating a token if the robot already has a token with this name. The existing token is described, but its secret cannot be retrieved."`
+	Replace     bool `xor:"existing" help:"Replace existing tokens of the robot with this name. The new token is created before the existing ones are deleted."`
 }
 
 // Run executes the create command.
-func (c *createCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
 	if len(rs) == 0 {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
 	// must guarantee that exactly one robot exists in the specified account
@@ -64,18 +96,38 @@ func (c *createCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 	var id uuid.UUID
 	found := false
 	for _, r := range rs {
-		if r.Name == c.RobotName {
+		if robotMatches(r, c.RobotName, c.RobotID) {
 			if found {
 				return errors.Errorf(errMultipleRobotFmt, c.RobotName, upCtx.Account)
 			}
 			id = r.ID
 			found = true
+			c.RobotName = r.Name
 		}
 	}
 	if !found {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
-	res, err := tc.Create(context.Background(), &tokens.TokenCreateParameters{
+	var existing []common.DataSet
+	if c.IfNotExists || c.Replace {
+		ts, err := rc.ListTokens(ctx, id)
+		if err != nil {
+			return err
+		}
+		existing = tokensNamed(ts.DataSet, c.TokenName)
+	}
+	if c.IfNotExists && len(existing) > 0 {
+		p.Printfln("%s/%s/%s already exists", upCtx.Account, c.RobotName, c.TokenName)
+		for _, t := range existing {
+			p.Printfln(pterm.LightMagenta("Access ID: ") + t.ID.String())
+			if created, ok := metaTime(t, metaCreatedAt); ok {
+				p.Printfln(pterm.LightMagenta("Created: ") + created.Format(time.RFC3339))
+			}
+		}
+		p.Printfln("The secret of an existing token cannot be retrieved, so no credentials or manifests were written.")
+		return nil
+	}
+	res, err := tc.Create(ctx, &tokens.TokenCreateParameters{
 		Attributes: tokens.TokenAttributes{
 			Name: c.TokenName,
 		},
@@ -92,13 +144,40 @@ func (c *createCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 		return err
 	}
 	p.Printfln("%s/%s/%s created", upCtx.Account, c.RobotName, c.TokenName)
+
+	if err := c.writeToken(p, res.ID.String(), fmt.Sprint(res.DataSet.Meta["jwt"])); err != nil {
+		return err
+	}
+
+	// Existing tokens are only deleted once the new token has been written,
+	// so that a failed replacement never leaves the robot without a usable
+	// token.
+	failed := 0
+	for _, t := range existing {
+		if err := tc.Delete(ctx, t.ID); err != nil {
+			failed++
+			p.Printfln("Failed to delete replaced token %s: %s", t.ID, err)
+			continue
+		}
+		p.Printfln("Replaced token %s deleted", t.ID)
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtReplaceFailed, failed, len(existing))
+	}
+	return nil
+}
+
+// writeToken writes the credentials of a created token to the requested
+// outputs.
+func (c *createCmd) writeToken(p pterm.TextPrinter, access, token string) error {
+	if c.Manifest != "" {
+		if err := c.writeManifest(access, token); err != nil {
+			return errors.Wrap(err, errWriteManifest)
+		}
+	}
 	if c.Output == "" {
-		p.Printfln("Refusing to emit sensitive output. Please specify output location.")
 		return nil
 	}
-
-	access := res.ID.String()
-	token := fmt.Sprint(res.DataSet.Meta["jwt"])
 	if c.Output == "-" {
 		pterm.Println()
 		p.Printfln(pterm.LightMagenta("Access ID: ") + access)
@@ -116,3 +195,35 @@ func (c *createCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 		Token:    token,
 	})
 }
+
+// tokensNamed returns the tokens with the supplied name.
+func tokensNamed(ts []common.DataSet, name string) []common.DataSet {
+	out := []common.DataSet{}
+	for _, t := range ts {
+		if tokenMatches(t, name, uuid.Nil) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// writeManifest writes the manifests that supply the token to an in-cluster
+// agent.
+func (c *createCmd) writeManifest(access, token string) error {
+	objs := kube.TokenBundle{
+		Namespace:      c.Namespace,
+		SecretName:     c.SecretName,
+		ServiceAccount: c.ServiceAccount,
+		Deployment:     c.Deployment,
+		Container:      c.Container,
+	}.Manifests(access, token)
+	if c.Manifest == "-" {
+		return kube.WriteManifests(os.Stdout, objs...)
+	}
+	f, err := os.OpenFile(filepath.Clean(c.Manifest), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck,gosec
+	return kube.WriteManifests(f, objs...)
+}
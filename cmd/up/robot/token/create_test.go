@@ -0,0 +1,128 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/common"
+)
+
+func TestCreateAfterApply(t *testing.T) {
+	id := uuid.MustParse("9b0ab2b4-4a3b-4c5e-8a2e-3f6d1c7e9a10")
+	type want struct {
+		robotName string
+		tokenName string
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		cmd    *createCmd
+		want   want
+	}{
+		"Names": {
+			reason: "A robot name and token name should be accepted.",
+			cmd:    &createCmd{RobotName: "ci", TokenName: "deploy", Output: "-"},
+			want:   want{robotName: "ci", tokenName: "deploy"},
+		},
+		"RobotID": {
+			reason: "When the robot is selected by ID the only argument is the token name.",
+			cmd:    &createCmd{RobotName: "deploy", RobotID: id, Output: "-"},
+			want:   want{tokenName: "deploy"},
+		},
+		"RobotNameAndID": {
+			reason: "A robot name cannot be combined with --robot-id.",
+			cmd:    &createCmd{RobotName: "ci", TokenName: "deploy", RobotID: id},
+			want:   want{robotName: "ci", tokenName: "deploy", err: errors.New(errRobotNameOrID)},
+		},
+		"Manifest": {
+			reason: "Manifests can be written instead of the token file.",
+			cmd:    &createCmd{RobotName: "ci", TokenName: "deploy", Manifest: "-"},
+			want:   want{robotName: "ci", tokenName: "deploy"},
+		},
+		"NoOutput": {
+			reason: "Either a token file or manifests must be written.",
+			cmd:    &createCmd{RobotName: "ci", TokenName: "deploy"},
+			want:   want{robotName: "ci", tokenName: "deploy", err: errors.New(errOutputOrManifest)},
+		},
+		"ContainerWithoutDeployment": {
+			reason: "A container can only be supplied with a Deployment.",
+			cmd:    &createCmd{RobotName: "ci", TokenName: "deploy", Manifest: "-", Container: "main"},
+			want:   want{robotName: "ci", tokenName: "deploy", err: errors.New(errContainerDeployment)},
+		},
+		"NoTokenName": {
+			reason: "A token name must be supplied.",
+			cmd:    &createCmd{RobotName: "ci"},
+			want:   want{robotName: "ci", err: errors.New(errTokenName)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cmd.AfterApply()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAfterApply(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.robotName, tc.cmd.RobotName); diff != "" {
+				t.Errorf("\n%s\nAfterApply(): -want robot name, +got robot name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tokenName, tc.cmd.TokenName); diff != "" {
+				t.Errorf("\n%s\nAfterApply(): -want token name, +got token name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTokensNamed(t *testing.T) {
+	ts := []common.DataSet{
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), AttributeSet: map[string]any{"name": "deploy"}},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), AttributeSet: map[string]any{"name": "ci"}},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), AttributeSet: map[string]any{"name": "deploy"}},
+	}
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   []uuid.UUID
+	}{
+		"Existing": {
+			reason: "Every token with the name should be returned.",
+			name:   "deploy",
+			want: []uuid.UUID{
+				uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+				uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+			},
+		},
+		"NotExisting": {
+			reason: "No tokens should be returned if none has the name.",
+			name:   "other",
+			want:   []uuid.UUID{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []uuid.UUID{}
+			for _, tok := range tokensNamed(ts, tc.name) {
+				got = append(got, tok.ID)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntokensNamed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,70 +17,111 @@ package token
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/upbound/up-sdk-go/service/accounts"
+	"github.com/upbound/up-sdk-go/service/common"
 	"github.com/upbound/up-sdk-go/service/organizations"
 	"github.com/upbound/up-sdk-go/service/robots"
 	"github.com/upbound/up-sdk-go/service/tokens"
 
 	"github.com/upbound/up/internal/input"
+	"github.com/upbound/up/internal/kube"
 	"github.com/upbound/up/internal/upbound"
 )
 
 // BeforeApply sets default values for the delete command, before assignment and validation.
 func (c *deleteCmd) BeforeApply() error {
 	c.prompter = input.NewPrompter()
+	c.selector = input.NewSelector()
 	return nil
 }
 
-// AfterApply accepts user input by default to confirm the delete operation.
-func (c *deleteCmd) AfterApply(p pterm.TextPrinter, upCtx *upbound.Context) error {
-	if c.Force {
+// AfterApply constructs a Kubernetes client if a kubeconfig was supplied to
+// search for pull secrets.
+func (c *deleteCmd) AfterApply(upCtx *upbound.Context) error {
+	shiftArgs(&c.RobotName, &c.TokenName, c.RobotID)
+	if err := validateRobot(c.RobotName, c.RobotID); err != nil {
+		return err
+	}
+	n := 0
+	for _, set := range []bool{c.TokenName != "", c.ID != uuid.Nil, c.Interactive} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New(errNameOrSelect)
+	}
+	if c.Kubeconfig == "" {
 		return nil
 	}
-
-	confirm, err := c.prompter.Prompt("Are you sure you want to delete this robot token? [y/n]", false)
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
 	if err != nil {
 		return err
 	}
-
-	if input.InputYes(confirm) {
-		p.Printfln("Deleting robot token %s/%s/%s. This cannot be undone.", upCtx.Account, c.RobotName, c.TokenName)
-		return nil
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
 	}
-
-	return fmt.Errorf("operation canceled")
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.kClient = client
+	return nil
 }
 
 // deleteCmd deletes a robot token on Upbound.
 type deleteCmd struct {
 	prompter input.Prompter
+	selector input.Selector
+	kClient  kubernetes.Interface
+
+	RobotName   string    `arg:"" optional:"" help:"Name of robot. Omitted if --robot-id is supplied."`
+	TokenName   string    `arg:"" optional:"" help:"Name of token. Omitted if --id or --interactive is supplied."`
+	RobotID     uuid.UUID `help:"ID of robot. Selects a robot that shares its name with others."`
+	ID          uuid.UUID `name:"id" help:"ID of token. Selects a token that shares its name with others."`
+	Interactive bool      `help:"Select the tokens to delete from a list of all tokens of the robot."`
 
-	RobotName string `arg:"" required:"" help:"Name of robot."`
-	TokenName string `arg:"" required:"" help:"Name of token."`
+	// NOTE(hasheddan): kong automatically cleans paths tagged with existingfile.
+	Kubeconfig string `type:"existingfile" help:"Kubeconfig of a cluster to search for pull secrets that use the token."`
+	DryRun     bool   `help:"Show the dependents of the token without deleting it."`
+	Force      bool   `help:"Force delete token even if conflicts or dependents exist." default:"false"`
+}
+
+// Help returns the help text for the delete command.
+func (c *deleteCmd) Help() string {
+	return `
+If --kubeconfig is supplied, the image pull secrets in that cluster that
+authenticate with the token are shown before it is deleted. A token used by
+pull secrets is only deleted if --force is supplied.
 
-	Force bool `help:"Force delete token even if conflicts exist." default:"false"`
+With --interactive, the tokens to delete are selected from a list and deleted
+after a single confirmation. Deletion continues if a token cannot be deleted,
+and the tokens that failed are listed at the end.`
 }
 
 // Run executes the delete command.
-func (c *deleteCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *deleteCmd) Run(ctx context.Context, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
 	if len(rs) == 0 {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
 	// must guarantee that exactly one robot exists in the specified account
@@ -88,25 +129,29 @@ func (c *deleteCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 	// updated.
 	var rid *uuid.UUID
 	for _, r := range rs {
-		if r.Name == c.RobotName {
+		if robotMatches(r, c.RobotName, c.RobotID) {
 			if rid != nil {
 				return errors.Errorf(errMultipleRobotFmt, c.RobotName, upCtx.Account)
 			}
 			// Pin range variable so that we can take address.
 			r := r
 			rid = &r.ID
+			c.RobotName = r.Name
 		}
 	}
 	if rid == nil {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 
-	ts, err := rc.ListTokens(context.Background(), *rid)
+	ts, err := rc.ListTokens(ctx, *rid)
 	if err != nil {
 		return err
 	}
+	if c.Interactive {
+		return c.deleteSelected(ctx, p, tc, upCtx.Account, ts.DataSet)
+	}
 	if len(ts.DataSet) == 0 {
-		return errors.Errorf(errFindTokenFmt, c.TokenName, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindTokenFmt, ref(c.TokenName, c.ID), c.RobotName, upCtx.Account)
 	}
 
 	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
@@ -115,22 +160,134 @@ func (c *deleteCmd) Run(p pterm.TextPrinter, ac *accounts.Client, oc *organizati
 	// when the API is updated.
 	var tid *uuid.UUID
 	for _, t := range ts.DataSet {
-		if fmt.Sprint(t.AttributeSet["name"]) == c.TokenName {
+		if tokenMatches(t, c.TokenName, c.ID) {
 			if tid != nil && !c.Force {
 				return errors.Errorf(errMultipleTokenFmt, c.TokenName, c.RobotName, upCtx.Account)
 			}
 			// Pin range variable so that we can take address.
 			t := t
 			tid = &t.ID
+			c.TokenName = fmt.Sprint(t.AttributeSet["name"])
 		}
 	}
 	if tid == nil {
-		return errors.Errorf(errFindTokenFmt, c.TokenName, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindTokenFmt, ref(c.TokenName, c.ID), c.RobotName, upCtx.Account)
+	}
+
+	var secrets []types.NamespacedName
+	if c.kClient != nil {
+		secrets, err = kube.FindPullSecrets(ctx, c.kClient, tid.String())
+		if err != nil {
+			return err
+		}
+		if len(secrets) == 0 {
+			p.Printfln("Robot token %s/%s/%s is not used by any pull secrets.", upCtx.Account, c.RobotName, c.TokenName)
+		} else {
+			p.Printfln("Robot token %s/%s/%s is used by the following pull secrets:", upCtx.Account, c.RobotName, c.TokenName)
+			for _, s := range secrets {
+				p.Printfln("  %s", s)
+			}
+		}
+	}
+	if c.DryRun {
+		return nil
+	}
+	if len(secrets) > 0 && !c.Force {
+		return errors.Errorf(errDependentsFmt, c.TokenName, len(secrets))
+	}
+	if !c.Force {
+		confirm, err := c.prompter.Prompt("Are you sure you want to delete this robot token? [y/n]", false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return fmt.Errorf("operation canceled")
+		}
+		p.Printfln("Deleting robot token %s/%s/%s. This cannot be undone.", upCtx.Account, c.RobotName, c.TokenName)
 	}
 
-	if err := tc.Delete(context.Background(), *tid); err != nil {
+	if err := tc.Delete(ctx, *tid); err != nil {
 		return err
 	}
 	p.Printfln("%s/%s/%s deleted", upCtx.Account, c.RobotName, c.TokenName)
 	return nil
 }
+
+// deleteSelected deletes the tokens that the user selects from the supplied
+// tokens after a single confirmation.
+func (c *deleteCmd) deleteSelected(ctx context.Context, p pterm.TextPrinter, tc *tokens.Client, account string, ts []common.DataSet) error { //nolint:gocyclo
+	if len(ts) == 0 {
+		p.Printfln("No tokens found for robot %s in %s", c.RobotName, account)
+		return nil
+	}
+	byOption := make(map[string]common.DataSet, len(ts))
+	options := make([]string, 0, len(ts))
+	for _, t := range ts {
+		o := tokenOption(t)
+		byOption[o] = t
+		options = append(options, o)
+	}
+	sort.Strings(options)
+	selected, err := c.selector.Select("Select tokens to delete", options)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		p.Printfln("No tokens selected.")
+		return nil
+	}
+
+	used := 0
+	if c.kClient != nil {
+		for _, o := range selected {
+			secrets, err := kube.FindPullSecrets(ctx, c.kClient, byOption[o].ID.String())
+			if err != nil {
+				return err
+			}
+			if len(secrets) == 0 {
+				p.Printfln("Robot token %s is not used by any pull secrets.", o)
+				continue
+			}
+			used++
+			p.Printfln("Robot token %s is used by the following pull secrets:", o)
+			for _, s := range secrets {
+				p.Printfln("  %s", s)
+			}
+		}
+	}
+	if c.DryRun {
+		return nil
+	}
+	if used > 0 && !c.Force {
+		return errors.Errorf(errFmtSelectedDependents, used)
+	}
+	if !c.Force {
+		confirm, err := c.prompter.Prompt(fmt.Sprintf("Are you sure you want to delete %d robot tokens? [y/n]", len(selected)), false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return errors.New(errOperationCanceled)
+		}
+	}
+
+	failed := 0
+	for _, o := range selected {
+		if err := tc.Delete(ctx, byOption[o].ID); err != nil {
+			failed++
+			p.Printfln("Failed to delete %s: %s", o, err)
+			continue
+		}
+		p.Printfln("%s/%s/%s deleted", account, c.RobotName, byOption[o].AttributeSet["name"])
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtDeleteFailed, failed, len(selected))
+	}
+	return nil
+}
+
+// tokenOption returns the option by which a token is presented for selection.
+// The ID is included because token names are not unique.
+func tokenOption(t common.DataSet) string {
+	return fmt.Sprintf("%s (%s)", t.AttributeSet["name"], t.ID)
+}
@@ -16,7 +16,6 @@ package token
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -35,63 +34,81 @@ import (
 
 // AfterApply sets default values in command after assignment and validation.
 func (c *getCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
+	shiftArgs(&c.RobotName, &c.TokenName, c.RobotID)
+	if err := validateRobot(c.RobotName, c.RobotID); err != nil {
+		return err
+	}
+	if (c.TokenName == "") == (c.ID == uuid.Nil) {
+		return errors.New(errTokenNameOrID)
+	}
 	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
 	return nil
 }
 
 // getCmd deletes a robot token on Upbound.
 type getCmd struct {
-	RobotName string `arg:"" required:"" help:"Name of robot."`
-	TokenName string `arg:"" required:"" help:"Name of token."`
+	RobotName string    `arg:"" optional:"" help:"Name of robot. Omitted if --robot-id is supplied."`
+	TokenName string    `arg:"" optional:"" help:"Name of token. Omitted if --id is supplied."`
+	RobotID   uuid.UUID `help:"ID of robot. Selects a robot that shares its name with others."`
+	ID        uuid.UUID `name:"id" help:"ID of token. Selects a token that shares its name with others."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{common.DataSet{}}
 }
 
 // Run executes the get robot token command.
-func (c *getCmd) Run(printer upterm.ObjectPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
 	if len(rs) == 0 {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 
 	// We pick the first robot account with this name, though there
 	// may be more than one. If a user wants to see all of the tokens
-	// for robots with the same name, they can use the list commands
+	// for robots with the same name, they can use the list commands, and
+	// select one of them with --robot-id.
 	var rid *uuid.UUID
 	for _, r := range rs {
-		if r.Name == c.RobotName {
+		if robotMatches(r, c.RobotName, c.RobotID) {
 			// Pin range variable so that we can take address.
 			r := r
 			rid = &r.ID
+			c.RobotName = r.Name
 			break
 		}
 	}
 	if rid == nil {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 
-	ts, err := rc.ListTokens(context.Background(), *rid)
+	ts, err := rc.ListTokens(ctx, *rid)
 	if err != nil {
 		return err
 	}
 	if len(ts.DataSet) == 0 {
-		return errors.Errorf(errFindTokenFmt, c.TokenName, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindTokenFmt, ref(c.TokenName, c.ID), c.RobotName, upCtx.Account)
 	}
 
 	// We pick the first token with this name, though there may be more
 	// than one. If a user wants to see all of the tokens with the same name
-	// they can use the list command.
+	// they can use the list command, and select one of them with --id.
 	var theToken *common.DataSet
 	for _, t := range ts.DataSet {
-		if fmt.Sprint(t.AttributeSet["name"]) == c.TokenName {
+		if tokenMatches(t, c.TokenName, c.ID) {
 			// Pin range variable so that we can take address.
 			t := t
 			theToken = &t
@@ -99,7 +116,7 @@ func (c *getCmd) Run(printer upterm.ObjectPrinter, ac *accounts.Client, oc *orga
 		}
 	}
 	if theToken == nil {
-		return errors.Errorf(errFindTokenFmt, c.TokenName, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindTokenFmt, ref(c.TokenName, c.ID), c.RobotName, upCtx.Account)
 	}
 	return printer.Print(*theToken, fieldNames, extractFields)
 }
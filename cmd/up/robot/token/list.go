@@ -34,34 +34,46 @@ import (
 	"github.com/upbound/up/internal/upterm"
 )
 
-var fieldNames = []string{"NAME", "ID", "CREATED"}
+var fieldNames = []string{"NAME", "ID", "CREATED", "LAST USED", "LAST USED FROM"}
 
 // AfterApply sets default values in command after assignment and validation.
 func (c *listCmd) AfterApply(kongCtx *kong.Context, upCtx *upbound.Context) error {
+	if err := validateRobot(c.RobotName, c.RobotID); err != nil {
+		return err
+	}
 	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
 	return nil
 }
 
 // listCmd creates a robot on Upbound.
 type listCmd struct {
-	RobotName string `arg:"" required:"" help:"Name of robot." predictor:"robots"`
+	RobotName string    `arg:"" optional:"" help:"Name of robot. Required unless --robot-id is supplied." predictor:"robots"`
+	RobotID   uuid.UUID `help:"ID of robot. Selects a robot that shares its name with others."`
+	UnusedFor Age       `help:"Only list tokens that have not been used for this long, e.g. 90d. Tokens whose use is not reported are judged by their creation time."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{common.DataSet{}}
 }
 
 // Run executes the list robot tokens command.
-func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error { //nolint:gocyclo
-	a, err := ac.Get(context.Background(), upCtx.Account)
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
 	if err != nil {
 		return err
 	}
 	if a.Account.Type != accounts.AccountOrganization {
 		return errors.New(errUserAccount)
 	}
-	rs, err := oc.ListRobots(context.Background(), a.Organization.ID)
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
 	if err != nil {
 		return err
 	}
 	if len(rs) == 0 {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
 	// must guarantee that exactly one robot exists in the specified account
@@ -69,39 +81,62 @@ func (c *listCmd) Run(printer upterm.ObjectPrinter, p pterm.TextPrinter, ac *acc
 	// updated.
 	var rid *uuid.UUID
 	for _, r := range rs {
-		if r.Name == c.RobotName {
+		if robotMatches(r, c.RobotName, c.RobotID) {
 			if rid != nil {
 				return errors.Errorf(errMultipleRobotFmt, c.RobotName, upCtx.Account)
 			}
 			// Pin range variable so that we can take address.
 			r := r
 			rid = &r.ID
+			c.RobotName = r.Name
 		}
 	}
 	if rid == nil {
-		return errors.Errorf(errFindRobotFmt, c.RobotName, upCtx.Account)
+		return errors.Errorf(errFindRobotFmt, ref(c.RobotName, c.RobotID), upCtx.Account)
 	}
 
-	ts, err := rc.ListTokens(context.Background(), *rid)
+	ts, err := rc.ListTokens(ctx, *rid)
 	if err != nil {
 		return err
 	}
-	if len(ts.DataSet) == 0 {
+	dataSet := ts.DataSet
+	if c.UnusedFor > 0 {
+		dataSet = unusedFor(dataSet, time.Duration(c.UnusedFor), time.Now())
+	}
+	if len(dataSet) == 0 {
 		p.Printfln("No tokens found for robot %s in %s", c.RobotName, upCtx.Account)
 		return nil
 	}
-	return printer.Print(ts.DataSet, fieldNames, extractFields)
+	return printer.Print(dataSet, fieldNames, extractFields)
+}
+
+// unusedFor returns the tokens that have not been used for at least the
+// supplied duration before now. Tokens for which neither use nor creation time
+// is reported are included, as they cannot be shown to be in use.
+func unusedFor(ts []common.DataSet, d time.Duration, now time.Time) []common.DataSet {
+	out := []common.DataSet{}
+	for _, t := range ts {
+		if since, ok := UnusedSince(t); ok && now.Sub(since) < d {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
 }
 
 func extractFields(obj any) []string {
 	t := obj.(common.DataSet)
 
 	n := fmt.Sprint(t.AttributeSet["name"])
-	c := "n/a"
-	if ca, ok := t.Meta["createdAt"]; ok {
-		if ct, err := time.Parse(time.RFC3339, fmt.Sprint(ca)); err == nil {
-			c = duration.HumanDuration(time.Since(ct))
-		}
+	c, lu, from := "n/a", "n/a", "n/a"
+	if ct, ok := metaTime(t, metaCreatedAt); ok {
+		c = duration.HumanDuration(time.Since(ct))
+	}
+	if lt, ok := metaTime(t, metaLastUsedAt); ok {
+		lu = duration.HumanDuration(time.Since(lt))
+	}
+	if f := lastUsedFrom(t); f != "" {
+		from = f
 	}
-	return []string{n, t.ID.String(), c}
+	return []string{n, t.ID.String(), c, lu, from}
 }
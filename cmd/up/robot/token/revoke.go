@@ -0,0 +1,205 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up-sdk-go/service/accounts"
+	"github.com/upbound/up-sdk-go/service/common"
+	"github.com/upbound/up-sdk-go/service/organizations"
+	"github.com/upbound/up-sdk-go/service/robots"
+	"github.com/upbound/up-sdk-go/service/tokens"
+
+	"github.com/upbound/up/internal/input"
+	"github.com/upbound/up/internal/journal"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errRevokeFilter      = "either --all or at least one of --older-than and --unused-for must be provided"
+	errRevokeAllFilter   = "--all cannot be used with --older-than or --unused-for"
+	errRevokeRobot       = "exactly one of --robot or --robot-id must be provided"
+	errFmtRevokeFailed   = "failed to revoke %d of %d tokens"
+	errOperationCanceled = "operation canceled"
+)
+
+// BeforeApply sets default values for the revoke command, before assignment
+// and validation.
+func (c *revokeCmd) BeforeApply() error {
+	c.prompter = input.NewPrompter()
+	return nil
+}
+
+// AfterApply validates the revoke command after assignment.
+func (c *revokeCmd) AfterApply() error {
+	filtered := c.OlderThan > 0 || c.UnusedFor > 0
+	if c.All && filtered {
+		return errors.New(errRevokeAllFilter)
+	}
+	if !c.All && !filtered {
+		return errors.New(errRevokeFilter)
+	}
+	if (c.Robot == "") == (c.RobotID == uuid.Nil) {
+		return errors.New(errRevokeRobot)
+	}
+	return nil
+}
+
+// revokeCmd deletes the tokens of a robot that match filters in one
+// operation.
+type revokeCmd struct {
+	prompter input.Prompter
+
+	Robot     string    `help:"Name of the robot whose tokens are revoked. Required unless --robot-id is supplied." predictor:"robots"`
+	RobotID   uuid.UUID `help:"ID of the robot whose tokens are revoked. Selects a robot that shares its name with others."`
+	All       bool      `help:"Revoke all tokens of the robot."`
+	OlderThan Age       `help:"Revoke tokens created longer ago than this, e.g. 90d."`
+	UnusedFor Age       `help:"Revoke tokens that have not been used for this long, e.g. 90d. Tokens whose use is not reported are judged by their creation time."`
+	DryRun    bool      `help:"Show the tokens that would be revoked without revoking them."`
+	Force     bool      `help:"Revoke the tokens without asking for confirmation."`
+	Resume    string    `help:"ID of a partially failed revocation to resume. Tokens it already revoked are skipped."`
+}
+
+// Help returns the help text for the revoke command.
+func (c *revokeCmd) Help() string {
+	return `
+Revoke deletes every token of a robot that matches the supplied filters, for
+example after a credential leak. Filters are combined, so a token must match all
+of them to be revoked. Revocation continues if a token cannot be deleted, and
+the tokens that failed are listed at the end along with an operation ID that
+can be passed to --resume to retry only those tokens.`
+}
+
+// Run executes the revoke command.
+func (c *revokeCmd) Run(ctx context.Context, p pterm.TextPrinter, ac *accounts.Client, oc *organizations.Client, rc *robots.Client, tc *tokens.Client, upCtx *upbound.Context) error { //nolint:gocyclo
+	a, err := ac.Get(ctx, upCtx.Account)
+	if err != nil {
+		return err
+	}
+	if a.Account.Type != accounts.AccountOrganization {
+		return errors.New(errUserAccount)
+	}
+	rs, err := oc.ListRobots(ctx, a.Organization.ID)
+	if err != nil {
+		return err
+	}
+	// TODO(hasheddan): because this API does not guarantee name uniqueness, we
+	// must guarantee that exactly one robot exists in the specified account
+	// with the provided name. Logic should be simplified when the API is
+	// updated.
+	var rid *uuid.UUID
+	for _, r := range rs {
+		if robotMatches(r, c.Robot, c.RobotID) {
+			if rid != nil {
+				return errors.Errorf(errMultipleRobotFmt, c.Robot, upCtx.Account)
+			}
+			// Pin range variable so that we can take address.
+			r := r
+			rid = &r.ID
+			c.Robot = r.Name
+		}
+	}
+	if rid == nil {
+		return errors.Errorf(errFindRobotFmt, ref(c.Robot, c.RobotID), upCtx.Account)
+	}
+
+	ts, err := rc.ListTokens(ctx, *rid)
+	if err != nil {
+		return err
+	}
+	matches := c.filter(ts.DataSet, time.Now())
+	if len(matches) == 0 {
+		p.Printfln("No matching tokens found for robot %s in %s", c.Robot, upCtx.Account)
+		return nil
+	}
+	p.Printfln("The following tokens of robot %s/%s match:", upCtx.Account, c.Robot)
+	for _, t := range matches {
+		p.Printfln("  %s (%s)", t.AttributeSet["name"], t.ID)
+	}
+	if c.DryRun {
+		return nil
+	}
+	if !c.Force {
+		confirm, err := c.prompter.Prompt(fmt.Sprintf("Are you sure you want to revoke %d tokens? [y/n]", len(matches)), false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return errors.New(errOperationCanceled)
+		}
+	}
+
+	store, err := journal.NewStore()
+	if err != nil {
+		return err
+	}
+	j, err := store.Start(fmt.Sprintf("robot-token-revoke/%s/%s", upCtx.Account, c.Robot), c.Resume)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, t := range matches {
+		if j.Done(t.ID.String()) {
+			continue
+		}
+		err := tc.Delete(ctx, t.ID)
+		if jerr := j.Record(t.ID.String(), err); jerr != nil {
+			return jerr
+		}
+		if err != nil {
+			failed++
+			p.Printfln("Failed to revoke %s (%s): %s", t.AttributeSet["name"], t.ID, err)
+		}
+	}
+	p.Printfln("Revoked %d of %d tokens of robot %s/%s", len(matches)-failed, len(matches), upCtx.Account, c.Robot)
+	if failed > 0 {
+		p.Println(j.ResumeHint())
+		return errors.Errorf(errFmtRevokeFailed, failed, len(matches))
+	}
+	return j.Finish()
+}
+
+// filter returns the tokens that match all of the command's filters at the
+// supplied time. Tokens for which the filtered times are not reported cannot
+// be shown to match, so they are not revoked.
+func (c *revokeCmd) filter(ts []common.DataSet, now time.Time) []common.DataSet {
+	if c.All {
+		return ts
+	}
+	out := []common.DataSet{}
+	for _, t := range ts {
+		if c.OlderThan > 0 {
+			created, ok := metaTime(t, metaCreatedAt)
+			if !ok || now.Sub(created) < time.Duration(c.OlderThan) {
+				continue
+			}
+		}
+		if c.UnusedFor > 0 {
+			since, ok := UnusedSince(t)
+			if !ok || now.Sub(since) < time.Duration(c.UnusedFor) {
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/common"
+)
+
+func TestRevokeAfterApply(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cmd    *revokeCmd
+		want   error
+	}{
+		"All": {
+			reason: "Revoking all tokens should be valid.",
+			cmd:    &revokeCmd{Robot: "ci", All: true},
+		},
+		"Filter": {
+			reason: "Revoking tokens matching a filter should be valid.",
+			cmd:    &revokeCmd{Robot: "ci", OlderThan: Age(time.Hour)},
+		},
+		"RobotID": {
+			reason: "The robot may be selected by ID instead of name.",
+			cmd:    &revokeCmd{RobotID: uuid.MustParse("9b0ab2b4-4a3b-4c5e-8a2e-3f6d1c7e9a10"), All: true},
+		},
+		"NoRobot": {
+			reason: "Either --robot or --robot-id must be supplied.",
+			cmd:    &revokeCmd{All: true},
+			want:   errors.New(errRevokeRobot),
+		},
+		"RobotAndRobotID": {
+			reason: "--robot and --robot-id cannot be combined.",
+			cmd:    &revokeCmd{Robot: "ci", RobotID: uuid.MustParse("9b0ab2b4-4a3b-4c5e-8a2e-3f6d1c7e9a10"), All: true},
+			want:   errors.New(errRevokeRobot),
+		},
+		"NoFilter": {
+			reason: "Either --all or a filter must be supplied.",
+			cmd:    &revokeCmd{},
+			want:   errors.New(errRevokeFilter),
+		},
+		"AllWithFilter": {
+			reason: "--all cannot be combined with filters.",
+			cmd:    &revokeCmd{All: true, UnusedFor: Age(time.Hour)},
+			want:   errors.New(errRevokeAllFilter),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cmd.AfterApply()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAfterApply(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRevokeFilter(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) Age { return Age(time.Duration(n) * 24 * time.Hour) }
+	ts := []common.DataSet{
+		{AttributeSet: map[string]any{"name": "old-unused"}, Meta: map[string]any{
+			metaCreatedAt:  "2023-01-01T00:00:00Z",
+			metaLastUsedAt: "2023-02-01T00:00:00Z",
+		}},
+		{AttributeSet: map[string]any{"name": "old-used"}, Meta: map[string]any{
+			metaCreatedAt:  "2023-01-01T00:00:00Z",
+			metaLastUsedAt: "2023-09-30T00:00:00Z",
+		}},
+		{AttributeSet: map[string]any{"name": "new"}, Meta: map[string]any{
+			metaCreatedAt: "2023-09-15T00:00:00Z",
+		}},
+		{AttributeSet: map[string]any{"name": "unknown"}},
+	}
+	cases := map[string]struct {
+		reason string
+		cmd    *revokeCmd
+		want   []string
+	}{
+		"All": {
+			reason: "All tokens should match --all.",
+			cmd:    &revokeCmd{All: true},
+			want:   []string{"old-unused", "old-used", "new", "unknown"},
+		},
+		"OlderThan": {
+			reason: "Tokens created before the cutoff should match.",
+			cmd:    &revokeCmd{OlderThan: days(90)},
+			want:   []string{"old-unused", "old-used"},
+		},
+		"OlderThanAndUnusedFor": {
+			reason: "Tokens should match all filters.",
+			cmd:    &revokeCmd{OlderThan: days(90), UnusedFor: days(30)},
+			want:   []string{"old-unused"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []string{}
+			for _, tok := range tc.cmd.filter(ts, now) {
+				got = append(got, tok.AttributeSet["name"].(string))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nfilter(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/common"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// tokenV1 is version v1 of the machine output of a robot token.
+type tokenV1 struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedFrom string     `json:"lastUsedFrom,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(common.DataSet{}, upterm.Schema{
+		Kind:    "RobotToken",
+		Version: "v1",
+		Convert: convertTokenV1,
+	})
+}
+
+func convertTokenV1(obj any) any {
+	t := obj.(common.DataSet)
+	out := tokenV1{ID: t.ID, LastUsedFrom: lastUsedFrom(t)}
+	if n, ok := t.AttributeSet["name"]; ok && n != nil {
+		out.Name = fmt.Sprint(n)
+	}
+	if ct, ok := metaTime(t, metaCreatedAt); ok {
+		ct = ct.UTC()
+		out.CreatedAt = &ct
+	}
+	if lt, ok := metaTime(t, metaLastUsedAt); ok {
+		lt = lt.UTC()
+		out.LastUsedAt = &lt
+	}
+	return out
+}
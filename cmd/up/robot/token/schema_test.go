@@ -0,0 +1,52 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up-sdk-go/service/common"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+func TestTokenSchemaV1(t *testing.T) {
+	tok := common.DataSet{
+		ID:           uuid.MustParse("0b8a4c3e-4a8e-4f3c-9d2a-1c4b5e6f7a8b"),
+		AttributeSet: map[string]any{"name": "deploy"},
+		Meta: map[string]any{
+			metaCreatedAt:    "2023-01-01T00:00:00Z",
+			metaLastUsedAt:   "2023-09-30T00:00:00Z",
+			metaLastUsedFrom: "10.0.0.1",
+		},
+	}
+	got, err := upterm.MachineJSON(tok)
+	if err != nil {
+		t.Fatalf("MachineJSON(...): %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "token-v1.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+		t.Errorf("MachineJSON(...): -want, +got:\n%s", diff)
+	}
+}
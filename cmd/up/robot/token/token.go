@@ -15,19 +15,47 @@
 package token
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
 
+	"github.com/upbound/up-sdk-go/service/common"
+	"github.com/upbound/up-sdk-go/service/organizations"
 	"github.com/upbound/up-sdk-go/service/tokens"
 
 	"github.com/upbound/up/internal/upbound"
 )
 
 const (
-	errUserAccount      = "robots are not currently supported for user accounts"
-	errMultipleRobotFmt = "found multiple robots with name %s in %s"
-	errMultipleTokenFmt = "found multiple tokens with name %s for robot %s in %s"
-	errFindRobotFmt     = "could not find robot %s in %s"
-	errFindTokenFmt     = "could not find token %s for robot %s in %s"
+	errUserAccount           = "robots are not currently supported for user accounts"
+	errMultipleRobotFmt      = "found multiple robots with name %s in %s, use --robot-id to select one"
+	errMultipleTokenFmt      = "found multiple tokens with name %s for robot %s in %s, use --id to select one"
+	errFindRobotFmt          = "could not find robot %s in %s"
+	errFindTokenFmt          = "could not find token %s for robot %s in %s"
+	errDependentsFmt         = "token %s is used by %d pull secrets, use --force to delete it anyway"
+	errFmtInvalidAge         = "invalid duration %q: must be a number of days, e.g. 90d, or a duration, e.g. 12h"
+	errRobotNameOrID         = "exactly one of a robot name or --robot-id must be provided"
+	errTokenName             = "a token name must be provided"
+	errTokenNameOrID         = "exactly one of a token name or --id must be provided"
+	errNameOrSelect          = "exactly one of a token name, --id, or --interactive must be provided"
+	errFmtDeleteFailed       = "failed to delete %d of %d tokens"
+	errFmtSelectedDependents = "%d of the selected tokens are used by pull secrets, use --force to delete them anyway"
+	errOutputOrManifest      = "either --output or --manifest must be provided"
+	errContainerDeployment   = "--container requires --deployment"
+	errWriteManifest         = "unable to write manifest"
+	errFmtReplaceFailed      = "failed to delete %d of %d replaced tokens"
+)
+
+// Keys of token metadata reported by the API.
+const (
+	metaCreatedAt    = "createdAt"
+	metaLastUsedAt   = "lastUsedAt"
+	metaLastUsedFrom = "lastUsedIP"
 )
 
 // AfterApply constructs and binds a robots client to any subcommands
@@ -46,5 +74,117 @@ type Cmd struct {
 	Create createCmd `cmd:"" help:"Create a token for the robot."`
 	Delete deleteCmd `cmd:"" help:"Delete a token for the robot."`
 	List   listCmd   `cmd:"" help:"List the tokens for the robot."`
+	Revoke revokeCmd `cmd:"" help:"Revoke the tokens of a robot that match filters."`
 	Get    getCmd    `cmd:"" help:"Get a token for the robot."`
 }
+
+// shiftArgs assigns the positional arguments of a command that accepts a robot
+// name followed by a token name. When the robot is selected by ID only the
+// token name is supplied, so it is parsed as the robot name.
+func shiftArgs(robotName, tokenName *string, robotID uuid.UUID) {
+	if robotID != uuid.Nil && *tokenName == "" {
+		*robotName, *tokenName = "", *robotName
+	}
+}
+
+// validateRobot validates that a robot is selected by exactly one of its name
+// and its ID.
+func validateRobot(name string, id uuid.UUID) error {
+	if (name == "") == (id == uuid.Nil) {
+		return errors.New(errRobotNameOrID)
+	}
+	return nil
+}
+
+// robotMatches reports whether a robot is the one identified by the supplied
+// ID or, if no ID is supplied, by the supplied name. Robot names are not
+// unique, so an ID is needed to identify a robot that shares its name.
+func robotMatches(r organizations.Robot, name string, id uuid.UUID) bool {
+	if id != uuid.Nil {
+		return r.ID == id
+	}
+	return r.Name == name
+}
+
+// tokenMatches reports whether a token is the one identified by the supplied
+// ID or, if no ID is supplied, by the supplied name.
+func tokenMatches(t common.DataSet, name string, id uuid.UUID) bool {
+	if id != uuid.Nil {
+		return t.ID == id
+	}
+	return fmt.Sprint(t.AttributeSet["name"]) == name
+}
+
+// ref returns the ID if one is supplied, and otherwise the name, for use in
+// messages about a robot or token that could not be found.
+func ref(name string, id uuid.UUID) string {
+	if id != uuid.Nil {
+		return id.String()
+	}
+	return name
+}
+
+// Age is a duration that may also be supplied as a number of days, e.g. 90d.
+type Age time.Duration
+
+// Decode parses an age from a kong flag.
+func (a *Age) Decode(ctx *kong.DecodeContext) error {
+	var value string
+	if err := ctx.Scan.PopValueInto("duration", &value); err != nil {
+		return err
+	}
+	d, err := parseAge(value)
+	if err != nil {
+		return err
+	}
+	*a = Age(d)
+	return nil
+}
+
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, errors.Errorf(errFmtInvalidAge, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errors.Errorf(errFmtInvalidAge, s)
+	}
+	return d, nil
+}
+
+// metaTime returns the time stored under the supplied key of the token's
+// metadata, if the API reports it.
+func metaTime(t common.DataSet, key string) (time.Time, bool) {
+	v, ok := t.Meta[key]
+	if !ok || v == nil {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, fmt.Sprint(v))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
+
+// lastUsedFrom returns the source address from which the token was last
+// used, if the API reports it.
+func lastUsedFrom(t common.DataSet) string {
+	if v, ok := t.Meta[metaLastUsedFrom]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// UnusedSince returns the time since which the token has not been used: its
+// last use, or its creation if it has never been used or its use is not
+// reported.
+func UnusedSince(t common.DataSet) (time.Time, bool) {
+	if lu, ok := metaTime(t, metaLastUsedAt); ok {
+		return lu, true
+	}
+	return metaTime(t, metaCreatedAt)
+}
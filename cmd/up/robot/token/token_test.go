@@ -0,0 +1,101 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up-sdk-go/service/common"
+)
+
+func TestParseAge(t *testing.T) {
+	type want struct {
+		d   time.Duration
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Days": {
+			reason: "A number of days should be parsed.",
+			s:      "90d",
+			want:   want{d: 90 * 24 * time.Hour},
+		},
+		"Duration": {
+			reason: "A Go duration should be parsed.",
+			s:      "12h",
+			want:   want{d: 12 * time.Hour},
+		},
+		"Invalid": {
+			reason: "Other values should be rejected.",
+			s:      "ninety days",
+			want:   want{err: errors.Errorf(errFmtInvalidAge, "ninety days")},
+		},
+		"Negative": {
+			reason: "Negative durations should be rejected.",
+			s:      "-1d",
+			want:   want{err: errors.Errorf(errFmtInvalidAge, "-1d")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := parseAge(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseAge(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.d, d); diff != "" {
+				t.Errorf("\n%s\nparseAge(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnusedFor(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	token := func(name string, meta map[string]any) common.DataSet {
+		return common.DataSet{AttributeSet: map[string]any{"name": name}, Meta: meta}
+	}
+	ts := []common.DataSet{
+		token("recently-used", map[string]any{
+			metaCreatedAt:  "2023-01-01T00:00:00Z",
+			metaLastUsedAt: "2023-09-30T00:00:00Z",
+		}),
+		token("stale", map[string]any{
+			metaCreatedAt:  "2023-01-01T00:00:00Z",
+			metaLastUsedAt: "2023-05-01T00:00:00Z",
+		}),
+		token("new", map[string]any{
+			metaCreatedAt: "2023-09-15T00:00:00Z",
+		}),
+		token("never-used", map[string]any{
+			metaCreatedAt: "2023-01-01T00:00:00Z",
+		}),
+		token("unknown", nil),
+	}
+	got := []string{}
+	for _, t := range unusedFor(ts, 90*24*time.Hour, now) {
+		got = append(got, t.AttributeSet["name"].(string))
+	}
+	if diff := cmp.Diff([]string{"stale", "never-used", "unknown"}, got); diff != "" {
+		t.Errorf("unusedFor(...): -want, +got:\n%s", diff)
+	}
+}
@@ -15,5 +15,6 @@
 package billing
 
 type Cmd struct {
-	Get getCmd `cmd:"" help:"Get a billing report for submission to Upbound."`
+	Get      getCmd      `cmd:"" help:"Get a billing report for submission to Upbound."`
+	Estimate estimateCmd `cmd:"" help:"Estimate the bill for a billing report using an Upbound price list."`
 }
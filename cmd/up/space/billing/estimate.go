@@ -0,0 +1,186 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/config"
+	uphttp "github.com/upbound/up/internal/http"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/usage/estimate"
+	"github.com/upbound/up/internal/usage/report"
+	reporttar "github.com/upbound/up/internal/usage/report/file/tar"
+)
+
+const (
+	errReadPriceList = "unable to read price list"
+)
+
+var (
+	estimateFieldNames  = []string{"ACCOUNT", "START", "END", "CURRENCY", "RESOURCE HOURS", "TOTAL"}
+	uncoveredFieldNames = []string{"GROUP", "VERSION", "KIND", "EVENTS", "RESOURCE HOURS"}
+)
+
+// billEstimate is the estimated bill for a billing report.
+type billEstimate struct {
+	UpboundAccount string              `json:"account"`
+	Start          time.Time           `json:"start"`
+	End            time.Time           `json:"end"`
+	Effective      string              `json:"effective,omitempty"`
+	Filter         *report.EventFilter `json:"filter,omitempty"`
+	Estimate       *estimate.Estimate  `json:"estimate"`
+}
+
+// estimateCmd estimates the bill for a billing report.
+type estimateCmd struct {
+	Report    string `arg:"" type:"path" help:"Billing report to estimate. Either the archive written by 'up space billing get', or a directory into which it has been extracted."`
+	PriceList string `required:"" env:"UP_BILLING_PRICE_LIST" help:"URL or file of the price list to apply."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// Help returns the help text for the estimate command.
+func (c *estimateCmd) Help() string {
+	return `
+The report is verified against its manifest, then the price list given with
+--price-list is applied to each usage event in it. An event records the largest
+number of resources of a kind that ran in a control plane during a window of
+time, and is priced as that many resources running for the whole window. Each
+resource is priced by the first price in the list whose pattern matches its
+group, version, and kind.
+
+Usage of resources that the price list does not cover is listed separately and
+is not included in the estimate. The estimate is for information only and may
+differ from your invoice.
+
+The estimate is printed as tables of the cost of each control plane and of the
+uncovered usage. With --format or --output it is printed as a single object.`
+}
+
+// PrintedObjects returns the objects printed by the estimate command.
+func (c *estimateCmd) PrintedObjects() []any {
+	return []any{billEstimate{}, estimate.ControlPlane{}, estimate.Uncovered{}}
+}
+
+// Run executes the estimate command.
+func (c *estimateCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter) error {
+	files, err := reporttar.Open(c.Report)
+	if err != nil {
+		return err
+	}
+	m, err := reporttar.Verify(files)
+	if err != nil {
+		return err
+	}
+	events, err := reporttar.Events(files, m)
+	if err != nil {
+		return err
+	}
+	pl, err := c.priceList(ctx)
+	if err != nil {
+		return err
+	}
+	be := billEstimate{
+		UpboundAccount: m.UpboundAccount,
+		Start:          m.TimeRange.Start,
+		End:            m.TimeRange.End,
+		Effective:      pl.Effective,
+		Filter:         m.Filter,
+		Estimate:       pl.Estimate(events),
+	}
+	// The tables cannot be shaped or read by other tools, so the estimate is
+	// printed as a single object if another format is requested.
+	if printer.Output.IsSet() || printer.Format != config.Default {
+		return printer.Print(be, estimateFieldNames, extractEstimateFields)
+	}
+	return printEstimate(&printer, p, be)
+}
+
+// priceList downloads or reads the price list.
+func (c *estimateCmd) priceList(ctx context.Context) (*estimate.PriceList, error) {
+	src := c.PriceList
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return estimate.Fetch(ctx, uphttp.NewClient(), src)
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadPriceList)
+	}
+	return estimate.Parse(b)
+}
+
+// printEstimate prints the estimated cost of each control plane, followed by
+// the usage that the price list does not cover.
+func printEstimate(printer *upterm.ObjectPrinter, p pterm.TextPrinter, be billEstimate) error {
+	effective := ""
+	if be.Effective != "" {
+		effective = fmt.Sprintf(" using prices effective %s", be.Effective)
+	}
+	p.Printfln("Estimated bill for Upbound account %s from %s to %s%s.\n", be.UpboundAccount, formatTimestamp(be.Start), formatTimestamp(be.End), effective)
+	if be.Filter != nil {
+		p.Printfln("Warning: the report is filtered and only contains usage selected by: %s.\n", be.Filter)
+	}
+
+	est := be.Estimate
+	rows := append(append([]estimate.ControlPlane{}, est.ControlPlanes...), estimate.ControlPlane{ID: "TOTAL", ResourceHours: est.ResourceHours, Cost: est.Total})
+	fieldNames := []string{"CONTROL PLANE", "RESOURCE HOURS", fmt.Sprintf("COST (%s)", est.Currency)}
+	if err := printer.Print(rows, fieldNames, extractControlPlaneFields); err != nil {
+		return err
+	}
+	if len(est.Uncovered) == 0 {
+		return nil
+	}
+	p.Printfln("Warning: %d usage events are not covered by the price list and are not included in the estimate.\n", est.UncoveredEvents())
+	return printer.Print(est.Uncovered, uncoveredFieldNames, extractUncoveredFields)
+}
+
+func extractEstimateFields(obj any) []string {
+	be := obj.(billEstimate)
+	return []string{
+		be.UpboundAccount,
+		formatTimestamp(be.Start),
+		formatTimestamp(be.End),
+		be.Estimate.Currency,
+		formatHours(be.Estimate.ResourceHours),
+		formatCost(be.Estimate.Total),
+	}
+}
+
+func extractControlPlaneFields(obj any) []string {
+	cp := obj.(estimate.ControlPlane)
+	return []string{cp.ID, formatHours(cp.ResourceHours), formatCost(cp.Cost)}
+}
+
+func extractUncoveredFields(obj any) []string {
+	u := obj.(estimate.Uncovered)
+	return []string{u.Group, u.Version, u.Kind, strconv.Itoa(u.Events), formatHours(u.ResourceHours)}
+}
+
+func formatHours(h float64) string {
+	return strconv.FormatFloat(h, 'f', 0, 64)
+}
+
+func formatCost(c float64) string {
+	return strconv.FormatFloat(c, 'f', 2, 64)
+}
@@ -29,8 +29,11 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/upbound/up/internal/usage"
+	"github.com/upbound/up/internal/usage/clientutil"
+	"github.com/upbound/up/internal/usage/clientutil/gcs"
 	"github.com/upbound/up/internal/usage/report"
 	reportaws "github.com/upbound/up/internal/usage/report/aws"
 	reporttar "github.com/upbound/up/internal/usage/report/file/tar"
@@ -43,6 +46,9 @@ const (
 	providerAzure = "azure"
 
 	errFmtProviderNotSupported = "%q is not supported"
+	errFmtProfileAWSOnly       = "--credentials-profile is not supported for %q"
+	errFmtGCPOnly              = "--gcp-billing-project and --gcp-encryption-key-file are not supported for %q"
+	errFmtAdaptiveGCPOnly      = "--adaptive-window is only supported for gcp, not %q"
 )
 
 type dateRange usage.TimeRange
@@ -74,6 +80,26 @@ func (d *dateRange) Decode(ctx *kong.DecodeContext) error {
 	return nil
 }
 
+// fileSize is a size in bytes that may be supplied as a Kubernetes quantity,
+// e.g. 100Mi.
+type fileSize int64
+
+func (s *fileSize) Decode(ctx *kong.DecodeContext) error {
+	var value string
+	if err := ctx.Scan.PopValueInto("file size", &value); err != nil {
+		return err
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid file size %q", value)
+	}
+	if q.Sign() < 0 {
+		return fmt.Errorf("invalid file size %q: must not be negative", value)
+	}
+	*s = fileSize(q.Value())
+	return nil
+}
+
 type provider string
 
 func (p provider) Validate() error {
@@ -89,20 +115,42 @@ func (p provider) Validate() error {
 }
 
 type getCmd struct {
-	Out string `optional:"" short:"o" env:"UP_BILLING_OUT" default:"upbound_billing_report.tgz" help:"Name of the output file."`
+	Out         string   `optional:"" short:"o" env:"UP_BILLING_OUT" default:"upbound_billing_report.tgz" help:"Name of the output file."`
+	MaxFileSize fileSize `env:"UP_BILLING_MAX_FILE_SIZE" help:"Split usage data in the report into numbered parts of at most this size, e.g. 100Mi. Zero writes a single usage file."`
+
+	AdaptiveWindow bool          `env:"UP_BILLING_ADAPTIVE_WINDOW" help:"Widen or narrow the window across which usage is aggregated according to the amount of usage data in each window. Windows wider than 1h report the maximum usage across the whole window. Only supported for gcp."`
+	MaxWindow      time.Duration `env:"UP_BILLING_MAX_WINDOW" default:"24h" help:"Widest window used with --adaptive-window. Set to 1h to keep hourly granularity."`
 
 	// TODO(branden): Make storage params optional and fetch missing values from spaces cluster.
 	Provider provider `required:"" enum:"aws,gcp,azure," env:"UP_BILLING_PROVIDER" group:"Storage" help:"Storage provider. Must be one of: aws, gcp, azure."`
 	Bucket   string   `required:"" env:"UP_BILLING_BUCKET" group:"Storage" help:"Storage bucket."`
 	Endpoint string   `env:"UP_BILLING_ENDPOINT" group:"Storage" help:"Custom storage endpoint."`
-	Account  string   `required:"" env:"UP_BILLING_ACCOUNT" group:"Storage" help:"Name of the Upbound account whose billing report is being collected."`
+	Account  []string `required:"" xor:"account" env:"UP_BILLING_ACCOUNT" group:"Storage" help:"Names of the Upbound accounts whose billing reports are being collected. A report is written for each account."`
+
+	AllAccounts bool `required:"" xor:"account" env:"UP_BILLING_ALL_ACCOUNTS" group:"Storage" help:"Collect billing reports for every account with usage data in the bucket."`
+
+	CredentialsFile    string `type:"existingfile" env:"UP_BILLING_CREDENTIALS_FILE" group:"Storage" help:"File containing explicit storage credentials: a service account key file for gcp, or a shared credentials file for aws. Ambient credentials are used if not set."`
+	CredentialsProfile string `env:"UP_BILLING_CREDENTIALS_PROFILE" group:"Storage" help:"Profile to use from the shared credentials file. Only supported for aws."`
+
+	GCPBillingProject    string `env:"UP_BILLING_GCP_BILLING_PROJECT" group:"Storage" help:"GCP project billed for reading a requester-pays bucket. Only supported for gcp."`
+	GCPEncryptionKeyFile string `type:"existingfile" env:"UP_BILLING_GCP_ENCRYPTION_KEY_FILE" group:"Storage" help:"File containing the customer-supplied AES-256 key, raw or base64 encoded, with which usage data is encrypted. Only supported for gcp."`
 
 	BillingMonth    time.Time  `format:"2006-01" required:"" xor:"billingperiod" env:"UP_BILLING_MONTH" group:"Billing period" help:"Get a report for a billing period of one calendar month. Format: 2006-01."`
 	BillingCustom   *dateRange `required:"" xor:"billingperiod" env:"UP_BILLING_CUSTOM" group:"Billing period" help:"Get a report for a custom billing period. Date range is inclusive. Format: 2006-01-02/2006-01-02."`
 	ForceIncomplete bool       `env:"UP_BILLING_FORCE_INCOMPLETE" group:"Billing period" help:"Get a report for an incomplete billing period."`
 
+	IncludeGVK []string `env:"UP_BILLING_INCLUDE_GVK" group:"Filter" help:"Only include usage for resources matching these patterns. Format: group[/version[/kind]]. Segments may contain wildcards, e.g. '*.aws.upbound.io'."`
+	ExcludeGVK []string `env:"UP_BILLING_EXCLUDE_GVK" group:"Filter" help:"Exclude usage for resources matching these patterns. Format: group[/version[/kind]]. Segments may contain wildcards, e.g. '*.crossplane.io'."`
+	IncludeMCP []string `env:"UP_BILLING_INCLUDE_MCP" group:"Filter" help:"Only include usage for control planes with these IDs. The ID of a control plane in a Space is the UID of its ControlPlane object."`
+	ExcludeMCP []string `env:"UP_BILLING_EXCLUDE_MCP" group:"Filter" help:"Exclude usage for control planes with these IDs. The ID of a control plane in a Space is the UID of its ControlPlane object."`
+
 	outAbs        string
+	accounts      []string
 	billingPeriod usage.TimeRange
+	filter        report.EventFilter
+	gcsBucket     gcs.BucketOptions
+	creds         clientutil.Credentials
+	opts          []report.GenerateOption
 }
 
 //go:embed get_help.txt
@@ -126,22 +174,173 @@ func (c *getCmd) Validate() error {
 		return fmt.Errorf("billing period is incomplete, use --force-incomplete to continue")
 	}
 
-	// Validate output filename.
+	// Parse event filters.
+	c.filter = report.EventFilter{
+		IncludeMCPs: c.IncludeMCP,
+		ExcludeMCPs: c.ExcludeMCP,
+	}
+	if c.filter.IncludeGVKs, err = parseGVKPatterns(c.IncludeGVK); err != nil {
+		return err
+	}
+	if c.filter.ExcludeGVKs, err = parseGVKPatterns(c.ExcludeGVK); err != nil {
+		return err
+	}
+
+	// Read storage credentials.
+	if c.CredentialsProfile != "" && c.Provider != providerAWS {
+		return fmt.Errorf(errFmtProfileAWSOnly, c.Provider)
+	}
+	c.creds = clientutil.Credentials{
+		File:    c.CredentialsFile,
+		Profile: c.CredentialsProfile,
+	}
+
+	// Read GCS bucket options.
+	if c.Provider != providerGCP && (c.GCPBillingProject != "" || c.GCPEncryptionKeyFile != "") {
+		return fmt.Errorf(errFmtGCPOnly, c.Provider)
+	}
+	c.gcsBucket.BillingProject = c.GCPBillingProject
+	if c.GCPEncryptionKeyFile != "" {
+		b, err := os.ReadFile(c.GCPEncryptionKeyFile)
+		if err != nil {
+			return errors.Wrap(err, "error reading encryption key")
+		}
+		if c.gcsBucket.EncryptionKey, err = gcs.ParseEncryptionKey(b); err != nil {
+			return err
+		}
+	}
+
+	// Configure adaptive window.
+	if c.AdaptiveWindow {
+		if c.Provider != providerGCP {
+			return fmt.Errorf(errFmtAdaptiveGCPOnly, c.Provider)
+		}
+		a := clientutil.DefaultAdaptiveWindow
+		a.Max = c.MaxWindow
+		if err := a.Validate(); err != nil {
+			return errors.Wrap(err, "invalid --max-window")
+		}
+		c.opts = append(c.opts, report.WithAdaptiveWindow(a))
+	}
+
+	// Validate output filename. Output filenames for all accounts are only
+	// known once accounts have been listed.
 	c.outAbs, err = filepath.Abs(c.Out)
 	if err != nil {
 		return err
 	}
-	_, err = os.Stat(c.outAbs)
-	if !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("file \"%s\" already exists", c.Out)
+	if c.AllAccounts {
+		return nil
+	}
+	c.accounts = c.Account
+	return c.validateOutputs()
+}
+
+// validateOutputs returns an error if any output file already exists.
+func (c *getCmd) validateOutputs() error {
+	outs := []string{}
+	for _, a := range c.accounts {
+		outs = append(outs, c.accountOut(a))
+	}
+	if len(c.accounts) > 1 {
+		outs = append(outs, c.summaryOut())
+	}
+	for _, out := range outs {
+		_, err := os.Stat(out)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("file \"%s\" already exists", out)
+		}
+	}
+	return nil
+}
+
+// accountOut returns the output filename of the report for an account. The
+// output filename is used as is when a single account is collected, and
+// suffixed with the account name otherwise.
+func (c *getCmd) accountOut(account string) string {
+	if len(c.accounts) == 1 {
+		return c.outAbs
+	}
+	base, ext := splitExt(c.outAbs)
+	return fmt.Sprintf("%s_%s%s", base, account, ext)
+}
+
+// summaryOut returns the filename of the summary written when several
+// accounts are collected.
+func (c *getCmd) summaryOut() string {
+	base, _ := splitExt(c.outAbs)
+	return base + "_summary.json"
+}
+
+// splitExt splits the extension from a filename, treating .tar.gz as a single
+// extension.
+func splitExt(name string) (string, string) {
+	if base, ok := strings.CutSuffix(name, ".tar.gz"); ok {
+		return base, ".tar.gz"
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext), ext
+}
+
+func (c *getCmd) Run(ctx context.Context) error {
+	if c.AllAccounts {
+		accounts, err := c.listAccounts(ctx)
+		if err != nil {
+			return err
+		}
+		if len(accounts) == 0 {
+			return fmt.Errorf("no accounts found in bucket %s", c.Bucket)
+		}
+		c.accounts = accounts
+		if err := c.validateOutputs(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.accounts) == 1 {
+		_, err := c.getReport(ctx, c.accounts[0])
+		return err
+	}
+
+	sum := summary{
+		TimeRange:   c.billingPeriod,
+		CollectedAt: time.Now(),
+		Accounts:    []accountSummary{},
+	}
+	failed := 0
+	for _, a := range c.accounts {
+		as := accountSummary{Account: a, File: c.accountOut(a)}
+		m, err := c.getReport(ctx, a)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error getting billing report for account %s: %s\n", a, err)
+			as.File = ""
+			as.Error = err.Error()
+			failed++
+		} else {
+			as.Events = m.Events
+			sum.Events += m.Events
+		}
+		sum.Accounts = append(sum.Accounts, as)
+		fmt.Printf("\n")
+	}
+	if err := writeSummary(c.summaryOut(), sum); err != nil {
+		return err
+	}
+	printSummary(sum)
+	fmt.Printf("Summary saved to %s\n", c.summaryOut())
+	if failed > 0 {
+		return fmt.Errorf("failed to get billing reports for %d of %d accounts", failed, len(c.accounts))
 	}
 	return nil
 }
 
-func (c *getCmd) Run() error {
+// getReport collects the billing report for an account and returns its
+// manifest.
+func (c *getCmd) getReport(ctx context.Context, account string) (reporttar.Manifest, error) {
+	out := c.accountOut(account)
 	fmt.Printf(
 		"Getting billing report for Upbound account %s from %s to %s.\n",
-		c.Account,
+		account,
 		formatTimestamp(c.billingPeriod.Start),
 		formatTimestamp(c.billingPeriod.End),
 	)
@@ -153,65 +352,103 @@ func (c *getCmd) Run() error {
 		fmt.Printf("Endpoint: %s\n", c.Endpoint)
 	}
 
-	if err := c.collectReport(); err != nil {
-		c.cleanupOnError()
-		return err
+	m, err := c.collectReport(ctx, account, out)
+	if err != nil {
+		cleanupOnError(out)
+		return reporttar.Manifest{}, err
 	}
 
 	fmt.Printf("\n")
-	fmt.Printf("Billing report saved to %s\n", c.outAbs)
-	return nil
+	fmt.Printf("Billing report saved to %s\n", out)
+	return m, nil
 }
 
-func (c *getCmd) cleanupOnError() {
-	if err := os.Remove(c.outAbs); err != nil {
+func (c *getCmd) listAccounts(ctx context.Context) ([]string, error) {
+	switch c.Provider {
+	case providerGCP:
+		return reportgcs.Accounts(ctx, c.Endpoint, c.Bucket, c.creds, c.gcsBucket)
+	case providerAWS:
+		return reportaws.Accounts(ctx, c.Endpoint, c.Bucket, c.creds)
+	default:
+		return nil, fmt.Errorf(errFmtProviderNotSupported, c.Provider)
+	}
+}
+
+func cleanupOnError(out string) {
+	if err := os.Remove(out); err != nil {
 		fmt.Fprintf(os.Stderr, "error cleaning up: %s", err)
 	}
 }
 
-func (c *getCmd) collectReport() error {
-	f, err := os.Create(c.outAbs)
+func (c *getCmd) collectReport(ctx context.Context, account, out string) (reporttar.Manifest, error) {
+	f, err := os.Create(out)
 	if err != nil {
-		return errors.Wrap(err, "error creating report")
+		return reporttar.Manifest{}, errors.Wrap(err, "error creating report")
 	}
 	defer f.Close() // nolint:errcheck
 
 	gw := gzip.NewWriter(f)
 	tw := tar.NewWriter(gw)
 
-	rw, err := reporttar.NewWriter(tw, report.Meta{
-		UpboundAccount: c.Account,
+	meta := report.Meta{
+		UpboundAccount: account,
 		TimeRange:      c.billingPeriod,
 		CollectedAt:    time.Now(),
-	})
+	}
+	if !c.filter.Empty() {
+		filter := c.filter
+		meta.Filter = &filter
+	}
+	rw, err := reporttar.NewWriter(tw, meta, reporttar.WithMaxFileSize(int64(c.MaxFileSize)))
 	if err != nil {
-		return errors.Wrap(err, "error creating report")
+		return reporttar.Manifest{}, errors.Wrap(err, "error creating report")
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	var w report.MCPGVKEventWriter = rw
+	if !c.filter.Empty() {
+		w = report.NewFilterWriter(rw, c.filter)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
 	defer stop()
 
+	bar := &progressBar{}
+	defer bar.stop()
+	opts := append([]report.GenerateOption{report.WithProgress(bar.update)}, c.opts...)
+
 	// TODO(branden): Add support for Azure.
 	switch {
 	case c.Provider == providerGCP:
-		if err := reportgcs.GenerateReport(ctx, c.Account, c.Endpoint, c.Bucket, c.billingPeriod, time.Hour, rw); err != nil {
-			return err
+		if err := reportgcs.GenerateReport(ctx, account, c.Endpoint, c.Bucket, c.creds, c.gcsBucket, c.billingPeriod, time.Hour, w, opts...); err != nil {
+			return reporttar.Manifest{}, err
 		}
 	case c.Provider == providerAWS:
-		if err := reportaws.GenerateReport(ctx, c.Account, c.Endpoint, c.Bucket, c.billingPeriod, rw); err != nil {
-			return err
+		if err := reportaws.GenerateReport(ctx, account, c.Endpoint, c.Bucket, c.creds, c.billingPeriod, w, opts...); err != nil {
+			return reporttar.Manifest{}, err
 		}
 	default:
-		return fmt.Errorf(errFmtProviderNotSupported, c.Provider)
+		return reporttar.Manifest{}, fmt.Errorf(errFmtProviderNotSupported, c.Provider)
 	}
 
 	if err := rw.Close(); err != nil {
-		return err
+		return reporttar.Manifest{}, err
 	}
 	if err := tw.Close(); err != nil {
-		return err
+		return reporttar.Manifest{}, err
+	}
+	return rw.Manifest(), gw.Close()
+}
+
+func parseGVKPatterns(patterns []string) ([]report.GVKPattern, error) {
+	out := make([]report.GVKPattern, 0, len(patterns))
+	for _, s := range patterns {
+		p, err := report.ParseGVKPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
 	}
-	return gw.Close()
+	return out, nil
 }
 
 func (c *getCmd) getBillingPeriod() (usage.TimeRange, error) {
@@ -92,3 +92,62 @@ func TestGetBillingPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountOut(t *testing.T) {
+	type args struct {
+		out      string
+		accounts []string
+		account  string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"SingleAccount": {
+			reason: "The output filename should be used as is for a single account.",
+			args: args{
+				out:      "/tmp/report.tgz",
+				accounts: []string{"acme"},
+				account:  "acme",
+			},
+			want: "/tmp/report.tgz",
+		},
+		"MultipleAccounts": {
+			reason: "The account name should be appended to the output filename for multiple accounts.",
+			args: args{
+				out:      "/tmp/report.tgz",
+				accounts: []string{"acme", "globex"},
+				account:  "globex",
+			},
+			want: "/tmp/report_globex.tgz",
+		},
+		"TarGz": {
+			reason: "The account name should be inserted before a .tar.gz extension.",
+			args: args{
+				out:      "/tmp/report.tar.gz",
+				accounts: []string{"acme", "globex"},
+				account:  "acme",
+			},
+			want: "/tmp/report_acme.tar.gz",
+		},
+		"NoExtension": {
+			reason: "The account name should be appended to an output filename without an extension.",
+			args: args{
+				out:      "/tmp/report",
+				accounts: []string{"acme", "globex"},
+				account:  "acme",
+			},
+			want: "/tmp/report_acme",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &getCmd{outAbs: tc.args.out, accounts: tc.args.accounts}
+			if diff := cmp.Diff(tc.want, c.accountOut(tc.args.account)); diff != "" {
+				t.Errorf("\n%s\naccountOut(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
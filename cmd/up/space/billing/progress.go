@@ -0,0 +1,58 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/upbound/up/internal/usage"
+)
+
+// progressBar shows the progress of reading usage data from storage. The bar
+// is started on the first update, once the total number of windows is known.
+type progressBar struct {
+	bar *pterm.ProgressbarPrinter
+}
+
+func (b *progressBar) update(p usage.Progress) {
+	if b.bar == nil {
+		bar, err := pterm.DefaultProgressbar.
+			WithTotal(p.WindowsTotal).
+			WithTitle(progressTitle(p)).
+			Start()
+		if err != nil {
+			return
+		}
+		b.bar = bar
+	}
+	b.bar.UpdateTitle(progressTitle(p))
+	if n := p.WindowsCompleted - b.bar.Current; n > 0 {
+		b.bar.Add(n)
+	}
+}
+
+func (b *progressBar) stop() {
+	if b.bar == nil {
+		return
+	}
+	_, _ = b.bar.Stop()
+}
+
+func progressTitle(p usage.Progress) string {
+	return fmt.Sprintf("%d events, %s read", p.EventsDecoded, resource.NewQuantity(p.BytesRead, resource.BinarySI))
+}
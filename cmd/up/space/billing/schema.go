@@ -0,0 +1,142 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"time"
+
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/usage/estimate"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+// billEstimateV1 is version v1 of the machine output of the estimated bill for
+// a billing report.
+type billEstimateV1 struct {
+	Account       string                   `json:"account"`
+	Start         time.Time                `json:"start"`
+	End           time.Time                `json:"end"`
+	Effective     string                   `json:"effective,omitempty"`
+	Filter        *eventFilterV1           `json:"filter,omitempty"`
+	Currency      string                   `json:"currency"`
+	ResourceHours float64                  `json:"resourceHours"`
+	Total         float64                  `json:"total"`
+	ControlPlanes []controlPlaneEstimateV1 `json:"controlPlanes"`
+	Uncovered     []uncoveredUsageV1       `json:"uncovered"`
+}
+
+// eventFilterV1 is version v1 of the machine output of the filter that
+// selected the usage in a billing report.
+type eventFilterV1 struct {
+	IncludeGVKs []string `json:"includeGVKs,omitempty"`
+	ExcludeGVKs []string `json:"excludeGVKs,omitempty"`
+	IncludeMCPs []string `json:"includeMCPs,omitempty"`
+	ExcludeMCPs []string `json:"excludeMCPs,omitempty"`
+}
+
+// controlPlaneEstimateV1 is version v1 of the machine output of the estimated
+// cost of the usage of a control plane.
+type controlPlaneEstimateV1 struct {
+	ID            string  `json:"id"`
+	ResourceHours float64 `json:"resourceHours"`
+	Cost          float64 `json:"cost"`
+}
+
+// uncoveredUsageV1 is version v1 of the machine output of the usage of a kind
+// of resource that a price list does not cover.
+type uncoveredUsageV1 struct {
+	Group         string  `json:"group"`
+	Version       string  `json:"version"`
+	Kind          string  `json:"kind"`
+	Events        int     `json:"events"`
+	ResourceHours float64 `json:"resourceHours"`
+}
+
+func init() {
+	upterm.RegisterSchema(billEstimate{}, upterm.Schema{
+		Kind:    "BillEstimate",
+		Version: "v1",
+		Convert: convertBillEstimateV1,
+	})
+	upterm.RegisterSchema(estimate.ControlPlane{}, upterm.Schema{
+		Kind:    "ControlPlaneEstimate",
+		Version: "v1",
+		Convert: func(obj any) any {
+			return convertControlPlaneEstimateV1(obj.(estimate.ControlPlane))
+		},
+	})
+	upterm.RegisterSchema(estimate.Uncovered{}, upterm.Schema{
+		Kind:    "UncoveredUsage",
+		Version: "v1",
+		Convert: func(obj any) any {
+			return convertUncoveredUsageV1(obj.(estimate.Uncovered))
+		},
+	})
+}
+
+func convertBillEstimateV1(obj any) any {
+	be := obj.(billEstimate)
+	est := be.Estimate
+	v := billEstimateV1{
+		Account:       be.UpboundAccount,
+		Start:         be.Start.UTC(),
+		End:           be.End.UTC(),
+		Effective:     be.Effective,
+		Filter:        convertEventFilterV1(be.Filter),
+		Currency:      est.Currency,
+		ResourceHours: est.ResourceHours,
+		Total:         est.Total,
+		ControlPlanes: make([]controlPlaneEstimateV1, len(est.ControlPlanes)),
+		Uncovered:     make([]uncoveredUsageV1, len(est.Uncovered)),
+	}
+	for i, cp := range est.ControlPlanes {
+		v.ControlPlanes[i] = convertControlPlaneEstimateV1(cp)
+	}
+	for i, u := range est.Uncovered {
+		v.Uncovered[i] = convertUncoveredUsageV1(u)
+	}
+	return v
+}
+
+func convertEventFilterV1(f *report.EventFilter) *eventFilterV1 {
+	if f == nil {
+		return nil
+	}
+	return &eventFilterV1{
+		IncludeGVKs: gvkStrings(f.IncludeGVKs),
+		ExcludeGVKs: gvkStrings(f.ExcludeGVKs),
+		IncludeMCPs: f.IncludeMCPs,
+		ExcludeMCPs: f.ExcludeMCPs,
+	}
+}
+
+func gvkStrings(ps []report.GVKPattern) []string {
+	if len(ps) == 0 {
+		return nil
+	}
+	s := make([]string, len(ps))
+	for i, p := range ps {
+		s[i] = p.String()
+	}
+	return s
+}
+
+func convertControlPlaneEstimateV1(cp estimate.ControlPlane) controlPlaneEstimateV1 {
+	return controlPlaneEstimateV1{ID: cp.ID, ResourceHours: cp.ResourceHours, Cost: cp.Cost}
+}
+
+func convertUncoveredUsageV1(u estimate.Uncovered) uncoveredUsageV1 {
+	return uncoveredUsageV1{Group: u.Group, Version: u.Version, Kind: u.Kind, Events: u.Events, ResourceHours: u.ResourceHours}
+}
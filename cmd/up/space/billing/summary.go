@@ -0,0 +1,67 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/usage"
+)
+
+// summary summarizes the billing reports collected for several accounts.
+type summary struct {
+	TimeRange   usage.TimeRange  `json:"time_range"`
+	CollectedAt time.Time        `json:"collected_at"`
+	Events      int              `json:"events"`
+	Accounts    []accountSummary `json:"accounts"`
+}
+
+// accountSummary summarizes the billing report collected for an account.
+type accountSummary struct {
+	Account string `json:"account"`
+	File    string `json:"file,omitempty"`
+	Events  int    `json:"events"`
+	Error   string `json:"error,omitempty"`
+}
+
+// writeSummary writes a summary as JSON to the named file.
+func writeSummary(name string, s summary) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding summary")
+	}
+	return errors.Wrap(os.WriteFile(name, b, 0644), "error writing summary")
+}
+
+// printSummary prints a table of the accounts in a summary.
+func printSummary(s summary) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCOUNT\tEVENTS\tREPORT")
+	for _, a := range s.Accounts {
+		report := a.File
+		if a.Error != "" {
+			report = "failed: " + a.Error
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", a.Account, a.Events, report)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t\n", s.Events)
+	_ = tw.Flush()
+}
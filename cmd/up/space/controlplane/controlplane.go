@@ -0,0 +1,42 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"github.com/alecthomas/kong"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/spaces"
+)
+
+// AfterApply constructs and binds a control plane client to any subcommands
+// that have Run() methods that receive it.
+func (c *Cmd) AfterApply(kongCtx *kong.Context, insCtx *install.Context) error {
+	dc, err := dynamic.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(spaces.NewControlPlaneClient(dc))
+	return nil
+}
+
+// Cmd contains commands for managing ControlPlane resources in a Space.
+type Cmd struct {
+	Create createCmd `cmd:"" help:"Create a control plane in the Space."`
+	Delete deleteCmd `cmd:"" help:"Delete a control plane in the Space."`
+	List   listCmd   `cmd:"" help:"List control planes in the Space."`
+	Get    getCmd    `cmd:"" help:"Get a single control plane in the Space."`
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/upbound/up/internal/spaces"
+)
+
+const errInvalidLabels = "invalid control plane labels"
+
+// AfterApply validates the create command after assignment.
+func (c *createCmd) AfterApply() error {
+	if _, err := labels.ValidatedSelectorFromSet(c.Labels); err != nil {
+		return errors.Wrap(err, errInvalidLabels)
+	}
+	return nil
+}
+
+// createCmd creates a ControlPlane resource in a Space.
+type createCmd struct {
+	Name string `arg:"" required:"" help:"Name of control plane."`
+
+	CrossplaneVersion string            `help:"Version of Crossplane to run in the control plane. Defaults to the version chosen by the Space."`
+	Class             string            `help:"Resource class of the control plane, which determines the resources allocated to it. Defaults to the class chosen by the Space."`
+	Labels            map[string]string `help:"Labels for the control plane in the form key=value. May be repeated."`
+}
+
+// Run executes the create command.
+func (c *createCmd) Run(ctx context.Context, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	if _, err := sc.Create(ctx, c.Name, spaces.ControlPlaneOptions{
+		Labels:            c.Labels,
+		CrossplaneVersion: c.CrossplaneVersion,
+		Class:             c.Class,
+	}); err != nil {
+		return err
+	}
+	p.Printfln("%s created", c.Name)
+	return nil
+}
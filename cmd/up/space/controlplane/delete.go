@@ -0,0 +1,37 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/spaces"
+)
+
+// deleteCmd deletes a ControlPlane resource in a Space.
+type deleteCmd struct {
+	Name string `arg:"" required:"" help:"Name of control plane."`
+}
+
+// Run executes the delete command.
+func (c *deleteCmd) Run(ctx context.Context, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	if err := sc.Delete(ctx, c.Name); err != nil {
+		return err
+	}
+	p.Printfln("%s deleted", c.Name)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/alecthomas/kong"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *getCmd) AfterApply(kongCtx *kong.Context) error {
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// getCmd gets a single ControlPlane resource in a Space.
+type getCmd struct {
+	Name string `arg:"" required:"" help:"Name of control plane."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the get command.
+func (c *getCmd) PrintedObjects() []any {
+	return []any{resources.ControlPlane{}}
+}
+
+// Run executes the get command.
+func (c *getCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, sc *spaces.ControlPlaneClient) error {
+	ctp, err := sc.Get(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+	return printer.Print(*ctp, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+}
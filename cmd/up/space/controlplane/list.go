@@ -0,0 +1,64 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"context"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const errParseSelector = "unable to parse label selector"
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *listCmd) AfterApply(kongCtx *kong.Context) error {
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	if _, err := labels.Parse(c.Selector); err != nil {
+		return errors.Wrap(err, errParseSelector)
+	}
+	return nil
+}
+
+// listCmd lists ControlPlane resources in a Space.
+type listCmd struct {
+	Selector string `short:"l" help:"Only list control planes with labels matching this selector, e.g. env=prod,team!=payments."`
+
+	Output upterm.OutputFlags `embed:""`
+}
+
+// PrintedObjects returns the objects printed by the list command.
+func (c *listCmd) PrintedObjects() []any {
+	return []any{resources.ControlPlane{}}
+}
+
+// Run executes the list command.
+func (c *listCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter, sc *spaces.ControlPlaneClient) error {
+	ctps, err := sc.List(ctx, c.Selector)
+	if err != nil {
+		return err
+	}
+	if len(ctps) == 0 {
+		p.Printfln("No control planes found")
+		return nil
+	}
+	return printer.Print(ctps, spaces.ControlPlaneFieldNames, spaces.ExtractControlPlaneFields)
+}
@@ -15,8 +15,6 @@
 package space
 
 import (
-	"github.com/pterm/pterm"
-
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
 	"github.com/upbound/up/internal/upterm"
@@ -25,7 +23,7 @@ import (
 // AfterApply sets default values in command after assignment and validation.
 func (c *destroyCmd) AfterApply(insCtx *install.Context) error {
 	// NOTE(tnthornton) we currently only have support for stylized output.
-	pterm.EnableStyling()
+	upterm.EnableStyling()
 	upterm.DefaultObjPrinter.Pretty = true
 
 	mgr, err := helm.NewManager(insCtx.Kubeconfig,
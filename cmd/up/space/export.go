@@ -0,0 +1,181 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/up/internal/kube"
+)
+
+const (
+	exportFormatRaw         = "raw"
+	exportFormatHelmRelease = "helmrelease"
+
+	// fluxInterval is the reconcile interval of exported Flux resources.
+	fluxInterval = "10m"
+
+	fileNamespace      = "namespace.yaml"
+	filePullSecret     = "pull-secret.yaml"
+	fileSpaces         = "spaces.yaml"
+	fileHelmRepository = "helmrepository.yaml"
+	fileHelmRelease    = "helmrelease.yaml"
+	fileKustomization  = "kustomization.yaml"
+
+	errCreateExportDir    = "unable to create export directory"
+	errRenderManifests    = "unable to render Spaces manifests"
+	errFmtWriteExportFile = "unable to write %s"
+)
+
+// exportManifests writes the manifests that init would apply to the export
+// directory instead of applying them, along with a kustomization that lists
+// them. Depending on the export format the chart is either rendered to raw
+// manifests or referenced by a Flux HelmRelease.
+func (c *initCmd) exportManifests(params map[string]any) error {
+	if err := os.MkdirAll(c.ExportManifests, 0755); err != nil {
+		return errors.Wrap(err, errCreateExportDir)
+	}
+	secret, err := kube.BuildImagePullSecret(defaultImagePullSecret, ns, c.id, c.token, c.Registry.String())
+	if err != nil {
+		return errors.Wrap(err, errCreateImagePullSecret)
+	}
+	files := []exportFile{
+		{name: fileNamespace, obj: &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: ns},
+		}},
+		{name: filePullSecret, obj: secret, sensitive: true},
+	}
+
+	switch c.ExportFormat {
+	case exportFormatHelmRelease:
+		files = append(files,
+			exportFile{name: fileHelmRepository, obj: c.helmRepository()},
+			exportFile{name: fileHelmRelease, obj: c.helmRelease(params)},
+		)
+	default:
+		manifests, err := c.helmMgr.Render(strings.TrimPrefix(c.Version, "v"), params)
+		if err != nil {
+			return errors.Wrap(err, errRenderManifests)
+		}
+		files = append(files, exportFile{name: fileSpaces, raw: manifests})
+	}
+
+	resources := make([]string, len(files))
+	for i, f := range files {
+		resources[i] = f.name
+	}
+	files = append(files, exportFile{name: fileKustomization, obj: map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  resources,
+	}})
+
+	for _, f := range files {
+		if err := f.write(c.ExportManifests); err != nil {
+			return err
+		}
+	}
+	pterm.Info.Printfln("Spaces manifests exported to %s", c.ExportManifests)
+	pterm.Warning.Printfln("%s contains registry credentials, encrypt it before committing it to version control", filepath.Join(c.ExportManifests, filePullSecret))
+	return nil
+}
+
+// helmRepository builds a Flux HelmRepository for the Spaces chart registry.
+func (c *initCmd) helmRepository() map[string]any {
+	return map[string]any{
+		"apiVersion": "source.toolkit.fluxcd.io/v1beta2",
+		"kind":       "HelmRepository",
+		"metadata": map[string]any{
+			"name":      spacesChart,
+			"namespace": ns,
+		},
+		"spec": map[string]any{
+			"type":     "oci",
+			"url":      "oci://" + c.Repo.String(),
+			"interval": fluxInterval,
+			"secretRef": map[string]any{
+				"name": defaultImagePullSecret,
+			},
+		},
+	}
+}
+
+// helmRelease builds a Flux HelmRelease that installs the Spaces chart with
+// the supplied parameters.
+func (c *initCmd) helmRelease(params map[string]any) map[string]any {
+	return map[string]any{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2beta1",
+		"kind":       "HelmRelease",
+		"metadata": map[string]any{
+			"name":      spacesChart,
+			"namespace": ns,
+		},
+		"spec": map[string]any{
+			"interval":        fluxInterval,
+			"releaseName":     spacesChart,
+			"targetNamespace": ns,
+			"chart": map[string]any{
+				"spec": map[string]any{
+					"chart":   spacesChart,
+					"version": strings.TrimPrefix(c.Version, "v"),
+					"sourceRef": map[string]any{
+						"kind": "HelmRepository",
+						"name": spacesChart,
+					},
+				},
+			},
+			"install": map[string]any{
+				"crds": "CreateReplace",
+			},
+			"upgrade": map[string]any{
+				"crds": "CreateReplace",
+			},
+			"values": params,
+		},
+	}
+}
+
+// exportFile is a file written to the export directory. It holds either an
+// object that is marshalled to YAML or raw manifests.
+type exportFile struct {
+	name      string
+	obj       any
+	raw       []byte
+	sensitive bool
+}
+
+func (f exportFile) write(dir string) error {
+	b := f.raw
+	if f.obj != nil {
+		var err error
+		if b, err = yaml.Marshal(f.obj); err != nil {
+			return errors.Wrapf(err, errFmtWriteExportFile, f.name)
+		}
+	}
+	perm := os.FileMode(0644)
+	if f.sensitive {
+		perm = 0600
+	}
+	return errors.Wrapf(os.WriteFile(filepath.Join(dir, f.name), b, perm), errFmtWriteExportFile, f.name)
+}
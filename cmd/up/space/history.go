@@ -0,0 +1,72 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/upterm"
+)
+
+var historyFieldNames = []string{"REVISION", "VERSION", "STATUS", "UPDATED", "DESCRIPTION"}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *historyCmd) AfterApply(insCtx *install.Context) error {
+	mgr, err := helm.NewManager(insCtx.Kubeconfig,
+		spacesChart,
+		c.Repo,
+		helm.WithNamespace(ns),
+		helm.IsOCI())
+	if err != nil {
+		return err
+	}
+	c.mgr = mgr
+	return nil
+}
+
+// historyCmd shows the release history of the Upbound Spaces deployment.
+type historyCmd struct {
+	mgr install.Manager
+
+	commonParams
+}
+
+// PrintedObjects returns the objects printed by the history command.
+func (c *historyCmd) PrintedObjects() []any {
+	return []any{install.Revision{}}
+}
+
+// Run executes the history command.
+func (c *historyCmd) Run(p pterm.TextPrinter, printer upterm.ObjectPrinter) error {
+	revs, err := c.mgr.History()
+	if err != nil {
+		return err
+	}
+	if len(revs) == 0 {
+		p.Println("No release history found for Space")
+		return nil
+	}
+	return printer.Print(revs, historyFieldNames, extractHistoryFields)
+}
+
+func extractHistoryFields(obj any) []string {
+	r := obj.(install.Revision)
+	return []string{strconv.Itoa(r.Revision), r.Version, r.Status, r.Updated.Format(time.RFC3339), r.Description}
+}
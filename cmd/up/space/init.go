@@ -17,7 +17,6 @@ package space
 import (
 	"context"
 	"fmt"
-	"io"
 	"strings"
 	"time"
 
@@ -34,7 +33,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/yaml"
 
 	"github.com/upbound/up/cmd/up/space/prerequisites"
 	"github.com/upbound/up/internal/config"
@@ -43,6 +41,7 @@ import (
 	"github.com/upbound/up/internal/install/helm"
 	"github.com/upbound/up/internal/kube"
 	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/secrets"
 	"github.com/upbound/up/internal/upbound"
 	"github.com/upbound/up/internal/upterm"
 )
@@ -72,7 +71,6 @@ const (
 
 	jsonKey = "_json_key"
 
-	errReadTokenFile          = "unable to read token file"
 	errReadParametersFile     = "unable to read parameters file"
 	errParseInstallParameters = "unable to parse install parameters"
 	errGetRegistryToken       = "failed to acquire auth token"
@@ -83,6 +81,7 @@ const (
 	errUpdateConfig           = "unable to update config"
 
 	errFmtCreateNamespace = "failed to create namespace %s"
+	errMirrorHelmRelease  = "--image-mirror and --image-mirror-map cannot be combined with --export-format=helmrelease"
 )
 
 func init() {
@@ -98,9 +97,9 @@ func (c *initCmd) BeforeApply() error {
 }
 
 // AfterApply sets default values in command after assignment and validation.
-func (c *initCmd) AfterApply(insCtx *install.Context, kongCtx *kong.Context, quiet config.QuietFlag) error { //nolint:gocyclo
+func (c *initCmd) AfterApply(ctx context.Context, insCtx *install.Context, kongCtx *kong.Context, quiet config.QuietFlag) error { //nolint:gocyclo
 	// NOTE(tnthornton) we currently only have support for stylized output.
-	pterm.EnableStyling()
+	upterm.EnableStyling()
 	upterm.DefaultObjPrinter.Pretty = true
 
 	upCtx, err := upbound.NewFromFlags(c.Flags)
@@ -109,12 +108,12 @@ func (c *initCmd) AfterApply(insCtx *install.Context, kongCtx *kong.Context, qui
 	}
 	kongCtx.Bind(upCtx)
 
-	b, err := io.ReadAll(c.TokenFile)
-	defer c.TokenFile.Close() // nolint:errcheck
+	resolver := secrets.NewResolver()
+	token, err := c.Token(ctx, resolver)
 	if err != nil {
-		return errors.Wrap(err, errReadTokenFile)
+		return err
 	}
-	c.token = string(b)
+	c.token = token
 	prereqs, err := prerequisites.New(insCtx.Kubeconfig)
 	if err != nil {
 		return err
@@ -133,6 +132,15 @@ func (c *initCmd) AfterApply(insCtx *install.Context, kongCtx *kong.Context, qui
 		return err
 	}
 	c.dClient = dClient
+	mirror, err := c.Mirror()
+	if err != nil {
+		return err
+	}
+	// Exported HelmReleases reference the chart without rendering it, so
+	// its images cannot be rewritten.
+	if mirror != nil && c.ExportManifests != "" && c.ExportFormat == exportFormatHelmRelease {
+		return errors.New(errMirrorHelmRelease)
+	}
 	mgr, err := helm.NewManager(insCtx.Kubeconfig,
 		spacesChart,
 		c.Repo,
@@ -140,28 +148,31 @@ func (c *initCmd) AfterApply(insCtx *install.Context, kongCtx *kong.Context, qui
 		helm.WithBasicAuth(c.id, c.token),
 		helm.IsOCI(),
 		helm.WithChart(c.Bundle),
-		helm.Wait(),
+		helm.WithImageMirror(mirror),
+		helm.WithChartDigest(c.ChartDigest),
+		helm.WithChartKeyring(c.ChartKeyring),
+		helm.WithCacheDir(c.ChartCacheDir),
+		helm.WithRedactedKeys(insCtx.RedactKeys...),
+		helm.WaitForReadiness(c.readiness.Report),
 	)
 	if err != nil {
 		return err
 	}
 	c.helmMgr = mgr
 
-	base := map[string]any{}
-	if c.File != nil {
-		defer c.File.Close() //nolint:errcheck,gosec
-		b, err := io.ReadAll(c.File)
-		if err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := yaml.Unmarshal(b, &base); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := c.File.Close(); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
+	}
+	defaults, err := profileValues(c.ValuesProfile)
+	if err != nil {
+		return err
 	}
-	c.parser = helm.NewParser(base, c.Set)
+	secretParams, err := c.SecretParameters(ctx, resolver)
+	if err != nil {
+		return err
+	}
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithDefaults(defaults), helm.WithLiteralOverrides(secretParams))
 	c.quiet = quiet
 	return nil
 }
@@ -175,29 +186,41 @@ type initCmd struct {
 	dClient    dynamic.Interface
 	prompter   input.Prompter
 	pullSecret *kube.ImagePullApplicator
+	readiness  readinessSpinner
 	id         string
 	token      string
 	quiet      config.QuietFlag
 
 	Version string `arg:"" help:"Upbound Spaces version to install."`
 
+	ExportManifests string `type:"path" placeholder:"DIR" help:"Write the manifests that install Spaces to this directory instead of applying them."`
+	ExportFormat    string `enum:"raw,helmrelease" default:"raw" help:"Format of exported manifests. raw renders the chart to plain manifests, helmrelease references it from a Flux HelmRelease."`
+
 	commonParams
+	scanParams
 	install.CommonParams
 
 	Flags upbound.Flags `embed:""`
 }
 
 // Run executes the install command.
-func (c *initCmd) Run(insCtx *install.Context, upCtx *upbound.Context) error {
-	ctx := context.Background()
-
+func (c *initCmd) Run(ctx context.Context, insCtx *install.Context, upCtx *upbound.Context) error {
 	params, err := c.parser.Parse()
 	if err != nil {
 		return errors.Wrap(err, errParseInstallParameters)
 	}
 
+	if err := c.scanChart(ctx, c.helmMgr, c.Version, params, c.id, c.token); err != nil {
+		return err
+	}
+
+	// Nothing is applied to the cluster when exporting manifests.
+	if c.ExportManifests != "" {
+		return c.exportManifests(params)
+	}
+
 	// check if required prerequisites are installed
-	status := c.prereqs.Check()
+	status := c.prereqs.Check(ctx)
 
 	// At least 1 prerequisite is not installed, check if we should install the
 	// missing ones for the client.
@@ -217,7 +240,7 @@ func (c *initCmd) Run(insCtx *install.Context, upCtx *upbound.Context) error {
 			return nil
 		}
 
-		if err := c.installPrereqs(); err != nil {
+		if err := c.installPrereqs(ctx); err != nil {
 			return err
 		}
 	}
@@ -229,7 +252,7 @@ func (c *initCmd) Run(insCtx *install.Context, upCtx *upbound.Context) error {
 		return err
 	}
 
-	if err := c.deploySpace(context.Background(), params); err != nil {
+	if err := c.deploySpace(ctx, params); err != nil {
 		return err
 	}
 
@@ -239,9 +262,9 @@ func (c *initCmd) Run(insCtx *install.Context, upCtx *upbound.Context) error {
 	return nil
 }
 
-func (c *initCmd) installPrereqs() error {
+func (c *initCmd) installPrereqs(ctx context.Context) error {
 
-	status := c.prereqs.Check()
+	status := c.prereqs.Check(ctx)
 	for i, p := range status.NotInstalled {
 		if err := upterm.WrapWithSuccessSpinner(
 			upterm.StepCounter(
@@ -250,7 +273,7 @@ func (c *initCmd) installPrereqs() error {
 				len(status.NotInstalled),
 			),
 			upterm.CheckmarkSuccessSpinner,
-			p.Install,
+			func() error { return p.Install(ctx) },
 		); err != nil {
 			return err
 		}
@@ -304,9 +327,8 @@ func (c *initCmd) deploySpace(ctx context.Context, params map[string]any) error
 		return install()
 	}
 
-	if err := upterm.WrapWithSuccessSpinner(
+	if err := c.readiness.Wrap(
 		upterm.StepCounter("Initializing Space components", 2, 3),
-		upterm.CheckmarkSuccessSpinner,
 		install,
 	); err != nil {
 		return err
@@ -0,0 +1,160 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/metrics"
+)
+
+const (
+	metricsPortName        = "metrics"
+	defaultMetricsPath     = "/metrics"
+	annotationScrapePort   = "prometheus.io/port"
+	annotationScrapePath   = "prometheus.io/path"
+	metricsDurationUnknown = "-"
+
+	errListPods         = "unable to list pods"
+	errFmtScrapeMetrics = "unable to scrape metrics of pod %s"
+	errNoMetricsPods    = "no running pods exposing metrics found"
+	errMetricsInterval  = "--interval must be greater than zero"
+)
+
+// metricsCmd prints health indicators of Spaces components.
+type metricsCmd struct {
+	Namespace string        `short:"n" default:"upbound-system" help:"Namespace of the Spaces components."`
+	Selector  string        `short:"l" help:"Only scrape pods matching this label selector."`
+	Interval  time.Duration `default:"10s" help:"Time between the two scrapes that rates and latencies are computed from."`
+}
+
+// scrapeTarget is a pod exposing metrics.
+type scrapeTarget struct {
+	pod  string
+	port string
+	path string
+}
+
+// Run executes the metrics command.
+func (c *metricsCmd) Run(ctx context.Context, insCtx *install.Context) error {
+	if c.Interval <= 0 {
+		return errors.New(errMetricsInterval)
+	}
+	client, err := kubernetes.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	pods, err := client.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: c.Selector})
+	if err != nil {
+		return errors.Wrap(err, errListPods)
+	}
+	targets := scrapeTargets(pods.Items)
+	if len(targets) == 0 {
+		return errors.New(errNoMetricsPods)
+	}
+
+	// Metrics are scraped through the API server proxy, so no local ports
+	// need to be forwarded.
+	scrape := func(t scrapeTarget) (*metrics.Sample, error) {
+		b, err := client.CoreV1().Pods(c.Namespace).ProxyGet("http", t.pod, t.port, t.path, nil).DoRaw(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtScrapeMetrics, t.pod)
+		}
+		s, err := metrics.Parse(bytes.NewReader(b), time.Now())
+		return s, errors.Wrapf(err, errFmtScrapeMetrics, t.pod)
+	}
+	prev := make([]*metrics.Sample, len(targets))
+	for i, t := range targets {
+		if prev[i], err = scrape(t); err != nil {
+			return err
+		}
+	}
+	pterm.Info.Printfln("Scraped %d pods, scraping again in %s to compute rates...", len(targets), c.Interval)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(c.Interval):
+	}
+
+	ctrls := pterm.TableData{{"POD", "CONTROLLER", "RECONCILES/S", "ERRORS/S", "ERRORS", "QUEUE"}}
+	api := pterm.TableData{{"POD", "REQUESTS/S", "ERRORS/S", "MEAN LATENCY", "P99 LATENCY"}}
+	for i, t := range targets {
+		cur, err := scrape(t)
+		if err != nil {
+			return err
+		}
+		h := metrics.Summarize(prev[i], cur)
+		for _, ch := range h.Controllers {
+			ctrls = append(ctrls, []string{t.pod, ch.Controller, formatRate(ch.ReconcileRate), formatRate(ch.ErrorRate), strconv.FormatFloat(ch.Errors, 'f', -1, 64), strconv.FormatFloat(ch.QueueDepth, 'f', -1, 64)})
+		}
+		api = append(api, []string{t.pod, formatRate(h.API.RequestRate), formatRate(h.API.ErrorRate), formatLatency(h.API.MeanLatency), formatLatency(h.API.P99Latency)})
+	}
+
+	pterm.DefaultSection.Println("Controllers")
+	if err := pterm.DefaultTable.WithHasHeader().WithData(ctrls).Render(); err != nil {
+		return err
+	}
+	pterm.DefaultSection.Println("Kubernetes API requests")
+	return pterm.DefaultTable.WithHasHeader().WithData(api).Render()
+}
+
+// scrapeTargets returns the running pods that expose metrics, either on the
+// port given by the prometheus.io/port annotation or on a container port
+// named metrics.
+func scrapeTargets(pods []corev1.Pod) []scrapeTarget {
+	targets := []scrapeTarget{}
+	for _, p := range pods {
+		if p.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		t := scrapeTarget{pod: p.Name, port: p.Annotations[annotationScrapePort], path: p.Annotations[annotationScrapePath]}
+		if t.path == "" {
+			t.path = defaultMetricsPath
+		}
+		for _, ctr := range p.Spec.Containers {
+			for _, cp := range ctr.Ports {
+				if t.port == "" && cp.Name == metricsPortName {
+					t.port = strconv.Itoa(int(cp.ContainerPort))
+				}
+			}
+		}
+		if t.port != "" {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+func formatRate(r float64) string {
+	return fmt.Sprintf("%.2f", r)
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return metricsDurationUnknown
+	}
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/kube"
+)
+
+// portForwardCmd forwards local ports to a pod or service in the Spaces
+// cluster.
+type portForwardCmd struct {
+	Target string   `arg:"" required:"" help:"Pod or service to forward to in the form [pod/|svc/]name. Bare names refer to services."`
+	Ports  []string `arg:"" required:"" help:"Ports to forward in the form [local:]remote."`
+
+	Namespace string   `short:"n" default:"upbound-system" help:"Namespace of the pod or service."`
+	Address   []string `default:"localhost" help:"Addresses to listen on."`
+}
+
+// Unbounded returns true as the port-forward command runs until interrupted.
+func (c *portForwardCmd) Unbounded() bool {
+	return true
+}
+
+// Run executes the port-forward command.
+func (c *portForwardCmd) Run(ctx context.Context, kongCtx *kong.Context, insCtx *install.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return kube.PortForward(ctx, insCtx.Kubeconfig, kube.PortForwardOptions{
+		Namespace: c.Namespace,
+		Target:    c.Target,
+		Ports:     c.Ports,
+		Addresses: c.Address,
+		Out:       kongCtx.Stdout,
+		ErrOut:    kongCtx.Stderr,
+	})
+}
@@ -89,8 +89,8 @@ func (c *CertManager) GetName() string {
 }
 
 // Install performs a Helm install of the chart.
-func (c *CertManager) Install() error {
-	if c.IsInstalled() {
+func (c *CertManager) Install(ctx context.Context) error {
+	if c.IsInstalled(ctx) {
 		// nothing to do
 		return nil
 	}
@@ -98,7 +98,7 @@ func (c *CertManager) Install() error {
 	// create namespace before creating chart.
 	_, err := c.kclient.CoreV1().
 		Namespaces().
-		Create(context.Background(),
+		Create(ctx,
 			&corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: chartName,
@@ -112,11 +112,11 @@ func (c *CertManager) Install() error {
 }
 
 // IsInstalled checks if cert-manager has been installed in the target cluster.
-func (c *CertManager) IsInstalled() bool {
+func (c *CertManager) IsInstalled(ctx context.Context) bool {
 	_, err := c.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			certificatesCRD,
 			metav1.GetOptions{},
 		)
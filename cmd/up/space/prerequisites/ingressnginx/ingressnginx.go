@@ -124,8 +124,8 @@ func (c *IngressNginx) GetName() string {
 }
 
 // Install performs a Helm install of the chart.
-func (c *IngressNginx) Install() error { //nolint:gocyclo
-	if c.IsInstalled() {
+func (c *IngressNginx) Install(ctx context.Context) error { //nolint:gocyclo
+	if c.IsInstalled(ctx) {
 		// nothing to do
 		return nil
 	}
@@ -133,7 +133,7 @@ func (c *IngressNginx) Install() error { //nolint:gocyclo
 	// create namespace before creating chart.
 	_, err := c.kclient.CoreV1().
 		Namespaces().
-		Create(context.Background(),
+		Create(ctx,
 			&corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: chartName,
@@ -152,7 +152,7 @@ func (c *IngressNginx) Install() error { //nolint:gocyclo
 			AppsV1().
 			Deployments(chartName).
 			Get(
-				context.Background(),
+				ctx,
 				"ingress-nginx-controller",
 				metav1.GetOptions{},
 			)
@@ -169,12 +169,12 @@ func (c *IngressNginx) Install() error { //nolint:gocyclo
 }
 
 // IsInstalled checks if cert-manager has been installed in the target cluster.
-func (c *IngressNginx) IsInstalled() bool {
+func (c *IngressNginx) IsInstalled(ctx context.Context) bool {
 	il, err := c.kclient.
 		NetworkingV1().
 		IngressClasses().
 		List(
-			context.Background(),
+			ctx,
 			metav1.ListOptions{},
 		)
 
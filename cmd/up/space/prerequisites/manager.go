@@ -15,6 +15,8 @@
 package prerequisites
 
 import (
+	"context"
+
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"k8s.io/client-go/rest"
 
@@ -34,8 +36,8 @@ var (
 type Prerequisite interface {
 	GetName() string
 
-	Install() error
-	IsInstalled() bool
+	Install(ctx context.Context) error
+	IsInstalled(ctx context.Context) bool
 }
 
 // Manager provides APIs for interacting with Prerequisites within the target
@@ -90,10 +92,10 @@ func New(config *rest.Config) (*Manager, error) {
 
 // Check performs IsInstalled checks for each of the Prerequisites against the
 // target cluster.
-func (m *Manager) Check() *Status {
+func (m *Manager) Check(ctx context.Context) *Status {
 	notInstalled := []Prerequisite{}
 	for _, p := range m.prereqs {
-		if !p.IsInstalled() {
+		if !p.IsInstalled(ctx) {
 			notInstalled = append(notInstalled, p)
 		}
 	}
@@ -106,27 +106,27 @@ func (h *Helm) GetName() string {
 }
 
 // Install performs a kubectl apply of the package.
-func (h *Helm) Install() error { //nolint:gocyclo
-	if h.IsInstalled() {
+func (h *Helm) Install(ctx context.Context) error { //nolint:gocyclo
+	if h.IsInstalled(ctx) {
 		// nothing to do
 		return nil
 	}
 
-	if !h.isUXPInstalled() {
+	if !h.isUXPInstalled(ctx) {
 		return fmt.Errorf(errFmtUXPRequired, providerName)
 	}
 
-	if err := h.createServiceAccount(); err != nil {
+	if err := h.createServiceAccount(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
 	}
-	if err := h.createClusterRoleBinding(); err != nil {
+	if err := h.createClusterRoleBinding(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
 	}
-	if err := h.createControllerConfig(); err != nil {
+	if err := h.createControllerConfig(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
@@ -143,7 +143,7 @@ func (h *Helm) Install() error { //nolint:gocyclo
 	_, err := h.dClient.
 		Resource(pkgGVR).
 		Create(
-			context.Background(),
+			ctx,
 			p.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
@@ -151,9 +151,6 @@ func (h *Helm) Install() error { //nolint:gocyclo
 		return err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	for {
 		p, err := h.dClient.Resource(pkgGVR).Get(ctx, pkgName, metav1.GetOptions{})
 		if err != nil && !kerrors.IsNotFound(err) {
@@ -175,17 +172,20 @@ func (h *Helm) Install() error { //nolint:gocyclo
 			); err == nil {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 	}
 
-	return h.createProviderConfig()
+	return h.createProviderConfig(ctx)
 }
 
 // IsInstalled checks if provider-helm has been installed in the target cluster.
-func (h *Helm) IsInstalled() bool {
+func (h *Helm) IsInstalled(ctx context.Context) bool {
 	_, err := h.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			objectsCRD,
 			metav1.GetOptions{},
 		)
@@ -193,18 +193,18 @@ func (h *Helm) IsInstalled() bool {
 }
 
 // isUXPInstalled checks if UXP exists in the target cluster.
-func (h *Helm) isUXPInstalled() bool {
+func (h *Helm) isUXPInstalled(ctx context.Context) bool {
 	_, err := h.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			xrdCRD,
 			metav1.GetOptions{},
 		)
 	return !kerrors.IsNotFound(err)
 }
 
-func (h *Helm) createServiceAccount() error {
+func (h *Helm) createServiceAccount(ctx context.Context) error {
 	sa := &v1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ccName,
@@ -216,14 +216,14 @@ func (h *Helm) createServiceAccount() error {
 		CoreV1().
 		ServiceAccounts(ns).
 		Create(
-			context.Background(),
+			ctx,
 			sa,
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (h *Helm) createClusterRoleBinding() error {
+func (h *Helm) createClusterRoleBinding(ctx context.Context) error {
 	crb := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: ccName,
@@ -246,14 +246,14 @@ func (h *Helm) createClusterRoleBinding() error {
 		RbacV1().
 		ClusterRoleBindings().
 		Create(
-			context.Background(),
+			ctx,
 			crb,
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (h *Helm) createControllerConfig() error {
+func (h *Helm) createControllerConfig(ctx context.Context) error {
 	cc := &resources.ControllerConfig{}
 	cc.SetName(ccName)
 	cc.SetServiceAccountName(ccName)
@@ -262,14 +262,14 @@ func (h *Helm) createControllerConfig() error {
 	_, err := h.dClient.
 		Resource(resources.ControllerConfigGRV).
 		Create(
-			context.Background(),
+			ctx,
 			cc.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (h *Helm) createProviderConfig() error {
+func (h *Helm) createProviderConfig(ctx context.Context) error {
 	pc := &resources.ProviderConfig{}
 	pc.SetName("upbound-cluster")
 	pc.SetGroupVersionKind(resources.ProviderConfigHelmGVK)
@@ -278,7 +278,7 @@ func (h *Helm) createProviderConfig() error {
 	_, err := h.dClient.
 		Resource(resources.ProviderConfigHelmGVK.GroupVersion().WithResource("providerconfigs")).
 		Create(
-			context.Background(),
+			ctx,
 			pc.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
@@ -105,27 +105,27 @@ func (k *Kubernetes) GetName() string {
 }
 
 // Install performs a Helm install of the chart.
-func (k *Kubernetes) Install() error { //nolint:gocyclo
-	if k.IsInstalled() {
+func (k *Kubernetes) Install(ctx context.Context) error { //nolint:gocyclo
+	if k.IsInstalled(ctx) {
 		// nothing to do
 		return nil
 	}
 
-	if !k.isUXPInstalled() {
+	if !k.isUXPInstalled(ctx) {
 		return fmt.Errorf(errFmtUXPRequired, providerName)
 	}
 
-	if err := k.createServiceAccount(); err != nil {
+	if err := k.createServiceAccount(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
 	}
-	if err := k.createClusterRoleBinding(); err != nil {
+	if err := k.createClusterRoleBinding(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
 	}
-	if err := k.createControllerConfig(); err != nil {
+	if err := k.createControllerConfig(ctx); err != nil {
 		if !kerrors.IsAlreadyExists(err) {
 			return err
 		}
@@ -142,7 +142,7 @@ func (k *Kubernetes) Install() error { //nolint:gocyclo
 	_, err := k.dClient.
 		Resource(pkgGVR).
 		Create(
-			context.Background(),
+			ctx,
 			p.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
@@ -151,9 +151,6 @@ func (k *Kubernetes) Install() error { //nolint:gocyclo
 		return err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	for {
 		p, err := k.dClient.Resource(pkgGVR).Get(ctx, pkgName, metav1.GetOptions{})
 		if err != nil && !kerrors.IsNotFound(err) {
@@ -175,35 +172,38 @@ func (k *Kubernetes) Install() error { //nolint:gocyclo
 			); err == nil {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 	}
 
-	return k.createProviderConfig()
+	return k.createProviderConfig(ctx)
 }
 
 // IsInstalled checks if cert-manager has been installed in the target cluster.
-func (k *Kubernetes) IsInstalled() bool {
+func (k *Kubernetes) IsInstalled(ctx context.Context) bool {
 	_, err := k.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			objectsCRD,
 			metav1.GetOptions{},
 		)
 	return !kerrors.IsNotFound(err)
 }
 
-func (k *Kubernetes) isUXPInstalled() bool {
+func (k *Kubernetes) isUXPInstalled(ctx context.Context) bool {
 	_, err := k.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			xrdCRD,
 			metav1.GetOptions{},
 		)
 	return !kerrors.IsNotFound(err)
 }
 
-func (k *Kubernetes) createServiceAccount() error {
+func (k *Kubernetes) createServiceAccount(ctx context.Context) error {
 	sa := &v1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ccName,
@@ -215,14 +215,14 @@ func (k *Kubernetes) createServiceAccount() error {
 		CoreV1().
 		ServiceAccounts(ns).
 		Create(
-			context.Background(),
+			ctx,
 			sa,
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (k *Kubernetes) createClusterRoleBinding() error {
+func (k *Kubernetes) createClusterRoleBinding(ctx context.Context) error {
 	crb := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: ccName,
@@ -245,14 +245,14 @@ func (k *Kubernetes) createClusterRoleBinding() error {
 		RbacV1().
 		ClusterRoleBindings().
 		Create(
-			context.Background(),
+			ctx,
 			crb,
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (k *Kubernetes) createControllerConfig() error {
+func (k *Kubernetes) createControllerConfig(ctx context.Context) error {
 	cc := &resources.ControllerConfig{}
 	cc.SetName(ccName)
 	cc.SetServiceAccountName(ccName)
@@ -261,14 +261,14 @@ func (k *Kubernetes) createControllerConfig() error {
 	_, err := k.dClient.
 		Resource(resources.ControllerConfigGRV).
 		Create(
-			context.Background(),
+			ctx,
 			cc.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
 	return err
 }
 
-func (k *Kubernetes) createProviderConfig() error {
+func (k *Kubernetes) createProviderConfig(ctx context.Context) error {
 	pc := &resources.ProviderConfig{}
 	pc.SetName("upbound-cluster")
 	pc.SetGroupVersionKind(resources.ProviderConfigKubernetesGVK)
@@ -277,7 +277,7 @@ func (k *Kubernetes) createProviderConfig() error {
 	_, err := k.dClient.
 		Resource(resources.ProviderConfigKubernetesGVK.GroupVersion().WithResource("providerconfigs")).
 		Create(
-			context.Background(),
+			ctx,
 			pc.GetUnstructured(),
 			metav1.CreateOptions{},
 		)
@@ -88,15 +88,15 @@ func (u *UXP) GetName() string {
 }
 
 // Install performs a Helm install of the chart.
-func (u *UXP) Install() error {
-	if u.IsInstalled() {
+func (u *UXP) Install(ctx context.Context) error {
+	if u.IsInstalled(ctx) {
 		// nothing to do
 		return nil
 	}
 	// create namespace before creating chart.
 	_, err := u.kclient.CoreV1().
 		Namespaces().
-		Create(context.Background(),
+		Create(ctx,
 			&corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: ns,
@@ -109,11 +109,11 @@ func (u *UXP) Install() error {
 }
 
 // IsInstalled checks if UXP has been installed in the target cluster.
-func (u *UXP) IsInstalled() bool {
+func (u *UXP) IsInstalled(ctx context.Context) bool {
 	_, err := u.crdclient.
 		CustomResourceDefinitions().
 		Get(
-			context.Background(),
+			ctx,
 			xrdCRD,
 			metav1.GetOptions{},
 		)
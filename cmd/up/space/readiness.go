@@ -0,0 +1,81 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// readinessSpinner shows the readiness of the Space components on a spinner
+// while the helm manager waits for them, so that users can see which
+// component is holding up an operation.
+type readinessSpinner struct {
+	msg     string
+	spinner *pterm.SpinnerPrinter
+}
+
+// Wrap runs f while showing msg on a spinner that is updated with the
+// readiness reported during f.
+func (r *readinessSpinner) Wrap(msg string, f func() error) error {
+	s, err := upterm.CheckmarkSuccessSpinner.Start(msg)
+	if err != nil {
+		return err
+	}
+	r.msg, r.spinner = msg, s
+	defer func() { r.spinner = nil }()
+
+	if err := f(); err != nil {
+		return err
+	}
+	s.UpdateText(msg)
+	s.Success()
+	return nil
+}
+
+// Report updates the spinner with the readiness of the supplied components.
+// It is a no-op when no spinner is shown.
+func (r *readinessSpinner) Report(ss []install.ComponentStatus) {
+	if r.spinner == nil {
+		return
+	}
+	r.spinner.UpdateText(fmt.Sprintf("%s: %s", r.msg, summarizeReadiness(ss)))
+}
+
+// summarizeReadiness describes how many components are ready and the first
+// component that is not.
+func summarizeReadiness(ss []install.ComponentStatus) string {
+	ready := 0
+	var waiting *install.ComponentStatus
+	for i := range ss {
+		if ss[i].Ready {
+			ready++
+			continue
+		}
+		if waiting == nil {
+			waiting = &ss[i]
+		}
+	}
+	sum := fmt.Sprintf("%d/%d components ready", ready, len(ss))
+	if waiting != nil {
+		sum += fmt.Sprintf(", waiting for %s/%s: %s", strings.ToLower(waiting.Kind), waiting.Name, waiting.Message)
+	}
+	return sum
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/input"
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	errParseRevision = "unable to parse revision"
+	errNoHistory     = "no release history found for Space"
+)
+
+// BeforeApply sets default values in rollback before assignment and
+// validation.
+func (c *rollbackCmd) BeforeApply() error {
+	c.prompter = input.NewPrompter()
+	return nil
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *rollbackCmd) AfterApply(insCtx *install.Context) error {
+	// NOTE(tnthornton) we currently only have support for stylized output.
+	upterm.EnableStyling()
+	upterm.DefaultObjPrinter.Pretty = true
+
+	mgr, err := helm.NewManager(insCtx.Kubeconfig,
+		spacesChart,
+		c.Repo,
+		helm.WithNamespace(ns),
+		helm.IsOCI(),
+		helm.WaitForReadiness(c.readiness.Report))
+	if err != nil {
+		return err
+	}
+	c.mgr = mgr
+	return nil
+}
+
+// rollbackCmd rolls back the Upbound Spaces deployment to a previous
+// revision.
+type rollbackCmd struct {
+	mgr       install.Manager
+	prompter  input.Prompter
+	readiness readinessSpinner
+
+	Revision int `arg:"" optional:"" help:"Revision to roll back to. If not provided, the release history is shown and a revision is prompted for."`
+
+	commonParams
+}
+
+// PrintedObjects returns the objects printed by the rollback command.
+func (c *rollbackCmd) PrintedObjects() []any {
+	return []any{install.Revision{}}
+}
+
+// Run executes the rollback command.
+func (c *rollbackCmd) Run(printer upterm.ObjectPrinter) error {
+	if c.Revision == 0 {
+		revs, err := c.mgr.History()
+		if err != nil {
+			return err
+		}
+		if len(revs) == 0 {
+			return errors.New(errNoHistory)
+		}
+		if err := printer.Print(revs, historyFieldNames, extractHistoryFields); err != nil {
+			return err
+		}
+		in, err := c.prompter.Prompt("Revision to roll back to (leave empty for previous revision)", false)
+		if err != nil {
+			return err
+		}
+		if in != "" {
+			if c.Revision, err = strconv.Atoi(in); err != nil {
+				return errors.Wrap(err, errParseRevision)
+			}
+		}
+	}
+
+	rollback := func() error {
+		return c.mgr.Rollback(c.Revision)
+	}
+	msg := "Rolling back Space to previous revision"
+	if c.Revision != 0 {
+		msg = fmt.Sprintf("Rolling back Space to revision %d", c.Revision)
+	}
+	if err := c.readiness.Wrap(msg, rollback); err != nil {
+		return err
+	}
+
+	version, err := c.mgr.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	pterm.Info.Printfln("Space is running version %s", version)
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/scan"
+	"github.com/upbound/up/internal/upterm"
+)
+
+const (
+	errRenderScan         = "unable to render Spaces manifests for scanning"
+	errFindImages         = "unable to find Spaces images"
+	errFmtVulnerabilities = "found %d vulnerabilities of severity %s or higher in Spaces images"
+	errScanSeverity       = "invalid --scan-severity"
+)
+
+type scanParams struct {
+	Scan         bool   `help:"Scan the images of the Spaces chart for vulnerabilities with trivy before installing, failing if any are found at or above --scan-severity."`
+	ScanSeverity string `default:"HIGH" placeholder:"LOW|MEDIUM|HIGH|CRITICAL" help:"Lowest severity of vulnerabilities that fails --scan."`
+	TrivyPath    string `default:"trivy" help:"Path to the trivy binary used by --scan."`
+}
+
+// scanChart renders the chart at the supplied version and scans the images it
+// references, printing any vulnerabilities at or above the severity
+// threshold.
+func (c *scanParams) scanChart(ctx context.Context, mgr install.Manager, version string, params map[string]any, id, token string) error {
+	if !c.Scan {
+		return nil
+	}
+	threshold, err := scan.ParseSeverity(c.ScanSeverity)
+	if err != nil {
+		return errors.Wrap(err, errScanSeverity)
+	}
+	manifests, err := mgr.Render(strings.TrimPrefix(version, "v"), params)
+	if err != nil {
+		return errors.Wrap(err, errRenderScan)
+	}
+	images, err := scan.ImagesFromManifests(manifests)
+	if err != nil {
+		return errors.Wrap(err, errFindImages)
+	}
+	scanner := scan.NewTrivy(scan.WithTrivyPath(c.TrivyPath), scan.WithRegistryAuth(id, token))
+	var results []scan.Result
+	if err := upterm.WrapWithSuccessSpinner(
+		"Scanning Spaces images for vulnerabilities",
+		upterm.CheckmarkSuccessSpinner,
+		func() error {
+			results, err = scan.Images(ctx, scanner, images, threshold)
+			return err
+		},
+	); err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	data := pterm.TableData{{"IMAGE", "ID", "SEVERITY", "PACKAGE", "INSTALLED", "FIXED"}}
+	count := 0
+	for _, r := range results {
+		for _, v := range r.Vulnerabilities {
+			data = append(data, []string{r.Image, v.ID, string(v.Severity), v.Package, v.InstalledVersion, v.FixedVersion})
+			count++
+		}
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+	return errors.Errorf(errFmtVulnerabilities, count, threshold)
+}
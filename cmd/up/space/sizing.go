@@ -0,0 +1,111 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtUnknownValuesProfile = "unknown values profile %q, must be one of: %s"
+)
+
+// componentResources builds the resource requests and limits of a component.
+func componentResources(cpu, memory, cpuLimit, memoryLimit string) map[string]any {
+	return map[string]any{
+		"requests": map[string]any{
+			"cpu":    cpu,
+			"memory": memory,
+		},
+		"limits": map[string]any{
+			"cpu":    cpuLimit,
+			"memory": memoryLimit,
+		},
+	}
+}
+
+// valuesProfiles are curated sizing presets for the components of Spaces.
+// They are merged beneath the parameters file and --set overrides.
+var valuesProfiles = map[string]func() map[string]any{
+	"small": func() map[string]any {
+		return map[string]any{
+			"controller": map[string]any{
+				"replicas":  1,
+				"resources": componentResources("100m", "256Mi", "500m", "512Mi"),
+			},
+			"apollo": map[string]any{
+				"replicas":  1,
+				"resources": componentResources("100m", "256Mi", "500m", "512Mi"),
+			},
+			"router": map[string]any{
+				"replicas":  1,
+				"resources": componentResources("100m", "128Mi", "500m", "256Mi"),
+			},
+		}
+	},
+	"medium": func() map[string]any {
+		return map[string]any{
+			"controller": map[string]any{
+				"replicas":  2,
+				"resources": componentResources("500m", "1Gi", "1", "2Gi"),
+			},
+			"apollo": map[string]any{
+				"replicas":  2,
+				"resources": componentResources("500m", "1Gi", "1", "2Gi"),
+			},
+			"router": map[string]any{
+				"replicas":  2,
+				"resources": componentResources("250m", "256Mi", "1", "512Mi"),
+			},
+		}
+	},
+	"large": func() map[string]any {
+		return map[string]any{
+			"controller": map[string]any{
+				"replicas":  3,
+				"resources": componentResources("1", "2Gi", "2", "4Gi"),
+			},
+			"apollo": map[string]any{
+				"replicas":  3,
+				"resources": componentResources("1", "2Gi", "2", "4Gi"),
+			},
+			"router": map[string]any{
+				"replicas":  3,
+				"resources": componentResources("500m", "512Mi", "2", "1Gi"),
+			},
+		}
+	},
+}
+
+// profileValues returns the values of the named values profile, or nil if no
+// profile is named.
+func profileValues(name string) (map[string]any, error) {
+	if name == "" {
+		return nil, nil
+	}
+	p, ok := valuesProfiles[name]
+	if !ok {
+		names := make([]string, 0, len(valuesProfiles))
+		for n := range valuesProfiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, errors.Errorf(errFmtUnknownValuesProfile, name, strings.Join(names, ", "))
+	}
+	return p(), nil
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/alecthomas/kong"
 
 	"github.com/upbound/up/cmd/up/space/billing"
+	"github.com/upbound/up/cmd/up/space/controlplane"
+	"github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/feature"
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/kube"
@@ -35,29 +37,81 @@ func (c *Cmd) BeforeReset(p *kong.Path, maturity feature.Maturity) error {
 // AfterApply constructs and binds Upbound-specific context to any subcommands
 // that have Run() methods that receive it.
 func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
-	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+	return bindInstallContext(kongCtx, c.Kubeconfig)
+}
+
+// bindInstallContext binds the install context for the Spaces cluster of the
+// supplied kubeconfig.
+func bindInstallContext(kongCtx *kong.Context, path string) error {
+	kubeconfig, err := kube.GetKubeConfig(path)
 	if err != nil {
 		return err
 	}
 
 	kongCtx.Bind(&install.Context{
 		Kubeconfig: kubeconfig,
+		RedactKeys: redactKeys(),
 	})
 	return nil
 }
 
+// redactKeys returns the parameter keys whose values are redacted in addition
+// to the defaults, as configured in the up config. The config is optional for
+// space commands, so none are returned if it cannot be read.
+func redactKeys() []string {
+	p, err := config.GetDefaultPath()
+	if err != nil {
+		return nil
+	}
+	conf, err := config.Extract(config.NewFSSource(config.WithPath(p)))
+	if err != nil {
+		return nil
+	}
+	return conf.RedactedKeys()
+}
+
 // Cmd contains commands for interacting with spaces.
 type Cmd struct {
 	Billing    billing.Cmd `cmd:""`
 	Kubeconfig string      `type:"existingfile" help:"Override default kubeconfig path."`
 
-	Init    initCmd    `cmd:"" help:"Initialize an Upbound Spaces deployment."`
-	Destroy destroyCmd `cmd:"" help:"Remove the Upbound Spaces deployment."`
-	Upgrade upgradeCmd `cmd:"" help:"Upgrade the Upbound Spaces deployment."`
+	Init     initCmd     `cmd:"" help:"Initialize an Upbound Spaces deployment."`
+	Destroy  destroyCmd  `cmd:"" help:"Remove the Upbound Spaces deployment."`
+	Upgrade  upgradeCmd  `cmd:"" help:"Upgrade the Upbound Spaces deployment."`
+	Rollback rollbackCmd `cmd:"" help:"Rollback the Upbound Spaces deployment to a previous revision."`
+	History  historyCmd  `cmd:"" help:"Show the release history of the Upbound Spaces deployment."`
+
+	ControlPlane controlplane.Cmd `cmd:"" name:"controlplane" aliases:"ctp" help:"Manage control planes in the Upbound Spaces deployment."`
+
+	PortForward   portForwardCmd   `cmd:"" maturity:"beta" help:"Forward local ports to a pod or service in the Spaces cluster."`
+	SupportBundle supportBundleCmd `cmd:"" help:"Gather diagnostics from the Upbound Spaces deployment for support tickets."`
+	Metrics       metricsCmd       `cmd:"" help:"Show reconcile rates, errors, and API latencies scraped from the metrics of Spaces components."`
+}
+
+// BetaCmd contains the commands for interacting with spaces that are in beta.
+type BetaCmd struct {
+	Kubeconfig string `type:"existingfile" help:"Override default kubeconfig path."`
+
+	PortForward portForwardCmd `cmd:"" maturity:"beta" help:"Forward local ports to a pod or service in the Spaces cluster."`
+}
+
+// AfterApply constructs and binds context to any subcommands that have Run()
+// methods that receive it.
+func (c *BetaCmd) AfterApply(kongCtx *kong.Context) error {
+	return bindInstallContext(kongCtx, c.Kubeconfig)
 }
 
 type commonParams struct {
 	Repo *url.URL `hidden:"" env:"UPBOUND_REPO" default:"us-west1-docker.pkg.dev/orchestration-build/upbound-environments" help:"Set repo for Upbound."`
 
 	Registry *url.URL `hidden:"" env:"UPBOUND_REGISTRY_ENDPOINT" default:"https://us-west1-docker.pkg.dev" help:"Set registry for authentication."`
+
+	ValuesProfile string `placeholder:"small|medium|large" help:"Apply a sizing preset for replicas and resources of Spaces components. The parameters file and --set take precedence."`
+
+	ChartDigest  string `placeholder:"sha256:..." help:"Pin the Spaces chart to the OCI manifest with this digest."`
+	ChartKeyring string `type:"existingfile" placeholder:"PATH" help:"Verify the provenance of the Spaces chart against the public keys in this keyring."`
+
+	ChartCacheDir string `type:"path" env:"UP_CHART_CACHE_DIR" placeholder:"DIR" help:"Directory that pulled charts are cached in, shared between install and upgrade. Interrupted chart downloads are resumed from it. Defaults to ~/.cache/up/charts."`
+
+	install.MirrorParams
 }
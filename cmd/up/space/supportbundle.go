@@ -0,0 +1,110 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"os"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/support"
+	"github.com/upbound/up/internal/version"
+)
+
+const (
+	errCreateBundle = "unable to create support bundle file"
+)
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *supportBundleCmd) AfterApply(insCtx *install.Context) error {
+	kClient, err := kubernetes.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	dClient, err := dynamic.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	mgr, err := helm.NewManager(insCtx.Kubeconfig,
+		spacesChart,
+		c.Repo,
+		helm.WithNamespace(ns),
+		helm.IsOCI())
+	if err != nil {
+		return err
+	}
+	rules := c.Redact
+	if !c.NoDefaultRedactions {
+		rules = append(append([]string{}, support.DefaultRedactionRules...), c.Redact...)
+	}
+	r, err := support.NewRedactor(rules...)
+	if err != nil {
+		return err
+	}
+	c.collector = support.NewCollector(kClient, dClient, r,
+		support.WithRelease(mgr),
+		support.WithClientVersion(version.GetVersion()),
+		support.WithNamespaces(c.Namespaces...),
+		support.WithLogLines(c.LogLines),
+	)
+	return nil
+}
+
+// supportBundleCmd gathers diagnostics from the Upbound Spaces deployment into
+// a tarball that can be attached to support tickets.
+type supportBundleCmd struct {
+	collector *support.Collector
+
+	Output              string   `short:"o" type:"path" default:"space-support-bundle.tar.gz" help:"Path of the support bundle to write."`
+	Namespaces          []string `default:"upbound-system,crossplane-system,cert-manager,ingress-nginx" help:"Namespaces to collect logs, events and resources from."`
+	LogLines            int64    `default:"1000" help:"Number of lines to collect from the end of each container's logs."`
+	Redact              []string `help:"Regular expression matching the names of additional fields whose values are redacted. May be repeated."`
+	NoDefaultRedactions bool     `help:"Only redact Secret data and fields matching --redact, rather than also redacting fields that commonly hold sensitive values."`
+
+	commonParams
+}
+
+// Help returns the help text for the support-bundle command.
+func (c *supportBundleCmd) Help() string {
+	return `
+The support bundle contains the logs of the Space components, the version and
+history of the Space release, and the events and resources in the Space's
+namespaces. The data of Secrets is always redacted, as are the values of fields
+and environment variables whose names match a redaction rule. Review the bundle
+before sharing it.`
+}
+
+// Run executes the support-bundle command.
+func (c *supportBundleCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	f, err := os.OpenFile(c.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, errCreateBundle)
+	}
+	if err := c.collector.Collect(ctx, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errCreateBundle)
+	}
+	p.Printfln("Support bundle written to %s", c.Output)
+	return nil
+}
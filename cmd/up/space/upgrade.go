@@ -16,24 +16,30 @@ package space
 
 import (
 	"context"
-	"io"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/yaml"
 
 	"github.com/upbound/up/internal/config"
 	"github.com/upbound/up/internal/input"
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
 	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/secrets"
 	"github.com/upbound/up/internal/upterm"
 )
 
 const (
 	errParseUpgradeParameters = "unable to parse upgrade parameters"
+	errPlanUpgrade            = "unable to compute upgrade plan"
+	errUpgradeCanceled        = "upgrade canceled"
+	errScheduleInPast         = "--schedule must be in the future"
+	errScheduleUnsupported    = "--schedule cannot be combined with --bundle, --chart-digest, --chart-keyring or an image mirror"
+	errScheduleUpgrade        = "unable to schedule upgrade"
 )
 
 // BeforeApply sets default values in login before assignment and validation.
@@ -43,17 +49,17 @@ func (c *upgradeCmd) BeforeApply() error {
 }
 
 // AfterApply sets default values in command after assignment and validation.
-func (c *upgradeCmd) AfterApply(insCtx *install.Context, quiet config.QuietFlag) error {
+func (c *upgradeCmd) AfterApply(ctx context.Context, insCtx *install.Context, quiet config.QuietFlag) error {
 	// NOTE(tnthornton) we currently only have support for stylized output.
-	pterm.EnableStyling()
+	upterm.EnableStyling()
 	upterm.DefaultObjPrinter.Pretty = true
 
-	b, err := io.ReadAll(c.TokenFile)
-	defer c.TokenFile.Close() // nolint:errcheck
+	resolver := secrets.NewResolver()
+	token, err := c.Token(ctx, resolver)
 	if err != nil {
-		return errors.Wrap(err, errReadTokenFile)
+		return err
 	}
-	c.token = string(b)
+	c.token = token
 
 	c.id = jsonKey
 	kClient, err := kubernetes.NewForConfig(insCtx.Kubeconfig)
@@ -63,6 +69,10 @@ func (c *upgradeCmd) AfterApply(insCtx *install.Context, quiet config.QuietFlag)
 	c.kClient = kClient
 	secret := kube.NewSecretApplicator(kClient)
 	c.pullSecret = kube.NewImagePullApplicator(secret)
+	mirror, err := c.Mirror()
+	if err != nil {
+		return err
+	}
 	ins, err := helm.NewManager(insCtx.Kubeconfig,
 		spacesChart,
 		c.Repo,
@@ -71,27 +81,40 @@ func (c *upgradeCmd) AfterApply(insCtx *install.Context, quiet config.QuietFlag)
 		helm.IsOCI(),
 		helm.WithChart(c.Bundle),
 		helm.RollbackOnError(c.Rollback),
-		helm.Wait())
+		helm.WithImageMirror(mirror),
+		helm.WithChartDigest(c.ChartDigest),
+		helm.WithChartKeyring(c.ChartKeyring),
+		helm.WithCacheDir(c.ChartCacheDir),
+		helm.WithRedactedKeys(insCtx.RedactKeys...),
+		helm.WaitForReadiness(c.readiness.Report))
 	if err != nil {
 		return err
 	}
 	c.helmMgr = ins
-	base := map[string]any{}
-	if c.File != nil {
-		defer c.File.Close() //nolint:errcheck,gosec
-		b, err := io.ReadAll(c.File)
-		if err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := yaml.Unmarshal(b, &base); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
+	}
+	defaults, err := profileValues(c.ValuesProfile)
+	if err != nil {
+		return err
+	}
+	secretParams, err := c.SecretParameters(ctx, resolver)
+	if err != nil {
+		return err
+	}
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithDefaults(defaults), helm.WithLiteralOverrides(secretParams))
+	c.quiet = quiet
+	if !c.Schedule.IsZero() {
+		// The upgrade Job pulls the chart itself, so it can only use the
+		// repository and the image pull secret.
+		if c.Bundle != nil || c.ChartDigest != "" || c.ChartKeyring != "" || mirror != nil {
+			return errors.New(errScheduleUnsupported)
 		}
-		if err := c.File.Close(); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
+		if !c.Schedule.After(time.Now()) {
+			return errors.New(errScheduleInPast)
 		}
 	}
-	c.parser = helm.NewParser(base, c.Set)
-	c.quiet = quiet
 	return nil
 }
 
@@ -101,6 +124,7 @@ type upgradeCmd struct {
 	parser     install.ParameterParser
 	prompter   input.Prompter
 	pullSecret *kube.ImagePullApplicator
+	readiness  readinessSpinner
 	id         string
 	token      string
 	kClient    kubernetes.Interface
@@ -111,26 +135,57 @@ type upgradeCmd struct {
 	Version string `arg:"" help:"Upbound Spaces version to upgrade to."`
 
 	Rollback bool `help:"Rollback to previously installed version on failed upgrade."`
+	Yes      bool `short:"y" help:"Apply the upgrade without confirming the upgrade plan."`
+
+	Schedule      time.Time `placeholder:"RFC3339" help:"Upgrade at this time, e.g. 2023-09-01T02:00:00Z, using a job in the cluster instead of upgrading now. Replaces any previously scheduled upgrade."`
+	ScheduleImage string    `default:"alpine/helm:3.12.3" help:"Image of the job that runs a scheduled upgrade. Must contain helm and a POSIX shell."`
 
 	commonParams
+	scanParams
 	install.CommonParams
 }
 
 // Run executes the upgrade command.
-func (c *upgradeCmd) Run(insCtx *install.Context) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
+func (c *upgradeCmd) Run(ctx context.Context, insCtx *install.Context) error {
 	params, err := c.parser.Parse()
 	if err != nil {
 		return errors.Wrap(err, errParseUpgradeParameters)
 	}
 
+	// Scanning pulls every image, which may take longer than the timeout
+	// for changes to the cluster.
+	if err := c.scanChart(ctx, c.helmMgr, c.Version, params, c.id, c.token); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	plan, err := c.helmMgr.PlanUpgrade(strings.TrimPrefix(c.Version, "v"), params)
+	if err != nil {
+		return errors.Wrap(err, errPlanUpgrade)
+	}
+	printUpgradePlan(plan)
+
+	if !c.Yes {
+		confirm, err := c.prompter.Prompt("Would you like to proceed with the upgrade? [y/n]", false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return errors.New(errUpgradeCanceled)
+		}
+	}
+
 	// Create or update image pull secret.
 	if err := c.pullSecret.Apply(ctx, defaultImagePullSecret, ns, c.id, c.token, c.Registry.String()); err != nil {
 		return errors.Wrap(err, errCreateImagePullSecret)
 	}
 
+	if !c.Schedule.IsZero() {
+		return c.scheduleUpgrade(ctx, params)
+	}
+
 	if err := c.upgradeUpbound(params); err != nil {
 		return err
 	}
@@ -138,6 +193,36 @@ func (c *upgradeCmd) Run(insCtx *install.Context) error {
 	return nil
 }
 
+// scheduleUpgrade stores the upgrade in the cluster, to be applied at the
+// scheduled time by a job.
+func (c *upgradeCmd) scheduleUpgrade(ctx context.Context, params map[string]any) error {
+	version, at, ok, err := install.GetScheduledUpgrade(ctx, c.kClient, ns)
+	if err != nil {
+		return errors.Wrap(err, errScheduleUpgrade)
+	}
+	if ok {
+		pterm.Warning.Printfln("Replacing the upgrade to %s scheduled at %s", version, at.Local().Format(time.RFC1123))
+	}
+	s := install.ScheduledUpgrade{
+		Namespace:  ns,
+		Release:    spacesChart,
+		Chart:      "oci://" + c.Repo.String() + "/" + spacesChart,
+		Version:    strings.TrimPrefix(c.Version, "v"),
+		At:         c.Schedule,
+		Parameters: params,
+		PullSecret: defaultImagePullSecret,
+		Image:      c.ScheduleImage,
+		Atomic:     c.Rollback,
+	}
+	if err := s.Apply(ctx, c.kClient); err != nil {
+		return errors.Wrap(err, errScheduleUpgrade)
+	}
+	pterm.Success.Printfln("Scheduled upgrade to %s at %s", s.Version, c.Schedule.Local().Format(time.RFC1123))
+	pterm.Info.Printfln("Follow it with: kubectl logs -n %s -f job/%s", ns, install.ScheduledUpgradeName)
+	pterm.Info.Printfln("Cancel it with: kubectl delete job -n %s %s", ns, install.ScheduledUpgradeName)
+	return nil
+}
+
 func (c *upgradeCmd) upgradeUpbound(params map[string]any) error {
 	upgrade := func() error {
 		if err := c.helmMgr.Upgrade(strings.TrimPrefix(c.Version, "v"), params); err != nil {
@@ -146,13 +231,88 @@ func (c *upgradeCmd) upgradeUpbound(params map[string]any) error {
 		return nil
 	}
 
-	if err := upterm.WrapWithSuccessSpinner(
-		"Upgrading Space",
-		upterm.CheckmarkSuccessSpinner,
-		upgrade,
-	); err != nil {
+	if err := c.readiness.Wrap("Upgrading Space", upgrade); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// printUpgradePlan prints the changes that an upgrade would apply.
+func printUpgradePlan(plan *install.UpgradePlan) {
+	pterm.Info.Printfln("Upgrading Space from %s to %s", plan.CurrentVersion, plan.TargetVersion)
+	pterm.Println()
+
+	if len(plan.Changelog) > 0 {
+		pterm.DefaultSection.Println("Changelog")
+		for _, c := range plan.Changelog {
+			pterm.Println("  - " + c)
+		}
+		pterm.Println()
+	}
+
+	if len(plan.CRDs) > 0 {
+		pterm.DefaultSection.Println("CustomResourceDefinitions")
+		for _, crd := range plan.CRDs {
+			line := fmt.Sprintf("%s (%s)", crd.Name, crd.Change)
+			if len(crd.AddedVersions) > 0 {
+				line += fmt.Sprintf(" +%s", strings.Join(crd.AddedVersions, ","))
+			}
+			if len(crd.RemovedVersions) > 0 {
+				line += fmt.Sprintf(" -%s", strings.Join(crd.RemovedVersions, ","))
+			}
+			if crd.Change == install.ChangeRemoved || len(crd.RemovedVersions) > 0 {
+				pterm.Warning.Println(line)
+				continue
+			}
+			pterm.Println("  " + line)
+		}
+		pterm.Println()
+	}
+
+	if len(plan.Images) > 0 {
+		pterm.DefaultSection.Println("Images")
+		for _, img := range plan.Images {
+			from, to := img.From, img.To
+			if from == "" {
+				from = "(none)"
+			}
+			if to == "" {
+				to = "(removed)"
+			}
+			pterm.Println(fmt.Sprintf("  %s: %s -> %s", img.Repository, from, to))
+		}
+		pterm.Println()
+	}
+
+	if len(plan.Removed) > 0 {
+		pterm.DefaultSection.Println("Removed Values")
+		for _, v := range plan.Removed {
+			if v.InUse {
+				pterm.Warning.Printfln("%s is set but is no longer supported and may have been renamed", v.Key)
+				continue
+			}
+			pterm.Println("  " + v.Key)
+		}
+		pterm.Println()
+	}
+
+	if len(plan.Parameters) > 0 {
+		pterm.DefaultSection.Println("Parameters")
+		for _, p := range plan.Parameters {
+			switch p.Change {
+			case install.ChangeAdded:
+				pterm.Println(fmt.Sprintf("  %s: %s (added)", p.Key, p.To))
+			case install.ChangeRemoved:
+				pterm.Println(fmt.Sprintf("  %s: %s (removed)", p.Key, p.From))
+			default:
+				pterm.Println(fmt.Sprintf("  %s: %s -> %s", p.Key, p.From, p.To))
+			}
+		}
+		pterm.Println()
+	}
+
+	if plan.Breaking() {
+		pterm.Warning.Println("This upgrade contains potentially breaking changes.")
+	}
+}
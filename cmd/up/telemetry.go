@@ -0,0 +1,62 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/telemetry"
+	"github.com/upbound/up/internal/version"
+)
+
+// flushTimeout bounds how long sending telemetry may delay exiting.
+const flushTimeout = 2 * time.Second
+
+// recordTelemetry queues an event for the command if the user has opted in
+// to telemetry and configured an endpoint, and flushes the queue once enough events have been queued.
+// Telemetry must never affect the outcome of a command, so all errors are
+// ignored.
+func recordTelemetry(command string, d time.Duration, cmdErr error) {
+	p, err := config.GetDefaultPath()
+	if err != nil {
+		return
+	}
+	conf, err := config.Extract(config.NewFSSource(config.WithPath(p)))
+	if err != nil || !conf.TelemetryEnabled() {
+		return
+	}
+	q, err := telemetry.NewQueue()
+	if err != nil {
+		return
+	}
+	if err := q.Append(telemetry.Event{
+		Command:    command,
+		DurationMS: d.Milliseconds(),
+		Success:    cmdErr == nil,
+		Version:    version.GetVersion(),
+		Time:       time.Now().UTC(),
+	}); err != nil {
+		return
+	}
+	events, err := q.Events()
+	if err != nil || len(events) < telemetry.FlushThreshold {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	_ = q.Flush(ctx, telemetry.NewHTTPSender(conf.Telemetry.Endpoint))
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"net/url"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/telemetry"
+	"github.com/upbound/up/internal/upbound"
+)
+
+const (
+	errUpdateConfig = "unable to update config file"
+	errFmtEndpoint  = "invalid telemetry endpoint %q, must be an http or https URL"
+)
+
+// AfterApply constructs and binds Upbound-specific context to any subcommands
+// that have Run() methods that receive it.
+func (c *Cmd) AfterApply(kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(upbound.Flags{})
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(upCtx)
+	return nil
+}
+
+// Cmd contains commands for managing telemetry.
+type Cmd struct {
+	On     onCmd     `cmd:"" help:"Opt in to sending anonymous usage metrics."`
+	Off    offCmd    `cmd:"" help:"Opt out of sending anonymous usage metrics."`
+	Status statusCmd `cmd:"" help:"Show whether anonymous usage metrics are sent."`
+}
+
+// Help returns the help text for the telemetry commands.
+func (c *Cmd) Help() string {
+	return `
+When telemetry is on, up records the name of each command that is run (without
+arguments or flags), how long it took, whether it succeeded, and the version of
+up. No account, profile, or resource information is recorded. Events are
+queued locally and sent in batches to the endpoint given when telemetry is
+turned on.`
+}
+
+// AfterApply validates the endpoint.
+func (c *onCmd) AfterApply() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf(errFmtEndpoint, c.Endpoint)
+	}
+	return nil
+}
+
+type onCmd struct {
+	Endpoint string `required:"" help:"URL to which anonymous usage metrics are sent."`
+}
+
+// Run executes the on command.
+func (c *onCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		cfg.Telemetry = &config.Telemetry{Enabled: true, Endpoint: c.Endpoint}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, errUpdateConfig)
+	}
+	p.Println("Telemetry is on. Thank you for helping improve up!")
+	return nil
+}
+
+type offCmd struct{}
+
+// Run executes the off command.
+func (c *offCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+		if cfg.Telemetry == nil {
+			cfg.Telemetry = &config.Telemetry{}
+		}
+		cfg.Telemetry.Enabled = false
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, errUpdateConfig)
+	}
+	// Events recorded before opting out are discarded rather than sent.
+	q, err := telemetry.NewQueue()
+	if err != nil {
+		return err
+	}
+	if err := q.Clear(); err != nil {
+		return err
+	}
+	p.Println("Telemetry is off.")
+	return nil
+}
+
+type statusCmd struct{}
+
+// Run executes the status command.
+func (c *statusCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error {
+	if !upCtx.Cfg.TelemetryEnabled() {
+		p.Println("Telemetry is off.")
+		return nil
+	}
+	q, err := telemetry.NewQueue()
+	if err != nil {
+		return err
+	}
+	events, err := q.Events()
+	if err != nil {
+		return err
+	}
+	p.Println("Telemetry is on.")
+	p.Printfln("%d events are queued to be sent to %s.", len(events), upCtx.Cfg.Telemetry.Endpoint)
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/version"
+)
+
+const (
+	// updateCheckInterval is the minimum time between passive checks for a
+	// new version of up.
+	updateCheckInterval = 24 * time.Hour
+	// updateCheckTimeout bounds how long a passive check may delay exiting.
+	updateCheckTimeout = 2 * time.Second
+
+	errGetExecutable = "unable to determine path of up binary"
+	errLatestVersion = "unable to determine latest version of up"
+)
+
+// AfterApply constructs and binds Upbound-specific context to the command.
+func (c *upgradeCLICmd) AfterApply(kongCtx *kong.Context) error {
+	upCtx, err := upbound.NewFromFlags(upbound.Flags{})
+	if err != nil {
+		return err
+	}
+	kongCtx.Bind(upCtx)
+	return nil
+}
+
+// upgradeCLICmd replaces the running up binary with a newer version.
+type upgradeCLICmd struct {
+	Channel       string `enum:"stable,beta," default:"" help:"Release channel to upgrade from. Defaults to the last channel used, or stable. Can be: stable, beta"`
+	TargetVersion string `help:"Version to install. Defaults to the latest version on the channel."`
+	Check         bool   `help:"Only report whether a newer version is available."`
+	Notifications string `enum:"on,off," default:"" help:"Turn warnings that a newer version is available on or off, then exit. Can be: on, off"`
+}
+
+// Help returns the help text for the upgrade-cli command.
+func (c *upgradeCLICmd) Help() string {
+	return `
+The downloaded binary is verified against the SHA-256 checksum published
+alongside it before the current binary is replaced. The checksum is fetched
+from the same host as the binary, so it detects corrupted downloads but not a
+compromised download host. The signature of the binary is not verified.
+
+Unless turned off with --notifications=off, up checks for a newer version on
+the configured channel at most once a day and prints a warning to stderr if
+one is available.`
+}
+
+// Run executes the upgrade-cli command.
+func (c *upgradeCLICmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error { //nolint:gocyclo
+	if c.Notifications != "" {
+		if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+			if cfg.Updates == nil {
+				cfg.Updates = &config.Updates{}
+			}
+			cfg.Updates.DisableNotifications = c.Notifications == "off"
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, errUpdateConfig)
+		}
+		p.Printfln("Notifications of new versions are %s.", c.Notifications)
+		return nil
+	}
+
+	channel := c.Channel
+	if channel == "" {
+		channel = updateChannel(upCtx.Cfg)
+	}
+	i := version.NewInformer(version.WithChannel(channel))
+	target := c.TargetVersion
+	if target == "" {
+		latest, err := i.Latest(ctx)
+		if err != nil {
+			return errors.Wrap(err, errLatestVersion)
+		}
+		target = latest
+	}
+	local := version.GetVersion()
+	if target == local {
+		p.Printfln("up %s is already installed.", local)
+		return nil
+	}
+	if c.Check {
+		p.Printfln("up %s is available on the %s channel (installed: %s). Run up upgrade-cli to upgrade.", target, channel, local)
+		return nil
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, errGetExecutable)
+	}
+	bin, err := i.Download(ctx, target)
+	if err != nil {
+		return err
+	}
+	if err := version.ReplaceExecutable(path, bin); err != nil {
+		return err
+	}
+	p.Println("Verified the SHA-256 checksum of the downloaded binary. Its signature was not verified.")
+
+	// The channel used is remembered for subsequent upgrades and passive
+	// checks.
+	if c.Channel != "" {
+		if err := upCtx.ModifyConfig(func(cfg *config.Config) error {
+			if cfg.Updates == nil {
+				cfg.Updates = &config.Updates{}
+			}
+			cfg.Updates.Channel = c.Channel
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, errUpdateConfig)
+		}
+	}
+	p.Printfln("up upgraded from %s to %s.", local, target)
+	return nil
+}
+
+// updateChannel returns the configured release channel, defaulting to
+// stable.
+func updateChannel(conf *config.Config) string {
+	if conf.Updates == nil || conf.Updates.Channel == "" {
+		return version.ChannelStable
+	}
+	return conf.Updates.Channel
+}
+
+// notifyUpgrade warns on w if a newer version of up is available. Checks are
+// made at most once per updateCheckInterval, only when w is a terminal, and
+// never for commands that manage up itself. Like telemetry, the check must
+// never affect the outcome of a command, so all errors are ignored.
+func notifyUpgrade(command string, w io.Writer) {
+	if strings.HasPrefix(command, "upgrade-cli") || strings.HasPrefix(command, "xpls") {
+		return
+	}
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return
+	}
+	p, err := config.GetDefaultPath()
+	if err != nil {
+		return
+	}
+	src := config.NewFSSource(config.WithPath(p))
+	conf, err := config.Extract(src)
+	if err != nil || !conf.UpdateNotificationsEnabled() {
+		return
+	}
+	if conf.Updates != nil && time.Since(conf.Updates.LastChecked) < updateCheckInterval {
+		return
+	}
+	// Another up process may have checked since the config was read, in which
+	// case the timestamp is left as is and no check is made.
+	due := false
+	conf, err = src.ModifyConfig(func(cfg *config.Config) error {
+		if cfg.Updates == nil {
+			cfg.Updates = &config.Updates{}
+		}
+		if time.Since(cfg.Updates.LastChecked) < updateCheckInterval {
+			return nil
+		}
+		cfg.Updates.LastChecked = time.Now().UTC()
+		due = true
+		return nil
+	})
+	if err != nil || !due {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	defer cancel()
+	local, remote, ok := version.NewInformer(version.WithChannel(updateChannel(conf))).CanUpgrade(ctx)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(w, "A newer version of up is available (%s, installed: %s). Run up upgrade-cli to upgrade, or up upgrade-cli --notifications=off to stop these warnings.\n", remote, local)
+}
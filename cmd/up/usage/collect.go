@@ -0,0 +1,293 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+
+	"github.com/upbound/up/internal/usage"
+	"github.com/upbound/up/internal/usage/clientutil"
+	"github.com/upbound/up/internal/usage/clientutil/gcs"
+	"github.com/upbound/up/internal/usage/collect"
+	"github.com/upbound/up/internal/usage/report"
+	reportaws "github.com/upbound/up/internal/usage/report/aws"
+	reportgcs "github.com/upbound/up/internal/usage/report/gcs"
+	"github.com/upbound/up/internal/usage/sink/bigquery"
+	"github.com/upbound/up/internal/usage/sink/kafkarest"
+	"github.com/upbound/up/internal/usage/sink/webhook"
+)
+
+const (
+	providerAWS = "aws"
+	providerGCP = "gcp"
+
+	errFmtProviderNotSupported = "%q is not supported"
+	errFmtGCPOnly              = "--gcp-billing-project and --gcp-encryption-key-file are not supported for %q"
+	errFmtProfileAWSOnly       = "--credentials-profile is not supported for %q"
+	errIntervalDaemonOnly      = "--interval can only be used with --daemon"
+	errIntervalTooShort        = "--interval must be at least 1m"
+	errReadEncryptionKey       = "unable to read encryption key"
+	errKafkaRESTFlags          = "--kafka-rest-url and --kafka-rest-topic must be supplied together"
+	errCheckGapsDaemon         = "--check-gaps cannot be used with --daemon"
+	errFmtGaps                 = "%d hours without usage data"
+	errBigQueryCredentials     = "--bigquery-credentials-file can only be used with --bigquery-table"
+	errCreateBigQueryClient    = "unable to create BigQuery client"
+)
+
+// collectCmd collects usage data from storage into a local directory.
+type collectCmd struct {
+	Dir       string        `short:"o" type:"path" env:"UP_USAGE_DIR" default:"upbound_usage" help:"Directory in which collected usage is stored."`
+	Since     time.Time     `format:"2006-01-02" env:"UP_USAGE_SINCE" help:"Date from which to collect usage if none has been collected. Defaults to the start of the current month. Format: 2006-01-02."`
+	Daemon    bool          `help:"Keep running and collect new usage every interval."`
+	Interval  time.Duration `env:"UP_USAGE_INTERVAL" help:"How often to collect new usage when running as a daemon. Defaults to 1h."`
+	CheckGaps bool          `help:"Instead of collecting, report hours since --since without usage data in storage, and fail if there are any."`
+
+	Provider string `required:"" enum:"aws,gcp" env:"UP_USAGE_PROVIDER" group:"Storage" help:"Storage provider. Must be one of: aws, gcp."`
+	Bucket   string `required:"" env:"UP_USAGE_BUCKET" group:"Storage" help:"Storage bucket."`
+	Endpoint string `env:"UP_USAGE_ENDPOINT" group:"Storage" help:"Custom storage endpoint."`
+	Account  string `required:"" env:"UP_USAGE_ACCOUNT" group:"Storage" help:"Name of the Upbound account whose usage is being collected."`
+
+	CredentialsFile    string `type:"existingfile" env:"UP_USAGE_CREDENTIALS_FILE" group:"Storage" help:"File containing explicit storage credentials: a service account key file for gcp, or a shared credentials file for aws. Ambient credentials are used if not set."`
+	CredentialsProfile string `env:"UP_USAGE_CREDENTIALS_PROFILE" group:"Storage" help:"Profile to use from the shared credentials file. Only supported for aws."`
+
+	GCPBillingProject    string `env:"UP_USAGE_GCP_BILLING_PROJECT" group:"Storage" help:"GCP project billed for reading a requester-pays bucket. Only supported for gcp."`
+	GCPEncryptionKeyFile string `type:"existingfile" env:"UP_USAGE_GCP_ENCRYPTION_KEY_FILE" group:"Storage" help:"File containing the customer-supplied AES-256 key, raw or base64 encoded, with which usage data is encrypted. Only supported for gcp."`
+
+	WebhookURL      string            `env:"UP_USAGE_WEBHOOK_URL" group:"Sinks" help:"Send collected usage events to this URL as JSON arrays in POST requests."`
+	WebhookHeader   map[string]string `env:"UP_USAGE_WEBHOOK_HEADER" group:"Sinks" help:"Header to set on webhook requests in the form name=value, e.g. Authorization='Bearer token'. May be repeated."`
+	KafkaRESTURL    string            `name:"kafka-rest-url" env:"UP_USAGE_KAFKA_REST_URL" group:"Sinks" help:"Produce collected usage events to Kafka through the Kafka REST Proxy (v2 API) at this URL."`
+	KafkaRESTTopic  string            `name:"kafka-rest-topic" env:"UP_USAGE_KAFKA_REST_TOPIC" group:"Sinks" help:"Kafka topic to which usage events are produced through the Kafka REST Proxy."`
+	KafkaRESTHeader map[string]string `name:"kafka-rest-header" env:"UP_USAGE_KAFKA_REST_HEADER" group:"Sinks" help:"Header to set on Kafka REST Proxy requests in the form name=value. May be repeated."`
+
+	BigQueryTable           string `name:"bigquery-table" env:"UP_USAGE_BIGQUERY_TABLE" group:"Sinks" help:"Insert collected usage events into this BigQuery table. Format: project.dataset.table. The table is created if it does not exist."`
+	BigQueryCredentialsFile string `name:"bigquery-credentials-file" type:"existingfile" env:"UP_USAGE_BIGQUERY_CREDENTIALS_FILE" group:"Sinks" help:"Service account key file used to insert events into BigQuery. Ambient credentials are used if not set."`
+
+	creds     clientutil.Credentials
+	gcsBucket gcs.BucketOptions
+	bqTable   bigquery.Table
+}
+
+// Help returns the help text for the collect command.
+func (c *collectCmd) Help() string {
+	return `
+Usage is collected for each complete hour since the last collection and
+appended to events.jsonl in the output directory, one aggregated usage event
+per line. The end of the last collected hour is recorded in cursor.json, so
+re-running the command only collects new usage.
+
+Collected events may also be streamed to a webhook with --webhook-url, to a
+Kafka topic through a Kafka REST Proxy with --kafka-rest-url and
+--kafka-rest-topic, or into a BigQuery table with --bigquery-table. The
+BigQuery table is created, partitioned by day, if it does not exist. Transient BigQuery errors are retried
+with backoff, and each event is inserted with an ID that lets BigQuery drop
+duplicates.
+Events are sent to each sink before they are stored. If sending fails, the
+same hours are collected and sent again, so sinks may receive duplicates.
+
+With --daemon, the command keeps running and collects new usage every
+--interval until interrupted. Collections that fail are retried at the next
+interval.
+
+With --check-gaps, nothing is collected. Instead, the hours from --since up to
+the start of the current hour for which storage holds no usage data are
+reported, so that collection outages are discovered before usage is queried or
+submitted.`
+}
+
+// Validate validates and reads the storage configuration.
+func (c *collectCmd) Validate() error {
+	if c.CheckGaps && c.Daemon {
+		return errors.New(errCheckGapsDaemon)
+	}
+	if c.Interval != 0 && !c.Daemon {
+		return errors.New(errIntervalDaemonOnly)
+	}
+	if c.Daemon && c.Interval == 0 {
+		c.Interval = time.Hour
+	}
+	if c.Daemon && c.Interval < time.Minute {
+		return errors.New(errIntervalTooShort)
+	}
+	if c.CredentialsProfile != "" && c.Provider != providerAWS {
+		return errors.Errorf(errFmtProfileAWSOnly, c.Provider)
+	}
+	c.creds = clientutil.Credentials{
+		File:    c.CredentialsFile,
+		Profile: c.CredentialsProfile,
+	}
+	if c.Provider != providerGCP && (c.GCPBillingProject != "" || c.GCPEncryptionKeyFile != "") {
+		return errors.Errorf(errFmtGCPOnly, c.Provider)
+	}
+	c.gcsBucket.BillingProject = c.GCPBillingProject
+	if c.GCPEncryptionKeyFile != "" {
+		b, err := os.ReadFile(c.GCPEncryptionKeyFile)
+		if err != nil {
+			return errors.Wrap(err, errReadEncryptionKey)
+		}
+		if c.gcsBucket.EncryptionKey, err = gcs.ParseEncryptionKey(b); err != nil {
+			return err
+		}
+	}
+	if (c.KafkaRESTURL == "") != (c.KafkaRESTTopic == "") {
+		return errors.New(errKafkaRESTFlags)
+	}
+	if c.BigQueryTable != "" {
+		t, err := bigquery.ParseTable(c.BigQueryTable)
+		if err != nil {
+			return err
+		}
+		c.bqTable = t
+	} else if c.BigQueryCredentialsFile != "" {
+		return errors.New(errBigQueryCredentials)
+	}
+	if c.Since.IsZero() {
+		now := time.Now().UTC()
+		c.Since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return nil
+}
+
+// Unbounded returns true if the collect command runs until interrupted.
+func (c *collectCmd) Unbounded() bool {
+	return c.Daemon
+}
+
+// Run executes the collect command.
+func (c *collectCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
+	if c.CheckGaps {
+		return c.checkGaps(ctx, p)
+	}
+	sinks, err := c.sinks(ctx)
+	if err != nil {
+		return err
+	}
+	col := &collect.Collector{
+		Account: c.Account,
+		Store:   collect.NewStore(c.Dir),
+		Report:  c.report,
+		Sinks:   sinks,
+		Since:   c.Since,
+	}
+
+	if !c.Daemon {
+		res, ok, err := col.CollectOnce(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			p.Printfln("No new usage to collect for account %s.", c.Account)
+			return nil
+		}
+		printResult(p, res)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	p.Printfln("Collecting usage for account %s into %s every %s. Press Ctrl+C to stop.", c.Account, c.Dir, c.Interval)
+	return col.Run(ctx, c.Interval, func(res collect.Result) {
+		printResult(p, res)
+	}, func(err error) {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", time.Now().Format(time.RFC3339), err)
+	})
+}
+
+// checkGaps reports the hours since --since for which no usage is stored.
+func (c *collectCmd) checkGaps(ctx context.Context, p pterm.TextPrinter) error {
+	tr := usage.TimeRange{
+		Start: c.Since.UTC().Truncate(time.Hour),
+		End:   time.Now().UTC().Truncate(time.Hour),
+	}
+	gaps, err := collect.Gaps(ctx, tr, c.partitions)
+	if err != nil {
+		return err
+	}
+	if len(gaps) == 0 {
+		p.Printfln("Usage data is stored for every hour from %s to %s.", tr.Start.Format(time.RFC3339), tr.End.Format(time.RFC3339))
+		return nil
+	}
+	hours := 0
+	for _, g := range gaps {
+		n := int(g.End.Sub(g.Start) / time.Hour)
+		hours += n
+		p.Printfln("No usage data from %s to %s (%d hours).", g.Start.Format(time.RFC3339), g.End.Format(time.RFC3339), n)
+	}
+	return errors.Errorf(errFmtGaps, hours)
+}
+
+// partitions counts the usage objects in the configured storage for each hour
+// of a time range.
+func (c *collectCmd) partitions(ctx context.Context, tr usage.TimeRange) ([]report.Partition, error) {
+	switch c.Provider {
+	case providerGCP:
+		return reportgcs.Partitions(ctx, c.Account, c.Endpoint, c.Bucket, c.creds, c.gcsBucket, tr)
+	case providerAWS:
+		return reportaws.Partitions(ctx, c.Account, c.Endpoint, c.Bucket, c.creds, tr)
+	default:
+		return nil, errors.Errorf(errFmtProviderNotSupported, c.Provider)
+	}
+}
+
+// report reads usage for a time range from the configured storage provider.
+func (c *collectCmd) report(ctx context.Context, tr usage.TimeRange, w report.MCPGVKEventWriter) error {
+	switch c.Provider {
+	case providerGCP:
+		return reportgcs.GenerateReport(ctx, c.Account, c.Endpoint, c.Bucket, c.creds, c.gcsBucket, tr, time.Hour, w)
+	case providerAWS:
+		return reportaws.GenerateReport(ctx, c.Account, c.Endpoint, c.Bucket, c.creds, tr, w)
+	default:
+		return errors.Errorf(errFmtProviderNotSupported, c.Provider)
+	}
+}
+
+// sinks returns the configured event sinks.
+func (c *collectCmd) sinks(ctx context.Context) ([]usage.EventSink, error) {
+	sinks := []usage.EventSink{}
+	if c.WebhookURL != "" {
+		sinks = append(sinks, webhook.NewSink(c.WebhookURL, webhook.WithHeaders(c.WebhookHeader)))
+	}
+	if c.KafkaRESTURL != "" {
+		sinks = append(sinks, kafkarest.NewSink(c.KafkaRESTURL, c.KafkaRESTTopic, kafkarest.WithHeaders(c.KafkaRESTHeader)))
+	}
+	if c.BigQueryTable != "" {
+		opts := []option.ClientOption{}
+		if c.BigQueryCredentialsFile != "" {
+			opts = append(opts, option.WithCredentialsFile(c.BigQueryCredentialsFile))
+		}
+		hc, err := clientutil.GoogleHTTPClient(ctx, []string{bq.BigqueryScope}, opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, errCreateBigQueryClient)
+		}
+		svc, err := bq.NewService(ctx, option.WithHTTPClient(hc))
+		if err != nil {
+			return nil, errors.Wrap(err, errCreateBigQueryClient)
+		}
+		sinks = append(sinks, bigquery.NewSink(svc, c.bqTable))
+	}
+	return sinks, nil
+}
+
+func printResult(p pterm.TextPrinter, res collect.Result) {
+	p.Printfln("Collected %d usage events from %s to %s.", res.Events, res.TimeRange.Start.Format(time.RFC3339), res.TimeRange.End.Format(time.RFC3339))
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/spaces"
+	"github.com/upbound/up/internal/upbound"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/usage/aggregate"
+	reporttar "github.com/upbound/up/internal/usage/report/file/tar"
+)
+
+const (
+	noCostCenter = "<none>"
+
+	errReadCostCenters      = "unable to read cost centers"
+	errParseCostCenters     = "unable to parse cost centers"
+	errCostCentersSpaceOnly = "cost centers can only be read from the control planes of a Space profile, use --cost-centers instead"
+)
+
+var costCenterFieldNames = []string{"COST CENTER", "CONTROL PLANES", "EVENTS", "RESOURCE HOURS"}
+
+// reportCmd reports usage rolled up by cost center.
+type reportCmd struct {
+	Path        string `arg:"" type:"path" help:"Usage export to report on. Either the archive written by 'up space billing get', or a directory into which it has been extracted."`
+	CostCenters string `type:"existingfile" help:"JSON file mapping control plane IDs to cost centers. Read from the control planes in the Space of the current profile if not set."`
+
+	// Common Upbound API configuration
+	Flags upbound.Flags `embed:""`
+}
+
+// Help returns the help text for the report command.
+func (c *reportCmd) Help() string {
+	return `
+The export is verified against its manifest, then the usage of each control
+plane is rolled up by the cost center it is charged to, as set with
+'up controlplane tag --cost-center'. Each usage event records the largest number
+of resources of a kind that ran in a control plane during a window of time, and
+is counted as that many resources running for the whole window.
+
+Cost centers are read from the upbound.io/cost-center label of the control
+planes in the Space of the current profile, which are matched to usage by
+their UID. Usage of control planes that are not tagged, or that no longer
+exist, is reported without a cost center. To report on usage without access to
+the Space, supply a JSON file mapping control plane IDs to cost centers with
+--cost-centers, e.g. {"4f8a...": "platform"}.`
+}
+
+// PrintedObjects returns the objects printed by the report command.
+func (c *reportCmd) PrintedObjects() []any {
+	return []any{aggregate.CostCenterUsage{}}
+}
+
+// Run executes the report command.
+func (c *reportCmd) Run(ctx context.Context, printer upterm.ObjectPrinter) error {
+	files, err := reporttar.Open(c.Path)
+	if err != nil {
+		return err
+	}
+	m, err := reporttar.Verify(files)
+	if err != nil {
+		return err
+	}
+	events, err := reporttar.Events(files, m)
+	if err != nil {
+		return err
+	}
+	ccs, err := c.costCenters(ctx)
+	if err != nil {
+		return err
+	}
+	ag := &aggregate.ResourceHoursPerCostCenter{CostCenters: ccs}
+	for _, e := range events {
+		if err := ag.Add(e); err != nil {
+			return err
+		}
+	}
+	return printer.Print(ag.Usage(), costCenterFieldNames, extractCostCenterFields)
+}
+
+// costCenters returns the cost centers of control planes by ID.
+func (c *reportCmd) costCenters(ctx context.Context) (map[string]string, error) {
+	if c.CostCenters != "" {
+		b, err := os.ReadFile(c.CostCenters)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadCostCenters)
+		}
+		ccs := map[string]string{}
+		if err := json.Unmarshal(b, &ccs); err != nil {
+			return nil, errors.Wrap(err, errParseCostCenters)
+		}
+		return ccs, nil
+	}
+
+	upCtx, err := upbound.NewFromFlags(c.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if upCtx.Profile.Type != config.SpaceProfileType {
+		return nil, errors.New(errCostCentersSpaceOnly)
+	}
+	kubeconfig, err := kube.GetKubeConfigWithContext(upCtx.Profile.Kubeconfig, upCtx.Profile.KubeContext)
+	if err != nil {
+		return nil, err
+	}
+	if upCtx.WrapTransport != nil {
+		kubeconfig.Wrap(upCtx.WrapTransport)
+	}
+	dc, err := dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	ctps, err := spaces.NewControlPlaneClient(dc).List(ctx, resources.CostCenterLabel)
+	if err != nil {
+		return nil, err
+	}
+	ccs := make(map[string]string, len(ctps))
+	for _, ctp := range ctps {
+		ccs[string(ctp.GetUID())] = ctp.GetLabels()[resources.CostCenterLabel]
+	}
+	return ccs, nil
+}
+
+func extractCostCenterFields(obj any) []string {
+	u := obj.(aggregate.CostCenterUsage)
+	cc := u.CostCenter
+	if cc == "" {
+		cc = noCostCenter
+	}
+	return []string{
+		cc,
+		fmt.Sprint(len(u.ControlPlanes)),
+		fmt.Sprint(u.Events),
+		fmt.Sprintf("%.0f", u.ResourceHours),
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/usage/aggregate"
+)
+
+// costCenterUsageV1 is version v1 of the machine output of the usage of a
+// cost center.
+type costCenterUsageV1 struct {
+	CostCenter    string   `json:"costCenter"`
+	ControlPlanes []string `json:"controlPlanes"`
+	Events        int      `json:"events"`
+	ResourceHours float64  `json:"resourceHours"`
+}
+
+func init() {
+	upterm.RegisterSchema(aggregate.CostCenterUsage{}, upterm.Schema{
+		Kind:    "CostCenterUsage",
+		Version: "v1",
+		Convert: func(obj any) any {
+			u := obj.(aggregate.CostCenterUsage)
+			return costCenterUsageV1{CostCenter: u.CostCenter, ControlPlanes: u.ControlPlanes, Events: u.Events, ResourceHours: u.ResourceHours}
+		},
+	})
+}
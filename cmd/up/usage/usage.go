@@ -0,0 +1,23 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package usage contains commands for collecting usage data.
+package usage
+
+// Cmd contains commands for collecting usage data.
+type Cmd struct {
+	Collect collectCmd `cmd:"" help:"Collect usage data from storage into a local directory."`
+	Verify  verifyCmd  `cmd:"" help:"Verify a usage export against its manifest."`
+	Report  reportCmd  `cmd:"" help:"Report the usage in a usage export by cost center."`
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"time"
+
+	"github.com/pterm/pterm"
+
+	reporttar "github.com/upbound/up/internal/usage/report/file/tar"
+)
+
+// verifyCmd verifies a usage export against its manifest.
+type verifyCmd struct {
+	Path string `arg:"" type:"path" help:"Usage export to verify. Either the archive written by 'up space billing get', or a directory into which it has been extracted."`
+}
+
+// Help returns the help text for the verify command.
+func (c *verifyCmd) Help() string {
+	return `
+Usage exports contain a manifest that lists the SHA-256 checksum and number of
+events of each usage file, and the account and time range covered by the
+export. Verifying an export before submitting it ensures that no usage file is
+missing, has been modified, or has been added.`
+}
+
+// Run executes the verify command.
+func (c *verifyCmd) Run(p pterm.TextPrinter) error {
+	files, err := reporttar.Open(c.Path)
+	if err != nil {
+		return err
+	}
+	m, err := reporttar.Verify(files)
+	if err != nil {
+		return err
+	}
+	p.Printfln("Usage export for account %s from %s to %s is valid: %d events in %d usage files.",
+		m.UpboundAccount,
+		m.TimeRange.Start.Format(time.RFC3339),
+		m.TimeRange.End.Format(time.RFC3339),
+		m.Events,
+		len(m.Parts),
+	)
+	if m.Filter != nil {
+		p.Printfln("The export is filtered and only contains usage selected by: %s.", m.Filter)
+	}
+	return nil
+}
@@ -16,7 +16,6 @@ package uxp
 
 import (
 	"context"
-	"io"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
@@ -24,10 +23,11 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/yaml"
 
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/secrets"
+	"github.com/upbound/up/internal/upterm"
 )
 
 const (
@@ -36,7 +36,7 @@ const (
 )
 
 // AfterApply sets default values in command after assignment and validation.
-func (c *installCmd) AfterApply(insCtx *install.Context) error {
+func (c *installCmd) AfterApply(ctx context.Context, insCtx *install.Context) error {
 	repo := RepoURL
 	if c.Unstable {
 		repo = uxpUnstableRepoURL
@@ -56,21 +56,15 @@ func (c *installCmd) AfterApply(insCtx *install.Context) error {
 		return err
 	}
 	c.kClient = client
-	base := map[string]any{}
-	if c.File != nil {
-		defer c.File.Close() //nolint:errcheck,gosec
-		b, err := io.ReadAll(c.File)
-		if err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := yaml.Unmarshal(b, &base); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := c.File.Close(); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
+	}
+	secretParams, err := c.SecretParameters(ctx, secrets.NewResolver())
+	if err != nil {
+		return err
 	}
-	c.parser = helm.NewParser(base, c.Set)
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithLiteralOverrides(secretParams))
 	return nil
 }
 
@@ -87,9 +81,9 @@ type installCmd struct {
 }
 
 // Run executes the install command.
-func (c *installCmd) Run(p pterm.TextPrinter, insCtx *install.Context) error {
+func (c *installCmd) Run(ctx context.Context, p pterm.TextPrinter, insCtx *install.Context) error {
 	// Create namespace if it does not exist.
-	_, err := c.kClient.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{
+	_, err := c.kClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: insCtx.Namespace,
 		},
@@ -15,14 +15,31 @@
 package uxp
 
 import (
+	"context"
 	"net/url"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apixv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	"k8s.io/client-go/dynamic"
 
+	"github.com/upbound/up/internal/input"
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/upterm"
 )
 
+const (
+	errStuckManagedResources = "managed resources are stuck deleting, remove their finalizers to remove custom resource definitions"
+)
+
+// BeforeApply sets default values in command before assignment and validation.
+func (c *uninstallCmd) BeforeApply() error {
+	c.prompter = input.NewPrompter()
+	return nil
+}
+
 // AfterApply sets default values in command after assignment and validation.
 func (c *uninstallCmd) AfterApply(insCtx *install.Context) error {
 	// NOTE(hasheddan): we always pass default repo URL because the repo URL is
@@ -35,19 +52,85 @@ func (c *uninstallCmd) AfterApply(insCtx *install.Context) error {
 		return err
 	}
 	c.mgr = mgr
+	crds, err := apixv1client.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	kube, err := dynamic.NewForConfig(insCtx.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	c.cleaner = install.NewCRDCleaner(crds.CustomResourceDefinitions(), kube)
 	return nil
 }
 
 // uninstallCmd uninstalls UXP.
 type uninstallCmd struct {
-	mgr install.Manager
+	mgr      install.Manager
+	cleaner  *install.CRDCleaner
+	prompter input.Prompter
+
+	RemoveCRDs      bool `name:"remove-crds" help:"Remove the custom resource definitions of Crossplane, its packages and composite resources once no managed resources remain."`
+	ForceFinalizers bool `help:"Remove the finalizers of managed resources that are stuck deleting when removing custom resource definitions, without prompting. Their external resources are left behind."`
 }
 
 // Run executes the uninstall command.
-func (c *uninstallCmd) Run(p pterm.TextPrinter, insCtx *install.Context) error {
+func (c *uninstallCmd) Run(ctx context.Context, p pterm.TextPrinter) error {
 	if err := c.mgr.Uninstall(); err != nil {
 		return err
 	}
 	p.Printfln("UXP uninstalled")
+	if !c.RemoveCRDs {
+		return nil
+	}
+	crds, err := c.cleaner.CRDs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.removeStuckFinalizers(ctx, p, crds); err != nil {
+		return err
+	}
+	if err := c.cleaner.DeleteCRDs(ctx, crds); err != nil {
+		return err
+	}
+	p.Printfln("%d custom resource definitions removed", len(crds))
+	return nil
+}
+
+// removeStuckFinalizers removes the finalizers of managed resources that are
+// being deleted but cannot be, for instance because their provider is gone.
+func (c *uninstallCmd) removeStuckFinalizers(ctx context.Context, p pterm.TextPrinter, crds []apiextensionsv1.CustomResourceDefinition) error {
+	mrs, err := c.cleaner.ManagedResources(ctx, crds)
+	if err != nil {
+		return err
+	}
+	stuck := make([]install.ManagedResource, 0, len(mrs))
+	for _, mr := range mrs {
+		if mr.Stuck() {
+			stuck = append(stuck, mr)
+		}
+	}
+	if len(stuck) == 0 {
+		return nil
+	}
+	upterm.Warnf("%d managed resources are stuck deleting:", len(stuck))
+	for _, mr := range stuck {
+		p.Printfln("  %s/%s %v", mr.Kind, mr.Name, mr.Finalizers)
+	}
+	if !c.ForceFinalizers {
+		confirm, err := c.prompter.Prompt("Remove their finalizers? Their external resources will be left behind. [y/n]", false)
+		if err != nil {
+			return err
+		}
+		if !input.InputYes(confirm) {
+			return errors.New(errStuckManagedResources)
+		}
+	}
+	for _, mr := range stuck {
+		if err := c.cleaner.RemoveFinalizers(ctx, mr); err != nil {
+			return err
+		}
+	}
+	p.Printfln("Finalizers of %d managed resources removed", len(stuck))
 	return nil
 }
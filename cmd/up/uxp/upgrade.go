@@ -15,14 +15,15 @@
 package uxp
 
 import (
-	"io"
+	"context"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/pterm/pterm"
-	"sigs.k8s.io/yaml"
 
 	"github.com/upbound/up/internal/install"
 	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/secrets"
+	"github.com/upbound/up/internal/upterm"
 )
 
 const (
@@ -30,7 +31,7 @@ const (
 )
 
 // AfterApply sets default values in command after assignment and validation.
-func (c *upgradeCmd) AfterApply(insCtx *install.Context) error {
+func (c *upgradeCmd) AfterApply(ctx context.Context, insCtx *install.Context) error {
 	repo := RepoURL
 	if c.Unstable {
 		repo = uxpUnstableRepoURL
@@ -47,21 +48,15 @@ func (c *upgradeCmd) AfterApply(insCtx *install.Context) error {
 		return err
 	}
 	c.mgr = ins
-	base := map[string]any{}
-	if c.File != nil {
-		defer c.File.Close() //nolint:errcheck,gosec
-		b, err := io.ReadAll(c.File)
-		if err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := yaml.Unmarshal(b, &base); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
-		if err := c.File.Close(); err != nil {
-			return errors.Wrap(err, errReadParametersFile)
-		}
+	files, err := c.Parameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, errReadParametersFile)
+	}
+	secretParams, err := c.SecretParameters(ctx, secrets.NewResolver())
+	if err != nil {
+		return err
 	}
-	c.parser = helm.NewParser(base, c.Set)
+	c.parser = helm.NewParser(map[string]any{}, c.Set, helm.WithValuesFiles(files...), helm.WithWarnFn(upterm.Warnf), helm.WithLiteralOverrides(secretParams))
 	return nil
 }
 
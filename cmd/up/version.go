@@ -0,0 +1,214 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/upbound/up/internal/install/helm"
+	"github.com/upbound/up/internal/kube"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/version"
+)
+
+const (
+	componentUp         = "up"
+	componentKubernetes = "Kubernetes"
+	componentSpaces     = "Spaces"
+	componentUXP        = "UXP"
+	componentCrossplane = "Crossplane"
+
+	// upboundNamespace is the namespace Spaces and UXP are installed in.
+	upboundNamespace = "upbound-system"
+	spacesRelease    = "spaces"
+	uxpRelease       = "universal-crossplane"
+	crossplaneName   = "crossplane"
+
+	versionNotInstalled = "not installed"
+	versionUnknown      = "unknown"
+)
+
+var versionFieldNames = []string{"COMPONENT", "VERSION", "DETAILS"}
+
+// crossplaneNamespaces are the namespaces the Crossplane deployment is looked
+// up in, in order.
+var crossplaneNamespaces = []string{upboundNamespace, "crossplane-system"}
+
+// componentVersion is the version of a single component.
+type componentVersion struct {
+	Component string `json:"component"`
+	Version   string `json:"version"`
+	Details   string `json:"details,omitempty"`
+}
+
+// componentVersionV1 is version v1 of the machine output of a component
+// version.
+type componentVersionV1 struct {
+	Component string `json:"component"`
+	Version   string `json:"version"`
+	Details   string `json:"details,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(componentVersion{}, upterm.Schema{
+		Kind:    "ComponentVersion",
+		Version: "v1",
+		Convert: func(obj any) any {
+			v := obj.(componentVersion)
+			return componentVersionV1{Component: v.Component, Version: v.Version, Details: v.Details}
+		},
+	})
+}
+
+// AfterApply sets default values in command after assignment and validation.
+func (c *versionCmd) AfterApply(kongCtx *kong.Context) error {
+	kongCtx.Bind(pterm.DefaultTable.WithWriter(kongCtx.Stdout).WithSeparator("   "))
+	return nil
+}
+
+// versionCmd prints the versions of up and the components in the current
+// cluster.
+type versionCmd struct {
+	Client bool `xor:"version-scope" help:"Only show the version of up."`
+	Server bool `xor:"version-scope" help:"Only show the versions of the components in the current cluster."`
+
+	Kubeconfig string `type:"existingfile" help:"Override default kubeconfig path."`
+}
+
+// Help returns the help text for the version command.
+func (c *versionCmd) Help() string {
+	return `
+Prints the version of up along with the versions of the Kubernetes API server,
+Spaces, UXP, and Crossplane in the current cluster, so that bug reports can
+include all of them. Components that cannot be reached are reported with the
+reason, rather than failing the command.`
+}
+
+// PrintedObjects returns the objects printed by the version command.
+func (c *versionCmd) PrintedObjects() []any {
+	return []any{componentVersion{}}
+}
+
+// Run executes the version command.
+func (c *versionCmd) Run(ctx context.Context, printer upterm.ObjectPrinter) error {
+	vs := []componentVersion{}
+	if !c.Server {
+		vs = append(vs, componentVersion{
+			Component: componentUp,
+			Version:   version.GetVersion(),
+			Details:   fmt.Sprintf("%s, %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH),
+		})
+	}
+	if !c.Client {
+		vs = append(vs, c.serverVersions(ctx)...)
+	}
+	return printer.Print(vs, versionFieldNames, extractVersionFields)
+}
+
+// serverVersions returns the versions of the components in the cluster of
+// the kubeconfig.
+func (c *versionCmd) serverVersions(ctx context.Context) []componentVersion {
+	kubeconfig, err := kube.GetKubeConfig(c.Kubeconfig)
+	if err != nil {
+		return []componentVersion{unreachable(componentKubernetes, err)}
+	}
+	client, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return []componentVersion{unreachable(componentKubernetes, err)}
+	}
+	return clusterVersions(ctx, client)
+}
+
+// clusterVersions returns the versions of the API server, Spaces, UXP, and
+// Crossplane. If the API server cannot be reached the other components are
+// not queried.
+func clusterVersions(ctx context.Context, client kubernetes.Interface) []componentVersion {
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return []componentVersion{unreachable(componentKubernetes, err)}
+	}
+	secrets := client.CoreV1().Secrets(upboundNamespace)
+	return []componentVersion{
+		{Component: componentKubernetes, Version: info.GitVersion, Details: info.Platform},
+		releaseVersion(componentSpaces, secrets, spacesRelease),
+		releaseVersion(componentUXP, secrets, uxpRelease),
+		crossplaneVersion(ctx, client),
+	}
+}
+
+// releaseVersion returns the version of the component installed by the helm
+// release.
+func releaseVersion(component string, secrets corev1client.SecretInterface, release string) componentVersion {
+	v, err := helm.DeployedVersion(secrets, release)
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		return componentVersion{Component: component, Version: versionNotInstalled}
+	case err != nil:
+		return unreachable(component, err)
+	}
+	return componentVersion{Component: component, Version: v, Details: fmt.Sprintf("release %s/%s", upboundNamespace, release)}
+}
+
+// crossplaneVersion returns the version of Crossplane from the image tag of
+// its deployment, which covers both UXP and upstream installations.
+func crossplaneVersion(ctx context.Context, client kubernetes.Interface) componentVersion {
+	for _, ns := range crossplaneNamespaces {
+		d, err := client.AppsV1().Deployments(ns).Get(ctx, crossplaneName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return unreachable(componentCrossplane, err)
+		}
+		for _, ctr := range d.Spec.Template.Spec.Containers {
+			if ctr.Name != crossplaneName && len(d.Spec.Template.Spec.Containers) > 1 {
+				continue
+			}
+			return componentVersion{Component: componentCrossplane, Version: imageTag(ctr.Image), Details: fmt.Sprintf("deployment %s/%s", ns, crossplaneName)}
+		}
+	}
+	return componentVersion{Component: componentCrossplane, Version: versionNotInstalled}
+}
+
+// imageTag returns the tag of an image reference, ignoring any digest.
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return versionUnknown
+	}
+	return image[i+1:]
+}
+
+func unreachable(component string, err error) componentVersion {
+	return componentVersion{Component: component, Version: versionUnknown, Details: err.Error()}
+}
+
+func extractVersionFields(obj any) []string {
+	v := obj.(componentVersion)
+	return []string{v.Component, v.Version, v.Details}
+}
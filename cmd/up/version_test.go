@@ -0,0 +1,107 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+func TestClusterVersions(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: crossplaneName, Namespace: "crossplane-system"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: crossplaneName, Image: "xpkg.upbound.io/crossplane/crossplane:v1.13.2"}},
+				},
+			},
+		},
+	})
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &kversion.Info{GitVersion: "v1.27.3", Platform: "linux/amd64"}
+	rel := &release.Release{
+		Name:    spacesRelease,
+		Version: 2,
+		Info:    &release.Info{Status: release.StatusDeployed},
+		Chart:   &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.1"}},
+	}
+	if err := driver.NewSecrets(client.CoreV1().Secrets(upboundNamespace)).Create("sh.helm.release.v1.spaces.v2", rel); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	want := []componentVersion{
+		{Component: componentKubernetes, Version: "v1.27.3", Details: "linux/amd64"},
+		{Component: componentSpaces, Version: "1.0.1", Details: "release upbound-system/spaces"},
+		{Component: componentUXP, Version: versionNotInstalled},
+		{Component: componentCrossplane, Version: "v1.13.2", Details: "deployment crossplane-system/crossplane"},
+	}
+	if diff := cmp.Diff(want, clusterVersions(context.Background(), client)); diff != "" {
+		t.Errorf("clusterVersions(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	cases := map[string]struct {
+		image string
+		want  string
+	}{
+		"Tag":            {image: "crossplane/crossplane:v1.13.2", want: "v1.13.2"},
+		"TagAndDigest":   {image: "crossplane/crossplane:v1.13.2@sha256:abc", want: "v1.13.2"},
+		"RegistryPort":   {image: "localhost:5000/crossplane", want: versionUnknown},
+		"NoTag":          {image: "crossplane/crossplane", want: versionUnknown},
+		"RegistryAndTag": {image: "localhost:5000/crossplane:v1.14.0", want: "v1.14.0"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, imageTag(tc.image)); diff != "" {
+				t.Errorf("imageTag(%q): -want, +got:\n%s", tc.image, diff)
+			}
+		})
+	}
+}
+
+func TestComponentVersionSchemaV1(t *testing.T) {
+	vs := []componentVersion{
+		{Component: componentUp, Version: "v0.21.0"},
+		{Component: componentSpaces, Version: "1.0.1", Details: "release upbound-system/spaces"},
+	}
+	got, err := upterm.MachineJSON(vs)
+	if err != nil {
+		t.Fatalf("MachineJSON(...): %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "componentversionlist-v1.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+		t.Errorf("MachineJSON(...): -want, +got:\n%s", diff)
+	}
+}
@@ -125,7 +125,7 @@ type batchCmd struct {
 }
 
 // Run executes the batch command.
-func (c *batchCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { //nolint:gocyclo
+func (c *batchCmd) Run(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context) error { //nolint:gocyclo
 	baseImgMap := make(map[string]v1.Image, len(c.Platform))
 	for _, p := range c.Platform {
 		tokens := strings.Split(p, "_")
@@ -136,7 +136,7 @@ func (c *batchCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { //no
 		if err != nil {
 			return err
 		}
-		img, err := c.fetch(context.Background(), ref)
+		img, err := c.fetch(ctx, ref)
 		if err != nil {
 			return err
 		}
@@ -160,7 +160,7 @@ func (c *batchCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { //no
 					concurrency <- struct{}{}
 				}()
 			}
-			err := c.processService(p, upCtx, baseImgMap, s)
+			err := c.processService(ctx, p, upCtx, baseImgMap, s)
 			p.PrintOnErrorf(fmt.Sprintf("Publishing of smaller provider package has failed for service %q: %%v", s), err)
 			chErr <- errors.WithMessagef(err, errProcessFmt, s)
 		}()
@@ -188,7 +188,7 @@ func (c *batchCmd) Run(p pterm.TextPrinter, upCtx *upbound.Context) error { //no
 // the smaller provider controller binary (which is platform specific) on top
 // of the addendum layers and then pushes the built multi-arch package
 // (if `len(c.Platforms) > 1`) to the specified package repository.
-func (c *batchCmd) processService(p pterm.TextPrinter, upCtx *upbound.Context, baseImgMap map[string]v1.Image, s string) error { //nolint:gocyclo
+func (c *batchCmd) processService(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context, baseImgMap map[string]v1.Image, s string) error { //nolint:gocyclo
 	imgs := make([]v1.Image, 0, len(c.Platform))
 	// image layers added on top of the base image by xpkg push to be reused
 	// across the platforms so that they are computed only once.
@@ -255,7 +255,7 @@ func (c *batchCmd) processService(p pterm.TextPrinter, upCtx *upbound.Context, b
 		return nil
 	}
 	// now try to push the package with the specified retry configuration.
-	return c.pushWithRetry(p, upCtx, imgs, s)
+	return c.pushWithRetry(ctx, p, upCtx, imgs, s)
 }
 
 // Optionally stores the provider package under the configured directory,
@@ -305,13 +305,13 @@ func (c *batchCmd) getPackageVersion() string {
 	return tokens[len(tokens)-1]
 }
 
-func (c *batchCmd) pushWithRetry(p pterm.TextPrinter, upCtx *upbound.Context, imgs []v1.Image, s string) error {
+func (c *batchCmd) pushWithRetry(ctx context.Context, p pterm.TextPrinter, upCtx *upbound.Context, imgs []v1.Image, s string) error {
 	t := c.getPackageURL(s)
 	tries := c.PushRetry + 1
 	retryMsg := ""
 	for i := uint(0); i < tries; i++ {
 		p.Printfln("Pushing xpkg to %s.%s", t, retryMsg)
-		err := PushImages(p, upCtx, imgs, t, c.Create, c.Flags.Profile)
+		err := PushImages(ctx, p, upCtx, imgs, t, c.Create, c.Flags.Profile)
 		if err == nil {
 			break
 		}
@@ -30,6 +30,7 @@ import (
 	"github.com/upbound/up/internal/xpkg"
 	"github.com/upbound/up/internal/xpkg/parser/examples"
 	"github.com/upbound/up/internal/xpkg/parser/yaml"
+	"github.com/upbound/up/internal/xpkg/render"
 )
 
 const (
@@ -82,11 +83,16 @@ func (c *buildCmd) AfterApply() error {
 					xpkg.SkipContains(c.ExamplesRoot), xpkg.SkipContains(c.AuthExt))...),
 		),
 		authBE,
+		// Golden files written by the test command sit next to the examples
+		// but hold the resources composed from them, which are not examples of
+		// the package's APIs, so they are left out of the package.
 		parser.NewFsBackend(
 			c.fs,
 			parser.FsDir(ex),
 			parser.FsFilters(
-				buildFilters(ex, c.Ignore)...),
+				append(
+					buildFilters(ex, c.Ignore),
+					xpkg.SkipContains(render.GoldenSuffix))...),
 		),
 		pp,
 		examples.New(),
@@ -124,7 +130,8 @@ will use this information to install the package into a Crossplane instance.
 
 Only configuration and provider packages are supported at this time. 
 
-Example claims can be specified in the examples directory.
+Example claims can be specified in the examples directory. Golden files written
+by the test command are not included in the package.
 
 For more generic information, see the xpkg parent command help. Also see the
 Crossplane documentation for more information on building packages:
@@ -135,20 +142,20 @@ Even more details can be found in the xpkg reference document.`
 }
 
 // Run executes the build command.
-func (c *buildCmd) Run(p pterm.TextPrinter) error { //nolint:gocyclo
+func (c *buildCmd) Run(ctx context.Context, p pterm.TextPrinter) error { //nolint:gocyclo
 	var buildOpts []xpkg.BuildOpt
 	if c.Controller != "" {
 		ref, err := name.ParseReference(c.Controller)
 		if err != nil {
 			return err
 		}
-		base, err := c.fetch(context.Background(), ref)
+		base, err := c.fetch(ctx, ref)
 		if err != nil {
 			return err
 		}
 		buildOpts = append(buildOpts, xpkg.WithController(base))
 	}
-	img, meta, err := c.builder.Build(context.Background(), buildOpts...)
+	img, meta, err := c.builder.Build(ctx, buildOpts...)
 	if err != nil {
 		return errors.Wrap(err, errBuildPackage)
 	}
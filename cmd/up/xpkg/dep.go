@@ -39,9 +39,8 @@ const (
 
 // AfterApply constructs and binds Upbound-specific context to any subcommands
 // that have Run() methods that receive it.
-func (c *depCmd) AfterApply(kongCtx *kong.Context, p pterm.TextPrinter) error {
+func (c *depCmd) AfterApply(ctx context.Context, kongCtx *kong.Context, p pterm.TextPrinter) error {
 	kongCtx.Bind(pterm.DefaultBulletList.WithWriter(kongCtx.Stdout))
-	ctx := context.Background()
 	fs := afero.NewOsFs()
 
 	cache, err := cache.NewLocal(c.CacheDir)
@@ -82,9 +81,6 @@ func (c *depCmd) AfterApply(kongCtx *kong.Context, p pterm.TextPrinter) error {
 			return err
 		}
 	}
-
-	// workaround interfaces not being bindable ref: https://github.com/alecthomas/kong/issues/48
-	kongCtx.BindTo(ctx, (*context.Context)(nil))
 	return nil
 }
 
@@ -0,0 +1,123 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/xpkg/dep/cache"
+	"github.com/upbound/up/internal/xpkg/dep/manager"
+	"github.com/upbound/up/internal/xpkg/dep/resolver/image"
+	"github.com/upbound/up/internal/xpkg/lint"
+)
+
+const (
+	errFmtLintFailed = "found %d errors and %d warnings"
+)
+
+var lintFieldNames = []string{"LOCATION", "SEVERITY", "RULE", "MESSAGE"}
+
+// AfterApply constructs the linter for the package directory.
+func (c *lintCmd) AfterApply() error {
+	root, err := filepath.Abs(c.PackageRoot)
+	if err != nil {
+		return err
+	}
+	cache, err := cache.NewLocal(c.CacheDir)
+	if err != nil {
+		return err
+	}
+	m, err := manager.New(
+		manager.WithCache(cache),
+		manager.WithResolver(image.NewResolver()),
+	)
+	if err != nil {
+		return err
+	}
+	c.linter = lint.New(root, lint.WithDepManager(m))
+	return nil
+}
+
+// lintCmd lints a package directory.
+type lintCmd struct {
+	linter *lint.Linter
+
+	PackageRoot string `short:"f" help:"Path to package directory." default:"."`
+	CacheDir    string `short:"d" help:"Directory used for caching package dependencies." default:"~/.up/cache/" env:"CACHE_DIR" type:"path"`
+	FailOn      string `enum:"error,warning" default:"error" help:"Severity of findings at which the command fails. Must be one of: error, warning."`
+}
+
+// Help returns the help text for the lint command.
+func (c *lintCmd) Help() string {
+	return `
+The lint command validates a package directory before it is built or pushed.
+It reports files that are not valid YAML, problems with the package metadata in
+crossplane.yaml, objects that the kind of package cannot contain, invalid CRD
+and XRD schemas, Compositions that reference undefined composite resources, and
+example manifests that do not match the schema of the XRD or CRD that defines
+them. Examples must be in a directory named examples within the package
+directory.
+
+The dependencies of the package are fetched into the cache so that the
+resources its Compositions compose can be validated.
+
+Each finding has a severity, error or warning, and the rule that produced it.
+Use --format=json or --format=yaml for machine-readable findings. The command
+fails if any finding is at least as severe as --fail-on, so that it can gate
+builds in CI.`
+}
+
+// PrintedObjects returns the objects printed by the lint command.
+func (c *lintCmd) PrintedObjects() []any {
+	return []any{lint.Finding{}}
+}
+
+// Run executes the lint command.
+func (c *lintCmd) Run(ctx context.Context, printer upterm.ObjectPrinter) error {
+	findings, err := c.linter.Lint(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printer.Print(findings, lintFieldNames, extractLintFields); err != nil {
+		return err
+	}
+	errs, warns := 0, 0
+	for _, f := range findings {
+		switch f.Severity {
+		case lint.SeverityError:
+			errs++
+		case lint.SeverityWarning:
+			warns++
+		}
+	}
+	if errs > 0 || (c.FailOn == string(lint.SeverityWarning) && warns > 0) {
+		return errors.Errorf(errFmtLintFailed, errs, warns)
+	}
+	return nil
+}
+
+func extractLintFields(obj any) []string {
+	f := obj.(lint.Finding)
+	loc := f.File
+	if f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+	return []string{loc, string(f.Severity), f.Rule, f.Message}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/xpkg/lint"
+	"github.com/upbound/up/internal/xpkg/render"
+)
+
+// lintFindingV1 is version v1 of the machine output of a problem found by
+// linting a package.
+type lintFindingV1 struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+// testResultV1 is version v1 of the machine output of the result of rendering
+// an example of a package.
+type testResultV1 struct {
+	Example string `json:"example"`
+	Golden  string `json:"golden"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(lint.Finding{}, upterm.Schema{
+		Kind:    "LintFinding",
+		Version: "v1",
+		Convert: func(obj any) any {
+			f := obj.(lint.Finding)
+			return lintFindingV1{Severity: string(f.Severity), Rule: f.Rule, File: f.File, Line: f.Line, Column: f.Column, Message: f.Message}
+		},
+	})
+	upterm.RegisterSchema(render.Result{}, upterm.Schema{
+		Kind:    "TestResult",
+		Version: "v1",
+		Convert: func(obj any) any {
+			r := obj.(render.Result)
+			return testResultV1{Example: r.Example, Golden: r.Golden, Status: string(r.Status), Message: r.Message, Diff: r.Diff}
+		},
+	})
+}
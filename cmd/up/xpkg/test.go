@@ -0,0 +1,118 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpkg
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/pterm/pterm"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/config"
+	"github.com/upbound/up/internal/upterm"
+	"github.com/upbound/up/internal/xpkg/render"
+)
+
+const (
+	errFmtTestFailed = "%d of %d examples failed"
+)
+
+var testFieldNames = []string{"EXAMPLE", "GOLDEN", "STATUS", "MESSAGE"}
+
+// AfterApply constructs the tester for the package directory.
+func (c *testCmd) AfterApply() error {
+	root, err := filepath.Abs(c.PackageRoot)
+	if err != nil {
+		return err
+	}
+	ex, err := filepath.Abs(c.ExamplesRoot)
+	if err != nil {
+		return err
+	}
+	c.tester = render.New(root, ex, render.WithUpdate(c.Update))
+	return nil
+}
+
+// testCmd renders the Compositions of a package against its examples.
+type testCmd struct {
+	tester *render.Tester
+
+	PackageRoot  string `short:"f" help:"Path to package directory." default:"."`
+	ExamplesRoot string `short:"e" help:"Path to package examples directory." default:"./examples"`
+	Update       bool   `help:"Write the rendered resources to the golden files instead of comparing them."`
+}
+
+// Help returns the help text for the test command.
+func (c *testCmd) Help() string {
+	return `
+The test command renders the Compositions of a package against the example
+composite resources and claims in its examples directory, and compares the
+composed resources to golden files. The golden file for an example file is
+next to it, with the extension replaced by .golden.yaml, so the golden file for
+examples/bucket.yaml is examples/bucket.golden.yaml.
+
+Claims are rendered as a composite resource with the same name as the claim.
+The Composition is selected the same way Crossplane selects it: by the
+compositionRef or compositionSelector of the example, by the default
+Composition of the XRD, or by being the only compatible Composition.
+
+Compositions that use Composition Functions are rendered the way Crossplane
+runs them: the resources of the Composition are composed first, then each
+function of the pipeline is run in a container with docker, which must be
+installed. Function images are pulled with the credentials docker is logged in
+with. Functions have no network access unless their network policy is Runner,
+and examples fail if a function returns a fatal result.
+
+Run with --update to write the golden files, then review and commit them. The
+command fails if any example fails to render or renders resources that differ
+from its golden file, and prints the differences.`
+}
+
+// PrintedObjects returns the objects printed by the test command.
+func (c *testCmd) PrintedObjects() []any {
+	return []any{render.Result{}}
+}
+
+// Run executes the test command.
+func (c *testCmd) Run(ctx context.Context, printer upterm.ObjectPrinter, p pterm.TextPrinter) error {
+	results, err := c.tester.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printer.Print(results, testFieldNames, extractTestFields); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status != render.StatusFail {
+			continue
+		}
+		failed++
+		if r.Diff != "" && printer.Format == config.Default && !printer.Quiet {
+			p.Printfln("--- %s\n+++ %s (rendered)\n%s", r.Golden, r.Example, r.Diff)
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtTestFailed, failed, len(results))
+	}
+	return nil
+}
+
+func extractTestFields(obj any) []string {
+	r := obj.(render.Result)
+	return []string{r.Example, r.Golden, string(r.Status), r.Message}
+}
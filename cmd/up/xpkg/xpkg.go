@@ -28,6 +28,8 @@ func (c *Cmd) BeforeReset(p *kong.Path, maturity feature.Maturity) error {
 // Cmd contains commands for interacting with xpkgs.
 type Cmd struct {
 	Build     buildCmd     `cmd:"" help:"Build a package, by default from the current directory."`
+	Lint      lintCmd      `cmd:"" help:"Lint a package, by default in the current directory."`
+	Test      testCmd      `cmd:"" help:"Render the Compositions of a package against its examples and compare them to golden files."`
 	XPExtract xpExtractCmd `cmd:"" maturity:"alpha" help:"Extract package contents into a Crossplane cache compatible format. Fetches from a remote registry by default."`
 	Init      initCmd      `cmd:"" help:"Initialize a package, by default in the current directory."`
 	Dep       depCmd       `cmd:"" help:"Manage package dependencies in the filesystem and populate the cache, e.g. used by the Crossplane Language Server."`
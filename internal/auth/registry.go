@@ -55,7 +55,7 @@ type Provider interface {
 func NewProvider(modifiers ...ProviderModifierFn) *UpboundRegistry {
 
 	p := &UpboundRegistry{
-		client: &http.Client{},
+		client: uphttp.NewClient(),
 	}
 
 	for _, m := range modifiers {
@@ -20,6 +20,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
@@ -49,11 +52,157 @@ const (
 	Default Format = "default"
 	JSON    Format = "json"
 	YAML    Format = "yaml"
+	// Machine prints JSON following a versioned schema that does not change
+	// along with the table output.
+	Machine Format = "machine"
 )
 
 // Config is format for the up configuration file.
 type Config struct {
 	Upbound Upbound `json:"upbound"`
+
+	// Telemetry configures collection of anonymous usage metrics. Telemetry
+	// is disabled if unset.
+	Telemetry *Telemetry `json:"telemetry,omitempty"`
+
+	// Updates configures how up checks for new versions of itself.
+	Updates *Updates `json:"updates,omitempty"`
+
+	// Features configures the visibility of commands that are not yet
+	// stable.
+	Features *Features `json:"features,omitempty"`
+
+	// Output configures the default output of commands.
+	Output *Output `json:"output,omitempty"`
+
+	// Aliases map names to the commands and arguments they expand to, e.g.
+	// "ls" to "controlplane list". An alias is only expanded if it is the
+	// first argument and does not shadow a command.
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// CommandDefaults map commands, e.g. "controlplane list", to flags in the
+	// form --name=value that are supplied to the command unless they are set
+	// on the command line.
+	CommandDefaults map[string][]string `json:"commandDefaults,omitempty"`
+
+	// Hooks map commands, e.g. "space upgrade", to shell commands that are
+	// run before and after them. Hooks of a command also run for its
+	// subcommands, and hooks of "*" run for all commands.
+	Hooks map[string]Hooks `json:"hooks,omitempty"`
+}
+
+// Hooks are shell commands that are run before and after a command.
+type Hooks struct {
+	// Pre hooks run in order before the command. The command is not run if
+	// a pre hook fails.
+	Pre []string `json:"pre,omitempty"`
+	// Post hooks run in order after the command, whether it succeeded or
+	// not.
+	Post []string `json:"post,omitempty"`
+}
+
+// AllCommands is the key of hooks that run for all commands.
+const AllCommands = "*"
+
+// CommandHooks returns the hooks of the supplied command, e.g. "usage
+// collect". Hooks of all commands are followed by hooks of parent commands,
+// then by hooks of the command itself.
+func (c *Config) CommandHooks(command string) Hooks {
+	h := Hooks{}
+	add := func(key string) {
+		if kh, ok := c.Hooks[key]; ok {
+			h.Pre = append(h.Pre, kh.Pre...)
+			h.Post = append(h.Post, kh.Post...)
+		}
+	}
+	add(AllCommands)
+	parts := strings.Fields(command)
+	for i := range parts {
+		add(strings.Join(parts[:i+1], " "))
+	}
+	return h
+}
+
+// Output contains default output settings. Flags supplied to a command take
+// precedence.
+type Output struct {
+	// Format is the default format for get and list commands.
+	Format Format `json:"format,omitempty"`
+	// NoColor disables colored and animated output.
+	NoColor bool `json:"noColor,omitempty"`
+	// RedactKeys are parameter keys whose values are redacted when install
+	// and upgrade parameters are printed, in addition to keys containing
+	// token, password, or key.
+	RedactKeys []string `json:"redactKeys,omitempty"`
+}
+
+// OutputFormat returns the configured default output format.
+func (c *Config) OutputFormat() Format {
+	if c.Output == nil || c.Output.Format == "" {
+		return Default
+	}
+	return c.Output.Format
+}
+
+// RedactedKeys returns the configured additional parameter keys whose values
+// are redacted.
+func (c *Config) RedactedKeys() []string {
+	if c.Output == nil {
+		return nil
+	}
+	return c.Output.RedactKeys
+}
+
+// ColorEnabled returns false if the user has disabled colored output.
+func (c *Config) ColorEnabled() bool {
+	return c.Output == nil || !c.Output.NoColor
+}
+
+// Features contains configuration for commands that are not yet stable.
+type Features struct {
+	// ShowMaturing shows alpha and beta commands alongside stable commands
+	// in help output rather than only in the alpha and beta namespaces.
+	ShowMaturing bool `json:"showMaturing,omitempty"`
+}
+
+// ShowMaturingCommands returns true if the user has opted in to showing
+// alpha and beta commands alongside stable commands.
+func (c *Config) ShowMaturingCommands() bool {
+	return c.Features != nil && c.Features.ShowMaturing
+}
+
+// Updates contains configuration for checking for new versions of up.
+type Updates struct {
+	// Channel is the release channel to check for new versions. Defaults to
+	// stable if unset.
+	Channel string `json:"channel,omitempty"`
+	// DisableNotifications disables warnings that a newer version of up is
+	// available.
+	DisableNotifications bool `json:"disableNotifications,omitempty"`
+	// LastChecked is the last time up checked for a new version.
+	LastChecked time.Time `json:"lastChecked,omitempty"`
+}
+
+// UpdateNotificationsEnabled returns true unless the user has disabled
+// warnings that a newer version of up is available.
+func (c *Config) UpdateNotificationsEnabled() bool {
+	return c.Updates == nil || !c.Updates.DisableNotifications
+}
+
+// Telemetry contains configuration for anonymous usage metrics.
+type Telemetry struct {
+	// Enabled indicates that the user has opted in to telemetry.
+	Enabled bool `json:"enabled"`
+
+	// Endpoint is the URL to which events are sent. There is no default, so
+	// telemetry is not sent unless an endpoint is configured.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// TelemetryEnabled returns true if the user has opted in to telemetry and
+// configured the endpoint to which it is sent.
+func (c *Config) TelemetryEnabled() bool {
+	return c.Telemetry != nil && c.Telemetry.Enabled && c.Telemetry.Endpoint != ""
 }
 
 // Extract performs extraction of configuration from the provided source.
@@ -82,6 +231,22 @@ type Upbound struct {
 	// Profiles contain sets of credentials for communicating with Upbound. Key
 	// is name of the profile.
 	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// ControlPlaneLabels contain labels for control planes. The Upbound API
+	// does not support labels, so they are stored locally. Key is the account
+	// and name of the control plane in the form account/name.
+	ControlPlaneLabels map[string]map[string]string `json:"controlPlaneLabels,omitempty"`
+
+	// ControlPlaneAnnotations contain annotations for control planes. They
+	// are stored locally for the same reason as labels, and are keyed the
+	// same way.
+	ControlPlaneAnnotations map[string]map[string]string `json:"controlPlaneAnnotations,omitempty"`
+
+	// PendingDeletions contain the times at which control planes scheduled
+	// for deletion are due to be deleted. The Upbound API does not support
+	// scheduled deletion, so it is tracked locally. Key is the account and
+	// name of the control plane in the form account/name.
+	PendingDeletions map[string]time.Time `json:"pendingDeletions,omitempty"`
 }
 
 // ProfileType is a type of Upbound profile.
@@ -91,6 +256,10 @@ type ProfileType string
 const (
 	UserProfileType  ProfileType = "user"
 	TokenProfileType ProfileType = "token"
+	// SpaceProfileType profiles manage control planes in a self-hosted Space
+	// through the Kubernetes API of the Space cluster rather than the
+	// Upbound API.
+	SpaceProfileType ProfileType = "space"
 )
 
 // A Profile is a set of credentials
@@ -107,6 +276,14 @@ type Profile struct {
 	// Account is the default account to use when this profile is selected.
 	Account string `json:"account,omitempty"`
 
+	// Kubeconfig is the path of the kubeconfig of the Space cluster of a
+	// space profile. The same defaults as kubectl are used if empty.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+
+	// KubeContext is the kubeconfig context of the Space cluster of a space
+	// profile. The current context is used if empty.
+	KubeContext string `json:"kubeContext,omitempty"`
+
 	// BaseConfig represent persisted settings for this profile.
 	// For example:
 	// * flags
@@ -266,3 +443,104 @@ func (c *Config) BaseToJSON(name string) (io.Reader, error) {
 
 	return &buf, nil
 }
+
+func controlPlaneKey(account, name string) string {
+	return account + "/" + name
+}
+
+// GetControlPlaneLabels returns the labels of the control plane with the
+// supplied name in the supplied account. If no labels are set, a nil map is
+// returned.
+func (c *Config) GetControlPlaneLabels(account, name string) map[string]string {
+	return c.Upbound.ControlPlaneLabels[controlPlaneKey(account, name)]
+}
+
+// SetControlPlaneLabels sets the labels of the control plane with the supplied
+// name in the supplied account. Setting empty labels removes them.
+func (c *Config) SetControlPlaneLabels(account, name string, labels map[string]string) {
+	if len(labels) == 0 {
+		c.RemoveControlPlaneLabels(account, name)
+		return
+	}
+	if c.Upbound.ControlPlaneLabels == nil {
+		c.Upbound.ControlPlaneLabels = map[string]map[string]string{}
+	}
+	c.Upbound.ControlPlaneLabels[controlPlaneKey(account, name)] = labels
+}
+
+// RemoveControlPlaneLabels removes the labels of the control plane with the
+// supplied name in the supplied account.
+func (c *Config) RemoveControlPlaneLabels(account, name string) {
+	delete(c.Upbound.ControlPlaneLabels, controlPlaneKey(account, name))
+}
+
+// GetControlPlaneAnnotations returns the annotations of the control plane with
+// the supplied name in the supplied account. If no annotations are set, a nil
+// map is returned.
+func (c *Config) GetControlPlaneAnnotations(account, name string) map[string]string {
+	return c.Upbound.ControlPlaneAnnotations[controlPlaneKey(account, name)]
+}
+
+// SetControlPlaneAnnotations sets the annotations of the control plane with
+// the supplied name in the supplied account. Setting empty annotations removes
+// them.
+func (c *Config) SetControlPlaneAnnotations(account, name string, annotations map[string]string) {
+	if len(annotations) == 0 {
+		c.RemoveControlPlaneAnnotations(account, name)
+		return
+	}
+	if c.Upbound.ControlPlaneAnnotations == nil {
+		c.Upbound.ControlPlaneAnnotations = map[string]map[string]string{}
+	}
+	c.Upbound.ControlPlaneAnnotations[controlPlaneKey(account, name)] = annotations
+}
+
+// RemoveControlPlaneAnnotations removes the annotations of the control plane
+// with the supplied name in the supplied account.
+func (c *Config) RemoveControlPlaneAnnotations(account, name string) {
+	delete(c.Upbound.ControlPlaneAnnotations, controlPlaneKey(account, name))
+}
+
+// GetPendingDeletion returns the time at which the control plane with the
+// supplied name in the supplied account is due to be deleted. It returns false
+// if the control plane is not scheduled for deletion.
+func (c *Config) GetPendingDeletion(account, name string) (time.Time, bool) {
+	t, ok := c.Upbound.PendingDeletions[controlPlaneKey(account, name)]
+	return t, ok
+}
+
+// SetPendingDeletion schedules the control plane with the supplied name in
+// the supplied account for deletion at the supplied time.
+func (c *Config) SetPendingDeletion(account, name string, at time.Time) {
+	if c.Upbound.PendingDeletions == nil {
+		c.Upbound.PendingDeletions = map[string]time.Time{}
+	}
+	c.Upbound.PendingDeletions[controlPlaneKey(account, name)] = at
+}
+
+// RemovePendingDeletion cancels the scheduled deletion of the control plane
+// with the supplied name in the supplied account.
+func (c *Config) RemovePendingDeletion(account, name string) {
+	delete(c.Upbound.PendingDeletions, controlPlaneKey(account, name))
+}
+
+// GetPendingDeletions returns the names of the control planes in the supplied
+// account that are scheduled for deletion, partitioned by whether they are
+// due at the supplied time. Names are sorted.
+func (c *Config) GetPendingDeletions(account string, now time.Time) (due, pending []string) {
+	prefix := controlPlaneKey(account, "")
+	for k, t := range c.Upbound.PendingDeletions {
+		name := strings.TrimPrefix(k, prefix)
+		if name == k {
+			continue
+		}
+		if t.After(now) {
+			pending = append(pending, name)
+			continue
+		}
+		due = append(due, name)
+	}
+	sort.Strings(due)
+	sort.Strings(pending)
+	return due, pending
+}
@@ -17,6 +17,7 @@ package config
 import (
 	"io"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -516,3 +517,213 @@ func TestBaseToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSetControlPlaneLabels(t *testing.T) {
+	type args struct {
+		account string
+		name    string
+		labels  map[string]string
+		cfg     *Config
+	}
+	type want struct {
+		labels map[string]map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Add": {
+			reason: "Labels should be stored under the account and control plane name.",
+			args: args{
+				account: "cool-org",
+				name:    "cool-ctp",
+				labels:  map[string]string{"env": "prod"},
+				cfg:     &Config{},
+			},
+			want: want{
+				labels: map[string]map[string]string{
+					"cool-org/cool-ctp": {"env": "prod"},
+				},
+			},
+		},
+		"Replace": {
+			reason: "Setting labels should replace existing labels.",
+			args: args{
+				account: "cool-org",
+				name:    "cool-ctp",
+				labels:  map[string]string{"env": "dev"},
+				cfg: &Config{
+					Upbound: Upbound{
+						ControlPlaneLabels: map[string]map[string]string{
+							"cool-org/cool-ctp": {"env": "prod", "team": "a"},
+						},
+					},
+				},
+			},
+			want: want{
+				labels: map[string]map[string]string{
+					"cool-org/cool-ctp": {"env": "dev"},
+				},
+			},
+		},
+		"RemoveEmpty": {
+			reason: "Setting empty labels should remove existing labels.",
+			args: args{
+				account: "cool-org",
+				name:    "cool-ctp",
+				cfg: &Config{
+					Upbound: Upbound{
+						ControlPlaneLabels: map[string]map[string]string{
+							"cool-org/cool-ctp":  {"env": "prod"},
+							"cool-org/other-ctp": {"env": "dev"},
+						},
+					},
+				},
+			},
+			want: want{
+				labels: map[string]map[string]string{
+					"cool-org/other-ctp": {"env": "dev"},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.args.cfg.SetControlPlaneLabels(tc.args.account, tc.args.name, tc.args.labels)
+
+			if diff := cmp.Diff(tc.want.labels, tc.args.cfg.Upbound.ControlPlaneLabels); diff != "" {
+				t.Errorf("\n%s\nSetControlPlaneLabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetControlPlaneAnnotations(t *testing.T) {
+	type args struct {
+		account     string
+		name        string
+		annotations map[string]string
+		cfg         *Config
+	}
+	type want struct {
+		annotations map[string]map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Add": {
+			reason: "Annotations should be stored under the account and control plane name.",
+			args: args{
+				account:     "cool-org",
+				name:        "cool-ctp",
+				annotations: map[string]string{"owner": "platform"},
+				cfg:         &Config{},
+			},
+			want: want{
+				annotations: map[string]map[string]string{
+					"cool-org/cool-ctp": {"owner": "platform"},
+				},
+			},
+		},
+		"RemoveEmpty": {
+			reason: "Setting empty annotations should remove existing annotations.",
+			args: args{
+				account: "cool-org",
+				name:    "cool-ctp",
+				cfg: &Config{
+					Upbound: Upbound{
+						ControlPlaneAnnotations: map[string]map[string]string{
+							"cool-org/cool-ctp":  {"owner": "platform"},
+							"cool-org/other-ctp": {"owner": "payments"},
+						},
+					},
+				},
+			},
+			want: want{
+				annotations: map[string]map[string]string{
+					"cool-org/other-ctp": {"owner": "payments"},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.args.cfg.SetControlPlaneAnnotations(tc.args.account, tc.args.name, tc.args.annotations)
+
+			if diff := cmp.Diff(tc.want.annotations, tc.args.cfg.Upbound.ControlPlaneAnnotations); diff != "" {
+				t.Errorf("\n%s\nSetControlPlaneAnnotations(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetPendingDeletions(t *testing.T) {
+	now := time.Date(2023, 5, 4, 3, 0, 0, 0, time.UTC)
+	cfg := &Config{}
+	cfg.SetPendingDeletion("cool-org", "due-ctp", now.Add(-time.Hour))
+	cfg.SetPendingDeletion("cool-org", "now-ctp", now)
+	cfg.SetPendingDeletion("cool-org", "later-ctp", now.Add(time.Hour))
+	cfg.SetPendingDeletion("other-org", "due-ctp", now.Add(-time.Hour))
+	cfg.SetPendingDeletion("cool-org", "restored-ctp", now.Add(-time.Hour))
+	cfg.RemovePendingDeletion("cool-org", "restored-ctp")
+
+	due, pending := cfg.GetPendingDeletions("cool-org", now)
+	if diff := cmp.Diff([]string{"due-ctp", "now-ctp"}, due); diff != "" {
+		t.Errorf("GetPendingDeletions(...): -want due, +got due:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"later-ctp"}, pending); diff != "" {
+		t.Errorf("GetPendingDeletions(...): -want pending, +got pending:\n%s", diff)
+	}
+	at, ok := cfg.GetPendingDeletion("cool-org", "later-ctp")
+	if diff := cmp.Diff(now.Add(time.Hour), at); !ok || diff != "" {
+		t.Errorf("GetPendingDeletion(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestCommandHooks(t *testing.T) {
+	cfg := &Config{
+		Hooks: map[string]Hooks{
+			AllCommands:    {Post: []string{"notify"}},
+			"usage":        {Pre: []string{"aws sso login"}},
+			"usage report": {Pre: []string{"echo report"}, Post: []string{"echo done"}},
+			"space":        {Pre: []string{"echo space"}},
+		},
+	}
+	cases := map[string]struct {
+		reason  string
+		command string
+		want    Hooks
+	}{
+		"Subcommand": {
+			reason:  "Hooks of all commands and parent commands should precede hooks of the command.",
+			command: "usage report",
+			want: Hooks{
+				Pre:  []string{"aws sso login", "echo report"},
+				Post: []string{"notify", "echo done"},
+			},
+		},
+		"Parent": {
+			reason:  "Hooks of a parent command should run for its subcommands.",
+			command: "usage collect",
+			want:    Hooks{Pre: []string{"aws sso login"}, Post: []string{"notify"}},
+		},
+		"Unrelated": {
+			reason:  "Only hooks of all commands should run for commands without hooks.",
+			command: "spaces list",
+			want:    Hooks{Post: []string{"notify"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cfg.CommandHooks(tc.command)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCommandHooks(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
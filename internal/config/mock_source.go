@@ -19,6 +19,7 @@ type MockSource struct {
 	InitializeFn   func() error
 	GetConfigFn    func() (*Config, error)
 	UpdateConfigFn func(*Config) error
+	ModifyConfigFn func(fn func(*Config) error) (*Config, error)
 }
 
 // Initialize calls the underlying initialize function.
@@ -35,3 +36,8 @@ func (m *MockSource) GetConfig() (*Config, error) {
 func (m *MockSource) UpdateConfig(c *Config) error {
 	return m.UpdateConfigFn(c)
 }
+
+// ModifyConfig calls the underlying modify config function.
+func (m *MockSource) ModifyConfig(fn func(*Config) error) (*Config, error) {
+	return m.ModifyConfigFn(fn)
+}
@@ -0,0 +1,301 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtUnknownSetting = "unknown setting %q, run 'up config view' to list settings"
+	errFmtInvalidValue   = "invalid value %q for %s, must be one of: %s"
+	errFmtInvalidBool    = "invalid value %q for %s, must be true or false"
+
+	errTelemetryNoEndpoint = "telemetry.endpoint must be set before telemetry is turned on"
+)
+
+// Update channels.
+const (
+	channelStable = "stable"
+	channelBeta   = "beta"
+)
+
+// A Setting is a documented configuration value that can be read and written
+// by key. Settings of a profile apply to the default profile.
+type Setting struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+
+	get func(c *Config) (string, error)
+	// set sets the value of the setting. An empty value restores the
+	// default.
+	set func(c *Config, v string) error
+}
+
+// Settings returns all settings sorted by key.
+func Settings() []Setting {
+	s := []Setting{
+		{
+			Key:         "account",
+			Description: "Default account of the default profile.",
+			get: withDefaultProfile(func(c *Config, name string) (string, error) {
+				return c.Upbound.Profiles[name].Account, nil
+			}),
+			set: func(c *Config, v string) error {
+				name, p, err := c.GetDefaultUpboundProfile()
+				if err != nil {
+					return err
+				}
+				p.Account = v
+				c.Upbound.Profiles[name] = p
+				return nil
+			},
+		},
+		baseSetting("domain", "domain", "Root Upbound domain of the default profile."),
+		baseSetting("endpoints.api", "override_api_endpoint", "Upbound API endpoint of the default profile. Derived from the domain if unset."),
+		baseSetting("endpoints.proxy", "override_proxy_endpoint", "Upbound proxy endpoint of the default profile. Derived from the domain if unset."),
+		baseSetting("endpoints.registry", "override_registry_endpoint", "Package registry endpoint of the default profile. Derived from the domain if unset."),
+		{
+			Key:         "output.format",
+			Description: "Default format for get and list commands: default, json, yaml, or machine.",
+			get: func(c *Config) (string, error) {
+				return string(c.OutputFormat()), nil
+			},
+			set: func(c *Config, v string) error {
+				if err := oneOf("output.format", v, string(Default), string(JSON), string(YAML), string(Machine)); err != nil {
+					return err
+				}
+				if c.Output == nil {
+					c.Output = &Output{}
+				}
+				c.Output.Format = Format(v)
+				return nil
+			},
+		},
+		{
+			Key:         "output.color",
+			Description: "Whether output is colored and animated: true or false.",
+			get: func(c *Config) (string, error) {
+				return strconv.FormatBool(c.ColorEnabled()), nil
+			},
+			set: boolSetter("output.color", true, func(c *Config, b bool) {
+				if c.Output == nil {
+					c.Output = &Output{}
+				}
+				c.Output.NoColor = !b
+			}),
+		},
+		{
+			Key:         "output.redact-keys",
+			Description: "Comma separated parameter keys whose values are redacted when install and upgrade parameters are printed, in addition to keys containing token, password, or key.",
+			get: func(c *Config) (string, error) {
+				return strings.Join(c.RedactedKeys(), ","), nil
+			},
+			set: func(c *Config, v string) error {
+				if c.Output == nil {
+					c.Output = &Output{}
+				}
+				c.Output.RedactKeys = nil
+				for _, k := range strings.Split(v, ",") {
+					if k = strings.TrimSpace(k); k != "" {
+						c.Output.RedactKeys = append(c.Output.RedactKeys, k)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Key:         "telemetry",
+			Description: "Whether anonymous usage metrics are sent: true or false.",
+			get: func(c *Config) (string, error) {
+				return strconv.FormatBool(c.TelemetryEnabled()), nil
+			},
+			set: func(c *Config, v string) error {
+				enabled := false
+				if v != "" {
+					var err error
+					if enabled, err = strconv.ParseBool(v); err != nil {
+						return errors.Errorf(errFmtInvalidBool, v, "telemetry")
+					}
+				}
+				if c.Telemetry == nil {
+					c.Telemetry = &Telemetry{}
+				}
+				if enabled && c.Telemetry.Endpoint == "" {
+					return errors.New(errTelemetryNoEndpoint)
+				}
+				c.Telemetry.Enabled = enabled
+				return nil
+			},
+		},
+		{
+			Key:         "telemetry.endpoint",
+			Description: "URL to which anonymous usage metrics are sent. Telemetry is not sent unless this is set.",
+			get: func(c *Config) (string, error) {
+				if c.Telemetry == nil {
+					return "", nil
+				}
+				return c.Telemetry.Endpoint, nil
+			},
+			set: func(c *Config, v string) error {
+				if c.Telemetry == nil {
+					c.Telemetry = &Telemetry{}
+				}
+				c.Telemetry.Endpoint = v
+				return nil
+			},
+		},
+		{
+			Key:         "updates.channel",
+			Description: "Release channel checked for new versions of up: stable or beta.",
+			get: func(c *Config) (string, error) {
+				if c.Updates == nil || c.Updates.Channel == "" {
+					return channelStable, nil
+				}
+				return c.Updates.Channel, nil
+			},
+			set: func(c *Config, v string) error {
+				if err := oneOf("updates.channel", v, channelStable, channelBeta); err != nil {
+					return err
+				}
+				if c.Updates == nil {
+					c.Updates = &Updates{}
+				}
+				c.Updates.Channel = v
+				return nil
+			},
+		},
+		{
+			Key:         "updates.notifications",
+			Description: "Whether to warn when a newer version of up is available: true or false.",
+			get: func(c *Config) (string, error) {
+				return strconv.FormatBool(c.UpdateNotificationsEnabled()), nil
+			},
+			set: boolSetter("updates.notifications", true, func(c *Config, b bool) {
+				if c.Updates == nil {
+					c.Updates = &Updates{}
+				}
+				c.Updates.DisableNotifications = !b
+			}),
+		},
+		{
+			Key:         "features.show-maturing",
+			Description: "Whether alpha and beta commands are shown alongside stable commands: true or false.",
+			get: func(c *Config) (string, error) {
+				return strconv.FormatBool(c.ShowMaturingCommands()), nil
+			},
+			set: boolSetter("features.show-maturing", false, func(c *Config, b bool) {
+				c.Features = &Features{ShowMaturing: b}
+			}),
+		},
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Key < s[j].Key })
+	return s
+}
+
+// GetSetting returns the value of the setting with the supplied key.
+func (c *Config) GetSetting(key string) (string, error) {
+	s, err := lookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return s.get(c)
+}
+
+// SetSetting sets the value of the setting with the supplied key. An empty
+// value restores the default.
+func (c *Config) SetSetting(key, value string) error {
+	s, err := lookupSetting(key)
+	if err != nil {
+		return err
+	}
+	return s.set(c, value)
+}
+
+func lookupSetting(key string) (Setting, error) {
+	for _, s := range Settings() {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, errors.Errorf(errFmtUnknownSetting, key)
+}
+
+// withDefaultProfile returns a getter that reads from the default profile,
+// or returns an empty value if there is no default profile.
+func withDefaultProfile(fn func(c *Config, name string) (string, error)) func(c *Config) (string, error) {
+	return func(c *Config) (string, error) {
+		name, _, err := c.GetDefaultUpboundProfile()
+		if err != nil {
+			return "", nil // nolint:nilerr
+		}
+		return fn(c, name)
+	}
+}
+
+// baseSetting returns a setting stored in the base config of the default
+// profile under the supplied key.
+func baseSetting(key, baseKey, description string) Setting {
+	return Setting{
+		Key:         key,
+		Description: description,
+		get: withDefaultProfile(func(c *Config, name string) (string, error) {
+			return c.Upbound.Profiles[name].BaseConfig[baseKey], nil
+		}),
+		set: func(c *Config, v string) error {
+			name, _, err := c.GetDefaultUpboundProfile()
+			if err != nil {
+				return err
+			}
+			if v == "" {
+				return c.RemoveFromBaseConfig(name, baseKey)
+			}
+			return c.AddToBaseConfig(name, baseKey, v)
+		},
+	}
+}
+
+// boolSetter returns a setter that parses a boolean value, using def if the
+// value is empty.
+func boolSetter(key string, def bool, fn func(c *Config, b bool)) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		b := def
+		if v != "" {
+			var err error
+			if b, err = strconv.ParseBool(v); err != nil {
+				return errors.Errorf(errFmtInvalidBool, v, key)
+			}
+		}
+		fn(c, b)
+		return nil
+	}
+}
+
+// oneOf returns an error unless v is empty or one of the allowed values.
+func oneOf(key, v string, allowed ...string) error {
+	if v == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if v == a {
+			return nil
+		}
+	}
+	return errors.Errorf(errFmtInvalidValue, v, key, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetSetting(t *testing.T) {
+	profile := func() *Config {
+		return &Config{Upbound: Upbound{
+			Default:  "default",
+			Profiles: map[string]Profile{"default": {ID: "cool-user", Type: UserProfileType}},
+		}}
+	}
+
+	type args struct {
+		cfg   *Config
+		key   string
+		value string
+	}
+	type want struct {
+		value string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Account": {
+			reason: "The account should be set on the default profile.",
+			args:   args{cfg: profile(), key: "account", value: "cool-org"},
+			want:   want{value: "cool-org"},
+		},
+		"AccountNoProfile": {
+			reason: "Profile settings cannot be set without a default profile.",
+			args:   args{cfg: &Config{}, key: "account", value: "cool-org"},
+			want:   want{err: errors.New(errNoDefaultSpecified)},
+		},
+		"Endpoint": {
+			reason: "Endpoints should be stored in the base config of the default profile.",
+			args:   args{cfg: profile(), key: "endpoints.api", value: "https://api.example.com"},
+			want:   want{value: "https://api.example.com"},
+		},
+		"OutputFormat": {
+			reason: "A valid output format should be set.",
+			args:   args{cfg: &Config{}, key: "output.format", value: "json"},
+			want:   want{value: "json"},
+		},
+		"InvalidOutputFormat": {
+			reason: "An invalid output format should be rejected.",
+			args:   args{cfg: &Config{}, key: "output.format", value: "xml"},
+			want:   want{value: "default", err: errors.Errorf(errFmtInvalidValue, "xml", "output.format", "default, json, yaml, machine")},
+		},
+		"RedactKeys": {
+			reason: "Redacted keys should be set from a comma separated list, ignoring whitespace.",
+			args:   args{cfg: &Config{}, key: "output.redact-keys", value: "webhook, clientSecret,"},
+			want:   want{value: "webhook,clientSecret"},
+		},
+		"Color": {
+			reason: "Color should be disabled when set to false.",
+			args:   args{cfg: &Config{}, key: "output.color", value: "false"},
+			want:   want{value: "false"},
+		},
+		"ResetColor": {
+			reason: "An empty value should restore the default.",
+			args:   args{cfg: &Config{Output: &Output{NoColor: true}}, key: "output.color"},
+			want:   want{value: "true"},
+		},
+		"InvalidBool": {
+			reason: "A value that is not a boolean should be rejected.",
+			args:   args{cfg: &Config{}, key: "telemetry", value: "maybe"},
+			want:   want{value: "false", err: errors.Errorf(errFmtInvalidBool, "maybe", "telemetry")},
+		},
+		"TelemetryNoEndpoint": {
+			reason: "Telemetry should not be turned on without an endpoint.",
+			args:   args{cfg: &Config{}, key: "telemetry", value: "true"},
+			want:   want{value: "false", err: errors.New(errTelemetryNoEndpoint)},
+		},
+		"Telemetry": {
+			reason: "Telemetry should be turned on if an endpoint is set.",
+			args:   args{cfg: &Config{Telemetry: &Telemetry{Endpoint: "https://metrics.example.org"}}, key: "telemetry", value: "true"},
+			want:   want{value: "true"},
+		},
+		"Unknown": {
+			reason: "Unknown settings should be rejected.",
+			args:   args{cfg: &Config{}, key: "colour", value: "true"},
+			want:   want{err: errors.Errorf(errFmtUnknownSetting, "colour")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.cfg.SetSetting(tc.args.key, tc.args.value)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetSetting(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			got, _ := tc.args.cfg.GetSetting(tc.args.key)
+			if diff := cmp.Diff(tc.want.value, got); diff != "" {
+				t.Errorf("\n%s\nGetSetting(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
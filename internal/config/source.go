@@ -15,19 +15,49 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/spf13/afero"
 )
 
+const (
+	// lockSuffix is appended to the config path to build the path of the
+	// advisory lock file guarding writes.
+	lockSuffix = ".lock"
+
+	// staleSuffix is appended to the lock path and the modification time of a
+	// stale lock to build the path of the claim to remove it.
+	staleSuffix = ".stale"
+
+	// staleLockAge is the age after which a lock file is assumed to have been
+	// left behind by a process that did not exit cleanly.
+	staleLockAge = 30 * time.Second
+
+	defaultLockTimeout   = 10 * time.Second
+	defaultRetryInterval = 50 * time.Millisecond
+
+	// readAttempts is the number of times a config that fails to parse is
+	// read before giving up.
+	readAttempts = 3
+
+	errFmtLockTimeout = "timed out waiting for config lock %s; remove it if no other up process is running"
+	errWriteConfig    = "unable to write config file"
+	errParseConfig    = "unable to parse config file"
+)
+
 // Source is a source for interacting with a Config.
 type Source interface {
 	Initialize() error
 	GetConfig() (*Config, error)
 	UpdateConfig(*Config) error
+	ModifyConfig(fn func(*Config) error) (*Config, error)
 }
 
 // NewFSSource constructs a new FSSource. Path must be supplied via modifier or
@@ -40,7 +70,9 @@ type Source interface {
 // example).
 func NewFSSource(modifiers ...FSSourceModifier) *FSSource {
 	src := &FSSource{
-		fs: afero.NewOsFs(),
+		fs:            afero.NewOsFs(),
+		lockTimeout:   defaultLockTimeout,
+		retryInterval: defaultRetryInterval,
 	}
 	for _, m := range modifiers {
 		m(src)
@@ -70,6 +102,9 @@ func WithFS(fs afero.Fs) FSSourceModifier {
 type FSSource struct {
 	fs   afero.Fs
 	path string
+
+	lockTimeout   time.Duration
+	retryInterval time.Duration
 }
 
 // Initialize creates a config in the filesystem if one does not exist. If path
@@ -98,32 +133,100 @@ func (src *FSSource) Initialize() error {
 	return nil
 }
 
-// GetConfig fetches the config from a filesystem.
+// GetConfig fetches the config from a filesystem. A config that fails to parse
+// is read again a few times before an error is returned, as it may have been
+// caught mid-write by a process that does not replace it atomically.
 func (src *FSSource) GetConfig() (*Config, error) {
+	var err error
+	for i := 0; i < readAttempts; i++ {
+		if i > 0 {
+			time.Sleep(src.retryInterval)
+		}
+		var b []byte
+		b, err = src.read()
+		if err != nil {
+			return nil, err
+		}
+		conf := &Config{}
+		if len(b) == 0 {
+			return conf, nil
+		}
+		if err = json.Unmarshal(b, conf); err == nil {
+			return conf, nil
+		}
+	}
+	return nil, errors.Wrap(err, errParseConfig)
+}
+
+func (src *FSSource) read() ([]byte, error) {
 	f, err := src.fs.Open(src.path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close() // nolint:errcheck
-	b, err := io.ReadAll(f)
+	return io.ReadAll(f)
+}
+
+// UpdateConfig updates the Config in the filesystem. Writers are serialized
+// with an advisory lock file next to the config, and the config is written to
+// a temporary file that is renamed over the original so that readers never
+// observe a partially written config. The supplied Config replaces the stored
+// one, so changes to a Config read earlier should be made with ModifyConfig.
+func (src *FSSource) UpdateConfig(c *Config) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	unlock, err := src.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return src.write(b)
+}
+
+// ModifyConfig reads the Config from the filesystem, applies the supplied
+// function to it, and writes it back, all while holding the config lock. This
+// ensures that changes made by other processes between reading and writing
+// the config are not lost. The config is not written if the function returns
+// an error or leaves it unchanged. The modified Config is returned.
+func (src *FSSource) ModifyConfig(fn func(*Config) error) (*Config, error) {
+	unlock, err := src.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	c, err := src.GetConfig()
+	if os.IsNotExist(err) {
+		c, err = &Config{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	conf := &Config{}
-	if len(b) == 0 {
-		return conf, nil
+	before, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal(b, conf); err != nil {
+	if err := fn(c); err != nil {
 		return nil, err
 	}
-	return conf, nil
+	after, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(before, after) {
+		return c, nil
+	}
+	return c, src.write(after)
 }
 
-// UpdateConfig updates the Config in the filesystem.
-func (src *FSSource) UpdateConfig(c *Config) error {
-	f, err := src.fs.OpenFile(src.path, os.O_RDWR|os.O_TRUNC, 0600)
+// write writes the supplied config to a temporary file that is renamed over
+// the original. The caller must hold the config lock.
+func (src *FSSource) write(b []byte) error {
+	f, err := afero.TempFile(src.fs, filepath.Dir(src.path), filepath.Base(src.path)+".*.tmp")
 	if err != nil {
-		return err
+		return errors.Wrap(err, errWriteConfig)
 	}
 	// NOTE(hasheddan): We both defer and explicitly call Close() to ensure that
 	// we close the file in the case that we encounter an error before write,
@@ -132,12 +235,64 @@ func (src *FSSource) UpdateConfig(c *Config) error {
 	// deferred Close() will error (see https://golang.org/pkg/os/#File.Close),
 	// but we do not check it.
 	defer f.Close() // nolint:errcheck
-	b, err := json.Marshal(c)
+	// The temporary file is removed if it could not be renamed into place.
+	defer src.fs.Remove(f.Name()) // nolint:errcheck
+	if _, err := f.Write(b); err != nil {
+		return errors.Wrap(err, errWriteConfig)
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, errWriteConfig)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errWriteConfig)
+	}
+	return errors.Wrap(src.fs.Rename(f.Name(), src.path), errWriteConfig)
+}
+
+// lock acquires the advisory lock for the config, waiting up to the lock
+// timeout for another process to release it. The returned function releases
+// the lock.
+func (src *FSSource) lock() (func(), error) {
+	p := src.path + lockSuffix
+	deadline := time.Now().Add(src.lockTimeout)
+	for {
+		f, err := src.fs.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = src.fs.Remove(p) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if fi, err := src.fs.Stat(p); err == nil && time.Since(fi.ModTime()) > staleLockAge {
+			src.takeOver(p, fi.ModTime())
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf(errFmtLockTimeout, p)
+		}
+		time.Sleep(src.retryInterval)
+	}
+}
+
+// takeOver removes the stale lock at the supplied path, which was last
+// modified at the supplied time. Concurrent writers that find the same stale
+// lock race to exclusively create a claim named after its modification time,
+// so that only one of them removes it. Otherwise a writer could remove the lock
+// another writer has just acquired after removing the stale lock.
+func (src *FSSource) takeOver(p string, modTime time.Time) {
+	claim := fmt.Sprintf("%s.%d%s", p, modTime.UnixNano(), staleSuffix)
+	f, err := src.fs.OpenFile(claim, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
 	if err != nil {
-		return err
+		// Another writer is taking over, or already took over, the lock.
+		time.Sleep(src.retryInterval)
+		return
 	}
-	if _, err := f.Write(b); err != nil {
-		return err
+	_ = f.Close()
+	// A writer that claims the stale lock after the claim is removed finds the
+	// lock removed or replaced, as it is removed before the claim.
+	if fi, err := src.fs.Stat(p); err == nil && fi.ModTime().Equal(modTime) {
+		_ = src.fs.Remove(p)
 	}
-	return f.Close()
+	_ = src.fs.Remove(claim)
 }
@@ -17,8 +17,12 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
@@ -78,6 +82,7 @@ func TestInitialize(t *testing.T) {
 }
 
 func TestGetConfig(t *testing.T) {
+	corrupt := `{"upbound":`
 	testConf := &Config{
 		Upbound: Upbound{
 			Default: "test",
@@ -113,6 +118,19 @@ func TestGetConfig(t *testing.T) {
 			},
 			want: testConf,
 		},
+		"ErrCorruptConfig": {
+			reason: "If the config cannot be parsed after retrying we should return an error.",
+			modifiers: []FSSourceModifier{
+				func(f *FSSource) {
+					f.path = "/.up/config.json"
+					f.retryInterval = time.Millisecond
+					fs := afero.NewMemMapFs()
+					_ = afero.WriteFile(fs, "/.up/config.json", []byte(corrupt), 0600)
+					f.fs = fs
+				},
+			},
+			err: errors.Wrap(json.Unmarshal([]byte(corrupt), &Config{}), errParseConfig),
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -157,6 +175,35 @@ func TestUpdateConfig(t *testing.T) {
 			},
 			conf: testConf,
 		},
+		"StaleLock": {
+			reason: "A lock left behind by a process that exited uncleanly should be removed.",
+			modifiers: []FSSourceModifier{
+				func(f *FSSource) {
+					f.path = "/.up/config.json"
+					fs := afero.NewMemMapFs()
+					_ = afero.WriteFile(fs, "/.up/config.json.lock", nil, 0600)
+					old := time.Now().Add(-2 * staleLockAge)
+					_ = fs.Chtimes("/.up/config.json.lock", old, old)
+					f.fs = fs
+				},
+			},
+			conf: testConf,
+		},
+		"ErrLocked": {
+			reason: "We should return an error if another process holds the lock past the timeout.",
+			modifiers: []FSSourceModifier{
+				func(f *FSSource) {
+					f.path = "/.up/config.json"
+					f.lockTimeout = 10 * time.Millisecond
+					f.retryInterval = time.Millisecond
+					fs := afero.NewMemMapFs()
+					_ = afero.WriteFile(fs, "/.up/config.json.lock", nil, 0600)
+					f.fs = fs
+				},
+			},
+			conf: testConf,
+			err:  errors.Errorf(errFmtLockTimeout, "/.up/config.json.lock"),
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -168,3 +215,208 @@ func TestUpdateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateConfigAtomic(t *testing.T) {
+	testConf := &Config{
+		Upbound: Upbound{
+			Default: "test",
+		},
+	}
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/.up/config.json", []byte(`{"upbound":{"default":"old"}}`), 0600)
+	src := NewFSSource(WithFS(fs), WithPath("/.up/config.json"))
+
+	if err := src.UpdateConfig(testConf); err != nil {
+		t.Fatalf("UpdateConfig(...): %v", err)
+	}
+	conf, err := src.GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig(...): %v", err)
+	}
+	if diff := cmp.Diff(testConf, conf); diff != "" {
+		t.Errorf("GetConfig(...): -want, +got:\n%s", diff)
+	}
+	// Neither the lock nor the temporary file should be left behind.
+	files, err := afero.ReadDir(fs, "/.up")
+	if err != nil {
+		t.Fatalf("ReadDir(...): %v", err)
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name()
+	}
+	if diff := cmp.Diff([]string{"config.json"}, names); diff != "" {
+		t.Errorf("ReadDir(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestModifyConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+	stored := `{"upbound":{"default":"old"}}`
+	cases := map[string]struct {
+		reason string
+		fn     func(*Config) error
+		want   *Config
+		stored string
+		err    error
+	}{
+		"Modified": {
+			reason: "The config as stored should be modified and written back.",
+			fn: func(c *Config) error {
+				c.Upbound.Default = "new"
+				return nil
+			},
+			want:   &Config{Upbound: Upbound{Default: "new"}},
+			stored: `{"upbound":{"default":"new"}}`,
+		},
+		"Unchanged": {
+			reason: "A config that is not changed should not be written.",
+			fn:     func(c *Config) error { return nil },
+			want:   &Config{Upbound: Upbound{Default: "old"}},
+			stored: stored,
+		},
+		"ErrModify": {
+			reason: "The config should not be written if the function returns an error.",
+			fn: func(c *Config) error {
+				c.Upbound.Default = "new"
+				return errBoom
+			},
+			stored: stored,
+			err:    errBoom,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "/.up/config.json", []byte(stored), 0600)
+			src := NewFSSource(WithFS(fs), WithPath("/.up/config.json"))
+			got, err := src.ModifyConfig(tc.fn)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nModifyConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nModifyConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			b, _ := afero.ReadFile(fs, "/.up/config.json")
+			if diff := cmp.Diff(tc.stored, string(b)); diff != "" {
+				t.Errorf("\n%s\nModifyConfig(...): -want stored, +got stored:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestModifyConfigConcurrent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/.up/config.json", []byte(`{}`), 0600)
+
+	// Each writer reads the config, adds its own profile, and writes it back.
+	// No profile may be lost to a concurrent writer.
+	const writers = 10
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			src := NewFSSource(WithFS(fs), WithPath("/.up/config.json"))
+			src.retryInterval = time.Millisecond
+			_, err := src.ModifyConfig(func(c *Config) error {
+				return c.AddOrUpdateUpboundProfile(string(rune('a'+i)), Profile{ID: "cool-user", Type: UserProfileType})
+			})
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("ModifyConfig(...): %v", err)
+		}
+	}
+	conf, err := NewFSSource(WithFS(fs), WithPath("/.up/config.json")).GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig(...): %v", err)
+	}
+	if diff := cmp.Diff(writers, len(conf.Upbound.Profiles)); diff != "" {
+		t.Errorf("ModifyConfig(...): -want profiles, +got profiles:\n%s", diff)
+	}
+}
+
+// barrierFs blocks the first n Stat calls of a path until all of them are
+// made, so that concurrent writers all find the same lock. It delays all but
+// the first removal of the path, so that a writer that removes a lock it found
+// stale does so after another writer acquired the lock.
+type barrierFs struct {
+	afero.Fs
+	path    string
+	n       int32
+	wg      *sync.WaitGroup
+	removes int32
+}
+
+func (fs *barrierFs) Stat(name string) (os.FileInfo, error) {
+	fi, err := fs.Fs.Stat(name)
+	if name == fs.path && atomic.AddInt32(&fs.n, -1) >= 0 {
+		fs.wg.Done()
+		fs.wg.Wait()
+	}
+	return fi, err
+}
+
+func (fs *barrierFs) Remove(name string) error {
+	if name == fs.path && atomic.AddInt32(&fs.removes, 1) > 1 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return fs.Fs.Remove(name)
+}
+
+func TestModifyConfigStaleLockConcurrent(t *testing.T) {
+	const writers = 10
+	mfs := afero.NewMemMapFs()
+	_ = afero.WriteFile(mfs, "/.up/config.json", []byte(`{}`), 0600)
+	_ = afero.WriteFile(mfs, "/.up/config.json.lock", nil, 0600)
+	old := time.Now().Add(-2 * staleLockAge)
+	_ = mfs.Chtimes("/.up/config.json.lock", old, old)
+	wg := &sync.WaitGroup{}
+	wg.Add(writers)
+	fs := &barrierFs{Fs: mfs, path: "/.up/config.json.lock", n: writers, wg: wg}
+
+	// Writers that all find the stale lock must still hold the lock one at a
+	// time once it is taken over.
+	var holders, overlaps int32
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			src := NewFSSource(WithFS(fs), WithPath("/.up/config.json"))
+			src.retryInterval = time.Millisecond
+			_, err := src.ModifyConfig(func(c *Config) error {
+				if atomic.AddInt32(&holders, 1) > 1 {
+					atomic.AddInt32(&overlaps, 1)
+				}
+				defer atomic.AddInt32(&holders, -1)
+				time.Sleep(10 * time.Millisecond)
+				return c.AddOrUpdateUpboundProfile(string(rune('a'+i)), Profile{ID: "cool-user", Type: UserProfileType})
+			})
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("ModifyConfig(...): %v", err)
+		}
+	}
+	if overlaps > 0 {
+		t.Errorf("ModifyConfig(...): lock held by more than one writer %d times", overlaps)
+	}
+	conf, err := NewFSSource(WithFS(fs), WithPath("/.up/config.json")).GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig(...): %v", err)
+	}
+	if diff := cmp.Diff(writers, len(conf.Upbound.Profiles)); diff != "" {
+		t.Errorf("ModifyConfig(...): -want profiles, +got profiles:\n%s", diff)
+	}
+	// Neither the lock nor any claim to take it over should be left behind.
+	files, _ := afero.ReadDir(mfs, "/.up")
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name()
+	}
+	if diff := cmp.Diff([]string{"config.json"}, names); diff != "" {
+		t.Errorf("ReadDir(...): -want, +got:\n%s", diff)
+	}
+}
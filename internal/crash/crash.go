@@ -0,0 +1,256 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crash records reports of panics and failed commands locally, so
+// that they can be sent to the maintainers of up to reproduce issues.
+package crash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/config"
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	// Dir is the name of the directory in the up config directory in which
+	// crash reports are written.
+	Dir = "crashes"
+
+	redacted = "REDACTED"
+	fileExt  = ".json"
+
+	errWriteReport  = "unable to write crash report"
+	errReadReport   = "unable to read crash report"
+	errNoReports    = "no crash reports found"
+	errSendReport   = "unable to send crash report"
+	errFmtSendCode  = "crash report endpoint returned status %d"
+	errEncodeReport = "unable to encode crash report"
+)
+
+// secretKey matches keys that suggest a secret value.
+const secretKey = `[\w.-]*(?:token|password|passwd|secret|session|credentials?|api[_-]?key|access[_-]?key)[\w.-]*`
+
+var (
+	// jwtRe matches JSON Web Tokens, such as robot and session tokens.
+	jwtRe = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// bearerRe matches the credentials of an authorization header.
+	bearerRe = regexp.MustCompile(`(?i)\b(bearer)\s+[^\s"']+`)
+	// secretRe matches key=value and key:value pairs with a secret key. A
+	// colon followed by a space is not matched, as wrapped errors use it to
+	// separate messages.
+	secretRe = regexp.MustCompile(`(?i)\b(` + secretKey + `)(\s*=\s*|:)("[^"]*"|'[^']*'|[^\s,;"']+)`)
+	// jsonSecretRe matches JSON string fields with a secret key.
+	jsonSecretRe = regexp.MustCompile(`(?i)("` + secretKey + `"\s*:\s*)"[^"]*"`)
+	// homeRe matches the user name in home directory paths.
+	homeRe = regexp.MustCompile(`(/home/|/Users/|(?i:[A-Z]:\\Users\\))[^/\\\s"']+`)
+)
+
+// A Report describes a panic or a failed invocation of an up command. The
+// values of arguments are redacted, only the names of flags are kept, and
+// tokens, secrets and home directories are redacted from the error.
+type Report struct {
+	Time    time.Time `json:"time"`
+	Version string    `json:"version"`
+	OS      string    `json:"os"`
+	Arch    string    `json:"arch"`
+	Command string    `json:"command,omitempty"`
+	Args    []string  `json:"args"`
+	Panic   bool      `json:"panic"`
+	Error   string    `json:"error"`
+	Stack   string    `json:"stack,omitempty"`
+}
+
+// NewReport builds a report of the supplied error or panic value. The stack
+// is omitted if it is empty.
+func NewReport(version, command string, args []string, cause any, stack []byte) Report {
+	_, isErr := cause.(error)
+	home, _ := os.UserHomeDir()
+	return Report{
+		Time:    time.Now().UTC(),
+		Version: version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Command: command,
+		Args:    SanitizeArgs(args),
+		Panic:   !isErr,
+		Error:   Redact(fmt.Sprint(cause), home),
+		Stack:   string(stack),
+	}
+}
+
+// SanitizeArgs redacts all arguments but the names of flags, as values may
+// contain credentials or other confidential data.
+func SanitizeArgs(args []string) []string {
+	s := make([]string, len(args))
+	for i, a := range args {
+		switch {
+		case !strings.HasPrefix(a, "-") || a == "-":
+			s[i] = redacted
+		case strings.Contains(a, "="):
+			s[i] = a[:strings.Index(a, "=")+1] + redacted
+		default:
+			s[i] = a
+		}
+	}
+	return s
+}
+
+// Redact removes tokens, the values of secrets and the user name of home
+// directories from an error message. Paths in the supplied home directory are
+// shortened to start with ~. An empty home directory is ignored.
+func Redact(msg, home string) string {
+	if home != "" && home != string(filepath.Separator) {
+		msg = strings.ReplaceAll(msg, home, "~")
+	}
+	msg = jwtRe.ReplaceAllString(msg, redacted)
+	msg = bearerRe.ReplaceAllString(msg, "${1} "+redacted)
+	msg = secretRe.ReplaceAllString(msg, "${1}${2}"+redacted)
+	msg = jsonSecretRe.ReplaceAllString(msg, `${1}"`+redacted+`"`)
+	return homeRe.ReplaceAllString(msg, "${1}"+redacted)
+}
+
+// Store is a directory of crash reports.
+type Store struct {
+	fs  afero.Fs
+	dir string
+}
+
+// StoreOption modifies a Store.
+type StoreOption func(*Store)
+
+// WithFS overrides the Store filesystem with the given filesystem.
+func WithFS(fs afero.Fs) StoreOption {
+	return func(s *Store) {
+		s.fs = fs
+	}
+}
+
+// WithDir overrides the directory of the Store.
+func WithDir(d string) StoreOption {
+	return func(s *Store) {
+		s.dir = filepath.Clean(d)
+	}
+}
+
+// NewStore constructs a new Store. Reports are stored in the up config
+// directory unless a directory is supplied.
+func NewStore(opts ...StoreOption) (*Store, error) {
+	s := &Store{fs: afero.NewOsFs()}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.dir == "" {
+		p, err := config.GetDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		s.dir = filepath.Join(filepath.Dir(p), Dir)
+	}
+	return s, nil
+}
+
+// Write writes the report and returns its path.
+func (s *Store) Write(r Report) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, errWriteReport)
+	}
+	if err := s.fs.MkdirAll(s.dir, 0700); err != nil {
+		return "", errors.Wrap(err, errWriteReport)
+	}
+	name := fmt.Sprintf("%s-%d%s", r.Time.Format("20060102T150405Z"), os.Getpid(), fileExt)
+	p := filepath.Join(s.dir, name)
+	return p, errors.Wrap(afero.WriteFile(s.fs, p, b, 0600), errWriteReport)
+}
+
+// Read reads the report at the supplied path.
+func (s *Store) Read(path string) (Report, error) {
+	r := Report{}
+	b, err := afero.ReadFile(s.fs, path)
+	if err != nil {
+		return r, errors.Wrap(err, errReadReport)
+	}
+	return r, errors.Wrap(json.Unmarshal(b, &r), errReadReport)
+}
+
+// Latest returns the path of the most recently written report.
+func (s *Store) Latest() (string, error) {
+	infos, err := afero.ReadDir(s.fs, s.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, errReadReport)
+	}
+	names := make([]string, 0, len(infos))
+	for _, i := range infos {
+		if !i.IsDir() && filepath.Ext(i.Name()) == fileExt {
+			names = append(names, i.Name())
+		}
+	}
+	if len(names) == 0 {
+		return "", errors.New(errNoReports)
+	}
+	// Names start with the time the report was written.
+	sort.Strings(names)
+	return filepath.Join(s.dir, names[len(names)-1]), nil
+}
+
+// HTTPSender sends reports as JSON to an HTTP endpoint.
+type HTTPSender struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewHTTPSender constructs a new HTTPSender.
+func NewHTTPSender(endpoint string) *HTTPSender {
+	return &HTTPSender{
+		client:   uphttp.NewClient(),
+		endpoint: endpoint,
+	}
+}
+
+// Send sends the supplied report.
+func (h *HTTPSender) Send(ctx context.Context, r Report) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, errEncodeReport)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, errSendReport)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := h.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSendReport)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf(errFmtSendCode, res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestSanitizeArgs(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		args   []string
+		want   []string
+	}{
+		"Positional": {
+			reason: "Positional arguments should be redacted.",
+			args:   []string{"ctp", "get", "my-ctp"},
+			want:   []string{redacted, redacted, redacted},
+		},
+		"FlagValues": {
+			reason: "The values of flags should be redacted, but their names kept.",
+			args:   []string{"login", "--token=secret", "-p", "secret", "--debug"},
+			want:   []string{redacted, "--token=" + redacted, "-p", redacted, "--debug"},
+		},
+		"Stdin": {
+			reason: "A dash is a value and should be redacted.",
+			args:   []string{"-f", "-"},
+			want:   []string{"-f", redacted},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, SanitizeArgs(tc.args)); diff != "" {
+				t.Errorf("\n%s\nSanitizeArgs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	r := NewReport("v0.20.0", "login", nil, errors.New("boom"), nil)
+	if r.Panic || r.Error != "boom" {
+		t.Errorf("NewReport(...): want error report boom, got panic %t and %q", r.Panic, r.Error)
+	}
+	p := NewReport("v0.20.0", "login", nil, "index out of range", []byte("stack"))
+	if !p.Panic || p.Stack != "stack" {
+		t.Errorf("NewReport(...): want panic report with stack, got panic %t and %q", p.Panic, p.Stack)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	jwt := "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJyb2JvdCJ9.c2ln"
+	cases := map[string]struct {
+		reason string
+		msg    string
+		home   string
+		want   string
+	}{
+		"JWT": {
+			reason: "JSON Web Tokens should be redacted wherever they appear.",
+			msg:    "cannot use token " + jwt + " for login",
+			want:   "cannot use token " + redacted + " for login",
+		},
+		"Bearer": {
+			reason: "The credentials of an authorization header should be redacted.",
+			msg:    "request failed with Authorization: Bearer abc123",
+			want:   "request failed with Authorization: Bearer " + redacted,
+		},
+		"KeyValue": {
+			reason: "The values of key=value and key:value pairs with a secret key should be redacted.",
+			msg:    "invalid settings password=hunter2, api_key = 'abc' and session:xyz",
+			want:   "invalid settings password=" + redacted + ", api_key = " + redacted + " and session:" + redacted,
+		},
+		"JSON": {
+			reason: "The values of JSON fields with a secret key should be redacted.",
+			msg:    `unable to decode {"id":"robot","token": "abc"}`,
+			want:   `unable to decode {"id":"robot","token": "` + redacted + `"}`,
+		},
+		"WrappedError": {
+			reason: "Messages of wrapped errors should not be mistaken for secret values.",
+			msg:    "unable to read token: no such file",
+			want:   "unable to read token: no such file",
+		},
+		"HomeDirectory": {
+			reason: "Paths in the home directory should start with ~.",
+			msg:    "open /Users/jane/.up/config.json: permission denied",
+			home:   "/Users/jane",
+			want:   "open ~/.up/config.json: permission denied",
+		},
+		"OtherHomeDirectory": {
+			reason: "The user name of other home directories should be redacted.",
+			msg:    "open /home/john/kubeconfig and C:\\Users\\jane\\kubeconfig",
+			home:   "/Users/jane",
+			want:   "open /home/" + redacted + "/kubeconfig and C:\\Users\\" + redacted + "\\kubeconfig",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Redact(tc.msg, tc.home)); diff != "" {
+				t.Errorf("\n%s\nRedact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, _ := NewStore(WithFS(fs), WithDir("/crashes"))
+
+	if _, err := s.Latest(); !cmp.Equal(errors.New(errNoReports), err, test.EquateErrors()) {
+		t.Errorf("Latest(...): want error %q, got %v", errNoReports, err)
+	}
+
+	older := Report{Time: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Version: "v0.19.0", Args: []string{}}
+	newer := Report{Time: time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC), Version: "v0.20.0", Args: []string{}}
+	for _, r := range []Report{newer, older} {
+		if _, err := s.Write(r); err != nil {
+			t.Fatalf("Write(...): unexpected error: %v", err)
+		}
+	}
+
+	p, err := s.Latest()
+	if err != nil {
+		t.Fatalf("Latest(...): unexpected error: %v", err)
+	}
+	got, err := s.Read(p)
+	if err != nil {
+		t.Fatalf("Read(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(newer, got); diff != "" {
+		t.Errorf("Read(Latest(...)): -want, +got:\n%s", diff)
+	}
+}
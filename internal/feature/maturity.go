@@ -15,6 +15,9 @@
 package feature
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/alecthomas/kong"
 )
 
@@ -27,25 +30,59 @@ type Maturity string
 // Currently supported maturity levels.
 const (
 	Alpha  Maturity = "alpha"
+	Beta   Maturity = "beta"
 	Stable Maturity = "stable"
 )
 
-// HideMaturity hides commands that are not at the specified level of maturity.
+var showMaturing bool
+
+// ShowMaturing configures whether commands that are less mature than the
+// level being displayed are shown rather than hidden.
+func ShowMaturing(show bool) {
+	showMaturing = show
+}
+
+// rank orders maturity levels from most to least mature.
+func (m Maturity) rank() int {
+	switch m {
+	case Alpha:
+		return 2
+	case Beta:
+		return 1
+	case Stable:
+		return 0
+	}
+	return 0
+}
+
+// HideMaturity hides commands that are not at the specified level of maturity,
+// unless less mature commands are shown with ShowMaturing. Commands that are
+// not stable have their maturity prepended to their help text.
 func HideMaturity(p *kong.Path, maturity Maturity) error {
 	nodes := p.Node().Children // copy to avoid possibility of reslicing
 	nodes = append(nodes, p.Node())
 	for _, c := range nodes {
-		mt := Maturity(c.Tag.Get(maturityTag))
-		if mt == "" {
-			mt = Stable
+		mt := GetMaturity(c)
+		if mt != Stable {
+			labelMaturity(c, mt)
 		}
-		if mt != maturity {
-			c.Hidden = true
+		if mt == maturity || (showMaturing && mt.rank() > maturity.rank()) {
+			continue
 		}
+		c.Hidden = true
 	}
 	return nil
 }
 
+// labelMaturity prepends the maturity to the help text of the node. Nodes may
+// be visited by more than one hook, so the label is only added once.
+func labelMaturity(n *kong.Node, m Maturity) {
+	label := fmt.Sprintf("(%s) ", m)
+	if !strings.HasPrefix(n.Help, label) {
+		n.Help = label + n.Help
+	}
+}
+
 // GetMaturity gets the maturity of the node.
 func GetMaturity(n *kong.Node) Maturity {
 	if m := Maturity(n.Tag.Get(maturityTag)); m != "" {
@@ -0,0 +1,111 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"testing"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-cmp/cmp"
+)
+
+type leafCmd struct{}
+
+func (l *leafCmd) Run() error { return nil }
+
+type testCLI struct {
+	Stable leafCmd `cmd:"" help:"Stable command."`
+	Beta   leafCmd `cmd:"" maturity:"beta" help:"Beta command."`
+	Alpha  leafCmd `cmd:"" maturity:"alpha" help:"Alpha command."`
+}
+
+type node struct {
+	Hidden bool
+	Help   string
+}
+
+func TestHideMaturity(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		maturity Maturity
+		show     bool
+		want     map[string]node
+	}{
+		"Stable": {
+			reason:   "Only stable commands should be shown by default.",
+			maturity: Stable,
+			want: map[string]node{
+				"stable": {Help: "Stable command."},
+				"beta":   {Hidden: true, Help: "(beta) Beta command."},
+				"alpha":  {Hidden: true, Help: "(alpha) Alpha command."},
+			},
+		},
+		"StableShowMaturing": {
+			reason:   "All commands should be shown if maturing commands are shown.",
+			maturity: Stable,
+			show:     true,
+			want: map[string]node{
+				"stable": {Help: "Stable command."},
+				"beta":   {Help: "(beta) Beta command."},
+				"alpha":  {Help: "(alpha) Alpha command."},
+			},
+		},
+		"Beta": {
+			reason:   "Only beta commands should be shown in the beta namespace by default.",
+			maturity: Beta,
+			want: map[string]node{
+				"stable": {Hidden: true, Help: "Stable command."},
+				"beta":   {Help: "(beta) Beta command."},
+				"alpha":  {Hidden: true, Help: "(alpha) Alpha command."},
+			},
+		},
+		"BetaShowMaturing": {
+			reason:   "Alpha commands should be shown in the beta namespace if maturing commands are shown.",
+			maturity: Beta,
+			show:     true,
+			want: map[string]node{
+				"stable": {Hidden: true, Help: "Stable command."},
+				"beta":   {Help: "(beta) Beta command."},
+				"alpha":  {Help: "(alpha) Alpha command."},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ShowMaturing(tc.show)
+			defer ShowMaturing(false)
+
+			k, err := kong.New(&testCLI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &kong.Path{App: k.Model}
+			// Hooks may run more than once for a node, which must not
+			// label it twice.
+			for i := 0; i < 2; i++ {
+				if err := HideMaturity(p, tc.maturity); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := map[string]node{}
+			for _, c := range k.Model.Children {
+				got[c.Name] = node{Hidden: c.Hidden, Help: c.Help}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nHideMaturity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	cacheDirMode  = 0o700
+	cacheFileMode = 0o600
+	cacheFileExt  = ".json"
+)
+
+// ResponseCache stores responses to GET requests on disk, so that repeated
+// requests can be revalidated with their ETag or Last-Modified time instead of
+// downloading the response again. Failures to read or write the cache are
+// ignored; the request is then simply sent as is.
+type ResponseCache struct {
+	fs    afero.Fs
+	dir   string
+	paths []string
+	now   func() time.Time
+}
+
+// CacheOption modifies a ResponseCache.
+type CacheOption func(*ResponseCache)
+
+// WithCacheFS sets the filesystem the cache is stored in.
+func WithCacheFS(fs afero.Fs) CacheOption {
+	return func(c *ResponseCache) {
+		c.fs = fs
+	}
+}
+
+// WithCachedPaths limits the cache to requests whose URL path starts with one
+// of the supplied prefixes. All GET requests are cached if no prefix is set.
+func WithCachedPaths(prefixes ...string) CacheOption {
+	return func(c *ResponseCache) {
+		c.paths = append(c.paths, prefixes...)
+	}
+}
+
+// NewResponseCache builds a cache that stores responses in the supplied
+// directory.
+func NewResponseCache(dir string, opts ...CacheOption) *ResponseCache {
+	c := &ResponseCache{
+		fs:  afero.NewOsFs(),
+		dir: dir,
+		now: time.Now,
+	}
+	for _, fn := range opts {
+		fn(c)
+	}
+	return c
+}
+
+// WithResponseCache caches responses to GET requests in the supplied cache. A
+// nil cache is ignored.
+func WithResponseCache(c *ResponseCache) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = c
+	}
+}
+
+// cacheEntry is a response stored in the cache.
+type cacheEntry struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Stored     time.Time   `json:"stored"`
+}
+
+// fresh returns true if the server allowed the entry to be reused without
+// revalidation at the supplied time.
+func (e *cacheEntry) fresh(now time.Time) bool {
+	maxAge, ok := maxAge(e.Header)
+	return ok && now.Sub(e.Stored) < maxAge
+}
+
+// response builds a response to the supplied request from the entry.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// maxAge returns the max-age directive of the Cache-Control header, if any.
+func maxAge(h http.Header) (time.Duration, bool) {
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if k != "max-age" {
+			continue
+		}
+		s, err := strconv.Atoi(v)
+		if err != nil || s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	return 0, false
+}
+
+// noStore returns true if the Cache-Control header forbids storing the
+// response.
+func noStore(h http.Header) bool {
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		if strings.TrimSpace(d) == "no-store" {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if requests to the URL of the supplied request are
+// cached.
+func (c *ResponseCache) matches(req *http.Request) bool {
+	if len(c.paths) == 0 {
+		return true
+	}
+	for _, p := range c.paths {
+		if strings.HasPrefix(req.URL.Path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the name of the file that stores the response to the supplied
+// request. Credentials are part of the key so that responses are never shared
+// between sessions.
+func (c *ResponseCache) key(req *http.Request) string {
+	h := sha256.New()
+	for _, s := range []string{req.URL.String(), req.Header.Get("Cookie"), req.Header.Get("Authorization")} {
+		h.Write([]byte(s)) //nolint:errcheck
+		h.Write([]byte{0}) //nolint:errcheck
+	}
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil))+cacheFileExt)
+}
+
+func (c *ResponseCache) load(key string) (*cacheEntry, bool) {
+	b, err := afero.ReadFile(c.fs, key)
+	if err != nil {
+		return nil, false
+	}
+	e := &cacheEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *ResponseCache) store(key string, e *cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := c.fs.MkdirAll(c.dir, cacheDirMode); err != nil {
+		return
+	}
+	_ = afero.WriteFile(c.fs, key, b, cacheFileMode)
+}
+
+// Purge removes every stored response.
+func (c *ResponseCache) Purge() {
+	_ = c.fs.RemoveAll(c.dir)
+}
+
+// cachingTransport serves GET requests from a ResponseCache, revalidating
+// stored responses with the server. A successful request that modifies a
+// cached path purges the cache, so that the change is visible to the next
+// command.
+type cachingTransport struct {
+	next  http.RoundTripper
+	cache *ResponseCache
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.cache.matches(req) {
+		return t.next.RoundTrip(req)
+	}
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && req.Method != http.MethodHead && resp.StatusCode < http.StatusBadRequest {
+			t.cache.Purge()
+		}
+		return resp, err
+	}
+
+	key := t.cache.key(req)
+	e, ok := t.cache.load(key)
+	if ok && e.fresh(t.cache.now()) {
+		return e.response(req), nil
+	}
+	if ok {
+		// A RoundTripper must not modify the caller's request.
+		req = req.Clone(req.Context())
+		if etag := e.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := e.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		// A 304 carries the caching headers of the current representation.
+		for _, h := range []string{"Cache-Control", "ETag", "Last-Modified", "Expires"} {
+			if v := resp.Header.Get(h); v != "" {
+				e.Header.Set(h, v)
+			}
+		}
+		e.Stored = t.cache.now()
+		t.cache.store(key, e)
+		return e.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) ||
+		(resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.cache.store(key, &cacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Stored:     t.cache.now(),
+	})
+	return resp, nil
+}
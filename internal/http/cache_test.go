@@ -0,0 +1,170 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestResponseCache(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		cookie string
+	}
+	type response struct {
+		status int
+		body   string
+	}
+	type want struct {
+		responses []response
+		served    []string
+	}
+	cases := map[string]struct {
+		reason       string
+		cacheControl string
+		etag         string
+		paths        []string
+		reqs         []request
+		want         want
+	}{
+		"Revalidate": {
+			reason: "A stored response should be revalidated with its ETag and reused if it was not modified.",
+			etag:   `"v1"`,
+			reqs:   []request{{method: http.MethodGet, path: "/v1/accounts"}, {method: http.MethodGet, path: "/v1/accounts"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "accounts"}, {status: http.StatusOK, body: "accounts"}},
+				served:    []string{"GET /v1/accounts 200", "GET /v1/accounts 304"},
+			},
+		},
+		"Fresh": {
+			reason:       "A stored response within its max-age should be reused without a request.",
+			cacheControl: "private, max-age=60",
+			etag:         `"v1"`,
+			reqs:         []request{{method: http.MethodGet, path: "/v1/accounts"}, {method: http.MethodGet, path: "/v1/accounts"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "accounts"}, {status: http.StatusOK, body: "accounts"}},
+				served:    []string{"GET /v1/accounts 200"},
+			},
+		},
+		"NoValidator": {
+			reason: "A response without an ETag or Last-Modified time should not be stored.",
+			reqs:   []request{{method: http.MethodGet, path: "/v1/accounts"}, {method: http.MethodGet, path: "/v1/accounts"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "accounts"}, {status: http.StatusOK, body: "accounts"}},
+				served:    []string{"GET /v1/accounts 200", "GET /v1/accounts 200"},
+			},
+		},
+		"NoStore": {
+			reason:       "A response that forbids storing should not be stored.",
+			cacheControl: "no-store",
+			etag:         `"v1"`,
+			reqs:         []request{{method: http.MethodGet, path: "/v1/accounts"}, {method: http.MethodGet, path: "/v1/accounts"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "accounts"}, {status: http.StatusOK, body: "accounts"}},
+				served:    []string{"GET /v1/accounts 200", "GET /v1/accounts 200"},
+			},
+		},
+		"OtherPath": {
+			reason: "Responses to paths that are not cached should not be stored.",
+			etag:   `"v1"`,
+			paths:  []string{"/v1/accounts"},
+			reqs:   []request{{method: http.MethodGet, path: "/v1/robots"}, {method: http.MethodGet, path: "/v1/robots"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "robots"}, {status: http.StatusOK, body: "robots"}},
+				served:    []string{"GET /v1/robots 200", "GET /v1/robots 200"},
+			},
+		},
+		"Session": {
+			reason: "Responses should not be shared between sessions.",
+			etag:   `"v1"`,
+			reqs:   []request{{method: http.MethodGet, path: "/v1/accounts", cookie: "a"}, {method: http.MethodGet, path: "/v1/accounts", cookie: "b"}},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "accounts"}, {status: http.StatusOK, body: "accounts"}},
+				served:    []string{"GET /v1/accounts 200", "GET /v1/accounts 200"},
+			},
+		},
+		"Purge": {
+			reason:       "A request that modifies a cached path should purge the cache.",
+			cacheControl: "max-age=60",
+			etag:         `"v1"`,
+			reqs: []request{
+				{method: http.MethodGet, path: "/v1/controlPlanes/acme"},
+				{method: http.MethodPost, path: "/v1/controlPlanes/acme"},
+				{method: http.MethodGet, path: "/v1/controlPlanes/acme"},
+			},
+			want: want{
+				responses: []response{{status: http.StatusOK, body: "controlPlanes"}, {status: http.StatusOK, body: "controlPlanes"}, {status: http.StatusOK, body: "controlPlanes"}},
+				served:    []string{"GET /v1/controlPlanes/acme 200", "POST /v1/controlPlanes/acme 200", "GET /v1/controlPlanes/acme 200"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			served := []string{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := http.StatusOK
+				if tc.etag != "" {
+					w.Header().Set("ETag", tc.etag)
+					if r.Header.Get("If-None-Match") == tc.etag {
+						status = http.StatusNotModified
+					}
+				}
+				if tc.cacheControl != "" {
+					w.Header().Set("Cache-Control", tc.cacheControl)
+				}
+				served = append(served, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status))
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					// The body is the collection, e.g. accounts.
+					_, _ = w.Write([]byte(strings.Split(r.URL.Path, "/")[2]))
+				}
+			}))
+			defer srv.Close()
+
+			c := NewResponseCache("/cache", WithCacheFS(afero.NewMemMapFs()), WithCachedPaths(tc.paths...))
+			c.now = func() time.Time { return time.Unix(0, 0) }
+			client := NewClient(WithResponseCache(c))
+
+			got := want{}
+			for _, r := range tc.reqs {
+				req, _ := http.NewRequest(r.method, srv.URL+r.path, nil)
+				if r.cookie != "" {
+					req.Header.Set("Cookie", r.cookie)
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("\n%s\nDo(...): %v", tc.reason, err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close() //nolint:errcheck
+				got.responses = append(got.responses, response{status: resp.StatusCode, body: string(body)})
+			}
+			got.served = served
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}, response{})); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/uuid"
+
+	"github.com/upbound/up/internal/version"
+)
+
+const (
+	// RequestIDHeader is the header carrying the ID generated for every
+	// request, which lets Upbound support correlate failures server-side.
+	RequestIDHeader = "X-Request-Id"
+
+	userAgentProduct = "up"
+	unknownVersion   = "unknown"
+
+	errFmtRequestID = "%s (request ID: %s)"
+
+	maxIdleConnsPerHost = 10
+	idleConnTimeout     = 90 * time.Second
+)
+
+var (
+	transportsMu sync.Mutex
+	// transports holds the pooled transports shared by every client, keyed by
+	// whether TLS verification is skipped.
+	transports = map[bool]*http.Transport{}
+)
+
+// UserAgent returns the user agent sent by up, e.g. up/v0.20.0 linux/amd64.
+func UserAgent() string {
+	v := version.GetVersion()
+	if v == "" {
+		v = unknownVersion
+	}
+	return fmt.Sprintf("%s/%s %s/%s", userAgentProduct, v, runtime.GOOS, runtime.GOARCH)
+}
+
+// RequestIDError is an error caused by a request, annotated with the ID of
+// that request so that it can be shared with Upbound support.
+type RequestIDError struct {
+	err error
+	id  string
+}
+
+// WithRequestID annotates err with the ID of the request that caused it. Nil
+// errors and empty IDs are returned unchanged.
+func WithRequestID(err error, id string) error {
+	if err == nil || id == "" {
+		return err
+	}
+	return &RequestIDError{err: err, id: id}
+}
+
+// Error returns the error message followed by the request ID.
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf(errFmtRequestID, e.err, e.id)
+}
+
+// Unwrap returns the annotated error.
+func (e *RequestIDError) Unwrap() error {
+	return e.err
+}
+
+// RequestID returns the ID of the request that caused the error.
+func (e *RequestIDError) RequestID() string {
+	return e.id
+}
+
+// IsNotFound returns true if the annotated error is a not found error. The
+// Upbound SDK checks for this method without unwrapping errors.
+func (e *RequestIDError) IsNotFound() bool {
+	var nf interface{ IsNotFound() bool }
+	return errors.As(e.err, &nf) && nf.IsNotFound()
+}
+
+// Timeout returns true if the annotated error is a timeout, which url.Error
+// expects transport errors to report.
+func (e *RequestIDError) Timeout() bool {
+	var t interface{ Timeout() bool }
+	return errors.As(e.err, &t) && t.Timeout()
+}
+
+// Hook observes a request made by a client built with NewClient once it
+// completes. The response is nil if the request failed.
+type Hook func(req *http.Request, resp *http.Response, err error, d time.Duration)
+
+type clientOptions struct {
+	insecure  bool
+	userAgent string
+	wrap      func(http.RoundTripper) http.RoundTripper
+	jar       http.CookieJar
+	hooks     []Hook
+	cache     *ResponseCache
+}
+
+// ClientOption modifies the HTTP client or transport built by NewClient and
+// NewTransport.
+type ClientOption func(*clientOptions)
+
+// WithInsecureSkipTLSVerify disables TLS certificate verification.
+func WithInsecureSkipTLSVerify(skip bool) ClientOption {
+	return func(o *clientOptions) {
+		o.insecure = skip
+	}
+}
+
+// WithUserAgent overrides the user agent set on requests that do not already
+// carry one.
+func WithUserAgent(ua string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = ua
+	}
+}
+
+// WithWrapTransport wraps the pooled transport, e.g. for debug logging. A nil
+// function is ignored.
+func WithWrapTransport(fn func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.wrap = fn
+	}
+}
+
+// WithCookieJar sets the cookie jar of the client.
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(o *clientOptions) {
+		o.jar = jar
+	}
+}
+
+// WithHooks adds hooks that observe every completed request.
+func WithHooks(hooks ...Hook) ClientOption {
+	return func(o *clientOptions) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// NewClient builds an HTTP client that shares its connection pool with every
+// other client built by this package.
+func NewClient(opts ...ClientOption) *http.Client {
+	o := newClientOptions(opts...)
+	return &http.Client{
+		Transport: newTransport(o),
+		Jar:       o.jar,
+	}
+}
+
+// NewTransport builds an instrumented round tripper on top of the shared
+// connection pool, for consumers that bring their own client.
+func NewTransport(opts ...ClientOption) http.RoundTripper {
+	return newTransport(newClientOptions(opts...))
+}
+
+func newClientOptions(opts ...ClientOption) *clientOptions {
+	o := &clientOptions{
+		userAgent: UserAgent(),
+	}
+	for _, fn := range opts {
+		fn(o)
+	}
+	return o
+}
+
+func newTransport(o *clientOptions) http.RoundTripper {
+	var rt http.RoundTripper = pooledTransport(o.insecure)
+	if o.wrap != nil {
+		rt = o.wrap(rt)
+	}
+	if o.cache != nil {
+		rt = &cachingTransport{next: rt, cache: o.cache}
+	}
+	return &instrumentedTransport{
+		next:      rt,
+		userAgent: o.userAgent,
+		hooks:     o.hooks,
+	}
+}
+
+// pooledTransport returns the shared transport for the given TLS verification
+// setting, so that keep-alive connections are reused across clients.
+func pooledTransport(insecure bool) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[insecure]; ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.IdleConnTimeout = idleConnTimeout
+	t.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: insecure, //nolint:gosec
+	}
+	transports[insecure] = t
+	return t
+}
+
+// instrumentedTransport sets the user agent and a request ID on outgoing
+// requests, reports completed requests to hooks and annotates transport errors
+// with the request ID.
+type instrumentedTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	hooks     []Hook
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" && t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	id := req.Header.Get(RequestIDHeader)
+	if id == "" {
+		id = uuid.NewString()
+		req.Header.Set(RequestIDHeader, id)
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	for _, h := range t.hooks {
+		h(req, resp, err, time.Since(start))
+	}
+	return resp, WithRequestID(err, id)
+}
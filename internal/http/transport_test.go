@@ -0,0 +1,196 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewClient(t *testing.T) {
+	type want struct {
+		userAgent string
+		status    int
+		hooked    bool
+	}
+	cases := map[string]struct {
+		reason string
+		opts   []ClientOption
+		header string
+		want   want
+	}{
+		"DefaultUserAgent": {
+			reason: "Requests without a user agent should carry the up user agent.",
+			want: want{
+				userAgent: UserAgent(),
+				status:    http.StatusOK,
+			},
+		},
+		"OverrideUserAgent": {
+			reason: "The user agent option should override the default.",
+			opts:   []ClientOption{WithUserAgent("custom")},
+			want: want{
+				userAgent: "custom",
+				status:    http.StatusOK,
+			},
+		},
+		"KeepRequestUserAgent": {
+			reason: "A user agent already set on the request should be preserved.",
+			header: "caller",
+			want: want{
+				userAgent: "caller",
+				status:    http.StatusOK,
+			},
+		},
+		"Hooks": {
+			reason: "Hooks should observe completed requests.",
+			want: want{
+				userAgent: UserAgent(),
+				status:    http.StatusOK,
+				hooked:    true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotUA string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUA = r.UserAgent()
+				if r.Header.Get(RequestIDHeader) == "" {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			defer srv.Close()
+
+			hooked := false
+			opts := tc.opts
+			if tc.want.hooked {
+				opts = append(opts, WithHooks(func(_ *http.Request, resp *http.Response, err error, _ time.Duration) {
+					hooked = err == nil && resp.StatusCode == http.StatusOK
+				}))
+			}
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if tc.header != "" {
+				req.Header.Set("User-Agent", tc.header)
+			}
+			resp, err := NewClient(opts...).Do(req)
+			if err != nil {
+				t.Fatalf("\n%s\nDo(...): %v", tc.reason, err)
+			}
+			defer resp.Body.Close() //nolint:errcheck
+
+			got := want{userAgent: gotUA, status: resp.StatusCode, hooked: hooked}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPooledTransport(t *testing.T) {
+	if pooledTransport(false) != pooledTransport(false) {
+		t.Errorf("pooledTransport(false): expected transport to be shared")
+	}
+	if pooledTransport(false) == pooledTransport(true) {
+		t.Errorf("pooledTransport(true): expected a separate transport when skipping TLS verification")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	want := fmt.Sprintf("up/%s %s/%s", unknownVersion, runtime.GOOS, runtime.GOARCH)
+	if diff := cmp.Diff(want, UserAgent()); diff != "" {
+		t.Errorf("UserAgent(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRequestIDError(t *testing.T) {
+	errBoom := errors.New("boom")
+	failing := WithWrapTransport(func(http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errBoom
+		})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.org", nil)
+	req.Header.Set(RequestIDHeader, "failed-request")
+	_, err := NewClient(failing).Do(req) //nolint:bodyclose
+
+	var rerr *RequestIDError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Do(...): want a *RequestIDError, got %v", err)
+	}
+	if diff := cmp.Diff("failed-request", rerr.RequestID()); diff != "" {
+		t.Errorf("RequestID(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(errBoom, rerr.Unwrap(), test.EquateErrors()); diff != "" {
+		t.Errorf("Unwrap(): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		id     string
+		want   string
+	}{
+		"NoError": {
+			reason: "A nil error should not be annotated.",
+			id:     "failed-request",
+		},
+		"NoID": {
+			reason: "An error should be returned unchanged if there is no request ID.",
+			err:    errBoom,
+			want:   "boom",
+		},
+		"Annotated": {
+			reason: "An error should carry the ID of the request that caused it.",
+			err:    errBoom,
+			id:     "failed-request",
+			want:   "boom (request ID: failed-request)",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := WithRequestID(tc.err, tc.id)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWithRequestID(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("\n%s\nWithRequestID(...): want the annotated error to wrap %v", tc.reason, tc.err)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"os"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+)
+
+// Selector prompts a user to select any number of options.
+type Selector interface {
+	Select(label string, options []string) ([]string, error)
+}
+
+// NewSelector constructs a new selector that presents options as a list of
+// checkboxes on stdin.
+func NewSelector() Selector {
+	return &defaultSelector{
+		in:  os.Stdin,
+		tty: defaultTTY{},
+	}
+}
+
+// defaultSelector is a selector that uses an interactive pterm multiselect.
+type defaultSelector struct {
+	in  file
+	tty tty
+}
+
+// Select prompts a user to select any of the options under the specified
+// label and returns the selected options.
+func (d *defaultSelector) Select(label string, options []string) ([]string, error) {
+	if !d.tty.IsTerminal(int(d.in.Fd())) {
+		return nil, errors.New(errNotTTY)
+	}
+	return pterm.DefaultInteractiveMultiselect.
+		WithOptions(options).
+		WithDefaultText(label).
+		WithMaxHeight(len(options)).
+		Show()
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelect(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		selector Selector
+		want     []string
+		err      error
+	}{
+		"NotATTY": {
+			reason: "Error should be returned if select is called and input is not TTY.",
+			selector: &defaultSelector{
+				in: &mockFile{
+					mockFd: func() uintptr { return 10 },
+				},
+				tty: &mockTTY{
+					mockIsTerminal: func(int) bool { return false },
+				},
+			},
+			err: errors.New(errNotTTY),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.selector.Select("Select", []string{"a", "b"})
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSelect(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apixv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// managedCategory is the category of the CRDs of Crossplane managed
+	// resources.
+	managedCategory = "managed"
+	// crossplaneGroupSuffix is the suffix of the API groups of Crossplane
+	// itself.
+	crossplaneGroupSuffix = "crossplane.io"
+
+	removeFinalizersPatch = `{"metadata":{"finalizers":null}}`
+
+	errListCRDs                  = "failed to list custom resource definitions"
+	errFmtListManaged            = "failed to list managed resources of %s"
+	errFmtRemoveFinalizers       = "failed to remove finalizers from %s %s"
+	errFmtDeleteCRD              = "failed to delete custom resource definition %s"
+	errFmtManagedResourcesRemain = "%d managed resources remain, delete them before removing custom resource definitions"
+)
+
+// ManagedResource is a Crossplane managed resource that remains in a cluster.
+type ManagedResource struct {
+	Resource   schema.GroupVersionResource
+	Kind       string
+	Name       string
+	Finalizers []string
+	Deleting   bool
+}
+
+// Stuck indicates that the managed resource is being deleted, but is blocked
+// by its finalizers.
+func (m ManagedResource) Stuck() bool {
+	return m.Deleting && len(m.Finalizers) > 0
+}
+
+// CRDCleaner removes the CustomResourceDefinitions of Crossplane, its packages
+// and composite resources from a cluster.
+type CRDCleaner struct {
+	crds apixv1client.CustomResourceDefinitionInterface
+	kube dynamic.Interface
+}
+
+// NewCRDCleaner constructs a CRDCleaner.
+func NewCRDCleaner(crds apixv1client.CustomResourceDefinitionInterface, kube dynamic.Interface) *CRDCleaner {
+	return &CRDCleaner{
+		crds: crds,
+		kube: kube,
+	}
+}
+
+// CRDs returns the CustomResourceDefinitions of Crossplane and the ones owned
+// by Crossplane objects, such as package revisions and composite resource
+// definitions.
+func (c *CRDCleaner) CRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	l, err := c.crds.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errListCRDs)
+	}
+	crds := make([]apiextensionsv1.CustomResourceDefinition, 0, len(l.Items))
+	for _, crd := range l.Items {
+		if isCrossplaneCRD(crd) {
+			crds = append(crds, crd)
+		}
+	}
+	return crds, nil
+}
+
+// ManagedResources returns the managed resources of the supplied
+// CustomResourceDefinitions that remain in the cluster.
+func (c *CRDCleaner) ManagedResources(ctx context.Context, crds []apiextensionsv1.CustomResourceDefinition) ([]ManagedResource, error) {
+	var mrs []ManagedResource
+	for _, crd := range crds {
+		if !isManagedCRD(crd) {
+			continue
+		}
+		gvr := schema.GroupVersionResource{
+			Group:    crd.Spec.Group,
+			Version:  storageVersion(crd),
+			Resource: crd.Spec.Names.Plural,
+		}
+		l, err := c.kube.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if kerrors.IsNotFound(err) {
+			// The CRD has been deleted in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtListManaged, crd.GetName())
+		}
+		for _, u := range l.Items {
+			mrs = append(mrs, ManagedResource{
+				Resource:   gvr,
+				Kind:       u.GetKind(),
+				Name:       u.GetName(),
+				Finalizers: u.GetFinalizers(),
+				Deleting:   u.GetDeletionTimestamp() != nil,
+			})
+		}
+	}
+	sort.Slice(mrs, func(i, j int) bool {
+		if mrs[i].Kind != mrs[j].Kind {
+			return mrs[i].Kind < mrs[j].Kind
+		}
+		return mrs[i].Name < mrs[j].Name
+	})
+	return mrs, nil
+}
+
+// RemoveFinalizers removes all finalizers from the managed resource. The
+// external resource of the managed resource is left behind.
+func (c *CRDCleaner) RemoveFinalizers(ctx context.Context, mr ManagedResource) error {
+	_, err := c.kube.Resource(mr.Resource).Patch(ctx, mr.Name, types.MergePatchType, []byte(removeFinalizersPatch), metav1.PatchOptions{})
+	return errors.Wrapf(resource.Ignore(kerrors.IsNotFound, err), errFmtRemoveFinalizers, mr.Kind, mr.Name)
+}
+
+// DeleteCRDs deletes the supplied CustomResourceDefinitions. It refuses to do
+// so while managed resources of any of them remain, as their external
+// resources would otherwise be orphaned.
+func (c *CRDCleaner) DeleteCRDs(ctx context.Context, crds []apiextensionsv1.CustomResourceDefinition) error {
+	mrs, err := c.ManagedResources(ctx, crds)
+	if err != nil {
+		return err
+	}
+	if len(mrs) > 0 {
+		return errors.Errorf(errFmtManagedResourcesRemain, len(mrs))
+	}
+	for _, crd := range crds {
+		if err := c.crds.Delete(ctx, crd.GetName(), metav1.DeleteOptions{}); resource.Ignore(kerrors.IsNotFound, err) != nil {
+			return errors.Wrapf(err, errFmtDeleteCRD, crd.GetName())
+		}
+	}
+	return nil
+}
+
+func isCrossplaneCRD(crd apiextensionsv1.CustomResourceDefinition) bool {
+	if isCrossplaneGroup(crd.Spec.Group) {
+		return true
+	}
+	for _, ref := range crd.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err == nil && isCrossplaneGroup(gv.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCrossplaneGroup(group string) bool {
+	return group == crossplaneGroupSuffix || strings.HasSuffix(group, "."+crossplaneGroupSuffix)
+}
+
+func isManagedCRD(crd apiextensionsv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Spec.Names.Categories {
+		if c == managedCategory {
+			return true
+		}
+	}
+	return false
+}
+
+func storageVersion(crd apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
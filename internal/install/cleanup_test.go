@@ -0,0 +1,210 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apixfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var bucketGVR = schema.GroupVersionResource{Group: "s3.aws.upbound.io", Version: "v1beta1", Resource: "buckets"}
+
+func crd(name, group string, categories ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     "buckets",
+				Categories: categories,
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Storage: true},
+			},
+		},
+	}
+}
+
+func bucket(name string, deleting bool, finalizers ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("s3.aws.upbound.io/v1beta1")
+	u.SetKind("Bucket")
+	u.SetName(name)
+	u.SetFinalizers(finalizers)
+	if deleting {
+		now := metav1.NewTime(time.Now())
+		u.SetDeletionTimestamp(&now)
+	}
+	return u
+}
+
+func newCleaner(crds []runtime.Object, objs ...runtime.Object) (*CRDCleaner, *apixfake.Clientset) {
+	apix := apixfake.NewSimpleClientset(crds...)
+	kube := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		bucketGVR: "BucketList",
+	}, objs...)
+	return NewCRDCleaner(apix.ApiextensionsV1().CustomResourceDefinitions(), kube), apix
+}
+
+func TestCRDCleanerCRDs(t *testing.T) {
+	owned := crd("buckets.s3.aws.upbound.io", "s3.aws.upbound.io", managedCategory)
+	owned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "pkg.crossplane.io/v1", Kind: "ProviderRevision", Name: "provider-aws-s3"}})
+
+	cases := map[string]struct {
+		reason string
+		crds   []runtime.Object
+		want   []string
+	}{
+		"CrossplaneGroup": {
+			reason: "CRDs in Crossplane API groups should be returned.",
+			crds: []runtime.Object{
+				crd("providers.pkg.crossplane.io", "pkg.crossplane.io"),
+				crd("certificates.cert-manager.io", "cert-manager.io"),
+			},
+			want: []string{"providers.pkg.crossplane.io"},
+		},
+		"OwnedByCrossplane": {
+			reason: "CRDs owned by Crossplane objects should be returned.",
+			crds: []runtime.Object{
+				owned,
+				crd("buckets.example.org", "example.org", managedCategory),
+			},
+			want: []string{"buckets.s3.aws.upbound.io"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newCleaner(tc.crds)
+			crds, err := c.CRDs(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nCRDs(...): unexpected error: %v", tc.reason, err)
+			}
+			got := make([]string, 0, len(crds))
+			for _, crd := range crds {
+				got = append(got, crd.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCRDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDCleanerManagedResources(t *testing.T) {
+	managed := crd("buckets.s3.aws.upbound.io", "s3.aws.upbound.io", managedCategory)
+	unmanaged := crd("buckets.s3.aws.upbound.io", "s3.aws.upbound.io")
+
+	cases := map[string]struct {
+		reason string
+		crd    *apiextensionsv1.CustomResourceDefinition
+		objs   []runtime.Object
+		want   []ManagedResource
+	}{
+		"Managed": {
+			reason: "Objects of managed CRDs should be returned, noting whether they are being deleted.",
+			crd:    managed,
+			objs: []runtime.Object{
+				bucket("b", true, "finalizer.managedresource.crossplane.io"),
+				bucket("a", false),
+			},
+			want: []ManagedResource{
+				{Resource: bucketGVR, Kind: "Bucket", Name: "a"},
+				{Resource: bucketGVR, Kind: "Bucket", Name: "b", Finalizers: []string{"finalizer.managedresource.crossplane.io"}, Deleting: true},
+			},
+		},
+		"NotManaged": {
+			reason: "Objects of CRDs that are not in the managed category should be ignored.",
+			crd:    unmanaged,
+			objs: []runtime.Object{
+				bucket("a", false),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newCleaner(nil, tc.objs...)
+			got, err := c.ManagedResources(context.Background(), []apiextensionsv1.CustomResourceDefinition{*tc.crd})
+			if err != nil {
+				t.Fatalf("\n%s\nManagedResources(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nManagedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDCleanerDeleteCRDs(t *testing.T) {
+	managed := crd("buckets.s3.aws.upbound.io", "s3.aws.upbound.io", managedCategory)
+
+	cases := map[string]struct {
+		reason  string
+		objs    []runtime.Object
+		err     error
+		deleted bool
+	}{
+		"ManagedResourcesRemain": {
+			reason: "CRDs should not be deleted while managed resources remain.",
+			objs: []runtime.Object{
+				bucket("a", false),
+			},
+			err: errors.Errorf(errFmtManagedResourcesRemain, 1),
+		},
+		"Successful": {
+			reason:  "CRDs should be deleted when no managed resources remain.",
+			deleted: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, apix := newCleaner([]runtime.Object{managed.DeepCopy()}, tc.objs...)
+			err := c.DeleteCRDs(context.Background(), []apiextensionsv1.CustomResourceDefinition{*managed})
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteCRDs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			l, _ := apix.ApiextensionsV1().CustomResourceDefinitions().List(context.Background(), metav1.ListOptions{})
+			if deleted := len(l.Items) == 0; deleted != tc.deleted {
+				t.Errorf("\n%s\nDeleteCRDs(...): deleted %t, want %t", tc.reason, deleted, tc.deleted)
+			}
+		})
+	}
+}
+
+func TestCRDCleanerRemoveFinalizers(t *testing.T) {
+	c, _ := newCleaner(nil, bucket("a", false, "finalizer.managedresource.crossplane.io"))
+	mr := ManagedResource{Resource: bucketGVR, Kind: "Bucket", Name: "a"}
+	if err := c.RemoveFinalizers(context.Background(), mr); err != nil {
+		t.Fatalf("RemoveFinalizers(...): unexpected error: %v", err)
+	}
+	u, err := c.kube.Resource(bucketGVR).Get(context.Background(), "a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string(nil), u.GetFinalizers()); diff != "" {
+		t.Errorf("RemoveFinalizers(...): -want finalizers, +got finalizers:\n%s", diff)
+	}
+}
@@ -15,22 +15,80 @@
 package install
 
 import (
+	"context"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"k8s.io/client-go/rest"
+
+	"github.com/upbound/up/internal/secrets"
+)
+
+const (
+	errTokenRequired     = "either --token-file or --token-from must be supplied"
+	errTokenFileAndFrom  = "--token-file and --token-from cannot be combined"
+	errReadTokenFile     = "unable to read token file"
+	errFmtResolveSetFrom = "unable to resolve parameter %s"
 )
 
 // Context includes common data that installer consumers may utilize.
 type Context struct {
 	Kubeconfig *rest.Config
 	Namespace  string
+	// RedactKeys are parameter keys whose values are redacted when
+	// parameters are printed.
+	RedactKeys []string
 }
 
 // CommonParams are common parameters for installing and upgrading.
 type CommonParams struct {
 	Set    map[string]string `help:"Set parameters."`
-	File   *os.File          `short:"f" help:"Parameters file."`
+	File   []string          `short:"f" sep:"none" placeholder:"PATH|URL|-" help:"Parameters file. Read from standard input if -, or fetched if an https URL. May be repeated, later files are deep merged over earlier ones."`
 	Bundle *os.File          `help:"Local bundle path."`
 
-	TokenFile *os.File `name:"token-file" required:"" help:"File containing authentication token."`
+	FileChecksum []string `placeholder:"sha256:HEX" help:"Checksum that the contents of the parameters file must match. If repeated, one for each --file in the same order."`
+
+	SetFrom map[string]string `placeholder:"KEY=URI;..." help:"Set parameters from secrets in a secret manager. Values are references like those of --token-from."`
+
+	TokenFile *os.File `name:"token-file" help:"File containing authentication token."`
+	TokenFrom string   `name:"token-from" placeholder:"URI" help:"Reference to the authentication token in a secret manager: vault://PATH#KEY, awssm://NAME[#KEY] or gcpsm://projects/PROJECT/secrets/SECRET[#KEY]."`
+}
+
+// Token returns the authentication token, either read from the token file or
+// resolved from the secret manager the token reference refers to.
+func (p *CommonParams) Token(ctx context.Context, r *secrets.Resolver) (string, error) {
+	switch {
+	case p.TokenFile != nil && p.TokenFrom != "":
+		return "", errors.New(errTokenFileAndFrom)
+	case p.TokenFrom != "":
+		return r.Resolve(ctx, p.TokenFrom)
+	case p.TokenFile != nil:
+		defer p.TokenFile.Close() // nolint:errcheck
+		b, err := io.ReadAll(p.TokenFile)
+		if err != nil {
+			return "", errors.Wrap(err, errReadTokenFile)
+		}
+		return strings.TrimSpace(string(b)), nil
+	default:
+		return "", errors.New(errTokenRequired)
+	}
+}
+
+// SecretParameters resolves the secret references of the parameters set from
+// a secret manager.
+func (p *CommonParams) SecretParameters(ctx context.Context, r *secrets.Resolver) (map[string]string, error) {
+	if len(p.SetFrom) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string, len(p.SetFrom))
+	for k, ref := range p.SetFrom {
+		v, err := r.Resolve(ctx, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtResolveSetFrom, k)
+		}
+		params[k] = v
+	}
+	return params, nil
 }
@@ -0,0 +1,214 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/spf13/afero"
+)
+
+const (
+	blobsDir         = "blobs"
+	partialExt       = ".partial"
+	downloadAttempts = 3
+
+	errGetDigest          = "failed to get digest of OCI image layer"
+	errOpenBlob           = "failed to open cached chart blob"
+	errCopyBlob           = "failed to copy cached chart blob"
+	errNewBlobTransport   = "failed to authenticate to chart registry"
+	errFmtFetchBlobStatus = "failed to fetch chart blob: unexpected status %s"
+	errFmtDownloadBlob    = "failed to download chart blob after %d attempts"
+	errFmtBlobDigest      = "downloaded chart blob has digest %s but %s is required"
+)
+
+// errRangeNotSatisfiable is returned by a blobFetchFn if the offset is not
+// before the end of the blob, i.e. a partial download is at least as long as
+// the blob.
+var errRangeNotSatisfiable = errors.New("failed to fetch chart blob: requested range is not satisfiable")
+
+// A blobFetchFn fetches the contents of a blob of a repository, starting at
+// the supplied offset if possible. It returns the offset the contents actually
+// start at, which is 0 if the registry does not support range requests.
+type blobFetchFn func(ctx context.Context, repo name.Repository, d v1.Hash, offset int64) (io.ReadCloser, int64, error)
+
+// newRangeFetcher returns a blobFetchFn that fetches blobs from the registry
+// with HTTP range requests, so that interrupted downloads can be resumed.
+func newRangeFetcher(auth authn.Authenticator, rt http.RoundTripper) blobFetchFn {
+	return func(ctx context.Context, repo name.Repository, d v1.Hash, offset int64) (io.ReadCloser, int64, error) {
+		t, err := transport.NewWithContext(ctx, repo.Registry, auth, rt, []string{repo.Scope(transport.PullScope)})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errNewBlobTransport)
+		}
+		u := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", repo.Registry.Scheme(), repo.RegistryStr(), repo.RepositoryStr(), d)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		res, err := (&http.Client{Transport: t}).Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch res.StatusCode {
+		case http.StatusPartialContent:
+			return res.Body, offset, nil
+		case http.StatusOK:
+			return res.Body, 0, nil
+		case http.StatusRequestedRangeNotSatisfiable:
+			_ = res.Body.Close()
+			return nil, 0, errRangeNotSatisfiable
+		default:
+			_ = res.Body.Close()
+			return nil, 0, errors.Errorf(errFmtFetchBlobStatus, res.Status)
+		}
+	}
+}
+
+// blobPath returns the path of the blob with the supplied digest in the blob
+// cache of the puller.
+func (p *registryPuller) blobPath(d v1.Hash) string {
+	return filepath.Join(p.blobDir, d.Algorithm, d.Hex)
+}
+
+// download writes the compressed contents of the layer to the file. The
+// contents are cached by digest, so that charts are pulled only once for any
+// install or upgrade sharing the cache, and downloads that are interrupted are
+// resumed rather than restarted. Contents are always verified against the
+// digest of the layer.
+func (p *registryPuller) download(ctx context.Context, repo name.Repository, l v1.Layer, fileName string) error {
+	if p.blobDir == "" || p.fetchBlob == nil {
+		return p.write(l, fileName)
+	}
+	d, err := l.Digest()
+	if err != nil {
+		return errors.Wrap(err, errGetDigest)
+	}
+	blob := p.blobPath(d)
+	if err := p.verify(blob, d); err != nil {
+		if err := p.fetchToCache(ctx, repo, d, blob); err != nil {
+			return err
+		}
+	}
+	return p.copy(blob, fileName)
+}
+
+// fetchToCache downloads the blob with the supplied digest to the path, resuming
+// from any partial download of an earlier attempt.
+func (p *registryPuller) fetchToCache(ctx context.Context, repo name.Repository, d v1.Hash, path string) error {
+	if err := p.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	partial := path + partialExt
+	var err error
+	for i := 0; i < downloadAttempts; i++ {
+		if err = p.resume(ctx, repo, d, partial); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtDownloadBlob, downloadAttempts)
+	}
+	if err := p.verify(partial, d); err != nil {
+		// A corrupt download must not be resumed.
+		_ = p.fs.Remove(partial)
+		return err
+	}
+	return p.fs.Rename(partial, path)
+}
+
+// resume appends the rest of the blob to the partial download.
+func (p *registryPuller) resume(ctx context.Context, repo name.Repository, d v1.Hash, partial string) error {
+	var offset int64
+	if fi, err := p.fs.Stat(partial); err == nil {
+		offset = fi.Size()
+	}
+	body, start, err := p.fetchBlob(ctx, repo, d, offset)
+	if errors.Is(err, errRangeNotSatisfiable) && offset > 0 {
+		// The partial download is complete if it matches the digest, e.g.
+		// when an earlier run was interrupted before renaming it. Otherwise it
+		// is longer than the blob, and the download is restarted.
+		if p.verify(partial, d) == nil {
+			return nil
+		}
+		if err := p.fs.Remove(partial); err != nil {
+			return err
+		}
+		body, start, err = p.fetchBlob(ctx, repo, d, 0)
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close() // nolint:errcheck
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if start == 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	f, err := p.fs.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// verify returns an error if the file does not exist or its contents do not
+// match the digest.
+func (p *registryPuller) verify(path string, d v1.Hash) error {
+	f, err := p.fs.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	got := v1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(h.Sum(nil))}
+	if got != d {
+		return errors.Errorf(errFmtBlobDigest, got, d)
+	}
+	return nil
+}
+
+// copy copies the cached blob to the file.
+func (p *registryPuller) copy(blob, fileName string) error {
+	f, err := p.fs.Open(blob)
+	if err != nil {
+		return errors.Wrap(err, errOpenBlob)
+	}
+	defer f.Close() // nolint:errcheck
+	return errors.Wrap(afero.WriteReader(p.fs, fileName, f), errCopyBlob)
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/spf13/afero"
+)
+
+func newBlobFetchFn(content string, ranges bool, err error) blobFetchFn {
+	return func(_ context.Context, _ name.Repository, _ v1.Hash, offset int64) (io.ReadCloser, int64, error) {
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ranges {
+			offset = 0
+		}
+		if offset > 0 && offset >= int64(len(content)) {
+			return nil, 0, errRangeNotSatisfiable
+		}
+		return io.NopCloser(strings.NewReader(content[offset:])), offset, nil
+	}
+}
+
+func TestRegistryPullerDownload(t *testing.T) {
+	errBoom := errors.New("boom")
+	content := "chart contents"
+	l := static.NewLayer([]byte(content), HelmChartContentLayerMediaType)
+	d, _ := l.Digest()
+	repo, _ := name.NewRepository("registry.upbound.io/enterprise/enterprise")
+	blob := "/cache/blobs/sha256/" + d.Hex
+	// A corrupt partial download is resumed from its end.
+	corrupt, _, _ := v1.SHA256(strings.NewReader("corrupt" + content[len("corrupt"):]))
+
+	cases := map[string]struct {
+		reason  string
+		files   map[string]string
+		fetch   blobFetchFn
+		err     error
+		partial bool
+	}{
+		"Cached": {
+			reason: "A cached blob should not be fetched again.",
+			files:  map[string]string{blob: content},
+			fetch:  newBlobFetchFn("", false, errBoom),
+		},
+		"Fetched": {
+			reason: "A blob that is not cached should be fetched.",
+			fetch:  newBlobFetchFn(content, true, nil),
+		},
+		"CorruptCache": {
+			reason: "A cached blob that does not match its digest should be fetched again.",
+			files:  map[string]string{blob: "corrupt"},
+			fetch:  newBlobFetchFn(content, true, nil),
+		},
+		"Resumed": {
+			reason: "A partially downloaded blob should be resumed.",
+			files:  map[string]string{blob + partialExt: content[:5]},
+			fetch:  newBlobFetchFn(content, true, nil),
+		},
+		"RangesNotSupported": {
+			reason: "A partially downloaded blob should be restarted if the registry does not support ranges.",
+			files:  map[string]string{blob + partialExt: content[:5]},
+			fetch:  newBlobFetchFn(content, false, nil),
+		},
+		"CompletePartial": {
+			reason: "A complete partial download should be verified and cached when its range cannot be fetched.",
+			files:  map[string]string{blob + partialExt: content},
+			fetch:  newBlobFetchFn(content, true, nil),
+		},
+		"OverlongPartial": {
+			reason: "A partial download longer than the blob should be restarted when its range cannot be fetched.",
+			files:  map[string]string{blob + partialExt: content + " and more"},
+			fetch:  newBlobFetchFn(content, true, nil),
+		},
+		"DigestMismatch": {
+			reason: "A downloaded blob that does not match its digest should be discarded.",
+			files:  map[string]string{blob + partialExt: "corrupt"},
+			fetch:  newBlobFetchFn(content, true, nil),
+			err:    errors.Errorf(errFmtBlobDigest, corrupt, d),
+		},
+		"ErrorFetch": {
+			reason:  "If the blob cannot be fetched an error should be returned, keeping the partial download.",
+			files:   map[string]string{blob + partialExt: content[:5]},
+			fetch:   newBlobFetchFn("", true, errBoom),
+			err:     errors.Wrapf(errBoom, errFmtDownloadBlob, downloadAttempts),
+			partial: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for f, c := range tc.files {
+				_ = afero.WriteFile(fs, f, []byte(c), 0644)
+			}
+			p := &registryPuller{fs: fs, blobDir: "/cache/blobs", fetchBlob: tc.fetch}
+			err := p.download(context.Background(), repo, l, "/tmp/chart.tgz")
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndownload(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				_, serr := fs.Stat(blob + partialExt)
+				if partial := serr == nil; partial != tc.partial {
+					t.Errorf("\n%s\ndownload(...): partial download kept %t, want %t", tc.reason, partial, tc.partial)
+				}
+				return
+			}
+			got, _ := afero.ReadFile(fs, "/tmp/chart.tgz")
+			if diff := cmp.Diff(content, string(got)); diff != "" {
+				t.Errorf("\n%s\ndownload(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err := p.verify(blob, d); err != nil {
+				t.Errorf("\n%s\ndownload(...): blob is not cached: %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestRangeFetcher(t *testing.T) {
+	content := "chart contents"
+	d := v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("0", 64)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/enterprise/blobs/" + d.String():
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	repo, _ := name.NewRepository(strings.TrimPrefix(srv.URL, "http://")+"/enterprise", name.Insecure)
+
+	cases := map[string]struct {
+		reason string
+		digest v1.Hash
+		offset int64
+		want   string
+		start  int64
+		err    error
+	}{
+		"Full": {
+			reason: "The whole blob should be fetched without an offset.",
+			digest: d,
+			want:   content,
+		},
+		"Range": {
+			reason: "The rest of the blob should be fetched from the offset.",
+			digest: d,
+			offset: 6,
+			want:   "contents",
+			start:  6,
+		},
+		"RangeNotSatisfiable": {
+			reason: "A distinct error should be returned if the offset is at the end of the blob.",
+			digest: d,
+			offset: int64(len(content)),
+			err:    errRangeNotSatisfiable,
+		},
+		"NotFound": {
+			reason: "An error should be returned if the blob does not exist.",
+			digest: v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("1", 64)},
+			err:    errors.Errorf(errFmtFetchBlobStatus, "404 Not Found"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			body, start, err := newRangeFetcher(authn.Anonymous, http.DefaultTransport)(context.Background(), repo, tc.digest, tc.offset)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			defer body.Close() // nolint:errcheck
+			got, _ := io.ReadAll(body)
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if start != tc.start {
+				t.Errorf("\n%s\nFetch(...): start %d, want %d", tc.reason, start, tc.start)
+			}
+		})
+	}
+}
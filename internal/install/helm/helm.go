@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/Masterminds/semver"
@@ -33,8 +34,10 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	uphttp "github.com/upbound/up/internal/http"
 	"github.com/upbound/up/internal/install"
 )
 
@@ -44,6 +47,7 @@ const (
 	defaultNamespace = "upbound-system"
 	allVersions      = ">0.0.0-0"
 	waitTimeout      = 10 * time.Minute
+	maxHistory       = 256
 )
 
 const (
@@ -56,10 +60,13 @@ const (
 	errGetLatestPulled                   = "could not identify chart pulled as latest"
 	errCorruptTempDirFmt                 = "corrupt chart tmp directory, consider removing cache (%s)"
 	errMoveLatest                        = "could not move latest pulled chart to cache"
+	errDigestRequiresOCI                 = "chart digest can only be pinned for OCI charts"
 
 	errUpgradeFromAlternateVersionFmt = "cannot upgrade %s to %s with version mismatch"
 	errFailedUpgradeFailedRollback    = "failed upgrade resulted in a failed rollback"
 	errFailedUpgradeRollback          = "failed upgrade was rolled back"
+	errGetHistory                     = "could not get release history"
+	errRollbackFmt                    = "could not rollback to revision %d"
 )
 
 type helmPuller interface {
@@ -94,6 +101,21 @@ type helmUpgrader interface {
 
 type helmRollbacker interface {
 	Run(string) error
+	SetVersion(int)
+}
+
+// rollbacker adapts a Helm rollback action, which is configured with the
+// revision to roll back to before it is run.
+type rollbacker struct {
+	*action.Rollback
+}
+
+func (r *rollbacker) SetVersion(version int) {
+	r.Version = version
+}
+
+type helmHistorian interface {
+	Run(string) ([]*release.Release, error)
 }
 
 type helmUninstaller interface {
@@ -120,11 +142,17 @@ type installer struct {
 	rollbackOnError bool
 	force           bool
 	wait            bool
+	reuseValues     bool
+	report          func([]install.ComponentStatus)
 	home            HomeDirFn
 	fs              afero.Fs
 	tempDir         TempDirFn
 	log             logging.Logger
 	oci             bool
+	mirror          *install.ImageMirror
+	chartDigest     string
+	chartKeyring    string
+	redactKeys      []string
 
 	// Auth
 	username string
@@ -135,8 +163,12 @@ type installer struct {
 	getClient       helmGetter
 	installClient   helmInstaller
 	upgradeClient   helmUpgrader
+	dryRunClient    helmUpgrader
+	renderClient    helmInstaller
 	rollbackClient  helmRollbacker
+	historyClient   helmHistorian
 	uninstallClient helmUninstaller
+	kube            kubernetes.Interface
 
 	// Loader
 	load LoaderFn
@@ -174,6 +206,21 @@ func IsOCI() InstallerModifierFn {
 	}
 }
 
+// WithChartDigest pins the OCI chart to the manifest with the supplied digest.
+func WithChartDigest(d string) InstallerModifierFn {
+	return func(h *installer) {
+		h.chartDigest = d
+	}
+}
+
+// WithChartKeyring verifies the provenance of pulled charts against the public
+// keys in the supplied keyring.
+func WithChartKeyring(k string) InstallerModifierFn {
+	return func(h *installer) {
+		h.chartKeyring = k
+	}
+}
+
 // WithLogger sets the logger for the helm installer.
 func WithLogger(l logging.Logger) InstallerModifierFn {
 	return func(h *installer) {
@@ -216,6 +263,14 @@ func Wait() InstallerModifierFn {
 	}
 }
 
+// ReuseValues will cause upgrades to merge the given parameters on top of the
+// values of the installed release rather than replacing them.
+func ReuseValues() InstallerModifierFn {
+	return func(h *installer) {
+		h.reuseValues = true
+	}
+}
+
 // NewManager builds a helm install manager for UXP.
 func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifiers ...InstallerModifierFn) (install.Manager, error) { // nolint:gocyclo
 	h := &installer{
@@ -233,6 +288,9 @@ func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifie
 		m(h)
 	}
 
+	if h.chartDigest != "" && !h.oci {
+		return nil, errors.New(errDigestRequiresOCI)
+	}
 	if h.cacheDir == "" {
 		home, err := h.home()
 		if err != nil {
@@ -259,10 +317,15 @@ func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifie
 
 	// Pull Client
 	if h.oci {
-		h.pullClient = newRegistryPuller(withRemoteOpts(remote.WithAuth(&authn.Basic{
+		auth := &authn.Basic{
 			Username: h.username,
 			Password: h.password,
-		})), withRepoURL(h.repoURL))
+		}
+		h.pullClient = newRegistryPuller(withRemoteOpts(
+			remote.WithAuth(auth),
+			remote.WithTransport(uphttp.NewTransport()),
+		), withRepoURL(h.repoURL), withDigest(h.chartDigest), withKeyring(h.chartKeyring),
+			withBlobCache(filepath.Join(h.cacheDir, blobsDir), newRangeFetcher(auth, uphttp.NewTransport())))
 	} else {
 		// TODO(hasheddan): we currently use our own OCI client instead of the
 		// upstream Helm support.
@@ -275,9 +338,24 @@ func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifie
 		p.Password = h.password
 		p.Settings = &cli.EnvSettings{}
 		p.RepoURL = h.repoURL.String()
+		if h.chartKeyring != "" {
+			p.Verify = true
+			p.Keyring = h.chartKeyring
+		}
 		h.pullClient = &puller{p}
 	}
 
+	// Helm's own wait is replaced by waitForReadiness when readiness is
+	// reported.
+	helmWait := h.wait && h.report == nil
+	if h.report != nil {
+		kube, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		h.kube = kube
+	}
+
 	// Get Client
 	h.getClient = action.NewGet(actionConfig)
 
@@ -285,17 +363,39 @@ func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifie
 	ic := action.NewInstall(actionConfig)
 	ic.Namespace = h.namespace
 	ic.ReleaseName = h.chartName
-	ic.Wait = h.wait
+	ic.Wait = helmWait
 	ic.Timeout = waitTimeout
 	h.installClient = ic
 
 	// Upgrade Client
 	uc := action.NewUpgrade(actionConfig)
 	uc.Namespace = h.namespace
-	uc.Wait = h.wait
+	uc.Wait = helmWait
 	uc.Timeout = waitTimeout
+	uc.ReuseValues = h.reuseValues
 	h.upgradeClient = uc
 
+	// Dry Run Upgrade Client
+	dc := action.NewUpgrade(actionConfig)
+	dc.Namespace = h.namespace
+	dc.DryRun = true
+	dc.ReuseValues = h.reuseValues
+	h.dryRunClient = dc
+
+	// Render Client
+	// A client-only install replaces the Kubernetes client and release
+	// storage of its configuration, so it must not share actionConfig.
+	rc := action.NewInstall(&action.Configuration{Log: func(format string, v ...any) {
+		h.log.Debug(fmt.Sprintf(format, v...))
+	}})
+	rc.Namespace = h.namespace
+	rc.ReleaseName = h.releaseName
+	rc.DryRun = true
+	rc.ClientOnly = true
+	rc.Replace = true
+	rc.IncludeCRDs = true
+	h.renderClient = rc
+
 	// Uninstall Client
 	unc := action.NewUninstall(actionConfig)
 	unc.Wait = h.wait
@@ -304,9 +404,14 @@ func NewManager(config *rest.Config, chartName string, repoURL *url.URL, modifie
 
 	// Rollback Client
 	rb := action.NewRollback(actionConfig)
-	rb.Wait = h.wait
+	rb.Wait = helmWait
 	rb.Timeout = waitTimeout
-	h.rollbackClient = rb
+	h.rollbackClient = &rollbacker{rb}
+
+	// History Client
+	hc := action.NewHistory(actionConfig)
+	hc.Max = maxHistory
+	h.historyClient = hc
 
 	return h, nil
 }
@@ -362,8 +467,15 @@ func (h *installer) Install(version string, parameters map[string]any) error {
 		return err
 	}
 
-	_, err = h.installClient.Run(helmChart, parameters)
-	return err
+	h.logParameters("Installing chart", version, parameters)
+	parameters, err = h.mirrorValues(helmChart, parameters)
+	if err != nil {
+		return err
+	}
+	if _, err := h.installClient.Run(helmChart, parameters); err != nil {
+		return err
+	}
+	return h.waitForReadiness()
 }
 
 // Upgrade upgrades an existing installation to a new version.
@@ -391,7 +503,15 @@ func (h *installer) Upgrade(version string, parameters map[string]any) error {
 		return err
 	}
 
+	h.logParameters("Upgrading chart", version, parameters)
+	parameters, err = h.mirrorValues(helmChart, parameters)
+	if err != nil {
+		return err
+	}
 	_, upErr := h.upgradeClient.Run(h.releaseName, helmChart, parameters)
+	if upErr == nil {
+		upErr = h.waitForReadiness()
+	}
 	if upErr != nil && h.rollbackOnError {
 		if rErr := h.rollbackClient.Run(h.releaseName); rErr != nil {
 			return errors.Wrap(rErr, errFailedUpgradeFailedRollback)
@@ -401,6 +521,47 @@ func (h *installer) Upgrade(version string, parameters map[string]any) error {
 	return upErr
 }
 
+// History returns the revisions of the installation, oldest first.
+func (h *installer) History() ([]install.Revision, error) {
+	if _, err := h.GetCurrentVersion(); err != nil {
+		return nil, err
+	}
+	rels, err := h.historyClient.Run(h.releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetHistory)
+	}
+	sort.Slice(rels, func(i, j int) bool { return rels[i].Version < rels[j].Version })
+	revs := make([]install.Revision, 0, len(rels))
+	for _, r := range rels {
+		rev := install.Revision{
+			Revision: r.Version,
+		}
+		if r.Chart != nil && r.Chart.Metadata != nil {
+			rev.Version = r.Chart.Metadata.Version
+		}
+		if r.Info != nil {
+			rev.Status = r.Info.Status.String()
+			rev.Updated = r.Info.LastDeployed.Time
+			rev.Description = r.Info.Description
+		}
+		revs = append(revs, rev)
+	}
+	return revs, nil
+}
+
+// Rollback rolls the installation back to the supplied revision and waits for
+// it to become ready. A revision of 0 rolls back to the previous revision.
+func (h *installer) Rollback(revision int) error {
+	if _, err := h.GetCurrentVersion(); err != nil {
+		return err
+	}
+	h.rollbackClient.SetVersion(revision)
+	if err := h.rollbackClient.Run(h.releaseName); err != nil {
+		return errors.Wrapf(err, errRollbackFmt, revision)
+	}
+	return h.waitForReadiness()
+}
+
 // Uninstall uninstalls an installation.
 func (h *installer) Uninstall() error {
 	_, err := h.uninstallClient.Run(h.chartName)
@@ -415,7 +576,9 @@ func (h *installer) pullAndLoad(version string) (*chart.Chart, error) { //nolint
 		// the chart from the cache.
 		// version = strings.TrimPrefix(version, "v")
 		fileName := filepath.Join(h.cacheDir, fmt.Sprintf("%s-%s.tgz", h.chartName, version))
-		if _, err := h.fs.Stat(filepath.Join(h.cacheDir, fileName)); err != nil {
+		// A cached chart is not trusted when the pulled chart has to be
+		// verified.
+		if _, err := h.fs.Stat(fileName); err != nil || h.verifies() {
 			h.pullClient.SetDestDir(h.cacheDir)
 			if err := h.pullChart(version); err != nil {
 				return nil, errors.Wrap(err, errPullChart)
@@ -440,13 +603,20 @@ func (h *installer) pullAndLoad(version string) (*chart.Chart, error) { //nolint
 	if err != nil {
 		return nil, errors.Wrap(err, errGetLatestPulled)
 	}
-	if len(files) != 1 {
+	charts := make([]os.FileInfo, 0, len(files))
+	for _, f := range files {
+		// Provenance files are pulled alongside signed charts.
+		if filepath.Ext(f.Name()) != provenanceExt {
+			charts = append(charts, f)
+		}
+	}
+	if len(charts) != 1 {
 		return nil, errors.Errorf(errCorruptTempDirFmt, h.cacheDir)
 	}
 	// load the chart before copying to cache so that we are able to identify
 	// this version in the cache if it is explicitly specified in a future
 	// install or upgrade.
-	tmpFileName := filepath.Join(tmp, files[0].Name())
+	tmpFileName := filepath.Join(tmp, charts[0].Name())
 	c, err := h.load(tmpFileName)
 	if err != nil {
 		return nil, err
@@ -458,6 +628,20 @@ func (h *installer) pullAndLoad(version string) (*chart.Chart, error) { //nolint
 	return c, nil
 }
 
+// logParameters logs the parameters of an install or upgrade, redacting
+// sensitive values so that logs can be shared.
+func (h *installer) logParameters(msg, version string, parameters map[string]any) {
+	if h.log == nil {
+		return
+	}
+	h.log.Debug(msg, "version", version, "parameters", Redact(parameters, h.redactKeys...))
+}
+
+// verifies indicates whether pulled charts are pinned or verified.
+func (h *installer) verifies() bool {
+	return h.chartDigest != "" || h.chartKeyring != ""
+}
+
 func (h *installer) pullChart(version string) error {
 	// NOTE(hasheddan): Because UXP uses different Helm repos for stable and
 	// development versions, we are safe to set version to latest in repo
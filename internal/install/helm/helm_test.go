@@ -16,6 +16,7 @@ package helm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -25,6 +26,9 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	helmtime "helm.sh/helm/v3/pkg/time"
+
+	"github.com/upbound/up/internal/install"
 )
 
 type mockGetClient struct {
@@ -78,6 +82,18 @@ func (m *mockRollbackClient) Run(r string) error {
 	return m.runFn(r)
 }
 
+// SetVersion is a no op.
+func (m *mockRollbackClient) SetVersion(int) {}
+
+type mockHistoryClient struct {
+	runFn func(string) ([]*release.Release, error)
+}
+
+// Run calls the underlying run function.
+func (m *mockHistoryClient) Run(r string) ([]*release.Release, error) {
+	return m.runFn(r)
+}
+
 type mockUninstallClient struct {
 	runFn func(string) (*release.UninstallReleaseResponse, error)
 }
@@ -878,6 +894,165 @@ func TestUninstall(t *testing.T) {
 	}
 }
 
+func TestHistory(t *testing.T) {
+	errBoom := errors.New("boom")
+	chartName := "primary-chart"
+	deployed := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	getClient := &mockGetClient{
+		runFn: func(string) (*release.Release, error) {
+			return &release.Release{
+				Chart: &chart.Chart{
+					Metadata: &chart.Metadata{
+						Version: "1.1.0",
+					},
+				},
+			}, nil
+		},
+	}
+	cases := map[string]struct {
+		reason    string
+		installer *installer
+		want      []install.Revision
+		err       error
+	}{
+		"ErrorHistory": {
+			reason: "If unable to get release history an error should be returned.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient:   getClient,
+				historyClient: &mockHistoryClient{
+					runFn: func(string) ([]*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+			},
+			err: errors.Wrap(errBoom, errGetHistory),
+		},
+		"Successful": {
+			reason: "Revisions should be returned in ascending order.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient:   getClient,
+				historyClient: &mockHistoryClient{
+					runFn: func(string) ([]*release.Release, error) {
+						return []*release.Release{
+							{
+								Version: 2,
+								Chart:   &chart.Chart{Metadata: &chart.Metadata{Version: "1.1.0"}},
+								Info: &release.Info{
+									Status:       release.StatusDeployed,
+									LastDeployed: helmtime.Time{Time: deployed},
+									Description:  "Upgrade complete",
+								},
+							},
+							{
+								Version: 1,
+								Chart:   &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+								Info: &release.Info{
+									Status:       release.StatusSuperseded,
+									LastDeployed: helmtime.Time{Time: deployed},
+									Description:  "Install complete",
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			want: []install.Revision{
+				{Revision: 1, Version: "1.0.0", Status: "superseded", Updated: deployed, Description: "Install complete"},
+				{Revision: 2, Version: "1.1.0", Status: "deployed", Updated: deployed, Description: "Upgrade complete"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			revs, err := tc.installer.History()
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nHistory(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, revs); diff != "" {
+				t.Errorf("\n%s\nHistory(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+	chartName := "primary-chart"
+	getClient := &mockGetClient{
+		runFn: func(string) (*release.Release, error) {
+			return &release.Release{
+				Chart: &chart.Chart{
+					Metadata: &chart.Metadata{
+						Version: "1.1.0",
+					},
+				},
+			}, nil
+		},
+	}
+	cases := map[string]struct {
+		reason    string
+		installer *installer
+		revision  int
+		err       error
+	}{
+		"ErrorNotInstalled": {
+			reason: "If the release is not installed an error should be returned.",
+			installer: &installer{
+				namespace:   "test",
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient: &mockGetClient{
+					runFn: func(string) (*release.Release, error) {
+						return nil, driver.ErrReleaseNotFound
+					},
+				},
+			},
+			err: errors.Wrapf(driver.ErrReleaseNotFound, errGetInstalledReleaseFmt, chartName, "test"),
+		},
+		"ErrorRollback": {
+			reason: "If rollback fails an error should be returned.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient:   getClient,
+				rollbackClient: &mockRollbackClient{
+					runFn: func(string) error {
+						return errBoom
+					},
+				},
+			},
+			revision: 3,
+			err:      errors.Wrapf(errBoom, errRollbackFmt, 3),
+		},
+		"Successful": {
+			reason: "If rollback succeeds no error should be returned.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient:   getClient,
+				rollbackClient: &mockRollbackClient{
+					runFn: func(string) error {
+						return nil
+					},
+				},
+			},
+			revision: 3,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.installer.Rollback(tc.revision)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRollback(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestEquivalentVersions(t *testing.T) {
 	cases := map[string]struct {
 		reason  string
@@ -0,0 +1,51 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/upbound/up/internal/install"
+)
+
+const (
+	errMirrorValues = "could not rewrite chart images to mirror"
+)
+
+// WithImageMirror rewrites the image references in the chart values to the
+// supplied mirror. A nil mirror is ignored.
+func WithImageMirror(m *install.ImageMirror) InstallerModifierFn {
+	return func(h *installer) {
+		h.mirror = m
+	}
+}
+
+// mirrorValues returns the parameters merged over the chart values, including
+// those of subcharts, with every image reference rewritten to the mirror. The
+// parameters are returned unchanged if no mirror is configured.
+func (h *installer) mirrorValues(c *chart.Chart, parameters map[string]any) (map[string]any, error) {
+	if h.mirror == nil {
+		return parameters, nil
+	}
+	// CoalesceValues copies the parameters rather than modifying them.
+	vals, err := chartutil.CoalesceValues(c, parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, errMirrorValues)
+	}
+	h.mirror.RewriteValues(vals)
+	return vals, nil
+}
@@ -16,6 +16,8 @@ package helm
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"helm.sh/helm/v3/pkg/strvals"
 
@@ -24,24 +26,164 @@ import (
 
 // Parser is a helm-style parameter parser.
 type Parser struct {
+	defaults  map[string]any
 	values    map[string]any
+	files     []install.ValuesFile
 	overrides map[string]string
+	literals  map[string]string
+	warn      WarnFn
+}
+
+// WarnFn reports a warning.
+type WarnFn func(format string, args ...any)
+
+// ParserModifierFn modifies the parser.
+type ParserModifierFn func(*Parser)
+
+// WithValuesFiles sets values files that are deep merged in order on top of
+// the base values, so that later files take precedence over earlier ones.
+func WithValuesFiles(f ...install.ValuesFile) ParserModifierFn {
+	return func(p *Parser) {
+		p.files = f
+	}
+}
+
+// WithWarnFn sets the function used to warn about values of a values file
+// that are replaced by a later one.
+func WithWarnFn(fn WarnFn) ParserModifierFn {
+	return func(p *Parser) {
+		p.warn = fn
+	}
+}
+
+// WithDefaults sets values that the base values and overrides are merged on
+// top of, such as a sizing preset.
+func WithDefaults(d map[string]any) ParserModifierFn {
+	return func(p *Parser) {
+		p.defaults = d
+	}
+}
+
+// WithLiteralOverrides sets overrides whose values are used verbatim rather
+// than parsed, such as secrets that may contain commas or braces. Keys are
+// dot separated paths. Literal overrides take precedence over all other
+// values.
+func WithLiteralOverrides(o map[string]string) ParserModifierFn {
+	return func(p *Parser) {
+		p.literals = o
+	}
 }
 
 // NewParser returns a parameter parser backed by helm.
-func NewParser(base map[string]any, overrides map[string]string) install.ParameterParser {
-	return &Parser{
+func NewParser(base map[string]any, overrides map[string]string, modifiers ...ParserModifierFn) install.ParameterParser {
+	p := &Parser{
 		values:    base,
 		overrides: overrides,
+		warn:      func(string, ...any) {},
+	}
+	for _, m := range modifiers {
+		m(p)
 	}
+	return p
 }
 
-// Parse parses install and upgrade parameters
+// Parse parses install and upgrade parameters. Literal overrides take
+// precedence over overrides, which take precedence over values files, which
+// take precedence over base values, which take precedence over defaults.
 func (p *Parser) Parse() (map[string]any, error) {
+	if p.values == nil {
+		p.values = map[string]any{}
+	}
+	if p.defaults != nil {
+		p.values = mergeValues(p.defaults, p.values)
+	}
+	if len(p.files) > 0 {
+		p.values = mergeValues(p.values, p.mergeFiles())
+	}
 	for k, v := range p.overrides {
 		if err := strvals.ParseInto(fmt.Sprintf("%s=%s", k, v), p.values); err != nil {
 			return nil, err
 		}
 	}
+	for k, v := range p.literals {
+		setPath(p.values, strings.Split(k, "."), v)
+	}
 	return p.values, nil
 }
+
+// setPath sets the value at the path in the values, creating or replacing
+// intermediate maps as needed.
+func setPath(values map[string]any, path []string, v string) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := values[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			values[k] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = v
+}
+
+// mergeFiles deep merges the values files in order, warning about each value
+// of a file that is replaced with a different one by a later file.
+func (p *Parser) mergeFiles() map[string]any {
+	out := map[string]any{}
+	origins := map[string]string{}
+	for _, f := range p.files {
+		p.mergeFile(out, f.Values, "", f.Source, origins)
+	}
+	return out
+}
+
+// mergeFile merges the values of a file into dst, recording the file each
+// value was set by.
+func (p *Parser) mergeFile(dst, src map[string]any, prefix, source string, origins map[string]string) {
+	for k, v := range src {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		old, exists := dst[k]
+		sm, ok := v.(map[string]any)
+		if dm, isMap := old.(map[string]any); ok && isMap {
+			p.mergeFile(dm, sm, path, source, origins)
+			continue
+		}
+		if exists && !reflect.DeepEqual(old, v) {
+			p.warn("%s from %s overrides the value from %s", path, source, origins[path])
+		}
+		origins[path] = source
+		if ok {
+			// Copy maps so that the merged values of later files do not
+			// modify those of earlier files.
+			m := map[string]any{}
+			p.mergeFile(m, sm, path, source, origins)
+			v = m
+		}
+		dst[k] = v
+	}
+}
+
+// mergeValues returns a copy of base overlaid with overlay. Nested maps are
+// merged recursively, any other value in overlay replaces the one in base.
+// Nested maps of base are copied so that later overrides do not modify them.
+func mergeValues(base, overlay map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(overlay))
+	for k, v := range base {
+		if m, ok := v.(map[string]any); ok {
+			v = mergeValues(m, nil)
+		}
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if om, ok := v.(map[string]any); ok {
+			if bm, ok := out[k].(map[string]any); ok {
+				out[k] = mergeValues(bm, om)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -15,10 +15,13 @@
 package helm
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/install"
 )
 
 func TestParse(t *testing.T) {
@@ -73,9 +76,56 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulDefaults": {
+			reason: "Base values should be merged on top of defaults and overrides should take precedence over both.",
+			parser: &Parser{
+				defaults: map[string]any{
+					"replicas": 1,
+					"resources": map[string]any{
+						"cpu":    "100m",
+						"memory": "128Mi",
+					},
+					"other": "default",
+				},
+				values: map[string]any{
+					"resources": map[string]any{
+						"memory": "256Mi",
+					},
+				},
+				overrides: map[string]string{
+					"replicas": "3",
+				},
+			},
+			params: map[string]any{
+				"replicas": int64(3),
+				"resources": map[string]any{
+					"cpu":    "100m",
+					"memory": "256Mi",
+				},
+				"other": "default",
+			},
+		},
+		"SuccessfulLiteralOverrides": {
+			reason: "Literal overrides should be set verbatim and take precedence over overrides.",
+			parser: &Parser{
+				values: map[string]any{},
+				overrides: map[string]string{
+					"license.key": "fromSet",
+				},
+				literals: map[string]string{
+					"license.key": `{"a":"b,c"}`,
+				},
+			},
+			params: map[string]any{
+				"license": map[string]any{
+					"key": `{"a":"b,c"}`,
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
+			tc.parser.warn = func(string, ...any) {}
 			p, err := tc.parser.Parse()
 			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -86,3 +136,38 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseValuesFiles(t *testing.T) {
+	var warnings []string
+	p := NewParser(map[string]any{"base": true}, map[string]string{"replicas": "5"},
+		WithDefaults(map[string]any{"replicas": 1, "image": map[string]any{"tag": "v1"}}),
+		WithValuesFiles(
+			install.ValuesFile{Source: "base.yaml", Values: map[string]any{
+				"replicas": 2,
+				"image":    map[string]any{"repository": "spaces", "tag": "v2"},
+			}},
+			install.ValuesFile{Source: "prod.yaml", Values: map[string]any{
+				"image": map[string]any{"tag": "v3"},
+			}},
+		),
+		WithWarnFn(func(format string, args ...any) {
+			warnings = append(warnings, fmt.Sprintf(format, args...))
+		}),
+	)
+	got, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(...): %v", err)
+	}
+	want := map[string]any{
+		"base":     true,
+		"replicas": int64(5),
+		"image":    map[string]any{"repository": "spaces", "tag": "v3"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nLater values files and overrides should take precedence.\nParse(...): -want, +got:\n%s", diff)
+	}
+	wantWarnings := []string{"image.tag from prod.yaml overrides the value from base.yaml"}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("\nValues replaced by a later file should be warned about.\nParse(...): -want warnings, +got warnings:\n%s", diff)
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/up/internal/install"
+)
+
+const (
+	kindCRD = "CustomResourceDefinition"
+
+	// annotationChanges is the chart annotation Artifact Hub uses to list the
+	// changes in a chart version.
+	annotationChanges = "artifacthub.io/changes"
+
+	errGetInstalledRelease = "could not get installed release"
+	errRenderUpgrade       = "could not render upgrade"
+	errParseManifest       = "could not parse release manifest"
+)
+
+// PlanUpgrade computes the changes that upgrading the installed release to
+// the supplied version would apply. Nothing is changed in the cluster.
+func (h *installer) PlanUpgrade(version string, parameters map[string]any) (*install.UpgradePlan, error) { //nolint:gocyclo
+	current, err := h.GetCurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := h.getClient.Run(h.releaseName)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetInstalledRelease)
+	}
+
+	var helmChart *chart.Chart
+	if h.chartFile == nil {
+		helmChart, err = h.pullAndLoad(version)
+	} else {
+		helmChart, err = h.load(h.chartFile.Name())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Removed values are diffed against the parameters as supplied, not the
+	// chart values they are merged over for the mirror.
+	mirrored, err := h.mirrorValues(helmChart, parameters)
+	if err != nil {
+		return nil, err
+	}
+	target, err := h.dryRunClient.Run(h.releaseName, helmChart, mirrored)
+	if err != nil {
+		return nil, errors.Wrap(err, errRenderUpgrade)
+	}
+
+	curObjs, err := releaseObjects(rel)
+	if err != nil {
+		return nil, err
+	}
+	tarObjs, err := releaseObjects(target)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &install.UpgradePlan{
+		CurrentVersion: current,
+		TargetVersion:  version,
+		CRDs:           diffCRDs(filterKind(curObjs, kindCRD), filterKind(tarObjs, kindCRD)),
+		Images:         diffImages(collectImages(curObjs), collectImages(tarObjs)),
+	}
+	if helmChart.Metadata != nil {
+		plan.TargetVersion = helmChart.Metadata.Version
+		plan.Changelog = changelog(helmChart.Metadata.Annotations)
+	}
+	if rel.Chart != nil {
+		plan.Removed = diffValues(rel.Chart.Values, helmChart.Values, rel.Config, parameters)
+	}
+	plan.Parameters = diffParameters(rel.Config, parameters, h.redactKeys...)
+	return plan, nil
+}
+
+// changelog returns the changes listed in the Artifact Hub changes annotation
+// of a chart. The annotation is a YAML list whose entries are either plain
+// descriptions or objects with a kind and a description. An annotation that
+// cannot be parsed is ignored, because the changelog is informational only.
+func changelog(annotations map[string]string) []string {
+	raw, ok := annotations[annotationChanges]
+	if !ok {
+		return nil
+	}
+	entries := []any{}
+	if err := yaml.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		switch t := e.(type) {
+		case string:
+			out = append(out, t)
+		case map[string]any:
+			desc, _ := t["description"].(string)
+			if desc == "" {
+				continue
+			}
+			if kind, _ := t["kind"].(string); kind != "" {
+				desc = kind + ": " + desc
+			}
+			out = append(out, desc)
+		}
+	}
+	return out
+}
+
+// releaseObjects returns the objects rendered for a release, including the
+// CRDs that are shipped in the chart crds directory.
+func releaseObjects(rel *release.Release) ([]map[string]any, error) {
+	if rel == nil {
+		return nil, nil
+	}
+	docs := []string{}
+	if rel.Chart != nil {
+		for _, c := range rel.Chart.CRDObjects() {
+			if c.File != nil {
+				docs = append(docs, string(c.File.Data))
+			}
+		}
+	}
+	docs = append(docs, splitManifests(rel.Manifest)...)
+	objs := make([]map[string]any, 0, len(docs))
+	for _, d := range docs {
+		// CRD files may contain multiple documents.
+		for _, m := range splitManifests(d) {
+			o := map[string]any{}
+			if err := yaml.Unmarshal([]byte(m), &o); err != nil {
+				return nil, errors.Wrap(err, errParseManifest)
+			}
+			if len(o) == 0 {
+				continue
+			}
+			objs = append(objs, o)
+		}
+	}
+	return objs, nil
+}
+
+// splitManifests splits a YAML stream into its documents in the order they
+// appear in the stream.
+func splitManifests(s string) []string {
+	m := releaseutil.SplitManifests(s)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	docs := make([]string, len(keys))
+	for i, k := range keys {
+		docs[i] = m[k]
+	}
+	return docs
+}
+
+func filterKind(objs []map[string]any, kind string) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	for _, o := range objs {
+		if k, _ := o["kind"].(string); k != kind {
+			continue
+		}
+		md, _ := o["metadata"].(map[string]any)
+		name, _ := md["name"].(string)
+		out[name] = o
+	}
+	return out
+}
+
+// diffCRDs compares the supplied CRDs by name and reports the served versions
+// that were added or removed for those that changed.
+func diffCRDs(current, target map[string]map[string]any) []install.CRDChange {
+	changes := []install.CRDChange{}
+	for name, t := range target {
+		c, ok := current[name]
+		if !ok {
+			changes = append(changes, install.CRDChange{Name: name, Change: install.ChangeAdded})
+			continue
+		}
+		if reflect.DeepEqual(c["spec"], t["spec"]) {
+			continue
+		}
+		cv, tv := crdVersions(c), crdVersions(t)
+		changes = append(changes, install.CRDChange{
+			Name:            name,
+			Change:          install.ChangeModified,
+			AddedVersions:   difference(tv, cv),
+			RemovedVersions: difference(cv, tv),
+		})
+	}
+	for name := range current {
+		if _, ok := target[name]; !ok {
+			changes = append(changes, install.CRDChange{Name: name, Change: install.ChangeRemoved})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func crdVersions(crd map[string]any) []string {
+	spec, _ := crd["spec"].(map[string]any)
+	vs, _ := spec["versions"].([]any)
+	out := make([]string, 0, len(vs))
+	for _, v := range vs {
+		m, _ := v.(map[string]any)
+		if n, ok := m["name"].(string); ok {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// difference returns the elements of a that are not in b.
+func difference(a, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		set[s] = struct{}{}
+	}
+	var out []string
+	for _, s := range a {
+		if _, ok := set[s]; !ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// collectImages walks the supplied objects and returns the set of container
+// image references they contain.
+func collectImages(objs []map[string]any) map[string]struct{} {
+	images := map[string]struct{}{}
+	var walk func(v any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, val := range t {
+				if s, ok := val.(string); ok && k == "image" {
+					images[s] = struct{}{}
+					continue
+				}
+				walk(val)
+			}
+		case []any:
+			for _, val := range t {
+				walk(val)
+			}
+		}
+	}
+	for _, o := range objs {
+		walk(o)
+	}
+	return images
+}
+
+// splitImage splits an image reference into its repository and tag or
+// digest.
+func splitImage(image string) (string, string) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+	// A colon before the last slash belongs to a registry port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// diffImages compares the supplied image references by repository. A
+// repository may be referenced with more than one tag, so the tags that are
+// no longer referenced are paired in order with those that are newly
+// referenced, and any left over are reported as added or removed.
+func diffImages(current, target map[string]struct{}) []install.ImageChange {
+	byRepo := func(images map[string]struct{}) map[string][]string {
+		out := map[string][]string{}
+		for img := range images {
+			repo, tag := splitImage(img)
+			out[repo] = append(out[repo], tag)
+		}
+		return out
+	}
+	cur, tar := byRepo(current), byRepo(target)
+	repos := map[string]struct{}{}
+	for r := range cur {
+		repos[r] = struct{}{}
+	}
+	for r := range tar {
+		repos[r] = struct{}{}
+	}
+	changes := []install.ImageChange{}
+	for repo := range repos {
+		from, to := difference(cur[repo], tar[repo]), difference(tar[repo], cur[repo])
+		sort.Strings(from)
+		sort.Strings(to)
+		for i := 0; i < len(from) || i < len(to); i++ {
+			c := install.ImageChange{Repository: repo}
+			if i < len(from) {
+				c.From = from[i]
+			}
+			if i < len(to) {
+				c.To = to[i]
+			}
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Repository != changes[j].Repository {
+			return changes[i].Repository < changes[j].Repository
+		}
+		if changes[i].From != changes[j].From {
+			return changes[i].From < changes[j].From
+		}
+		return changes[i].To < changes[j].To
+	})
+	return changes
+}
+
+// diffValues reports the chart values that are no longer present in the
+// target chart defaults. Values set by the installed release or the supplied
+// parameters are marked as in use.
+func diffValues(current, target map[string]any, set ...map[string]any) []install.ValueChange {
+	tk := map[string]struct{}{}
+	for _, k := range flattenKeys("", target) {
+		tk[k] = struct{}{}
+	}
+	inUse := map[string]struct{}{}
+	for _, s := range set {
+		for _, k := range flattenKeys("", s) {
+			inUse[k] = struct{}{}
+		}
+	}
+	changes := []install.ValueChange{}
+	for _, k := range flattenKeys("", current) {
+		if _, ok := tk[k]; ok {
+			continue
+		}
+		_, used := inUse[k]
+		changes = append(changes, install.ValueChange{Key: k, InUse: used})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// diffParameters reports the parameters that differ between the installed
+// release and the upgrade. Parameters of the installed release that are not
+// supplied are reported as removed, because upgrades do not reuse them. Plans
+// are printed, so the values of sensitive parameters are redacted.
+func diffParameters(current, target map[string]any, redactKeys ...string) []install.ParameterChange {
+	keys := sensitiveKeys(redactKeys)
+	cur, tar := flattenValues("", current), flattenValues("", target)
+	change := func(k string, t install.ChangeType) install.ParameterChange {
+		c := install.ParameterChange{Key: k, Change: t, From: cur[k], To: tar[k]}
+		if sensitivePath(k, keys) {
+			if c.From != "" {
+				c.From = Redacted
+			}
+			if c.To != "" {
+				c.To = Redacted
+			}
+		}
+		return c
+	}
+	changes := []install.ParameterChange{}
+	for k, t := range tar {
+		c, ok := cur[k]
+		switch {
+		case !ok:
+			changes = append(changes, change(k, install.ChangeAdded))
+		case c != t:
+			changes = append(changes, change(k, install.ChangeModified))
+		}
+	}
+	for k := range cur {
+		if _, ok := tar[k]; !ok {
+			changes = append(changes, change(k, install.ChangeRemoved))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// flattenValues returns the leaf values of the supplied map, formatted as
+// strings and keyed by their dot separated paths.
+func flattenValues(prefix string, values map[string]any) map[string]string {
+	flat := map[string]string{}
+	for k, v := range values {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if m, ok := v.(map[string]any); ok && len(m) > 0 {
+			for fk, fv := range flattenValues(p, m) {
+				flat[fk] = fv
+			}
+			continue
+		}
+		flat[p] = fmt.Sprint(v)
+	}
+	return flat
+}
+
+// flattenKeys returns the dot separated paths to all leaf values in the
+// supplied map.
+func flattenKeys(prefix string, values map[string]any) []string {
+	keys := []string{}
+	for k, v := range values {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if m, ok := v.(map[string]any); ok && len(m) > 0 {
+			keys = append(keys, flattenKeys(p, m)...)
+			continue
+		}
+		keys = append(keys, p)
+	}
+	return keys
+}
@@ -0,0 +1,382 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/upbound/up/internal/install"
+)
+
+const (
+	currentManifest = `---
+# Source: spaces/templates/crd.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  versions:
+  - name: v1alpha1
+---
+# Source: spaces/templates/crd-old.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.org
+spec:
+  group: example.org
+---
+# Source: spaces/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: spaces
+spec:
+  template:
+    spec:
+      containers:
+      - name: spaces
+        image: registry.example.org:5000/spaces:v1.0.0
+      - name: sidecar
+        image: sidecar:v1
+`
+	targetManifest = `---
+# Source: spaces/templates/crd.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  versions:
+  - name: v1beta1
+---
+# Source: spaces/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: spaces
+spec:
+  template:
+    spec:
+      containers:
+      - name: spaces
+        image: registry.example.org:5000/spaces:v1.1.0
+      - name: sidecar
+        image: sidecar:v1
+`
+)
+
+func TestPlanUpgrade(t *testing.T) {
+	errBoom := errors.New("boom")
+	chartName := "spaces"
+	installed := &release.Release{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Version: "1.0.0"},
+			Values: map[string]any{
+				"features": map[string]any{"alpha": true},
+				"replicas": 1,
+			},
+		},
+		Config:   map[string]any{"features": map[string]any{"alpha": false}},
+		Manifest: currentManifest,
+	}
+	cases := map[string]struct {
+		reason     string
+		installer  *installer
+		version    string
+		parameters map[string]any
+		want       *install.UpgradePlan
+		err        error
+	}{
+		"ErrorRender": {
+			reason: "If unable to render the upgrade an error should be returned.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient: &mockGetClient{
+					runFn: func(string) (*release.Release, error) {
+						return installed, nil
+					},
+				},
+				pullClient: &mockPullClient{
+					runFn: func(string) (string, error) {
+						return "", nil
+					},
+				},
+				dryRunClient: &mockUpgradeClient{
+					runFn: func(string, *chart.Chart, map[string]any) (*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+				cacheDir: "/",
+				load: func(string) (*chart.Chart, error) {
+					return &chart.Chart{}, nil
+				},
+			},
+			version: "1.1.0",
+			err:     errors.Wrap(errBoom, errRenderUpgrade),
+		},
+		"Successful": {
+			reason: "A plan describing the CRD, image, and value changes should be returned.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient: &mockGetClient{
+					runFn: func(string) (*release.Release, error) {
+						return installed, nil
+					},
+				},
+				pullClient: &mockPullClient{
+					runFn: func(string) (string, error) {
+						return "", nil
+					},
+				},
+				dryRunClient: &mockUpgradeClient{
+					runFn: func(string, *chart.Chart, map[string]any) (*release.Release, error) {
+						return &release.Release{Manifest: targetManifest}, nil
+					},
+				},
+				cacheDir: "/",
+				load: func(string) (*chart.Chart, error) {
+					return &chart.Chart{
+						Metadata: &chart.Metadata{
+							Version: "1.1.0",
+							Annotations: map[string]string{
+								annotationChanges: "- kind: changed\n  description: Renamed features to featureFlags\n",
+							},
+						},
+						Values: map[string]any{
+							"featureFlags": map[string]any{"alpha": true},
+							"replicas":     1,
+						},
+					}, nil
+				},
+			},
+			version: "1.1.0",
+			want: &install.UpgradePlan{
+				CurrentVersion: "1.0.0",
+				TargetVersion:  "1.1.0",
+				Changelog:      []string{"changed: Renamed features to featureFlags"},
+				CRDs: []install.CRDChange{
+					{Name: "gadgets.example.org", Change: install.ChangeRemoved},
+					{Name: "widgets.example.org", Change: install.ChangeModified, AddedVersions: []string{"v1beta1"}, RemovedVersions: []string{"v1alpha1"}},
+				},
+				Images: []install.ImageChange{
+					{Repository: "registry.example.org:5000/spaces", From: "v1.0.0", To: "v1.1.0"},
+				},
+				Removed: []install.ValueChange{
+					{Key: "features.alpha", InUse: true},
+				},
+				Parameters: []install.ParameterChange{
+					{Key: "features.alpha", Change: install.ChangeRemoved, From: "false"},
+				},
+			},
+		},
+		"RedactedParameters": {
+			reason: "Parameter changes should be reported with the values of sensitive parameters redacted.",
+			installer: &installer{
+				chartName:   chartName,
+				releaseName: chartName,
+				getClient: &mockGetClient{
+					runFn: func(string) (*release.Release, error) {
+						return &release.Release{
+							Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+							Config:   map[string]any{"account": "acme", "registry": map[string]any{"password": "hunter2"}},
+							Manifest: currentManifest,
+						}, nil
+					},
+				},
+				pullClient: &mockPullClient{
+					runFn: func(string) (string, error) {
+						return "", nil
+					},
+				},
+				dryRunClient: &mockUpgradeClient{
+					runFn: func(string, *chart.Chart, map[string]any) (*release.Release, error) {
+						return &release.Release{Manifest: currentManifest}, nil
+					},
+				},
+				cacheDir:   "/",
+				redactKeys: []string{"webhook"},
+				load: func(string) (*chart.Chart, error) {
+					return &chart.Chart{Metadata: &chart.Metadata{Version: "1.1.0"}}, nil
+				},
+			},
+			version: "1.1.0",
+			parameters: map[string]any{
+				"account":  "acme-corp",
+				"registry": map[string]any{"password": "hunter3"},
+				"webhook":  "https://hooks.example.org/abc",
+			},
+			want: &install.UpgradePlan{
+				CurrentVersion: "1.0.0",
+				TargetVersion:  "1.1.0",
+				CRDs:           []install.CRDChange{},
+				Images:         []install.ImageChange{},
+				Removed:        []install.ValueChange{},
+				Parameters: []install.ParameterChange{
+					{Key: "account", Change: install.ChangeModified, From: "acme", To: "acme-corp"},
+					{Key: "registry.password", Change: install.ChangeModified, From: Redacted, To: Redacted},
+					{Key: "webhook", Change: install.ChangeAdded, To: Redacted},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.installer.fs = afero.NewMemMapFs()
+			plan, err := tc.installer.PlanUpgrade(tc.version, tc.parameters)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPlanUpgrade(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, plan); diff != "" {
+				t.Errorf("\n%s\nPlanUpgrade(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		image  string
+		repo   string
+		tag    string
+	}{
+		"Tag": {
+			reason: "An image with a tag should be split on the colon.",
+			image:  "xpkg.upbound.io/upbound/spaces:v1.0.0",
+			repo:   "xpkg.upbound.io/upbound/spaces",
+			tag:    "v1.0.0",
+		},
+		"RegistryPort": {
+			reason: "A colon in the registry host should not be treated as a tag.",
+			image:  "localhost:5000/spaces",
+			repo:   "localhost:5000/spaces",
+		},
+		"Digest": {
+			reason: "An image with a digest should be split on the at sign.",
+			image:  "spaces@sha256:abc",
+			repo:   "spaces",
+			tag:    "sha256:abc",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo, tag := splitImage(tc.image)
+			if diff := cmp.Diff(tc.repo, repo); diff != "" {
+				t.Errorf("\n%s\nsplitImage(...): -want repo, +got repo:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.tag, tag); diff != "" {
+				t.Errorf("\n%s\nsplitImage(...): -want tag, +got tag:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffImages(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		current map[string]struct{}
+		target  map[string]struct{}
+		want    []install.ImageChange
+	}{
+		"Unchanged": {
+			reason:  "Images referenced by both versions should not be reported.",
+			current: map[string]struct{}{"spaces:v1": {}},
+			target:  map[string]struct{}{"spaces:v1": {}},
+			want:    []install.ImageChange{},
+		},
+		"MultipleTags": {
+			reason: "Each tag of a repository should be compared, rather than only one tag per repository.",
+			current: map[string]struct{}{
+				"spaces:v1":  {},
+				"spaces:v2":  {},
+				"sidecar:v1": {},
+			},
+			target: map[string]struct{}{
+				"spaces:v1":  {},
+				"spaces:v3":  {},
+				"sidecar:v1": {},
+				"sidecar:v2": {},
+			},
+			want: []install.ImageChange{
+				{Repository: "sidecar", To: "v2"},
+				{Repository: "spaces", From: "v2", To: "v3"},
+			},
+		},
+		"Removed": {
+			reason:  "Images no longer referenced should be reported without a target tag.",
+			current: map[string]struct{}{"spaces:v1": {}, "spaces:v2": {}},
+			target:  map[string]struct{}{"spaces:v2": {}},
+			want: []install.ImageChange{
+				{Repository: "spaces", From: "v1"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := diffImages(tc.current, tc.target)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndiffImages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        []string
+	}{
+		"NoAnnotation": {
+			reason: "A chart without the changes annotation should have no changelog.",
+		},
+		"Strings": {
+			reason:      "Plain entries should be returned as they are.",
+			annotations: map[string]string{annotationChanges: "- Added a thing\n- Fixed a thing\n"},
+			want:        []string{"Added a thing", "Fixed a thing"},
+		},
+		"Objects": {
+			reason:      "Structured entries should be prefixed with their kind.",
+			annotations: map[string]string{annotationChanges: "- kind: added\n  description: A thing\n- description: Another thing\n"},
+			want:        []string{"added: A thing", "Another thing"},
+		},
+		"Invalid": {
+			reason:      "An annotation that cannot be parsed should be ignored.",
+			annotations: map[string]string{annotationChanges: "not: a list"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := changelog(tc.annotations)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nchangelog(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
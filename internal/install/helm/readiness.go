@@ -0,0 +1,218 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/upbound/up/internal/install"
+)
+
+const (
+	readinessInterval = 2 * time.Second
+
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+	kindDaemonSet   = "DaemonSet"
+
+	errGetRelease     = "could not get release to wait for"
+	errFmtNotReady    = "timed out waiting for components to become ready: %s"
+	errFmtGetWorkload = "could not get %s"
+)
+
+// WaitForReadiness waits for operations to complete like Wait, but rather than
+// blocking silently it watches the workloads installed by the release and
+// reports their readiness to the supplied function whenever it changes.
+func WaitForReadiness(report func([]install.ComponentStatus)) InstallerModifierFn {
+	return func(h *installer) {
+		h.wait = true
+		h.report = report
+	}
+}
+
+// component is a workload installed by a release.
+type component struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// releaseComponents returns the workloads installed by a release. Workloads
+// without a namespace are installed in the supplied namespace.
+func releaseComponents(rel *release.Release, namespace string) ([]component, error) {
+	objs, err := releaseObjects(rel)
+	if err != nil {
+		return nil, err
+	}
+	comps := []component{}
+	for _, o := range objs {
+		kind, _ := o["kind"].(string)
+		if kind != kindDeployment && kind != kindStatefulSet && kind != kindDaemonSet {
+			continue
+		}
+		meta, _ := o["metadata"].(map[string]any)
+		name, _ := meta["name"].(string)
+		ns, _ := meta["namespace"].(string)
+		if ns == "" {
+			ns = namespace
+		}
+		comps = append(comps, component{kind: kind, namespace: ns, name: name})
+	}
+	return comps, nil
+}
+
+// waitForReadiness blocks until the workloads of the release are ready,
+// reporting their readiness as it changes. It is a no-op unless the installer
+// was configured with WaitForReadiness.
+func (h *installer) waitForReadiness() error {
+	if h.report == nil {
+		return nil
+	}
+	rel, err := h.getClient.Run(h.releaseName)
+	if err != nil {
+		return errors.Wrap(err, errGetRelease)
+	}
+	comps, err := releaseComponents(rel, h.namespace)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	t := time.NewTicker(readinessInterval)
+	defer t.Stop()
+
+	var last []install.ComponentStatus
+	for {
+		ss := componentStatuses(ctx, h.kube, comps)
+		if !reflect.DeepEqual(ss, last) {
+			for _, s := range ss {
+				h.log.Debug("Component readiness", "kind", s.Kind, "namespace", s.Namespace, "name", s.Name, "ready", s.Ready, "message", s.Message)
+			}
+			h.report(ss)
+			last = ss
+		}
+		notReady := []string{}
+		for _, s := range ss {
+			if !s.Ready {
+				notReady = append(notReady, fmt.Sprintf("%s/%s", strings.ToLower(s.Kind), s.Name))
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Errorf(errFmtNotReady, strings.Join(notReady, ", "))
+		case <-t.C:
+		}
+	}
+}
+
+// componentStatuses returns the readiness of the supplied components.
+func componentStatuses(ctx context.Context, kube kubernetes.Interface, comps []component) []install.ComponentStatus {
+	ss := make([]install.ComponentStatus, len(comps))
+	for i, c := range comps {
+		ss[i] = install.ComponentStatus{Kind: c.kind, Namespace: c.namespace, Name: c.name}
+		ready, msg, sel, err := workloadReadiness(ctx, kube, c)
+		if err != nil {
+			ss[i].Message = errors.Wrapf(err, errFmtGetWorkload, strings.ToLower(c.kind)).Error()
+			continue
+		}
+		ss[i].Ready = ready
+		ss[i].Message = msg
+		if ready {
+			continue
+		}
+		if p := podProblem(ctx, kube, c.namespace, sel); p != "" {
+			ss[i].Message = fmt.Sprintf("%s (%s)", msg, p)
+		}
+	}
+	return ss
+}
+
+// workloadReadiness returns whether the supplied workload is ready, a
+// description of its progress, and the selector of its pods.
+func workloadReadiness(ctx context.Context, kube kubernetes.Interface, c component) (bool, string, *metav1.LabelSelector, error) {
+	apps := kube.AppsV1()
+	switch c.kind {
+	case kindDeployment:
+		d, err := apps.Deployments(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", nil, err
+		}
+		want := replicas(d.Spec.Replicas)
+		ready := d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedReplicas == want && d.Status.AvailableReplicas == want
+		return ready, fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, want), d.Spec.Selector, nil
+	case kindStatefulSet:
+		s, err := apps.StatefulSets(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", nil, err
+		}
+		want := replicas(s.Spec.Replicas)
+		ready := s.Status.ObservedGeneration >= s.Generation && s.Status.UpdatedReplicas == want && s.Status.ReadyReplicas == want
+		return ready, fmt.Sprintf("%d/%d replicas ready", s.Status.ReadyReplicas, want), s.Spec.Selector, nil
+	default:
+		d, err := apps.DaemonSets(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", nil, err
+		}
+		want := d.Status.DesiredNumberScheduled
+		ready := d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedNumberScheduled == want && d.Status.NumberReady == want
+		return ready, fmt.Sprintf("%d/%d pods ready", d.Status.NumberReady, want), d.Spec.Selector, nil
+	}
+}
+
+// podProblem returns a description of the first problem found with a pod
+// matching the supplied selector, such as a container that cannot pull its
+// image, or an empty string if none is found.
+func podProblem(ctx context.Context, kube kubernetes.Interface, namespace string, sel *metav1.LabelSelector) string {
+	if sel == nil {
+		return ""
+	}
+	pods, err := kube.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(sel)})
+	if err != nil {
+		return ""
+	}
+	for _, p := range pods.Items {
+		for _, cs := range append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...) {
+			if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "ContainerCreating" && w.Reason != "PodInitializing" {
+				return fmt.Sprintf("pod %s: %s", p.Name, w.Reason)
+			}
+		}
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason != "" {
+				return fmt.Sprintf("pod %s: %s", p.Name, c.Reason)
+			}
+		}
+	}
+	return ""
+}
+
+func replicas(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+	return *r
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/upbound/up/internal/install"
+)
+
+const testManifest = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: store
+  namespace: data
+`
+
+func TestReleaseComponents(t *testing.T) {
+	got, err := releaseComponents(&release.Release{Manifest: testManifest}, "upbound-system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []component{
+		{kind: kindDeployment, namespace: "upbound-system", name: "controller"},
+		{kind: kindStatefulSet, namespace: "data", name: "store"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(component{})); diff != "" {
+		t.Errorf("releaseComponents(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestComponentStatuses(t *testing.T) {
+	one := int32(1)
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "controller"}}
+	kube := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "upbound-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one, Selector: sel},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "controller-abc", Namespace: "upbound-system", Labels: sel.MatchLabels},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "controller",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}}},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "data"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &one},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1, UpdatedReplicas: 1},
+		},
+	)
+	got := componentStatuses(context.Background(), kube, []component{
+		{kind: kindDeployment, namespace: "upbound-system", name: "controller"},
+		{kind: kindStatefulSet, namespace: "data", name: "store"},
+		{kind: kindDaemonSet, namespace: "upbound-system", name: "agent"},
+	})
+	want := []install.ComponentStatus{
+		{Kind: kindDeployment, Namespace: "upbound-system", Name: "controller", Message: "0/1 replicas available (pod controller-abc: ImagePullBackOff)"},
+		{Kind: kindStatefulSet, Namespace: "data", Name: "store", Ready: true, Message: "1/1 replicas ready"},
+		{Kind: kindDaemonSet, Namespace: "upbound-system", Name: "agent", Message: `could not get daemonset: daemonsets.apps "agent" not found`},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("componentStatuses(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	one := int32(1)
+	reports := 0
+	h := &installer{
+		releaseName: "spaces",
+		namespace:   "upbound-system",
+		log:         logging.NewNopLogger(),
+		getClient: &mockGetClient{
+			runFn: func(string) (*release.Release, error) {
+				return &release.Release{Manifest: testManifest}, nil
+			},
+		},
+		kube: fake.NewSimpleClientset(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "upbound-system"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &one},
+				Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "data"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &one},
+				Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1, UpdatedReplicas: 1},
+			},
+		),
+		report: func([]install.ComponentStatus) { reports++ },
+	}
+	if err := h.waitForReadiness(); err != nil {
+		t.Fatal(err)
+	}
+	if reports != 1 {
+		t.Errorf("waitForReadiness(): want 1 report, got %d", reports)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"strings"
+)
+
+// Redacted replaces the values of sensitive keys.
+const Redacted = "<redacted>"
+
+// DefaultSensitiveKeys are the keys whose values are always redacted. A key is
+// sensitive if its name contains one of them, ignoring case, e.g. apiToken or
+// privateKey.
+var DefaultSensitiveKeys = []string{"token", "password", "key"}
+
+// WithRedactedKeys adds keys whose values are redacted when parameters are
+// logged or reported, in addition to DefaultSensitiveKeys.
+func WithRedactedKeys(keys ...string) InstallerModifierFn {
+	return func(h *installer) {
+		h.redactKeys = append(h.redactKeys, keys...)
+	}
+}
+
+// Redact returns a copy of the values in which the values of sensitive keys
+// are replaced, so that they can be printed. The values of keys that match the
+// supplied keys or DefaultSensitiveKeys are redacted, including all values
+// nested below them.
+func Redact(values map[string]any, keys ...string) map[string]any {
+	return redactMap(values, sensitiveKeys(keys))
+}
+
+// sensitiveKeys returns the lower case supplied keys and DefaultSensitiveKeys.
+func sensitiveKeys(keys []string) []string {
+	all := make([]string, 0, len(DefaultSensitiveKeys)+len(keys))
+	for _, k := range append(append([]string{}, DefaultSensitiveKeys...), keys...) {
+		all = append(all, strings.ToLower(k))
+	}
+	return all
+}
+
+func redactMap(values map[string]any, keys []string) map[string]any {
+	if values == nil {
+		return nil
+	}
+	out := make(map[string]any, len(values))
+	for k, v := range values {
+		if sensitive(k, keys) {
+			out[k] = Redacted
+			continue
+		}
+		out[k] = redactValue(v, keys)
+	}
+	return out
+}
+
+func redactValue(v any, keys []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return redactMap(t, keys)
+	case []any:
+		out := make([]any, len(t))
+		for i := range t {
+			out[i] = redactValue(t[i], keys)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// sensitivePath indicates whether any key of the dot separated path contains
+// one of the lower case keys.
+func sensitivePath(path string, keys []string) bool {
+	for _, name := range strings.Split(path, ".") {
+		if sensitive(name, keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitive indicates whether the name contains one of the lower case keys.
+func sensitive(name string, keys []string) bool {
+	name = strings.ToLower(name)
+	for _, k := range keys {
+		if k != "" && strings.Contains(name, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedact(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		values map[string]any
+		keys   []string
+		want   map[string]any
+	}{
+		"Nil": {
+			reason: "Redacting no values should return no values.",
+		},
+		"DefaultKeys": {
+			reason: "Values of keys containing a default sensitive key should be redacted, ignoring case.",
+			values: map[string]any{
+				"account": "acme",
+				"registry": map[string]any{
+					"password": "hunter2",
+					"apiToken": "abc",
+				},
+				"tls": map[string]any{
+					"privateKey": map[string]any{"data": "-----BEGIN"},
+				},
+			},
+			want: map[string]any{
+				"account": "acme",
+				"registry": map[string]any{
+					"password": Redacted,
+					"apiToken": Redacted,
+				},
+				"tls": map[string]any{
+					"privateKey": Redacted,
+				},
+			},
+		},
+		"AdditionalKeys": {
+			reason: "Values of additional keys should be redacted, including in lists.",
+			values: map[string]any{
+				"webhooks": []any{
+					map[string]any{"url": "https://example.org", "clientSecret": "s3cr3t"},
+				},
+			},
+			keys: []string{"Secret"},
+			want: map[string]any{
+				"webhooks": []any{
+					map[string]any{"url": "https://example.org", "clientSecret": Redacted},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Redact(tc.values, tc.keys...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRedact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
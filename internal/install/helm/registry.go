@@ -15,6 +15,7 @@
 package helm
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -24,6 +25,8 @@ import (
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/spf13/afero"
+	"golang.org/x/sync/errgroup"
+	"helm.sh/helm/v3/pkg/downloader"
 )
 
 const (
@@ -34,6 +37,12 @@ const (
 	// HelmChartContentLayerMediaType is the reserved media type for Helm chart
 	// package content.
 	HelmChartContentLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// HelmChartProvenanceLayerMediaType is the reserved media type for Helm
+	// chart provenance files.
+	HelmChartProvenanceLayerMediaType = "application/vnd.cncf.helm.chart.provenance.v1.prov"
+
+	provenanceExt = ".prov"
 )
 
 const (
@@ -43,6 +52,8 @@ const (
 	errNotSingleLayer    = "OCI image does not have a single layer"
 	errLayerMediaTypeFmt = "OCI image layer has media type %s and %s is required"
 	errReadCompressed    = "failed to read compressed chart contents"
+	errNoProvenance      = "OCI image does not have a provenance layer, the chart cannot be verified"
+	errVerifyChart       = "failed to verify chart provenance"
 )
 
 type fetchFn func(ref name.Reference, options ...remote.Option) (v1.Image, error)
@@ -52,10 +63,14 @@ var _ helmPuller = &registryPuller{}
 type registryPuller struct {
 	fs                afero.Fs
 	fetch             fetchFn
+	fetchBlob         blobFetchFn
 	acceptedMediaType string
 
 	cacheDir   string
+	blobDir    string
 	version    string
+	digest     string
+	keyring    string
 	repoURL    *url.URL
 	remoteOpts []remote.Option
 }
@@ -74,6 +89,30 @@ func withRepoURL(u *url.URL) registryPullerOpt {
 	}
 }
 
+// withDigest pins the chart to the OCI manifest with the supplied digest.
+func withDigest(d string) registryPullerOpt {
+	return func(r *registryPuller) {
+		r.digest = d
+	}
+}
+
+// withKeyring verifies the provenance of the chart against the public keys in
+// the supplied keyring.
+func withKeyring(k string) registryPullerOpt {
+	return func(r *registryPuller) {
+		r.keyring = k
+	}
+}
+
+// withBlobCache caches the contents of pulled charts by digest in the supplied
+// directory, fetching them with the supplied function.
+func withBlobCache(dir string, fn blobFetchFn) registryPullerOpt {
+	return func(r *registryPuller) {
+		r.blobDir = dir
+		r.fetchBlob = fn
+	}
+}
+
 func newRegistryPuller(opts ...registryPullerOpt) *registryPuller {
 	r := &registryPuller{
 		fs:                afero.NewOsFs(),
@@ -86,8 +125,14 @@ func newRegistryPuller(opts ...registryPullerOpt) *registryPuller {
 	return r
 }
 
-func (p *registryPuller) Run(chartName string) (string, error) {
-	ref, err := name.ParseReference(fmt.Sprintf("%s/%s:%s", p.repoURL.String(), chartName, p.version))
+func (p *registryPuller) Run(chartName string) (string, error) { //nolint:gocyclo
+	r := fmt.Sprintf("%s/%s:%s", p.repoURL.String(), chartName, p.version)
+	if p.digest != "" {
+		// The manifest is fetched by digest, which the registry client
+		// verifies against its contents.
+		r += "@" + p.digest
+	}
+	ref, err := name.ParseReference(r)
 	if err != nil {
 		return "", errors.Wrap(err, errImageReference)
 	}
@@ -99,10 +144,24 @@ func (p *registryPuller) Run(chartName string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, errGetImageLayers)
 	}
-	if len(ls) != 1 {
+	// Signed charts carry their provenance file in a second layer.
+	var prov v1.Layer
+	content := make([]v1.Layer, 0, len(ls))
+	for _, l := range ls {
+		mt, err := l.MediaType()
+		if err != nil {
+			return "", err
+		}
+		if string(mt) == HelmChartProvenanceLayerMediaType {
+			prov = l
+			continue
+		}
+		content = append(content, l)
+	}
+	if len(content) != 1 {
 		return "", errors.New(errNotSingleLayer)
 	}
-	chart := ls[0]
+	chart := content[0]
 	mt, err := chart.MediaType()
 	if err != nil {
 		return "", err
@@ -110,20 +169,47 @@ func (p *registryPuller) Run(chartName string) (string, error) {
 	if string(mt) != p.acceptedMediaType {
 		return "", errors.Errorf(errLayerMediaTypeFmt, string(mt), p.acceptedMediaType)
 	}
-	read, err := chart.Compressed()
+	fileName := filepath.Join(p.cacheDir, fmt.Sprintf("%s-%s.tgz", chartName, p.version))
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error {
+		return p.download(ctx, ref.Context(), chart, fileName)
+	})
+	if prov != nil {
+		g.Go(func() error {
+			return p.download(ctx, ref.Context(), prov, fileName+provenanceExt)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+	if p.keyring == "" {
+		// TODO(hasheddan): the native helm pull client will build up a
+		// string containing information about operations that took place
+		// while acquiring the chart. We currently do not use that
+		// information in the Helm manager, so we return an empty string in
+		// all cases in the registry puller. We should evaluate in the future
+		// if exposing this information is relevant to users.
+		return "", nil
+	}
+	if prov == nil {
+		return "", errors.New(errNoProvenance)
+	}
+	if _, err := downloader.VerifyChart(fileName, p.keyring); err != nil {
+		// An unverified chart must not be picked up from the cache.
+		_ = p.fs.Remove(fileName)
+		return "", errors.Wrap(err, errVerifyChart)
+	}
+	return "", nil
+}
+
+// write writes the compressed contents of the layer to the file.
+func (p *registryPuller) write(l v1.Layer, fileName string) error {
+	read, err := l.Compressed()
 	if err != nil {
-		return "", errors.Wrap(err, errReadCompressed)
+		return errors.Wrap(err, errReadCompressed)
 	}
 	defer read.Close() // nolint:gosec,errcheck
-	fileName := filepath.Join(p.cacheDir, fmt.Sprintf("%s-%s.tgz", chartName, p.version))
-
-	// TODO(hasheddan): the native helm pull client will build up a string
-	// containing information about operations that took place while acquiring
-	// the chart. We currently do not use that information in the Helm manager,
-	// so we return an empty string in all cases in the registry puller. We
-	// should evaluate in the future if exposing this information is relevant to
-	// users.
-	return "", afero.WriteReader(p.fs, fileName, read)
+	return afero.WriteReader(p.fs, fileName, read)
 }
 
 func (p *registryPuller) SetDestDir(dir string) {
@@ -23,8 +23,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/spf13/afero"
 )
@@ -42,6 +44,9 @@ func TestRegistryPullerRun(t *testing.T) {
 	u, _ := url.Parse("registry.upbound.io/enterprise")
 	img, _ := random.Image(1, 1)
 	badImg, _ := random.Image(1, 2)
+	signedImg, _ := mutate.Append(img, mutate.Addendum{
+		Layer: static.NewLayer([]byte("signature"), HelmChartProvenanceLayerMediaType),
+	})
 	cases := map[string]struct {
 		reason    string
 		puller    *registryPuller
@@ -57,6 +62,16 @@ func TestRegistryPullerRun(t *testing.T) {
 			chartName: "~?",
 			err:       errors.Wrap(errors.New("could not parse reference: registry.upbound.io/enterprise/~?:1.0.0"), errImageReference),
 		},
+		"ErrorParseDigest": {
+			reason: "If the pinned digest is not valid we should return an error.",
+			puller: &registryPuller{
+				version: version,
+				digest:  "sha256:boom",
+				repoURL: u,
+			},
+			chartName: chart,
+			err:       errors.Wrap(errors.New("could not parse reference: registry.upbound.io/enterprise/enterprise:1.0.0@sha256:boom"), errImageReference),
+		},
 		"ErrorFetch": {
 			reason: "If we fail to fetch image we should return an error.",
 			puller: &registryPuller{
@@ -88,6 +103,30 @@ func TestRegistryPullerRun(t *testing.T) {
 			chartName: chart,
 			err:       errors.Errorf(errLayerMediaTypeFmt, string(types.DockerLayer), "obscurity"),
 		},
+		"ErrorNoProvenance": {
+			reason: "If the chart has to be verified but the image has no provenance layer we should return an error.",
+			puller: &registryPuller{
+				fs:                afero.NewMemMapFs(),
+				fetch:             newFetchFn(img, nil),
+				acceptedMediaType: string(types.DockerLayer),
+				version:           version,
+				keyring:           "pubring.gpg",
+				repoURL:           u,
+			},
+			chartName: chart,
+			err:       errors.New(errNoProvenance),
+		},
+		"SuccessfulProvenanceLayer": {
+			reason: "If image has a provenance layer alongside the chart layer no error should be returned.",
+			puller: &registryPuller{
+				fs:                afero.NewMemMapFs(),
+				fetch:             newFetchFn(signedImg, nil),
+				acceptedMediaType: string(types.DockerLayer),
+				version:           version,
+				repoURL:           u,
+			},
+			chartName: chart,
+		},
 		"Successful": {
 			reason: "If image is able to be fetched, content is a valid media type, and we successfully write to filesystem no error should be returned.",
 			puller: &registryPuller{
@@ -0,0 +1,48 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// DeployedVersion returns the chart version of the deployed release with the
+// supplied name. Release storage is read directly, so unlike a Manager no
+// chart repository or cache is needed. driver.ErrReleaseNotFound is returned
+// if no such release is deployed.
+func DeployedVersion(secrets corev1.SecretInterface, name string) (string, error) {
+	rels, err := driver.NewSecrets(secrets).Query(map[string]string{
+		"name":   name,
+		"owner":  "helm",
+		"status": "deployed",
+	})
+	if err != nil {
+		return "", err
+	}
+	// Only one revision of a release is deployed at a time, but the latest
+	// is preferred should storage be inconsistent.
+	latest := rels[0]
+	for _, r := range rels[1:] {
+		if r.Version > latest.Version {
+			latest = r
+		}
+	}
+	if latest.Chart == nil || latest.Chart.Metadata == nil {
+		return "", errors.New(errVerifyInstalledVersion)
+	}
+	return latest.Chart.Metadata.Version, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const (
+	errRenderChart = "could not render chart"
+)
+
+// Render renders the manifests that installing the supplied version would
+// apply, including CRDs and hooks, without contacting the cluster.
+func (h *installer) Render(version string, parameters map[string]any) ([]byte, error) {
+	var helmChart *chart.Chart
+	var err error
+	if h.chartFile == nil {
+		helmChart, err = h.pullAndLoad(version)
+	} else {
+		helmChart, err = h.load(h.chartFile.Name())
+	}
+	if err != nil {
+		return nil, err
+	}
+	parameters, err = h.mirrorValues(helmChart, parameters)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := h.renderClient.Run(helmChart, parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, errRenderChart)
+	}
+	var b strings.Builder
+	b.WriteString(rel.Manifest)
+	// Hooks are not part of the release manifest, but are applied by helm on
+	// install, so they are rendered the same way as helm template does.
+	for _, hk := range rel.Hooks {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", hk.Path, hk.Manifest)
+	}
+	return []byte(b.String()), nil
+}
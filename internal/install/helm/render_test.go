@@ -0,0 +1,99 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestRender(t *testing.T) {
+	errBoom := errors.New("boom")
+	fsSetup := func() afero.Fs {
+		fs := afero.NewMemMapFs()
+		f, _ := fs.Create("test-real-version.tgz")
+		_ = f.Close()
+		return fs
+	}
+	load := func(string) (*chart.Chart, error) {
+		return nil, nil
+	}
+	pull := &mockPullClient{
+		runFn: func(string) (string, error) {
+			return "", nil
+		},
+	}
+	cases := map[string]struct {
+		reason    string
+		installer *installer
+		want      string
+		err       error
+	}{
+		"ErrorRender": {
+			reason: "If unable to render the chart an error should be returned.",
+			installer: &installer{
+				pullClient: pull,
+				renderClient: &mockInstallClient{
+					runFn: func(*chart.Chart, map[string]any) (*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+				cacheDir:  "/",
+				chartName: "test",
+				load:      load,
+			},
+			err: errors.Wrap(errBoom, errRenderChart),
+		},
+		"Successful": {
+			reason: "Rendered manifests should include hooks.",
+			installer: &installer{
+				pullClient: pull,
+				renderClient: &mockInstallClient{
+					runFn: func(*chart.Chart, map[string]any) (*release.Release, error) {
+						return &release.Release{
+							Manifest: "---\n# Source: test/templates/deployment.yaml\nkind: Deployment\n",
+							Hooks: []*release.Hook{{
+								Path:     "test/templates/job.yaml",
+								Manifest: "kind: Job",
+							}},
+						}, nil
+					},
+				},
+				cacheDir:  "/",
+				chartName: "test",
+				load:      load,
+			},
+			want: "---\n# Source: test/templates/deployment.yaml\nkind: Deployment\n---\n# Source: test/templates/job.yaml\nkind: Job\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.installer.fs = fsSetup()
+			b, err := tc.installer.Render("real-version", nil)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, string(b)); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -14,15 +14,124 @@
 
 package install
 
+import "time"
+
 // Manager can install and manage Upbound software in a Kubernetes cluster.
 // TODO(hasheddan): support custom error types, such as AlreadyExists.
 type Manager interface {
 	GetCurrentVersion() (string, error)
 	Install(version string, parameters map[string]any) error
 	Upgrade(version string, parameters map[string]any) error
+	PlanUpgrade(version string, parameters map[string]any) (*UpgradePlan, error)
+	Render(version string, parameters map[string]any) ([]byte, error)
+	History() ([]Revision, error)
+	Rollback(revision int) error
 	Uninstall() error
 }
 
+// ValuesFile is a parameters file in the format of a Helm values file.
+type ValuesFile struct {
+	// Source is the path or URL the file was read from.
+	Source string
+	Values map[string]any
+}
+
+// Revision is a single entry in the release history of an installation.
+type Revision struct {
+	Revision    int       `json:"revision"`
+	Version     string    `json:"version"`
+	Status      string    `json:"status"`
+	Updated     time.Time `json:"updated"`
+	Description string    `json:"description"`
+}
+
+// ComponentStatus is the readiness of a workload installed by a release.
+type ComponentStatus struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Ready     bool
+	// Message describes the progress of the component, and why it is not
+	// ready if it is not.
+	Message string
+}
+
+// ChangeType describes how a resource differs between two versions.
+type ChangeType string
+
+// Change types.
+const (
+	ChangeAdded    ChangeType = "Added"
+	ChangeRemoved  ChangeType = "Removed"
+	ChangeModified ChangeType = "Modified"
+)
+
+// UpgradePlan describes the changes that upgrading to a target version would
+// apply, without applying them.
+type UpgradePlan struct {
+	CurrentVersion string
+	TargetVersion  string
+
+	// Changelog lists the changes the target chart version declares, if any.
+	Changelog []string
+
+	CRDs       []CRDChange
+	Images     []ImageChange
+	Removed    []ValueChange
+	Parameters []ParameterChange
+}
+
+// CRDChange describes a CustomResourceDefinition that differs between the
+// current and target version.
+type CRDChange struct {
+	Name            string
+	Change          ChangeType
+	AddedVersions   []string
+	RemovedVersions []string
+}
+
+// ImageChange describes an image whose tag differs between the current and
+// target version. From is empty for images that are only referenced by the
+// target version, and To is empty for images that are no longer referenced.
+type ImageChange struct {
+	Repository string
+	From       string
+	To         string
+}
+
+// ValueChange describes a chart value that is no longer present in the
+// target version. InUse indicates that the value is currently set, meaning
+// the upgrade may silently drop the configuration it provides.
+type ValueChange struct {
+	Key   string
+	InUse bool
+}
+
+// ParameterChange describes a parameter that differs between the installed
+// release and the upgrade. Values of sensitive parameters are redacted.
+type ParameterChange struct {
+	Key    string
+	Change ChangeType
+	From   string
+	To     string
+}
+
+// Breaking indicates whether the plan contains changes that are likely to
+// require user intervention.
+func (p *UpgradePlan) Breaking() bool {
+	for _, v := range p.Removed {
+		if v.InUse {
+			return true
+		}
+	}
+	for _, c := range p.CRDs {
+		if c.Change == ChangeRemoved || len(c.RemovedVersions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // ParameterParser parses install and upgrade parameters.
 type ParameterParser interface {
 	Parse() (map[string]any, error)
@@ -0,0 +1,148 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	errReadMirrorMap  = "unable to read image mirror mapping file"
+	errParseMirrorMap = "unable to parse image mirror mapping file"
+)
+
+// MirrorParams are parameters for installing from a mirror registry.
+type MirrorParams struct {
+	ImageMirror    string `placeholder:"REGISTRY[/PATH]" help:"Registry to pull every image referenced by the chart values from instead of its original registry, keeping the repository path."`
+	ImageMirrorMap string `type:"existingfile" placeholder:"FILE" help:"YAML file mapping image prefixes to mirror prefixes. Takes precedence over --image-mirror; map a prefix to itself to leave it unchanged."`
+}
+
+// Mirror returns the image mirror described by the parameters, or nil if no
+// mirror is configured.
+func (p *MirrorParams) Mirror() (*ImageMirror, error) {
+	return p.mirror(afero.NewOsFs())
+}
+
+func (p *MirrorParams) mirror(fs afero.Fs) (*ImageMirror, error) {
+	if p.ImageMirror == "" && p.ImageMirrorMap == "" {
+		return nil, nil
+	}
+	m := &ImageMirror{Registry: strings.TrimSuffix(p.ImageMirror, "/")}
+	if p.ImageMirrorMap == "" {
+		return m, nil
+	}
+	b, err := afero.ReadFile(fs, p.ImageMirrorMap)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadMirrorMap)
+	}
+	if err := yaml.Unmarshal(b, &m.Mappings); err != nil {
+		return nil, errors.Wrap(err, errParseMirrorMap)
+	}
+	return m, nil
+}
+
+// ImageMirror rewrites image references to a mirror registry.
+type ImageMirror struct {
+	// Registry replaces the registry of every image reference that no
+	// mapping matches. References are left unchanged if it is empty.
+	Registry string
+
+	// Mappings map prefixes of image references, such as
+	// xpkg.upbound.io/spaces-artifacts, to the prefixes that replace them.
+	// The longest matching prefix is used.
+	Mappings map[string]string
+}
+
+// Rewrite returns the image reference rewritten to the mirror. References
+// without an explicit registry are returned unchanged.
+func (m *ImageMirror) Rewrite(ref string) string {
+	host, _, ok := strings.Cut(ref, "/")
+	if !ok || !isRegistryHost(host) {
+		return ref
+	}
+	return m.rewrite(ref, host)
+}
+
+// RewriteRegistry returns the registry, optionally followed by a path,
+// rewritten to the mirror.
+func (m *ImageMirror) RewriteRegistry(registry string) string {
+	host, _, _ := strings.Cut(registry, "/")
+	if !isRegistryHost(host) {
+		return registry
+	}
+	return m.rewrite(registry, host)
+}
+
+func (m *ImageMirror) rewrite(ref, host string) string {
+	best := ""
+	for from := range m.Mappings {
+		if len(from) > len(best) && hasImagePrefix(ref, from) {
+			best = from
+		}
+	}
+	if best != "" {
+		return strings.TrimSuffix(m.Mappings[best], "/") + ref[len(best):]
+	}
+	if m.Registry == "" {
+		return ref
+	}
+	return m.Registry + ref[len(host):]
+}
+
+// RewriteValues rewrites the image references in the supplied chart values in
+// place. Strings are rewritten if their key is image or repository, which
+// hold full references, or registry, which holds a registry.
+func (m *ImageMirror) RewriteValues(values map[string]any) {
+	for k, v := range values {
+		switch t := v.(type) {
+		case string:
+			switch k {
+			case "image", "repository":
+				values[k] = m.Rewrite(t)
+			case "registry":
+				values[k] = m.RewriteRegistry(t)
+			}
+		case map[string]any:
+			m.RewriteValues(t)
+		case []any:
+			for _, e := range t {
+				if em, ok := e.(map[string]any); ok {
+					m.RewriteValues(em)
+				}
+			}
+		}
+	}
+}
+
+// hasImagePrefix returns true if the reference is the prefix or is nested
+// below it, rather than merely sharing its leading characters.
+func hasImagePrefix(ref, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(ref, prefix) {
+		return false
+	}
+	rest := ref[len(prefix):]
+	return rest == "" || strings.ContainsAny(rest[:1], "/:@")
+}
+
+// isRegistryHost returns true if the first component of an image reference
+// is a registry host, as opposed to a Docker Hub namespace.
+func isRegistryHost(s string) bool {
+	return s == "localhost" || strings.ContainsAny(s, ".:")
+}
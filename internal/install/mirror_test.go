@@ -0,0 +1,165 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestImageMirrorRewrite(t *testing.T) {
+	m := &ImageMirror{
+		Registry: "registry.corp.example.com",
+		Mappings: map[string]string{
+			"xpkg.upbound.io/spaces-artifacts": "registry.corp.example.com/spaces",
+			"registry.k8s.io":                  "registry.k8s.io",
+		},
+	}
+	cases := map[string]struct {
+		reason string
+		ref    string
+		want   string
+	}{
+		"Registry": {
+			reason: "The registry of a reference that no mapping matches should be replaced.",
+			ref:    "us-west1-docker.pkg.dev/orchestration-build/upbound-environments/mxe-controller:v1.0.0",
+			want:   "registry.corp.example.com/orchestration-build/upbound-environments/mxe-controller:v1.0.0",
+		},
+		"Mapping": {
+			reason: "The prefix of a reference that a mapping matches should be replaced.",
+			ref:    "xpkg.upbound.io/spaces-artifacts/spaces-router:v1.0.0",
+			want:   "registry.corp.example.com/spaces/spaces-router:v1.0.0",
+		},
+		"MappingRepository": {
+			reason: "A mapping should match a reference to the repository it names.",
+			ref:    "xpkg.upbound.io/spaces-artifacts@sha256:abc",
+			want:   "registry.corp.example.com/spaces@sha256:abc",
+		},
+		"PartialComponent": {
+			reason: "A mapping should not match a repository that merely starts with the same characters.",
+			ref:    "xpkg.upbound.io/spaces-artifacts-dev/router:v1.0.0",
+			want:   "registry.corp.example.com/spaces-artifacts-dev/router:v1.0.0",
+		},
+		"Exception": {
+			reason: "A prefix mapped to itself should be left unchanged.",
+			ref:    "registry.k8s.io/ingress-nginx/controller:v1.9.0",
+			want:   "registry.k8s.io/ingress-nginx/controller:v1.9.0",
+		},
+		"NoRegistry": {
+			reason: "A reference without an explicit registry should be left unchanged.",
+			ref:    "upbound/spaces-router:v1.0.0",
+			want:   "upbound/spaces-router:v1.0.0",
+		},
+		"Tag": {
+			reason: "A colon in a reference without a path is a tag, not a registry port.",
+			ref:    "nginx:1.25",
+			want:   "nginx:1.25",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, m.Rewrite(tc.ref)); diff != "" {
+				t.Errorf("\n%s\nRewrite(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImageMirrorRewriteValues(t *testing.T) {
+	m := &ImageMirror{Registry: "registry.corp.example.com/mirror"}
+	values := map[string]any{
+		"image": "xpkg.upbound.io/upbound/controller:v1.0.0",
+		"router": map[string]any{
+			"image": map[string]any{
+				"registry":   "xpkg.upbound.io",
+				"repository": "spaces-artifacts/router",
+				"tag":        "v1.0.0",
+			},
+		},
+		"sidecars": []any{
+			map[string]any{"repository": "ghcr.io/example/sidecar"},
+		},
+		"name": "xpkg.upbound.io/upbound/controller",
+	}
+	want := map[string]any{
+		"image": "registry.corp.example.com/mirror/upbound/controller:v1.0.0",
+		"router": map[string]any{
+			"image": map[string]any{
+				"registry":   "registry.corp.example.com/mirror",
+				"repository": "spaces-artifacts/router",
+				"tag":        "v1.0.0",
+			},
+		},
+		"sidecars": []any{
+			map[string]any{"repository": "registry.corp.example.com/mirror/example/sidecar"},
+		},
+		"name": "xpkg.upbound.io/upbound/controller",
+	}
+	m.RewriteValues(values)
+	if diff := cmp.Diff(want, values); diff != "" {
+		t.Errorf("RewriteValues(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMirrorParams(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "mirror.yaml", []byte("registry.k8s.io: registry.k8s.io\n"), 0o600)
+	_ = afero.WriteFile(fs, "invalid.yaml", []byte("- registry.k8s.io\n"), 0o600)
+
+	type want struct {
+		mirror *ImageMirror
+		err    bool
+	}
+	cases := map[string]struct {
+		reason string
+		params MirrorParams
+		want   want
+	}{
+		"None": {
+			reason: "No mirror should be returned if none is configured.",
+		},
+		"Registry": {
+			reason: "A trailing slash should be removed from the mirror registry.",
+			params: MirrorParams{ImageMirror: "registry.corp.example.com/"},
+			want:   want{mirror: &ImageMirror{Registry: "registry.corp.example.com"}},
+		},
+		"Mappings": {
+			reason: "Mappings should be read from the mapping file.",
+			params: MirrorParams{ImageMirror: "registry.corp.example.com", ImageMirrorMap: "mirror.yaml"},
+			want: want{mirror: &ImageMirror{
+				Registry: "registry.corp.example.com",
+				Mappings: map[string]string{"registry.k8s.io": "registry.k8s.io"},
+			}},
+		},
+		"InvalidMappings": {
+			reason: "An error should be returned if the mapping file is not a map.",
+			params: MirrorParams{ImageMirrorMap: "invalid.yaml"},
+			want:   want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.params.mirror(fs)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nMirror(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mirror, got); diff != "" {
+				t.Errorf("\n%s\nMirror(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
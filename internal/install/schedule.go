@@ -0,0 +1,229 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ScheduledUpgradeName is the name of the objects of a scheduled
+	// upgrade.
+	ScheduledUpgradeName = "up-scheduled-upgrade"
+	// DefaultScheduledUpgradeImage is the default image of the Job that runs
+	// a scheduled upgrade. It must contain helm and a POSIX shell.
+	DefaultScheduledUpgradeImage = "alpine/helm:3.12.3"
+
+	// AnnotationTargetVersion is the annotation of a scheduled upgrade Job
+	// that holds the version it upgrades to.
+	AnnotationTargetVersion = "upbound.io/target-version"
+	// AnnotationScheduledAt is the annotation of a scheduled upgrade Job that
+	// holds the time at which it upgrades, in RFC 3339 format.
+	AnnotationScheduledAt = "upbound.io/scheduled-at"
+
+	keyValues       = "values.yaml"
+	planMountPath   = "/plan"
+	registryMount   = "/registry"
+	clusterAdmin    = "cluster-admin"
+	upgradeTimeout  = "30m"
+	finishedJobTTL  = int32(7 * 24 * 60 * 60)
+	upgradeAttempts = int32(3)
+
+	// upgradeScript waits until the scheduled time, then upgrades the
+	// release. The wait is derived from the scheduled time, so that it is
+	// not extended if the pod is restarted.
+	upgradeScript = `set -e
+now=$(date +%s)
+if [ "$UPGRADE_AT" -gt "$now" ]; then
+  echo "Waiting until $UPGRADE_AT_RFC3339 to upgrade $RELEASE to $VERSION"
+  sleep $((UPGRADE_AT - now))
+fi
+helm upgrade "$RELEASE" "$CHART" --version "$VERSION" --namespace "$NAMESPACE" \
+  --values ` + planMountPath + "/" + keyValues + ` --registry-config ` + registryMount + `/.dockerconfigjson \
+  --wait --timeout ` + upgradeTimeout + ` $HELM_FLAGS
+`
+
+	errMarshalValues         = "unable to marshal upgrade values"
+	errFmtApplyScheduled     = "unable to apply %s of scheduled upgrade"
+	errDeleteScheduledJob    = "unable to delete previously scheduled upgrade"
+	errGetScheduledUpgrade   = "unable to get scheduled upgrade"
+	errParseScheduledUpgrade = "scheduled upgrade job is missing its annotations"
+)
+
+// A ScheduledUpgrade is an upgrade of a Helm release that is run by a Job in
+// the cluster at a later time, e.g. during a maintenance window.
+type ScheduledUpgrade struct {
+	// Namespace of the release and of the objects of the scheduled upgrade.
+	Namespace string
+	// Release is the name of the Helm release.
+	Release string
+	// Chart is the OCI reference of the chart, e.g. oci://REPO/CHART.
+	Chart string
+	// Version of the chart to upgrade to.
+	Version string
+	// At is the time at which the upgrade starts.
+	At time.Time
+	// Parameters are the values of the upgraded release.
+	Parameters map[string]any
+	// PullSecret is the name of the image pull Secret used to pull the
+	// chart.
+	PullSecret string
+	// Image of the Job that runs the upgrade.
+	Image string
+	// Atomic rolls the release back if the upgrade fails.
+	Atomic bool
+}
+
+// Objects returns the objects that make up the scheduled upgrade: a Secret
+// holding the upgrade values, a ServiceAccount that is allowed to upgrade the
+// release, and the Job that runs the upgrade.
+func (s ScheduledUpgrade) Objects() (*corev1.Secret, *corev1.ServiceAccount, *rbacv1.ClusterRoleBinding, *batchv1.Job, error) {
+	values, err := yaml.Marshal(s.Parameters)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, errMarshalValues)
+	}
+	meta := metav1.ObjectMeta{Name: ScheduledUpgradeName, Namespace: s.Namespace}
+	secret := &corev1.Secret{
+		ObjectMeta: meta,
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{keyValues: values},
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: meta}
+	// Upgrades may change cluster scoped objects like CRDs and ClusterRoles,
+	// so the Job needs the same permissions as up.
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: ScheduledUpgradeName},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterAdmin},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: ScheduledUpgradeName, Namespace: s.Namespace}},
+	}
+	flags := ""
+	if s.Atomic {
+		flags = "--atomic"
+	}
+	at := s.At.UTC()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScheduledUpgradeName,
+			Namespace: s.Namespace,
+			Annotations: map[string]string{
+				AnnotationTargetVersion: s.Version,
+				AnnotationScheduledAt:   at.Format(time.RFC3339),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            pointer.Int32(upgradeAttempts - 1),
+			TTLSecondsAfterFinished: pointer.Int32(finishedJobTTL),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ScheduledUpgradeName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "upgrade",
+						Image:   s.Image,
+						Command: []string{"/bin/sh", "-c", upgradeScript},
+						Env: []corev1.EnvVar{
+							{Name: "UPGRADE_AT", Value: strconv.FormatInt(at.Unix(), 10)},
+							{Name: "UPGRADE_AT_RFC3339", Value: at.Format(time.RFC3339)},
+							{Name: "RELEASE", Value: s.Release},
+							{Name: "CHART", Value: s.Chart},
+							{Name: "VERSION", Value: s.Version},
+							{Name: "NAMESPACE", Value: s.Namespace},
+							{Name: "HELM_FLAGS", Value: flags},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "plan", MountPath: planMountPath, ReadOnly: true},
+							{Name: "registry", MountPath: registryMount, ReadOnly: true},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "plan", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: ScheduledUpgradeName}}},
+						{Name: "registry", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: s.PullSecret}}},
+					},
+				},
+			},
+		},
+	}
+	return secret, sa, crb, job, nil
+}
+
+// Apply schedules the upgrade, replacing any previously scheduled upgrade.
+func (s ScheduledUpgrade) Apply(ctx context.Context, client kubernetes.Interface) error {
+	secret, sa, crb, job, err := s.Objects()
+	if err != nil {
+		return err
+	}
+	if err := CancelScheduledUpgrade(ctx, client, s.Namespace); err != nil {
+		return err
+	}
+	secrets := client.CoreV1().Secrets(s.Namespace)
+	if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); kerrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtApplyScheduled, "secret")
+	}
+	if _, err := client.CoreV1().ServiceAccounts(s.Namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, errFmtApplyScheduled, "service account")
+	}
+	if _, err := client.RbacV1().ClusterRoleBindings().Create(ctx, crb, metav1.CreateOptions{}); err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, errFmtApplyScheduled, "cluster role binding")
+	}
+	if _, err := client.BatchV1().Jobs(s.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, errFmtApplyScheduled, "job")
+	}
+	return nil
+}
+
+// GetScheduledUpgrade returns the target version and time of the upgrade
+// scheduled in the namespace, and whether one is scheduled.
+func GetScheduledUpgrade(ctx context.Context, client kubernetes.Interface, namespace string) (string, time.Time, bool, error) {
+	job, err := client.BatchV1().Jobs(namespace).Get(ctx, ScheduledUpgradeName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, errors.Wrap(err, errGetScheduledUpgrade)
+	}
+	at, err := time.Parse(time.RFC3339, job.GetAnnotations()[AnnotationScheduledAt])
+	version := job.GetAnnotations()[AnnotationTargetVersion]
+	if err != nil || version == "" {
+		return "", time.Time{}, false, errors.New(errParseScheduledUpgrade)
+	}
+	return version, at, true, nil
+}
+
+// CancelScheduledUpgrade deletes the Job of the upgrade scheduled in the
+// namespace, if any. The values of the upgrade are kept until an upgrade is
+// scheduled again.
+func CancelScheduledUpgrade(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	bg := metav1.DeletePropagationBackground
+	err := client.BatchV1().Jobs(namespace).Delete(ctx, ScheduledUpgradeName, metav1.DeleteOptions{PropagationPolicy: &bg})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, errDeleteScheduledJob)
+	}
+	return nil
+}
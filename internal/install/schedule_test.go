@@ -0,0 +1,130 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScheduledUpgradeApply(t *testing.T) {
+	at := time.Date(2023, 9, 1, 2, 0, 0, 0, time.UTC)
+	s := ScheduledUpgrade{
+		Namespace:  "upbound-system",
+		Release:    "spaces",
+		Chart:      "oci://example.org/upbound/spaces",
+		Version:    "1.2.0",
+		At:         at,
+		Parameters: map[string]any{"account": "acme"},
+		PullSecret: "upbound-pull-secret",
+		Image:      DefaultScheduledUpgradeImage,
+		Atomic:     true,
+	}
+	previous := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:        ScheduledUpgradeName,
+		Namespace:   "upbound-system",
+		Annotations: map[string]string{AnnotationTargetVersion: "1.1.0", AnnotationScheduledAt: "2023-08-01T02:00:00Z"},
+	}}
+
+	cases := map[string]struct {
+		reason string
+		objs   []runtime.Object
+	}{
+		"New": {
+			reason: "A new upgrade should be scheduled.",
+		},
+		"Replace": {
+			reason: "A previously scheduled upgrade should be replaced.",
+			objs:   []runtime.Object{previous},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.objs...)
+			if err := s.Apply(context.Background(), client); err != nil {
+				t.Fatalf("\n%s\nApply(...): unexpected error: %v", tc.reason, err)
+			}
+			secret, err := client.CoreV1().Secrets("upbound-system").Get(context.Background(), ScheduledUpgradeName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("\n%s\nApply(...): unexpected error getting values: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff("account: acme\n", string(secret.Data[keyValues])); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want values, +got values:\n%s", tc.reason, diff)
+			}
+			job, err := client.BatchV1().Jobs("upbound-system").Get(context.Background(), ScheduledUpgradeName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("\n%s\nApply(...): unexpected error getting job: %v", tc.reason, err)
+			}
+			wantEnv := []corev1.EnvVar{
+				{Name: "UPGRADE_AT", Value: "1693533600"},
+				{Name: "UPGRADE_AT_RFC3339", Value: "2023-09-01T02:00:00Z"},
+				{Name: "RELEASE", Value: "spaces"},
+				{Name: "CHART", Value: "oci://example.org/upbound/spaces"},
+				{Name: "VERSION", Value: "1.2.0"},
+				{Name: "NAMESPACE", Value: "upbound-system"},
+				{Name: "HELM_FLAGS", Value: "--atomic"},
+			}
+			if diff := cmp.Diff(wantEnv, job.Spec.Template.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("\n%s\nApply(...): -want env, +got env:\n%s", tc.reason, diff)
+			}
+			version, got, ok, err := GetScheduledUpgrade(context.Background(), client, "upbound-system")
+			if err != nil || !ok {
+				t.Fatalf("\n%s\nGetScheduledUpgrade(...): scheduled %t, error %v", tc.reason, ok, err)
+			}
+			if version != "1.2.0" || !got.Equal(at) {
+				t.Errorf("\n%s\nGetScheduledUpgrade(...): got %s at %s, want 1.2.0 at %s", tc.reason, version, got, at)
+			}
+		})
+	}
+}
+
+func TestCancelScheduledUpgrade(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: ScheduledUpgradeName, Namespace: "upbound-system"}}
+
+	cases := map[string]struct {
+		reason string
+		objs   []runtime.Object
+	}{
+		"Scheduled": {
+			reason: "A scheduled upgrade should be canceled.",
+			objs:   []runtime.Object{job},
+		},
+		"NotScheduled": {
+			reason: "Canceling should succeed if no upgrade is scheduled.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.objs...)
+			err := CancelScheduledUpgrade(context.Background(), client, "upbound-system")
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCancelScheduledUpgrade(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			_, _, ok, _ := GetScheduledUpgrade(context.Background(), client, "upbound-system")
+			if ok {
+				t.Errorf("\n%s\nCancelScheduledUpgrade(...): upgrade is still scheduled", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"time"
+
+	"github.com/upbound/up/internal/upterm"
+)
+
+// revisionV1 is version v1 of the machine output of a revision of an
+// installation.
+type revisionV1 struct {
+	Revision    int       `json:"revision"`
+	Version     string    `json:"version"`
+	Status      string    `json:"status"`
+	Updated     time.Time `json:"updated"`
+	Description string    `json:"description,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(Revision{}, upterm.Schema{
+		Kind:    "Revision",
+		Version: "v1",
+		Convert: func(obj any) any {
+			r := obj.(Revision)
+			return revisionV1{Revision: r.Revision, Version: r.Version, Status: r.Status, Updated: r.Updated.UTC(), Description: r.Description}
+		},
+	})
+}
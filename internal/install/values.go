@@ -0,0 +1,134 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	// stdinSource is the parameters file source that reads from standard
+	// input.
+	stdinSource    = "-"
+	checksumPrefix = "sha256:"
+
+	errInsecureURL       = "parameters files can only be fetched over https"
+	errFmtFetchStatus    = "unexpected status %s"
+	errFetchParameters   = "unable to fetch URL"
+	errChecksumFormat    = "checksum must be of the form sha256:HEX"
+	errChecksumCount     = "--file-checksum must be given once for each --file"
+	errStdinTwice        = "standard input can only be read by one --file"
+	errFmtReadSource     = "unable to read %s"
+	errFmtParseSource    = "unable to parse %s"
+	errFmtChecksumDiffer = "checksum of %s does not match: got sha256:%s"
+)
+
+// Parameters reads the parameters files from local paths, standard input if
+// the path is -, or https URLs. The contents are verified against the
+// checksums if any were supplied.
+func (p *CommonParams) Parameters(ctx context.Context) ([]ValuesFile, error) {
+	return p.parameters(ctx, afero.NewOsFs(), os.Stdin, uphttp.NewClient())
+}
+
+func (p *CommonParams) parameters(ctx context.Context, fs afero.Fs, stdin io.Reader, client *http.Client) ([]ValuesFile, error) {
+	if len(p.FileChecksum) > 0 && len(p.FileChecksum) != len(p.File) {
+		return nil, errors.New(errChecksumCount)
+	}
+	files := make([]ValuesFile, len(p.File))
+	stdinRead := false
+	for i, src := range p.File {
+		if src == stdinSource {
+			if stdinRead {
+				return nil, errors.New(errStdinTwice)
+			}
+			stdinRead = true
+		}
+		b, err := readSource(ctx, src, fs, stdin, client)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadSource, src)
+		}
+		if len(p.FileChecksum) > 0 {
+			if err := verifyChecksum(src, b, p.FileChecksum[i]); err != nil {
+				return nil, err
+			}
+		}
+		values := map[string]any{}
+		if err := yaml.Unmarshal(b, &values); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseSource, src)
+		}
+		// Empty files unmarshal to nil.
+		if values == nil {
+			values = map[string]any{}
+		}
+		files[i] = ValuesFile{Source: src, Values: values}
+	}
+	return files, nil
+}
+
+// readSource reads the contents of a parameters file source.
+func readSource(ctx context.Context, src string, fs afero.Fs, stdin io.Reader, client *http.Client) ([]byte, error) {
+	if src == stdinSource {
+		return io.ReadAll(stdin)
+	}
+	u, err := url.Parse(src)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return afero.ReadFile(fs, filepath.Clean(src))
+	}
+	if u.Scheme != "https" {
+		return nil, errors.New(errInsecureURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchParameters)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchParameters)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtFetchStatus, res.Status)
+	}
+	b, err := io.ReadAll(res.Body)
+	return b, errors.Wrap(err, errFetchParameters)
+}
+
+// verifyChecksum verifies that the contents of the source match the checksum.
+func verifyChecksum(src string, b []byte, checksum string) error {
+	want, ok := strings.CutPrefix(checksum, checksumPrefix)
+	if !ok {
+		return errors.New(errChecksumFormat)
+	}
+	sum := sha256.Sum256(b)
+	got := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(want, got) {
+		return errors.Errorf(errFmtChecksumDiffer, src, got)
+	}
+	return nil
+}
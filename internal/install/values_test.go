@@ -0,0 +1,133 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+const values = "replicas: 2\n"
+
+func checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParameters(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/values.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(values))
+	}))
+	defer srv.Close()
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "values.yaml", []byte(values), 0o600)
+	_ = afero.WriteFile(fs, "empty.yaml", nil, 0o600)
+
+	replicas := map[string]any{"replicas": float64(2)}
+
+	type want struct {
+		files []ValuesFile
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		params CommonParams
+		want   want
+	}{
+		"NoFile": {
+			reason: "No values files should be returned without a file.",
+			want:   want{files: []ValuesFile{}},
+		},
+		"ChecksumCount": {
+			reason: "A checksum must be supplied for each file.",
+			params: CommonParams{FileChecksum: []string{"sha256:" + checksum(values)}},
+			want:   want{err: errors.New(errChecksumCount)},
+		},
+		"LocalFiles": {
+			reason: "Values files should be read from local files in order.",
+			params: CommonParams{File: []string{"values.yaml", "empty.yaml"}},
+			want: want{files: []ValuesFile{
+				{Source: "values.yaml", Values: replicas},
+				{Source: "empty.yaml", Values: map[string]any{}},
+			}},
+		},
+		"Stdin": {
+			reason: "Values should be read from standard input.",
+			params: CommonParams{File: []string{"-"}},
+			want:   want{files: []ValuesFile{{Source: "-", Values: replicas}}},
+		},
+		"StdinTwice": {
+			reason: "Standard input cannot be read more than once.",
+			params: CommonParams{File: []string{"-", "-"}},
+			want:   want{err: errors.New(errStdinTwice)},
+		},
+		"URL": {
+			reason: "Values should be fetched from an https URL.",
+			params: CommonParams{File: []string{srv.URL + "/values.yaml"}},
+			want:   want{files: []ValuesFile{{Source: srv.URL + "/values.yaml", Values: replicas}}},
+		},
+		"URLNotFound": {
+			reason: "Unsuccessful responses should be returned as errors.",
+			params: CommonParams{File: []string{srv.URL + "/missing.yaml"}},
+			want:   want{err: errors.Wrapf(errors.Errorf(errFmtFetchStatus, "404 Not Found"), errFmtReadSource, srv.URL+"/missing.yaml")},
+		},
+		"InsecureURL": {
+			reason: "Values should not be fetched over plain http.",
+			params: CommonParams{File: []string{"http://example.com/values.yaml"}},
+			want:   want{err: errors.Wrapf(errors.New(errInsecureURL), errFmtReadSource, "http://example.com/values.yaml")},
+		},
+		"Checksum": {
+			reason: "Values that match the checksum should be returned.",
+			params: CommonParams{File: []string{"-"}, FileChecksum: []string{"sha256:" + strings.ToUpper(checksum(values))}},
+			want:   want{files: []ValuesFile{{Source: "-", Values: replicas}}},
+		},
+		"ChecksumMismatch": {
+			reason: "Values that do not match the checksum should be rejected.",
+			params: CommonParams{File: []string{"values.yaml"}, FileChecksum: []string{"sha256:abc"}},
+			want:   want{err: errors.Errorf(errFmtChecksumDiffer, "values.yaml", checksum(values))},
+		},
+		"ChecksumFormat": {
+			reason: "Checksums of an unknown form should be rejected.",
+			params: CommonParams{File: []string{"values.yaml"}, FileChecksum: []string{"md5:abc"}},
+			want:   want{err: errors.New(errChecksumFormat)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.params.parameters(context.Background(), fs, strings.NewReader(values), srv.Client())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparameters(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.files, got); diff != "" {
+				t.Errorf("\n%s\nparameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
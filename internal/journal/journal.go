@@ -0,0 +1,226 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journal records the progress of bulk operations so that an
+// interrupted or partially failed operation can be resumed.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/config"
+)
+
+const (
+	// dir is the directory under the up config directory that journals are
+	// stored in.
+	dir = "operations"
+
+	errReadJournal     = "unable to read operation journal"
+	errParseJournal    = "unable to parse operation journal"
+	errWriteJournal    = "unable to write operation journal"
+	errFmtNoJournal    = "no operation found with ID %q"
+	errFmtWrongJournal = "operation %q is a %q operation, not %q"
+)
+
+// Status is the outcome of an item of an operation.
+type Status string
+
+// Item statuses.
+const (
+	StatusSucceeded Status = "Succeeded"
+	StatusFailed    Status = "Failed"
+)
+
+// Item is the recorded outcome of a single item of an operation.
+type Item struct {
+	Status  Status    `json:"status"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// A Journal records the outcome of each item of a bulk operation.
+type Journal struct {
+	ID        string          `json:"id"`
+	Operation string          `json:"operation"`
+	Items     map[string]Item `json:"items"`
+
+	fs   afero.Fs
+	path string
+	now  func() time.Time
+}
+
+// Store persists journals in a directory.
+type Store struct {
+	fs  afero.Fs
+	dir string
+	now func() time.Time
+}
+
+// StoreOption modifies a Store.
+type StoreOption func(*Store)
+
+// WithFS overrides the filesystem journals are stored in.
+func WithFS(fs afero.Fs) StoreOption {
+	return func(s *Store) {
+		s.fs = fs
+	}
+}
+
+// WithDir overrides the directory journals are stored in.
+func WithDir(d string) StoreOption {
+	return func(s *Store) {
+		s.dir = filepath.Clean(d)
+	}
+}
+
+// NewStore constructs a Store that keeps journals in the up config directory
+// unless overridden.
+func NewStore(opts ...StoreOption) (*Store, error) {
+	s := &Store{
+		fs:  afero.NewOsFs(),
+		now: time.Now,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.dir == "" {
+		p, err := config.GetDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		s.dir = filepath.Join(filepath.Dir(p), dir)
+	}
+	return s, nil
+}
+
+// Start begins a new journal for the operation, or resumes the journal with
+// the supplied ID if it is not empty. A journal can only be resumed by the
+// operation that started it.
+func (s *Store) Start(operation, resume string) (*Journal, error) {
+	if resume != "" {
+		return s.open(operation, resume)
+	}
+	id := fmt.Sprintf("%s-%04x", s.now().UTC().Format("20060102-150405"), rand.Intn(1<<16)) //nolint:gosec // IDs only need to avoid collisions.
+	return &Journal{
+		ID:        id,
+		Operation: operation,
+		Items:     map[string]Item{},
+		fs:        s.fs,
+		path:      filepath.Join(s.dir, id+".json"),
+		now:       s.now,
+	}, nil
+}
+
+func (s *Store) open(operation, id string) (*Journal, error) {
+	p := filepath.Join(s.dir, filepath.Base(id)+".json")
+	b, err := afero.ReadFile(s.fs, p)
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf(errFmtNoJournal, id)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errReadJournal)
+	}
+	j := &Journal{}
+	if err := json.Unmarshal(b, j); err != nil {
+		return nil, errors.Wrap(err, errParseJournal)
+	}
+	if j.Operation != operation {
+		return nil, errors.Errorf(errFmtWrongJournal, id, j.Operation, operation)
+	}
+	if j.Items == nil {
+		j.Items = map[string]Item{}
+	}
+	j.fs, j.path, j.now = s.fs, p, s.now
+	return j, nil
+}
+
+// Done returns true if the item succeeded in this or a previous run of the
+// operation.
+func (j *Journal) Done(key string) bool {
+	return j.Items[key].Status == StatusSucceeded
+}
+
+// Record records the outcome of the item and persists the journal, so that
+// progress survives the process being interrupted.
+func (j *Journal) Record(key string, err error) error {
+	it := Item{Status: StatusSucceeded, Time: j.now().UTC().Truncate(time.Second)}
+	if err != nil {
+		it.Status, it.Message = StatusFailed, err.Error()
+	}
+	j.Items[key] = it
+	return j.save()
+}
+
+// Failed returns the sorted keys of the items that failed.
+func (j *Journal) Failed() []string {
+	f := []string{}
+	for k, it := range j.Items {
+		if it.Status == StatusFailed {
+			f = append(f, k)
+		}
+	}
+	sort.Strings(f)
+	return f
+}
+
+// ResumeHint tells the user how to retry the failed items of the operation.
+func (j *Journal) ResumeHint() string {
+	return fmt.Sprintf("Re-run with --resume %s to retry only the %d failed items.", j.ID, len(j.Failed()))
+}
+
+// Finish removes the journal if all recorded items succeeded, as there is
+// nothing left to resume. Otherwise the journal is kept.
+func (j *Journal) Finish() error {
+	if len(j.Failed()) > 0 {
+		return nil
+	}
+	if err := j.fs.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	return nil
+}
+
+// save atomically writes the journal so that a concurrent reader or an
+// interruption never observes a partially written file.
+func (j *Journal) save() error {
+	b, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	if err := j.fs.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	f, err := afero.TempFile(j.fs, filepath.Dir(j.path), filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, errWriteJournal)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errWriteJournal)
+	}
+	return errors.Wrap(j.fs.Rename(f.Name(), j.path), errWriteJournal)
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(WithFS(afero.NewMemMapFs()), WithDir("/operations"))
+	if err != nil {
+		t.Fatalf("NewStore(...): %v", err)
+	}
+	s.now = func() time.Time { return time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func TestResume(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := newTestStore(t)
+
+	j, err := s.Start("robot-import/acme", "")
+	if err != nil {
+		t.Fatalf("Start(...): %v", err)
+	}
+	if err := j.Record("ci-1", nil); err != nil {
+		t.Fatalf("Record(...): %v", err)
+	}
+	if err := j.Record("ci-2", errBoom); err != nil {
+		t.Fatalf("Record(...): %v", err)
+	}
+	if err := j.Finish(); err != nil {
+		t.Fatalf("Finish(): %v", err)
+	}
+
+	r, err := s.Start("robot-import/acme", j.ID)
+	if err != nil {
+		t.Fatalf("Start(...): %v", err)
+	}
+	if !r.Done("ci-1") {
+		t.Errorf("Done(%q): want true for an item that succeeded", "ci-1")
+	}
+	if r.Done("ci-2") {
+		t.Errorf("Done(%q): want false for an item that failed", "ci-2")
+	}
+	want := map[string]Item{
+		"ci-1": {Status: StatusSucceeded, Time: s.now()},
+		"ci-2": {Status: StatusFailed, Message: "boom", Time: s.now()},
+	}
+	if diff := cmp.Diff(want, r.Items); diff != "" {
+		t.Errorf("Items: -want, +got:\n%s", diff)
+	}
+
+	// Once the failed item succeeds there is nothing left to resume.
+	if err := r.Record("ci-2", nil); err != nil {
+		t.Fatalf("Record(...): %v", err)
+	}
+	if err := r.Finish(); err != nil {
+		t.Fatalf("Finish(): %v", err)
+	}
+	_, err = s.Start("robot-import/acme", j.ID)
+	if diff := cmp.Diff(errors.Errorf(errFmtNoJournal, j.ID), err, test.EquateErrors()); diff != "" {
+		t.Errorf("Start(...): -want err, +got err:\n%s", diff)
+	}
+}
+
+func TestStartWrongOperation(t *testing.T) {
+	s := newTestStore(t)
+	j, err := s.Start("robot-import/acme", "")
+	if err != nil {
+		t.Fatalf("Start(...): %v", err)
+	}
+	if err := j.Record("ci-1", errors.New("boom")); err != nil {
+		t.Fatalf("Record(...): %v", err)
+	}
+
+	_, err = s.Start("controlplane-delete/acme", j.ID)
+	want := errors.Errorf(errFmtWrongJournal, j.ID, "robot-import/acme", "controlplane-delete/acme")
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("Start(...): -want err, +got err:\n%s", diff)
+	}
+}
@@ -66,6 +66,17 @@ func NewImagePullApplicator(secret *SecretApplicator) *ImagePullApplicator {
 // Apply constructs an DockerConfig image pull Secret with the provided registry
 // and credentials.
 func (i *ImagePullApplicator) Apply(ctx context.Context, name, ns, user, pass, registry string) error {
+	secret, err := BuildImagePullSecret(name, ns, user, pass, registry)
+	if err != nil {
+		return err
+	}
+	// Create image pull secret if it does not exist.
+	return i.secret.Apply(ctx, ns, secret)
+}
+
+// BuildImagePullSecret constructs a DockerConfig image pull Secret with the
+// provided registry and credentials without creating it.
+func BuildImagePullSecret(name, ns, user, pass, registry string) (*corev1.Secret, error) {
 	regAuth := &create.DockerConfigJSON{
 		Auths: map[string]create.DockerConfigEntry{
 			registry: {
@@ -77,20 +88,23 @@ func (i *ImagePullApplicator) Apply(ctx context.Context, name, ns, user, pass, r
 	}
 	regAuthJSON, err := json.Marshal(regAuth)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	secret := &corev1.Secret{
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			Name:      name,
+			Namespace: ns,
 		},
 		Type: corev1.SecretTypeDockerConfigJson,
 		Data: map[string][]byte{
 			corev1.DockerConfigJsonKey: regAuthJSON,
 		},
-	}
-	// Create image pull secret if it does not exist.
-	return i.secret.Apply(ctx, ns, secret)
+	}, nil
 }
 
 // encodeDockerConfigFieldAuth returns base64 encoding of the username and
@@ -48,13 +48,25 @@ func GetKubeConfig(path string) (*rest.Config, error) {
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
 }
 
+// GetKubeConfigWithContext constructs a Kubernetes REST config from the
+// specified context of the specified kubeconfig. The same defaults as kubectl
+// are used for an empty path, and the current context for an empty context.
+func GetKubeConfigWithContext(path, context string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = path
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+}
+
 // BuildControlPlaneKubeconfig builds a kubeconfig entry for a control plane.
 func BuildControlPlaneKubeconfig(proxy *url.URL, id string, token string) *api.Config { //nolint:interfacer
 	conf := api.NewConfig()
 	key := fmt.Sprintf(UpboundKubeconfigKeyFmt, strings.ReplaceAll(id, "/", "-"))
-	proxy.Path = path.Join(proxy.Path, id, UpboundK8sResource)
+	// The proxy URL is copied so that it can be reused for other control
+	// planes.
+	server := *proxy
+	server.Path = path.Join(server.Path, id, UpboundK8sResource)
 	conf.Clusters[key] = &api.Cluster{
-		Server: proxy.String(),
+		Server: server.String(),
 	}
 	conf.AuthInfos[key] = &api.AuthInfo{
 		Token: token,
@@ -76,15 +88,7 @@ func ApplyControlPlaneKubeconfig(mcpConf *api.Config, existingFilePath string, w
 	if err != nil {
 		return err
 	}
-	for k, v := range mcpConf.Clusters {
-		conf.Clusters[k] = v
-	}
-	for k, v := range mcpConf.AuthInfos {
-		conf.AuthInfos[k] = v
-	}
-	for k, v := range mcpConf.Contexts {
-		conf.Contexts[k] = v
-	}
+	MergeKubeconfig(conf, mcpConf)
 	conf.CurrentContext = mcpConf.CurrentContext
 
 	// In the case of user error, for example providing an invalid access token,
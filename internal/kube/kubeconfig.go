@@ -0,0 +1,108 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ControlPlaneContext is a kubeconfig context generated for an Upbound
+// control plane.
+type ControlPlaneContext struct {
+	Context      string `json:"context"`
+	Account      string `json:"account"`
+	ControlPlane string `json:"controlPlane"`
+	Current      bool   `json:"current"`
+}
+
+// ControlPlaneContexts returns the contexts in the kubeconfig that were
+// generated for control planes, sorted by name. Contexts are identified by
+// their name and the path of the server of their cluster.
+func ControlPlaneContexts(conf *api.Config) []ControlPlaneContext {
+	prefix := strings.TrimSuffix(UpboundKubeconfigKeyFmt, "%s")
+	ctxs := []ControlPlaneContext{}
+	for name, c := range conf.Contexts {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		cluster, ok := conf.Clusters[c.Cluster]
+		if !ok {
+			continue
+		}
+		u, err := url.Parse(cluster.Server)
+		if err != nil {
+			continue
+		}
+		// The server path ends with <account>/<control plane>/k8s.
+		segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segs) < 3 || segs[len(segs)-1] != UpboundK8sResource {
+			continue
+		}
+		ctxs = append(ctxs, ControlPlaneContext{
+			Context:      name,
+			Account:      segs[len(segs)-3],
+			ControlPlane: segs[len(segs)-2],
+			Current:      name == conf.CurrentContext,
+		})
+	}
+	sort.Slice(ctxs, func(i, j int) bool { return ctxs[i].Context < ctxs[j].Context })
+	return ctxs
+}
+
+// MergeKubeconfig merges the clusters, users and contexts of src into dst,
+// replacing entries with the same name. The current context of dst is left
+// unchanged.
+func MergeKubeconfig(dst, src *api.Config) {
+	for k, v := range src.Clusters {
+		dst.Clusters[k] = v
+	}
+	for k, v := range src.AuthInfos {
+		dst.AuthInfos[k] = v
+	}
+	for k, v := range src.Contexts {
+		dst.Contexts[k] = v
+	}
+}
+
+// RemoveContexts removes the named contexts from the kubeconfig, along with
+// their clusters and users if no remaining context refers to them. The current
+// context is unset if it is removed.
+func RemoveContexts(conf *api.Config, names ...string) {
+	for _, n := range names {
+		c, ok := conf.Contexts[n]
+		if !ok {
+			continue
+		}
+		delete(conf.Contexts, n)
+		if conf.CurrentContext == n {
+			conf.CurrentContext = ""
+		}
+		clusterUsed, userUsed := false, false
+		for _, other := range conf.Contexts {
+			clusterUsed = clusterUsed || other.Cluster == c.Cluster
+			userUsed = userUsed || other.AuthInfo == c.AuthInfo
+		}
+		if !clusterUsed {
+			delete(conf.Clusters, c.Cluster)
+		}
+		if !userUsed {
+			delete(conf.AuthInfos, c.AuthInfo)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testKubeconfig() *api.Config {
+	proxy, _ := url.Parse("https://proxy.upbound.io/v1/controlPlanes")
+	conf := BuildControlPlaneKubeconfig(proxy, "cool-org/cool-ctp", "token")
+	other := BuildControlPlaneKubeconfig(proxy, "cool-org/old-ctp", "token")
+	MergeKubeconfig(conf, other)
+	conf.CurrentContext = other.CurrentContext
+	conf.Clusters["kind"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+	conf.AuthInfos["kind"] = &api.AuthInfo{Token: "token"}
+	conf.Contexts["kind"] = &api.Context{Cluster: "kind", AuthInfo: "kind"}
+	return conf
+}
+
+func TestControlPlaneContexts(t *testing.T) {
+	want := []ControlPlaneContext{
+		{Context: "upbound-cool-org-cool-ctp", Account: "cool-org", ControlPlane: "cool-ctp"},
+		{Context: "upbound-cool-org-old-ctp", Account: "cool-org", ControlPlane: "old-ctp", Current: true},
+	}
+	if diff := cmp.Diff(want, ControlPlaneContexts(testKubeconfig())); diff != "" {
+		t.Errorf("ControlPlaneContexts(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	proxy, _ := url.Parse("https://proxy.upbound.io/v1/controlPlanes")
+	conf := api.NewConfig()
+	conf.Clusters["upbound-cool-org-cool-ctp"] = &api.Cluster{Server: "https://stale"}
+	conf.CurrentContext = "kind"
+
+	MergeKubeconfig(conf, BuildControlPlaneKubeconfig(proxy, "cool-org/cool-ctp", "token"))
+
+	want := &api.Cluster{Server: "https://proxy.upbound.io/v1/controlPlanes/cool-org/cool-ctp/k8s"}
+	if diff := cmp.Diff(want, conf.Clusters["upbound-cool-org-cool-ctp"], cmpopts.IgnoreFields(api.Cluster{}, "Extensions")); diff != "" {
+		t.Errorf("MergeKubeconfig(...): -want cluster, +got cluster:\n%s", diff)
+	}
+	if _, ok := conf.Contexts["upbound-cool-org-cool-ctp"]; !ok {
+		t.Errorf("MergeKubeconfig(...): context was not merged")
+	}
+	if conf.CurrentContext != "kind" {
+		t.Errorf("MergeKubeconfig(...): current context %q should be unchanged", conf.CurrentContext)
+	}
+	if proxy.Path != "/v1/controlPlanes" {
+		t.Errorf("BuildControlPlaneKubeconfig(...): proxy URL was modified to %q", proxy.Path)
+	}
+}
+
+func TestRemoveContexts(t *testing.T) {
+	conf := testKubeconfig()
+	// A context that shares the cluster of a removed context.
+	conf.Contexts["shared"] = &api.Context{Cluster: "upbound-cool-org-old-ctp", AuthInfo: "kind"}
+
+	RemoveContexts(conf, "upbound-cool-org-old-ctp", "missing")
+
+	keys := func(m any) []string {
+		out := []string{}
+		switch m := m.(type) {
+		case map[string]*api.Context:
+			for k := range m {
+				out = append(out, k)
+			}
+		case map[string]*api.Cluster:
+			for k := range m {
+				out = append(out, k)
+			}
+		case map[string]*api.AuthInfo:
+			for k := range m {
+				out = append(out, k)
+			}
+		}
+		return out
+	}
+	byName := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	if diff := cmp.Diff([]string{"kind", "shared", "upbound-cool-org-cool-ctp"}, keys(conf.Contexts), byName); diff != "" {
+		t.Errorf("RemoveContexts(...): -want contexts, +got contexts:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"kind", "upbound-cool-org-cool-ctp", "upbound-cool-org-old-ctp"}, keys(conf.Clusters), byName); diff != "" {
+		t.Errorf("RemoveContexts(...): -want clusters, +got clusters:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"kind", "upbound-cool-org-cool-ctp"}, keys(conf.AuthInfos), byName); diff != "" {
+		t.Errorf("RemoveContexts(...): -want users, +got users:\n%s", diff)
+	}
+	if conf.CurrentContext != "" {
+		t.Errorf("RemoveContexts(...): current context %q should be unset", conf.CurrentContext)
+	}
+}
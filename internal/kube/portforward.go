@@ -0,0 +1,258 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	targetKindPod     = "pod"
+	targetKindService = "svc"
+
+	errGetTarget           = "unable to get port-forward target"
+	errCreatePortForwarder = "unable to create port forwarder"
+	errBuildRoundTripper   = "unable to build port-forward transport"
+	errPortForwardStopped  = "port-forward stopped"
+
+	errFmtInvalidTarget = "invalid port-forward target %q: must be of the form [pod/|svc/]name"
+	errFmtInvalidPort   = "invalid port mapping %q: must be of the form [local:]remote"
+	errFmtNoRunningPod  = "no running pod found for service %s"
+	errFmtPodNotRunning = "pod %s is not running"
+	errFmtServicePort   = "service %s has no port %d"
+	errFmtNamedPort     = "pod %s has no container port named %s"
+)
+
+// PortForwardOptions configure a port-forward.
+type PortForwardOptions struct {
+	// Namespace of the target.
+	Namespace string
+	// Target is the pod or service to forward to, in the form
+	// [pod/|svc/]name. Bare names refer to services.
+	Target string
+	// Ports are the ports to forward in the form [local:]remote. For
+	// services, remote ports are service ports.
+	Ports []string
+	// Addresses to listen on. Defaults to localhost.
+	Addresses []string
+	// Out and ErrOut receive progress and errors from the port-forward.
+	Out, ErrOut io.Writer
+	// Ready is closed once the port-forward is ready to accept connections.
+	// Optional.
+	Ready chan struct{}
+}
+
+// PortForward forwards local ports to a pod in the cluster described by the
+// supplied config until the context is done. If the target is a service,
+// ports are forwarded to a running pod selected by the service.
+func PortForward(ctx context.Context, cfg *rest.Config, opts PortForwardOptions) error {
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	pod, ports, err := resolvePortForward(ctx, client, opts.Namespace, opts.Target, opts.Ports)
+	if err != nil {
+		return err
+	}
+
+	rt, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return errors.Wrap(err, errBuildRoundTripper)
+	}
+	url := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: rt}, http.MethodPost, url)
+
+	addresses := opts.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{"localhost"}
+	}
+	ready := opts.Ready
+	if ready == nil {
+		ready = make(chan struct{})
+	}
+	stop := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(stop)
+	}()
+	fw, err := portforward.NewOnAddresses(dialer, addresses, ports, stop, ready, opts.Out, opts.ErrOut)
+	if err != nil {
+		return errors.Wrap(err, errCreatePortForwarder)
+	}
+	if err := fw.ForwardPorts(); err != nil {
+		return err
+	}
+	// ForwardPorts returns without error when stopped, so surface the reason
+	// if the context was cancelled due to a deadline.
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Wrap(ctx.Err(), errPortForwardStopped)
+	}
+	return nil
+}
+
+// resolvePortForward returns the pod to forward to and the port mappings
+// translated to container ports.
+func resolvePortForward(ctx context.Context, client kubernetes.Interface, namespace, target string, ports []string) (*corev1.Pod, []string, error) { //nolint:gocyclo
+	kind, name, err := parseTarget(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	mappings := make([]portMapping, len(ports))
+	for i, p := range ports {
+		if mappings[i], err = parsePortMapping(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var svc *corev1.Service
+	var pod *corev1.Pod
+	switch kind {
+	case targetKindPod:
+		pod, err = client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errGetTarget)
+		}
+	default:
+		svc, err = client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errGetTarget)
+		}
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errGetTarget)
+		}
+		for i := range pods.Items {
+			if pods.Items[i].Status.Phase == corev1.PodRunning {
+				pod = &pods.Items[i]
+				break
+			}
+		}
+		if pod == nil {
+			return nil, nil, errors.Errorf(errFmtNoRunningPod, name)
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, nil, errors.Errorf(errFmtPodNotRunning, pod.Name)
+	}
+
+	out := make([]string, len(mappings))
+	for i, m := range mappings {
+		remote := m.remote
+		if svc != nil {
+			if remote, err = servicePortToContainerPort(svc, pod, remote); err != nil {
+				return nil, nil, err
+			}
+		}
+		out[i] = fmt.Sprintf("%d:%d", m.local, remote)
+	}
+	return pod, out, nil
+}
+
+func parseTarget(target string) (string, string, error) {
+	kind, name, found := strings.Cut(target, "/")
+	if !found {
+		return targetKindService, target, nil
+	}
+	switch kind {
+	case targetKindPod, "pods", "po":
+		kind = targetKindPod
+	case targetKindService, "service", "services":
+		kind = targetKindService
+	default:
+		return "", "", errors.Errorf(errFmtInvalidTarget, target)
+	}
+	if name == "" {
+		return "", "", errors.Errorf(errFmtInvalidTarget, target)
+	}
+	return kind, name, nil
+}
+
+type portMapping struct {
+	local  int32
+	remote int32
+}
+
+// parsePortMapping parses a port mapping of the form [local:]remote. If local
+// is omitted, the remote port is used. If local is empty, a random local port
+// is chosen.
+func parsePortMapping(s string) (portMapping, error) {
+	local, remote, found := strings.Cut(s, ":")
+	if !found {
+		remote = local
+	}
+	r, err := strconv.ParseUint(remote, 10, 16)
+	if err != nil || r == 0 {
+		return portMapping{}, errors.Errorf(errFmtInvalidPort, s)
+	}
+	m := portMapping{local: int32(r), remote: int32(r)}
+	if !found {
+		return m, nil
+	}
+	m.local = 0
+	if local != "" {
+		l, err := strconv.ParseUint(local, 10, 16)
+		if err != nil {
+			return portMapping{}, errors.Errorf(errFmtInvalidPort, s)
+		}
+		m.local = int32(l)
+	}
+	return m, nil
+}
+
+// servicePortToContainerPort translates a service port to the container port
+// of the supplied pod that it targets.
+func servicePortToContainerPort(svc *corev1.Service, pod *corev1.Pod, port int32) (int32, error) {
+	for _, sp := range svc.Spec.Ports {
+		if sp.Port != port {
+			continue
+		}
+		if sp.TargetPort.StrVal == "" {
+			if sp.TargetPort.IntVal == 0 {
+				return port, nil
+			}
+			return sp.TargetPort.IntVal, nil
+		}
+		for _, c := range pod.Spec.Containers {
+			for _, cp := range c.Ports {
+				if cp.Name == sp.TargetPort.StrVal {
+					return cp.ContainerPort, nil
+				}
+			}
+		}
+		return 0, errors.Errorf(errFmtNamedPort, pod.Name, sp.TargetPort.StrVal)
+	}
+	return 0, errors.Errorf(errFmtServicePort, svc.Name, port)
+}
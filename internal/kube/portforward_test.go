@@ -0,0 +1,132 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	type want struct {
+		m   portMapping
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"RemoteOnly": {
+			reason: "A single port should be used for both local and remote.",
+			s:      "8080",
+			want:   want{m: portMapping{local: 8080, remote: 8080}},
+		},
+		"LocalAndRemote": {
+			reason: "Local and remote ports should be parsed.",
+			s:      "9090:8080",
+			want:   want{m: portMapping{local: 9090, remote: 8080}},
+		},
+		"RandomLocal": {
+			reason: "An empty local port should select a random local port.",
+			s:      ":8080",
+			want:   want{m: portMapping{local: 0, remote: 8080}},
+		},
+		"Invalid": {
+			reason: "A non-numeric port should return an error.",
+			s:      "http",
+			want:   want{err: errors.Errorf(errFmtInvalidPort, "http")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := parsePortMapping(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparsePortMapping(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.m, m, cmp.AllowUnexported(portMapping{})); diff != "" {
+				t.Errorf("\n%s\nparsePortMapping(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServicePortToContainerPort(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 443, TargetPort: intstr.FromString("https")},
+				{Port: 9090},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: 8443}},
+			}},
+		},
+	}
+	type want struct {
+		port int32
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		port   int32
+		want   want
+	}{
+		"NumericTarget": {
+			reason: "A numeric target port should be returned.",
+			port:   80,
+			want:   want{port: 8080},
+		},
+		"NamedTarget": {
+			reason: "A named target port should be resolved from the pod.",
+			port:   443,
+			want:   want{port: 8443},
+		},
+		"NoTarget": {
+			reason: "The service port should be used if no target port is set.",
+			port:   9090,
+			want:   want{port: 9090},
+		},
+		"UnknownPort": {
+			reason: "An error should be returned if the service has no such port.",
+			port:   8000,
+			want:   want{err: errors.Errorf(errFmtServicePort, "api", 8000)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			port, err := servicePortToContainerPort(svc, pod, tc.port)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nservicePortToContainerPort(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.port, port); diff != "" {
+				t.Errorf("\n%s\nservicePortToContainerPort(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
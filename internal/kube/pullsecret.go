@@ -0,0 +1,81 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/cmd/create"
+)
+
+const errListPullSecrets = "unable to list image pull secrets"
+
+// FindPullSecrets returns the image pull Secrets in all namespaces that
+// authenticate as any of the supplied users, sorted by namespace and name.
+// Secrets that cannot be parsed are ignored.
+func FindPullSecrets(ctx context.Context, client kubernetes.Interface, users ...string) ([]types.NamespacedName, error) {
+	want := make(map[string]bool, len(users))
+	for _, u := range users {
+		want[u] = true
+	}
+	l, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeDockerConfigJson)).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errListPullSecrets)
+	}
+	found := []types.NamespacedName{}
+	for _, s := range l.Items {
+		// The type field selector is not supported by all clients.
+		if s.Type != corev1.SecretTypeDockerConfigJson {
+			continue
+		}
+		cfg := &create.DockerConfigJSON{}
+		if err := json.Unmarshal(s.Data[corev1.DockerConfigJsonKey], cfg); err != nil {
+			continue
+		}
+		for _, e := range cfg.Auths {
+			if want[e.Username] || want[decodeDockerConfigFieldAuthUser(e.Auth)] {
+				found = append(found, types.NamespacedName{Namespace: s.Namespace, Name: s.Name})
+				break
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].String() < found[j].String()
+	})
+	return found, nil
+}
+
+// decodeDockerConfigFieldAuthUser returns the username encoded in the auth
+// field of a docker config entry, or an empty string if it cannot be decoded.
+func decodeDockerConfigFieldAuthUser(auth string) string {
+	b, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return ""
+	}
+	user, _, _ := strings.Cut(string(b), ":")
+	return user
+}
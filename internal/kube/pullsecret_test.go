@@ -0,0 +1,59 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func pullSecret(ns, name, cfg string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(cfg)},
+	}
+}
+
+func TestFindPullSecrets(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		pullSecret("upbound-system", "by-username", `{"auths":{"xpkg.upbound.io":{"username":"robot-token"}}}`),
+		// cm9ib3QtdG9rZW46c2VjcmV0 is robot-token:secret.
+		pullSecret("crossplane-system", "by-auth", `{"auths":{"xpkg.upbound.io":{"auth":"cm9ib3QtdG9rZW46c2VjcmV0"}}}`),
+		pullSecret("upbound-system", "other-user", `{"auths":{"xpkg.upbound.io":{"username":"someone-else"}}}`),
+		pullSecret("upbound-system", "invalid", `{`),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "upbound-system", Name: "opaque"},
+			Data:       map[string][]byte{"username": []byte("robot-token")},
+		},
+	)
+	got, err := FindPullSecrets(context.Background(), client, "robot-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.NamespacedName{
+		{Namespace: "crossplane-system", Name: "by-auth"},
+		{Namespace: "upbound-system", Name: "by-username"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FindPullSecrets(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// ReadOnlyNamespace is the namespace of read-only ServiceAccounts.
+	ReadOnlyNamespace = "kube-system"
+
+	clusterInfoName = "cluster-info"
+	clusterInfoKey  = "kubeconfig"
+
+	// managedByLabel marks the ServiceAccounts and ClusterRoleBindings that up
+	// provisions, so that objects of the same name that were created by
+	// someone else are never modified.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByUp    = "up"
+
+	errCreateReadOnlyAccount = "unable to create read-only service account"
+	errBindReadOnlyRoles     = "unable to bind read-only roles"
+	errCreateReadOnlyToken   = "unable to create read-only token"
+	errGetClusterInfo        = "unable to get the cluster-info config map"
+	errParseClusterInfo      = "unable to parse the kubeconfig of the cluster-info config map"
+	errNoClusterInfo         = "the cluster-info config map does not name an API server"
+	errGetReadOnlyAccount    = "unable to get read-only service account"
+	errFmtNotManaged         = "%s %q already exists and is not managed by up, delete it or choose another name with --read-only-name"
+)
+
+// ReadOnlyClusterRoles are the ClusterRoles bound to read-only
+// ServiceAccounts. view grants read access to namespaced resources except
+// Secrets, while crossplane-view grants read access to Crossplane resources.
+var ReadOnlyClusterRoles = []string{"view", "crossplane-view"}
+
+// A ReadOnlyToken is a token of a read-only ServiceAccount.
+type ReadOnlyToken struct {
+	Token  string
+	Expiry time.Time
+}
+
+// ProvisionReadOnlyToken creates a ServiceAccount with the supplied name that
+// is bound to the read-only ClusterRoles and requests a token for it through
+// the TokenRequest API that expires after the supplied duration. Existing
+// ServiceAccounts that up provisioned are reused, and existing bindings that up
+// provisioned are corrected if they bind other subjects or roles. Objects of
+// the same name that up did not provision are left alone and an error is
+// returned. Access is revoked when the token expires or the ServiceAccount is
+// deleted.
+func ProvisionReadOnlyToken(ctx context.Context, client kubernetes.Interface, name string, expiry time.Duration) (*ReadOnlyToken, error) {
+	sas := client.CoreV1().ServiceAccounts(ReadOnlyNamespace)
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ReadOnlyNamespace, Labels: managedLabels()}}
+	_, err := sas.Create(ctx, sa, metav1.CreateOptions{})
+	switch {
+	case kerrors.IsAlreadyExists(err):
+		got, err := sas.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, errGetReadOnlyAccount)
+		}
+		if !managed(got.ObjectMeta) {
+			return nil, errors.Errorf(errFmtNotManaged, "ServiceAccount", name)
+		}
+	case err != nil:
+		return nil, errors.Wrap(err, errCreateReadOnlyAccount)
+	}
+	for _, role := range ReadOnlyClusterRoles {
+		crb := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name + ":" + role, Labels: managedLabels()},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: ReadOnlyNamespace}},
+		}
+		if err := ensureClusterRoleBinding(ctx, client, crb); err != nil {
+			return nil, errors.Wrap(err, errBindReadOnlyRoles)
+		}
+	}
+	secs := int64(expiry.Seconds())
+	tr := &authv1.TokenRequest{Spec: authv1.TokenRequestSpec{ExpirationSeconds: &secs}}
+	tr, err = sas.CreateToken(ctx, name, tr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateReadOnlyToken)
+	}
+	return &ReadOnlyToken{Token: tr.Status.Token, Expiry: tr.Status.ExpirationTimestamp.Time}, nil
+}
+
+// ensureClusterRoleBinding creates the supplied ClusterRoleBinding, or makes
+// an existing one of the same name bind the same role to the same subjects.
+// The role of a binding cannot be changed, so a binding of another role is
+// replaced. Existing bindings that up did not provision are never modified.
+func ensureClusterRoleBinding(ctx context.Context, client kubernetes.Interface, want *rbacv1.ClusterRoleBinding) error {
+	crbs := client.RbacV1().ClusterRoleBindings()
+	got, err := crbs.Get(ctx, want.Name, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		_, err = crbs.Create(ctx, want, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	case !managed(got.ObjectMeta):
+		return errors.Errorf(errFmtNotManaged, "ClusterRoleBinding", got.Name)
+	case got.RoleRef != want.RoleRef:
+		if err := crbs.Delete(ctx, got.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &got.UID}}); err != nil {
+			return err
+		}
+		_, err = crbs.Create(ctx, want, metav1.CreateOptions{})
+		return err
+	case !equality.Semantic.DeepEqual(got.Subjects, want.Subjects):
+		got.Subjects = want.Subjects
+		_, err = crbs.Update(ctx, got, metav1.UpdateOptions{})
+		return err
+	}
+	return nil
+}
+
+// ClusterInfo returns the API server of the cluster, as published in the
+// cluster-info ConfigMap of the kube-public namespace.
+func ClusterInfo(ctx context.Context, client kubernetes.Interface) (*api.Cluster, error) {
+	cm, err := client.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(ctx, clusterInfoName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errGetClusterInfo)
+	}
+	conf, err := clientcmd.Load([]byte(cm.Data[clusterInfoKey]))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseClusterInfo)
+	}
+	names := make([]string, 0, len(conf.Clusters))
+	for n, c := range conf.Clusters {
+		if c.Server != "" {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.New(errNoClusterInfo)
+	}
+	sort.Strings(names)
+	return conf.Clusters[names[0]], nil
+}
+
+// managedLabels returns the labels of objects that up provisions.
+func managedLabels() map[string]string {
+	return map[string]string{managedByLabel: managedByUp}
+}
+
+// managed returns true if the object was provisioned by up.
+func managed(o metav1.ObjectMeta) bool {
+	return o.Labels[managedByLabel] == managedByUp
+}
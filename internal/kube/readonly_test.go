@@ -0,0 +1,231 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestProvisionReadOnlyToken(t *testing.T) {
+	errBoom := errors.New("boom")
+	expiry := time.Date(2023, 5, 4, 3, 2, 1, 0, time.UTC)
+	subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "auditors", Namespace: ReadOnlyNamespace}
+	binding := func(role string, subjects ...rbacv1.Subject) rbacv1.ClusterRoleBinding {
+		return rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "auditors:" + role, Labels: managedLabels()},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+			Subjects:   subjects,
+		}
+	}
+	// withTokens makes the client issue tokens through the TokenRequest API.
+	withTokens := func(c *fake.Clientset) *fake.Clientset {
+		c.PrependReactor("create", "serviceaccounts", func(a ktesting.Action) (bool, runtime.Object, error) {
+			if a.GetSubresource() != "token" {
+				return false, nil, nil
+			}
+			tr := a.(ktesting.CreateAction).GetObject().(*authv1.TokenRequest).DeepCopy()
+			if tr.Spec.ExpirationSeconds == nil || *tr.Spec.ExpirationSeconds != 3600 {
+				return true, nil, errors.New("unexpected expiry")
+			}
+			tr.Status = authv1.TokenRequestStatus{Token: "viewer", ExpirationTimestamp: metav1.NewTime(expiry)}
+			return true, tr, nil
+		})
+		return c
+	}
+	wrongSubject := binding("view", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "someone"})
+	wrongRole := binding("view", subject)
+	wrongRole.RoleRef.Name = "edit"
+	unmanaged := binding("view", rbacv1.Subject{Kind: rbacv1.UserKind, Name: "someone"})
+	unmanaged.Labels = nil
+	unmanagedSA := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "auditors", Namespace: ReadOnlyNamespace}}
+
+	type want struct {
+		token    *ReadOnlyToken
+		bindings []rbacv1.ClusterRoleBinding
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		client *fake.Clientset
+		want   want
+	}{
+		"Issued": {
+			reason: "A token that expires should be requested after binding the read-only roles.",
+			client: withTokens(fake.NewSimpleClientset()),
+			want: want{
+				token:    &ReadOnlyToken{Token: "viewer", Expiry: expiry},
+				bindings: []rbacv1.ClusterRoleBinding{binding("crossplane-view", subject), binding("view", subject)},
+			},
+		},
+		"CorrectSubjects": {
+			reason: "An existing binding of other subjects should be changed to bind the ServiceAccount.",
+			client: withTokens(fake.NewSimpleClientset(&wrongSubject)),
+			want: want{
+				token:    &ReadOnlyToken{Token: "viewer", Expiry: expiry},
+				bindings: []rbacv1.ClusterRoleBinding{binding("crossplane-view", subject), binding("view", subject)},
+			},
+		},
+		"ReplaceRole": {
+			reason: "An existing binding of another role should be replaced, as its role cannot be changed.",
+			client: withTokens(fake.NewSimpleClientset(&wrongRole)),
+			want: want{
+				token:    &ReadOnlyToken{Token: "viewer", Expiry: expiry},
+				bindings: []rbacv1.ClusterRoleBinding{binding("crossplane-view", subject), binding("view", subject)},
+			},
+		},
+		"UnmanagedBinding": {
+			reason: "An existing binding that up did not provision should not be modified.",
+			client: withTokens(fake.NewSimpleClientset(&unmanaged)),
+			want: want{
+				bindings: []rbacv1.ClusterRoleBinding{unmanaged},
+				err:      errors.Wrap(errors.Errorf(errFmtNotManaged, "ClusterRoleBinding", "auditors:view"), errBindReadOnlyRoles),
+			},
+		},
+		"UnmanagedServiceAccount": {
+			reason: "An existing ServiceAccount that up did not provision should not be bound.",
+			client: withTokens(fake.NewSimpleClientset(unmanagedSA)),
+			want: want{
+				bindings: []rbacv1.ClusterRoleBinding{},
+				err:      errors.Errorf(errFmtNotManaged, "ServiceAccount", "auditors"),
+			},
+		},
+		"ErrCreateServiceAccount": {
+			reason: "Errors creating the ServiceAccount should be returned.",
+			client: func() *fake.Clientset {
+				c := fake.NewSimpleClientset()
+				c.PrependReactor("create", "serviceaccounts", func(ktesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errBoom
+				})
+				return c
+			}(),
+			want: want{
+				bindings: []rbacv1.ClusterRoleBinding{},
+				err:      errors.Wrap(errBoom, errCreateReadOnlyAccount),
+			},
+		},
+		"ErrCreateToken": {
+			reason: "Errors requesting the token should be returned.",
+			client: func() *fake.Clientset {
+				c := fake.NewSimpleClientset()
+				c.PrependReactor("create", "serviceaccounts", func(a ktesting.Action) (bool, runtime.Object, error) {
+					return a.GetSubresource() == "token", nil, errBoom
+				})
+				return c
+			}(),
+			want: want{
+				bindings: []rbacv1.ClusterRoleBinding{binding("crossplane-view", subject), binding("view", subject)},
+				err:      errors.Wrap(errBoom, errCreateReadOnlyToken),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			token, err := ProvisionReadOnlyToken(ctx, tc.client, "auditors", time.Hour)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProvisionReadOnlyToken(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, token); diff != "" {
+				t.Errorf("\n%s\nProvisionReadOnlyToken(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+			l, _ := tc.client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+			bindings := []rbacv1.ClusterRoleBinding{}
+			for _, crb := range l.Items {
+				bindings = append(bindings, rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: crb.Name, Labels: crb.Labels}, RoleRef: crb.RoleRef, Subjects: crb.Subjects})
+			}
+			if diff := cmp.Diff(tc.want.bindings, bindings); diff != "" {
+				t.Errorf("\n%s\nProvisionReadOnlyToken(...): -want bindings, +got bindings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClusterInfo(t *testing.T) {
+	clusterInfo := func(kubeconfig string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterInfoName, Namespace: metav1.NamespacePublic},
+			Data:       map[string]string{clusterInfoKey: kubeconfig},
+		}
+	}
+
+	type want struct {
+		cluster *api.Cluster
+		err     bool
+	}
+	cases := map[string]struct {
+		reason string
+		client *fake.Clientset
+		want   want
+	}{
+		"Published": {
+			reason: "The API server published in cluster-info should be returned.",
+			client: fake.NewSimpleClientset(clusterInfo(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: ""
+  cluster:
+    server: https://10.0.0.1:6443
+    certificate-authority-data: Y2E=
+`)),
+			want: want{
+				cluster: &api.Cluster{Server: "https://10.0.0.1:6443", CertificateAuthorityData: []byte("ca")},
+			},
+		},
+		"NoServer": {
+			reason: "An error should be returned if cluster-info does not name an API server.",
+			client: fake.NewSimpleClientset(clusterInfo("apiVersion: v1\nkind: Config\n")),
+			want: want{
+				err: true,
+			},
+		},
+		"NotPublished": {
+			reason: "An error should be returned if the control plane does not publish cluster-info.",
+			client: fake.NewSimpleClientset(),
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cluster, err := ClusterInfo(context.Background(), tc.client)
+			if (err != nil) != tc.want.err {
+				t.Errorf("\n%s\nClusterInfo(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			var got *api.Cluster
+			if cluster != nil {
+				got = &api.Cluster{Server: cluster.Server, CertificateAuthorityData: cluster.CertificateAuthorityData}
+			}
+			if diff := cmp.Diff(tc.want.cluster, got); diff != "" {
+				t.Errorf("\n%s\nClusterInfo(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TokenAccessIDKey is the key of the access ID in a robot token Secret.
+	TokenAccessIDKey = "accessId"
+	// TokenKey is the key of the token in a robot token Secret.
+	TokenKey = "token"
+
+	// Environment variables from which up and Upbound agents read a robot
+	// token.
+	accessIDEnv = "UP_ACCESS_ID"
+	tokenEnv    = "UP_TOKEN"
+
+	yamlSeparator = "---\n"
+)
+
+// TokenBundle describes the objects needed to supply a robot token to an
+// agent running in a Kubernetes cluster.
+type TokenBundle struct {
+	// Namespace of every object in the bundle.
+	Namespace string
+	// SecretName is the name of the Secret that holds the token.
+	SecretName string
+	// ServiceAccount is the name of a ServiceAccount for the agent. It is
+	// omitted from the bundle if empty.
+	ServiceAccount string
+	// Deployment is the name of the Deployment of the agent. It is omitted
+	// from the bundle if empty.
+	Deployment string
+	// Container is the name of the container of the Deployment that reads
+	// the token. The name of the Deployment is used if empty.
+	Container string
+}
+
+// Manifests builds the Secret holding the supplied token and, if configured,
+// the ServiceAccount of the agent and a partial Deployment that exposes the
+// Secret to the agent as environment variables. The Deployment is meant to be
+// applied with server-side apply to an existing Deployment.
+func (b TokenBundle) Manifests(accessID, token string) []any {
+	objs := []any{
+		corev1ac.Secret(b.SecretName, b.Namespace).
+			WithType(corev1.SecretTypeOpaque).
+			WithStringData(map[string]string{
+				TokenAccessIDKey: accessID,
+				TokenKey:         token,
+			}),
+	}
+	if b.ServiceAccount != "" {
+		objs = append(objs, corev1ac.ServiceAccount(b.ServiceAccount, b.Namespace))
+	}
+	if b.Deployment == "" {
+		return objs
+	}
+	container := b.Container
+	if container == "" {
+		container = b.Deployment
+	}
+	pod := corev1ac.PodSpec().WithContainers(corev1ac.Container().
+		WithName(container).
+		WithEnv(b.secretEnv(accessIDEnv, TokenAccessIDKey), b.secretEnv(tokenEnv, TokenKey)))
+	if b.ServiceAccount != "" {
+		pod = pod.WithServiceAccountName(b.ServiceAccount)
+	}
+	return append(objs, appsv1ac.Deployment(b.Deployment, b.Namespace).
+		WithSpec(appsv1ac.DeploymentSpec().
+			WithTemplate(corev1ac.PodTemplateSpec().WithSpec(pod))))
+}
+
+func (b TokenBundle) secretEnv(name, key string) *corev1ac.EnvVarApplyConfiguration {
+	return corev1ac.EnvVar().
+		WithName(name).
+		WithValueFrom(corev1ac.EnvVarSource().
+			WithSecretKeyRef(corev1ac.SecretKeySelector().
+				WithName(b.SecretName).
+				WithKey(key)))
+}
+
+// WriteManifests writes the supplied objects to w as a multi-document YAML
+// stream.
+func WriteManifests(w io.Writer, objs ...any) error {
+	for i, o := range objs {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, yamlSeparator); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
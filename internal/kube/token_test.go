@@ -0,0 +1,135 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenBundle(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		bundle TokenBundle
+		want   string
+	}{
+		"Secret": {
+			reason: "Only the Secret should be built if no ServiceAccount or Deployment is supplied.",
+			bundle: TokenBundle{Namespace: "agents", SecretName: "up-token"},
+			want: `apiVersion: v1
+kind: Secret
+metadata:
+  name: up-token
+  namespace: agents
+stringData:
+  accessId: access
+  token: secret
+type: Opaque
+`,
+		},
+		"Deployment": {
+			reason: "The Deployment should read the token from the Secret in its container.",
+			bundle: TokenBundle{Namespace: "agents", SecretName: "up-token", Deployment: "agent", Container: "main"},
+			want: `apiVersion: v1
+kind: Secret
+metadata:
+  name: up-token
+  namespace: agents
+stringData:
+  accessId: access
+  token: secret
+type: Opaque
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: agent
+  namespace: agents
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: UP_ACCESS_ID
+          valueFrom:
+            secretKeyRef:
+              key: accessId
+              name: up-token
+        - name: UP_TOKEN
+          valueFrom:
+            secretKeyRef:
+              key: token
+              name: up-token
+        name: main
+`,
+		},
+		"ServiceAccount": {
+			reason: "The ServiceAccount should be built and bound to the Deployment, whose name is used for its container.",
+			bundle: TokenBundle{Namespace: "agents", SecretName: "up-token", ServiceAccount: "agent", Deployment: "agent"},
+			want: `apiVersion: v1
+kind: Secret
+metadata:
+  name: up-token
+  namespace: agents
+stringData:
+  accessId: access
+  token: secret
+type: Opaque
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: agent
+  namespace: agents
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: agent
+  namespace: agents
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: UP_ACCESS_ID
+          valueFrom:
+            secretKeyRef:
+              key: accessId
+              name: up-token
+        - name: UP_TOKEN
+          valueFrom:
+            secretKeyRef:
+              key: token
+              name: up-token
+        name: agent
+      serviceAccountName: agent
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			if err := WriteManifests(b, tc.bundle.Manifests("access", "secret")...); err != nil {
+				t.Fatalf("\n%s\nWriteManifests(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("\n%s\nManifests(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ type Provider interface {
 func NewProvider(modifiers ...ProviderModifierFn) *DMV {
 
 	p := &DMV{
-		client: &http.Client{},
+		client: uphttp.NewClient(),
 	}
 
 	for _, m := range modifiers {
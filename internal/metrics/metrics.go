@@ -0,0 +1,271 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics summarizes the health of controllers from their Prometheus
+// metrics.
+package metrics
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Metrics exposed by controller-runtime and client-go.
+const (
+	reconcileTotal           = "controller_runtime_reconcile_total"
+	reconcileErrorsTotal     = "controller_runtime_reconcile_errors_total"
+	workqueueDepth           = "workqueue_depth"
+	restClientRequests       = "rest_client_requests_total"
+	restClientRequestLatency = "rest_client_request_duration_seconds"
+
+	labelController = "controller"
+	labelResult     = "result"
+	labelName       = "name"
+	labelCode       = "code"
+
+	resultError = "error"
+
+	errParseMetrics = "unable to parse metrics"
+)
+
+// A Sample of the metrics of a component at a point in time.
+type Sample struct {
+	Time     time.Time
+	families map[string]*dto.MetricFamily
+}
+
+// Parse parses metrics in the Prometheus text format, as scraped at the
+// supplied time.
+func Parse(r io.Reader, t time.Time) (*Sample, error) {
+	var p expfmt.TextParser
+	f, err := p.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseMetrics)
+	}
+	return &Sample{Time: t, families: f}, nil
+}
+
+// ControllerHealth are the health indicators of a controller.
+type ControllerHealth struct {
+	Controller string
+	// ReconcileRate and ErrorRate are per second.
+	ReconcileRate float64
+	ErrorRate     float64
+	// Errors is the total number of reconcile errors since the component
+	// started.
+	Errors     float64
+	QueueDepth float64
+}
+
+// APIHealth are the health indicators of requests to the Kubernetes API.
+type APIHealth struct {
+	// RequestRate and ErrorRate are per second. Errors are requests that
+	// failed or were answered with a 5xx status.
+	RequestRate float64
+	ErrorRate   float64
+	MeanLatency time.Duration
+	P99Latency  time.Duration
+}
+
+// Health of a component.
+type Health struct {
+	Controllers []ControllerHealth
+	API         APIHealth
+}
+
+// Summarize returns the health of a component from two samples of its
+// metrics. Rates and latencies are those observed between the samples.
+func Summarize(prev, cur *Sample) Health {
+	secs := cur.Time.Sub(prev.Time).Seconds()
+	rate := func(d float64) float64 {
+		if secs <= 0 {
+			return 0
+		}
+		return d / secs
+	}
+
+	isError := func(l map[string]string) bool { return l[labelResult] == resultError }
+	reconciles := delta(counters(prev, reconcileTotal, labelController, nil), counters(cur, reconcileTotal, labelController, nil))
+	errs := delta(counters(prev, reconcileTotal, labelController, isError), counters(cur, reconcileTotal, labelController, isError))
+	totalErrs := counters(cur, reconcileErrorsTotal, labelController, nil)
+	depth := gauges(cur, workqueueDepth, labelName)
+
+	names := map[string]bool{}
+	for _, m := range []map[string]float64{reconciles, totalErrs} {
+		for n := range m {
+			names[n] = true
+		}
+	}
+	h := Health{Controllers: make([]ControllerHealth, 0, len(names))}
+	for n := range names {
+		h.Controllers = append(h.Controllers, ControllerHealth{
+			Controller:    n,
+			ReconcileRate: rate(reconciles[n]),
+			ErrorRate:     rate(errs[n]),
+			Errors:        totalErrs[n],
+			QueueDepth:    depth[n],
+		})
+	}
+	sort.Slice(h.Controllers, func(i, j int) bool { return h.Controllers[i].Controller < h.Controllers[j].Controller })
+
+	isAPIError := func(l map[string]string) bool {
+		return strings.HasPrefix(l[labelCode], "5") || l[labelCode] == "<error>"
+	}
+	h.API.RequestRate = rate(sum(counters(cur, restClientRequests, "", nil)) - sum(counters(prev, restClientRequests, "", nil)))
+	h.API.ErrorRate = rate(sum(counters(cur, restClientRequests, "", isAPIError)) - sum(counters(prev, restClientRequests, "", isAPIError)))
+
+	hp, hc := histogram(prev, restClientRequestLatency), histogram(cur, restClientRequestLatency)
+	if n := hc.count - hp.count; n > 0 {
+		h.API.MeanLatency = seconds((hc.sum - hp.sum) / n)
+		h.API.P99Latency = seconds(quantile(0.99, hc.sub(hp)))
+	}
+	return h
+}
+
+// counters sums the values of the counters of the named family, grouped by
+// the supplied label, that match the supplied filter. All counters are
+// grouped under the empty string if the label is empty.
+func counters(s *Sample, name, label string, filter func(map[string]string) bool) map[string]float64 {
+	out := map[string]float64{}
+	f, ok := s.families[name]
+	if !ok {
+		return out
+	}
+	for _, m := range f.GetMetric() {
+		l := labels(m)
+		if filter != nil && !filter(l) {
+			continue
+		}
+		out[l[label]] += m.GetCounter().GetValue()
+	}
+	return out
+}
+
+// gauges sums the values of the gauges of the named family, grouped by the
+// supplied label.
+func gauges(s *Sample, name, label string) map[string]float64 {
+	out := map[string]float64{}
+	f, ok := s.families[name]
+	if !ok {
+		return out
+	}
+	for _, m := range f.GetMetric() {
+		out[labels(m)[label]] += m.GetGauge().GetValue()
+	}
+	return out
+}
+
+func labels(m *dto.Metric) map[string]string {
+	l := make(map[string]string, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		l[lp.GetName()] = lp.GetValue()
+	}
+	return l
+}
+
+// delta returns the difference of each counter between two samples. Counters
+// that were reset in between are considered to have started from zero.
+func delta(prev, cur map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(cur))
+	for k, v := range cur {
+		d := v - prev[k]
+		if d < 0 {
+			d = v
+		}
+		out[k] = d
+	}
+	return out
+}
+
+func sum(m map[string]float64) float64 {
+	s := 0.0
+	for _, v := range m {
+		s += v
+	}
+	return s
+}
+
+// hist is a histogram aggregated across series.
+type hist struct {
+	count float64
+	sum   float64
+	// buckets maps upper bounds to cumulative counts.
+	buckets map[float64]float64
+}
+
+func histogram(s *Sample, name string) hist {
+	h := hist{buckets: map[float64]float64{}}
+	f, ok := s.families[name]
+	if !ok {
+		return h
+	}
+	for _, m := range f.GetMetric() {
+		mh := m.GetHistogram()
+		h.count += float64(mh.GetSampleCount())
+		h.sum += mh.GetSampleSum()
+		for _, b := range mh.GetBucket() {
+			h.buckets[b.GetUpperBound()] += float64(b.GetCumulativeCount())
+		}
+	}
+	return h
+}
+
+// sub returns the observations of h that were not yet observed by o.
+func (h hist) sub(o hist) hist {
+	out := hist{count: h.count - o.count, sum: h.sum - o.sum, buckets: make(map[float64]float64, len(h.buckets))}
+	for ub, c := range h.buckets {
+		out.buckets[ub] = c - o.buckets[ub]
+	}
+	return out
+}
+
+// quantile estimates the q-quantile of the histogram by linear interpolation
+// within the bucket it falls into, like PromQL's histogram_quantile.
+func quantile(q float64, h hist) float64 {
+	bounds := make([]float64, 0, len(h.buckets))
+	for ub := range h.buckets {
+		bounds = append(bounds, ub)
+	}
+	sort.Float64s(bounds)
+	if len(bounds) == 0 || h.count <= 0 {
+		return 0
+	}
+	rank := q * h.count
+	lower, prevCount := 0.0, 0.0
+	for _, ub := range bounds {
+		c := h.buckets[ub]
+		if c >= rank {
+			if math.IsInf(ub, 1) {
+				return lower
+			}
+			if c == prevCount {
+				return ub
+			}
+			return lower + (ub-lower)*(rank-prevCount)/(c-prevCount)
+		}
+		lower, prevCount = ub, c
+	}
+	return lower
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const before = `# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="controlplane",result="success"} 100
+controller_runtime_reconcile_total{controller="controlplane",result="error"} 10
+controller_runtime_reconcile_total{controller="backup",result="success"} 5
+# TYPE controller_runtime_reconcile_errors_total counter
+controller_runtime_reconcile_errors_total{controller="controlplane"} 10
+controller_runtime_reconcile_errors_total{controller="backup"} 0
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",method="GET"} 1000
+rest_client_requests_total{code="500",method="GET"} 2
+# TYPE rest_client_request_duration_seconds histogram
+rest_client_request_duration_seconds_bucket{verb="GET",le="0.01"} 500
+rest_client_request_duration_seconds_bucket{verb="GET",le="0.1"} 900
+rest_client_request_duration_seconds_bucket{verb="GET",le="1"} 1000
+rest_client_request_duration_seconds_bucket{verb="GET",le="+Inf"} 1000
+rest_client_request_duration_seconds_sum{verb="GET"} 20
+rest_client_request_duration_seconds_count{verb="GET"} 1000
+`
+
+const after = `# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="controlplane",result="success"} 140
+controller_runtime_reconcile_total{controller="controlplane",result="error"} 20
+controller_runtime_reconcile_total{controller="backup",result="success"} 5
+# TYPE controller_runtime_reconcile_errors_total counter
+controller_runtime_reconcile_errors_total{controller="controlplane"} 20
+controller_runtime_reconcile_errors_total{controller="backup"} 0
+# TYPE workqueue_depth gauge
+workqueue_depth{name="controlplane"} 7
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",method="GET"} 1090
+rest_client_requests_total{code="500",method="GET"} 12
+# TYPE rest_client_request_duration_seconds histogram
+rest_client_request_duration_seconds_bucket{verb="GET",le="0.01"} 500
+rest_client_request_duration_seconds_bucket{verb="GET",le="0.1"} 900
+rest_client_request_duration_seconds_bucket{verb="GET",le="1"} 1100
+rest_client_request_duration_seconds_bucket{verb="GET",le="+Inf"} 1100
+rest_client_request_duration_seconds_sum{verb="GET"} 70
+rest_client_request_duration_seconds_count{verb="GET"} 1100
+`
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	prev, err := Parse(strings.NewReader(before), now)
+	if err != nil {
+		t.Fatalf("Parse(...): %v", err)
+	}
+	cur, err := Parse(strings.NewReader(after), now.Add(10*time.Second))
+	if err != nil {
+		t.Fatalf("Parse(...): %v", err)
+	}
+
+	want := Health{
+		Controllers: []ControllerHealth{
+			{Controller: "backup"},
+			{Controller: "controlplane", ReconcileRate: 5, ErrorRate: 1, Errors: 20, QueueDepth: 7},
+		},
+		API: APIHealth{
+			RequestRate: 10,
+			ErrorRate:   1,
+			// 50s over 100 requests.
+			MeanLatency: 500 * time.Millisecond,
+			// All 100 requests fell into the 0.1-1s bucket.
+			P99Latency: 991 * time.Millisecond,
+		},
+	}
+	if diff := cmp.Diff(want, Summarize(prev, cur)); diff != "" {
+		t.Errorf("\nSummarize(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		q      float64
+		h      hist
+		want   float64
+	}{
+		"Empty": {
+			reason: "The quantile of an empty histogram should be zero.",
+			q:      0.99,
+			h:      hist{buckets: map[float64]float64{}},
+		},
+		"Interpolated": {
+			reason: "The quantile should be interpolated within its bucket.",
+			q:      0.5,
+			h:      hist{count: 100, buckets: map[float64]float64{1: 40, 2: 60}},
+			want:   1.5,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, quantile(tc.q, tc.h)); diff != "" {
+				t.Errorf("\n%s\nquantile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
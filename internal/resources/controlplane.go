@@ -0,0 +1,126 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// ControlPlaneGVK is the GroupVersionKind of a control plane in a Space.
+	ControlPlaneGVK = schema.GroupVersionKind{
+		Group:   "spaces.upbound.io",
+		Version: "v1alpha1",
+		Kind:    "ControlPlane",
+	}
+	// ControlPlaneGVR is the GroupVersionResource of a control plane in a
+	// Space.
+	ControlPlaneGVR = schema.GroupVersionResource{
+		Group:    ControlPlaneGVK.Group,
+		Version:  ControlPlaneGVK.Version,
+		Resource: "controlplanes",
+	}
+)
+
+// States of the Crossplane of a control plane.
+const (
+	// CrossplaneStateRunning is the state of a control plane whose
+	// Crossplane and providers are running.
+	CrossplaneStateRunning = "Running"
+	// CrossplaneStatePaused is the state of a control plane whose Crossplane
+	// and providers are scaled down, so that nothing is reconciled.
+	CrossplaneStatePaused = "Paused"
+)
+
+// CostCenterLabel is the label that assigns a control plane to the cost
+// center its usage is charged to.
+const CostCenterLabel = "upbound.io/cost-center"
+
+// ControlPlane represents the ControlPlane CustomResource of a Space and
+// extends an unstructured.Unstructured.
+type ControlPlane struct {
+	unstructured.Unstructured
+}
+
+// GetUnstructured returns the underlying *unstructured.Unstructured.
+func (c *ControlPlane) GetUnstructured() *unstructured.Unstructured {
+	return &c.Unstructured
+}
+
+// GetCondition returns the condition for the given xpv1.ConditionType if it
+// exists, otherwise returns an empty condition.
+func (c *ControlPlane) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	conditioned := xpv1.ConditionedStatus{}
+	// The path is directly `status` because conditions are inline.
+	if err := fieldpath.Pave(c.Object).GetValueInto("status", &conditioned); err != nil {
+		return xpv1.Condition{}
+	}
+	return conditioned.GetCondition(ct)
+}
+
+// GetCrossplaneVersion returns the Crossplane version of the control plane,
+// or an empty string if the Space default is used.
+func (c *ControlPlane) GetCrossplaneVersion() string {
+	v, _ := fieldpath.Pave(c.Object).GetString("spec.crossplane.version")
+	return v
+}
+
+// SetCrossplaneVersion sets the Crossplane version of the control plane.
+func (c *ControlPlane) SetCrossplaneVersion(v string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.crossplane.version", v)
+}
+
+// GetClass returns the resource class of the control plane, or an empty
+// string if the Space default is used.
+func (c *ControlPlane) GetClass() string {
+	v, _ := fieldpath.Pave(c.Object).GetString("spec.class")
+	return v
+}
+
+// SetClass sets the resource class of the control plane.
+func (c *ControlPlane) SetClass(class string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.class", class)
+}
+
+// GetConfigurationPackage returns the Configuration package the Space installs
+// in the control plane, or an empty string if there is none.
+func (c *ControlPlane) GetConfigurationPackage() string {
+	v, _ := fieldpath.Pave(c.Object).GetString("spec.configuration.package")
+	return v
+}
+
+// SetConfigurationPackage sets the Configuration package the Space installs
+// in the control plane.
+func (c *ControlPlane) SetConfigurationPackage(pkg string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.configuration.package", pkg)
+}
+
+// GetCrossplaneState returns the state of the Crossplane of the control plane.
+// Crossplane is running if no state is set.
+func (c *ControlPlane) GetCrossplaneState() string {
+	v, _ := fieldpath.Pave(c.Object).GetString("spec.crossplane.state")
+	if v == "" {
+		return CrossplaneStateRunning
+	}
+	return v
+}
+
+// SetCrossplaneState sets the state of the Crossplane of the control plane.
+func (c *ControlPlane) SetCrossplaneState(state string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.crossplane.state", state)
+}
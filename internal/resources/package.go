@@ -54,6 +54,24 @@ func (p *Package) GetHealthy() bool {
 	return resource.IsConditionTrue(conditioned.GetCondition("Healthy"))
 }
 
+// GetConditions returns the conditions of the package. If the conditions
+// cannot be determined, none are returned.
+func (p *Package) GetConditions() []xpv1.Condition {
+	conditioned := xpv1.ConditionedStatus{}
+	// The path is directly `status` because conditions are inline.
+	if err := fieldpath.Pave(p.Object).GetValueInto("status", &conditioned); err != nil {
+		return nil
+	}
+	return conditioned.Conditions
+}
+
+// GetCurrentRevision returns the name of the current revision of the
+// package, or an empty string if there is none.
+func (p *Package) GetCurrentRevision() string {
+	rev, _ := fieldpath.Pave(p.Object).GetString("status.currentRevision")
+	return rev
+}
+
 // SetPackage sets the package reference.
 func (p *Package) SetPackage(pkg string) {
 	_ = fieldpath.Pave(p.Object).SetValue("spec.package", pkg)
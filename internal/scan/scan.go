@@ -0,0 +1,149 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scan finds known vulnerabilities in the images of rendered
+// manifests.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	errDecodeManifests = "unable to decode manifests"
+	errFmtSeverity     = "unknown severity %q: must be one of LOW, MEDIUM, HIGH, or CRITICAL"
+	errFmtScanImage    = "unable to scan image %s"
+)
+
+// Severity is the severity of a vulnerability.
+type Severity string
+
+// Severities of vulnerabilities, ordered from lowest to highest.
+const (
+	SeverityUnknown  Severity = "UNKNOWN"
+	SeverityLow      Severity = "LOW"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityHigh     Severity = "HIGH"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+var severities = []Severity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// ParseSeverity parses a severity threshold.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToUpper(s))
+	for _, v := range severities[1:] {
+		if sev == v {
+			return sev, nil
+		}
+	}
+	return "", errors.Errorf(errFmtSeverity, s)
+}
+
+// AtLeast returns the severities that are at least as high as the supplied
+// one.
+func AtLeast(s Severity) []Severity {
+	for i, v := range severities {
+		if v == s {
+			return severities[i:]
+		}
+	}
+	return nil
+}
+
+// A Vulnerability found in an image.
+type Vulnerability struct {
+	ID               string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         Severity
+	Title            string
+}
+
+// A Scanner finds vulnerabilities of the supplied severities in an image.
+type Scanner interface {
+	Scan(ctx context.Context, image string, severities []Severity) ([]Vulnerability, error)
+}
+
+// Result are the vulnerabilities found in an image.
+type Result struct {
+	Image           string
+	Vulnerabilities []Vulnerability
+}
+
+// Images scans each of the supplied images for vulnerabilities at or above
+// the threshold, returning the results of images with vulnerabilities.
+func Images(ctx context.Context, s Scanner, images []string, threshold Severity) ([]Result, error) {
+	res := []Result{}
+	for _, img := range images {
+		vulns, err := s.Scan(ctx, img, AtLeast(threshold))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtScanImage, img)
+		}
+		if len(vulns) > 0 {
+			res = append(res, Result{Image: img, Vulnerabilities: vulns})
+		}
+	}
+	return res, nil
+}
+
+// ImagesFromManifests returns the sorted, distinct container images
+// referenced by the supplied multi-document YAML manifests.
+func ImagesFromManifests(manifests []byte) ([]string, error) {
+	seen := map[string]bool{}
+	d := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var doc any
+		err := d.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errDecodeManifests)
+		}
+		collectImages(doc, false, seen)
+	}
+	images := make([]string, 0, len(seen))
+	for img := range seen {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// collectImages walks the supplied object and records the image of each
+// container, i.e. each object in a list of containers.
+func collectImages(obj any, container bool, seen map[string]bool) {
+	switch o := obj.(type) {
+	case map[string]any:
+		if img, ok := o["image"].(string); ok && container && img != "" {
+			seen[img] = true
+		}
+		for k, v := range o {
+			collectImages(v, k == "containers" || k == "initContainers" || k == "ephemeralContainers", seen)
+		}
+	case []any:
+		for _, v := range o {
+			collectImages(v, container, seen)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImagesFromManifests(t *testing.T) {
+	manifests := `apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: xpkg.upbound.io/spaces/init:v1.0.0
+      containers:
+      - name: controller
+        image: xpkg.upbound.io/spaces/controller:v1.0.0
+        env:
+        - name: image
+          value: ignored
+---
+apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      containers:
+      - name: hook
+        image: xpkg.upbound.io/spaces/controller:v1.0.0
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  versions:
+  - schema:
+      openAPIV3Schema:
+        properties:
+          image:
+            type: string
+`
+	type want struct {
+		images []string
+		err    error
+	}
+	cases := map[string]struct {
+		reason    string
+		manifests string
+		want      want
+	}{
+		"Images": {
+			reason:    "The distinct images of all containers should be returned.",
+			manifests: manifests,
+			want: want{
+				images: []string{"xpkg.upbound.io/spaces/controller:v1.0.0", "xpkg.upbound.io/spaces/init:v1.0.0"},
+			},
+		},
+		"Empty": {
+			reason: "No images should be returned for empty manifests.",
+			want: want{
+				images: []string{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			images, err := ImagesFromManifests([]byte(tc.manifests))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nImagesFromManifests(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.images, images); diff != "" {
+				t.Errorf("\n%s\nImagesFromManifests(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	type want struct {
+		atLeast []Severity
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"High": {
+			reason: "A threshold should include all higher severities.",
+			s:      "high",
+			want: want{
+				atLeast: []Severity{SeverityHigh, SeverityCritical},
+			},
+		},
+		"Low": {
+			reason: "The lowest threshold should not include unknown severities.",
+			s:      "LOW",
+			want: want{
+				atLeast: []Severity{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical},
+			},
+		},
+		"Unknown": {
+			reason: "Unknown severities should be rejected.",
+			s:      "severe",
+			want: want{
+				err: errors.Errorf(errFmtSeverity, "severe"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSeverity(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseSeverity(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.atLeast, AtLeast(s)); diff != "" {
+				t.Errorf("\n%s\nAtLeast(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type mockScanner struct {
+	vulns map[string][]Vulnerability
+	err   error
+}
+
+func (m *mockScanner) Scan(_ context.Context, image string, _ []Severity) ([]Vulnerability, error) {
+	return m.vulns[image], m.err
+}
+
+func TestImages(t *testing.T) {
+	errBoom := errors.New("boom")
+	critical := Vulnerability{ID: "CVE-2023-0001", Severity: SeverityCritical}
+
+	type want struct {
+		res []Result
+		err error
+	}
+	cases := map[string]struct {
+		reason  string
+		scanner Scanner
+		want    want
+	}{
+		"Vulnerable": {
+			reason:  "Only images with vulnerabilities should be returned.",
+			scanner: &mockScanner{vulns: map[string][]Vulnerability{"b": {critical}}},
+			want: want{
+				res: []Result{{Image: "b", Vulnerabilities: []Vulnerability{critical}}},
+			},
+		},
+		"ScanError": {
+			reason:  "Errors scanning an image should be returned.",
+			scanner: &mockScanner{err: errBoom},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtScanImage, "a"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := Images(context.Background(), tc.scanner, []string{"a", "b"}, SeverityHigh)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nImages(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nImages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
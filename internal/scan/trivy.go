@@ -0,0 +1,140 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	defaultTrivyPath = "trivy"
+
+	errRunTrivy   = "unable to run trivy, is it installed?"
+	errFmtTrivy   = "trivy failed: %s"
+	errParseTrivy = "unable to parse trivy report"
+)
+
+// runFn runs a command with the supplied environment and returns its
+// standard output.
+type runFn func(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
+
+// Trivy scans images by running the trivy CLI.
+type Trivy struct {
+	path     string
+	username string
+	password string
+	run      runFn
+}
+
+// TrivyOption modifies a Trivy scanner.
+type TrivyOption func(*Trivy)
+
+// WithTrivyPath sets the path to the trivy binary.
+func WithTrivyPath(p string) TrivyOption {
+	return func(t *Trivy) {
+		t.path = p
+	}
+}
+
+// WithRegistryAuth sets the credentials used by trivy to pull images.
+func WithRegistryAuth(username, password string) TrivyOption {
+	return func(t *Trivy) {
+		t.username = username
+		t.password = password
+	}
+}
+
+// NewTrivy constructs a new Trivy scanner.
+func NewTrivy(opts ...TrivyOption) *Trivy {
+	t := &Trivy{
+		path: defaultTrivyPath,
+		run:  run,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
+}
+
+// trivyReport is the subset of the JSON report of trivy that is used.
+type trivyReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+		}
+	}
+}
+
+// Scan scans the image for vulnerabilities of the supplied severities.
+func (t *Trivy) Scan(ctx context.Context, image string, severities []Severity) ([]Vulnerability, error) {
+	sev := make([]string, len(severities))
+	for i, s := range severities {
+		sev[i] = string(s)
+	}
+	var env []string
+	if t.username != "" {
+		env = append(env, "TRIVY_USERNAME="+t.username, "TRIVY_PASSWORD="+t.password)
+	}
+	out, err := t.run(ctx, env, t.path, "image", "--quiet", "--format", "json", "--severity", strings.Join(sev, ","), image)
+	if err != nil {
+		return nil, err
+	}
+	r := &trivyReport{}
+	if err := json.Unmarshal(out, r); err != nil {
+		return nil, errors.Wrap(err, errParseTrivy)
+	}
+	vulns := []Vulnerability{}
+	for _, res := range r.Results {
+		for _, v := range res.Vulnerabilities {
+			vulns = append(vulns, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         Severity(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+func run(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return nil, errors.Errorf(errFmtTrivy, strings.TrimSpace(stderr.String()))
+	case err != nil:
+		return nil, errors.Wrap(err, errRunTrivy)
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrivyScan(t *testing.T) {
+	errBoom := errors.New("boom")
+	report := `{"Results":[{"Target":"controller","Vulnerabilities":[{"VulnerabilityID":"CVE-2023-0001","PkgName":"openssl","InstalledVersion":"3.0.0","FixedVersion":"3.0.1","Severity":"CRITICAL","Title":"bad"}]},{"Target":"app"}]}`
+
+	type want struct {
+		vulns []Vulnerability
+		args  []string
+		env   []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		opts   []TrivyOption
+		out    string
+		err    error
+		want   want
+	}{
+		"Vulnerabilities": {
+			reason: "Vulnerabilities in the report should be returned.",
+			opts:   []TrivyOption{WithRegistryAuth("_json_key", "secret")},
+			out:    report,
+			want: want{
+				vulns: []Vulnerability{{
+					ID:               "CVE-2023-0001",
+					Package:          "openssl",
+					InstalledVersion: "3.0.0",
+					FixedVersion:     "3.0.1",
+					Severity:         SeverityCritical,
+					Title:            "bad",
+				}},
+				args: []string{"image", "--quiet", "--format", "json", "--severity", "HIGH,CRITICAL", "controller:v1"},
+				env:  []string{"TRIVY_USERNAME=_json_key", "TRIVY_PASSWORD=secret"},
+			},
+		},
+		"NoResults": {
+			reason: "An empty list should be returned if nothing was found.",
+			out:    `{}`,
+			want: want{
+				vulns: []Vulnerability{},
+				args:  []string{"image", "--quiet", "--format", "json", "--severity", "HIGH,CRITICAL", "controller:v1"},
+			},
+		},
+		"RunError": {
+			reason: "Errors running trivy should be returned.",
+			err:    errBoom,
+			want: want{
+				args: []string{"image", "--quiet", "--format", "json", "--severity", "HIGH,CRITICAL", "controller:v1"},
+				err:  errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var args, env []string
+			tr := NewTrivy(tc.opts...)
+			tr.run = func(_ context.Context, e []string, _ string, a ...string) ([]byte, error) {
+				env, args = e, a
+				return []byte(tc.out), tc.err
+			}
+			vulns, err := tr.Scan(context.Background(), "controller:v1", AtLeast(SeverityHigh))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.vulns, vulns); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.args, args); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want args, +got args:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.env, env); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want env, +got env:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
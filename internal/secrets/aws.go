@@ -0,0 +1,81 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// awsRegionParam is the query parameter of a reference that overrides
+	// the region of the secret.
+	awsRegionParam = "region"
+
+	errAWSSession = "unable to create AWS session"
+)
+
+// AWSSecretsManager fetches secrets from AWS Secrets Manager. References have
+// the form awssm://NAME[?region=REGION][#KEY], where NAME is the name of the
+// secret and KEY selects a key of a JSON secret. Secrets may also be referred
+// to by ARN as awssm:///ARN. Credentials and the default region are taken from
+// the default AWS credential chain.
+type AWSSecretsManager struct {
+	newClient func(region string) (secretsmanageriface.SecretsManagerAPI, error)
+}
+
+// NewAWSSecretsManager constructs an AWS Secrets Manager backend.
+func NewAWSSecretsManager() *AWSSecretsManager {
+	return &AWSSecretsManager{
+		newClient: func(region string) (secretsmanageriface.SecretsManagerAPI, error) {
+			sess, err := session.NewSessionWithOptions(session.Options{
+				SharedConfigState: session.SharedConfigEnable,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, errAWSSession)
+			}
+			cfg := &aws.Config{}
+			if region != "" {
+				cfg.Region = aws.String(region)
+			}
+			return secretsmanager.New(sess, cfg), nil
+		},
+	}
+}
+
+// Fetch fetches a secret from AWS Secrets Manager.
+func (a *AWSSecretsManager) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	client, err := a.newClient(ref.Query().Get(awsRegionParam))
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(refPath(ref)),
+	})
+	if err != nil {
+		return "", err
+	}
+	secret := string(out.SecretBinary)
+	if out.SecretString != nil {
+		secret = aws.StringValue(out.SecretString)
+	}
+	return selectJSONKey(secret, ref.Fragment)
+}
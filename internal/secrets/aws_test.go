@@ -0,0 +1,111 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mockSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	getSecretValueFn func(*secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+func (m *mockSecretsManager) GetSecretValueWithContext(_ aws.Context, in *secretsmanager.GetSecretValueInput, _ ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	return m.getSecretValueFn(in)
+}
+
+func TestAWSSecretsManagerFetch(t *testing.T) {
+	errBoom := errors.New("boom")
+	type want struct {
+		region string
+		value  string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		fn     func(*secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+		ref    string
+		want   want
+	}{
+		"ErrGetSecretValue": {
+			reason: "Errors getting the secret value should be returned.",
+			fn: func(*secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+				return nil, errBoom
+			},
+			ref: "awssm://upbound/token",
+			want: want{
+				err: errBoom,
+			},
+		},
+		"SuccessfulString": {
+			reason: "The secret string should be returned, with the region taken from the reference.",
+			fn: func(in *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+				return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(aws.StringValue(in.SecretId) + "-value")}, nil
+			},
+			ref: "awssm://upbound/token?region=us-east-1",
+			want: want{
+				region: "us-east-1",
+				value:  "upbound/token-value",
+			},
+		},
+		"SuccessfulARNKey": {
+			reason: "Secrets referred to by ARN should support selecting a key.",
+			fn: func(in *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+				if aws.StringValue(in.SecretId) != "arn:aws:secretsmanager:us-east-1:123:secret:upbound" {
+					return nil, errBoom
+				}
+				return &secretsmanager.GetSecretValueOutput{SecretBinary: []byte(`{"token":"abc"}`)}, nil
+			},
+			ref: "awssm:///arn:aws:secretsmanager:us-east-1:123:secret:upbound#token",
+			want: want{
+				value: "abc",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var region string
+			a := &AWSSecretsManager{
+				newClient: func(r string) (secretsmanageriface.SecretsManagerAPI, error) {
+					region = r
+					return &mockSecretsManager{getSecretValueFn: tc.fn}, nil
+				},
+			}
+			ref, _ := url.Parse(tc.ref)
+			v, err := a.Fetch(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.region, region); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want region, +got region:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
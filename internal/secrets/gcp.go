@@ -0,0 +1,110 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com/v1/"
+	gcpCloudPlatformScope    = "https://www.googleapis.com/auth/cloud-platform"
+	gcpLatestVersion         = "/versions/latest"
+
+	errGCPCredentials      = "unable to find GCP credentials"
+	errFmtGCPStatus        = "GCP Secret Manager returned status %d"
+	errDecodeGCPSecret     = "unable to decode GCP secret"
+	errFmtInvalidGCPSecret = "invalid GCP secret %q, must be projects/PROJECT/secrets/SECRET[/versions/VERSION]"
+)
+
+// GCPSecretManager fetches secrets from GCP Secret Manager. References have
+// the form gcpsm://projects/PROJECT/secrets/SECRET[/versions/VERSION][#KEY],
+// where KEY selects a key of a JSON secret. The latest version is used if no
+// version is supplied. Credentials are taken from Application Default
+// Credentials.
+type GCPSecretManager struct {
+	client      uphttp.Client
+	endpoint    string
+	tokenSource func(ctx context.Context) (oauth2.TokenSource, error)
+}
+
+// NewGCPSecretManager constructs a GCP Secret Manager backend.
+func NewGCPSecretManager() *GCPSecretManager {
+	return &GCPSecretManager{
+		client:   uphttp.NewClient(),
+		endpoint: gcpSecretManagerEndpoint,
+		tokenSource: func(ctx context.Context) (oauth2.TokenSource, error) {
+			return google.DefaultTokenSource(ctx, gcpCloudPlatformScope)
+		},
+	}
+}
+
+// Fetch fetches a secret from GCP Secret Manager.
+func (g *GCPSecretManager) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	name := refPath(ref)
+	segs := strings.Split(name, "/")
+	valid := (len(segs) == 4 || len(segs) == 6 && segs[4] == "versions") && segs[0] == "projects" && segs[2] == "secrets"
+	if !valid {
+		return "", errors.Errorf(errFmtInvalidGCPSecret, name)
+	}
+	if len(segs) == 4 {
+		name += gcpLatestVersion
+	}
+	ts, err := g.tokenSource(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, errGCPCredentials)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return "", errors.Wrap(err, errGCPCredentials)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	token.SetAuthHeader(req)
+	res, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf(errFmtGCPStatus, res.StatusCode)
+	}
+	secret := struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return "", errors.Wrap(err, errDecodeGCPSecret)
+	}
+	b, err := base64.StdEncoding.DecodeString(secret.Payload.Data)
+	if err != nil {
+		return "", errors.Wrap(err, errDecodeGCPSecret)
+	}
+	return selectJSONKey(string(b), ref.Fragment)
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+func TestGCPSecretManagerFetch(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{"token":"abc"}`))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gcp-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/projects/p/secrets/s/versions/latest:access" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"payload":{"data":"` + payload + `"}}`))
+	}))
+	defer srv.Close()
+	gcp := &GCPSecretManager{
+		client:   srv.Client(),
+		endpoint: srv.URL + "/",
+		tokenSource: func(context.Context) (oauth2.TokenSource, error) {
+			return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcp-token"}), nil
+		},
+	}
+
+	type want struct {
+		value string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		ref    string
+		want   want
+	}{
+		"ErrInvalidName": {
+			reason: "A reference that is not a secret name should return an error.",
+			ref:    "gcpsm://projects/p/s",
+			want: want{
+				err: errors.Errorf(errFmtInvalidGCPSecret, "projects/p/s"),
+			},
+		},
+		"ErrStatus": {
+			reason: "An error should be returned if the secret version does not exist.",
+			ref:    "gcpsm://projects/p/secrets/s/versions/2",
+			want: want{
+				err: errors.Errorf(errFmtGCPStatus, http.StatusNotFound),
+			},
+		},
+		"SuccessfulLatest": {
+			reason: "The latest version should be accessed if no version is supplied.",
+			ref:    "gcpsm://projects/p/secrets/s",
+			want: want{
+				value: `{"token":"abc"}`,
+			},
+		},
+		"SuccessfulKey": {
+			reason: "A key of a JSON secret should be selectable.",
+			ref:    "gcpsm://projects/p/secrets/s/versions/latest#token",
+			want: want{
+				value: "abc",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, _ := url.Parse(tc.ref)
+			v, err := gcp.Fetch(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
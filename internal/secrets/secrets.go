@@ -0,0 +1,152 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves references to secrets stored in external secret
+// managers, so that sensitive values do not need to be stored on disk.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// SchemeVault refers to a secret in HashiCorp Vault, e.g.
+	// vault://secret/data/upbound#token.
+	SchemeVault = "vault"
+	// SchemeAWS refers to a secret in AWS Secrets Manager, e.g.
+	// awssm://upbound/token?region=us-east-1#token.
+	SchemeAWS = "awssm"
+	// SchemeGCP refers to a secret in GCP Secret Manager, e.g.
+	// gcpsm://projects/my-project/secrets/upbound-token.
+	SchemeGCP = "gcpsm"
+
+	errFmtParseRef          = "unable to parse secret reference %q"
+	errFmtUnsupportedScheme = "unsupported secret reference scheme %q, must be one of: %s"
+	errFmtResolve           = "unable to resolve secret %s"
+	errFmtKeyNotFound       = "key %q not found in secret"
+	errKeyRequired          = "secret has multiple keys, select one with #KEY"
+	errNotJSONObject        = "secret is not a JSON object, so a key cannot be selected"
+	errEmptySecret          = "secret is empty"
+)
+
+// A Backend fetches secrets from a secret manager. The fragment of the
+// reference, if any, selects a key of the secret.
+type Backend interface {
+	Fetch(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// Resolver resolves secret references to their values using the backend
+// registered for the scheme of the reference.
+type Resolver struct {
+	backends map[string]Backend
+}
+
+// ResolverOption modifies a Resolver.
+type ResolverOption func(*Resolver)
+
+// WithBackend registers a backend for a reference scheme, replacing the
+// default backend for the scheme if there is one.
+func WithBackend(scheme string, b Backend) ResolverOption {
+	return func(r *Resolver) {
+		r.backends[scheme] = b
+	}
+}
+
+// NewResolver constructs a Resolver that supports Vault, AWS Secrets Manager
+// and GCP Secret Manager references.
+func NewResolver(opts ...ResolverOption) *Resolver {
+	r := &Resolver{
+		backends: map[string]Backend{
+			SchemeVault: NewVault(),
+			SchemeAWS:   NewAWSSecretsManager(),
+			SchemeGCP:   NewGCPSecretManager(),
+		},
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// Resolve returns the value of the secret the reference refers to.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme == "" {
+		return "", errors.Errorf(errFmtParseRef, ref)
+	}
+	b, ok := r.backends[u.Scheme]
+	if !ok {
+		schemes := make([]string, 0, len(r.backends))
+		for s := range r.backends {
+			schemes = append(schemes, s)
+		}
+		sort.Strings(schemes)
+		return "", errors.Errorf(errFmtUnsupportedScheme, u.Scheme, strings.Join(schemes, ", "))
+	}
+	v, err := b.Fetch(ctx, u)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtResolve, ref)
+	}
+	if v == "" {
+		return "", errors.Wrapf(errors.New(errEmptySecret), errFmtResolve, ref)
+	}
+	return v, nil
+}
+
+// selectKey returns the value of the key in the data of a secret. If no key
+// is supplied the data must have a single key.
+func selectKey(data map[string]any, key string) (string, error) {
+	if key == "" {
+		if len(data) != 1 {
+			return "", errors.New(errKeyRequired)
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	v, ok := data[key]
+	if !ok {
+		return "", errors.Errorf(errFmtKeyNotFound, key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// selectJSONKey returns the secret if no key is supplied, or the value of the
+// key if the secret is a JSON object.
+func selectJSONKey(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	data := map[string]any{}
+	if err := json.Unmarshal([]byte(secret), &data); err != nil {
+		return "", errors.New(errNotJSONObject)
+	}
+	return selectKey(data, key)
+}
+
+// refPath returns the host and path of a reference without a leading slash,
+// which together identify a secret.
+func refPath(ref *url.URL) string {
+	return strings.TrimPrefix(ref.Host+ref.Path, "/")
+}
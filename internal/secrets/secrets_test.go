@@ -0,0 +1,169 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mockBackend struct {
+	fetchFn func(ctx context.Context, ref *url.URL) (string, error)
+}
+
+func (m *mockBackend) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	return m.fetchFn(ctx, ref)
+}
+
+func TestResolve(t *testing.T) {
+	errBoom := errors.New("boom")
+	type want struct {
+		value string
+		err   error
+	}
+	cases := map[string]struct {
+		reason  string
+		backend Backend
+		ref     string
+		want    want
+	}{
+		"ErrNoScheme": {
+			reason: "A reference without a scheme should return an error.",
+			ref:    "secret/data/upbound",
+			want: want{
+				err: errors.Errorf(errFmtParseRef, "secret/data/upbound"),
+			},
+		},
+		"ErrUnsupportedScheme": {
+			reason: "A reference with an unknown scheme should return an error.",
+			ref:    "azkv://vault/secret",
+			want: want{
+				err: errors.Errorf(errFmtUnsupportedScheme, "azkv", "awssm, gcpsm, mock"),
+			},
+		},
+		"ErrFetch": {
+			reason: "Errors fetching the secret should be returned.",
+			backend: &mockBackend{fetchFn: func(context.Context, *url.URL) (string, error) {
+				return "", errBoom
+			}},
+			ref: "mock://secret",
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtResolve, "mock://secret"),
+			},
+		},
+		"ErrEmpty": {
+			reason: "An empty secret should return an error.",
+			backend: &mockBackend{fetchFn: func(context.Context, *url.URL) (string, error) {
+				return "", nil
+			}},
+			ref: "mock://secret",
+			want: want{
+				err: errors.Wrapf(errors.New(errEmptySecret), errFmtResolve, "mock://secret"),
+			},
+		},
+		"Successful": {
+			reason: "The secret should be fetched from the backend of the scheme.",
+			backend: &mockBackend{fetchFn: func(_ context.Context, ref *url.URL) (string, error) {
+				return refPath(ref) + "#" + ref.Fragment, nil
+			}},
+			ref: "mock://upbound/token#key",
+			want: want{
+				value: "upbound/token#key",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewResolver(WithBackend("mock", tc.backend))
+			delete(r.backends, SchemeVault)
+			v, err := r.Resolve(context.Background(), tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelectJSONKey(t *testing.T) {
+	type want struct {
+		value string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		secret string
+		key    string
+		want   want
+	}{
+		"NoKey": {
+			reason: "The whole secret should be returned if no key is selected.",
+			secret: `{"token":"abc"}`,
+			want: want{
+				value: `{"token":"abc"}`,
+			},
+		},
+		"Key": {
+			reason: "The value of the selected key should be returned.",
+			secret: `{"token":"abc","user":"robot"}`,
+			key:    "token",
+			want: want{
+				value: "abc",
+			},
+		},
+		"ObjectValue": {
+			reason: "Values that are not strings should be returned as JSON.",
+			secret: `{"key":{"type":"service_account"}}`,
+			key:    "key",
+			want: want{
+				value: `{"type":"service_account"}`,
+			},
+		},
+		"ErrKeyNotFound": {
+			reason: "Selecting a missing key should return an error.",
+			secret: `{"token":"abc"}`,
+			key:    "password",
+			want: want{
+				err: errors.Errorf(errFmtKeyNotFound, "password"),
+			},
+		},
+		"ErrNotJSON": {
+			reason: "Selecting a key of a secret that is not JSON should return an error.",
+			secret: "abc",
+			key:    "token",
+			want: want{
+				err: errors.New(errNotJSONObject),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := selectJSONKey(tc.secret, tc.key)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nselectJSONKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nselectJSONKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
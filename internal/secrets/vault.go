@@ -0,0 +1,124 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	envVaultAddr      = "VAULT_ADDR"
+	envVaultToken     = "VAULT_TOKEN"
+	envVaultNamespace = "VAULT_NAMESPACE"
+
+	// vaultTokenFile is the file in the home directory in which the Vault
+	// CLI stores the token after login.
+	vaultTokenFile = ".vault-token"
+
+	errVaultAddr         = "VAULT_ADDR must be set to resolve Vault secrets"
+	errVaultToken        = "VAULT_TOKEN must be set or a token must be stored by vault login"
+	errFmtVaultStatus    = "vault returned status %d"
+	errDecodeVaultSecret = "unable to decode Vault secret"
+)
+
+// Vault fetches secrets from HashiCorp Vault. References have the form
+// vault://PATH#KEY, where PATH is the API path of the secret, e.g.
+// secret/data/upbound for version 2 of the KV secrets engine. The address and
+// token are taken from the same environment variables as the Vault CLI.
+type Vault struct {
+	client    uphttp.Client
+	addr      string
+	token     func() (string, error)
+	namespace string
+}
+
+// NewVault constructs a Vault backend from the environment.
+func NewVault() *Vault {
+	return &Vault{
+		client:    uphttp.NewClient(),
+		addr:      os.Getenv(envVaultAddr),
+		token:     vaultToken,
+		namespace: os.Getenv(envVaultNamespace),
+	}
+}
+
+// vaultToken returns the token from the environment, falling back to the
+// token stored by the Vault CLI.
+func vaultToken() (string, error) {
+	if t := os.Getenv(envVaultToken); t != "" {
+		return t, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New(errVaultToken)
+	}
+	b, err := os.ReadFile(filepath.Join(home, vaultTokenFile)) //nolint:gosec
+	if err != nil {
+		return "", errors.New(errVaultToken)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Fetch fetches a secret from Vault.
+func (v *Vault) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	if v.addr == "" {
+		return "", errors.New(errVaultAddr)
+	}
+	token, err := v.token()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.addr, "/")+"/v1/"+refPath(ref), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	res, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf(errFmtVaultStatus, res.StatusCode)
+	}
+	secret := struct {
+		Data map[string]any `json:"data"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return "", errors.Wrap(err, errDecodeVaultSecret)
+	}
+	data := secret.Data
+	// Version 2 of the KV secrets engine nests the secret data alongside its
+	// metadata.
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	return selectKey(data, ref.Fragment)
+}
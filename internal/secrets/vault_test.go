@@ -0,0 +1,109 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVaultFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/upbound":
+			_, _ = w.Write([]byte(`{"data":{"data":{"token":"abc","user":"robot"},"metadata":{"version":1}}}`))
+		case "/v1/kv/upbound":
+			_, _ = w.Write([]byte(`{"data":{"token":"def"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	type want struct {
+		value string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		vault  *Vault
+		ref    string
+		want   want
+	}{
+		"ErrNoAddress": {
+			reason: "An error should be returned if the Vault address is not set.",
+			vault:  &Vault{},
+			ref:    "vault://secret/data/upbound#token",
+			want: want{
+				err: errors.New(errVaultAddr),
+			},
+		},
+		"ErrStatus": {
+			reason: "An error should be returned if Vault does not return the secret.",
+			vault:  &Vault{client: srv.Client(), addr: srv.URL, token: func() (string, error) { return "root", nil }},
+			ref:    "vault://secret/data/missing#token",
+			want: want{
+				err: errors.Errorf(errFmtVaultStatus, http.StatusNotFound),
+			},
+		},
+		"ErrKeyRequired": {
+			reason: "A key must be selected if the secret has multiple keys.",
+			vault:  &Vault{client: srv.Client(), addr: srv.URL, token: func() (string, error) { return "root", nil }},
+			ref:    "vault://secret/data/upbound",
+			want: want{
+				err: errors.New(errKeyRequired),
+			},
+		},
+		"SuccessfulKVv2": {
+			reason: "The data of a version 2 KV secret should be unwrapped.",
+			vault:  &Vault{client: srv.Client(), addr: srv.URL + "/", token: func() (string, error) { return "root", nil }},
+			ref:    "vault://secret/data/upbound#token",
+			want: want{
+				value: "abc",
+			},
+		},
+		"SuccessfulKVv1": {
+			reason: "The only key of a secret should be used if none is selected.",
+			vault:  &Vault{client: srv.Client(), addr: srv.URL, token: func() (string, error) { return "root", nil }},
+			ref:    "vault://kv/upbound",
+			want: want{
+				value: "def",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, _ := url.Parse(tc.ref)
+			v, err := tc.vault.Fetch(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
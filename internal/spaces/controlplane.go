@@ -0,0 +1,201 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spaces manages control planes in a self-hosted Space through the
+// Kubernetes API of the Space cluster.
+package spaces
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/upbound/up/internal/resources"
+)
+
+const (
+	notAvailable = "n/a"
+
+	errCreateControlPlane = "unable to create control plane"
+	errGetControlPlane    = "unable to get control plane"
+	errListControlPlanes  = "unable to list control planes"
+	errDeleteControlPlane = "unable to delete control plane"
+	errSetState           = "unable to set the state of control plane"
+	errSetLabel           = "unable to set the label of control plane"
+)
+
+// ControlPlaneOptions configure a control plane when it is created.
+type ControlPlaneOptions struct {
+	// Labels of the control plane.
+	Labels map[string]string
+	// Annotations of the control plane.
+	Annotations map[string]string
+	// CrossplaneVersion of the control plane. The Space default is used if
+	// empty.
+	CrossplaneVersion string
+	// Class is the resource class of the control plane, which determines the
+	// resources allocated to it. The Space default is used if empty.
+	Class string
+	// ConfigurationPackage is a reference to a Configuration package that the
+	// Space installs in the control plane.
+	ConfigurationPackage string
+}
+
+// ControlPlaneClient manages ControlPlane resources in a Space.
+type ControlPlaneClient struct {
+	r dynamic.NamespaceableResourceInterface
+}
+
+// NewControlPlaneClient constructs a ControlPlaneClient with the passed
+// dynamic client of a Space cluster.
+func NewControlPlaneClient(c dynamic.Interface) *ControlPlaneClient {
+	return &ControlPlaneClient{r: c.Resource(resources.ControlPlaneGVR)}
+}
+
+// Create creates a control plane with the supplied name.
+func (c *ControlPlaneClient) Create(ctx context.Context, name string, o ControlPlaneOptions) (*resources.ControlPlane, error) {
+	ctp := &resources.ControlPlane{}
+	ctp.SetGroupVersionKind(resources.ControlPlaneGVK)
+	ctp.SetName(name)
+	ctp.SetLabels(o.Labels)
+	ctp.SetAnnotations(o.Annotations)
+	if o.CrossplaneVersion != "" {
+		ctp.SetCrossplaneVersion(o.CrossplaneVersion)
+	}
+	if o.Class != "" {
+		ctp.SetClass(o.Class)
+	}
+	if o.ConfigurationPackage != "" {
+		ctp.SetConfigurationPackage(o.ConfigurationPackage)
+	}
+	u, err := c.r.Create(ctx, ctp.GetUnstructured(), metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateControlPlane)
+	}
+	return &resources.ControlPlane{Unstructured: *u}, nil
+}
+
+// Get gets the control plane with the supplied name.
+func (c *ControlPlaneClient) Get(ctx context.Context, name string) (*resources.ControlPlane, error) {
+	u, err := c.r.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errGetControlPlane)
+	}
+	return &resources.ControlPlane{Unstructured: *u}, nil
+}
+
+// List lists the control planes with labels matching the supplied selector.
+// All control planes are listed if the selector is empty.
+func (c *ControlPlaneClient) List(ctx context.Context, selector string) ([]resources.ControlPlane, error) {
+	l, err := c.r.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrap(err, errListControlPlanes)
+	}
+	ctps := make([]resources.ControlPlane, len(l.Items))
+	for i := range l.Items {
+		ctps[i] = resources.ControlPlane{Unstructured: l.Items[i]}
+	}
+	return ctps, nil
+}
+
+// Delete deletes the control plane with the supplied name.
+func (c *ControlPlaneClient) Delete(ctx context.Context, name string) error {
+	return errors.Wrap(c.r.Delete(ctx, name, metav1.DeleteOptions{}), errDeleteControlPlane)
+}
+
+// SetCrossplaneState sets the state of the Crossplane of the control plane with
+// the supplied name, e.g. to pause it.
+func (c *ControlPlaneClient) SetCrossplaneState(ctx context.Context, name, state string) (*resources.ControlPlane, error) {
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"crossplane": map[string]any{"state": state},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errSetState)
+	}
+	u, err := c.r.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errSetState)
+	}
+	return &resources.ControlPlane{Unstructured: *u}, nil
+}
+
+// SetLabel sets a label of the control plane with the supplied name. The label
+// is removed if the value is empty.
+func (c *ControlPlaneClient) SetLabel(ctx context.Context, name, key, value string) (*resources.ControlPlane, error) {
+	var v any
+	if value != "" {
+		v = value
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{key: v},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errSetLabel)
+	}
+	u, err := c.r.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errSetLabel)
+	}
+	return &resources.ControlPlane{Unstructured: *u}, nil
+}
+
+// ControlPlaneFieldNames are the names of the fields returned by
+// ExtractControlPlaneFields.
+var ControlPlaneFieldNames = []string{"NAME", "CROSSPLANE VERSION", "CLASS", "SYNCED", "READY", "LABELS"}
+
+// ExtractControlPlaneFields extracts the fields of a control plane for
+// printing.
+func ExtractControlPlaneFields(obj any) []string {
+	c := obj.(resources.ControlPlane)
+	return []string{
+		c.GetName(),
+		orNotAvailable(c.GetCrossplaneVersion()),
+		orNotAvailable(c.GetClass()),
+		orNotAvailable(string(c.GetCondition(xpv1.TypeSynced).Status)),
+		orNotAvailable(string(c.GetCondition(xpv1.TypeReady).Status)),
+		formatLabels(c.GetLabels()),
+	}
+}
+
+func orNotAvailable(s string) string {
+	if s == "" {
+		return notAvailable
+	}
+	return s
+}
+
+// formatLabels formats labels as a sorted, comma separated list of key=value
+// pairs.
+func formatLabels(l map[string]string) string {
+	if len(l) == 0 {
+		return notAvailable
+	}
+	pairs := make([]string, 0, len(l))
+	for k, v := range l {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/upbound/up/internal/resources"
+)
+
+func TestControlPlaneClient(t *testing.T) {
+	dc := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		resources.ControlPlaneGVR: "ControlPlaneList",
+	})
+	c := NewControlPlaneClient(dc)
+	ctx := context.Background()
+
+	if _, err := c.Create(ctx, "prod", ControlPlaneOptions{Labels: map[string]string{"env": "prod"}, CrossplaneVersion: "1.12.1-up.1", Class: "large"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Create(ctx, "dev", ControlPlaneOptions{Labels: map[string]string{"env": "dev"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get(ctx, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1.12.1-up.1", got.GetCrossplaneVersion()); diff != "" {
+		t.Errorf("Get(...): -want version, +got version:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"prod", "1.12.1-up.1", "large", "n/a", "n/a", "env=prod"}, ExtractControlPlaneFields(*got)); diff != "" {
+		t.Errorf("ExtractControlPlaneFields(...): -want, +got:\n%s", diff)
+	}
+
+	l, err := c.List(ctx, "env=dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, ctp := range l {
+		names = append(names, ctp.GetName())
+	}
+	if diff := cmp.Diff([]string{"dev"}, names); diff != "" {
+		t.Errorf("List(...): -want, +got:\n%s", diff)
+	}
+
+	paused, err := c.SetCrossplaneState(ctx, "dev", resources.CrossplaneStatePaused)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(resources.CrossplaneStatePaused, paused.GetCrossplaneState()); diff != "" {
+		t.Errorf("SetCrossplaneState(...): -want state, +got state:\n%s", diff)
+	}
+	if diff := cmp.Diff(resources.CrossplaneStateRunning, got.GetCrossplaneState()); diff != "" {
+		t.Errorf("GetCrossplaneState(...): -want default state, +got state:\n%s", diff)
+	}
+
+	tagged, err := c.SetLabel(ctx, "dev", resources.CostCenterLabel, "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("platform", tagged.GetLabels()[resources.CostCenterLabel]); diff != "" {
+		t.Errorf("SetLabel(...): -want label, +got label:\n%s", diff)
+	}
+	untagged, err := c.SetLabel(ctx, "dev", resources.CostCenterLabel, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := untagged.GetLabels()[resources.CostCenterLabel]; ok {
+		t.Errorf("SetLabel(...): want label removed, got labels %v", untagged.GetLabels())
+	}
+
+	if err := c.Delete(ctx, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, "prod"); !kerrors.IsNotFound(err) {
+		t.Errorf("Get(...): want not found error after Delete(...), got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/upterm"
+)
+
+// controlPlaneV1 is version v1 of the machine output of a control plane in a
+// Space.
+type controlPlaneV1 struct {
+	Name                 string            `json:"name"`
+	CrossplaneVersion    string            `json:"crossplaneVersion,omitempty"`
+	Class                string            `json:"class,omitempty"`
+	ConfigurationPackage string            `json:"configurationPackage,omitempty"`
+	Synced               string            `json:"synced,omitempty"`
+	Ready                string            `json:"ready,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty"`
+	CreatedAt            *time.Time        `json:"createdAt,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(resources.ControlPlane{}, upterm.Schema{
+		Kind:    "SpaceControlPlane",
+		Version: "v1",
+		Convert: convertControlPlaneV1,
+	})
+}
+
+func convertControlPlaneV1(obj any) any {
+	c := obj.(resources.ControlPlane)
+	out := controlPlaneV1{
+		Name:                 c.GetName(),
+		CrossplaneVersion:    c.GetCrossplaneVersion(),
+		Class:                c.GetClass(),
+		ConfigurationPackage: c.GetConfigurationPackage(),
+		Synced:               string(c.GetCondition(xpv1.TypeSynced).Status),
+		Ready:                string(c.GetCondition(xpv1.TypeReady).Status),
+		Labels:               c.GetLabels(),
+		Annotations:          c.GetAnnotations(),
+	}
+	if ts := c.GetCreationTimestamp(); !ts.IsZero() {
+		t := ts.UTC()
+		out.CreatedAt = &t
+	}
+	return out
+}
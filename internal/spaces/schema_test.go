@@ -0,0 +1,79 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/upbound/up/internal/resources"
+	"github.com/upbound/up/internal/upterm"
+)
+
+func TestControlPlaneSchemaV1(t *testing.T) {
+	ctp := resources.ControlPlane{}
+	ctp.SetGroupVersionKind(resources.ControlPlaneGVK)
+	ctp.SetName("prod")
+	ctp.SetLabels(map[string]string{"env": "prod"})
+	ctp.SetCreationTimestamp(metav1.NewTime(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)))
+	ctp.SetCrossplaneVersion("1.12.1-up.1")
+	ctp.SetClass("large")
+	ctp.Object["status"] = map[string]any{
+		"conditions": []any{
+			map[string]any{"type": "Synced", "status": "True"},
+			map[string]any{"type": "Ready", "status": "False"},
+		},
+	}
+	empty := resources.ControlPlane{}
+	empty.SetName("dev")
+
+	cases := map[string]struct {
+		reason string
+		obj    any
+		golden string
+	}{
+		"ControlPlane": {
+			reason: "A control plane should be printed with the fields of schema v1.",
+			obj:    ctp,
+			golden: "controlplane-v1.golden",
+		},
+		"ControlPlaneList": {
+			reason: "Unset fields of listed control planes should be omitted.",
+			obj:    []resources.ControlPlane{empty},
+			golden: "controlplanelist-v1.golden",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := upterm.MachineJSON(tc.obj)
+			if err != nil {
+				t.Fatalf("\n%s\nMachineJSON(...): %v", tc.reason, err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", tc.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+				t.Errorf("\n%s\nMachineJSON(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package support gathers diagnostics from a Space cluster into a support
+// bundle that can be attached to support tickets.
+package support
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/upbound/up/internal/install"
+	"github.com/upbound/up/internal/resources"
+)
+
+const (
+	mode = 0644
+
+	versionsFilename = "versions.yaml"
+	historyFilename  = "release/history.yaml"
+	errorsFilename   = "errors.txt"
+	eventsFilename   = "events.yaml"
+	clusterDir       = "cluster"
+	logsDir          = "logs"
+
+	defaultLogLines = 1000
+
+	errWriteBundle = "unable to write support bundle"
+)
+
+// Resource is a kind of resource that is included in a support bundle.
+type Resource struct {
+	schema.GroupVersionResource
+
+	// Namespaced resources are collected from each of the bundle's
+	// namespaces, while cluster scoped resources are collected once.
+	Namespaced bool
+}
+
+// DefaultNamespaces are the namespaces of the Space components and their
+// prerequisites.
+var DefaultNamespaces = []string{"upbound-system", "crossplane-system", "cert-manager", "ingress-nginx"}
+
+// DefaultResources are the resources included in a support bundle by default.
+var DefaultResources = []Resource{
+	{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "services"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, Namespaced: true},
+	{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "nodes"}},
+	{GroupVersionResource: resources.ControlPlaneGVR},
+}
+
+// A Collector gathers diagnostics from a Space cluster into a support bundle.
+// Failures to collect individual diagnostics are recorded in the bundle rather
+// than aborting it, so that a partial bundle is still produced for a broken
+// Space.
+type Collector struct {
+	kube     kubernetes.Interface
+	dynamic  dynamic.Interface
+	redactor *Redactor
+
+	release       install.Manager
+	clientVersion string
+	namespaces    []string
+	resources     []Resource
+	logLines      int64
+	now           func() time.Time
+}
+
+// CollectorOption modifies a Collector.
+type CollectorOption func(*Collector)
+
+// WithRelease includes the version and history of the Space's helm release in
+// the bundle.
+func WithRelease(m install.Manager) CollectorOption {
+	return func(c *Collector) {
+		c.release = m
+	}
+}
+
+// WithClientVersion includes the version of up in the bundle.
+func WithClientVersion(v string) CollectorOption {
+	return func(c *Collector) {
+		c.clientVersion = v
+	}
+}
+
+// WithNamespaces sets the namespaces from which logs, events and namespaced
+// resources are collected.
+func WithNamespaces(ns ...string) CollectorOption {
+	return func(c *Collector) {
+		c.namespaces = ns
+	}
+}
+
+// WithResources sets the resources included in the bundle.
+func WithResources(r ...Resource) CollectorOption {
+	return func(c *Collector) {
+		c.resources = r
+	}
+}
+
+// WithLogLines sets the number of lines collected from the end of each
+// container's logs.
+func WithLogLines(n int64) CollectorOption {
+	return func(c *Collector) {
+		c.logLines = n
+	}
+}
+
+// NewCollector constructs a Collector for the cluster served by the supplied
+// clients.
+func NewCollector(k kubernetes.Interface, d dynamic.Interface, r *Redactor, opts ...CollectorOption) *Collector {
+	c := &Collector{
+		kube:       k,
+		dynamic:    d,
+		redactor:   r,
+		namespaces: DefaultNamespaces,
+		resources:  DefaultResources,
+		logLines:   defaultLogLines,
+		now:        time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// versions are the versions of the components of a Space.
+type versions struct {
+	Client  string `json:"client,omitempty"`
+	Server  string `json:"server,omitempty"`
+	Release string `json:"release,omitempty"`
+}
+
+// bundle writes files to a support bundle.
+type bundle struct {
+	tw   *tar.Writer
+	now  time.Time
+	errs []string
+}
+
+func (b *bundle) write(name string, data []byte) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}); err != nil {
+		return errors.Wrap(err, errWriteBundle)
+	}
+	_, err := b.tw.Write(data)
+	return errors.Wrap(err, errWriteBundle)
+}
+
+func (b *bundle) writeYAML(name string, obj any) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		b.fail(name, err)
+		return nil
+	}
+	return b.write(name, data)
+}
+
+// fail records a failure to collect a diagnostic.
+func (b *bundle) fail(what string, err error) {
+	b.errs = append(b.errs, fmt.Sprintf("%s: %s", what, err))
+}
+
+// Collect writes a gzipped tarball of the Space's diagnostics to the supplied
+// writer.
+func (c *Collector) Collect(ctx context.Context, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	b := &bundle{tw: tar.NewWriter(gw), now: c.now()}
+
+	if err := c.collectVersions(b); err != nil {
+		return err
+	}
+	if err := c.collectHistory(b); err != nil {
+		return err
+	}
+	for _, r := range c.resources {
+		if r.Namespaced {
+			continue
+		}
+		if err := c.collectResources(ctx, b, r, ""); err != nil {
+			return err
+		}
+	}
+	for _, ns := range c.namespaces {
+		if err := c.collectNamespace(ctx, b, ns); err != nil {
+			return err
+		}
+	}
+	if len(b.errs) > 0 {
+		if err := b.write(errorsFilename, []byte(strings.Join(b.errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, errWriteBundle)
+	}
+	return errors.Wrap(gw.Close(), errWriteBundle)
+}
+
+func (c *Collector) collectVersions(b *bundle) error {
+	v := versions{Client: c.clientVersion}
+	sv, err := c.kube.Discovery().ServerVersion()
+	if err != nil {
+		b.fail("server version", err)
+	} else {
+		v.Server = sv.GitVersion
+	}
+	if c.release != nil {
+		if v.Release, err = c.release.GetCurrentVersion(); err != nil {
+			b.fail("release version", err)
+		}
+	}
+	return b.writeYAML(versionsFilename, v)
+}
+
+func (c *Collector) collectHistory(b *bundle) error {
+	if c.release == nil {
+		return nil
+	}
+	revs, err := c.release.History()
+	if err != nil {
+		b.fail("release history", err)
+		return nil
+	}
+	return b.writeYAML(historyFilename, revs)
+}
+
+func (c *Collector) collectNamespace(ctx context.Context, b *bundle, ns string) error {
+	for _, r := range c.resources {
+		if !r.Namespaced {
+			continue
+		}
+		if err := c.collectResources(ctx, b, r, ns); err != nil {
+			return err
+		}
+	}
+	if err := c.collectEvents(ctx, b, ns); err != nil {
+		return err
+	}
+	return c.collectLogs(ctx, b, ns)
+}
+
+// collectResources writes the redacted resources of the supplied kind to a
+// single YAML list. Cluster scoped resources are collected if ns is empty.
+func (c *Collector) collectResources(ctx context.Context, b *bundle, r Resource, ns string) error {
+	dir := clusterDir
+	if ns != "" {
+		dir = ns
+	}
+	name := path.Join(dir, resourceFilename(r.GroupVersionResource))
+	l, err := c.dynamic.Resource(r.GroupVersionResource).Namespace(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.fail(name, err)
+		return nil
+	}
+	items := make([]map[string]any, len(l.Items))
+	for i := range l.Items {
+		c.redactor.Redact(&l.Items[i])
+		items[i] = l.Items[i].Object
+	}
+	return b.writeYAML(name, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	})
+}
+
+func (c *Collector) collectEvents(ctx context.Context, b *bundle, ns string) error {
+	name := path.Join(ns, eventsFilename)
+	l, err := c.kube.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.fail(name, err)
+		return nil
+	}
+	return b.writeYAML(name, l.Items)
+}
+
+// collectLogs writes the tail of the logs of every container in the supplied
+// namespace, including those of the previous instance of restarted
+// containers.
+func (c *Collector) collectLogs(ctx context.Context, b *bundle, ns string) error {
+	pods, err := c.kube.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.fail(path.Join(ns, logsDir), err)
+		return nil
+	}
+	for _, p := range pods.Items {
+		for _, s := range p.Status.ContainerStatuses {
+			if err := c.collectLog(ctx, b, p, s.Name, false); err != nil {
+				return err
+			}
+			if s.RestartCount == 0 {
+				continue
+			}
+			if err := c.collectLog(ctx, b, p, s.Name, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Collector) collectLog(ctx context.Context, b *bundle, p corev1.Pod, container string, previous bool) error {
+	name := path.Join(p.GetNamespace(), logsDir, p.GetName(), container+".log")
+	if previous {
+		name = path.Join(p.GetNamespace(), logsDir, p.GetName(), container+".previous.log")
+	}
+	data, err := c.kube.CoreV1().Pods(p.GetNamespace()).GetLogs(p.GetName(), &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &c.logLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		b.fail(name, err)
+		return nil
+	}
+	return b.write(name, data)
+}
+
+// resourceFilename returns the name of the file that holds resources of the
+// supplied kind, e.g. deployments.apps.yaml.
+func resourceFilename(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource + ".yaml"
+	}
+	return gvr.Resource + "." + gvr.Group + ".yaml"
+}
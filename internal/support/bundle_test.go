@@ -0,0 +1,151 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dfake "k8s.io/client-go/dynamic/fake"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	"github.com/upbound/up/internal/install"
+)
+
+var _ install.Manager = &mockRelease{}
+
+type mockRelease struct {
+	install.Manager
+}
+
+func (m *mockRelease) GetCurrentVersion() (string, error) {
+	return "1.0.0", nil
+}
+
+func (m *mockRelease) History() ([]install.Revision, error) {
+	return []install.Revision{{Revision: 1, Version: "1.0.0", Status: "deployed"}}, nil
+}
+
+func TestCollect(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	kube := kfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "spaces-controller", Namespace: "upbound-system"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "controller", RestartCount: 1},
+		}},
+	})
+	dyn := dfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		secrets:     "SecretList",
+		deployments: "DeploymentList",
+	}, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "creds", "namespace": "upbound-system"},
+		"data":       map[string]any{"key": "c2VjcmV0"},
+	}})
+	dyn.PrependReactor("list", "deployments", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("boom")
+	})
+	r, err := NewRedactor(DefaultRedactionRules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCollector(kube, dyn, r,
+		WithRelease(&mockRelease{}),
+		WithClientVersion("v0.20.0"),
+		WithNamespaces("upbound-system"),
+		WithResources(
+			Resource{GroupVersionResource: secrets, Namespaced: true},
+			// Listing deployments fails, which should be recorded in the
+			// bundle rather than aborting it.
+			Resource{GroupVersionResource: deployments, Namespaced: true},
+		),
+	)
+	c.now = func() time.Time { return time.Unix(0, 0) }
+
+	buf := &bytes.Buffer{}
+	if err := c.Collect(context.Background(), buf); err != nil {
+		t.Fatal(err)
+	}
+	files := readBundle(t, buf)
+
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	want := []string{
+		"errors.txt",
+		"release/history.yaml",
+		"upbound-system/events.yaml",
+		"upbound-system/logs/spaces-controller/controller.log",
+		"upbound-system/logs/spaces-controller/controller.previous.log",
+		"upbound-system/secrets.yaml",
+		"versions.yaml",
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("Collect(...): -want files, +got files:\n%s", diff)
+	}
+	if s := files["upbound-system/secrets.yaml"]; strings.Contains(s, "c2VjcmV0") || !strings.Contains(s, Redacted) {
+		t.Errorf("Collect(...): want redacted secret data, got:\n%s", s)
+	}
+	if s := files["versions.yaml"]; !strings.Contains(s, "client: v0.20.0") || !strings.Contains(s, "release: 1.0.0") {
+		t.Errorf("Collect(...): want client and release versions, got:\n%s", s)
+	}
+	if s := files["errors.txt"]; !strings.Contains(s, "upbound-system/deployments.apps.yaml") {
+		t.Errorf("Collect(...): want failure to list deployments recorded, got:\n%s", s)
+	}
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[h.Name] = string(b)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package support
+
+import (
+	"regexp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// Redacted replaces the values removed from a support bundle.
+	Redacted = "REDACTED"
+
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+	errFmtInvalidRule = "invalid redaction rule %q"
+)
+
+// DefaultRedactionRules match the names of fields that commonly hold
+// sensitive values. They avoid matching references to sensitive values, such
+// as secretName or tokenRef, which are useful when diagnosing a Space.
+var DefaultRedactionRules = []string{
+	`(?i)password`,
+	`(?i)passwd`,
+	`(?i)token$`,
+	`(?i)^secret$`,
+	`(?i)secret.?(access.?)?key$`,
+	`(?i)credentials?$`,
+	`(?i)private.?key`,
+}
+
+// A Redactor removes sensitive values from resources before they are written
+// to a support bundle. The data of Secrets is always redacted. The string
+// values of any other field whose name matches a rule are redacted, as are the
+// values of name/value pairs, such as container environment variables, whose
+// name matches a rule.
+type Redactor struct {
+	rules []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor that redacts fields matching the supplied
+// regular expressions.
+func NewRedactor(rules ...string) (*Redactor, error) {
+	r := &Redactor{rules: make([]*regexp.Regexp, len(rules))}
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidRule, rule)
+		}
+		r.rules[i] = re
+	}
+	return r, nil
+}
+
+// Redact removes sensitive values from the supplied resource in place.
+func (r *Redactor) Redact(u *unstructured.Unstructured) {
+	// Managed fields are noise in a support bundle, and the last applied
+	// configuration may hold unredacted copies of the resource's fields.
+	u.SetManagedFields(nil)
+	if a := u.GetAnnotations(); a[lastAppliedAnnotation] != "" {
+		delete(a, lastAppliedAnnotation)
+		if len(a) == 0 {
+			a = nil
+		}
+		u.SetAnnotations(a)
+	}
+	if u.GetAPIVersion() == "v1" && u.GetKind() == "Secret" {
+		for _, f := range []string{"data", "stringData"} {
+			if d, ok := u.Object[f].(map[string]any); ok {
+				for k := range d {
+					d[k] = Redacted
+				}
+			}
+		}
+	}
+	r.redact(u.Object)
+}
+
+func (r *Redactor) redact(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if n, ok := v["name"].(string); ok && r.matches(n) {
+			if _, ok := v["value"].(string); ok {
+				v["value"] = Redacted
+			}
+		}
+		for k, f := range v {
+			if _, ok := f.(string); ok && r.matches(k) {
+				v[k] = Redacted
+				continue
+			}
+			r.redact(f)
+		}
+	case []any:
+		for _, e := range v {
+			r.redact(e)
+		}
+	}
+}
+
+func (r *Redactor) matches(name string) bool {
+	for _, re := range r.rules {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
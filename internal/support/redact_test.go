@@ -0,0 +1,129 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package support
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRedact(t *testing.T) {
+	type args struct {
+		rules []string
+		obj   map[string]any
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]any
+	}{
+		"SecretData": {
+			reason: "The data of Secrets should always be redacted.",
+			args: args{
+				obj: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Secret",
+					"metadata": map[string]any{
+						"name": "creds",
+						"annotations": map[string]any{
+							lastAppliedAnnotation: `{"data":{"key":"c2VjcmV0"}}`,
+							"owner":               "platform",
+						},
+					},
+					"data":       map[string]any{"key": "c2VjcmV0"},
+					"stringData": map[string]any{"other": "secret"},
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":        "creds",
+					"annotations": map[string]any{"owner": "platform"},
+				},
+				"data":       map[string]any{"key": Redacted},
+				"stringData": map[string]any{"other": Redacted},
+			},
+		},
+		"MatchingFields": {
+			reason: "String fields and name/value pairs whose names match a rule should be redacted.",
+			args: args{
+				rules: DefaultRedactionRules,
+				obj: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Example",
+					"spec": map[string]any{
+						"password":   "hunter2",
+						"secretName": "creds",
+						"tokenRef":   map[string]any{"name": "token"},
+						"env": []any{
+							map[string]any{"name": "AWS_SECRET_ACCESS_KEY", "value": "abc"},
+							map[string]any{"name": "REGION", "value": "us-east-1"},
+						},
+					},
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Example",
+				"spec": map[string]any{
+					"password":   Redacted,
+					"secretName": "creds",
+					"tokenRef":   map[string]any{"name": "token"},
+					"env": []any{
+						map[string]any{"name": "AWS_SECRET_ACCESS_KEY", "value": Redacted},
+						map[string]any{"name": "REGION", "value": "us-east-1"},
+					},
+				},
+			},
+		},
+		"NoRules": {
+			reason: "Fields of resources other than Secrets should not be redacted if there are no rules.",
+			args: args{
+				obj: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Example",
+					"spec":       map[string]any{"password": "hunter2"},
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Example",
+				"spec":       map[string]any{"password": "hunter2"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewRedactor(tc.args.rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u := &unstructured.Unstructured{Object: tc.args.obj}
+			r.Redact(u)
+			if diff := cmp.Diff(tc.want, u.Object); diff != "" {
+				t.Errorf("\n%s\nRedact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewRedactorInvalidRule(t *testing.T) {
+	if _, err := NewRedactor("("); err == nil {
+		t.Error("NewRedactor(...): want error for invalid rule, got nil")
+	}
+}
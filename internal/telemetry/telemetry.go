@@ -0,0 +1,208 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry records anonymous usage metrics for up commands. Events
+// are queued locally and sent in batches, and are only recorded if the user
+// has opted in.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/config"
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	// QueueFile is the name of the file in the up config directory in which
+	// events are queued.
+	QueueFile = "telemetry.jsonl"
+
+	// FlushThreshold is the number of queued events at which the queue
+	// should be flushed.
+	FlushThreshold = 20
+
+	errReadQueue    = "unable to read telemetry queue"
+	errWriteQueue   = "unable to write telemetry queue"
+	errSendEvents   = "unable to send telemetry events"
+	errFmtSendCode  = "telemetry endpoint returned status %d"
+	errEncodeEvents = "unable to encode telemetry events"
+)
+
+// An Event describes a single invocation of an up command. Events must not
+// contain arguments, flags, or any other user supplied values.
+type Event struct {
+	Command    string    `json:"command"`
+	DurationMS int64     `json:"durationMs"`
+	Success    bool      `json:"success"`
+	Version    string    `json:"version"`
+	Time       time.Time `json:"time"`
+}
+
+// A Sender sends a batch of events.
+type Sender interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// Queue is a file backed queue of events.
+type Queue struct {
+	fs   afero.Fs
+	path string
+}
+
+// QueueOption modifies a Queue.
+type QueueOption func(*Queue)
+
+// WithFS overrides the Queue filesystem with the given filesystem.
+func WithFS(fs afero.Fs) QueueOption {
+	return func(q *Queue) {
+		q.fs = fs
+	}
+}
+
+// WithPath overrides the path of the Queue file.
+func WithPath(p string) QueueOption {
+	return func(q *Queue) {
+		q.path = filepath.Clean(p)
+	}
+}
+
+// NewQueue constructs a new Queue. The queue is stored in the up config
+// directory unless a path is supplied.
+func NewQueue(opts ...QueueOption) (*Queue, error) {
+	q := &Queue{fs: afero.NewOsFs()}
+	for _, o := range opts {
+		o(q)
+	}
+	if q.path == "" {
+		p, err := config.GetDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		q.path = filepath.Join(filepath.Dir(p), QueueFile)
+	}
+	return q, nil
+}
+
+// Append adds an event to the queue.
+func (q *Queue) Append(e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, errWriteQueue)
+	}
+	if err := q.fs.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return errors.Wrap(err, errWriteQueue)
+	}
+	f, err := q.fs.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, errWriteQueue)
+	}
+	defer f.Close() // nolint:errcheck
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return errors.Wrap(err, errWriteQueue)
+	}
+	return errors.Wrap(f.Close(), errWriteQueue)
+}
+
+// Events returns the queued events. Events that cannot be parsed are
+// skipped.
+func (q *Queue) Events() ([]Event, error) {
+	b, err := afero.ReadFile(q.fs, q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errReadQueue)
+	}
+	events := []Event{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		e := Event{}
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, errors.Wrap(s.Err(), errReadQueue)
+}
+
+// Flush sends all queued events and clears the queue. The queue is left
+// unchanged if the events cannot be sent.
+func (q *Queue) Flush(ctx context.Context, s Sender) error {
+	events, err := q.Events()
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		if err := s.Send(ctx, events); err != nil {
+			return err
+		}
+	}
+	return q.Clear()
+}
+
+// Clear removes all queued events.
+func (q *Queue) Clear() error {
+	if err := q.fs.Remove(q.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, errWriteQueue)
+	}
+	return nil
+}
+
+// HTTPSender sends events as a JSON array to an HTTP endpoint.
+type HTTPSender struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewHTTPSender constructs a new HTTPSender that sends events to the supplied
+// endpoint.
+func NewHTTPSender(endpoint string) *HTTPSender {
+	return &HTTPSender{
+		client:   uphttp.NewClient(),
+		endpoint: endpoint,
+	}
+}
+
+// Send sends the supplied events.
+func (h *HTTPSender) Send(ctx context.Context, events []Event) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(err, errEncodeEvents)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := h.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf(errFmtSendCode, res.StatusCode)
+	}
+	return nil
+}
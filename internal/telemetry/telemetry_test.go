@@ -0,0 +1,104 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+type mockSender struct {
+	events []Event
+	err    error
+}
+
+func (m *mockSender) Send(_ context.Context, events []Event) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func TestQueueFlush(t *testing.T) {
+	errBoom := errors.New("boom")
+	events := []Event{
+		{Command: "controlplane list", DurationMS: 120, Success: true, Version: "v0.1.0", Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Command: "robot create <name>", DurationMS: 80, Version: "v0.1.0", Time: time.Date(2023, 1, 1, 0, 1, 0, 0, time.UTC)},
+	}
+	type want struct {
+		sent   []Event
+		queued []Event
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		events []Event
+		sender *mockSender
+		want   want
+	}{
+		"Empty": {
+			reason: "Flushing an empty queue should not send any events.",
+			sender: &mockSender{},
+		},
+		"SendFailed": {
+			reason: "Events should remain queued if they cannot be sent.",
+			events: events,
+			sender: &mockSender{err: errBoom},
+			want: want{
+				queued: events,
+				err:    errBoom,
+			},
+		},
+		"Successful": {
+			reason: "Queued events should be sent and the queue cleared.",
+			events: events,
+			sender: &mockSender{},
+			want: want{
+				sent: events,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q, err := NewQueue(WithFS(afero.NewMemMapFs()), WithPath("/.up/telemetry.jsonl"))
+			if err != nil {
+				t.Fatalf("NewQueue(...): %s", err)
+			}
+			for _, e := range tc.events {
+				if err := q.Append(e); err != nil {
+					t.Fatalf("Append(...): %s", err)
+				}
+			}
+			err = q.Flush(context.Background(), tc.sender)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFlush(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.sent, tc.sender.events); diff != "" {
+				t.Errorf("\n%s\nFlush(...): -want sent, +got sent:\n%s", tc.reason, diff)
+			}
+			queued, _ := q.Events()
+			if diff := cmp.Diff(tc.want.queued, queued); diff != "" {
+				t.Errorf("\n%s\nFlush(...): -want queued, +got queued:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	errListXRDs   = "unable to list composite resource definitions"
+	errFmtListXRs = "unable to list %s"
+)
+
+var xrdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.crossplane.io",
+	Version:  "v1",
+	Resource: "compositeresourcedefinitions",
+}
+
+// xrdVersion is a version of the resources defined by an XRD.
+type xrdVersion struct {
+	Name          string `json:"name"`
+	Referenceable bool   `json:"referenceable"`
+}
+
+// ListComposites returns the claims and composite resources of every kind
+// defined by the XRDs in the control plane.
+func ListComposites(ctx context.Context, client dynamic.Interface) (claims, composites []unstructured.Unstructured, err error) {
+	xrds, err := client.Resource(xrdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errListXRDs)
+	}
+	for _, xrd := range xrds.Items {
+		p := fieldpath.Pave(xrd.Object)
+		group, _ := p.GetString("spec.group")
+		var versions []xrdVersion
+		_ = p.GetValueInto("spec.versions", &versions)
+		version := referenceableVersion(versions)
+		if version == "" {
+			continue
+		}
+		for _, path := range []string{"spec.names.plural", "spec.claimNames.plural"} {
+			plural, err := p.GetString(path)
+			if err != nil || plural == "" {
+				// Claims are optional.
+				continue
+			}
+			gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: plural}
+			l, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, errFmtListXRs, gvr.GroupResource())
+			}
+			if path == "spec.names.plural" {
+				composites = append(composites, l.Items...)
+				continue
+			}
+			claims = append(claims, l.Items...)
+		}
+	}
+	return claims, composites, nil
+}
+
+// referenceableVersion returns the version of an XRD that composite resources
+// are referred to by, or the first version if none is marked referenceable.
+func referenceableVersion(versions []xrdVersion) string {
+	for _, v := range versions {
+		if v.Referenceable {
+			return v.Name
+		}
+	}
+	if len(versions) > 0 {
+		return versions[0].Name
+	}
+	return ""
+}
+
+// Claimed returns true if the composite resource is bound to a claim.
+func Claimed(xr unstructured.Unstructured) bool {
+	name, _ := fieldpath.Pave(xr.Object).GetString("spec.claimRef.name")
+	return name != ""
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"github.com/upbound/up/internal/upterm"
+)
+
+// ResourceV1 is version v1 of the machine output of a resource and the
+// resources it composes.
+type ResourceV1 struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Name       string       `json:"name"`
+	Namespace  string       `json:"namespace,omitempty"`
+	Synced     string       `json:"synced"`
+	Ready      string       `json:"ready"`
+	Message    string       `json:"message,omitempty"`
+	Event      string       `json:"event,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Resources  []ResourceV1 `json:"resources,omitempty"`
+}
+
+func init() {
+	upterm.RegisterSchema(&Resource{}, upterm.Schema{
+		Kind:    "Resource",
+		Version: "v1",
+		Convert: func(obj any) any {
+			return NewResourceV1(obj.(*Resource))
+		},
+	})
+}
+
+// NewResourceV1 returns version v1 of the machine output of the supplied
+// resource.
+func NewResourceV1(r *Resource) ResourceV1 {
+	out := ResourceV1{
+		APIVersion: r.APIVersion,
+		Kind:       r.Kind,
+		Name:       r.Name,
+		Namespace:  r.Namespace,
+		Synced:     r.Synced,
+		Ready:      r.Ready,
+		Message:    r.Message,
+		Event:      r.Event,
+		Error:      r.Error,
+	}
+	for _, c := range r.Resources {
+		out.Resources = append(out.Resources, NewResourceV1(c))
+	}
+	return out
+}
@@ -0,0 +1,289 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace walks the tree of a Crossplane claim or composite resource and
+// the resources composed by it.
+package trace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/upbound/up/internal/kube"
+)
+
+const (
+	statusNone = "-"
+
+	errFmtMapKind     = "unable to find the resource of kind %s"
+	errFmtMapResource = "unable to find the kind of resource %s"
+	errFmtGet         = "unable to get %s %s"
+	errFmtInvalidRef  = "invalid resource %q: must be of the form TYPE[.GROUP]/NAME"
+)
+
+// Ref refers to a resource in a control plane.
+type Ref struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// String returns the kind and name of the resource, as used by kubectl.
+func (r Ref) String() string {
+	gk := schema.FromAPIVersionAndKind(r.APIVersion, r.Kind).GroupKind()
+	return fmt.Sprintf("%s/%s", strings.ToLower(gk.String()), r.Name)
+}
+
+// Resource is a node in the tree of a claim or composite resource.
+type Resource struct {
+	Ref
+
+	Synced  string `json:"synced"`
+	Ready   string `json:"ready"`
+	Message string `json:"message,omitempty"`
+
+	// Event is the message of the most recent warning event of the
+	// resource, if events are fetched.
+	Event string `json:"event,omitempty"`
+
+	// Error is set if the resource could not be fetched.
+	Error string `json:"error,omitempty"`
+
+	Resources []*Resource `json:"resources,omitempty"`
+}
+
+// A Walker walks the trees of claims and composite resources.
+type Walker struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+	events typedcorev1.EventsGetter
+}
+
+// A WalkerOption configures a Walker.
+type WalkerOption func(w *Walker)
+
+// WithEvents configures the Walker to include the most recent warning event
+// of each resource in the tree.
+func WithEvents(e typedcorev1.EventsGetter) WalkerOption {
+	return func(w *Walker) {
+		w.events = e
+	}
+}
+
+// NewWalker returns a Walker that fetches resources with the supplied client,
+// finding the resource of each kind with the mapper.
+func NewWalker(client dynamic.Interface, mapper meta.RESTMapper, opts ...WalkerOption) *Walker {
+	w := &Walker{client: client, mapper: mapper}
+	for _, o := range opts {
+		o(w)
+	}
+	return w
+}
+
+// Resolve returns a reference to the resource identified by a TYPE[.GROUP]/NAME
+// argument as accepted by kubectl, such as bucket.example.org/data. The
+// namespace is only used for namespaced resources.
+func (w *Walker) Resolve(arg, namespace string) (Ref, error) {
+	typ, name, ok := strings.Cut(arg, "/")
+	if !ok || typ == "" || name == "" {
+		return Ref{}, errors.Errorf(errFmtInvalidRef, arg)
+	}
+	gvr := schema.ParseGroupResource(typ).WithVersion("")
+	gvk, err := w.mapper.KindFor(gvr)
+	if err != nil {
+		return Ref{}, errors.Wrapf(err, errFmtMapResource, typ)
+	}
+	m, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return Ref{}, errors.Wrapf(err, errFmtMapKind, gvk.Kind)
+	}
+	ref := Ref{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: name}
+	if m.Scope.Name() == meta.RESTScopeNameNamespace {
+		ref.Namespace = namespace
+	}
+	return ref, nil
+}
+
+// Walk returns the tree of the referenced resource. A resource that cannot be
+// fetched is included in the tree with its error, so that the rest of the tree
+// can still be shown. An error is only returned if the root cannot be fetched.
+func (w *Walker) Walk(ctx context.Context, ref Ref) (*Resource, error) {
+	u, err := w.get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return w.walk(ctx, u), nil
+}
+
+// FromObject returns the tree of a resource that has already been fetched.
+func (w *Walker) FromObject(ctx context.Context, u *unstructured.Unstructured) *Resource {
+	return w.walk(ctx, u)
+}
+
+func (w *Walker) walk(ctx context.Context, u *unstructured.Unstructured) *Resource {
+	r := NewResource(u)
+	if w.events != nil {
+		r.Event = w.lastWarning(ctx, u)
+	}
+	for _, ref := range children(u) {
+		cu, err := w.get(ctx, ref)
+		if err != nil {
+			r.Resources = append(r.Resources, &Resource{Ref: ref, Synced: statusNone, Ready: statusNone, Error: err.Error()})
+			continue
+		}
+		r.Resources = append(r.Resources, w.walk(ctx, cu))
+	}
+	return r
+}
+
+func (w *Walker) get(ctx context.Context, ref Ref) (*unstructured.Unstructured, error) {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	m, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtMapKind, gvk.Kind)
+	}
+	var ri dynamic.ResourceInterface = w.client.Resource(m.Resource)
+	if m.Scope.Name() == meta.RESTScopeNameNamespace {
+		ri = w.client.Resource(m.Resource).Namespace(ref.Namespace)
+	}
+	u, err := ri.Get(ctx, ref.Name, metav1.GetOptions{})
+	return u, errors.Wrapf(err, errFmtGet, strings.ToLower(ref.Kind), ref.Name)
+}
+
+// lastWarning returns the message of the most recent warning event of the
+// object. Events that cannot be listed are omitted from the tree, as they are
+// only supplementary.
+func (w *Walker) lastWarning(ctx context.Context, u *unstructured.Unstructured) string {
+	// Events of cluster scoped resources are recorded in the default
+	// namespace, so events are listed in all namespaces.
+	l, err := w.events.Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.uid": string(u.GetUID()),
+			"type":               corev1.EventTypeWarning,
+		}.String(),
+	})
+	if err != nil || len(l.Items) == 0 {
+		return ""
+	}
+	latest := l.Items[0]
+	for _, e := range l.Items[1:] {
+		if kube.EventTime(e).After(kube.EventTime(latest)) {
+			latest = e
+		}
+	}
+	return latest.Message
+}
+
+// NewResource returns the node for the supplied object without any of the
+// resources it composes.
+func NewResource(u *unstructured.Unstructured) *Resource {
+	r := &Resource{
+		Ref: Ref{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Name:       u.GetName(),
+			Namespace:  u.GetNamespace(),
+		},
+		Synced: statusNone,
+		Ready:  statusNone,
+	}
+	conditioned := xpv1.ConditionedStatus{}
+	// The path is directly `status` because conditions are inline.
+	_ = fieldpath.Pave(u.Object).GetValueInto("status", &conditioned)
+	for _, c := range conditioned.Conditions {
+		switch c.Type {
+		case xpv1.TypeSynced:
+			r.Synced = string(c.Status)
+		case xpv1.TypeReady:
+			r.Ready = string(c.Status)
+		default:
+			continue
+		}
+		if c.Status != corev1.ConditionTrue && r.Message == "" {
+			r.Message = c.Message
+		}
+	}
+	return r
+}
+
+// children returns the references to the resources composed by the supplied
+// object. A claim refers to its composite resource, and a composite resource
+// to the resources it composes.
+func children(u *unstructured.Unstructured) []Ref {
+	p := fieldpath.Pave(u.Object)
+	var refs []Ref
+	if ref := (Ref{}); p.GetValueInto("spec.resourceRef", &ref) == nil && ref.Name != "" {
+		refs = append(refs, ref)
+	}
+	var composed []Ref
+	if p.GetValueInto("spec.resourceRefs", &composed) == nil {
+		for _, ref := range composed {
+			if ref.Name == "" {
+				// Resources that have not been created yet are
+				// referred to without a name.
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Print prints the tree of the resource, with the status of each resource, the
+// message of any condition that is not true, and the most recent warning
+// event.
+func Print(w io.Writer, r *Resource) error {
+	return printTree(w, r, "", "")
+}
+
+func printTree(w io.Writer, r *Resource, prefix, childPrefix string) error {
+	line := fmt.Sprintf("%s%s  synced=%s ready=%s", prefix, r.Ref, r.Synced, r.Ready)
+	switch {
+	case r.Error != "":
+		line += "  error: " + r.Error
+	case r.Message != "":
+		line += "  " + r.Message
+	}
+	if r.Event != "" {
+		line += "  event: " + r.Event
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return err
+	}
+	for i, c := range r.Resources {
+		branch, next := "├─ ", "│  "
+		if i == len(r.Resources)-1 {
+			branch, next = "└─ ", "   "
+		}
+		if err := printTree(w, c, childPrefix+branch, childPrefix+next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func object(apiVersion, kind, namespace, name string, spec map[string]any, conds ...map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name},
+		"spec":       spec,
+	}}
+	if namespace != "" {
+		u.SetNamespace(namespace)
+	}
+	if len(conds) > 0 {
+		cs := make([]any, len(conds))
+		for i, c := range conds {
+			cs[i] = c
+		}
+		u.Object["status"] = map[string]any{"conditions": cs}
+	}
+	return u
+}
+
+func condition(t, status, message string) map[string]any {
+	return map[string]any{"type": t, "status": status, "message": message, "reason": "Test", "lastTransitionTime": "2023-10-01T00:00:00Z"}
+}
+
+func mapper() meta.RESTMapper {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XBucket"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "BucketPolicy"}, meta.RESTScopeRoot)
+	return m
+}
+
+func TestWalk(t *testing.T) {
+	claim := object("example.org/v1", "Bucket", "default", "data", map[string]any{
+		"resourceRef": map[string]any{"apiVersion": "example.org/v1", "kind": "XBucket", "name": "data-x7k2p"},
+	}, condition("Synced", "True", ""), condition("Ready", "False", "Composite resource is not ready"))
+	xr := object("example.org/v1", "XBucket", "", "data-x7k2p", map[string]any{
+		"claimRef": map[string]any{"apiVersion": "example.org/v1", "kind": "Bucket", "namespace": "default", "name": "data"},
+		"resourceRefs": []any{
+			map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket", "name": "data-x7k2p-b"},
+			map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "BucketPolicy", "name": "data-x7k2p-p"},
+			map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "BucketPolicy"},
+		},
+	}, condition("Synced", "True", ""), condition("Ready", "False", "Unready resources: data-x7k2p-b"))
+	bucket := object("s3.aws.upbound.io/v1beta1", "Bucket", "", "data-x7k2p-b", map[string]any{},
+		condition("Synced", "False", "cannot create bucket: AccessDenied"), condition("Ready", "False", ""))
+
+	cases := map[string]struct {
+		reason string
+		ref    Ref
+		want   *Resource
+		err    error
+	}{
+		"Claim": {
+			reason: "The tree of a claim should include its composite resource and the resources it composes, including those that cannot be fetched.",
+			ref:    Ref{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "default", Name: "data"},
+			want: &Resource{
+				Ref:     Ref{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "default", Name: "data"},
+				Synced:  "True",
+				Ready:   "False",
+				Message: "Composite resource is not ready",
+				Resources: []*Resource{{
+					Ref:     Ref{APIVersion: "example.org/v1", Kind: "XBucket", Name: "data-x7k2p"},
+					Synced:  "True",
+					Ready:   "False",
+					Message: "Unready resources: data-x7k2p-b",
+					Resources: []*Resource{
+						{
+							Ref:     Ref{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Name: "data-x7k2p-b"},
+							Synced:  "False",
+							Ready:   "False",
+							Message: "cannot create bucket: AccessDenied",
+						},
+						{
+							Ref:    Ref{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "BucketPolicy", Name: "data-x7k2p-p"},
+							Synced: statusNone,
+							Ready:  statusNone,
+							Error:  `unable to get bucketpolicy data-x7k2p-p: bucketpolicies.s3.aws.upbound.io "data-x7k2p-p" not found`,
+						},
+					},
+				}},
+			},
+		},
+		"MissingRoot": {
+			reason: "An error should be returned if the root cannot be fetched.",
+			ref:    Ref{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "default", Name: "missing"},
+			err:    errors.Wrapf(kerrors.NewNotFound(schema.GroupResource{Group: "example.org", Resource: "buckets"}, "missing"), errFmtGet, "bucket", "missing"),
+		},
+		"UnknownKind": {
+			reason: "An error should be returned if the resource of a kind cannot be found.",
+			ref:    Ref{APIVersion: "example.org/v1", Kind: "Table", Name: "t"},
+			err:    errors.Wrapf(&meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.org", Kind: "Table"}, SearchedVersions: []string{"v1"}}, errFmtMapKind, "Table"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClient(runtime.NewScheme(), claim, xr, bucket)
+			got, err := NewWalker(client, mapper()).Walk(context.Background(), tc.ref)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWalk(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWalk(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWalkEvents(t *testing.T) {
+	xr := object("example.org/v1", "XBucket", "", "data-x7k2p", map[string]any{
+		"resourceRefs": []any{
+			map[string]any{"apiVersion": "s3.aws.upbound.io/v1beta1", "kind": "Bucket", "name": "data-x7k2p-b"},
+		},
+	})
+	xr.SetUID("xr")
+	bucket := object("s3.aws.upbound.io/v1beta1", "Bucket", "", "data-x7k2p-b", map[string]any{})
+	bucket.SetUID("bucket")
+	event := func(name, uid, typ, message string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+			InvolvedObject: corev1.ObjectReference{UID: types.UID(uid)},
+			Type:           typ,
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	kc := kfake.NewSimpleClientset(
+		event("a", "bucket", corev1.EventTypeWarning, "cannot create bucket: AccessDenied", now.Add(-time.Hour)),
+		event("b", "bucket", corev1.EventTypeWarning, "cannot create bucket: BucketAlreadyExists", now),
+		event("c", "bucket", corev1.EventTypeNormal, "Successfully requested creation", now.Add(time.Hour)),
+		event("d", "xr", corev1.EventTypeNormal, "Successfully composed resources", now),
+	)
+	// The fake clientset does not filter by field selectors.
+	kc.PrependReactor("list", "events", func(action ktesting.Action) (bool, runtime.Object, error) {
+		sel := action.(ktesting.ListAction).GetListRestrictions().Fields
+		all, err := kc.Tracker().List(corev1.SchemeGroupVersion.WithResource("events"), corev1.SchemeGroupVersion.WithKind("Event"), "")
+		if err != nil {
+			return true, nil, err
+		}
+		l := &corev1.EventList{}
+		for _, e := range all.(*corev1.EventList).Items {
+			if sel.Matches(fields.Set{"involvedObject.uid": string(e.InvolvedObject.UID), "type": e.Type}) {
+				l.Items = append(l.Items, e)
+			}
+		}
+		return true, l, nil
+	})
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), xr, bucket)
+	got, err := NewWalker(client, mapper(), WithEvents(kc.CoreV1())).Walk(context.Background(), Ref{APIVersion: "example.org/v1", Kind: "XBucket", Name: "data-x7k2p"})
+	if err != nil {
+		t.Fatalf("Walk(...): unexpected error: %s", err)
+	}
+	want := &Resource{
+		Ref:    Ref{APIVersion: "example.org/v1", Kind: "XBucket", Name: "data-x7k2p"},
+		Synced: statusNone,
+		Ready:  statusNone,
+		Resources: []*Resource{{
+			Ref:    Ref{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Name: "data-x7k2p-b"},
+			Synced: statusNone,
+			Ready:  statusNone,
+			Event:  "cannot create bucket: BucketAlreadyExists",
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Walk(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		arg       string
+		namespace string
+		want      Ref
+		err       error
+	}{
+		"Claim": {
+			reason:    "A namespaced resource should be resolved in the supplied namespace.",
+			arg:       "bucket.example.org/data",
+			namespace: "team-a",
+			want:      Ref{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "team-a", Name: "data"},
+		},
+		"Composite": {
+			reason:    "A cluster scoped resource should be resolved without a namespace.",
+			arg:       "xbuckets.example.org/data-x7k2p",
+			namespace: "team-a",
+			want:      Ref{APIVersion: "example.org/v1", Kind: "XBucket", Name: "data-x7k2p"},
+		},
+		"NoName": {
+			reason: "A resource must be named.",
+			arg:    "bucket.example.org",
+			err:    errors.Errorf(errFmtInvalidRef, "bucket.example.org"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewWalker(nil, mapper()).Resolve(tc.arg, tc.namespace)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	r := &Resource{
+		Ref:     Ref{APIVersion: "example.org/v1", Kind: "Bucket", Namespace: "default", Name: "data"},
+		Synced:  "True",
+		Ready:   "False",
+		Message: "Composite resource is not ready",
+		Resources: []*Resource{{
+			Ref:    Ref{APIVersion: "example.org/v1", Kind: "XBucket", Name: "data-x7k2p"},
+			Synced: "True",
+			Ready:  "False",
+			Resources: []*Resource{
+				{Ref: Ref{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "Bucket", Name: "b"}, Synced: "True", Ready: "True", Event: "cannot update bucket"},
+				{Ref: Ref{APIVersion: "s3.aws.upbound.io/v1beta1", Kind: "BucketPolicy", Name: "p"}, Synced: "-", Ready: "-", Error: "not found"},
+			},
+		}},
+	}
+	want := `bucket.example.org/data  synced=True ready=False  Composite resource is not ready
+└─ xbucket.example.org/data-x7k2p  synced=True ready=False
+   ├─ bucket.s3.aws.upbound.io/b  synced=True ready=True  event: cannot update bucket
+   └─ bucketpolicy.s3.aws.upbound.io/p  synced=- ready=-  error: not found
+`
+	b := &bytes.Buffer{}
+	if err := Print(b, r); err != nil {
+		t.Fatalf("Print(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("Print(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestListComposites(t *testing.T) {
+	xrd := func(kind, plural, claimKind, claimPlural string) *unstructured.Unstructured {
+		spec := map[string]any{
+			"group": "example.org",
+			"names": map[string]any{"kind": kind, "plural": plural},
+			"versions": []any{
+				map[string]any{"name": "v1alpha1"},
+				map[string]any{"name": "v1", "referenceable": true},
+			},
+		}
+		if claimKind != "" {
+			spec["claimNames"] = map[string]any{"kind": claimKind, "plural": claimPlural}
+		}
+		return object("apiextensions.crossplane.io/v1", "CompositeResourceDefinition", "", plural+".example.org", spec)
+	}
+	claim := object("example.org/v1", "Bucket", "default", "data", map[string]any{})
+	xr := object("example.org/v1", "XBucket", "", "data-x7k2p", map[string]any{})
+	network := object("example.org/v1", "XNetwork", "", "net", map[string]any{})
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		xrdGVR: "CompositeResourceDefinitionList",
+		{Group: "example.org", Version: "v1", Resource: "buckets"}:   "BucketList",
+		{Group: "example.org", Version: "v1", Resource: "xbuckets"}:  "XBucketList",
+		{Group: "example.org", Version: "v1", Resource: "xnetworks"}: "XNetworkList",
+	}, xrd("XBucket", "xbuckets", "Bucket", "buckets"), xrd("XNetwork", "xnetworks", "", ""), claim, xr, network)
+
+	claims, xrs, err := ListComposites(context.Background(), client)
+	if err != nil {
+		t.Fatalf("ListComposites(...): unexpected error: %s", err)
+	}
+	names := func(us []unstructured.Unstructured) []string {
+		out := make([]string, len(us))
+		for i, u := range us {
+			out[i] = u.GetName()
+		}
+		// The fake client does not list resources in a stable order.
+		sort.Strings(out)
+		return out
+	}
+	if diff := cmp.Diff([]string{"data"}, names(claims)); diff != "" {
+		t.Errorf("ListComposites(...): -want claims, +got claims:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"data-x7k2p", "net"}, names(xrs)); diff != "" {
+		t.Errorf("ListComposites(...): -want composites, +got composites:\n%s", diff)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upbound
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+)
+
+const (
+	errListOrganizations = "unable to list organizations to discover account"
+	errAmbiguousAccount  = "multiple organizations are available, specify one with --account"
+	errSelectAccount     = "unable to select account"
+
+	selectAccountText = "Select the account used to execute command"
+)
+
+// ErrAmbiguousAccount indicates that no account was specified and the current
+// user belongs to multiple organizations.
+var ErrAmbiguousAccount = errors.New(errAmbiguousAccount)
+
+// OrganizationLister lists the organizations accessible to the current user.
+type OrganizationLister interface {
+	List(ctx context.Context) ([]organizations.Organization, error)
+}
+
+// AccountSelector picks one of the given account names.
+type AccountSelector func(names []string) (string, error)
+
+// DiscoverAccount indicates that Context should discover the account from the
+// organizations accessible to the current user if neither a flag nor the
+// profile supplies one. A single organization is used as is, otherwise the
+// user is prompted to choose when running in an interactive terminal. The
+// organizations are listed using the supplied context, which should carry the
+// command's timeout.
+func DiscoverAccount(ctx context.Context) Option {
+	return func(c *Context) {
+		c.discoverCtx = ctx
+	}
+}
+
+// WithOrganizationLister overrides the lister used for account discovery.
+func WithOrganizationLister(l OrganizationLister) Option {
+	return func(ctx *Context) {
+		ctx.orgLister = l
+	}
+}
+
+// WithAccountSelector overrides how the user chooses between multiple
+// discovered accounts.
+func WithAccountSelector(s AccountSelector) Option {
+	return func(ctx *Context) {
+		ctx.selectAccount = s
+	}
+}
+
+// discoverAccountName determines the account from the organizations the
+// current user has access to. An empty name is returned if the user does not
+// belong to any organization.
+func (c *Context) discoverAccountName(ctx context.Context) (string, error) {
+	l := c.orgLister
+	if l == nil {
+		cfg, err := c.BuildSDKConfig()
+		if err != nil {
+			return "", err
+		}
+		l = organizations.NewClient(cfg)
+	}
+	orgs, err := l.List(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, errListOrganizations)
+	}
+	names := make([]string, len(orgs))
+	for i, o := range orgs {
+		names[i] = o.Name
+	}
+	sort.Strings(names)
+
+	switch len(names) {
+	case 0:
+		return "", nil
+	case 1:
+		return names[0], nil
+	}
+	s := c.selectAccount
+	if s == nil {
+		s = selectAccountInteractive
+	}
+	name, err := s(names)
+	return name, errors.Wrap(err, errSelectAccount)
+}
+
+// selectAccountInteractive prompts the user to select an account, refusing to
+// do so if stdin is not an interactive terminal.
+func selectAccountInteractive(names []string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", ErrAmbiguousAccount
+	}
+	return pterm.DefaultInteractiveSelect.
+		WithOptions(names).
+		WithDefaultText(selectAccountText).
+		Show()
+}
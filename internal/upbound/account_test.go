@@ -0,0 +1,124 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upbound
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up-sdk-go/service/organizations"
+)
+
+type fakeLister struct {
+	orgs []organizations.Organization
+	err  error
+}
+
+func (f *fakeLister) List(context.Context) ([]organizations.Organization, error) {
+	return f.orgs, f.err
+}
+
+func TestDiscoverAccountName(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		lister   OrganizationLister
+		selector AccountSelector
+	}
+	type want struct {
+		name string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrList": {
+			reason: "An error listing organizations should be returned.",
+			args: args{
+				lister: &fakeLister{err: errBoom},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListOrganizations),
+			},
+		},
+		"NoOrganizations": {
+			reason: "No account should be discovered if the user belongs to no organization.",
+			args: args{
+				lister: &fakeLister{},
+			},
+			want: want{},
+		},
+		"SingleOrganization": {
+			reason: "The only organization should be used without prompting.",
+			args: args{
+				lister: &fakeLister{orgs: []organizations.Organization{{Name: "cool-org"}}},
+				selector: func([]string) (string, error) {
+					return "", errBoom
+				},
+			},
+			want: want{
+				name: "cool-org",
+			},
+		},
+		"MultipleOrganizations": {
+			reason: "The user should choose between sorted organization names.",
+			args: args{
+				lister: &fakeLister{orgs: []organizations.Organization{{Name: "zeta"}, {Name: "alpha"}}},
+				selector: func(names []string) (string, error) {
+					if diff := cmp.Diff([]string{"alpha", "zeta"}, names); diff != "" {
+						return "", errors.Errorf("unexpected names: %s", diff)
+					}
+					return names[1], nil
+				},
+			},
+			want: want{
+				name: "zeta",
+			},
+		},
+		"ErrSelect": {
+			reason: "An error selecting between organizations should be returned.",
+			args: args{
+				lister: &fakeLister{orgs: []organizations.Organization{{Name: "zeta"}, {Name: "alpha"}}},
+				selector: func([]string) (string, error) {
+					return "", errBoom
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errSelectAccount),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Context{}
+			WithOrganizationLister(tc.args.lister)(c)
+			WithAccountSelector(tc.args.selector)(c)
+
+			got, err := c.discoverAccountName(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ndiscoverAccountName(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\ndiscoverAccountName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,11 +16,15 @@ package upbound
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -30,12 +34,10 @@ import (
 	"github.com/upbound/up-sdk-go"
 
 	"github.com/upbound/up/internal/config"
+	uphttp "github.com/upbound/up/internal/http"
 )
 
 const (
-	// UserAgent is the default user agent to use to make requests to the
-	// Upbound API.
-	UserAgent = "up-cli"
 	// CookieName is the default cookie name used to identify a session token.
 	CookieName = "SID"
 
@@ -49,8 +51,20 @@ const (
 
 	// Default registry subdomain.
 	xpkgSubdomain = "xpkg."
+
+	// Directory, relative to the home directory, in which API responses are
+	// cached.
+	responseCacheDir = ".cache/up/http"
+
+	// PredictTimeout bounds the API calls made to predict shell completions,
+	// so that completing a command never hangs the shell.
+	PredictTimeout = 5 * time.Second
 )
 
+// cachedPaths are the API paths whose responses are cached and revalidated, as
+// they are listed by many commands and completions.
+var cachedPaths = []string{"/v1/accounts", "/v1/organizations", "/v1/controlPlanes"}
+
 const (
 	errProfileNotFoundFmt = "profile not found with identifier: %s"
 )
@@ -92,8 +106,12 @@ type Context struct {
 	WrapTransport func(rt http.RoundTripper) http.RoundTripper
 
 	allowMissingProfile bool
+	discoverCtx         context.Context
+	orgLister           OrganizationLister
+	selectAccount       AccountSelector
 	cfgPath             string
 	fs                  afero.Fs
+	responseCache       *uphttp.ResponseCache
 }
 
 // Option modifies a Context
@@ -185,6 +203,17 @@ func NewFromFlags(f Flags, opts ...Option) (*Context, error) { //nolint:gocyclo
 	c.Account = of.Account
 	c.Domain = of.Domain
 
+	if home, err := os.UserHomeDir(); err == nil {
+		prefixes := make([]string, len(cachedPaths))
+		for i, p := range cachedPaths {
+			prefixes[i] = path.Join("/", c.APIEndpoint.Path, p)
+		}
+		c.responseCache = uphttp.NewResponseCache(filepath.Join(home, responseCacheDir),
+			uphttp.WithCacheFS(c.fs),
+			uphttp.WithCachedPaths(prefixes...),
+		)
+	}
+
 	// If account has not already been set, use the profile default.
 	if c.Account == "" {
 		c.Account = c.Profile.Account
@@ -209,9 +238,30 @@ func NewFromFlags(f Flags, opts ...Option) (*Context, error) { //nolint:gocyclo
 	default:
 	}
 
+	// If neither the flags nor the profile supply an account, fall back to
+	// the organizations the session has access to.
+	if c.Account == "" && c.discoverCtx != nil && c.Profile.Session != "" {
+		if c.Account, err = c.discoverAccountName(c.discoverCtx); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+// ModifyConfig applies the supplied function to the config as currently
+// stored by the config source and writes the result, holding the config lock
+// throughout so that concurrent changes made by other up processes are not
+// lost. Cfg is replaced by the modified config.
+func (c *Context) ModifyConfig(fn func(*config.Config) error) error {
+	cfg, err := c.CfgSrc.ModifyConfig(fn)
+	if err != nil {
+		return err
+	}
+	c.Cfg = cfg
+	return nil
+}
+
 // BuildSDKConfig builds an Upbound SDK config suitable for usage with any
 // service client.
 func (c *Context) BuildSDKConfig() (*up.Config, error) {
@@ -226,27 +276,41 @@ func (c *Context) BuildSDKConfig() (*up.Config, error) {
 		},
 		})
 	}
-	var tr http.RoundTripper = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: c.InsecureSkipTLSVerify, //nolint:gosec
-		},
-	}
-	if c.WrapTransport != nil {
-		tr = c.WrapTransport(tr)
-	}
 	client := up.NewClient(func(u *up.HTTPClient) {
 		u.BaseURL = c.APIEndpoint
-		u.HTTP = &http.Client{
-			Jar:       cj,
-			Transport: tr,
-		}
-		u.UserAgent = UserAgent
+		u.HTTP = c.HTTPClient(uphttp.WithCookieJar(cj), uphttp.WithResponseCache(c.responseCache))
+		u.UserAgent = uphttp.UserAgent()
+		u.ErrorHandler = &requestIDErrorHandler{handler: &up.DefaultErrorHandler{}}
 	})
 	return up.NewConfig(func(conf *up.Config) {
 		conf.Client = client
 	}), nil
 }
 
+// requestIDErrorHandler annotates errors returned by the Upbound API with the
+// ID of the failed request, so that they can be shared with Upbound support.
+type requestIDErrorHandler struct {
+	handler up.ResponseErrorHandler
+}
+
+// Handle handles HTTP response errors from the Upbound API.
+func (h *requestIDErrorHandler) Handle(res *http.Response) error {
+	err := h.handler.Handle(res)
+	if err == nil || res.Request == nil {
+		return err
+	}
+	return uphttp.WithRequestID(err, res.Request.Header.Get(uphttp.RequestIDHeader))
+}
+
+// HTTPClient builds an HTTP client that honors the TLS and debug settings of
+// the context and shares its connection pool with other clients.
+func (c *Context) HTTPClient(opts ...uphttp.ClientOption) *http.Client {
+	return uphttp.NewClient(append([]uphttp.ClientOption{
+		uphttp.WithInsecureSkipTLSVerify(c.InsecureSkipTLSVerify),
+		uphttp.WithWrapTransport(c.WrapTransport),
+	}, opts...)...)
+}
+
 // applyOverrides applies applicable overrides to the given Flags based on the
 // pre-existing configs, if there are any.
 func (c *Context) applyOverrides(f Flags, profileName string) (Flags, error) {
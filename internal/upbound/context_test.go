@@ -15,9 +15,13 @@
 package upbound
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alecthomas/kong"
@@ -27,7 +31,12 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/spf13/afero"
 
+	"github.com/upbound/up-sdk-go"
+	uerrors "github.com/upbound/up-sdk-go/errors"
+	"github.com/upbound/up-sdk-go/service/organizations"
+
 	"github.com/upbound/up/internal/config"
+	uphttp "github.com/upbound/up/internal/http"
 )
 
 var (
@@ -104,7 +113,17 @@ func withURL(uri string) *url.URL {
 	return u
 }
 
+// contextLister fails with the error of the context it lists with, if any.
+type contextLister struct{}
+
+func (contextLister) List(ctx context.Context) ([]organizations.Organization, error) {
+	return nil, ctx.Err()
+}
+
 func TestNewFromFlags(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
 	type args struct {
 		flags []string
 		opts  []Option
@@ -296,6 +315,48 @@ func TestNewFromFlags(t *testing.T) {
 				wrapTransport: true,
 			},
 		},
+		"DiscoverAccountUsesContext": {
+			reason: "Account discovery should list organizations with the supplied context, so that it honors the command's timeout.",
+			args: args{
+				flags: []string{},
+				opts: []Option{
+					withConfig(defaultConfigJSON),
+					withPath("/.up/config.json"),
+					DiscoverAccount(canceled),
+					WithOrganizationLister(&contextLister{}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(context.Canceled, errListOrganizations),
+			},
+		},
+		"DiscoverAccountSkippedWhenSet": {
+			reason: "Organizations should not be listed if the account is already set.",
+			args: args{
+				flags: []string{"--account=my-org"},
+				opts: []Option{
+					withConfig(defaultConfigJSON),
+					withPath("/.up/config.json"),
+					DiscoverAccount(canceled),
+					WithOrganizationLister(&contextLister{}),
+				},
+			},
+			want: want{
+				c: &Context{
+					ProfileName: "default",
+					Account:     "my-org",
+					APIEndpoint: withURL("https://api.upbound.io"),
+					Domain:      withURL("https://upbound.io"),
+					Profile: config.Profile{
+						ID:      "someone@upbound.io",
+						Type:    config.UserProfileType,
+						Session: "a token",
+					},
+					ProxyEndpoint:    withURL("https://proxy.upbound.io/v1/controlPlanes"),
+					RegistryEndpoint: withURL("https://xpkg.upbound.io"),
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -335,3 +396,47 @@ func TestNewFromFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestIDErrorHandler(t *testing.T) {
+	type want struct {
+		err      string
+		notFound bool
+	}
+	cases := map[string]struct {
+		reason string
+		status int
+		want   want
+	}{
+		"Success": {
+			reason: "Successful responses should not return an error.",
+			status: http.StatusOK,
+		},
+		"NotFound": {
+			reason: "Error responses should carry the ID of the failed request and keep their SDK semantics.",
+			status: http.StatusNotFound,
+			want: want{
+				err:      "Not Found (request ID: failed-request)",
+				notFound: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://example.org", nil)
+			req.Header.Set(uphttp.RequestIDHeader, "failed-request")
+			res := &http.Response{
+				StatusCode: tc.status,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Request:    req,
+			}
+			err := (&requestIDErrorHandler{handler: &up.DefaultErrorHandler{}}).Handle(res)
+			got := want{notFound: uerrors.IsNotFound(err)}
+			if err != nil {
+				got.err = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pterm/pterm"
+)
+
+const (
+	noValue = "<none>"
+
+	errFmtInvalidColumn = "invalid custom column %q: must be of the form HEADER:.path"
+	errConvertObject    = "unable to convert object for custom columns"
+)
+
+// A Column of custom columns output.
+type Column struct {
+	// Header of the column.
+	Header string
+	// Path of the field shown in the column, e.g. .status or
+	// .controlPlane.name, relative to the object's JSON representation.
+	Path string
+}
+
+// CustomColumns shape table output into user supplied columns, similar to
+// kubectl's custom-columns output.
+type CustomColumns []Column
+
+// ParseCustomColumns parses custom columns of the form
+// HEADER:.path[,HEADER:.path...].
+func ParseCustomColumns(spec string) (CustomColumns, error) {
+	parts := strings.Split(spec, ",")
+	cols := make(CustomColumns, len(parts))
+	for i, p := range parts {
+		header, path, ok := strings.Cut(p, ":")
+		if !ok || header == "" || !strings.HasPrefix(path, ".") || len(path) < 2 {
+			return nil, errors.Errorf(errFmtInvalidColumn, p)
+		}
+		cols[i] = Column{Header: header, Path: strings.TrimPrefix(path, ".")}
+	}
+	return cols, nil
+}
+
+// headers returns the column headers.
+func (c CustomColumns) headers() []string {
+	h := make([]string, len(c))
+	for i, col := range c {
+		h[i] = col.Header
+	}
+	return h
+}
+
+// values returns the values of the columns for the supplied object. Fields
+// that are not present are shown as <none>.
+func (c CustomColumns) values(obj any) ([]string, error) {
+	v, err := toJSONValue(obj)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New(errConvertObject)
+	}
+	p := fieldpath.Pave(m)
+	vals := make([]string, len(c))
+	for i, col := range c {
+		v, err := p.GetValue(col.Path)
+		if err != nil || v == nil {
+			vals[i] = noValue
+			continue
+		}
+		if s, ok := v.(string); ok {
+			vals[i] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, errConvertObject)
+		}
+		vals[i] = string(b)
+	}
+	return vals, nil
+}
+
+// print renders the columns of the supplied object, or of each element if it
+// is a slice or array.
+func (c CustomColumns) print(tp *pterm.TablePrinter, obj any) error {
+	items := []any{obj}
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Array || v.Kind() == reflect.Slice {
+		items = make([]any, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+	data := make([][]string, 0, len(items)+1)
+	data = append(data, c.headers())
+	for _, item := range items {
+		vals, err := c.values(item)
+		if err != nil {
+			return err
+		}
+		data = append(data, vals)
+	}
+	return tp.WithHasHeader().WithData(data).Render()
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseCustomColumns(t *testing.T) {
+	type want struct {
+		cols CustomColumns
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Valid": {
+			reason: "Columns should be parsed from a custom-columns format.",
+			s:      "NAME:.name,STATUS:.status.phase",
+			want: want{
+				cols: CustomColumns{{Header: "NAME", Path: "name"}, {Header: "STATUS", Path: "status.phase"}},
+			},
+		},
+		"NoPath": {
+			reason: "Columns without a path should be rejected.",
+			s:      "NAME",
+			want: want{
+				err: errors.Errorf(errFmtInvalidColumn, "NAME"),
+			},
+		},
+		"RelativePath": {
+			reason: "Column paths must start with a dot.",
+			s:      "NAME:name",
+			want: want{
+				err: errors.Errorf(errFmtInvalidColumn, "NAME:name"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cols, err := ParseCustomColumns(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseCustomColumns(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cols, cols); diff != "" {
+				t.Errorf("\n%s\nParseCustomColumns(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCustomColumnsValues(t *testing.T) {
+	type item struct {
+		Name   string         `json:"name"`
+		Labels map[string]any `json:"labels,omitempty"`
+		Count  int            `json:"count"`
+	}
+	cols := CustomColumns{{Header: "NAME", Path: "name"}, {Header: "ENV", Path: "labels.env"}, {Header: "COUNT", Path: "count"}}
+	cases := map[string]struct {
+		reason string
+		obj    any
+		want   []string
+	}{
+		"Struct": {
+			reason: "Values should be extracted from the JSON representation of a struct.",
+			obj:    item{Name: "prod", Labels: map[string]any{"env": "prod"}, Count: 3},
+			want:   []string{"prod", "prod", "3"},
+		},
+		"MissingField": {
+			reason: "Missing fields should be shown as <none>.",
+			obj:    item{Name: "dev"},
+			want:   []string{"dev", noValue, "0"},
+		},
+		"Unstructured": {
+			reason: "Values should be extracted from the content of unstructured objects.",
+			obj: unstructured.Unstructured{Object: map[string]any{
+				"name":   "staging",
+				"labels": map[string]any{"env": "staging"},
+			}},
+			want: []string{"staging", "staging", noValue},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cols.values(tc.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"k8s.io/client-go/tools/clientcmd"
+
+	uerrors "github.com/upbound/up-sdk-go/errors"
+
+	"github.com/upbound/up/internal/upbound"
+)
+
+// SuggestedError is a common failure explained in human-friendly terms,
+// along with a suggestion of how to resolve it.
+type SuggestedError struct {
+	err error
+
+	Message    string
+	Suggestion string
+}
+
+// Error returns the explanation and the suggestion.
+func (e *SuggestedError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.Message, e.Suggestion)
+}
+
+// Unwrap returns the explained error.
+func (e *SuggestedError) Unwrap() error {
+	return e.err
+}
+
+// explainer explains the error if it recognizes it.
+type explainer func(err error) *SuggestedError
+
+var explainers = []explainer{
+	explainExpiredLogin,
+	explainAmbiguousAccount,
+	explainContextNotFound,
+	explainRegistryUnauthorized,
+}
+
+// Explain replaces common failures, such as an expired login or an unknown
+// kubeconfig context, with a human-friendly explanation that suggests the
+// next command to run. Other errors are returned unchanged.
+func Explain(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, e := range explainers {
+		if s := e(err); s != nil {
+			return s
+		}
+	}
+	return err
+}
+
+func explainExpiredLogin(err error) *SuggestedError {
+	var uerr *uerrors.Error
+	if !errors.As(err, &uerr) || uerr.Status != http.StatusUnauthorized {
+		return nil
+	}
+	return &SuggestedError{
+		err:        err,
+		Message:    "Your Upbound session has expired or is not valid.",
+		Suggestion: "Run 'up login' to log in again.",
+	}
+}
+
+func explainAmbiguousAccount(err error) *SuggestedError {
+	if !errors.Is(err, upbound.ErrAmbiguousAccount) {
+		return nil
+	}
+	return &SuggestedError{
+		err:        err,
+		Message:    "No account is set and you belong to multiple organizations.",
+		Suggestion: "Pass one with --account or UP_ACCOUNT, or log in with 'up login --account <organization>' to store it in the profile.",
+	}
+}
+
+func explainContextNotFound(err error) *SuggestedError {
+	if !clientcmd.IsContextNotFound(err) && !errors.Is(err, clientcmd.ErrNoContext) {
+		return nil
+	}
+	return &SuggestedError{
+		err:        err,
+		Message:    "The kubeconfig context was not found.",
+		Suggestion: "Run 'kubectl config get-contexts' to list the available contexts, or pass a different kubeconfig with --kubeconfig.",
+	}
+}
+
+func explainRegistryUnauthorized(err error) *SuggestedError {
+	var terr *transport.Error
+	if !errors.As(err, &terr) || terr.StatusCode != http.StatusUnauthorized {
+		return nil
+	}
+	return &SuggestedError{
+		err:        err,
+		Message:    "The registry rejected the credentials.",
+		Suggestion: "Run 'up login' to refresh your credentials, and check that the account has access to the repository.",
+	}
+}
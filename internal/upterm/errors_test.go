@@ -0,0 +1,92 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"k8s.io/client-go/tools/clientcmd"
+
+	uerrors "github.com/upbound/up-sdk-go/errors"
+
+	"github.com/upbound/up/internal/upbound"
+)
+
+func TestExplain(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason     string
+		err        error
+		suggestion string
+	}{
+		"Nil": {
+			reason: "A nil error should not be explained.",
+		},
+		"Unknown": {
+			reason: "An unknown error should be returned unchanged.",
+			err:    errBoom,
+		},
+		"ExpiredLogin": {
+			reason:     "An unauthorized response from the API should suggest logging in again.",
+			err:        errors.Wrap(&uerrors.Error{Status: http.StatusUnauthorized, Title: "Unauthorized"}, "cannot list control planes"),
+			suggestion: "Run 'up login' to log in again.",
+		},
+		"NotFound": {
+			reason: "Other API errors should be returned unchanged.",
+			err:    &uerrors.Error{Status: http.StatusNotFound, Title: "Not Found"},
+		},
+		"AmbiguousAccount": {
+			reason:     "An ambiguous account should suggest passing one.",
+			err:        errors.Wrap(upbound.ErrAmbiguousAccount, "unable to select account"),
+			suggestion: "Pass one with --account or UP_ACCOUNT, or log in with 'up login --account <organization>' to store it in the profile.",
+		},
+		"ContextNotFound": {
+			reason:     "An unknown kubeconfig context should suggest listing the contexts.",
+			err:        errors.Wrap(clientcmd.ErrNoContext, "cannot load kubeconfig"),
+			suggestion: "Run 'kubectl config get-contexts' to list the available contexts, or pass a different kubeconfig with --kubeconfig.",
+		},
+		"RegistryUnauthorized": {
+			reason:     "An unauthorized response from a registry should suggest refreshing the credentials.",
+			err:        errors.Wrap(&transport.Error{StatusCode: http.StatusUnauthorized}, "failed to push package"),
+			suggestion: "Run 'up login' to refresh your credentials, and check that the account has access to the repository.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Explain(tc.err)
+			var s *SuggestedError
+			if !errors.As(got, &s) {
+				if got != tc.err { //nolint:errorlint // Unexplained errors must be returned as is.
+					t.Errorf("\n%s\nExplain(...): want unchanged error %v, got %v", tc.reason, tc.err, got)
+				}
+				if tc.suggestion != "" {
+					t.Errorf("\n%s\nExplain(...): want suggestion %q, got none", tc.reason, tc.suggestion)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.suggestion, s.Suggestion); diff != "" {
+				t.Errorf("\n%s\nExplain(...): -want suggestion, +got suggestion:\n%s", tc.reason, diff)
+			}
+			if !errors.Is(got, tc.err) {
+				t.Errorf("\n%s\nExplain(...): explained error does not wrap %v", tc.reason, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/alecthomas/kong"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	outputCustomColumns = "custom-columns="
+	outputJSONPath      = "jsonpath="
+	outputGoTemplate    = "go-template="
+
+	errFmtInvalidOutput = "invalid output format %q: must be one of custom-columns=HEADER:.path[,HEADER:.path...], jsonpath=TEMPLATE, or go-template=TEMPLATE"
+	errParseJSONPath    = "unable to parse jsonpath template"
+	errParseGoTemplate  = "unable to parse go-template"
+	errMarshalObject    = "unable to convert object for output"
+	errFmtWatchNotList  = "unable to watch %T: not a list"
+)
+
+// Output shapes the output of get and list commands, similar to kubectl's
+// -o flag. It can be used as a kong flag of the form custom-columns=SPEC,
+// jsonpath=TEMPLATE, or go-template=TEMPLATE, and replaces the output format
+// of the ObjectPrinter when set.
+type Output struct {
+	columns  CustomColumns
+	jsonPath *jsonpath.JSONPath
+	template *template.Template
+}
+
+// OutputFlags are the flags of commands that print objects, which may be
+// shaped with -o.
+type OutputFlags struct {
+	Output Output `short:"o" help:"Shape the output with custom-columns=HEADER:.path[,HEADER:.path...], jsonpath=TEMPLATE, or go-template=TEMPLATE. Fields are referred to by their names in JSON output."`
+}
+
+// ParseOutput parses an output format.
+func ParseOutput(s string) (Output, error) {
+	switch {
+	case strings.HasPrefix(s, outputCustomColumns) && len(s) > len(outputCustomColumns):
+		cols, err := ParseCustomColumns(strings.TrimPrefix(s, outputCustomColumns))
+		return Output{columns: cols}, err
+	case strings.HasPrefix(s, outputJSONPath):
+		tmpl := strings.TrimPrefix(s, outputJSONPath)
+		// Like kubectl, accept templates without surrounding braces.
+		if !strings.Contains(tmpl, "{") {
+			tmpl = "{" + tmpl + "}"
+		}
+		j := jsonpath.New("output").AllowMissingKeys(true)
+		if err := j.Parse(tmpl); err != nil {
+			return Output{}, errors.Wrap(err, errParseJSONPath)
+		}
+		return Output{jsonPath: j}, nil
+	case strings.HasPrefix(s, outputGoTemplate):
+		t, err := template.New("output").Parse(strings.TrimPrefix(s, outputGoTemplate))
+		if err != nil {
+			return Output{}, errors.Wrap(err, errParseGoTemplate)
+		}
+		return Output{template: t}, nil
+	default:
+		return Output{}, errors.Errorf(errFmtInvalidOutput, s)
+	}
+}
+
+// Decode parses an output format from a kong flag.
+func (o *Output) Decode(ctx *kong.DecodeContext) error {
+	var value string
+	if err := ctx.Scan.PopValueInto("output format", &value); err != nil {
+		return err
+	}
+	out, err := ParseOutput(value)
+	if err != nil {
+		return err
+	}
+	*o = out
+	return nil
+}
+
+// AfterApply rebinds the ObjectPrinter with the output format, so that
+// commands only need to declare the flag.
+func (o Output) AfterApply(kongCtx *kong.Context, printer ObjectPrinter) error { //nolint:unparam
+	printer.Output = o
+	kongCtx.Bind(printer)
+	return nil
+}
+
+// IsSet indicates whether an output format was supplied.
+func (o Output) IsSet() bool {
+	return len(o.columns) > 0 || o.jsonPath != nil || o.template != nil
+}
+
+func (o Output) print(p *ObjectPrinter, obj any) error {
+	if len(o.columns) > 0 {
+		return o.columns.print(p.TablePrinter, obj)
+	}
+	return o.execute(os.Stdout, obj)
+}
+
+// execute writes the supplied object rendered by the jsonpath or go-template
+// of the output format.
+func (o Output) execute(w io.Writer, obj any) error {
+	v, err := toJSONValue(obj)
+	if err != nil {
+		return err
+	}
+	if o.jsonPath != nil {
+		return o.jsonPath.Execute(w, v)
+	}
+	return o.template.Execute(w, v)
+}
+
+// toJSONValue converts the supplied object into its JSON representation, so
+// that it can be navigated by the field names shown in JSON output.
+func toJSONValue(obj any) (any, error) {
+	// Marshal through a pointer so that types that implement json.Marshaler
+	// with a pointer receiver, such as unstructured objects, are respected.
+	ptr := reflect.New(reflect.TypeOf(obj))
+	ptr.Elem().Set(reflect.ValueOf(obj))
+	b, err := json.Marshal(ptr.Interface())
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalObject)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, errors.Wrap(err, errMarshalObject)
+	}
+	return v, nil
+}
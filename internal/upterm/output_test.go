@@ -0,0 +1,124 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOutput(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      string
+		set    bool
+		err    error
+	}{
+		"CustomColumns": {
+			reason: "Custom columns should be parsed.",
+			s:      "custom-columns=NAME:.name",
+			set:    true,
+		},
+		"JSONPath": {
+			reason: "A jsonpath template should be parsed.",
+			s:      "jsonpath={.id}",
+			set:    true,
+		},
+		"GoTemplate": {
+			reason: "A go-template should be parsed.",
+			s:      "go-template={{.id}}",
+			set:    true,
+		},
+		"Unknown": {
+			reason: "Unknown output formats should be rejected.",
+			s:      "wide",
+			err:    errors.Errorf(errFmtInvalidOutput, "wide"),
+		},
+		"EmptyCustomColumns": {
+			reason: "Custom columns without any columns should be rejected.",
+			s:      "custom-columns=",
+			err:    errors.Errorf(errFmtInvalidOutput, "custom-columns="),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := ParseOutput(tc.s)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseOutput(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.set, o.IsSet()); diff != "" {
+				t.Errorf("\n%s\nParseOutput(...).IsSet(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOutputExecute(t *testing.T) {
+	type item struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	items := []item{{ID: "1", Name: "prod"}, {ID: "2", Name: "dev"}}
+	cases := map[string]struct {
+		reason string
+		s      string
+		obj    any
+		want   string
+	}{
+		"JSONPathObject": {
+			reason: "A jsonpath template should extract a field by its JSON name.",
+			s:      "jsonpath={.id}",
+			obj:    items[0],
+			want:   "1",
+		},
+		"JSONPathWithoutBraces": {
+			reason: "A jsonpath template without braces should be accepted.",
+			s:      "jsonpath=.name",
+			obj:    items[0],
+			want:   "prod",
+		},
+		"JSONPathList": {
+			reason: "A jsonpath template should be able to range over a list.",
+			s:      "jsonpath={[*].name}",
+			obj:    items,
+			want:   "prod dev",
+		},
+		"GoTemplateList": {
+			reason: "A go-template should be able to range over a list.",
+			s:      `go-template={{range .}}{{.id}}={{.name}};{{end}}`,
+			obj:    items,
+			want:   "1=prod;2=dev;",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := ParseOutput(tc.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := &bytes.Buffer{}
+			if err := o.execute(buf, tc.obj); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("\n%s\nexecute(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -28,12 +28,16 @@ import (
 
 // The ObjectPrinter is intended to make it easy to print individual structs
 // and lists of structs for the 'get' and 'list' commands. It can print as
-// a human-readable table, or computer-readable (JSON or YAML)
+// a human-readable table, or computer-readable (JSON or YAML). Machine output
+// is JSON following the versioned schema registered for the printed type.
 type ObjectPrinter struct {
 	Quiet  config.QuietFlag
 	Pretty bool
 	Format config.Format
 
+	// Output, if set, replaces the output format.
+	Output Output
+
 	TablePrinter *pterm.TablePrinter
 }
 
@@ -56,7 +60,8 @@ func init() {
 // names for those fields (used for column headers) and a function that can be called
 // on a single struct that returns those fields as strings.
 // When printing JSON or YAML, this will print *all* fields, regardless of
-// the list of fields.
+// the list of fields. Machine output prints the fields of the registered
+// schema.
 func (p *ObjectPrinter) Print(obj any, fieldNames []string, extractFields func(any) []string) error {
 	// Step 1: If user specified quiet, skip printing entirely
 	if p.Quiet {
@@ -66,15 +71,21 @@ func (p *ObjectPrinter) Print(obj any, fieldNames []string, extractFields func(a
 	// Step 2: Enable color printing if desired. Note: This is only
 	// implemented for the default table printing, not JSON or YAML.
 	if p.Pretty {
-		pterm.EnableStyling()
+		EnableStyling()
 	}
 
-	// Step 3: Print the object with the appropriate formatting.
+	// Step 3: Print the object with the appropriate formatting. An output
+	// format takes precedence as it is requested for a specific command.
+	if p.Output.IsSet() {
+		return p.Output.print(p, obj)
+	}
 	switch p.Format { //nolint:exhaustive
 	case config.JSON:
 		return printJSON(obj)
 	case config.YAML:
 		return printYAML(obj)
+	case config.Machine:
+		return printMachine(obj)
 	default:
 		return p.printDefault(obj, fieldNames, extractFields)
 	}
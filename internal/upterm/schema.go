@@ -0,0 +1,187 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// SchemaVersionField is the field of machine output that holds the
+	// version of its schema.
+	SchemaVersionField = "schemaVersion"
+	// KindField is the field of machine output that holds the kind of the
+	// printed object.
+	KindField = "kind"
+
+	errFmtNoSchema        = "machine output is not supported for %s"
+	errFmtSchemaNotObject = "machine output of %s is not a JSON object"
+)
+
+// A Schema describes the machine output of a type. The output of a schema
+// version must only change in backwards compatible ways, i.e. by adding
+// fields. Anything else requires a new version.
+type Schema struct {
+	// Kind of the printed object, e.g. ControlPlane. Lists are printed with
+	// the kind <Kind>List.
+	Kind string
+
+	// Version of the schema, e.g. v1.
+	Version string
+
+	// Convert converts an object of the registered type to a value whose
+	// JSON representation follows the schema.
+	Convert func(obj any) any
+}
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[reflect.Type]Schema{}
+)
+
+// RegisterSchema registers the machine output schema of objects of the same
+// type as the supplied object. Lists of the type are printed using the same
+// schema. It is intended to be called from init functions.
+func RegisterSchema(obj any, s Schema) {
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	schemas[reflect.TypeOf(obj)] = s
+}
+
+// schemaFor returns the schema registered for the supplied type.
+func schemaFor(t reflect.Type) (Schema, bool) {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	s, ok := schemas[t]
+	return s, ok
+}
+
+// HasSchema returns true if a schema is registered for the type of the
+// supplied object, or for the elements of a supplied slice.
+func HasSchema(obj any) bool {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	_, ok := schemaFor(t)
+	return ok
+}
+
+// A PrintingCommand prints objects with an ObjectPrinter. It reports the types
+// it prints, so that tests can check that machine output is supported for each
+// of them.
+type PrintingCommand interface {
+	// PrintedObjects returns an object of each type the command prints.
+	PrintedObjects() []any
+}
+
+// listSchema returns the schema registered for the elements of the supplied
+// slice.
+func listSchema(objs any) (Schema, error) {
+	t := reflect.TypeOf(objs)
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return Schema{}, errors.Errorf(errFmtWatchNotList, objs)
+	}
+	s, ok := schemaFor(t.Elem())
+	if !ok {
+		return Schema{}, errors.Errorf(errFmtNoSchema, t.Elem())
+	}
+	return s, nil
+}
+
+// machineHeader holds the fields that all machine output starts with.
+type machineHeader struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Kind          string `json:"kind"`
+}
+
+// machineList is the machine output of a list of objects.
+type machineList struct {
+	machineHeader
+	Items []any `json:"items"`
+}
+
+// MachineJSON returns the machine output of an object, or of a slice of
+// objects, whose type has a registered schema. Single objects are printed with
+// the schemaVersion and kind fields alongside their own fields, while lists
+// are printed as an object with schemaVersion, kind, and items fields.
+func MachineJSON(obj any) ([]byte, error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return machineObject(obj)
+	}
+	s, err := listSchema(obj)
+	if err != nil {
+		return nil, err
+	}
+	l := machineList{
+		machineHeader: machineHeader{SchemaVersion: s.Version, Kind: s.Kind + "List"},
+		Items:         make([]any, v.Len()),
+	}
+	for i := range l.Items {
+		l.Items[i] = s.Convert(v.Index(i).Interface())
+	}
+	b, err := json.MarshalIndent(l, "", "    ")
+	return b, errors.Wrap(err, errMarshalObject)
+}
+
+// machineObject returns the machine output of a single object.
+func machineObject(obj any) ([]byte, error) {
+	t := reflect.TypeOf(obj)
+	s, ok := schemaFor(t)
+	if !ok {
+		return nil, errors.Errorf(errFmtNoSchema, t)
+	}
+	b, err := json.Marshal(s.Convert(obj))
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalObject)
+	}
+	if len(b) < 2 || b[0] != '{' {
+		return nil, errors.Errorf(errFmtSchemaNotObject, t)
+	}
+	// The schema version and kind precede the fields of the object.
+	h, err := json.Marshal(machineHeader{SchemaVersion: s.Version, Kind: s.Kind})
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalObject)
+	}
+	out := h[:len(h)-1]
+	if len(b) > 2 {
+		out = append(out, ',')
+	}
+	out = append(out, b[1:]...)
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, out, "", "    "); err != nil {
+		return nil, errors.Wrap(err, errMarshalObject)
+	}
+	return buf.Bytes(), nil
+}
+
+func printMachine(obj any) error {
+	b, err := MachineJSON(obj)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(b))
+	return err
+}
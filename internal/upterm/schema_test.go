@@ -0,0 +1,143 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+type schemaObj struct {
+	Name     string
+	Internal string
+	Created  time.Time
+	Count    uint64
+}
+
+type schemaObjV1 struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Count   uint64    `json:"count"`
+}
+
+type unregisteredObj struct{}
+
+func init() {
+	RegisterSchema(schemaObj{}, Schema{
+		Kind:    "Thing",
+		Version: "v1",
+		Convert: func(obj any) any {
+			o := obj.(schemaObj)
+			return schemaObjV1{Name: o.Name, Created: o.Created, Count: o.Count}
+		},
+	})
+}
+
+func TestMachineJSON(t *testing.T) {
+	created := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	type want struct {
+		golden string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		obj    any
+		want   want
+	}{
+		"Object": {
+			reason: "A single object should be printed with its schema version and kind.",
+			obj:    schemaObj{Name: "a", Internal: "hidden", Created: created, Count: 1<<60 + 1},
+			want:   want{golden: "object.golden"},
+		},
+		"List": {
+			reason: "A list should be printed as an object with the schema version, list kind, and items.",
+			obj:    []schemaObj{{Name: "a", Created: created}, {Name: "b", Created: created}},
+			want:   want{golden: "list.golden"},
+		},
+		"EmptyList": {
+			reason: "An empty list should be printed with an empty array of items.",
+			obj:    []schemaObj{},
+			want:   want{golden: "emptylist.golden"},
+		},
+		"NoSchema": {
+			reason: "Printing an object without a registered schema should return an error.",
+			obj:    unregisteredObj{},
+			want:   want{err: errors.Errorf(errFmtNoSchema, "upterm.unregisteredObj")},
+		},
+		"NoListSchema": {
+			reason: "Printing a list of objects without a registered schema should return an error.",
+			obj:    []unregisteredObj{},
+			want:   want{err: errors.Errorf(errFmtNoSchema, "upterm.unregisteredObj")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MachineJSON(tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nMachineJSON(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.golden == "" {
+				return
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", tc.want.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(string(want)), string(got)); diff != "" {
+				t.Errorf("\n%s\nMachineJSON(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHasSchema(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		obj    any
+		want   bool
+	}{
+		"Object": {
+			reason: "An object of a registered type should have a schema.",
+			obj:    schemaObj{},
+			want:   true,
+		},
+		"List": {
+			reason: "A list of objects of a registered type should have a schema.",
+			obj:    []schemaObj{},
+			want:   true,
+		},
+		"Unregistered": {
+			reason: "An object of a type that is not registered should not have a schema.",
+			obj:    unregisteredObj{},
+		},
+		"Nil": {
+			reason: "A nil object should not have a schema.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := HasSchema(tc.obj); got != tc.want {
+				t.Errorf("\n%s\nHasSchema(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -74,3 +74,8 @@ func WrapWithSuccessSpinner(msg string, spinner *pterm.SpinnerPrinter, f func()
 func StepCounter(msg string, index, total int) string {
 	return fmt.Sprintf("[%d/%d]: %s", index, total, msg)
 }
+
+// Warnf prints a formatted warning.
+func Warnf(format string, args ...any) {
+	pterm.Warning.Printfln(format, args...)
+}
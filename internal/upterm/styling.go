@@ -0,0 +1,55 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"os"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+var noColor bool
+
+// SetNoColor disables stylized output for the remainder of the process,
+// regardless of whether it is later requested.
+func SetNoColor(disable bool) {
+	noColor = disable
+	if disable {
+		pterm.DisableStyling()
+	}
+}
+
+// StylingSupported returns true if stylized output, including colors and
+// animated spinners, should be used. Styling is not supported if it has been
+// disabled with SetNoColor, if NO_COLOR is set, if TERM is dumb, or if stdout
+// is not a terminal.
+func StylingSupported() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// EnableStyling enables stylized output if it is supported and disables it
+// otherwise. Commands should call this rather than enabling styling in pterm
+// directly.
+func EnableStyling() {
+	if !StylingSupported() {
+		pterm.DisableStyling()
+		return
+	}
+	pterm.EnableStyling()
+}
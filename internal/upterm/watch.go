@@ -0,0 +1,214 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/upbound/up/internal/config"
+)
+
+// WatchEventType describes how an object changed between two listings.
+type WatchEventType string
+
+// Watch event types.
+const (
+	WatchAdded    WatchEventType = "ADDED"
+	WatchModified WatchEventType = "MODIFIED"
+	WatchDeleted  WatchEventType = "DELETED"
+)
+
+// WatchEvent is a change to an object observed while watching a list.
+type WatchEvent struct {
+	Type   WatchEventType `json:"type" yaml:"type"`
+	Object any            `json:"object" yaml:"object"`
+}
+
+// ListFn lists the objects to print. It must return a slice.
+type ListFn func(ctx context.Context) (any, error)
+
+// watched is the JSON representation of a listed object, along with its
+// serialized form to detect changes.
+type watched struct {
+	key   string
+	value any
+	raw   string
+}
+
+// Watch lists objects every interval until the context is done and prints
+// them whenever they change, similar to kubectl get --watch. With JSON, YAML or
+// machine output a change event is emitted for each added, modified or deleted
+// object, keyed by the supplied function. Otherwise the whole list is printed
+// again.
+func (p *ObjectPrinter) Watch(ctx context.Context, interval time.Duration, list ListFn, key func(any) string, fieldNames []string, extractFields func(any) []string) error {
+	events := !p.Output.IsSet() && (p.Format == config.JSON || p.Format == config.YAML || p.Format == config.Machine)
+	var schema *Schema
+	var prev []watched
+	for i := 0; ; i++ {
+		objs, err := list(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var convert func(any) any
+		if events && p.Format == config.Machine {
+			if schema == nil {
+				s, err := listSchema(objs)
+				if err != nil {
+					return err
+				}
+				schema = &s
+			}
+			convert = schema.Convert
+		}
+		cur, err := snapshot(objs, key, convert)
+		if err != nil {
+			return err
+		}
+		evs := watchEvents(prev, cur)
+		switch {
+		case events:
+			if err := p.printEvents(evs, schema); err != nil {
+				return err
+			}
+		case i == 0 || len(evs) > 0:
+			if i > 0 && !p.Quiet {
+				fmt.Println()
+			}
+			if err := p.Print(objs, fieldNames, extractFields); err != nil {
+				return err
+			}
+		}
+		prev = cur
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// snapshot converts the listed objects to their JSON representation. Objects
+// are converted with the supplied function first, unless it is nil.
+func snapshot(objs any, key func(any) string, convert func(any) any) ([]watched, error) {
+	s := reflect.ValueOf(objs)
+	if s.Kind() != reflect.Slice && s.Kind() != reflect.Array {
+		return nil, errors.Errorf(errFmtWatchNotList, objs)
+	}
+	out := make([]watched, s.Len())
+	for i := range out {
+		obj := s.Index(i).Interface()
+		in := obj
+		if convert != nil {
+			in = convert(obj)
+		}
+		v, err := toJSONValue(in)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalObject)
+		}
+		out[i] = watched{key: key(obj), value: v, raw: string(b)}
+	}
+	return out, nil
+}
+
+// watchEvents returns the events that turn the previous listing into the
+// current one. Added and modified objects are reported in the order they were
+// listed, followed by deleted objects ordered by key.
+func watchEvents(prev, cur []watched) []WatchEvent {
+	before := make(map[string]watched, len(prev))
+	for _, w := range prev {
+		before[w.key] = w
+	}
+	evs := []WatchEvent{}
+	seen := make(map[string]bool, len(cur))
+	for _, w := range cur {
+		seen[w.key] = true
+		b, ok := before[w.key]
+		switch {
+		case !ok:
+			evs = append(evs, WatchEvent{Type: WatchAdded, Object: w.value})
+		case b.raw != w.raw:
+			evs = append(evs, WatchEvent{Type: WatchModified, Object: w.value})
+		}
+	}
+	deleted := []watched{}
+	for _, w := range prev {
+		if !seen[w.key] {
+			deleted = append(deleted, w)
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].key < deleted[j].key })
+	for _, w := range deleted {
+		evs = append(evs, WatchEvent{Type: WatchDeleted, Object: w.value})
+	}
+	return evs
+}
+
+// machineEvent is the machine output of a watch event.
+type machineEvent struct {
+	machineHeader
+	WatchEvent
+}
+
+// printEvents prints one JSON object per line, or one YAML document per
+// event. Machine output events are printed as JSON objects that carry the
+// version of the supplied schema and the kind <Kind>Event.
+func (p *ObjectPrinter) printEvents(evs []WatchEvent, s *Schema) error {
+	if p.Quiet {
+		return nil
+	}
+	for _, e := range evs {
+		if s != nil {
+			b, err := json.Marshal(machineEvent{
+				machineHeader: machineHeader{SchemaVersion: s.Version, Kind: s.Kind + "Event"},
+				WatchEvent:    e,
+			})
+			if err != nil {
+				return errors.Wrap(err, errMarshalObject)
+			}
+			fmt.Println(string(b))
+			continue
+		}
+		if p.Format == config.YAML {
+			b, err := yaml.Marshal(e)
+			if err != nil {
+				return errors.Wrap(err, errMarshalObject)
+			}
+			fmt.Printf("---\n%s", b)
+			continue
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, errMarshalObject)
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
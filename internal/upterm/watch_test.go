@@ -0,0 +1,99 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upterm
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+type watchObj struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func watchKey(obj any) string {
+	return obj.(watchObj).Name
+}
+
+func TestWatchEvents(t *testing.T) {
+	type args struct {
+		prev []watchObj
+		cur  []watchObj
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []WatchEvent
+	}{
+		"Initial": {
+			reason: "All objects of the first listing should be added.",
+			args: args{
+				cur: []watchObj{{Name: "a", Status: "Ready"}, {Name: "b", Status: "Pending"}},
+			},
+			want: []WatchEvent{
+				{Type: WatchAdded, Object: map[string]any{"name": "a", "status": "Ready"}},
+				{Type: WatchAdded, Object: map[string]any{"name": "b", "status": "Pending"}},
+			},
+		},
+		"Unchanged": {
+			reason: "No events should be emitted if nothing changed.",
+			args: args{
+				prev: []watchObj{{Name: "a", Status: "Ready"}},
+				cur:  []watchObj{{Name: "a", Status: "Ready"}},
+			},
+			want: []WatchEvent{},
+		},
+		"Changes": {
+			reason: "Modified, added, and deleted objects should be reported, with deletions last.",
+			args: args{
+				prev: []watchObj{{Name: "c", Status: "Ready"}, {Name: "a", Status: "Pending"}, {Name: "b", Status: "Ready"}},
+				cur:  []watchObj{{Name: "a", Status: "Ready"}, {Name: "d", Status: "Pending"}},
+			},
+			want: []WatchEvent{
+				{Type: WatchModified, Object: map[string]any{"name": "a", "status": "Ready"}},
+				{Type: WatchAdded, Object: map[string]any{"name": "d", "status": "Pending"}},
+				{Type: WatchDeleted, Object: map[string]any{"name": "b", "status": "Ready"}},
+				{Type: WatchDeleted, Object: map[string]any{"name": "c", "status": "Ready"}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prev, err := snapshot(tc.args.prev, watchKey, nil)
+			if err != nil {
+				t.Fatalf("snapshot(...): %v", err)
+			}
+			cur, err := snapshot(tc.args.cur, watchKey, nil)
+			if err != nil {
+				t.Fatalf("snapshot(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, watchEvents(prev, cur)); diff != "" {
+				t.Errorf("\n%s\nwatchEvents(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSnapshotNotList(t *testing.T) {
+	_, err := snapshot(watchObj{Name: "a"}, watchKey, nil)
+	want := errors.Errorf(errFmtWatchNotList, watchObj{Name: "a"})
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nsnapshot(...): -want, +got:\n%s", diff)
+	}
+}
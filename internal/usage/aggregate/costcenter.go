@@ -0,0 +1,99 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+// CostCenterUsage is the usage of the control planes charged to a cost center.
+type CostCenterUsage struct {
+	// CostCenter is the name of the cost center. It is empty for the usage
+	// of control planes that are not tagged with a cost center.
+	CostCenter string `json:"cost_center"`
+	// ControlPlanes are the IDs of the control planes with usage, sorted.
+	ControlPlanes []string `json:"control_planes"`
+	// Events is the number of usage events.
+	Events int `json:"events"`
+	// ResourceHours is the number of resource hours used.
+	ResourceHours float64 `json:"resource_hours"`
+}
+
+// ResourceHoursPerCostCenter rolls up the maximum resource counts of MCPs into
+// resource hours per cost center.
+type ResourceHoursPerCostCenter struct {
+	// CostCenters maps MCP IDs to the cost center their usage is charged
+	// to. The usage of MCPs without a cost center is rolled up separately.
+	CostCenters map[string]string
+
+	usage map[string]*CostCenterUsage
+	mcps  map[string]map[string]bool
+}
+
+// Add adds an aggregated usage event to the roll-up. An event records the
+// maximum number of resources of a kind in an MCP across a window of time,
+// which is counted as that many resources running for the whole window.
+func (ag *ResourceHoursPerCostCenter) Add(e model.MCPGVKEvent) error {
+	if e.Name != mrCountMaxUpboundEventName {
+		return fmt.Errorf("expected event name %s, got %s", mrCountMaxUpboundEventName, e.Name)
+	}
+	if e.Tags.MCPID == "" {
+		return errors.New("MCPID tag is empty")
+	}
+
+	if ag.usage == nil {
+		ag.usage = make(map[string]*CostCenterUsage)
+		ag.mcps = make(map[string]map[string]bool)
+	}
+	cc := ag.CostCenters[e.Tags.MCPID]
+	u, ok := ag.usage[cc]
+	if !ok {
+		u = &CostCenterUsage{CostCenter: cc}
+		ag.usage[cc] = u
+		ag.mcps[cc] = make(map[string]bool)
+	}
+	u.Events++
+	u.ResourceHours += e.Value * e.TimestampEnd.Sub(e.Timestamp).Hours()
+	ag.mcps[cc][e.Tags.MCPID] = true
+	return nil
+}
+
+// Usage returns the usage of each cost center, sorted by name. The usage of
+// MCPs without a cost center is last.
+func (ag *ResourceHoursPerCostCenter) Usage() []CostCenterUsage {
+	usage := make([]CostCenterUsage, 0, len(ag.usage))
+	for cc, u := range ag.usage {
+		mcps := make([]string, 0, len(ag.mcps[cc]))
+		for id := range ag.mcps[cc] {
+			mcps = append(mcps, id)
+		}
+		sort.Strings(mcps)
+		u.ControlPlanes = mcps
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		a, b := usage[i].CostCenter, usage[j].CostCenter
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return a < b
+	})
+	return usage
+}
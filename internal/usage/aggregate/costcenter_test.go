@@ -0,0 +1,110 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestResourceHoursPerCostCenter(t *testing.T) {
+	start := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	event := func(mcp string, value float64, hours int) model.MCPGVKEvent {
+		return model.MCPGVKEvent{
+			Name:         "max_resource_count_per_gvk_per_mcp",
+			Tags:         model.MCPGVKEventTags{MCPID: mcp, Group: "example.com", Version: "v1", Kind: "Thing"},
+			Timestamp:    start,
+			TimestampEnd: start.Add(time.Duration(hours) * time.Hour),
+			Value:        value,
+		}
+	}
+	costCenters := map[string]string{
+		"mcp-a": "platform",
+		"mcp-b": "platform",
+		"mcp-c": "data",
+	}
+	type want struct {
+		usage []CostCenterUsage
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		events []model.MCPGVKEvent
+		want   want
+	}{
+		"Empty": {
+			reason: "No usage should be returned if no events were added.",
+			want: want{
+				usage: []CostCenterUsage{},
+			},
+		},
+		"UnexpectedName": {
+			reason: "Adding an event that is not an aggregated resource count should return an error.",
+			events: []model.MCPGVKEvent{{Name: "kube_managedresource_uid", Tags: model.MCPGVKEventTags{MCPID: "mcp-a"}}},
+			want: want{
+				usage: []CostCenterUsage{},
+				err:   errors.New("expected event name max_resource_count_per_gvk_per_mcp, got kube_managedresource_uid"),
+			},
+		},
+		"EmptyMCPID": {
+			reason: "Adding an event with an empty MCPID should return an error.",
+			events: []model.MCPGVKEvent{event("", 1, 1)},
+			want: want{
+				usage: []CostCenterUsage{},
+				err:   errors.New("MCPID tag is empty"),
+			},
+		},
+		"RollUp": {
+			reason: "Usage should be rolled up by cost center, with untagged MCPs last.",
+			events: []model.MCPGVKEvent{
+				event("mcp-b", 3, 1),
+				event("mcp-a", 2, 2),
+				event("mcp-d", 5, 1),
+				event("mcp-c", 1, 1),
+				event("mcp-a", 1, 1),
+			},
+			want: want{
+				usage: []CostCenterUsage{
+					{CostCenter: "data", ControlPlanes: []string{"mcp-c"}, Events: 1, ResourceHours: 1},
+					{CostCenter: "platform", ControlPlanes: []string{"mcp-a", "mcp-b"}, Events: 3, ResourceHours: 8},
+					{CostCenter: "", ControlPlanes: []string{"mcp-d"}, Events: 1, ResourceHours: 5},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ag := &ResourceHoursPerCostCenter{CostCenters: costCenters}
+			var err error
+			for _, e := range tc.events {
+				if err = ag.Add(e); err != nil {
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAdd(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.usage, ag.Usage()); diff != "" {
+				t.Errorf("\n%s\nUsage(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientutil
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultAdaptiveWindow is the adaptive window used when none is configured.
+var DefaultAdaptiveWindow = AdaptiveWindow{
+	Min:           time.Hour,
+	Max:           24 * time.Hour,
+	TargetObjects: 1000,
+}
+
+// AdaptiveWindow configures a usage query iterator to resize its window
+// according to the number of objects observed in each window. Windows holding
+// more than TargetObjects objects are narrowed to bound the amount of data
+// read at once, and windows holding fewer than half as many are widened to
+// avoid issuing many tiny queries. Usage aggregated across a window wider than
+// 1h holds the maximum of the whole window rather than of each hour, which is
+// coarser than the hourly granularity of billing.
+type AdaptiveWindow struct {
+	// Min is the narrowest window. Must be 1h or greater.
+	Min time.Duration
+	// Max is the widest window. Must not be less than Min.
+	Max time.Duration
+	// TargetObjects is the number of objects each window should hold.
+	TargetObjects int
+}
+
+// Validate returns an error if the adaptive window is invalid.
+func (a AdaptiveWindow) Validate() error {
+	if a.Min < time.Hour {
+		return fmt.Errorf("minimum window must be 1h or greater")
+	}
+	if a.Max < a.Min {
+		return fmt.Errorf("maximum window must not be less than minimum window")
+	}
+	if a.TargetObjects < 1 {
+		return fmt.Errorf("target objects must be 1 or greater")
+	}
+	return nil
+}
+
+// Resize returns the window to use next after observing objects in a window
+// of the given size. The window is halved when it held too many objects and
+// doubled when it held too few, and is always a whole number of hours between
+// Min and Max.
+func (a AdaptiveWindow) Resize(window time.Duration, objects int) time.Duration {
+	switch {
+	case objects > a.TargetObjects:
+		window /= 2
+	case objects < a.TargetObjects/2:
+		window *= 2
+	}
+	window = window.Truncate(time.Hour)
+	if window < a.Min {
+		window = a.Min
+	}
+	if window > a.Max {
+		window = a.Max
+	}
+	return window
+}
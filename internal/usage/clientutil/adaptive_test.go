@@ -0,0 +1,105 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAdaptiveWindowResize(t *testing.T) {
+	a := AdaptiveWindow{
+		Min:           time.Hour,
+		Max:           8 * time.Hour,
+		TargetObjects: 100,
+	}
+	type args struct {
+		window  time.Duration
+		objects int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   time.Duration
+	}{
+		"TooManyObjects": {
+			reason: "A window holding more than the target number of objects should be halved.",
+			args:   args{window: 4 * time.Hour, objects: 101},
+			want:   2 * time.Hour,
+		},
+		"TooFewObjects": {
+			reason: "A window holding less than half the target number of objects should be doubled.",
+			args:   args{window: 2 * time.Hour, objects: 49},
+			want:   4 * time.Hour,
+		},
+		"TargetObjects": {
+			reason: "A window holding about the target number of objects should be kept.",
+			args:   args{window: 2 * time.Hour, objects: 75},
+			want:   2 * time.Hour,
+		},
+		"TruncatedToHour": {
+			reason: "A halved window should be truncated to the hour.",
+			args:   args{window: 3 * time.Hour, objects: 200},
+			want:   time.Hour,
+		},
+		"Min": {
+			reason: "A window should not be narrowed below the minimum.",
+			args:   args{window: time.Hour, objects: 1000},
+			want:   time.Hour,
+		},
+		"Max": {
+			reason: "A window should not be widened beyond the maximum.",
+			args:   args{window: 8 * time.Hour, objects: 0},
+			want:   8 * time.Hour,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := a.Resize(tc.args.window, tc.args.objects)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nResize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAdaptiveWindowValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      AdaptiveWindow
+	}{
+		"MinTooSmall": {
+			reason: "A minimum window less than 1h should return an error.",
+			a:      AdaptiveWindow{Min: time.Minute, Max: time.Hour, TargetObjects: 1},
+		},
+		"MaxLessThanMin": {
+			reason: "A maximum window less than the minimum should return an error.",
+			a:      AdaptiveWindow{Min: 2 * time.Hour, Max: time.Hour, TargetObjects: 1},
+		},
+		"NoTarget": {
+			reason: "A target of zero objects should return an error.",
+			a:      AdaptiveWindow{Min: time.Hour, Max: time.Hour},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.a.Validate(); err == nil {
+				t.Errorf("\n%s\nValidate(): expected error", tc.reason)
+			}
+		})
+	}
+}
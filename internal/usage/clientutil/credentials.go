@@ -0,0 +1,34 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientutil
+
+// Credentials configure how a storage client authenticates. If no file is
+// supplied, ambient credentials are used: credentials from the environment,
+// GKE workload identity, EKS IAM roles for service accounts, or instance
+// metadata, as supported by each provider's SDK.
+type Credentials struct {
+	// File is the path of an explicit credentials file. For GCP this is a
+	// service account key file, and for AWS a shared credentials file.
+	// Optional.
+	File string
+	// Profile is the profile to use from an AWS shared credentials file.
+	// Optional, and only supported for AWS.
+	Profile string
+}
+
+// Ambient returns true if ambient credentials should be used.
+func (c Credentials) Ambient() bool {
+	return c.File == "" && c.Profile == ""
+}
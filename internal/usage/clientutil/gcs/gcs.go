@@ -15,12 +15,63 @@
 package gcs
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
 	"time"
 
 	"cloud.google.com/go/storage"
+
+	"github.com/upbound/up/internal/usage/clientutil"
 )
 
+// encryptionKeySize is the size of a customer-supplied AES-256 key.
+const encryptionKeySize = 32
+
+// BucketOptions configure how usage data is read from a bucket.
+type BucketOptions struct {
+	// BillingProject is the project billed for requests to a requester-pays
+	// bucket. Optional.
+	BillingProject string
+	// EncryptionKey is the customer-supplied AES-256 key with which usage
+	// objects are encrypted. Optional. Objects protected by customer-managed
+	// Cloud KMS keys are decrypted by GCS and do not require a key.
+	EncryptionKey []byte
+}
+
+// Bucket returns a handle for the named bucket, configured to bill requests
+// to the billing project if one is supplied.
+func Bucket(c *storage.Client, name string, o BucketOptions) *storage.BucketHandle {
+	bkt := c.Bucket(name)
+	if o.BillingProject != "" {
+		bkt = bkt.UserProject(o.BillingProject)
+	}
+	return bkt
+}
+
+// Object returns a handle for the named object in bkt, configured to decrypt
+// the object with the encryption key if one is supplied.
+func Object(bkt *storage.BucketHandle, name string, o BucketOptions) *storage.ObjectHandle {
+	obj := bkt.Object(name)
+	if len(o.EncryptionKey) > 0 {
+		obj = obj.Key(o.EncryptionKey)
+	}
+	return obj
+}
+
+// ParseEncryptionKey parses a customer-supplied AES-256 key. The key may be
+// either the raw 32 byte key or its base64 encoding, as accepted by gsutil.
+func ParseEncryptionKey(b []byte) ([]byte, error) {
+	if len(b) == encryptionKeySize {
+		return b, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("encryption key must be a %d byte AES-256 key or its base64 encoding", encryptionKeySize)
+	}
+	return key, nil
+}
+
 // UsageQuery() returns a query for usage data for an Upbound account across a
 // range of time. startTime is inclusive and endTime is exclusive to the hour.
 func UsageQuery(account string, startTime, endTime time.Time) (*storage.Query, error) {
@@ -49,12 +100,19 @@ func usageQuery(account string, startTime, endTime time.Time) *storage.Query {
 
 // UsageQueryIterator iterates through queries for usage data for an Upbound
 // account across a range of time. Each query covers a window of time within the
-// time range. Must be initialized with NewUsageQueryIterator().
+// time range. Must be initialized with NewUsageQueryIterator() or
+// NewAdaptiveUsageQueryIterator().
 type UsageQueryIterator struct {
-	Account string
-	Cursor  time.Time
-	EndTime time.Time
-	Window  time.Duration
+	Account   string
+	StartTime time.Time
+	Cursor    time.Time
+	EndTime   time.Time
+	Window    time.Duration
+	// Completed is the number of windows returned by Next().
+	Completed int
+	// Adaptive resizes Window according to the number of objects passed to
+	// Observe(). The window is fixed if Adaptive is nil.
+	Adaptive *clientutil.AdaptiveWindow
 }
 
 // NewUsageQueryIterator() returns an initialized *UsageQueryIterator.
@@ -71,13 +129,31 @@ func NewUsageQueryIterator(account string, startTime, endTime time.Time, window
 	endTime = endTime.Truncate(time.Hour)
 	window = window.Truncate(time.Hour)
 	return &UsageQueryIterator{
-		Account: account,
-		Cursor:  startTime,
-		EndTime: endTime,
-		Window:  window,
+		Account:   account,
+		StartTime: startTime,
+		Cursor:    startTime,
+		EndTime:   endTime,
+		Window:    window,
 	}, nil
 }
 
+// NewAdaptiveUsageQueryIterator() returns an initialized *UsageQueryIterator
+// whose window starts at a.Min and is resized by Observe(). startTime and
+// endTime are truncated to the hour.
+func NewAdaptiveUsageQueryIterator(account string, startTime, endTime time.Time, a clientutil.AdaptiveWindow) (*UsageQueryIterator, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	iter, err := NewUsageQueryIterator(account, startTime, endTime, a.Min)
+	if err != nil {
+		return nil, err
+	}
+	a.Min = a.Min.Truncate(time.Hour)
+	a.Max = a.Max.Truncate(time.Hour)
+	iter.Adaptive = &a
+	return iter, nil
+}
+
 // More() returns true if Next() has more queries to return.
 func (i *UsageQueryIterator) More() bool {
 	return i.Cursor.Before(i.EndTime)
@@ -90,6 +166,7 @@ func (i *UsageQueryIterator) Next() (*storage.Query, time.Time, time.Time, error
 		return nil, time.Time{}, time.Time{}, fmt.Errorf("iterator is done")
 	}
 	start := i.Cursor
+	i.Completed++
 	i.Cursor = i.Cursor.Add(i.Window)
 	if i.Cursor.After(i.EndTime) {
 		i.Cursor = i.EndTime
@@ -97,6 +174,32 @@ func (i *UsageQueryIterator) Next() (*storage.Query, time.Time, time.Time, error
 	return usageQuery(i.Account, start, i.Cursor), start, i.Cursor, nil
 }
 
+// Observe() records the number of objects read for the window last returned by
+// Next(). If the iterator is adaptive, the next window is resized accordingly.
+func (i *UsageQueryIterator) Observe(objects int) {
+	if i.Adaptive == nil {
+		return
+	}
+	i.Window = i.Adaptive.Resize(i.Window, objects)
+}
+
+// Progress() returns the number of windows returned by Next() and the total
+// number of windows in the time range. The total is an estimate based on the
+// current window if the iterator is adaptive.
+func (i *UsageQueryIterator) Progress() (completed, total int) {
+	return i.Completed, i.Completed + windows(i.Cursor, i.EndTime, i.Window)
+}
+
+// windows returns the number of windows needed to cover the time from start
+// to end. The last window may be partial.
+func windows(start, end time.Time, window time.Duration) int {
+	if window <= 0 || !end.After(start) {
+		return 0
+	}
+	d := end.Sub(start)
+	return int((d + window - 1) / window)
+}
+
 // formatDateUTC returns t in UTC as a string with the format YYYY-MM-DD.
 func formatDateUTC(t time.Time) string {
 	return t.UTC().Format(time.DateOnly)
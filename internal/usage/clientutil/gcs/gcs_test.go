@@ -23,6 +23,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/upbound/up/internal/usage/clientutil"
 )
 
 func TestUsageQuery(t *testing.T) {
@@ -160,10 +162,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    time.Hour,
 				},
 			},
 		},
@@ -177,10 +180,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  24 * time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    24 * time.Hour,
 				},
 			},
 		},
@@ -194,10 +198,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  30 * 24 * time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    30 * 24 * time.Hour,
 				},
 			},
 		},
@@ -211,10 +216,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    time.Hour,
 				},
 			},
 		},
@@ -389,3 +395,90 @@ func TestUsageQueryIterator(t *testing.T) {
 		})
 	}
 }
+
+func TestAdaptiveUsageQueryIterator(t *testing.T) {
+	start := time.Date(2006, 5, 4, 0, 0, 0, 0, time.UTC)
+	iter, err := NewAdaptiveUsageQueryIterator("test-account", start, start.Add(24*time.Hour), clientutil.AdaptiveWindow{
+		Min:           time.Hour,
+		Max:           8 * time.Hour,
+		TargetObjects: 100,
+	})
+	if err != nil {
+		t.Fatalf("NewAdaptiveUsageQueryIterator(...): %s", err)
+	}
+
+	// Windows hold few objects until 15:00, and many afterwards.
+	type window struct {
+		Start time.Time
+		End   time.Time
+	}
+	got := []window{}
+	for iter.More() {
+		_, s, e, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Next(): %s", err)
+		}
+		got = append(got, window{Start: s, End: e})
+		objects := 0
+		if e.After(start.Add(15 * time.Hour)) {
+			objects = 1000
+		}
+		iter.Observe(objects)
+	}
+	hours := func(s, e int) window {
+		return window{Start: start.Add(time.Duration(s) * time.Hour), End: start.Add(time.Duration(e) * time.Hour)}
+	}
+	want := []window{
+		hours(0, 1),
+		hours(1, 3),
+		hours(3, 7),
+		hours(7, 15),
+		hours(15, 23),
+		hours(23, 24),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Next(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	type want struct {
+		key []byte
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		b      []byte
+		want   want
+	}{
+		"Raw": {
+			reason: "A raw 32 byte key should be returned unchanged.",
+			b:      key,
+			want:   want{key: key},
+		},
+		"Base64": {
+			reason: "A base64 encoded key should be decoded.",
+			b:      []byte("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=\n"),
+			want:   want{key: key},
+		},
+		"TooShort": {
+			reason: "A key that is not 32 bytes should be rejected.",
+			b:      []byte("MDEyMzQ1Njc4OWFiY2RlZg=="),
+			want: want{
+				err: errors.New("encryption key must be a 32 byte AES-256 key or its base64 encoding"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := ParseEncryptionKey(tc.b)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseEncryptionKey(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, key); diff != "" {
+				t.Errorf("\n%s\nParseEncryptionKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientutil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	uphttp "github.com/upbound/up/internal/http"
+)
+
+const (
+	errGoogleTransport = "error creating Google API transport"
+)
+
+// GoogleHTTPClient returns an HTTP client authorized for the supplied scopes
+// that sends requests through the shared up transport. Pass it to a Google API
+// client with option.WithHTTPClient, which ignores other credential options.
+func GoogleHTTPClient(ctx context.Context, scopes []string, opts ...option.ClientOption) (*http.Client, error) {
+	t, err := htransport.NewTransport(ctx, uphttp.NewTransport(), append(opts, option.WithScopes(scopes...))...)
+	if err != nil {
+		return nil, errors.Wrap(err, errGoogleTransport)
+	}
+	return &http.Client{Transport: t}, nil
+}
@@ -16,9 +16,29 @@ package clientutil
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// AccountPrefix is the prefix shared by the names of every account's usage
+// data in storage. Names have the form account=<account>/date=.../hour=.../.
+const AccountPrefix = "account="
+
+// AccountFromPrefix returns the account whose usage data is stored under
+// prefix, which must have the form account=<account>/. Returns false if prefix
+// does not name an account.
+func AccountFromPrefix(prefix string) (string, bool) {
+	account, ok := strings.CutPrefix(prefix, AccountPrefix)
+	if !ok {
+		return "", false
+	}
+	account = strings.TrimSuffix(account, "/")
+	if account == "" || strings.Contains(account, "/") {
+		return "", false
+	}
+	return account, true
+}
+
 func usageQueryValues(account string, startTime, endTime time.Time) (startPrefix, endPrefix string) {
 	return fmt.Sprintf(
 			"account=%s/date=%s/hour=%02d/",
@@ -38,10 +58,13 @@ func usageQueryValues(account string, startTime, endTime time.Time) (startPrefix
 // account across a range of time. Each query covers a window of time within the
 // time range. Must be initialized with NewUsageQueryIterator().
 type UsageQueryIterator struct {
-	Account string
-	Cursor  time.Time
-	EndTime time.Time
-	Window  time.Duration
+	Account   string
+	StartTime time.Time
+	Cursor    time.Time
+	EndTime   time.Time
+	Window    time.Duration
+	// Completed is the number of windows returned by Next().
+	Completed int
 }
 
 // NewUsageQueryIterator() returns an initialized *UsageQueryIterator.
@@ -58,10 +81,11 @@ func NewUsageQueryIterator(account string, startTime, endTime time.Time, window
 	endTime = endTime.Truncate(time.Hour)
 	window = window.Truncate(time.Hour)
 	return &UsageQueryIterator{
-		Account: account,
-		Cursor:  startTime,
-		EndTime: endTime,
-		Window:  window,
+		Account:   account,
+		StartTime: startTime,
+		Cursor:    startTime,
+		EndTime:   endTime,
+		Window:    window,
 	}, nil
 }
 
@@ -77,6 +101,7 @@ func (i *UsageQueryIterator) Next() (string, string, time.Time, time.Time, error
 		return "", "", time.Time{}, time.Time{}, fmt.Errorf("iterator is done")
 	}
 	start := i.Cursor
+	i.Completed++
 	i.Cursor = i.Cursor.Add(i.Window)
 	if i.Cursor.After(i.EndTime) {
 		i.Cursor = i.EndTime
@@ -85,6 +110,22 @@ func (i *UsageQueryIterator) Next() (string, string, time.Time, time.Time, error
 	return startPrefix, endPrefix, start, i.Cursor, nil
 }
 
+// Progress() returns the number of windows returned by Next() and the total
+// number of windows in the time range.
+func (i *UsageQueryIterator) Progress() (completed, total int) {
+	return i.Completed, i.Completed + windows(i.Cursor, i.EndTime, i.Window)
+}
+
+// windows returns the number of windows needed to cover the time from start
+// to end. The last window may be partial.
+func windows(start, end time.Time, window time.Duration) int {
+	if window <= 0 || !end.After(start) {
+		return 0
+	}
+	d := end.Sub(start)
+	return int((d + window - 1) / window)
+}
+
 // formatDateUTC returns t in UTC as a string with the format YYYY-MM-DD.
 func formatDateUTC(t time.Time) string {
 	return t.UTC().Format(time.DateOnly)
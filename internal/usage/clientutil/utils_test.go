@@ -63,10 +63,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    time.Hour,
 				},
 			},
 		},
@@ -80,10 +81,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  24 * time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    24 * time.Hour,
 				},
 			},
 		},
@@ -97,10 +99,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  30 * 24 * time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    30 * 24 * time.Hour,
 				},
 			},
 		},
@@ -114,10 +117,11 @@ func TestNewUsageQueryIterator(t *testing.T) {
 			},
 			want: want{
 				iter: &UsageQueryIterator{
-					Account: "test-account",
-					Cursor:  time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
-					EndTime: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
-					Window:  time.Hour,
+					Account:   "test-account",
+					StartTime: time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					Cursor:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+					Window:    time.Hour,
 				},
 			},
 		},
@@ -275,3 +279,73 @@ func TestUsageQueryIterator(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageQueryIteratorProgress(t *testing.T) {
+	start := time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC)
+	iter, err := NewUsageQueryIterator("test-account", start, start.Add(5*time.Hour), 2*time.Hour)
+	if err != nil {
+		t.Fatalf("NewUsageQueryIterator(...): %s", err)
+	}
+	type progress struct {
+		Completed int
+		Total     int
+	}
+	got := []progress{}
+	for {
+		c, total := iter.Progress()
+		got = append(got, progress{Completed: c, Total: total})
+		if !iter.More() {
+			break
+		}
+		if _, _, _, _, err := iter.Next(); err != nil {
+			t.Fatalf("Next(): %s", err)
+		}
+	}
+	want := []progress{{0, 3}, {1, 3}, {2, 3}, {3, 3}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Progress(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestAccountFromPrefix(t *testing.T) {
+	type want struct {
+		account string
+		ok      bool
+	}
+	cases := map[string]struct {
+		reason string
+		prefix string
+		want   want
+	}{
+		"Account": {
+			reason: "An account prefix should return the account.",
+			prefix: "account=test-account/",
+			want:   want{account: "test-account", ok: true},
+		},
+		"NoTrailingSlash": {
+			reason: "An account prefix without a trailing slash should return the account.",
+			prefix: "account=test-account",
+			want:   want{account: "test-account", ok: true},
+		},
+		"NotAccount": {
+			reason: "A prefix that does not start with account= should not return an account.",
+			prefix: "other=test-account/",
+		},
+		"Empty": {
+			reason: "An account prefix without a name should not return an account.",
+			prefix: "account=/",
+		},
+		"Nested": {
+			reason: "A prefix below an account should not return an account.",
+			prefix: "account=test-account/date=2006-05-04/",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			account, ok := AccountFromPrefix(tc.prefix)
+			if diff := cmp.Diff(tc.want, want{account: account, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nAccountFromPrefix(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
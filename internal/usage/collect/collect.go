@@ -0,0 +1,268 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collect incrementally collects usage events from a storage backend
+// into a local store.
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/usage"
+	"github.com/upbound/up/internal/usage/model"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+const (
+	// EventsFile is the name of the file in the store directory to which
+	// events are appended, one JSON object per line.
+	EventsFile = "events.jsonl"
+	// CursorFile is the name of the file in the store directory that records
+	// the end of the last collected time range.
+	CursorFile = "cursor.json"
+
+	// sinkBatchSize is the maximum number of events sent to a sink at once.
+	sinkBatchSize = 500
+
+	errReadCursor   = "unable to read collection cursor"
+	errWriteCursor  = "unable to write collection cursor"
+	errWriteEvents  = "unable to write usage events"
+	errFmtCollect   = "unable to collect usage from %s to %s"
+	errFmtAccount   = "store contains usage for account %q, not %q"
+	errEncodeEvents = "unable to encode usage events"
+	errSendEvents   = "unable to send usage events to sink"
+	errFmtPartition = "unable to list usage partitions from %s to %s"
+)
+
+// A PartitionFunc counts the usage objects stored for each hour of a time
+// range.
+type PartitionFunc func(ctx context.Context, tr usage.TimeRange) ([]report.Partition, error)
+
+// A ReportFunc reads usage events for a time range from a storage backend and
+// writes them to w, aggregated per hour.
+type ReportFunc func(ctx context.Context, tr usage.TimeRange, w report.MCPGVKEventWriter) error
+
+// cursor is the persisted state of a store.
+type cursor struct {
+	Account string    `json:"account"`
+	Next    time.Time `json:"next"`
+}
+
+// Store is a local directory of collected usage events.
+type Store struct {
+	fs  afero.Fs
+	dir string
+}
+
+// StoreOption modifies a Store.
+type StoreOption func(*Store)
+
+// WithFS overrides the Store filesystem with the given filesystem.
+func WithFS(fs afero.Fs) StoreOption {
+	return func(s *Store) {
+		s.fs = fs
+	}
+}
+
+// NewStore constructs a new Store in the supplied directory.
+func NewStore(dir string, opts ...StoreOption) *Store {
+	s := &Store{fs: afero.NewOsFs(), dir: filepath.Clean(dir)}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Cursor returns the start of the next time range to collect for the
+// account. It returns false if nothing has been collected.
+func (s *Store) Cursor(account string) (time.Time, bool, error) {
+	b, err := afero.ReadFile(s.fs, filepath.Join(s.dir, CursorFile))
+	if os.IsNotExist(err) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, errReadCursor)
+	}
+	c := cursor{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return time.Time{}, false, errors.Wrap(err, errReadCursor)
+	}
+	if c.Account != account {
+		return time.Time{}, false, errors.Errorf(errFmtAccount, c.Account, account)
+	}
+	return c.Next, true, nil
+}
+
+// Append appends events to the store and advances the cursor of the account
+// to next.
+func (s *Store) Append(account string, events []model.MCPGVKEvent, next time.Time) error {
+	if err := s.fs.MkdirAll(s.dir, 0755); err != nil {
+		return errors.Wrap(err, errWriteEvents)
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return errors.Wrap(err, errEncodeEvents)
+		}
+	}
+	f, err := s.fs.OpenFile(filepath.Join(s.dir, EventsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, errWriteEvents)
+	}
+	defer f.Close() // nolint:errcheck
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, errWriteEvents)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errWriteEvents)
+	}
+
+	b, err := json.Marshal(cursor{Account: account, Next: next})
+	if err != nil {
+		return errors.Wrap(err, errWriteCursor)
+	}
+	return errors.Wrap(afero.WriteFile(s.fs, filepath.Join(s.dir, CursorFile), b, 0644), errWriteCursor)
+}
+
+// Collector collects complete hours of usage that have not yet been
+// collected into a Store.
+type Collector struct {
+	Account string
+	Store   *Store
+	Report  ReportFunc
+	// Sinks receive collected events before they are appended to the store.
+	// Events are delivered at least once: if sending fails, the time range is
+	// collected and sent to every sink again.
+	Sinks []usage.EventSink
+	// Since is the start of the first time range to collect if nothing has
+	// been collected.
+	Since time.Time
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Result describes a single collection.
+type Result struct {
+	TimeRange usage.TimeRange
+	Events    int
+}
+
+// CollectOnce collects usage from the end of the last collection up to the
+// start of the current hour. It returns false if there are no complete hours
+// to collect. Events are only appended to the store once the whole time range
+// has been read, so a failed collection is retried in full.
+func (c *Collector) CollectOnce(ctx context.Context) (Result, bool, error) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	start, ok, err := c.Store.Cursor(c.Account)
+	if err != nil {
+		return Result{}, false, err
+	}
+	if !ok {
+		start = c.Since
+	}
+	start = start.UTC().Truncate(time.Hour)
+	end := now().UTC().Truncate(time.Hour)
+	if end.Sub(start) < time.Hour {
+		return Result{}, false, nil
+	}
+
+	tr := usage.TimeRange{Start: start, End: end}
+	w := &sliceWriter{}
+	if err := c.Report(ctx, tr, w); err != nil {
+		return Result{}, false, errors.Wrapf(err, errFmtCollect, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	for _, sink := range c.Sinks {
+		bw := usage.NewBatchWriter(ctx, sink, sinkBatchSize)
+		for _, e := range w.events {
+			if err := bw.Write(e); err != nil {
+				return Result{}, false, errors.Wrap(err, errSendEvents)
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			return Result{}, false, errors.Wrap(err, errSendEvents)
+		}
+	}
+	if err := c.Store.Append(c.Account, w.events, end); err != nil {
+		return Result{}, false, err
+	}
+	return Result{TimeRange: tr, Events: len(w.events)}, true, nil
+}
+
+// Run collects usage every interval until the context is done. Each
+// collection that reads new usage is passed to collected. Failed collections
+// are passed to failed and retried at the next interval.
+func (c *Collector) Run(ctx context.Context, interval time.Duration, collected func(Result), failed func(error)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		res, ok, err := c.CollectOnce(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			failed(err)
+		case ok:
+			collected(res)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Gaps returns the time ranges within tr for which no usage objects are
+// stored, such as hours in which usage was not collected because of an outage.
+// Consecutive empty hours are merged into a single time range.
+func Gaps(ctx context.Context, tr usage.TimeRange, partitions PartitionFunc) ([]usage.TimeRange, error) {
+	parts, err := partitions(ctx, tr)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtPartition, tr.Start.Format(time.RFC3339), tr.End.Format(time.RFC3339))
+	}
+	gaps := []usage.TimeRange{}
+	for _, p := range parts {
+		if p.Objects > 0 {
+			continue
+		}
+		if n := len(gaps); n > 0 && gaps[n-1].End.Equal(p.TimeRange.Start) {
+			gaps[n-1].End = p.TimeRange.End
+			continue
+		}
+		gaps = append(gaps, p.TimeRange)
+	}
+	return gaps, nil
+}
+
+// sliceWriter buffers events in memory.
+type sliceWriter struct {
+	events []model.MCPGVKEvent
+}
+
+func (w *sliceWriter) Write(e model.MCPGVKEvent) error {
+	w.events = append(w.events, e)
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/usage"
+	"github.com/upbound/up/internal/usage/model"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+func TestCollectOnce(t *testing.T) {
+	since := time.Date(2023, 5, 4, 3, 0, 0, 0, time.UTC)
+	event := func(start time.Time) model.MCPGVKEvent {
+		return model.MCPGVKEvent{Name: "max_resource_count_per_gvk_per_mcp", Timestamp: start, TimestampEnd: start.Add(time.Hour), Value: 1}
+	}
+	// hourly writes one event per hour of the time range.
+	hourly := func(_ context.Context, tr usage.TimeRange, w report.MCPGVKEventWriter) error {
+		for h := tr.Start; h.Before(tr.End); h = h.Add(time.Hour) {
+			if err := w.Write(event(h)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	errBoom := errors.New("boom")
+
+	type call struct {
+		now    time.Time
+		report ReportFunc
+	}
+	type want struct {
+		res Result
+		ok  bool
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		calls  []call
+		want   []want
+		cursor time.Time
+	}{
+		"FirstCollection": {
+			reason: "All complete hours since the start should be collected.",
+			calls: []call{
+				{now: since.Add(2*time.Hour + 30*time.Minute), report: hourly},
+			},
+			want: []want{
+				{res: Result{TimeRange: usage.TimeRange{Start: since, End: since.Add(2 * time.Hour)}, Events: 2}, ok: true},
+			},
+			cursor: since.Add(2 * time.Hour),
+		},
+		"NoCompleteHour": {
+			reason: "Nothing should be collected until the next hour is complete.",
+			calls: []call{
+				{now: since.Add(time.Hour), report: hourly},
+				{now: since.Add(time.Hour + 59*time.Minute), report: hourly},
+				{now: since.Add(2 * time.Hour), report: hourly},
+			},
+			want: []want{
+				{res: Result{TimeRange: usage.TimeRange{Start: since, End: since.Add(time.Hour)}, Events: 1}, ok: true},
+				{},
+				{res: Result{TimeRange: usage.TimeRange{Start: since.Add(time.Hour), End: since.Add(2 * time.Hour)}, Events: 1}, ok: true},
+			},
+			cursor: since.Add(2 * time.Hour),
+		},
+		"RetryFailed": {
+			reason: "A failed collection should not advance the cursor.",
+			calls: []call{
+				{now: since.Add(time.Hour), report: func(context.Context, usage.TimeRange, report.MCPGVKEventWriter) error { return errBoom }},
+				{now: since.Add(time.Hour), report: hourly},
+			},
+			want: []want{
+				{err: errors.Wrapf(errBoom, errFmtCollect, "2023-05-04T03:00:00Z", "2023-05-04T04:00:00Z")},
+				{res: Result{TimeRange: usage.TimeRange{Start: since, End: since.Add(time.Hour)}, Events: 1}, ok: true},
+			},
+			cursor: since.Add(time.Hour),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewStore("/usage", WithFS(afero.NewMemMapFs()))
+			for i, call := range tc.calls {
+				now := call.now
+				c := &Collector{Account: "acct", Store: s, Report: call.report, Since: since, Now: func() time.Time { return now }}
+				res, ok, err := c.CollectOnce(context.Background())
+				if diff := cmp.Diff(tc.want[i].err, err, test.EquateErrors()); diff != "" {
+					t.Errorf("\n%s\nCollectOnce(...) call %d: -want err, +got err:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(tc.want[i].ok, ok); diff != "" {
+					t.Errorf("\n%s\nCollectOnce(...) call %d: -want ok, +got ok:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(tc.want[i].res, res); diff != "" {
+					t.Errorf("\n%s\nCollectOnce(...) call %d: -want, +got:\n%s", tc.reason, i, diff)
+				}
+			}
+			cursor, _, err := s.Cursor("acct")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.cursor, cursor); diff != "" {
+				t.Errorf("\n%s\nCursor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCursorAccountMismatch(t *testing.T) {
+	s := NewStore("/usage", WithFS(afero.NewMemMapFs()))
+	if err := s.Append("acct", nil, time.Date(2023, 5, 4, 3, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := s.Cursor("other")
+	if diff := cmp.Diff(errors.Errorf(errFmtAccount, "acct", "other"), err, test.EquateErrors()); diff != "" {
+		t.Errorf("Cursor(...): -want err, +got err:\n%s", diff)
+	}
+}
+
+func TestGaps(t *testing.T) {
+	start := time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC)
+	hour := func(h int) usage.TimeRange {
+		return usage.TimeRange{Start: start.Add(time.Duration(h) * time.Hour), End: start.Add(time.Duration(h+1) * time.Hour)}
+	}
+	// partitions stores objects in every hour but the empty ones.
+	partitions := func(empty ...int) PartitionFunc {
+		return func(_ context.Context, tr usage.TimeRange) ([]report.Partition, error) {
+			parts := []report.Partition{}
+			for h := 0; start.Add(time.Duration(h) * time.Hour).Before(tr.End); h++ {
+				p := report.Partition{TimeRange: hour(h), Objects: 2}
+				for _, e := range empty {
+					if e == h {
+						p.Objects = 0
+					}
+				}
+				parts = append(parts, p)
+			}
+			return parts, nil
+		}
+	}
+	errBoom := errors.New("boom")
+	tr := usage.TimeRange{Start: start, End: start.Add(6 * time.Hour)}
+
+	type want struct {
+		gaps []usage.TimeRange
+		err  error
+	}
+	cases := map[string]struct {
+		reason     string
+		partitions PartitionFunc
+		want       want
+	}{
+		"NoGaps": {
+			reason:     "No gaps should be returned if every hour has objects.",
+			partitions: partitions(),
+			want: want{
+				gaps: []usage.TimeRange{},
+			},
+		},
+		"Gaps": {
+			reason:     "Consecutive empty hours should be merged into a single gap.",
+			partitions: partitions(0, 2, 3, 5),
+			want: want{
+				gaps: []usage.TimeRange{
+					hour(0),
+					{Start: hour(2).Start, End: hour(3).End},
+					hour(5),
+				},
+			},
+		},
+		"ErrorPartitions": {
+			reason: "Errors listing partitions should be returned.",
+			partitions: func(context.Context, usage.TimeRange) ([]report.Partition, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtPartition, tr.Start.Format(time.RFC3339), tr.End.Format(time.RFC3339)),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gaps, err := Gaps(context.Background(), tr, tc.partitions)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGaps(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.gaps, gaps); diff != "" {
+				t.Errorf("\n%s\nGaps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -49,10 +49,14 @@ func (d *MCPGVKEventDecoder) More() bool {
 	return d.jd.More()
 }
 
-// Decode returns the next MCP GVK event from input.
+// Decode returns the next MCP GVK event from input. Events with the schema of
+// a previous version are converted to the latest schema.
 func (d *MCPGVKEventDecoder) Decode() (model.MCPGVKEvent, error) {
-	var e model.MCPGVKEvent
-	err := d.jd.Decode(&e)
+	var raw json.RawMessage
+	if err := d.jd.Decode(&raw); err != nil {
+		return model.MCPGVKEvent{}, fmt.Errorf("error decoding next event: %s", err.Error())
+	}
+	e, err := model.DecodeMCPGVKEvent(raw)
 	if err != nil {
 		return model.MCPGVKEvent{}, fmt.Errorf("error decoding next event: %s", err.Error())
 	}
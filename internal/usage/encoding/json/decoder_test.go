@@ -178,6 +178,28 @@ func TestMCPGVKEventDecoderDecode(t *testing.T) {
 				err:   nil,
 			},
 		},
+		"VersionedUsageObject": {
+			reason: "Decoding a usage object that records its schema version should return an MCPGVKEvent with its values.",
+			args: args{
+				reader: strings.NewReader(`[{"schema_version": "v1", "name": "event_name", "value": 2.0}]`),
+			},
+			want: want{
+				event: model.MCPGVKEvent{
+					Name:  "event_name",
+					Value: 2.0,
+				},
+			},
+		},
+		"UnknownSchemaVersion": {
+			reason: "Decoding a usage object with an unknown schema version should return an error.",
+			args: args{
+				reader: strings.NewReader(`[{"schema_version": "v99", "name": "event_name"}]`),
+			},
+			want: want{
+				event: model.MCPGVKEvent{},
+				err:   errors.New(`error decoding next event: unknown schema version "v99"`),
+			},
+		},
 		"UsageObject": {
 			reason: "Decoding from a JSON array containing a usage object should return aMCPGVKEvent with its values.",
 			args: args{
@@ -38,8 +38,18 @@ func NewMCPGVKEventEncoder(w io.Writer) (*MCPGVKEventEncoder, error) {
 	return &MCPGVKEventEncoder{w: w}, nil
 }
 
-// Encode encodes and writes an MCP GVK event.
+// Encode encodes and writes an MCP GVK event, recording the version of its
+// schema.
 func (e *MCPGVKEventEncoder) Encode(event model.MCPGVKEvent) error {
+	eventBytes, err := json.Marshal(event.Versioned())
+	if err != nil {
+		return err
+	}
+	return e.write(eventBytes)
+}
+
+// write writes an encoded event as the next item of the JSON array.
+func (e *MCPGVKEventEncoder) write(eventBytes []byte) error {
 	b := []byte{}
 
 	if e.wroteFirstItem {
@@ -47,14 +57,9 @@ func (e *MCPGVKEventEncoder) Encode(event model.MCPGVKEvent) error {
 		b = append(b, byte(','))
 	}
 	b = append(b, byte('\n'))
-
-	eventBytes, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
 	b = append(b, eventBytes...)
 
-	_, err = e.w.Write(b)
+	_, err := e.w.Write(b)
 	if err == nil {
 		e.wroteFirstItem = true
 	}
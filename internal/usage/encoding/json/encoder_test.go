@@ -105,7 +105,7 @@ func TestMCPGVKEventEncoder(t *testing.T) {
 			},
 			want: want{
 				bytes: []byte(`[
-{"name":"","tags":{"customresource_group":"","customresource_version":"","customresource_kind":"","upbound_account":"","mcp_id":""},"timestamp":"0001-01-01T00:00:00Z","timestamp_end":"0001-01-01T00:00:00Z","value":0}
+{"schema_version":"v1","name":"","tags":{"customresource_group":"","customresource_version":"","customresource_kind":"","upbound_account":"","mcp_id":""},"timestamp":"0001-01-01T00:00:00Z","timestamp_end":"0001-01-01T00:00:00Z","value":0}
 ]
 `),
 			},
@@ -157,9 +157,9 @@ func TestMCPGVKEventEncoder(t *testing.T) {
 			},
 			want: want{
 				bytes: []byte(`[
-{"name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1","customresource_kind":"things","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":5},
-{"name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1","customresource_kind":"foos","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":10},
-{"name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1alpha1","customresource_kind":"bars","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":8}
+{"schema_version":"v1","name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1","customresource_kind":"things","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":5},
+{"schema_version":"v1","name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1","customresource_kind":"foos","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":10},
+{"schema_version":"v1","name":"test_event","tags":{"customresource_group":"example.com","customresource_version":"v1alpha1","customresource_kind":"bars","upbound_account":"test-account","mcp_id":"test-mcpid"},"timestamp":"2006-05-04T03:02:01Z","timestamp_end":"2006-05-04T03:03:01Z","value":8}
 ]
 `),
 			},
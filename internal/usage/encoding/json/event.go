@@ -0,0 +1,163 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+// An EventEncodeFn encodes a usage event of a particular type as a JSON
+// object that includes an event_type field.
+type EventEncodeFn func(model.Event) ([]byte, error)
+
+// An EventDecodeFn decodes a usage event of a particular type from a JSON
+// object.
+type EventDecodeFn func([]byte) (model.Event, error)
+
+// EventEncoders are the encoders of each known type of usage event.
+var EventEncoders = map[model.EventType]EventEncodeFn{
+	model.EventTypeMCPGVK: func(e model.Event) ([]byte, error) {
+		return json.Marshal(struct {
+			EventType model.EventType `json:"event_type"`
+			model.VersionedMCPGVKEvent
+		}{EventType: model.EventTypeMCPGVK, VersionedMCPGVKEvent: e.(model.MCPGVKEvent).Versioned()})
+	},
+	model.EventTypeMCPLifecycle: func(e model.Event) ([]byte, error) {
+		return json.Marshal(struct {
+			EventType model.EventType `json:"event_type"`
+			model.MCPLifecycleEvent
+		}{EventType: model.EventTypeMCPLifecycle, MCPLifecycleEvent: e.(model.MCPLifecycleEvent)})
+	},
+	model.EventTypeMCPAPIRequest: func(e model.Event) ([]byte, error) {
+		return json.Marshal(struct {
+			EventType model.EventType `json:"event_type"`
+			model.MCPAPIRequestEvent
+		}{EventType: model.EventTypeMCPAPIRequest, MCPAPIRequestEvent: e.(model.MCPAPIRequestEvent)})
+	},
+}
+
+// EventDecoders are the decoders of each known type of usage event. Objects
+// without an event_type field are MCP GVK events, which were the only type
+// of usage event before event types were introduced.
+var EventDecoders = map[model.EventType]EventDecodeFn{
+	"":                    decodeMCPGVKEvent,
+	model.EventTypeMCPGVK: decodeMCPGVKEvent,
+	model.EventTypeMCPLifecycle: func(b []byte) (model.Event, error) {
+		var e model.MCPLifecycleEvent
+		err := json.Unmarshal(b, &e)
+		return e, err
+	},
+	model.EventTypeMCPAPIRequest: func(b []byte) (model.Event, error) {
+		var e model.MCPAPIRequestEvent
+		err := json.Unmarshal(b, &e)
+		return e, err
+	},
+}
+
+func decodeMCPGVKEvent(b []byte) (model.Event, error) {
+	return model.DecodeMCPGVKEvent(b)
+}
+
+// EventEncoder encodes usage events of any known type as a JSON array of
+// event objects to a writer. Must be initialized with NewEventEncoder().
+// Callers must call Close() when finished encoding.
+type EventEncoder struct {
+	array    *MCPGVKEventEncoder
+	encoders map[model.EventType]EventEncodeFn
+}
+
+// NewEventEncoder returns an initialized *EventEncoder that encodes events
+// with the supplied encoders, or EventEncoders if none are supplied.
+func NewEventEncoder(w io.Writer, encoders map[model.EventType]EventEncodeFn) (*EventEncoder, error) {
+	e, err := NewMCPGVKEventEncoder(w)
+	if err != nil {
+		return nil, err
+	}
+	if encoders == nil {
+		encoders = EventEncoders
+	}
+	return &EventEncoder{array: e, encoders: encoders}, nil
+}
+
+// Encode encodes and writes a usage event.
+func (e *EventEncoder) Encode(event model.Event) error {
+	enc, ok := e.encoders[event.EventType()]
+	if !ok {
+		return fmt.Errorf("no encoder for event type %q", event.EventType())
+	}
+	b, err := enc(event)
+	if err != nil {
+		return err
+	}
+	return e.array.write(b)
+}
+
+// Close closes the encoder.
+func (e *EventEncoder) Close() error {
+	return e.array.Close()
+}
+
+// EventDecoder decodes usage events of any known type from a reader
+// containing a JSON array of event objects. Must be initialized with
+// NewEventDecoder().
+type EventDecoder struct {
+	jd       *json.Decoder
+	decoders map[model.EventType]EventDecodeFn
+}
+
+// NewEventDecoder returns an initialized *EventDecoder that decodes events
+// with the supplied decoders, or EventDecoders if none are supplied.
+func NewEventDecoder(r io.Reader, decoders map[model.EventType]EventDecodeFn) (*EventDecoder, error) {
+	d, err := NewMCPGVKEventDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	if decoders == nil {
+		decoders = EventDecoders
+	}
+	return &EventDecoder{jd: d.jd, decoders: decoders}, nil
+}
+
+// More returns true if there is more input to be decoded.
+func (d *EventDecoder) More() bool {
+	return d.jd.More()
+}
+
+// Decode returns the next usage event from input.
+func (d *EventDecoder) Decode() (model.Event, error) {
+	var raw json.RawMessage
+	if err := d.jd.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("error decoding next event: %s", err.Error())
+	}
+	t := struct {
+		EventType model.EventType `json:"event_type"`
+	}{}
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("error decoding next event: %s", err.Error())
+	}
+	dec, ok := d.decoders[t.EventType]
+	if !ok {
+		return nil, fmt.Errorf("error decoding next event: unknown event type %q", t.EventType)
+	}
+	e, err := dec(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding next event: %s", err.Error())
+	}
+	return e, nil
+}
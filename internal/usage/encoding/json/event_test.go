@@ -0,0 +1,135 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestEventEncoderRoundTrip(t *testing.T) {
+	ts := time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC)
+	events := []model.Event{
+		model.MCPGVKEvent{
+			Name:      "kube_managedresource_uid",
+			Tags:      model.MCPGVKEventTags{Group: "example.com", Version: "v1", Kind: "Thing", MCPID: "mcp"},
+			Timestamp: ts,
+			Value:     3,
+		},
+		model.MCPLifecycleEvent{
+			Name:      "mcp_lifecycle",
+			Tags:      model.MCPLifecycleEventTags{UpboundAccount: "acme", MCPID: "mcp", Phase: model.MCPLifecycleCreated},
+			Timestamp: ts,
+		},
+		model.MCPAPIRequestEvent{
+			Name:         "mcp_api_requests",
+			Tags:         model.MCPAPIRequestEventTags{UpboundAccount: "acme", MCPID: "mcp", Verb: "GET", Code: "200"},
+			Timestamp:    ts,
+			TimestampEnd: ts.Add(time.Hour),
+			Value:        42,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	enc, err := NewEventEncoder(buf, nil)
+	if err != nil {
+		t.Fatalf("NewEventEncoder(...): %v", err)
+	}
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			t.Fatalf("Encode(...): %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	dec, err := NewEventDecoder(buf, nil)
+	if err != nil {
+		t.Fatalf("NewEventDecoder(...): %v", err)
+	}
+	got := []model.Event{}
+	for dec.More() {
+		e, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode(): %v", err)
+		}
+		got = append(got, e)
+	}
+	if diff := cmp.Diff(events, got); diff != "" {
+		t.Errorf("\nEvents of each type should be decoded as they were encoded.\n-want, +got:\n%s", diff)
+	}
+}
+
+func TestEventDecoderDecode(t *testing.T) {
+	type want struct {
+		event model.Event
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		input  string
+		want   want
+	}{
+		"Untyped": {
+			reason: "Objects without an event type should be decoded as MCP GVK events.",
+			input:  `[{"name": "event_name", "value": 1.0}]`,
+			want: want{
+				event: model.MCPGVKEvent{Name: "event_name", Value: 1.0},
+			},
+		},
+		"UnknownType": {
+			reason: "Objects with an unknown event type should return an error.",
+			input:  `[{"event_type": "mcp_unknown"}]`,
+			want: want{
+				err: errors.New(`error decoding next event: unknown event type "mcp_unknown"`),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewEventDecoder(strings.NewReader(tc.input), nil)
+			if err != nil {
+				t.Fatalf("NewEventDecoder(...): %v", err)
+			}
+			e, err := d.Decode()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDecode(): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.event, e); diff != "" {
+				t.Errorf("\n%s\nDecode(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEventEncoderUnknownType(t *testing.T) {
+	enc, err := NewEventEncoder(&bytes.Buffer{}, map[model.EventType]EventEncodeFn{})
+	if err != nil {
+		t.Fatalf("NewEventEncoder(...): %v", err)
+	}
+	want := errors.New(`no encoder for event type "mcp_gvk"`)
+	if diff := cmp.Diff(want, enc.Encode(model.MCPGVKEvent{}), test.EquateErrors()); diff != "" {
+		t.Errorf("\nEncode(...): -want err, +got err:\n%s", diff)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package estimate estimates the bill for usage recorded in a usage report
+// using an Upbound price list.
+package estimate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/usage/model"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+const (
+	errFetchPriceList  = "unable to download price list"
+	errFmtFetchCode    = "price list download returned status %d"
+	errParsePriceList  = "unable to parse price list"
+	errNoCurrency      = "price list does not specify a currency"
+	errFmtPricePattern = "invalid price %d"
+	errFmtNegative     = "invalid price %d: must not be negative"
+)
+
+// PriceList is an Upbound price list.
+type PriceList struct {
+	// Currency in which prices are given, e.g. USD.
+	Currency string `json:"currency"`
+	// Effective is the date from which the prices apply.
+	Effective string `json:"effective,omitempty"`
+	// Prices of resources. A resource is priced by the first price whose
+	// pattern matches its group, version, and kind.
+	Prices []Price `json:"prices"`
+}
+
+// Price is the price of running a resource for an hour.
+type Price struct {
+	// Resources is a pattern of the form group[/version[/kind]] matching
+	// the resources to which the price applies, e.g. '*.aws.upbound.io'.
+	Resources string `json:"resources"`
+	// ResourceHour is the price of running one resource for an hour.
+	ResourceHour float64 `json:"resource_hour"`
+
+	pattern report.GVKPattern
+}
+
+// Parse parses and validates a price list.
+func Parse(b []byte) (*PriceList, error) {
+	pl := &PriceList{}
+	if err := json.Unmarshal(b, pl); err != nil {
+		return nil, errors.Wrap(err, errParsePriceList)
+	}
+	if pl.Currency == "" {
+		return nil, errors.New(errNoCurrency)
+	}
+	for i := range pl.Prices {
+		p, err := report.ParseGVKPattern(pl.Prices[i].Resources)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtPricePattern, i)
+		}
+		if pl.Prices[i].ResourceHour < 0 {
+			return nil, errors.Errorf(errFmtNegative, i)
+		}
+		pl.Prices[i].pattern = p
+	}
+	return pl, nil
+}
+
+// Fetch downloads and parses the price list at url.
+func Fetch(ctx context.Context, c *http.Client, url string) (*PriceList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchPriceList)
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchPriceList)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtFetchCode, res.StatusCode)
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchPriceList)
+	}
+	return Parse(b)
+}
+
+// price returns the price matching the event's resource, if any.
+func (pl *PriceList) price(e model.MCPGVKEvent) (Price, bool) {
+	for _, p := range pl.Prices {
+		if p.pattern.Matches(e) {
+			return p, true
+		}
+	}
+	return Price{}, false
+}
+
+// Estimate is an estimated bill.
+type Estimate struct {
+	Currency string
+	// Total is the estimated cost of all covered usage.
+	Total float64
+	// ResourceHours is the number of covered resource hours.
+	ResourceHours float64
+	// ControlPlanes is the breakdown of the estimate by control plane,
+	// sorted by ID.
+	ControlPlanes []ControlPlane
+	// Uncovered is the usage of resources for which the price list has no
+	// price, sorted by group, version, and kind. It is not included in the
+	// estimate.
+	Uncovered []Uncovered
+}
+
+// ControlPlane is the estimated cost of the usage of a control plane.
+type ControlPlane struct {
+	ID            string
+	ResourceHours float64
+	Cost          float64
+}
+
+// Uncovered is the usage of a kind of resource that the price list does not
+// cover.
+type Uncovered struct {
+	Group         string
+	Version       string
+	Kind          string
+	Events        int
+	ResourceHours float64
+}
+
+// Estimate applies the price list to usage events. Each event records the
+// maximum number of resources of a kind in a control plane across a window of
+// time, which is billed as that many resources running for the whole window.
+func (pl *PriceList) Estimate(events []model.MCPGVKEvent) *Estimate {
+	est := &Estimate{Currency: pl.Currency, ControlPlanes: []ControlPlane{}, Uncovered: []Uncovered{}}
+	cps := map[string]*ControlPlane{}
+	uncovered := map[model.MCPGVKEventTags]*Uncovered{}
+	for _, e := range events {
+		hours := e.Value * e.TimestampEnd.Sub(e.Timestamp).Hours()
+		p, ok := pl.price(e)
+		if !ok {
+			key := model.MCPGVKEventTags{Group: e.Tags.Group, Version: e.Tags.Version, Kind: e.Tags.Kind}
+			u, ok := uncovered[key]
+			if !ok {
+				u = &Uncovered{Group: e.Tags.Group, Version: e.Tags.Version, Kind: e.Tags.Kind}
+				uncovered[key] = u
+			}
+			u.Events++
+			u.ResourceHours += hours
+			continue
+		}
+		cp, ok := cps[e.Tags.MCPID]
+		if !ok {
+			cp = &ControlPlane{ID: e.Tags.MCPID}
+			cps[e.Tags.MCPID] = cp
+		}
+		cost := hours * p.ResourceHour
+		cp.ResourceHours += hours
+		cp.Cost += cost
+		est.ResourceHours += hours
+		est.Total += cost
+	}
+	for _, cp := range cps {
+		est.ControlPlanes = append(est.ControlPlanes, *cp)
+	}
+	sort.Slice(est.ControlPlanes, func(i, j int) bool {
+		return est.ControlPlanes[i].ID < est.ControlPlanes[j].ID
+	})
+	for _, u := range uncovered {
+		est.Uncovered = append(est.Uncovered, *u)
+	}
+	sort.Slice(est.Uncovered, func(i, j int) bool {
+		a, b := est.Uncovered[i], est.Uncovered[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Kind < b.Kind
+	})
+	return est
+}
+
+// UncoveredEvents returns the number of events not covered by the price list.
+func (e *Estimate) UncoveredEvents() int {
+	n := 0
+	for _, u := range e.Uncovered {
+		n += u.Events
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package estimate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestParse(t *testing.T) {
+	type want struct {
+		pl  *PriceList
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		b      string
+		want   want
+	}{
+		"Valid": {
+			reason: "A valid price list should be parsed.",
+			b:      `{"currency":"USD","prices":[{"resources":"*.aws.upbound.io","resource_hour":0.01}]}`,
+			want: want{
+				pl: &PriceList{
+					Currency: "USD",
+					Prices:   []Price{{Resources: "*.aws.upbound.io", ResourceHour: 0.01}},
+				},
+			},
+		},
+		"NoCurrency": {
+			reason: "A price list without a currency should return an error.",
+			b:      `{"prices":[]}`,
+			want: want{
+				err: errors.New(errNoCurrency),
+			},
+		},
+		"InvalidPattern": {
+			reason: "A price with an invalid resource pattern should return an error.",
+			b:      `{"currency":"USD","prices":[{"resources":"a/b/c/d","resource_hour":1}]}`,
+			want: want{
+				err: errors.Wrapf(errors.Errorf("invalid GVK pattern %q: must be of the form group[/version[/kind]]", "a/b/c/d"), errFmtPricePattern, 0),
+			},
+		},
+		"Negative": {
+			reason: "A negative price should return an error.",
+			b:      `{"currency":"USD","prices":[{"resources":"*","resource_hour":-1}]}`,
+			want: want{
+				err: errors.Errorf(errFmtNegative, 0),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.b))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pl, got, cmpopts.IgnoreUnexported(Price{})); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		body   string
+		want   error
+	}{
+		"Success": {
+			reason: "A price list should be downloaded and parsed.",
+			status: http.StatusOK,
+			body:   `{"currency":"USD","prices":[]}`,
+		},
+		"ErrorStatus": {
+			reason: "A non-200 status should return an error.",
+			status: http.StatusNotFound,
+			want:   errors.Errorf(errFmtFetchCode, http.StatusNotFound),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			_, err := Fetch(context.Background(), srv.Client(), srv.URL)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	start := time.Date(2006, 5, 4, 0, 0, 0, 0, time.UTC)
+	event := func(mcp, group, kind string, value float64, hours int) model.MCPGVKEvent {
+		return model.MCPGVKEvent{
+			Name:         "max_resource_count_per_gvk_per_mcp",
+			Tags:         model.MCPGVKEventTags{MCPID: mcp, Group: group, Version: "v1", Kind: kind},
+			Timestamp:    start,
+			TimestampEnd: start.Add(time.Duration(hours) * time.Hour),
+			Value:        value,
+		}
+	}
+	pl, err := Parse([]byte(`{
+		"currency": "USD",
+		"prices": [
+			{"resources": "ec2.aws.upbound.io/*/Instance", "resource_hour": 0.5},
+			{"resources": "*.aws.upbound.io", "resource_hour": 0.25}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []model.MCPGVKEvent{
+		event("b", "s3.aws.upbound.io", "Bucket", 2, 1),
+		event("a", "ec2.aws.upbound.io", "Instance", 1, 2),
+		event("a", "s3.aws.upbound.io", "Bucket", 4, 1),
+		event("a", "storage.gcp.upbound.io", "Bucket", 3, 1),
+		event("b", "storage.gcp.upbound.io", "Bucket", 1, 2),
+	}
+	want := &Estimate{
+		Currency:      "USD",
+		Total:         2.5,
+		ResourceHours: 8,
+		ControlPlanes: []ControlPlane{
+			{ID: "a", ResourceHours: 6, Cost: 2},
+			{ID: "b", ResourceHours: 2, Cost: 0.5},
+		},
+		Uncovered: []Uncovered{
+			{Group: "storage.gcp.upbound.io", Version: "v1", Kind: "Bucket", Events: 2, ResourceHours: 5},
+		},
+	}
+	got := pl.Estimate(events)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Estimate(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(2, got.UncoveredEvents()); diff != "" {
+		t.Errorf("UncoveredEvents(): -want, +got:\n%s", diff)
+	}
+}
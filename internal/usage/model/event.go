@@ -0,0 +1,90 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// EventType identifies the type of a usage event.
+type EventType string
+
+// Types of usage events.
+const (
+	EventTypeMCPGVK        EventType = "mcp_gvk"
+	EventTypeMCPLifecycle  EventType = "mcp_lifecycle"
+	EventTypeMCPAPIRequest EventType = "mcp_api_request"
+)
+
+// Event is a usage event.
+type Event interface {
+	// EventType returns the type of the event.
+	EventType() EventType
+}
+
+// EventType returns the type of the event.
+func (MCPGVKEvent) EventType() EventType {
+	return EventTypeMCPGVK
+}
+
+// MCPLifecyclePhase is a phase in the lifecycle of an MCP.
+type MCPLifecyclePhase string
+
+// Phases in the lifecycle of an MCP.
+const (
+	MCPLifecycleCreated MCPLifecyclePhase = "created"
+	MCPLifecycleReady   MCPLifecyclePhase = "ready"
+	MCPLifecycleDeleted MCPLifecyclePhase = "deleted"
+)
+
+// MCPLifecycleEvent records an MCP entering a phase of its lifecycle.
+type MCPLifecycleEvent struct {
+	Name      string                `json:"name"`
+	Tags      MCPLifecycleEventTags `json:"tags"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+type MCPLifecycleEventTags struct {
+	UpboundAccount string            `json:"upbound_account"`
+	MCPID          string            `json:"mcp_id"`
+	Phase          MCPLifecyclePhase `json:"phase"`
+}
+
+// EventType returns the type of the event.
+func (MCPLifecycleEvent) EventType() EventType {
+	return EventTypeMCPLifecycle
+}
+
+// MCPAPIRequestEvent records the number of requests served by the API server
+// of an MCP in a time range.
+type MCPAPIRequestEvent struct {
+	Name         string                 `json:"name"`
+	Tags         MCPAPIRequestEventTags `json:"tags"`
+	Timestamp    time.Time              `json:"timestamp"`
+	TimestampEnd time.Time              `json:"timestamp_end"`
+	Value        float64                `json:"value"`
+}
+
+type MCPAPIRequestEventTags struct {
+	UpboundAccount string `json:"upbound_account"`
+	MCPID          string `json:"mcp_id"`
+	Verb           string `json:"verb"`
+	Code           string `json:"code"`
+}
+
+// EventType returns the type of the event.
+func (MCPAPIRequestEvent) EventType() EventType {
+	return EventTypeMCPAPIRequest
+}
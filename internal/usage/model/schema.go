@@ -0,0 +1,73 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Schema versions of encoded MCP GVK events. Events encoded before schema
+// versions were introduced do not record a version and have the v1 schema.
+const (
+	MCPGVKEventSchemaV1 = "v1"
+
+	// LatestMCPGVKEventSchema is the schema version of MCPGVKEvent.
+	LatestMCPGVKEventSchema = MCPGVKEventSchemaV1
+)
+
+// VersionedMCPGVKEvent is an MCP GVK event that records the version of its
+// schema when encoded.
+type VersionedMCPGVKEvent struct {
+	SchemaVersion string `json:"schema_version"`
+	MCPGVKEvent
+}
+
+// Versioned returns the event annotated with the latest schema version.
+func (e MCPGVKEvent) Versioned() VersionedMCPGVKEvent {
+	return VersionedMCPGVKEvent{SchemaVersion: LatestMCPGVKEventSchema, MCPGVKEvent: e}
+}
+
+// upconverters decode events of each schema version and convert them to the
+// latest schema. When the schema changes, the previous shape of MCPGVKEvent
+// is kept as a separate type that is decoded and converted here.
+var upconverters = map[string]func([]byte) (MCPGVKEvent, error){
+	"":                  decodeLatestMCPGVKEvent,
+	MCPGVKEventSchemaV1: decodeLatestMCPGVKEvent,
+}
+
+// DecodeMCPGVKEvent decodes an MCP GVK event encoded as JSON with any known
+// schema version, converting it to the latest schema.
+func DecodeMCPGVKEvent(b []byte) (MCPGVKEvent, error) {
+	v := struct {
+		SchemaVersion string `json:"schema_version"`
+	}{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return MCPGVKEvent{}, err
+	}
+	up, ok := upconverters[v.SchemaVersion]
+	if !ok {
+		return MCPGVKEvent{}, fmt.Errorf("unknown schema version %q", v.SchemaVersion)
+	}
+	return up(b)
+}
+
+func decodeLatestMCPGVKEvent(b []byte) (MCPGVKEvent, error) {
+	var e MCPGVKEvent
+	if err := json.Unmarshal(b, &e); err != nil {
+		return MCPGVKEvent{}, err
+	}
+	return e, nil
+}
@@ -16,6 +16,7 @@ package aws
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"golang.org/x/sync/errgroup"
 
+	uphttp "github.com/upbound/up/internal/http"
 	"github.com/upbound/up/internal/usage"
 	"github.com/upbound/up/internal/usage/aggregate"
 	"github.com/upbound/up/internal/usage/clientutil"
@@ -36,35 +38,126 @@ const (
 	// Number of objects to read concurrently.
 	concurrency = 10
 
-	errGetObject   = "error retrieving object from AWS S3"
-	errReadEvents  = "error reading events"
-	errWriteEvents = "error writing events"
+	errGetObject            = "error retrieving object from AWS S3"
+	errListObjects          = "error listing objects"
+	errListAccounts         = "error listing accounts"
+	errReadEvents           = "error reading events"
+	errWriteEvents          = "error writing events"
+	errAdaptiveNotSupported = "adaptive windows are not supported for AWS, as usage is read one hour at a time"
 )
 
 // GenerateReport initializes the client code and generates a usage report based on given inputs
-func GenerateReport(ctx context.Context, account, endpoint, bucket string, billingPeriod usage.TimeRange, w report.MCPGVKEventWriter) error {
-	sess, err := session.NewSession(&aws.Config{})
+func GenerateReport(ctx context.Context, account, endpoint, bucket string, creds clientutil.Credentials, billingPeriod usage.TimeRange, w report.MCPGVKEventWriter, opts ...report.GenerateOption) error {
+	o := report.NewGenerateOptions(opts...)
+	// Objects are listed by the prefix of a single hour, so a wider window
+	// would skip the usage data of its remaining hours.
+	if o.AdaptiveWindow != nil {
+		return errors.New(errAdaptiveNotSupported)
+	}
+	s3client, err := newClient(endpoint, creds)
 	if err != nil {
-		return errors.Wrap(err, "error creating aws session")
+		return err
 	}
-	config := &aws.Config{}
-	if endpoint != "" {
-		config = &aws.Config{
-			Endpoint: aws.String(endpoint),
+	if err := maxResourceCountPerGVKPerMCP(ctx, account, bucket, s3client, billingPeriod, w, o); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Partitions counts the usage objects stored for each hour of the time range.
+// At most 1000 objects are counted per hour.
+func Partitions(ctx context.Context, account, endpoint, bucket string, creds clientutil.Credentials, tr usage.TimeRange) ([]report.Partition, error) {
+	client, err := newClient(endpoint, creds)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := clientutil.NewUsageQueryIterator(account, tr.Start, tr.End, time.Hour)
+	if err != nil {
+		return nil, errors.Wrap(err, errListObjects)
+	}
+	parts := []report.Partition{}
+	for iter.More() {
+		startPrefix, _, start, end, err := iter.Next()
+		if err != nil {
+			return nil, errors.Wrap(err, errListObjects)
+		}
+		objects, err := client.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(startPrefix),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, errListObjects)
 		}
+		parts = append(parts, report.Partition{TimeRange: usage.TimeRange{Start: start, End: end}, Objects: len(objects.Contents)})
 	}
-	s3client := s3.New(sess, config)
+	return parts, nil
+}
 
-	if err := maxResourceCountPerGVKPerMCP(ctx, account, bucket, s3client, billingPeriod, w); err != nil {
-		return err
+// Accounts returns the sorted names of the accounts with usage data in the
+// bucket.
+func Accounts(ctx context.Context, endpoint, bucket string, creds clientutil.Credentials) ([]string, error) {
+	client, err := newClient(endpoint, creds)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	accounts := []string{}
+	err = client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(clientutil.AccountPrefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, p := range page.CommonPrefixes {
+			if account, ok := clientutil.AccountFromPrefix(aws.StringValue(p.Prefix)); ok {
+				accounts = append(accounts, account)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errListAccounts)
+	}
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// newClient returns an S3 client using the supplied credentials and custom
+// endpoint, if any. Requests are sent through the shared up transport.
+func newClient(endpoint string, creds clientutil.Credentials) (*s3.S3, error) {
+	sess, err := newSession(creds)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating aws session")
+	}
+	config := &aws.Config{
+		HTTPClient: uphttp.NewClient(),
+	}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+	return s3.New(sess, config), nil
+}
+
+// newSession returns a session using the supplied credentials. The default
+// credential chain is used for ambient credentials, which covers environment
+// variables, IAM roles for service accounts, and instance metadata.
+func newSession(creds clientutil.Credentials) (*session.Session, error) {
+	if creds.Ambient() {
+		return session.NewSession(&aws.Config{})
+	}
+	opts := session.Options{
+		Profile:           creds.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	}
+	if creds.File != "" {
+		opts.SharedConfigFiles = []string{creds.File}
+	}
+	return session.NewSessionWithOptions(opts)
 }
 
 // maxResourceCountPerGVKPerMCP reads usage data for an account and time range
 // from bkt and writes aggregated usage events to w. Events are aggregated
 // across 1hr windows of the time range.
-func maxResourceCountPerGVKPerMCP(ctx context.Context, account, bucket string, client *s3.S3, tr usage.TimeRange, w report.MCPGVKEventWriter) error {
+func maxResourceCountPerGVKPerMCP(ctx context.Context, account, bucket string, client *s3.S3, tr usage.TimeRange, w report.MCPGVKEventWriter, o report.GenerateOptions) error {
+	pc := &report.ProgressCounter{}
 	// TODO: Add support for aggregation windows other than 1 hour.
 	iter, err := clientutil.NewUsageQueryIterator(account, tr.Start, tr.End, time.Hour)
 	if err != nil {
@@ -99,7 +192,7 @@ func maxResourceCountPerGVKPerMCP(ctx context.Context, account, bucket string, c
 				if err != nil {
 					return errors.Wrap(err, errGetObject)
 				}
-				return readObject(ag, agMu, resp)
+				return readObject(ag, agMu, pc, resp)
 			})
 		}
 		if err := g.Wait(); err != nil {
@@ -113,13 +206,15 @@ func maxResourceCountPerGVKPerMCP(ctx context.Context, account, bucket string, c
 				return errors.Wrap(err, errWriteEvents)
 			}
 		}
+		completed, total := iter.Progress()
+		pc.Report(o.Progress, completed, total)
 	}
 	return nil
 }
 
 // readObject() decodes MCP GVK events from an object and adds them to an aggregate.
-func readObject(ag *aggregate.MaxResourceCountPerGVKPerMCP, agMu sync.Locker, obj *s3.GetObjectOutput) error {
-	d, err := json.NewMCPGVKEventDecoder(obj.Body)
+func readObject(ag *aggregate.MaxResourceCountPerGVKPerMCP, agMu sync.Locker, pc *report.ProgressCounter, obj *s3.GetObjectOutput) error {
+	d, err := json.NewMCPGVKEventDecoder(pc.Reader(obj.Body))
 	if err != nil {
 		return err
 	}
@@ -129,6 +224,7 @@ func readObject(ag *aggregate.MaxResourceCountPerGVKPerMCP, agMu sync.Locker, ob
 		if err != nil {
 			return err
 		}
+		pc.AddEvent()
 
 		agMu.Lock()
 		err = ag.Add(e)
@@ -0,0 +1,229 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tar
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/usage"
+	usagejson "github.com/upbound/up/internal/usage/encoding/json"
+	"github.com/upbound/up/internal/usage/model"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+const (
+	manifestFilename = "report/manifest.json"
+	usagePrefix      = "report/usage"
+
+	errOpenReport      = "unable to open usage report"
+	errReadArchive     = "unable to read usage report archive"
+	errReadDir         = "unable to read usage report directory"
+	errNoManifest      = "usage report does not contain a manifest"
+	errParseManifest   = "unable to parse usage report manifest"
+	errFmtMissingPart  = "usage file %s listed in the manifest is missing"
+	errFmtChecksum     = "checksum of usage file %s does not match the manifest"
+	errFmtParsePart    = "unable to parse usage file %s"
+	errFmtPartEvents   = "usage file %s contains %d events, the manifest lists %d"
+	errFmtTotalEvents  = "usage files contain %d events, the manifest lists %d"
+	errFmtUnlistedPart = "usage file %s is not listed in the manifest"
+	errMetaMismatch    = "usage report metadata does not match the manifest"
+	errParseMeta       = "unable to parse usage report metadata"
+)
+
+// Manifest lists the usage files of a usage report with their checksums and
+// event counts, so that the integrity of the report can be verified before it
+// is submitted.
+type Manifest struct {
+	UpboundAccount string          `json:"account"`
+	TimeRange      usage.TimeRange `json:"time_range"`
+	Events         int             `json:"events"`
+	Parts          []ManifestPart  `json:"parts"`
+
+	// Filter selected the events of the report. It is nil if the report
+	// contains all events for the account and time range.
+	Filter *report.EventFilter `json:"filter,omitempty"`
+}
+
+// ManifestPart describes a single usage file of a usage report.
+type ManifestPart struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	Events int    `json:"events"`
+}
+
+// Open reads the files of a usage report from path, which is either a gzipped
+// tar archive or a directory into which one has been extracted.
+func Open(p string) (map[string][]byte, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, errors.Wrap(err, errOpenReport)
+	}
+	if fi.IsDir() {
+		return ReadDir(os.DirFS(p))
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, errors.Wrap(err, errOpenReport)
+	}
+	defer f.Close() // nolint:errcheck
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(err, errOpenReport)
+	}
+	return ReadArchive(gr)
+}
+
+// ReadArchive reads the files of a usage report from a tar archive.
+func ReadArchive(r io.Reader) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadArchive)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadArchive)
+		}
+		files[path.Clean(h.Name)] = b
+	}
+}
+
+// ReadDir reads the files of a usage report that has been extracted into a
+// directory.
+func ReadDir(fsys fs.FS) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		files[p] = b
+		return nil
+	})
+	return files, errors.Wrap(err, errReadDir)
+}
+
+// Verify checks that the usage files of a usage report match its manifest and
+// returns the manifest. Files are keyed by their path in the report.
+func Verify(files map[string][]byte) (*Manifest, error) {
+	b, ok := files[manifestFilename]
+	if !ok {
+		return nil, errors.New(errNoManifest)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrap(err, errParseManifest)
+	}
+	if err := verifyMeta(files, m); err != nil {
+		return nil, err
+	}
+	listed := map[string]bool{}
+	total := 0
+	for _, p := range m.Parts {
+		listed[p.Name] = true
+		b, ok := files[p.Name]
+		if !ok {
+			return nil, errors.Errorf(errFmtMissingPart, p.Name)
+		}
+		sum := sha256.Sum256(b)
+		if hex.EncodeToString(sum[:]) != p.SHA256 {
+			return nil, errors.Errorf(errFmtChecksum, p.Name)
+		}
+		events := []json.RawMessage{}
+		if err := json.Unmarshal(b, &events); err != nil {
+			return nil, errors.Wrapf(err, errFmtParsePart, p.Name)
+		}
+		if len(events) != p.Events {
+			return nil, errors.Errorf(errFmtPartEvents, p.Name, len(events), p.Events)
+		}
+		total += len(events)
+	}
+	if total != m.Events {
+		return nil, errors.Errorf(errFmtTotalEvents, total, m.Events)
+	}
+	for name := range files {
+		if strings.HasPrefix(name, usagePrefix) && !listed[name] {
+			return nil, errors.Errorf(errFmtUnlistedPart, name)
+		}
+	}
+	return m, nil
+}
+
+// verifyMeta checks that the metadata of the report, if present, covers the
+// same account and time range, with the same filter, as the manifest.
+func verifyMeta(files map[string][]byte, m *Manifest) error {
+	b, ok := files[metaFilename]
+	if !ok {
+		return nil
+	}
+	meta := report.Meta{}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return errors.Wrap(err, errParseMeta)
+	}
+	if meta.UpboundAccount != m.UpboundAccount || !meta.TimeRange.Start.Equal(m.TimeRange.Start) || !meta.TimeRange.End.Equal(m.TimeRange.End) || !reflect.DeepEqual(meta.Filter, m.Filter) {
+		return errors.New(errMetaMismatch)
+	}
+	return nil
+}
+
+// Events decodes the events of the usage files listed in a manifest, in the
+// order in which they are listed. The files should have been verified against
+// the manifest with Verify().
+func Events(files map[string][]byte, m *Manifest) ([]model.MCPGVKEvent, error) {
+	events := []model.MCPGVKEvent{}
+	for _, p := range m.Parts {
+		b, ok := files[p.Name]
+		if !ok {
+			return nil, errors.Errorf(errFmtMissingPart, p.Name)
+		}
+		d, err := usagejson.NewMCPGVKEventDecoder(bytes.NewReader(b))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParsePart, p.Name)
+		}
+		for d.More() {
+			e, err := d.Decode()
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtParsePart, p.Name)
+			}
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tar
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+	"github.com/upbound/up/internal/usage/report"
+)
+
+// writeReport writes a usage report with the supplied number of events, split
+// into parts of at most maxFileSize bytes, and returns its files.
+func writeReport(t *testing.T, events int, maxFileSize int64) map[string][]byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	rw, err := NewWriter(tw, report.Meta{UpboundAccount: "test-account"}, WithMaxFileSize(maxFileSize))
+	if err != nil {
+		t.Fatalf("NewWriter(...): %s", err)
+	}
+	for i := 0; i < events; i++ {
+		if err := rw.Write(model.MCPGVKEvent{}); err != nil {
+			t.Fatalf("Writer.Write(...): %s", err)
+		}
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Writer.Close(): %s", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Writer.Close(): %s", err)
+	}
+	files, err := ReadArchive(buf)
+	if err != nil {
+		t.Fatalf("ReadArchive(...): %s", err)
+	}
+	return files
+}
+
+func TestVerify(t *testing.T) {
+	type want struct {
+		events int
+		parts  int
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		modify func(files map[string][]byte)
+		want   want
+	}{
+		"Valid": {
+			reason: "A report that has not been modified should be valid.",
+			modify: func(files map[string][]byte) {},
+			want: want{
+				events: 3,
+				parts:  3,
+			},
+		},
+		"NoManifest": {
+			reason: "A report without a manifest cannot be verified.",
+			modify: func(files map[string][]byte) {
+				delete(files, manifestFilename)
+			},
+			want: want{
+				err: errors.New(errNoManifest),
+			},
+		},
+		"MissingPart": {
+			reason: "A report that lacks a usage file listed in the manifest is not valid.",
+			modify: func(files map[string][]byte) {
+				delete(files, "report/usage-0002.json")
+			},
+			want: want{
+				err: errors.Errorf(errFmtMissingPart, "report/usage-0002.json"),
+			},
+		},
+		"ModifiedPart": {
+			reason: "A report with a usage file that does not match its checksum is not valid.",
+			modify: func(files map[string][]byte) {
+				files["report/usage-0002.json"] = []byte("[]")
+			},
+			want: want{
+				err: errors.Errorf(errFmtChecksum, "report/usage-0002.json"),
+			},
+		},
+		"UnlistedPart": {
+			reason: "A report with a usage file that is not listed in the manifest is not valid.",
+			modify: func(files map[string][]byte) {
+				files["report/usage-0004.json"] = []byte("[]")
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnlistedPart, "report/usage-0004.json"),
+			},
+		},
+		"MetaMismatch": {
+			reason: "A report whose metadata does not match the manifest is not valid.",
+			modify: func(files map[string][]byte) {
+				files[metaFilename] = []byte(`{"account":"other-account"}`)
+			},
+			want: want{
+				err: errors.New(errMetaMismatch),
+			},
+		},
+		"FilterMismatch": {
+			reason: "A report whose metadata records a filter that the manifest does not is not valid.",
+			modify: func(files map[string][]byte) {
+				files[metaFilename] = []byte(`{"account":"test-account","filter":{"include_mcps":["a"]}}`)
+			},
+			want: want{
+				err: errors.New(errMetaMismatch),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			files := writeReport(t, 3, 1)
+			tc.modify(files)
+			m, err := Verify(files)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nVerify(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.events, m.Events); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.parts, len(m.Parts)); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want parts, +got parts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVerifyFilter(t *testing.T) {
+	filter := &report.EventFilter{
+		IncludeGVKs: []report.GVKPattern{{Group: "*.aws.upbound.io"}},
+		ExcludeMCPs: []string{"a"},
+	}
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	rw, err := NewWriter(tw, report.Meta{UpboundAccount: "test-account", Filter: filter})
+	if err != nil {
+		t.Fatalf("NewWriter(...): %s", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Writer.Close(): %s", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Writer.Close(): %s", err)
+	}
+	files, err := ReadArchive(buf)
+	if err != nil {
+		t.Fatalf("ReadArchive(...): %s", err)
+	}
+	m, err := Verify(files)
+	if err != nil {
+		t.Fatalf("Verify(...): %s", err)
+	}
+	if diff := cmp.Diff(filter, m.Filter); diff != "" {
+		t.Errorf("Verify(...): -want filter, +got filter:\n%s", diff)
+	}
+}
+
+func TestVerifyExample(t *testing.T) {
+	f, err := os.Open("testdata/example.tar")
+	if err != nil {
+		t.Fatalf("os.Open(...): %s", err)
+	}
+	defer f.Close() //nolint:errcheck
+	files, err := ReadArchive(f)
+	if err != nil {
+		t.Fatalf("ReadArchive(...): %s", err)
+	}
+	m, err := Verify(files)
+	if err != nil {
+		t.Fatalf("Verify(...): %s", err)
+	}
+	if diff := cmp.Diff(7, m.Events); diff != "" {
+		t.Errorf("Verify(...): -want events, +got events:\n%s", diff)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	files := writeReport(t, 2, 0)
+	fsys := fstest.MapFS{}
+	for name, b := range files {
+		fsys[name] = &fstest.MapFile{Data: b}
+	}
+	got, err := ReadDir(fsys)
+	if err != nil {
+		t.Fatalf("ReadDir(...): %s", err)
+	}
+	if diff := cmp.Diff(files, got); diff != "" {
+		t.Errorf("ReadDir(...): -want, +got:\n%s", diff)
+	}
+}
@@ -17,7 +17,10 @@ package tar
 import (
 	"archive/tar"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 
 	usagejson "github.com/upbound/up/internal/usage/encoding/json"
 	"github.com/upbound/up/internal/usage/model"
@@ -25,9 +28,10 @@ import (
 )
 
 const (
-	metaFilename  = "report/meta.json"
-	usageFilename = "report/usage.json"
-	mode          = 0644
+	metaFilename         = "report/meta.json"
+	usageFilename        = "report/usage.json"
+	usagePartFilenameFmt = "report/usage-%04d.json"
+	mode                 = 0644
 )
 
 // Writer writes Upbound usage events for a single account to a usage report in
@@ -38,33 +42,129 @@ type Writer struct {
 	meta report.Meta
 	ee   *usagejson.MCPGVKEventEncoder
 	buf  *bytes.Buffer
+
+	// maxFileSize is the size in bytes after which usage data is rotated into
+	// a new part file. Zero disables rotation.
+	maxFileSize int64
+	part        int
+	partEvents  int
+
+	manifest Manifest
+}
+
+// WriterOption modifies a Writer.
+type WriterOption func(*Writer)
+
+// WithMaxFileSize rotates usage data into numbered part files, each holding a
+// valid JSON array, once the current part reaches size bytes. A part may
+// exceed size by at most one event.
+func WithMaxFileSize(size int64) WriterOption {
+	return func(w *Writer) {
+		w.maxFileSize = size
+	}
 }
 
 // NewWriter returns an initialized *Writer.
-func NewWriter(tw *tar.Writer, meta report.Meta) (*Writer, error) {
+func NewWriter(tw *tar.Writer, meta report.Meta, opts ...WriterOption) (*Writer, error) {
 	buf := &bytes.Buffer{}
 	ue, err := usagejson.NewMCPGVKEventEncoder(buf)
 	if err != nil {
 		return nil, err
 	}
-	return &Writer{tw: tw, meta: meta, ee: ue, buf: buf}, nil
+	w := &Writer{tw: tw, meta: meta, ee: ue, buf: buf, manifest: Manifest{
+		UpboundAccount: meta.UpboundAccount,
+		TimeRange:      meta.TimeRange,
+		Parts:          []ManifestPart{},
+		Filter:         meta.Filter,
+	}}
+	for _, o := range opts {
+		o(w)
+	}
+	return w, nil
 }
 
 // Write writes an Upbound usage event to a tar archive.
 func (w *Writer) Write(e model.MCPGVKEvent) error {
 	e.Tags.UpboundAccount = w.meta.UpboundAccount
-	return w.ee.Encode(e)
+	if err := w.ee.Encode(e); err != nil {
+		return err
+	}
+	w.partEvents++
+	if w.maxFileSize > 0 && int64(w.buf.Len()) >= w.maxFileSize {
+		return w.rotate()
+	}
+	return nil
 }
 
 // Close closes the writer.
 func (w *Writer) Close() error {
+	if w.maxFileSize > 0 {
+		// Only write a trailing part if it holds events or no parts have
+		// been written, so that the report always contains usage data.
+		if w.partEvents > 0 || w.part == 0 {
+			if err := w.rotate(); err != nil {
+				return err
+			}
+		}
+		if err := writeMeta(w.tw, w.meta); err != nil {
+			return err
+		}
+		return writeManifest(w.tw, w.manifest)
+	}
 	if err := w.ee.Close(); err != nil {
 		return err
 	}
 	if err := writeMeta(w.tw, w.meta); err != nil {
 		return err
 	}
-	return writeUsage(w.tw, w.buf.Bytes())
+	if err := w.writePart(usageFilename); err != nil {
+		return err
+	}
+	return writeManifest(w.tw, w.manifest)
+}
+
+// Manifest returns the manifest of the usage report. It is complete once the
+// writer has been closed.
+func (w *Writer) Manifest() Manifest {
+	return w.manifest
+}
+
+// writePart writes the buffered usage data to the archive and records it in
+// the manifest.
+func (w *Writer) writePart(name string) error {
+	b := w.buf.Bytes()
+	if err := writeUsage(w.tw, name, b); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	w.manifest.Parts = append(w.manifest.Parts, ManifestPart{
+		Name:   name,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(b)),
+		Events: w.partEvents,
+	})
+	w.manifest.Events += w.partEvents
+	return nil
+}
+
+// rotate closes the current part, writes it to the archive, and starts a new
+// part.
+func (w *Writer) rotate() error {
+	if err := w.ee.Close(); err != nil {
+		return err
+	}
+	w.part++
+	if err := w.writePart(fmt.Sprintf(usagePartFilenameFmt, w.part)); err != nil {
+		return err
+	}
+	w.buf.Reset()
+	w.partEvents = 0
+	ee, err := usagejson.NewMCPGVKEventEncoder(w.buf)
+	if err != nil {
+		return err
+	}
+	w.ee = ee
+	return nil
 }
 
 // writeMeta writes usage report metadata to a *tar.Writer.
@@ -84,10 +184,27 @@ func writeMeta(tw *tar.Writer, meta report.Meta) error {
 	return err
 }
 
+// writeManifest writes the manifest of a usage report to a *tar.Writer.
+func writeManifest(tw *tar.Writer, m Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name: manifestFilename,
+		Mode: mode,
+		Size: int64(len(b)),
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(b)
+	return err
+}
+
 // writeUsage writes usage data to a *tar.Writer.
-func writeUsage(tw *tar.Writer, b []byte) error {
+func writeUsage(tw *tar.Writer, name string, b []byte) error {
 	if err := tw.WriteHeader(&tar.Header{
-		Name: usageFilename,
+		Name: name,
 		Mode: mode,
 		Size: int64(len(b)),
 	}); err != nil {
@@ -17,6 +17,9 @@ package tar
 import (
 	"archive/tar"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -183,3 +186,95 @@ func TestWriter(t *testing.T) {
 		})
 	}
 }
+
+func TestWriterMaxFileSize(t *testing.T) {
+	meta := report.Meta{UpboundAccount: "test-account"}
+	type want struct {
+		files  []string
+		events []int
+	}
+	cases := map[string]struct {
+		reason      string
+		maxFileSize int64
+		events      int
+		want        want
+	}{
+		"NoEvents": {
+			reason:      "An empty usage part is written if no events are written.",
+			maxFileSize: 1,
+			events:      0,
+			want: want{
+				files:  []string{"report/usage-0001.json", metaFilename, manifestFilename},
+				events: []int{0},
+			},
+		},
+		"OneEventPerPart": {
+			reason:      "Each event is written to its own part if every event exceeds the max size.",
+			maxFileSize: 1,
+			events:      3,
+			want: want{
+				files:  []string{"report/usage-0001.json", "report/usage-0002.json", "report/usage-0003.json", metaFilename, manifestFilename},
+				events: []int{1, 1, 1},
+			},
+		},
+		"SinglePart": {
+			reason:      "All events are written to one part if the max size is not reached.",
+			maxFileSize: 1 << 20,
+			events:      3,
+			want: want{
+				files:  []string{"report/usage-0001.json", metaFilename, manifestFilename},
+				events: []int{3},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tw := tar.NewWriter(buf)
+			rw, err := NewWriter(tw, meta, WithMaxFileSize(tc.maxFileSize))
+			if err != nil {
+				t.Fatalf("\n%s\nNewWriter(...): %s", tc.reason, err)
+			}
+			for i := 0; i < tc.events; i++ {
+				if err := rw.Write(model.MCPGVKEvent{}); err != nil {
+					t.Fatalf("\n%s\nWriter.Write(...): %s", tc.reason, err)
+				}
+			}
+			if err := rw.Close(); err != nil {
+				t.Fatalf("\n%s\nWriter.Close(): %s", tc.reason, err)
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatalf("\n%s\ntar.Writer.Close(): %s", tc.reason, err)
+			}
+
+			files := []string{}
+			events := []int{}
+			tr := tar.NewReader(buf)
+			for {
+				h, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("\n%s\ntar.Reader.Next(): %s", tc.reason, err)
+				}
+				files = append(files, h.Name)
+				if h.Name == metaFilename || h.Name == manifestFilename {
+					continue
+				}
+				part := []model.MCPGVKEvent{}
+				if err := json.NewDecoder(tr).Decode(&part); err != nil {
+					t.Fatalf("\n%s\njson.Decode(%s): %s", tc.reason, h.Name, err)
+				}
+				events = append(events, len(part))
+			}
+			if diff := cmp.Diff(tc.want.files, files); diff != "" {
+				t.Errorf("\n%s\nWriter: -want files, +got files:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, events); diff != "" {
+				t.Errorf("\n%s\nWriter: -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
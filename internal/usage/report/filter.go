@@ -0,0 +1,196 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+const (
+	errFmtInvalidGVKPattern = "invalid GVK pattern %q: must be of the form group[/version[/kind]]"
+)
+
+// GVKPattern matches the group, version, and kind of an MCP GVK event. Each
+// segment is a shell pattern as understood by path.Match. Empty segments match
+// any value.
+type GVKPattern struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+// ParseGVKPattern parses a pattern of the form group[/version[/kind]].
+func ParseGVKPattern(s string) (GVKPattern, error) {
+	parts := strings.Split(s, "/")
+	if s == "" || len(parts) > 3 {
+		return GVKPattern{}, errors.Errorf(errFmtInvalidGVKPattern, s)
+	}
+	p := GVKPattern{Group: parts[0]}
+	if len(parts) > 1 {
+		p.Version = parts[1]
+	}
+	if len(parts) > 2 {
+		p.Kind = parts[2]
+	}
+	// Validate each segment so that malformed patterns are rejected up front
+	// rather than silently failing to match.
+	for _, seg := range []string{p.Group, p.Version, p.Kind} {
+		if _, err := path.Match(seg, ""); err != nil {
+			return GVKPattern{}, errors.Errorf(errFmtInvalidGVKPattern, s)
+		}
+	}
+	return p, nil
+}
+
+// Matches returns true if the event's GVK tags match the pattern.
+func (p GVKPattern) Matches(e model.MCPGVKEvent) bool {
+	return matchSegment(p.Group, e.Tags.Group) &&
+		matchSegment(p.Version, e.Tags.Version) &&
+		matchSegment(p.Kind, e.Tags.Kind)
+}
+
+// String returns the pattern in the form group[/version[/kind]].
+func (p GVKPattern) String() string {
+	s := p.Group
+	if p.Version != "" || p.Kind != "" {
+		s += "/" + p.Version
+	}
+	if p.Kind != "" {
+		s += "/" + p.Kind
+	}
+	return s
+}
+
+// MarshalText encodes the pattern in the form group[/version[/kind]].
+func (p GVKPattern) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a pattern of the form group[/version[/kind]].
+func (p *GVKPattern) UnmarshalText(b []byte) error {
+	parsed, err := ParseGVKPattern(string(b))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func matchSegment(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// EventFilter selects MCP GVK events by GVK and MCP. An event is selected if
+// it matches any include pattern (or no include patterns are set) and does not
+// match any exclude pattern.
+type EventFilter struct {
+	IncludeGVKs []GVKPattern `json:"include_gvks,omitempty"`
+	ExcludeGVKs []GVKPattern `json:"exclude_gvks,omitempty"`
+	IncludeMCPs []string     `json:"include_mcps,omitempty"`
+	ExcludeMCPs []string     `json:"exclude_mcps,omitempty"`
+}
+
+// Matches returns true if the event is selected by the filter.
+func (f EventFilter) Matches(e model.MCPGVKEvent) bool {
+	if len(f.IncludeGVKs) > 0 && !anyGVK(f.IncludeGVKs, e) {
+		return false
+	}
+	if anyGVK(f.ExcludeGVKs, e) {
+		return false
+	}
+	if len(f.IncludeMCPs) > 0 && !contains(f.IncludeMCPs, e.Tags.MCPID) {
+		return false
+	}
+	return !contains(f.ExcludeMCPs, e.Tags.MCPID)
+}
+
+// Empty returns true if the filter selects all events.
+func (f EventFilter) Empty() bool {
+	return len(f.IncludeGVKs) == 0 && len(f.ExcludeGVKs) == 0 && len(f.IncludeMCPs) == 0 && len(f.ExcludeMCPs) == 0
+}
+
+// String describes the filter, e.g. "include GVKs *.aws.upbound.io; exclude
+// MCPs 4f8a...".
+func (f EventFilter) String() string {
+	parts := []string{}
+	add := func(name string, values []string) {
+		if len(values) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", name, strings.Join(values, ", ")))
+		}
+	}
+	add("include GVKs", gvkStrings(f.IncludeGVKs))
+	add("exclude GVKs", gvkStrings(f.ExcludeGVKs))
+	add("include MCPs", f.IncludeMCPs)
+	add("exclude MCPs", f.ExcludeMCPs)
+	return strings.Join(parts, "; ")
+}
+
+func gvkStrings(patterns []GVKPattern) []string {
+	s := make([]string, len(patterns))
+	for i, p := range patterns {
+		s[i] = p.String()
+	}
+	return s
+}
+
+func anyGVK(patterns []GVKPattern, e model.MCPGVKEvent) bool {
+	for _, p := range patterns {
+		if p.Matches(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterWriter is an MCPGVKEventWriter that only writes events selected by a
+// filter to an underlying writer.
+type FilterWriter struct {
+	w      MCPGVKEventWriter
+	filter EventFilter
+}
+
+// NewFilterWriter returns a *FilterWriter that writes events selected by
+// filter to w.
+func NewFilterWriter(w MCPGVKEventWriter, filter EventFilter) *FilterWriter {
+	return &FilterWriter{w: w, filter: filter}
+}
+
+// Write writes the event to the underlying writer if it is selected by the
+// filter.
+func (fw *FilterWriter) Write(e model.MCPGVKEvent) error {
+	if !fw.filter.Matches(e) {
+		return nil
+	}
+	return fw.w.Write(e)
+}
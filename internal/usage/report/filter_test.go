@@ -0,0 +1,206 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestParseGVKPattern(t *testing.T) {
+	type want struct {
+		pattern GVKPattern
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty pattern should return an error.",
+			s:      "",
+			want: want{
+				err: errors.Errorf(errFmtInvalidGVKPattern, ""),
+			},
+		},
+		"TooManySegments": {
+			reason: "A pattern with more than three segments should return an error.",
+			s:      "a/b/c/d",
+			want: want{
+				err: errors.Errorf(errFmtInvalidGVKPattern, "a/b/c/d"),
+			},
+		},
+		"Malformed": {
+			reason: "A pattern with a malformed segment should return an error.",
+			s:      "[a/v1",
+			want: want{
+				err: errors.Errorf(errFmtInvalidGVKPattern, "[a/v1"),
+			},
+		},
+		"GroupOnly": {
+			reason: "A group pattern should leave version and kind unset.",
+			s:      "*.crossplane.io",
+			want: want{
+				pattern: GVKPattern{Group: "*.crossplane.io"},
+			},
+		},
+		"Full": {
+			reason: "A full pattern should set all segments.",
+			s:      "ec2.aws.upbound.io/v1beta1/Instance",
+			want: want{
+				pattern: GVKPattern{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseGVKPattern(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseGVKPattern(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pattern, got); diff != "" {
+				t.Errorf("\n%s\nParseGVKPattern(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGVKPatternText(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		pattern GVKPattern
+		want    string
+	}{
+		"GroupOnly": {
+			reason:  "A group pattern should omit version and kind.",
+			pattern: GVKPattern{Group: "*.crossplane.io"},
+			want:    "*.crossplane.io",
+		},
+		"GroupVersion": {
+			reason:  "A group and version pattern should omit kind.",
+			pattern: GVKPattern{Group: "ec2.aws.upbound.io", Version: "v1beta1"},
+			want:    "ec2.aws.upbound.io/v1beta1",
+		},
+		"AnyVersion": {
+			reason:  "A pattern that matches any version of a kind should keep the empty version.",
+			pattern: GVKPattern{Group: "ec2.aws.upbound.io", Kind: "Instance"},
+			want:    "ec2.aws.upbound.io//Instance",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := tc.pattern.MarshalText()
+			if err != nil {
+				t.Fatalf("\n%s\nMarshalText(): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(b)); diff != "" {
+				t.Errorf("\n%s\nMarshalText(): -want, +got:\n%s", tc.reason, diff)
+			}
+			got := GVKPattern{}
+			if err := got.UnmarshalText(b); err != nil {
+				t.Fatalf("\n%s\nUnmarshalText(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.pattern, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshalText(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEventFilterString(t *testing.T) {
+	f := EventFilter{
+		IncludeGVKs: []GVKPattern{{Group: "*.aws.upbound.io"}, {Group: "*.gcp.upbound.io"}},
+		ExcludeMCPs: []string{"a"},
+	}
+	want := "include GVKs *.aws.upbound.io, *.gcp.upbound.io; exclude MCPs a"
+	if diff := cmp.Diff(want, f.String()); diff != "" {
+		t.Errorf("String(): -want, +got:\n%s", diff)
+	}
+}
+
+type sliceWriter struct {
+	events []model.MCPGVKEvent
+}
+
+func (s *sliceWriter) Write(e model.MCPGVKEvent) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestFilterWriter(t *testing.T) {
+	ec2 := model.MCPGVKEvent{Tags: model.MCPGVKEventTags{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance", MCPID: "mcp-a"}}
+	rds := model.MCPGVKEvent{Tags: model.MCPGVKEventTags{Group: "rds.aws.upbound.io", Version: "v1beta1", Kind: "Instance", MCPID: "mcp-b"}}
+	pkg := model.MCPGVKEvent{Tags: model.MCPGVKEventTags{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider", MCPID: "mcp-a"}}
+	events := []model.MCPGVKEvent{ec2, rds, pkg}
+
+	cases := map[string]struct {
+		reason string
+		filter EventFilter
+		want   []model.MCPGVKEvent
+	}{
+		"NoFilter": {
+			reason: "An empty filter should write all events.",
+			want:   events,
+		},
+		"ExcludeGVK": {
+			reason: "Events matching an exclude pattern should not be written.",
+			filter: EventFilter{ExcludeGVKs: []GVKPattern{{Group: "*.crossplane.io"}}},
+			want:   []model.MCPGVKEvent{ec2, rds},
+		},
+		"IncludeGVK": {
+			reason: "Only events matching an include pattern should be written.",
+			filter: EventFilter{IncludeGVKs: []GVKPattern{{Group: "*.aws.upbound.io", Kind: "Instance"}}},
+			want:   []model.MCPGVKEvent{ec2, rds},
+		},
+		"IncludeAndExclude": {
+			reason: "Exclude patterns should take precedence over include patterns.",
+			filter: EventFilter{
+				IncludeGVKs: []GVKPattern{{Group: "*.aws.upbound.io"}},
+				ExcludeGVKs: []GVKPattern{{Group: "rds.aws.upbound.io"}},
+			},
+			want: []model.MCPGVKEvent{ec2},
+		},
+		"IncludeMCP": {
+			reason: "Only events for included MCPs should be written.",
+			filter: EventFilter{IncludeMCPs: []string{"mcp-a"}},
+			want:   []model.MCPGVKEvent{ec2, pkg},
+		},
+		"ExcludeMCP": {
+			reason: "Events for excluded MCPs should not be written.",
+			filter: EventFilter{ExcludeMCPs: []string{"mcp-a"}},
+			want:   []model.MCPGVKEvent{rds},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sw := &sliceWriter{}
+			fw := NewFilterWriter(sw, tc.filter)
+			for _, e := range events {
+				if err := fw.Write(e); err != nil {
+					t.Fatalf("Write(...): %s", err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, sw.events); diff != "" {
+				t.Errorf("\n%s\nWrite(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
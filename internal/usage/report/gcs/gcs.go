@@ -16,6 +16,7 @@ package gcs
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 
 	"github.com/upbound/up/internal/usage"
 	"github.com/upbound/up/internal/usage/aggregate"
+	"github.com/upbound/up/internal/usage/clientutil"
 	"github.com/upbound/up/internal/usage/clientutil/gcs"
 	"github.com/upbound/up/internal/usage/encoding/json"
 	"github.com/upbound/up/internal/usage/report"
@@ -36,34 +38,129 @@ const (
 	// Number of objects to read concurrently.
 	concurrency = 10
 
-	errReadEvents  = "error reading events"
-	errWriteEvents = "error writing events"
+	errReadEvents          = "error reading events"
+	errListObjects         = "error listing objects"
+	errListAccounts        = "error listing accounts"
+	errWriteEvents         = "error writing events"
+	errProfileNotSupported = "credentials profiles are not supported for GCP"
 )
 
 // GenerateReport initializes the client code and generates a usage report based on given inputs
-func GenerateReport(ctx context.Context, account, endpoint, bucket string, billingPeriod usage.TimeRange, window time.Duration, w report.MCPGVKEventWriter) error {
-	opts := []gcpopt.ClientOption{}
-	if endpoint != "" {
-		opts = append(opts, gcpopt.WithEndpoint(endpoint))
+func GenerateReport(ctx context.Context, account, endpoint, bucket string, creds clientutil.Credentials, bo gcs.BucketOptions, billingPeriod usage.TimeRange, window time.Duration, w report.MCPGVKEventWriter, opts ...report.GenerateOption) error {
+	if creds.Profile != "" {
+		return errors.New(errProfileNotSupported)
 	}
-	gcsCli, err := storage.NewClient(ctx, opts...)
+	bkt, err := newBucket(ctx, endpoint, bucket, creds, bo)
 	if err != nil {
-		return errors.Wrap(err, "error creating storage client")
+		return err
 	}
-	bkt := gcsCli.Bucket(bucket)
-	if err := maxResourceCountPerGVKPerMCP(ctx, account, bkt, billingPeriod, time.Hour, w); err != nil {
+	if err := maxResourceCountPerGVKPerMCP(ctx, account, bkt, bo, billingPeriod, time.Hour, w, report.NewGenerateOptions(opts...)); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Partitions counts the usage objects stored for each hour of the time range.
+func Partitions(ctx context.Context, account, endpoint, bucket string, creds clientutil.Credentials, bo gcs.BucketOptions, tr usage.TimeRange) ([]report.Partition, error) {
+	if creds.Profile != "" {
+		return nil, errors.New(errProfileNotSupported)
+	}
+	bkt, err := newBucket(ctx, endpoint, bucket, creds, bo)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := gcs.NewUsageQueryIterator(account, tr.Start, tr.End, time.Hour)
+	if err != nil {
+		return nil, errors.Wrap(err, errListObjects)
+	}
+	parts := []report.Partition{}
+	for iter.More() {
+		query, start, end, err := iter.Next()
+		if err != nil {
+			return nil, errors.Wrap(err, errListObjects)
+		}
+		// Only names are required to count objects.
+		if err := query.SetAttrSelection([]string{"Name"}); err != nil {
+			return nil, errors.Wrap(err, errListObjects)
+		}
+		n := 0
+		objects := bkt.Objects(ctx, query)
+		for {
+			_, err := objects.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, errListObjects)
+			}
+			n++
+		}
+		parts = append(parts, report.Partition{TimeRange: usage.TimeRange{Start: start, End: end}, Objects: n})
+	}
+	return parts, nil
+}
+
+// Accounts returns the sorted names of the accounts with usage data in the
+// bucket.
+func Accounts(ctx context.Context, endpoint, bucket string, creds clientutil.Credentials, bo gcs.BucketOptions) ([]string, error) {
+	if creds.Profile != "" {
+		return nil, errors.New(errProfileNotSupported)
+	}
+	bkt, err := newBucket(ctx, endpoint, bucket, creds, bo)
+	if err != nil {
+		return nil, err
+	}
+	accounts := []string{}
+	objects := bkt.Objects(ctx, &storage.Query{Prefix: clientutil.AccountPrefix, Delimiter: "/"})
+	for {
+		attrs, err := objects.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errListAccounts)
+		}
+		if account, ok := clientutil.AccountFromPrefix(attrs.Prefix); ok {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// newBucket returns a handle to the bucket. Application default credentials
+// are used unless a service account key file is supplied, which covers
+// workload identity and instance metadata. Requests are sent through the
+// shared up transport.
+func newBucket(ctx context.Context, endpoint, bucket string, creds clientutil.Credentials, bo gcs.BucketOptions) (*storage.BucketHandle, error) {
+	credOpts := []gcpopt.ClientOption{}
+	if creds.File != "" {
+		credOpts = append(credOpts, gcpopt.WithCredentialsFile(creds.File))
+	}
+	hc, err := clientutil.GoogleHTTPClient(ctx, []string{storage.ScopeReadOnly}, credOpts...)
+	if err != nil {
+		return nil, err
+	}
+	opts := []gcpopt.ClientOption{gcpopt.WithHTTPClient(hc)}
+	if endpoint != "" {
+		opts = append(opts, gcpopt.WithEndpoint(endpoint))
+	}
+	gcsCli, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating storage client")
+	}
+	return gcs.Bucket(gcsCli, bucket, bo), nil
+}
+
 // maxResourceCountPerGVKPerMCP reads usage data for an account and time range
 // from bkt and writes aggregated usage events to w. Events are aggregated
-// across each window of the time range.
-func maxResourceCountPerGVKPerMCP(ctx context.Context, account string, bkt *storage.BucketHandle, tr usage.TimeRange, window time.Duration, w report.MCPGVKEventWriter) error {
+// across each window of the time range. If o configures an adaptive window,
+// each window is resized according to the number of objects in the last.
+func maxResourceCountPerGVKPerMCP(ctx context.Context, account string, bkt *storage.BucketHandle, bo gcs.BucketOptions, tr usage.TimeRange, window time.Duration, w report.MCPGVKEventWriter, o report.GenerateOptions) error { //nolint:gocyclo
+	pc := &report.ProgressCounter{}
 	// TODO(branden): Extract provider-generic upbound event reader interface so
 	// that this function can be reused across providers.
-	iter, err := gcs.NewUsageQueryIterator(account, tr.Start, tr.End, window)
+	iter, err := newIterator(account, tr, window, o)
 	if err != nil {
 		return errors.Wrap(err, errReadEvents)
 	}
@@ -80,20 +177,28 @@ func maxResourceCountPerGVKPerMCP(ctx context.Context, account string, bkt *stor
 		ag := &aggregate.MaxResourceCountPerGVKPerMCP{}
 		agMu := &sync.Mutex{}
 
+		n := 0
 		for {
 			attrs, err := objects.Next()
 			if errors.Is(err, iterator.Done) {
 				break
 			}
+			// Listing fails if, for example, a requester-pays bucket is
+			// read without a billing project.
+			if err != nil {
+				return errors.Wrap(err, errReadEvents)
+			}
 
-			obj := bkt.Object(attrs.Name)
+			n++
+			obj := gcs.Object(bkt, attrs.Name, bo)
 			g.Go(func() error {
-				return readObject(ctx, ag, agMu, obj)
+				return readObject(ctx, ag, agMu, pc, obj)
 			})
 		}
 		if err := g.Wait(); err != nil {
 			return errors.Wrap(err, errReadEvents)
 		}
+		iter.Observe(n)
 
 		for _, e := range ag.UpboundEvents() {
 			e.Timestamp = start
@@ -102,19 +207,30 @@ func maxResourceCountPerGVKPerMCP(ctx context.Context, account string, bkt *stor
 				return errors.Wrap(err, errWriteEvents)
 			}
 		}
+		completed, total := iter.Progress()
+		pc.Report(o.Progress, completed, total)
 	}
 	return nil
 }
 
+// newIterator() returns an iterator across tr, which is adaptive if o
+// configures an adaptive window.
+func newIterator(account string, tr usage.TimeRange, window time.Duration, o report.GenerateOptions) (*gcs.UsageQueryIterator, error) {
+	if o.AdaptiveWindow != nil {
+		return gcs.NewAdaptiveUsageQueryIterator(account, tr.Start, tr.End, *o.AdaptiveWindow)
+	}
+	return gcs.NewUsageQueryIterator(account, tr.Start, tr.End, window)
+}
+
 // readObject() decodes MCP GVK events from an object and adds them to an aggregate.
-func readObject(ctx context.Context, ag *aggregate.MaxResourceCountPerGVKPerMCP, agMu sync.Locker, obj *storage.ObjectHandle) error {
+func readObject(ctx context.Context, ag *aggregate.MaxResourceCountPerGVKPerMCP, agMu sync.Locker, pc *report.ProgressCounter, obj *storage.ObjectHandle) error {
 	r, err := obj.NewReader(ctx)
 	if err != nil {
 		return err
 	}
 	defer r.Close() // nolint:errcheck
 
-	d, err := json.NewMCPGVKEventDecoder(r)
+	d, err := json.NewMCPGVKEventDecoder(pc.Reader(r))
 	if err != nil {
 		return err
 	}
@@ -124,6 +240,7 @@ func readObject(ctx context.Context, ag *aggregate.MaxResourceCountPerGVKPerMCP,
 		if err != nil {
 			return err
 		}
+		pc.AddEvent()
 
 		agMu.Lock()
 		err = ag.Add(e)
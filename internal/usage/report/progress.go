@@ -0,0 +1,102 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/upbound/up/internal/usage"
+	"github.com/upbound/up/internal/usage/clientutil"
+)
+
+// GenerateOptions configure how a report is generated.
+type GenerateOptions struct {
+	// Progress is called each time a window of the time range has been
+	// read. May be nil.
+	Progress usage.ProgressFunc
+	// AdaptiveWindow resizes the window across which events are aggregated
+	// according to the number of objects read for each window. The window is
+	// fixed if AdaptiveWindow is nil. Not supported by every provider.
+	AdaptiveWindow *clientutil.AdaptiveWindow
+}
+
+// A GenerateOption modifies GenerateOptions.
+type GenerateOption func(*GenerateOptions)
+
+// WithProgress reports the progress of generating a report to f.
+func WithProgress(f usage.ProgressFunc) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.Progress = f
+	}
+}
+
+// WithAdaptiveWindow resizes the window across which events are aggregated
+// according to a.
+func WithAdaptiveWindow(a clientutil.AdaptiveWindow) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.AdaptiveWindow = &a
+	}
+}
+
+// NewGenerateOptions returns GenerateOptions modified by opts.
+func NewGenerateOptions(opts ...GenerateOption) GenerateOptions {
+	o := GenerateOptions{}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// ProgressCounter counts the bytes read and events decoded while generating a
+// report. It is safe for concurrent use.
+type ProgressCounter struct {
+	bytes  atomic.Int64
+	events atomic.Int64
+}
+
+// Reader returns a reader that counts the bytes read from r.
+func (c *ProgressCounter) Reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &c.bytes}
+}
+
+// AddEvent counts a decoded event.
+func (c *ProgressCounter) AddEvent() {
+	c.events.Add(1)
+}
+
+// Report calls f, if it is not nil, with the counted progress.
+func (c *ProgressCounter) Report(f usage.ProgressFunc, completed, total int) {
+	if f == nil {
+		return
+	}
+	f(usage.Progress{
+		WindowsCompleted: completed,
+		WindowsTotal:     total,
+		BytesRead:        c.bytes.Load(),
+		EventsDecoded:    c.events.Load(),
+	})
+}
+
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage"
+)
+
+func TestProgressCounter(t *testing.T) {
+	type args struct {
+		data      []string
+		events    int
+		completed int
+		total     int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   usage.Progress
+	}{
+		"Empty": {
+			reason: "Nothing read should report zero bytes and events.",
+			args: args{
+				completed: 0,
+				total:     3,
+			},
+			want: usage.Progress{WindowsTotal: 3},
+		},
+		"ReadAndDecoded": {
+			reason: "Bytes read from every reader and every decoded event should be counted.",
+			args: args{
+				data:      []string{"hello", "world!"},
+				events:    4,
+				completed: 2,
+				total:     3,
+			},
+			want: usage.Progress{
+				WindowsCompleted: 2,
+				WindowsTotal:     3,
+				BytesRead:        11,
+				EventsDecoded:    4,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &ProgressCounter{}
+			for _, d := range tc.args.data {
+				if _, err := io.Copy(io.Discard, c.Reader(strings.NewReader(d))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := 0; i < tc.args.events; i++ {
+				c.AddEvent()
+			}
+
+			var got usage.Progress
+			c.Report(func(p usage.Progress) { got = p }, tc.args.completed, tc.args.total)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReport(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -26,6 +26,16 @@ type Meta struct {
 	UpboundAccount string          `json:"account"`
 	TimeRange      usage.TimeRange `json:"time_range"`
 	CollectedAt    time.Time       `json:"collected_at"`
+
+	// Filter selected the events of the report. It is nil if the report
+	// contains all events for the account and time range.
+	Filter *EventFilter `json:"filter,omitempty"`
+}
+
+// Partition is the number of usage objects stored for an hour of usage.
+type Partition struct {
+	TimeRange usage.TimeRange
+	Objects   int
 }
 
 // MCPGVKEventWriter is the interface that wraps a Write method for MCP GVK
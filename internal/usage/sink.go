@@ -0,0 +1,66 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"context"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+// An EventSink receives batches of usage events, e.g. to stream them into a
+// data platform.
+type EventSink interface {
+	Send(ctx context.Context, events []model.MCPGVKEvent) error
+}
+
+// BatchWriter writes usage events to an EventSink in batches. Callers must
+// call Flush() when finished writing.
+type BatchWriter struct {
+	ctx   context.Context
+	sink  EventSink
+	size  int
+	batch []model.MCPGVKEvent
+}
+
+// NewBatchWriter returns a BatchWriter that sends events to the sink in
+// batches of at most size events.
+func NewBatchWriter(ctx context.Context, sink EventSink, size int) *BatchWriter {
+	if size < 1 {
+		size = 1
+	}
+	return &BatchWriter{ctx: ctx, sink: sink, size: size}
+}
+
+// Write adds an event to the current batch, sending the batch if it is full.
+func (w *BatchWriter) Write(e model.MCPGVKEvent) error {
+	w.batch = append(w.batch, e)
+	if len(w.batch) < w.size {
+		return nil
+	}
+	return w.Flush()
+}
+
+// Flush sends the current batch, if it contains any events.
+func (w *BatchWriter) Flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	if err := w.sink.Send(w.ctx, w.batch); err != nil {
+		return err
+	}
+	w.batch = nil
+	return nil
+}
@@ -0,0 +1,319 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bigquery provides a usage event sink that streams events into a
+// BigQuery table.
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+const (
+	// defaultMaxRows is the number of rows inserted per request, as
+	// recommended for streaming inserts.
+	defaultMaxRows = 500
+	// defaultAttempts is the number of times a request is attempted before
+	// giving up.
+	defaultAttempts = 5
+	// defaultBackoff is the time waited before the first retry. It doubles
+	// with each retry.
+	defaultBackoff = time.Second
+
+	errFmtParseTable  = "invalid table %q: must have the form project.dataset.table"
+	errGetTable       = "unable to get BigQuery table"
+	errCreateTable    = "unable to create BigQuery table"
+	errInsertRows     = "unable to insert usage events into BigQuery"
+	errFmtInsertRows  = "failed to insert %d of %d usage events into BigQuery: %s"
+	errFmtGiveUpRetry = "giving up after %d attempts"
+)
+
+// Table identifies a BigQuery table.
+type Table struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// ParseTable parses a table of the form project.dataset.table.
+func ParseTable(s string) (Table, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return Table{}, errors.Errorf(errFmtParseTable, s)
+	}
+	return Table{Project: parts[0], Dataset: parts[1], Table: parts[2]}, nil
+}
+
+// String returns the table in the form project.dataset.table.
+func (t Table) String() string {
+	return t.Project + "." + t.Dataset + "." + t.Table
+}
+
+// Schema is the schema of the table into which usage events are inserted. The
+// table is partitioned by day on the timestamp column.
+var Schema = &bq.TableSchema{
+	Fields: []*bq.TableFieldSchema{
+		{Name: "name", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "upbound_account", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "mcp_id", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "customresource_group", Type: "STRING"},
+		{Name: "customresource_version", Type: "STRING"},
+		{Name: "customresource_kind", Type: "STRING"},
+		{Name: "timestamp", Type: "TIMESTAMP", Mode: "REQUIRED"},
+		{Name: "timestamp_end", Type: "TIMESTAMP", Mode: "REQUIRED"},
+		{Name: "value", Type: "FLOAT", Mode: "REQUIRED"},
+	},
+}
+
+// Sink streams usage events into a BigQuery table. The table is created with
+// Schema if it does not exist. Each event is inserted with an ID derived from
+// its tags and time range, so that BigQuery can drop events that are sent
+// again, e.g. when a request is retried.
+type Sink struct {
+	svc   *bq.Service
+	table Table
+
+	maxRows  int
+	attempts int
+	backoff  time.Duration
+
+	// mu guards ensured, which records whether the table is known to
+	// exist.
+	mu      sync.Mutex
+	ensured bool
+}
+
+// Option modifies a Sink.
+type Option func(*Sink)
+
+// WithMaxRows sets the maximum number of rows inserted per request. Larger
+// batches of events are split across several requests.
+func WithMaxRows(n int) Option {
+	return func(s *Sink) {
+		s.maxRows = n
+	}
+}
+
+// WithRetry sets the number of times a request that fails with a transient
+// error is attempted, and the time waited before the first retry. The wait
+// doubles with each retry.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(s *Sink) {
+		s.attempts = attempts
+		s.backoff = backoff
+	}
+}
+
+// NewSink constructs a Sink that inserts events into the table using the
+// supplied BigQuery service.
+func NewSink(svc *bq.Service, table Table, opts ...Option) *Sink {
+	s := &Sink{
+		svc:      svc,
+		table:    table,
+		maxRows:  defaultMaxRows,
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.maxRows < 1 {
+		s.maxRows = 1
+	}
+	if s.attempts < 1 {
+		s.attempts = 1
+	}
+	return s
+}
+
+// Send inserts a batch of events, creating the table first if necessary.
+func (s *Sink) Send(ctx context.Context, events []model.MCPGVKEvent) error {
+	if err := s.ensureTable(ctx); err != nil {
+		return err
+	}
+	for len(events) > 0 {
+		n := len(events)
+		if n > s.maxRows {
+			n = s.maxRows
+		}
+		if err := s.insert(ctx, events[:n]); err != nil {
+			return err
+		}
+		events = events[n:]
+	}
+	return nil
+}
+
+// ensureTable creates the table if it does not exist.
+func (s *Sink) ensureTable(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ensured {
+		return nil
+	}
+	err := s.retry(ctx, func() error {
+		_, err := s.svc.Tables.Get(s.table.Project, s.table.Dataset, s.table.Table).Context(ctx).Do()
+		return err
+	})
+	if isCode(err, http.StatusNotFound) {
+		err = s.retry(ctx, func() error {
+			_, err := s.svc.Tables.Insert(s.table.Project, s.table.Dataset, &bq.Table{
+				TableReference: &bq.TableReference{
+					ProjectId: s.table.Project,
+					DatasetId: s.table.Dataset,
+					TableId:   s.table.Table,
+				},
+				Schema: Schema,
+				TimePartitioning: &bq.TimePartitioning{
+					Type:  "DAY",
+					Field: "timestamp",
+				},
+			}).Context(ctx).Do()
+			return err
+		})
+		// The table may have been created concurrently.
+		if err != nil && !isCode(err, http.StatusConflict) {
+			return errors.Wrap(err, errCreateTable)
+		}
+		err = nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetTable)
+	}
+	s.ensured = true
+	return nil
+}
+
+// insert inserts events in a single request.
+func (s *Sink) insert(ctx context.Context, events []model.MCPGVKEvent) error {
+	req := &bq.TableDataInsertAllRequest{Rows: make([]*bq.TableDataInsertAllRequestRows, len(events))}
+	for i, e := range events {
+		req.Rows[i] = &bq.TableDataInsertAllRequestRows{
+			InsertId: insertID(e),
+			Json:     row(e),
+		}
+	}
+	var res *bq.TableDataInsertAllResponse
+	err := s.retry(ctx, func() error {
+		var err error
+		res, err = s.svc.Tabledata.InsertAll(s.table.Project, s.table.Dataset, s.table.Table, req).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, errInsertRows)
+	}
+	if len(res.InsertErrors) > 0 {
+		msg := ""
+		for _, ie := range res.InsertErrors {
+			if len(ie.Errors) > 0 && ie.Errors[0].Message != "" {
+				msg = ie.Errors[0].Message
+				break
+			}
+		}
+		return errors.Errorf(errFmtInsertRows, len(res.InsertErrors), len(events), msg)
+	}
+	return nil
+}
+
+// retry calls fn until it succeeds, returns an error that is not transient,
+// or has been attempted the configured number of times.
+func (s *Sink) retry(ctx context.Context, fn func() error) error {
+	wait := s.backoff
+	var err error
+	for i := 0; i < s.attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+		if err = fn(); err == nil || !transient(err) {
+			return err
+		}
+	}
+	return errors.Wrapf(err, errFmtGiveUpRetry, s.attempts)
+}
+
+// transient returns true if a request that failed with err may succeed if
+// retried.
+func transient(err error) bool {
+	gerr := &googleapi.Error{}
+	if !errors.As(err, &gerr) {
+		// Errors without a response, e.g. connection resets, are retried
+		// unless the context is done.
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch gerr.Code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isCode returns true if err is a BigQuery API error with the supplied HTTP
+// status code.
+func isCode(err error, code int) bool {
+	gerr := &googleapi.Error{}
+	return errors.As(err, &gerr) && gerr.Code == code
+}
+
+// row returns the row for an event.
+func row(e model.MCPGVKEvent) map[string]bq.JsonValue {
+	return map[string]bq.JsonValue{
+		"name":                   e.Name,
+		"upbound_account":        e.Tags.UpboundAccount,
+		"mcp_id":                 e.Tags.MCPID,
+		"customresource_group":   e.Tags.Group,
+		"customresource_version": e.Tags.Version,
+		"customresource_kind":    e.Tags.Kind,
+		"timestamp":              e.Timestamp.UTC().Format(time.RFC3339Nano),
+		"timestamp_end":          e.TimestampEnd.UTC().Format(time.RFC3339Nano),
+		"value":                  e.Value,
+	}
+}
+
+// insertID returns an ID that identifies an event by everything but its
+// value, so that an event collected twice is only inserted once.
+func insertID(e model.MCPGVKEvent) string {
+	h := sha256.New()
+	for _, s := range []string{
+		e.Name,
+		e.Tags.UpboundAccount,
+		e.Tags.MCPID,
+		e.Tags.Group,
+		e.Tags.Version,
+		e.Tags.Kind,
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		e.TimestampEnd.UTC().Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
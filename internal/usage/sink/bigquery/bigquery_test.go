@@ -0,0 +1,221 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+const (
+	getTable    = "GET /projects/p/datasets/d/tables/t"
+	createTable = "POST /projects/p/datasets/d/tables"
+	insertAll   = "POST /projects/p/datasets/d/tables/t/insertAll"
+)
+
+// response is a canned response to a request.
+type response struct {
+	status int
+	body   string
+}
+
+func TestSend(t *testing.T) {
+	events := []model.MCPGVKEvent{
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "a"}, Value: 1},
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "b"}, Value: 2},
+	}
+	ok := response{status: http.StatusOK, body: `{}`}
+	type want struct {
+		requests []string
+		err      error
+	}
+	cases := map[string]struct {
+		reason    string
+		maxRows   int
+		responses map[string][]response
+		want      want
+	}{
+		"ExistingTable": {
+			reason:  "Events should be inserted into an existing table.",
+			maxRows: 10,
+			responses: map[string][]response{
+				getTable:  {ok},
+				insertAll: {ok},
+			},
+			want: want{
+				requests: []string{getTable, insertAll},
+			},
+		},
+		"CreateTable": {
+			reason:  "A missing table should be created before events are inserted.",
+			maxRows: 10,
+			responses: map[string][]response{
+				getTable:    {{status: http.StatusNotFound, body: `{"error":{"code":404,"message":"not found"}}`}},
+				createTable: {ok},
+				insertAll:   {ok},
+			},
+			want: want{
+				requests: []string{getTable, createTable, insertAll},
+			},
+		},
+		"Batches": {
+			reason:  "Events should be inserted in batches of at most the maximum number of rows.",
+			maxRows: 1,
+			responses: map[string][]response{
+				getTable:  {ok},
+				insertAll: {ok, ok},
+			},
+			want: want{
+				requests: []string{getTable, insertAll, insertAll},
+			},
+		},
+		"RetryTransientError": {
+			reason:  "Requests that fail with a transient error should be retried.",
+			maxRows: 10,
+			responses: map[string][]response{
+				getTable:  {ok},
+				insertAll: {{status: http.StatusServiceUnavailable, body: `{"error":{"code":503,"message":"unavailable"}}`}, ok},
+			},
+			want: want{
+				requests: []string{getTable, insertAll, insertAll},
+			},
+		},
+		"RowErrors": {
+			reason:  "Rows that could not be inserted should be returned as an error.",
+			maxRows: 10,
+			responses: map[string][]response{
+				getTable:  {ok},
+				insertAll: {{status: http.StatusOK, body: `{"insertErrors":[{"index":1,"errors":[{"reason":"invalid","message":"boom"}]}]}`}},
+			},
+			want: want{
+				requests: []string{getTable, insertAll},
+				err:      errors.Errorf(errFmtInsertRows, 1, 2, "boom"),
+			},
+		},
+		"PermanentError": {
+			reason:  "Requests that fail with a permanent error should not be retried.",
+			maxRows: 10,
+			responses: map[string][]response{
+				getTable:  {ok},
+				insertAll: {{status: http.StatusBadRequest, body: `{"error":{"code":400,"message":"bad"}}`}},
+			},
+			want: want{
+				requests: []string{getTable, insertAll},
+				err:      errors.Wrap(errors.New("googleapi: Error 400: bad"), errInsertRows),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []string{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req := fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+				got = append(got, req)
+				res := response{status: http.StatusInternalServerError}
+				if rs := tc.responses[req]; len(rs) > 0 {
+					res, tc.responses[req] = rs[0], rs[1:]
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(res.status)
+				fmt.Fprint(w, res.body)
+			}))
+			defer srv.Close()
+
+			svc, err := bq.NewService(context.Background(), option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewSink(svc, Table{Project: "p", Dataset: "d", Table: "t"}, WithMaxRows(tc.maxRows), WithRetry(3, time.Millisecond))
+			err = s.Send(context.Background(), events)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want requests, +got requests:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	type want struct {
+		table Table
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Valid": {
+			reason: "A table of the form project.dataset.table should be parsed.",
+			s:      "p.d.t",
+			want:   want{table: Table{Project: "p", Dataset: "d", Table: "t"}},
+		},
+		"MissingProject": {
+			reason: "A table without a project should return an error.",
+			s:      "d.t",
+			want:   want{err: errors.Errorf(errFmtParseTable, "d.t")},
+		},
+		"EmptySegment": {
+			reason: "A table with an empty segment should return an error.",
+			s:      "p..t",
+			want:   want{err: errors.Errorf(errFmtParseTable, "p..t")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseTable(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseTable(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.table, got); diff != "" {
+				t.Errorf("\n%s\nParseTable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInsertID(t *testing.T) {
+	e := model.MCPGVKEvent{
+		Name:         "max_resource_count_per_gvk_per_mcp",
+		Tags:         model.MCPGVKEventTags{MCPID: "a", Kind: "Bucket"},
+		Timestamp:    time.Date(2006, 5, 4, 3, 0, 0, 0, time.UTC),
+		TimestampEnd: time.Date(2006, 5, 4, 4, 0, 0, 0, time.UTC),
+		Value:        1,
+	}
+	again := e
+	again.Value = 2
+	if insertID(e) != insertID(again) {
+		t.Errorf("insertID(...): events differing only in value should have the same ID")
+	}
+	other := e
+	other.Tags.MCPID = "b"
+	if insertID(e) == insertID(other) {
+		t.Errorf("insertID(...): events for different control planes should have different IDs")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kafkarest provides a usage event sink that produces events to a
+// Kafka topic through the v2 API of a Kafka REST Proxy. It does not speak the
+// Kafka protocol, so a REST Proxy must be deployed in front of the cluster.
+package kafkarest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	uphttp "github.com/upbound/up/internal/http"
+	"github.com/upbound/up/internal/usage/model"
+)
+
+const (
+	// contentType is the content type of the REST Proxy v2 JSON embedded
+	// format.
+	contentType = "application/vnd.kafka.json.v2+json"
+
+	errEncodeEvents   = "unable to encode usage events"
+	errSendEvents     = "unable to produce usage events through the Kafka REST Proxy"
+	errFmtSendCode    = "Kafka REST Proxy returned status %d"
+	errDecodeResponse = "unable to decode Kafka REST Proxy response"
+	errFmtProduce     = "failed to produce %d of %d usage events: %s"
+)
+
+// record is a single Kafka record in the REST Proxy v2 format.
+type record struct {
+	Key   string            `json:"key"`
+	Value model.MCPGVKEvent `json:"value"`
+}
+
+// produceRequest is the body of a REST Proxy v2 produce request.
+type produceRequest struct {
+	Records []record `json:"records"`
+}
+
+// produceResponse is the body of a REST Proxy v2 produce response. Records
+// that could not be produced have an error set.
+type produceResponse struct {
+	Offsets []struct {
+		Error string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Sink produces usage events to a Kafka topic through a Kafka REST Proxy, one
+// record per event. Records are keyed by control plane ID so that events for a
+// control plane are kept in order within a partition.
+type Sink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// Option modifies a Sink.
+type Option func(*Sink)
+
+// WithClient overrides the HTTP client used to produce events.
+func WithClient(c *http.Client) Option {
+	return func(s *Sink) {
+		s.client = c
+	}
+}
+
+// WithHeaders sets headers, e.g. Authorization, on each request.
+func WithHeaders(h map[string]string) Option {
+	return func(s *Sink) {
+		s.headers = h
+	}
+}
+
+// NewSink constructs a Sink that produces events to the topic through the
+// REST Proxy at the supplied base URL.
+func NewSink(proxyURL, topic string, opts ...Option) *Sink {
+	s := &Sink{
+		client: uphttp.NewClient(),
+		url:    strings.TrimSuffix(proxyURL, "/") + "/topics/" + url.PathEscape(topic),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Send produces a batch of events.
+func (s *Sink) Send(ctx context.Context, events []model.MCPGVKEvent) error {
+	pr := produceRequest{Records: make([]record, len(events))}
+	for i, e := range events {
+		pr.Records[i] = record{Key: e.Tags.MCPID, Value: e}
+	}
+	b, err := json.Marshal(pr)
+	if err != nil {
+		return errors.Wrap(err, errEncodeEvents)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf(errFmtSendCode, res.StatusCode)
+	}
+	pres := produceResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&pres); err != nil {
+		return errors.Wrap(err, errDecodeResponse)
+	}
+	failed := 0
+	msg := ""
+	for _, o := range pres.Offsets {
+		if o.Error != "" {
+			failed++
+			msg = o.Error
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf(errFmtProduce, failed, len(events), msg)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkarest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestSend(t *testing.T) {
+	events := []model.MCPGVKEvent{
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "a"}, Value: 1},
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "b"}, Value: 2},
+	}
+	cases := map[string]struct {
+		reason   string
+		status   int
+		response string
+		want     error
+	}{
+		"Success": {
+			reason:   "Events should be produced as records keyed by control plane ID.",
+			status:   http.StatusOK,
+			response: `{"offsets":[{"partition":0,"offset":1},{"partition":1,"offset":1}]}`,
+		},
+		"RecordError": {
+			reason:   "Records that could not be produced should be returned as an error.",
+			status:   http.StatusOK,
+			response: `{"offsets":[{"partition":0,"offset":1},{"error_code":50002,"error":"boom"}]}`,
+			want:     errors.Errorf(errFmtProduce, 1, 2, "boom"),
+		},
+		"ErrorStatus": {
+			reason: "A non-2xx status should be returned as an error.",
+			status: http.StatusNotFound,
+			want:   errors.Errorf(errFmtSendCode, http.StatusNotFound),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := produceRequest{}
+			var path, ct string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, ct = r.URL.Path, r.Header.Get("Content-Type")
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.response)
+			}))
+			defer srv.Close()
+
+			err := NewSink(srv.URL+"/", "usage").Send(context.Background(), events)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			want := produceRequest{Records: []record{{Key: "a", Value: events[0]}, {Key: "b", Value: events[1]}}}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want records, +got records:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("/topics/usage", path); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want path, +got path:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(contentType, ct); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want content type, +got content type:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
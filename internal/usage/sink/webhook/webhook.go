@@ -0,0 +1,94 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides a usage event sink that sends batches of events to
+// an HTTP endpoint.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	uphttp "github.com/upbound/up/internal/http"
+	"github.com/upbound/up/internal/usage/model"
+)
+
+const (
+	errEncodeEvents = "unable to encode usage events"
+	errSendEvents   = "unable to send usage events to webhook"
+	errFmtSendCode  = "webhook returned status %d"
+)
+
+// Sink sends batches of usage events to a webhook as a JSON array in the body
+// of a POST request.
+type Sink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// Option modifies a Sink.
+type Option func(*Sink)
+
+// WithClient overrides the HTTP client used to send events.
+func WithClient(c *http.Client) Option {
+	return func(s *Sink) {
+		s.client = c
+	}
+}
+
+// WithHeaders sets headers, e.g. Authorization, on each request.
+func WithHeaders(h map[string]string) Option {
+	return func(s *Sink) {
+		s.headers = h
+	}
+}
+
+// NewSink constructs a Sink that sends events to the supplied URL.
+func NewSink(url string, opts ...Option) *Sink {
+	s := &Sink{client: uphttp.NewClient(), url: url}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Send sends a batch of events.
+func (s *Sink) Send(ctx context.Context, events []model.MCPGVKEvent) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(err, errEncodeEvents)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errSendEvents)
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf(errFmtSendCode, res.StatusCode)
+	}
+	return nil
+}
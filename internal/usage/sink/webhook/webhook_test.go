@@ -0,0 +1,77 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+func TestSend(t *testing.T) {
+	events := []model.MCPGVKEvent{
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "a"}, Value: 1},
+		{Name: "max_resource_count_per_gvk_per_mcp", Tags: model.MCPGVKEventTags{MCPID: "b"}, Value: 2},
+	}
+	cases := map[string]struct {
+		reason string
+		status int
+		want   error
+	}{
+		"Success": {
+			reason: "Events should be sent as a JSON array with the configured headers.",
+			status: http.StatusAccepted,
+		},
+		"ErrorStatus": {
+			reason: "A non-2xx status should be returned as an error.",
+			status: http.StatusBadGateway,
+			want:   errors.Errorf(errFmtSendCode, http.StatusBadGateway),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []model.MCPGVKEvent
+			var auth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			s := NewSink(srv.URL, WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+			err := s.Send(context.Background(), events)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(events, got); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("Bearer token", auth); diff != "" {
+				t.Errorf("\n%s\nSend(...): -want header, +got header:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
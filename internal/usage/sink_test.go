@@ -0,0 +1,53 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/up/internal/usage/model"
+)
+
+type recordingSink struct {
+	batches [][]model.MCPGVKEvent
+}
+
+func (s *recordingSink) Send(_ context.Context, events []model.MCPGVKEvent) error {
+	s.batches = append(s.batches, append([]model.MCPGVKEvent{}, events...))
+	return nil
+}
+
+func TestBatchWriter(t *testing.T) {
+	e := func(v float64) model.MCPGVKEvent {
+		return model.MCPGVKEvent{Value: v}
+	}
+	s := &recordingSink{}
+	w := NewBatchWriter(context.Background(), s, 2)
+	for _, v := range []float64{1, 2, 3} {
+		if err := w.Write(e(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]model.MCPGVKEvent{{e(1), e(2)}, {e(3)}}
+	if diff := cmp.Diff(want, s.batches); diff != "" {
+		t.Errorf("BatchWriter: -want batches, +got batches:\n%s", diff)
+	}
+}
@@ -22,3 +22,16 @@ type TimeRange struct {
 	Start time.Time `json:"start"`
 	End   time.Time `json:"end"`
 }
+
+// Progress is the progress of reading usage data for a time range, which is
+// read in windows.
+type Progress struct {
+	WindowsCompleted int
+	WindowsTotal     int
+	BytesRead        int64
+	EventsDecoded    int64
+}
+
+// A ProgressFunc is called with the progress of reading usage data each time
+// a window has been read.
+type ProgressFunc func(Progress)
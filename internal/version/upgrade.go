@@ -0,0 +1,147 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	binURLFmt = "https://cli.upbound.io/%s/%s/bin/%s_%s/%s"
+
+	errFmtDownload         = "unable to download %s"
+	errFmtDownloadCode     = "download of %s returned status %d"
+	errInvalidChecksum     = "published checksum is not a valid SHA-256 digest"
+	errFmtChecksumMismatch = "checksum of downloaded binary %s does not match published checksum %s"
+	errReplaceBinary       = "unable to replace up binary"
+)
+
+// Download downloads the up binary of the supplied version for the current
+// platform from the Informer's channel and verifies it against its published
+// SHA-256 checksum. The checksum is served by the same host as the binary, so
+// it only guards against corrupted downloads. No signature is verified.
+func (i *Informer) Download(ctx context.Context, version string) ([]byte, error) {
+	url := i.binaryURL(version, runtime.GOOS, runtime.GOARCH)
+	bin, err := i.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := i.fetch(ctx, url+".sha256")
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(bin, sum); err != nil {
+		return nil, err
+	}
+	return bin, nil
+}
+
+func (i *Informer) binaryURL(version, goos, goarch string) string {
+	bin := "up"
+	if goos == "windows" {
+		bin = "up.exe"
+	}
+	c := i.channel
+	if c == "" {
+		c = ChannelStable
+	}
+	return fmt.Sprintf(binURLFmt, c, version, goos, goarch, bin)
+}
+
+func (i *Informer) fetch(ctx context.Context, url string) ([]byte, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtDownload, url)
+	}
+	resp, err := i.client.Do(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtDownload, url)
+	}
+	defer resp.Body.Close() // nolint:gosec,errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtDownloadCode, url, resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	return b, errors.Wrapf(err, errFmtDownload, url)
+}
+
+// verifyChecksum verifies that the SHA-256 digest of b matches the published
+// checksum, which may be followed by a file name as in sha256sum output.
+func verifyChecksum(b, published []byte) error {
+	fields := strings.Fields(string(published))
+	if len(fields) == 0 {
+		return errors.New(errInvalidChecksum)
+	}
+	want, err := hex.DecodeString(fields[0])
+	if err != nil || len(want) != sha256.Size {
+		return errors.New(errInvalidChecksum)
+	}
+	got := sha256.Sum256(b)
+	if !bytes.Equal(got[:], want) {
+		return errors.Errorf(errFmtChecksumMismatch, hex.EncodeToString(got[:]), fields[0])
+	}
+	return nil
+}
+
+// ReplaceExecutable replaces the binary at path with the supplied binary. The
+// new binary is written alongside the existing one and renamed into place so
+// that a failed upgrade does not leave a partially written binary behind.
+func ReplaceExecutable(path string, bin []byte) error {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return errors.Wrap(err, errReplaceBinary)
+	}
+	mode := os.FileMode(0755)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".up-upgrade-*")
+	if err != nil {
+		return errors.Wrap(err, errReplaceBinary)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+	if _, err := tmp.Write(bin); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, errReplaceBinary)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, errReplaceBinary)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return errors.Wrap(err, errReplaceBinary)
+	}
+	// NOTE: a running executable cannot be overwritten on Windows, but it can
+	// be renamed, so the existing binary is moved aside first.
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return errors.Wrap(err, errReplaceBinary)
+		}
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), errReplaceBinary)
+}
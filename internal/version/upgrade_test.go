@@ -0,0 +1,135 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	bin := []byte("up")
+	// SHA-256 of "up" and of "upx".
+	actual := "75a288c0d6898c5f7b054590845978a82a3ad79fcce3d43ff68a7501e5a91ee9"
+	sum := "7f196630cc8f69902d7370f94978deda7808b8a4c9a249cba8307cff9bb8ad7d"
+
+	type args struct {
+		bin       []byte
+		published []byte
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Match": {
+			reason: "A binary matching the published checksum should be accepted.",
+			args: args{
+				bin:       bin,
+				published: []byte(actual + "\n"),
+			},
+		},
+		"MatchWithFileName": {
+			reason: "A checksum in sha256sum format should be accepted.",
+			args: args{
+				bin:       bin,
+				published: []byte(actual + "  up\n"),
+			},
+		},
+		"Mismatch": {
+			reason: "A binary that does not match the published checksum should be rejected.",
+			args: args{
+				bin:       bin,
+				published: []byte(sum),
+			},
+			want: errors.Errorf(errFmtChecksumMismatch, actual, sum),
+		},
+		"Invalid": {
+			reason: "A published checksum that is not a SHA-256 digest should be rejected.",
+			args: args{
+				bin:       bin,
+				published: []byte("<html>not found</html>"),
+			},
+			want: errors.New(errInvalidChecksum),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := verifyChecksum(tc.args.bin, tc.args.published)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nverifyChecksum(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBinaryURL(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		i      *Informer
+		goos   string
+		want   string
+	}{
+		"DefaultChannel": {
+			reason: "The stable channel should be used if none is set.",
+			i:      &Informer{},
+			goos:   "linux",
+			want:   "https://cli.upbound.io/stable/v0.20.0/bin/linux_amd64/up",
+		},
+		"Windows": {
+			reason: "Windows binaries should have an exe extension.",
+			i:      &Informer{channel: ChannelBeta},
+			goos:   "windows",
+			want:   "https://cli.upbound.io/beta/v0.20.0/bin/windows_amd64/up.exe",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.i.binaryURL("v0.20.0", tc.goos, "amd64")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbinaryURL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "up")
+	if err := os.WriteFile(path, []byte("old"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceExecutable(path, []byte("new")); err != nil {
+		t.Fatalf("ReplaceExecutable(...): %v", err)
+	}
+	b, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("new", string(b)); diff != "" {
+		t.Errorf("ReplaceExecutable(...): -want, +got:\n%s", diff)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(os.FileMode(0700), fi.Mode().Perm()); diff != "" {
+		t.Errorf("ReplaceExecutable(...): -want mode, +got mode:\n%s", diff)
+	}
+}
@@ -23,13 +23,19 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 )
 
 const (
 	// 5 seconds should be more than enough time.
 	clientTimeout = 5 * time.Second
-	cliURL        = "https://cli.upbound.io/stable/current/version"
+	cliURLFmt     = "https://cli.upbound.io/%s/current/version"
+
+	// ChannelStable is the release channel for stable versions of up.
+	ChannelStable = "stable"
+	// ChannelBeta is the release channel for pre-release versions of up.
+	ChannelBeta = "beta"
 
 	errFailedToQueryRemoteFmt = "query to %s failed"
 	errInvalidLocalVersion    = "invalid local version detected"
@@ -55,15 +61,17 @@ type defaultClient struct {
 // Informer enables the caller to determine if they can upgrade their current
 // version of up.
 type Informer struct {
-	client client
-	log    logging.Logger
+	client  client
+	log     logging.Logger
+	channel string
 }
 
 // NewInformer constructs a new Informer.
 func NewInformer(opts ...Option) *Informer {
 	i := &Informer{
-		log:    logging.NewNopLogger(),
-		client: newClient(),
+		log:     logging.NewNopLogger(),
+		client:  newClient(),
+		channel: ChannelStable,
 	}
 
 	for _, o := range opts {
@@ -83,6 +91,14 @@ func WithLogger(l logging.Logger) Option {
 	}
 }
 
+// WithChannel overrides the release channel the Informer queries. Defaults to
+// the stable channel.
+func WithChannel(c string) Option {
+	return func(i *Informer) {
+		i.channel = c
+	}
+}
+
 // CanUpgrade queries locally for the version of up, uses the Informer's client
 // to check what the currently published version of up is and returns the local
 // and remote versions and whether or not we could upgrade up.
@@ -90,13 +106,26 @@ func (i *Informer) CanUpgrade(ctx context.Context) (string, string, bool) {
 	local := GetVersion()
 	remote, err := i.getCurrent(ctx)
 	if err != nil {
-		i.log.Debug(fmt.Sprintf(errFailedToQueryRemoteFmt, cliURL), "error", err)
+		i.log.Debug(fmt.Sprintf(errFailedToQueryRemoteFmt, i.versionURL()), "error", err)
 		return "", "", false
 	}
 
 	return local, remote, i.newAvailable(local, remote)
 }
 
+// Latest returns the currently published version of up on the Informer's
+// channel.
+func (i *Informer) Latest(ctx context.Context) (string, error) {
+	v, err := i.getCurrent(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, errFailedToQueryRemoteFmt, i.versionURL())
+	}
+	if _, err := semver.NewVersion(v); err != nil {
+		return "", errors.Wrap(err, errInvalidRemoteVersion)
+	}
+	return v, nil
+}
+
 func (i *Informer) newAvailable(local, remote string) bool {
 	lv, err := semver.NewVersion(local)
 	if err != nil {
@@ -115,7 +144,7 @@ func (i *Informer) newAvailable(local, remote string) bool {
 }
 
 func (i *Informer) getCurrent(ctx context.Context) (string, error) {
-	r, err := http.NewRequestWithContext(ctx, http.MethodGet, cliURL, nil)
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, i.versionURL(), nil)
 	if err != nil {
 		return "", err
 	}
@@ -133,6 +162,14 @@ func (i *Informer) getCurrent(ctx context.Context) (string, error) {
 	return strings.Trim(string(v), "\n"), nil
 }
 
+func (i *Informer) versionURL() string {
+	c := i.channel
+	if c == "" {
+		c = ChannelStable
+	}
+	return fmt.Sprintf(cliURLFmt, c)
+}
+
 func newClient() *defaultClient {
 	return &defaultClient{
 		client: http.Client{
@@ -0,0 +1,389 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint validates the contents of a package directory and reports the
+// problems it finds, so that they can be caught before a package is built.
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/golang/tools/lsp/protocol"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apimachyaml "k8s.io/apimachinery/pkg/util/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+
+	"github.com/upbound/up/internal/xpkg"
+	"github.com/upbound/up/internal/xpkg/snapshot"
+	"github.com/upbound/up/internal/xpkg/workspace"
+)
+
+// Severity of a finding.
+type Severity string
+
+// Severities of findings.
+const (
+	// SeverityError findings make the package invalid or unusable.
+	SeverityError Severity = "error"
+	// SeverityWarning findings should be fixed, but do not stop the package
+	// from working.
+	SeverityWarning Severity = "warning"
+)
+
+// Rules that produce findings.
+const (
+	// RuleSyntax findings are files that are not valid YAML.
+	RuleSyntax = "syntax"
+	// RuleSchema findings are objects that do not match their schema,
+	// including XRD and CRD schemas and example manifests that do not match
+	// the XRD or CRD that defines them.
+	RuleSchema = "schema"
+	// RuleMeta findings are problems with the package metadata in
+	// crossplane.yaml.
+	RuleMeta = "meta"
+	// RuleObjectKind findings are objects that packages of the kind cannot
+	// contain.
+	RuleObjectKind = "object-kind"
+	// RuleCompositionRef findings are Compositions that reference a kind of
+	// composite resource that is not defined.
+	RuleCompositionRef = "composition-ref"
+)
+
+const (
+	metaGroup = "meta.pkg.crossplane.io"
+
+	errNoMeta             = "no package metadata found, crossplane.yaml must contain a Configuration or Provider"
+	errFmtSyntax          = "document %d is not valid YAML: %s"
+	errFmtManyMeta        = "document %d defines package metadata, but a package must contain exactly one Configuration or Provider"
+	errFmtConstraints     = "Crossplane version constraint %q is not a valid semantic version range"
+	errFmtNoAnnotation    = "package metadata should set the %s annotation"
+	errFmtObjectKind      = "%s packages cannot contain %s objects"
+	errFmtNotUnstructured = "%T is not unstructured"
+	errFmtNoCompositeXRD  = "Composition references %s, which is not defined by an XRD in the package or its dependencies"
+)
+
+// recommendedAnnotations are the annotations that package metadata should set
+// so that the package is presented well in registries.
+var recommendedAnnotations = []string{
+	"meta.crossplane.io/maintainer",
+	"meta.crossplane.io/source",
+	"meta.crossplane.io/license",
+	"meta.crossplane.io/description",
+}
+
+// allowedKinds are the kinds of object that each kind of package may contain.
+var allowedKinds = map[string]map[schema.GroupKind]bool{
+	pkgmetav1.ConfigurationKind: {
+		{Group: "apiextensions.crossplane.io", Kind: xpextv1.CompositeResourceDefinitionKind}: true,
+		{Group: "apiextensions.crossplane.io", Kind: xpextv1.CompositionKind}:                 true,
+	},
+	pkgmetav1.ProviderKind: {
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:               true,
+		{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   true,
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: true,
+	},
+}
+
+// A Finding is a problem found in a package.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	// File in which the problem was found, relative to the package root.
+	File string `json:"file"`
+	// Line and Column at which the problem was found. They are zero if the
+	// position is not known.
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Linter lints a package directory.
+type Linter struct {
+	root string
+	fs   afero.Fs
+	m    snapshot.DepManager
+}
+
+// Option modifies a Linter.
+type Option func(*Linter)
+
+// WithFS overrides the filesystem the package directory is read from.
+func WithFS(fs afero.Fs) Option {
+	return func(l *Linter) {
+		l.fs = fs
+	}
+}
+
+// WithDepManager overrides the dependency manager used to fetch the
+// dependencies of the package, against which its compositions are validated.
+func WithDepManager(m snapshot.DepManager) Option {
+	return func(l *Linter) {
+		l.m = m
+	}
+}
+
+// New returns a Linter for the package in the supplied directory.
+func New(root string, opts ...Option) *Linter {
+	l := &Linter{
+		root: root,
+		fs:   afero.NewOsFs(),
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Lint lints the package and returns its findings, sorted by file and
+// position.
+func (l *Linter) Lint(ctx context.Context) ([]Finding, error) {
+	ws, err := workspace.New(l.root, workspace.WithFS(l.fs), workspace.WithPermissiveParser())
+	if err != nil {
+		return nil, err
+	}
+	fopts := []snapshot.FactoryOption{}
+	if l.m != nil {
+		fopts = append(fopts, snapshot.WithDepManager(l.m))
+	}
+	f, err := snapshot.NewFactory(l.root, fopts...)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := f.New(ctx, snapshot.WithWorkspace(ws))
+	if err != nil {
+		return nil, err
+	}
+	diags, err := snap.ValidateAllFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for uri, ds := range diags {
+		for _, d := range ds {
+			findings = append(findings, Finding{
+				Severity: severity(d.Severity),
+				Rule:     RuleSchema,
+				File:     l.relative(uri.Filename()),
+				Line:     int(d.Range.Start.Line) + 1,
+				Column:   int(d.Range.Start.Character) + 1,
+				Message:  d.Message,
+			})
+		}
+	}
+	findings = append(findings, l.lintFiles(ws.View())...)
+	findings = append(findings, l.lintObjects(ws.View(), snap)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return findings, nil
+}
+
+// lintFiles reports files that are not valid YAML and package metadata that is
+// defined more than once, both of which the workspace skips.
+func (l *Linter) lintFiles(v *workspace.View) []Finding {
+	findings := []Finding{}
+	metas := []Finding{}
+	for uri, d := range v.FileDetails() {
+		file := l.relative(uri.Filename())
+		yr := apimachyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(d.Body)))
+		for i := 1; ; i++ {
+			b, err := yr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err == nil {
+				b, err = k8syaml.YAMLToJSON(b)
+			}
+			if err != nil {
+				findings = append(findings, Finding{
+					Severity: SeverityError,
+					Rule:     RuleSyntax,
+					File:     file,
+					Message:  fmt.Sprintf(errFmtSyntax, i, err),
+				})
+				break
+			}
+			u := &unstructured.Unstructured{}
+			if err := u.UnmarshalJSON(b); err != nil || u.GroupVersionKind().Group != metaGroup {
+				continue
+			}
+			metas = append(metas, Finding{
+				Severity: SeverityError,
+				Rule:     RuleMeta,
+				File:     file,
+				Message:  fmt.Sprintf(errFmtManyMeta, i),
+			})
+		}
+	}
+	if len(metas) > 1 {
+		findings = append(findings, metas...)
+	}
+	return findings
+}
+
+// lintObjects reports problems with the package metadata, the kinds of objects
+// in the package, and the composite resources Compositions reference.
+func (l *Linter) lintObjects(v *workspace.View, snap *snapshot.Snapshot) []Finding {
+	var meta workspace.Node
+	objects := []workspace.Node{}
+	for _, d := range v.FileDetails() {
+		for id := range d.NodeIDs {
+			n, ok := v.Nodes()[id]
+			if !ok {
+				continue
+			}
+			if n.GetGVK().Group == metaGroup {
+				meta = n
+				continue
+			}
+			objects = append(objects, n)
+		}
+	}
+	if meta == nil {
+		return []Finding{{
+			Severity: SeverityError,
+			Rule:     RuleMeta,
+			File:     xpkg.MetaFile,
+			Message:  errNoMeta,
+		}}
+	}
+	findings := l.lintMeta(meta)
+
+	allowed := allowedKinds[meta.GetGVK().Kind]
+	for _, n := range objects {
+		// Examples may be of any kind.
+		if isExample(l.relative(n.GetFileName())) {
+			continue
+		}
+		gvk := n.GetGVK()
+		if allowed != nil && !allowed[gvk.GroupKind()] {
+			findings = append(findings, l.finding(SeverityError, RuleObjectKind, n, fmt.Sprintf(errFmtObjectKind, meta.GetGVK().Kind, gvk.GroupKind())))
+			continue
+		}
+		if gvk.Kind != xpextv1.CompositionKind {
+			continue
+		}
+		comp := &xpextv1.Composition{}
+		if err := fromUnstructured(n.GetObject(), comp); err != nil {
+			continue
+		}
+		ref := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind)
+		if snap.Validator(ref) == nil {
+			findings = append(findings, l.finding(SeverityError, RuleCompositionRef, n, fmt.Sprintf(errFmtNoCompositeXRD, ref)))
+		}
+	}
+	return findings
+}
+
+// lintMeta reports problems with the package metadata.
+func (l *Linter) lintMeta(n workspace.Node) []Finding {
+	findings := []Finding{}
+	u, ok := n.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return findings
+	}
+	if c, ok, _ := unstructured.NestedString(u.Object, "spec", "crossplane", "version"); ok {
+		o := &pkgmetav1.Configuration{}
+		o.Spec.Crossplane = &pkgmetav1.CrossplaneConstraints{Version: c}
+		if err := xpkg.PackageValidSemver(o); err != nil {
+			findings = append(findings, l.finding(SeverityError, RuleMeta, n, fmt.Sprintf(errFmtConstraints, c)))
+		}
+	}
+	for _, a := range recommendedAnnotations {
+		if u.GetAnnotations()[a] == "" {
+			findings = append(findings, l.finding(SeverityWarning, RuleMeta, n, fmt.Sprintf(errFmtNoAnnotation, a)))
+		}
+	}
+	return findings
+}
+
+// finding returns a finding positioned at the supplied node.
+func (l *Linter) finding(s Severity, rule string, n workspace.Node, msg string) Finding {
+	f := Finding{
+		Severity: s,
+		Rule:     rule,
+		File:     l.relative(n.GetFileName()),
+		Message:  msg,
+	}
+	if line, col, ok := position(n.GetAST()); ok {
+		f.Line, f.Column = line, col
+	}
+	return f
+}
+
+func (l *Linter) relative(path string) string {
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// position returns the line and column at which a YAML node starts.
+func position(n ast.Node) (int, int, bool) {
+	if n == nil || n.GetToken() == nil {
+		return 0, 0, false
+	}
+	if m, ok := n.(*ast.MappingNode); ok && len(m.Values) > 0 {
+		// The token of a block mapping is its first key.
+		n = m.Values[0].Key
+	}
+	p := n.GetToken().Position
+	return p.Line, p.Column, true
+}
+
+// isExample returns true if the file is in an examples directory, matching
+// how the workspace identifies example claims.
+func isExample(path string) bool {
+	return strings.Contains(filepath.Dir(path), "example")
+}
+
+func severity(s protocol.DiagnosticSeverity) Severity {
+	if s == protocol.SeverityWarning {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+func fromUnstructured(o runtime.Object, into any) error {
+	u, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return errors.Errorf(errFmtNotUnstructured, o)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, into)
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/upbound/up/internal/xpkg/dep/cache"
+	"github.com/upbound/up/internal/xpkg/dep/manager"
+)
+
+const (
+	testMeta = `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: platform
+  annotations:
+    meta.crossplane.io/maintainer: Acme
+    meta.crossplane.io/source: github.com/acme/platform
+    meta.crossplane.io/license: Apache-2.0
+    meta.crossplane.io/description: Acme platform
+spec:
+  crossplane:
+    version: ">=v1.13.0"
+`
+	testXRD = `apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xbuckets.acme.io
+spec:
+  group: acme.io
+  names:
+    kind: XBucket
+    plural: xbuckets
+  claimNames:
+    kind: Bucket
+    plural: buckets
+  versions:
+  - name: v1alpha1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              region:
+                type: string
+            required:
+            - region
+`
+	testComposition = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xbuckets.acme.io
+spec:
+  compositeTypeRef:
+    apiVersion: acme.io/v1alpha1
+    kind: XBucket
+  resources: []
+`
+	testExample = `apiVersion: acme.io/v1alpha1
+kind: Bucket
+metadata:
+  name: example
+  namespace: default
+spec:
+  region: us-west-1
+`
+)
+
+func TestLint(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		files  map[string]string
+		want   []Finding
+	}{
+		"Valid": {
+			reason: "A valid package should have no findings.",
+			files: map[string]string{
+				"crossplane.yaml":           testMeta,
+				"apis/definition.yaml":      testXRD,
+				"apis/composition.yaml":     testComposition,
+				"examples/bucket.yaml":      testExample,
+				"examples/not-a-claim.yaml": "# nothing to see here\n",
+			},
+			want: []Finding{},
+		},
+		"NoMeta": {
+			reason: "A package without metadata should be reported.",
+			files: map[string]string{
+				"apis/definition.yaml": testXRD,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleMeta, File: "crossplane.yaml", Message: errNoMeta},
+			},
+		},
+		"ManyMeta": {
+			reason: "Package metadata that is defined more than once should be reported.",
+			files: map[string]string{
+				"crossplane.yaml":      testMeta,
+				"more/crossplane.yaml": testMeta,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleMeta, File: "crossplane.yaml", Message: "document 1 defines package metadata, but a package must contain exactly one Configuration or Provider"},
+				{Severity: SeverityError, Rule: RuleMeta, File: "more/crossplane.yaml", Message: "document 1 defines package metadata, but a package must contain exactly one Configuration or Provider"},
+			},
+		},
+		"BadMeta": {
+			reason: "Invalid version constraints and missing annotations should be reported.",
+			files: map[string]string{
+				"crossplane.yaml": `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: platform
+  annotations:
+    meta.crossplane.io/maintainer: Acme
+    meta.crossplane.io/source: github.com/acme/platform
+    meta.crossplane.io/license: Apache-2.0
+spec:
+  crossplane:
+    version: "not a version"
+`,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleMeta, File: "crossplane.yaml", Line: 1, Column: 1, Message: `Crossplane version constraint "not a version" is not a valid semantic version range`},
+				{Severity: SeverityWarning, Rule: RuleMeta, File: "crossplane.yaml", Line: 1, Column: 1, Message: "package metadata should set the meta.crossplane.io/description annotation"},
+			},
+		},
+		"Syntax": {
+			reason: "Files that are not valid YAML should be reported.",
+			files: map[string]string{
+				"crossplane.yaml":      testMeta,
+				"apis/definition.yaml": "data: ok\n---\ndata: [a\n",
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleSyntax, File: "apis/definition.yaml", Message: "document 2 is not valid YAML: yaml: line 1: did not find expected ',' or ']'"},
+			},
+		},
+		"ObjectKind": {
+			reason: "Objects that a Configuration cannot contain should be reported.",
+			files: map[string]string{
+				"crossplane.yaml": testMeta,
+				"apis/secret.yaml": `apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleObjectKind, File: "apis/secret.yaml", Line: 1, Column: 1, Message: "Configuration packages cannot contain Secret objects"},
+				{Severity: SeverityWarning, Rule: RuleSchema, File: "apis/secret.yaml", Line: 1, Column: 13, Message: "no definition found for resource (/v1, Kind=Secret)"},
+			},
+		},
+		"CompositionRef": {
+			reason: "Compositions that reference an undefined composite resource should be reported.",
+			files: map[string]string{
+				"crossplane.yaml":       testMeta,
+				"apis/composition.yaml": testComposition,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleCompositionRef, File: "apis/composition.yaml", Line: 1, Column: 1, Message: "Composition references acme.io/v1alpha1, Kind=XBucket, which is not defined by an XRD in the package or its dependencies"},
+			},
+		},
+		"InvalidExample": {
+			reason: "Examples that do not match the schema of their XRD should be reported.",
+			files: map[string]string{
+				"crossplane.yaml":       testMeta,
+				"apis/definition.yaml":  testXRD,
+				"apis/composition.yaml": testComposition,
+				"examples/bucket.yaml": `apiVersion: acme.io/v1alpha1
+kind: Bucket
+metadata:
+  name: example
+spec:
+  region: 42
+`,
+			},
+			want: []Finding{
+				{Severity: SeverityError, Rule: RuleSchema, File: "examples/bucket.yaml", Line: 6, Column: 11, Message: "spec.region in body must be of type string: \"number\" (acme.io/v1alpha1, Kind=Bucket)"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = fs.MkdirAll("/pkg", os.ModePerm)
+			for f, body := range tc.files {
+				_ = afero.WriteFile(fs, "/pkg/"+f, []byte(body), os.ModePerm)
+			}
+			got, err := New("/pkg", WithFS(fs), WithDepManager(&mockDepManager{})).Lint(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nLint(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLint(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type mockDepManager struct{}
+
+func (m *mockDepManager) View(context.Context, []v1beta1.Dependency) (*manager.View, error) {
+	return &manager.View{}, nil
+}
+
+func (m *mockDepManager) Versions(context.Context, v1beta1.Dependency) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockDepManager) Watch() <-chan cache.Event {
+	return make(<-chan cache.Event)
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+type op struct {
+	kind byte
+	line string
+}
+
+// Diff returns a unified diff of the lines of want and got, or an empty string
+// if they are equal. Removed lines are those only in want.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	ops := diffLines(splitLines(want), splitLines(got))
+
+	b := &strings.Builder{}
+	// wi and gi are the line numbers of ops[i] in want and got.
+	wi, gi := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, o := range ops {
+		wi[i+1], gi[i+1] = wi[i], gi[i]
+		if o.kind != '+' {
+			wi[i+1]++
+		}
+		if o.kind != '-' {
+			gi[i+1]++
+		}
+	}
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Grow the hunk until it is followed by more unchanged lines than
+		// would be shown as context on both sides of the next change.
+		start := max(i-contextLines, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+				continue
+			}
+			if j-end >= 2*contextLines {
+				break
+			}
+		}
+		end = min(end+contextLines, len(ops))
+		fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", wi[start]+1, wi[end]-wi[start], gi[start]+1, gi[end]-gi[start])
+		for _, o := range ops[start:end] {
+			fmt.Fprintf(b, "%c%s\n", o.kind, o.line)
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// diffLines returns the edit script that turns a into b, computed from their
+// longest common subsequence.
+func diffLines(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
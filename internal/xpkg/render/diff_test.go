@@ -0,0 +1,60 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	type args struct {
+		want string
+		got  string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Equal": {
+			reason: "Equal inputs should have no diff.",
+			args:   args{want: "a\nb\n", got: "a\nb\n"},
+			want:   "",
+		},
+		"Added": {
+			reason: "Lines only in got should be added.",
+			args:   args{want: "a\n", got: "a\nb\n"},
+			want:   "@@ -1,1 +1,2 @@\n a\n+b\n",
+		},
+		"SeparateHunks": {
+			reason: "Changes far apart should be shown in separate hunks with context.",
+			args: args{
+				want: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+				got:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			},
+			want: "@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Diff(tc.args.want, tc.args.got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+
+	icomposite "github.com/crossplane/crossplane/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/xcrd"
+)
+
+const (
+	// defaultFunctionTimeout is the timeout Crossplane applies to functions
+	// that do not specify one.
+	defaultFunctionTimeout = 20 * time.Second
+
+	errFmtFunctionType    = "function %s is not a container function"
+	errFmtRunFunction     = "cannot run function %s"
+	errFmtParseFunctionIO = "cannot parse the FunctionIO returned by function %s"
+	errFmtFunctionFatal   = "function %s returned a fatal result: %s"
+	errFmtDocker          = "docker run failed: %s"
+	errRunDocker          = "cannot run docker"
+	errMarshalFunctionIO  = "cannot marshal FunctionIO"
+	errFmtRenderDesired   = "cannot render desired resource %s"
+)
+
+// A FunctionRunner runs a Composition Function packaged as an OCI image. It
+// passes the supplied FunctionIO to the function on stdin and returns the
+// FunctionIO the function writes to stdout.
+type FunctionRunner interface {
+	RunFunction(ctx context.Context, fn xpextv1.ContainerFunction, in []byte) ([]byte, error)
+}
+
+// A FunctionRunnerFn is a function that satisfies FunctionRunner.
+type FunctionRunnerFn func(ctx context.Context, fn xpextv1.ContainerFunction, in []byte) ([]byte, error)
+
+// RunFunction runs the supplied function.
+func (f FunctionRunnerFn) RunFunction(ctx context.Context, fn xpextv1.ContainerFunction, in []byte) ([]byte, error) {
+	return f(ctx, fn, in)
+}
+
+// DockerRunner runs Composition Functions in containers with the docker CLI.
+// Functions have no network access unless their network policy is Runner, and
+// are killed after their timeout, as when Crossplane runs them.
+type DockerRunner struct {
+	path string
+}
+
+// NewDockerRunner returns a DockerRunner that runs the docker binary in PATH.
+func NewDockerRunner() *DockerRunner {
+	return &DockerRunner{path: "docker"}
+}
+
+// RunFunction runs the supplied function in a container.
+func (r *DockerRunner) RunFunction(ctx context.Context, fn xpextv1.ContainerFunction, in []byte) ([]byte, error) {
+	timeout := defaultFunctionTimeout
+	if fn.Timeout != nil {
+		timeout = fn.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := []string{"run", "--rm", "--interactive"}
+	if fn.Network == nil || fn.Network.Policy == nil || *fn.Network.Policy != xpextv1.ContainerFunctionNetworkPolicyRunner {
+		args = append(args, "--network=none")
+	}
+	if fn.ImagePullPolicy != nil {
+		switch *fn.ImagePullPolicy {
+		case corev1.PullAlways:
+			args = append(args, "--pull=always")
+		case corev1.PullNever:
+			args = append(args, "--pull=never")
+		case corev1.PullIfNotPresent:
+			args = append(args, "--pull=missing")
+		}
+	}
+	args = append(args, fn.Image)
+
+	cmd := exec.CommandContext(ctx, r.path, args...)
+	cmd.Stdin = bytes.NewReader(in)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return nil, errors.Errorf(errFmtDocker, strings.TrimSpace(stderr.String()))
+	case err != nil:
+		return nil, errors.Wrap(err, errRunDocker)
+	}
+	return out, nil
+}
+
+// runFunctions runs the function pipeline of the supplied Composition, starting
+// from the supplied desired resources, and returns the resources the pipeline
+// desires. As when Crossplane runs the pipeline, the composite resource is
+// both observed and desired, and each function receives the resources desired
+// by the function before it. No composed resources are observed, as none exist.
+func runFunctions(ctx context.Context, r FunctionRunner, comp *xpextv1.Composition, xr *composite.Unstructured, desired []iov1alpha1.DesiredResource) ([]iov1alpha1.DesiredResource, error) {
+	raw, err := json.Marshal(xr)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalFunctionIO)
+	}
+	fnio := &iov1alpha1.FunctionIO{
+		Observed: iov1alpha1.Observed{Composite: iov1alpha1.ObservedComposite{Resource: runtime.RawExtension{Raw: raw}}},
+		Desired: iov1alpha1.Desired{
+			Composite: iov1alpha1.DesiredComposite{Resource: runtime.RawExtension{Raw: raw}},
+			Resources: desired,
+		},
+	}
+	fnio.SetGroupVersionKind(iov1alpha1.FunctionIOGroupVersionKind)
+
+	for _, fn := range comp.Spec.Functions {
+		if fn.Type != xpextv1.FunctionTypeContainer || fn.Container == nil {
+			return nil, errors.Errorf(errFmtFunctionType, fn.Name)
+		}
+		fnio.Config = fn.Config
+		fnio.Results = nil
+		in, err := k8syaml.Marshal(fnio)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalFunctionIO)
+		}
+		out, err := r.RunFunction(ctx, *fn.Container, in)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRunFunction, fn.Name)
+		}
+		next := &iov1alpha1.FunctionIO{}
+		if err := k8syaml.Unmarshal(out, next); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseFunctionIO, fn.Name)
+		}
+		for _, res := range next.Results {
+			if res.Severity == iov1alpha1.SeverityFatal {
+				return nil, errors.Errorf(errFmtFunctionFatal, fn.Name, res.Message)
+			}
+		}
+		// Functions may only change what is desired.
+		fnio.Desired = next.Desired
+	}
+	return fnio.Desired.Resources, nil
+}
+
+// renderDesired returns the supplied desired resource with the metadata
+// Crossplane renders for the composed resources of the supplied composite
+// resource.
+func renderDesired(xr *composite.Unstructured, d iov1alpha1.DesiredResource) (*composed.Unstructured, error) {
+	cd := composed.New()
+	if err := json.Unmarshal(d.Resource.Raw, cd); err != nil {
+		return nil, errors.Wrapf(err, errFmtRenderDesired, d.Name)
+	}
+	if cd.GetName() == "" {
+		cd.SetGenerateName(xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed] + "-")
+	}
+	meta.AddLabels(cd, map[string]string{
+		xcrd.LabelKeyNamePrefixForComposed: xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed],
+		xcrd.LabelKeyClaimName:             xr.GetLabels()[xcrd.LabelKeyClaimName],
+		xcrd.LabelKeyClaimNamespace:        xr.GetLabels()[xcrd.LabelKeyClaimNamespace],
+	})
+	icomposite.SetCompositionResourceName(cd, d.Name)
+	or := meta.AsController(meta.TypedReferenceTo(xr, xr.GetObjectKind().GroupVersionKind()))
+	if err := meta.AddControllerReference(cd, or); err != nil {
+		return nil, errors.Wrapf(err, errFmtRenderDesired, d.Name)
+	}
+	return cd, nil
+}
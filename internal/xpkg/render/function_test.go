@@ -0,0 +1,106 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestDockerRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("docker is stubbed with a shell script")
+	}
+	// The stub prints its arguments followed by its stdin, or fails if its
+	// stdin is empty.
+	dir := t.TempDir()
+	stub := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\nin=$(cat)\n[ -n \"$in\" ] || { echo 'no input' >&2; exit 1; }\necho \"$@\"\necho \"$in\"\n"
+	if err := os.WriteFile(stub, []byte(script), 0o755); err != nil { //nolint:gosec // The stub must be executable.
+		t.Fatal(err)
+	}
+	runner := xpextv1.ContainerFunctionNetworkPolicyRunner
+	always := corev1.PullAlways
+
+	type args struct {
+		fn xpextv1.ContainerFunction
+		in string
+	}
+	type want struct {
+		out string
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Isolated": {
+			reason: "Functions should have no network access by default.",
+			args: args{
+				fn: xpextv1.ContainerFunction{Image: "example.org/fn:v1"},
+				in: "input",
+			},
+			want: want{
+				out: "run --rm --interactive --network=none example.org/fn:v1\ninput\n",
+			},
+		},
+		"RunnerNetwork": {
+			reason: "Functions with the Runner network policy should share the network of docker.",
+			args: args{
+				fn: xpextv1.ContainerFunction{
+					Image:           "example.org/fn:v1",
+					ImagePullPolicy: &always,
+					Network:         &xpextv1.ContainerFunctionNetwork{Policy: &runner},
+				},
+				in: "input",
+			},
+			want: want{
+				out: "run --rm --interactive --pull=always example.org/fn:v1\ninput\n",
+			},
+		},
+		"Failed": {
+			reason: "The stderr of a failed function should be returned.",
+			args: args{
+				fn: xpextv1.ContainerFunction{Image: "example.org/fn:v1"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDocker, "no input"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &DockerRunner{path: stub}
+			out, err := r.RunFunction(context.Background(), tc.args.fn, []byte(tc.args.in))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, string(out)); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render renders the Compositions of a package directory against its
+// example composite resources and claims, and compares the composed resources
+// they produce to golden files. Compositions that use Composition Functions
+// are rendered by running their function containers.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apimachyaml "k8s.io/apimachinery/pkg/util/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+
+	icomposite "github.com/crossplane/crossplane/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/xcrd"
+)
+
+// GoldenSuffix is the suffix of the golden file for an example file. The
+// golden file for examples/bucket.yaml is examples/bucket.golden.yaml.
+const GoldenSuffix = ".golden.yaml"
+
+const (
+	errFmtReadFile          = "cannot read %s"
+	errFmtParseFile         = "cannot parse %s"
+	errFmtWriteGolden       = "cannot write golden file %s"
+	errFmtNoComposition     = "no Composition for %s"
+	errFmtManyCompositions  = "%d Compositions for %s, select one with spec.compositionRef"
+	errFmtCompositionNotFnd = "Composition %s does not exist"
+	errFmtRenderResource    = "cannot render resource %s"
+	errFmtRenderExample     = "cannot render %s %s"
+	errConfigure            = "cannot configure composite resource"
+	errCompose              = "cannot compose resources"
+	errMarshal              = "cannot marshal composed resource"
+	errGoldenMissing        = "golden file does not exist"
+	errGoldenMismatch       = "rendered resources do not match golden file"
+)
+
+// Status of a test case.
+type Status string
+
+// Statuses of test cases.
+const (
+	// StatusPass cases rendered the resources in their golden file.
+	StatusPass Status = "pass"
+	// StatusFail cases could not be rendered, or rendered resources that
+	// differ from their golden file.
+	StatusFail Status = "fail"
+	// StatusUpdated cases had their golden file written with the resources
+	// they rendered.
+	StatusUpdated Status = "updated"
+)
+
+// A Result is the outcome of rendering one example file.
+type Result struct {
+	Example string `json:"example"`
+	Golden  string `json:"golden"`
+	Status  Status `json:"status"`
+	Message string `json:"message,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// Tester renders the examples of a package directory and compares them to
+// their golden files.
+type Tester struct {
+	fs       afero.Fs
+	runner   FunctionRunner
+	root     string
+	examples string
+	update   bool
+}
+
+// Option modifies a Tester.
+type Option func(*Tester)
+
+// WithFS sets the filesystem the package directory is read from.
+func WithFS(fs afero.Fs) Option {
+	return func(t *Tester) {
+		t.fs = fs
+	}
+}
+
+// WithFunctionRunner sets the runner of Composition Functions.
+func WithFunctionRunner(r FunctionRunner) Option {
+	return func(t *Tester) {
+		t.runner = r
+	}
+}
+
+// WithUpdate causes the Tester to write the resources it renders to golden
+// files rather than comparing them.
+func WithUpdate(update bool) Option {
+	return func(t *Tester) {
+		t.update = update
+	}
+}
+
+// New constructs a Tester for the package directory at root, whose examples
+// are in the examples directory.
+func New(root, examples string, opts ...Option) *Tester {
+	t := &Tester{
+		fs:       afero.NewOsFs(),
+		runner:   NewDockerRunner(),
+		root:     root,
+		examples: examples,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
+}
+
+// Run renders every example file that contains a composite resource or claim
+// defined by the package, and returns one Result per file, ordered by path.
+func (t *Tester) Run(ctx context.Context) ([]Result, error) {
+	pkgFiles, err := t.files(t.root, true)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{}
+	for _, path := range pkgFiles {
+		objs, err := t.read(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range objs {
+			if err := p.add(u); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	exampleFiles, err := t.files(t.examples, false)
+	if err != nil {
+		return nil, err
+	}
+	results := []Result{}
+	for _, path := range exampleFiles {
+		res, ok, err := t.test(ctx, p, path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// test renders the example file at path and compares the result to its golden
+// file. It returns false if the file contains no composite resources or
+// claims.
+func (t *Tester) test(ctx context.Context, p *pkg, path string) (Result, bool, error) {
+	golden := strings.TrimSuffix(path, filepath.Ext(path)) + GoldenSuffix
+	res := Result{
+		Example: t.relative(path),
+		Golden:  t.relative(golden),
+	}
+
+	objs, err := t.read(path)
+	if err != nil {
+		return res, false, err
+	}
+	var out []byte
+	found := false
+	for _, u := range objs {
+		xrd, ok := p.definition(u.GroupVersionKind().GroupKind())
+		if !ok {
+			continue
+		}
+		found = true
+		b, err := p.render(ctx, t.runner, xrd, u)
+		if err != nil {
+			res.Status = StatusFail
+			res.Message = errors.Wrapf(err, errFmtRenderExample, u.GetKind(), u.GetName()).Error()
+			return res, true, nil
+		}
+		out = append(out, b...)
+	}
+	if !found {
+		return res, false, nil
+	}
+
+	if t.update {
+		if err := afero.WriteFile(t.fs, golden, out, 0o644); err != nil {
+			return res, false, errors.Wrapf(err, errFmtWriteGolden, res.Golden)
+		}
+		res.Status = StatusUpdated
+		return res, true, nil
+	}
+
+	want, err := afero.ReadFile(t.fs, golden)
+	switch {
+	case os.IsNotExist(err):
+		res.Status = StatusFail
+		res.Message = errGoldenMissing
+		return res, true, nil
+	case err != nil:
+		return res, false, errors.Wrapf(err, errFmtReadFile, res.Golden)
+	}
+	res.Status = StatusPass
+	if d := Diff(string(want), string(out)); d != "" {
+		res.Status = StatusFail
+		res.Message = errGoldenMismatch
+		res.Diff = d
+	}
+	return res, true, nil
+}
+
+// files returns the YAML files under dir, skipping golden files, or none if
+// dir does not exist. If skipExamples is true the examples directory is
+// skipped too.
+func (t *Tester) files(dir string, skipExamples bool) ([]string, error) {
+	files := []string{}
+	if ok, err := afero.DirExists(t.fs, dir); err != nil || !ok {
+		return files, err
+	}
+	err := afero.Walk(t.fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if skipExamples && path == t.examples {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" || strings.HasSuffix(path, GoldenSuffix) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+// read returns the objects in the YAML file at path.
+func (t *Tester) read(path string) ([]*unstructured.Unstructured, error) {
+	b, err := afero.ReadFile(t.fs, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadFile, t.relative(path))
+	}
+	objs := []*unstructured.Unstructured{}
+	yr := apimachyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+	for {
+		doc, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseFile, t.relative(path))
+		}
+		u := &unstructured.Unstructured{}
+		if err := k8syaml.Unmarshal(doc, &u.Object); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseFile, t.relative(path))
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
+
+func (t *Tester) relative(path string) string {
+	rel, err := filepath.Rel(t.root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// pkg holds the XRDs and Compositions of a package.
+type pkg struct {
+	xrds  []xpextv1.CompositeResourceDefinition
+	comps []xpextv1.Composition
+}
+
+func (p *pkg) add(u *unstructured.Unstructured) error {
+	gvk := u.GroupVersionKind()
+	if gvk.Group != xpextv1.Group {
+		return nil
+	}
+	switch gvk.Kind {
+	case xpextv1.CompositeResourceDefinitionKind:
+		xrd := xpextv1.CompositeResourceDefinition{}
+		if err := fromUnstructured(u, &xrd); err != nil {
+			return err
+		}
+		p.xrds = append(p.xrds, xrd)
+	case xpextv1.CompositionKind:
+		comp := xpextv1.Composition{}
+		if err := fromUnstructured(u, &comp); err != nil {
+			return err
+		}
+		p.comps = append(p.comps, comp)
+	}
+	return nil
+}
+
+// definition returns the XRD that defines the supplied kind of composite
+// resource or claim.
+func (p *pkg) definition(gk schema.GroupKind) (xpextv1.CompositeResourceDefinition, bool) {
+	for _, xrd := range p.xrds {
+		if xrd.Spec.Group != gk.Group {
+			continue
+		}
+		if xrd.Spec.Names.Kind == gk.Kind || (xrd.OffersClaim() && xrd.Spec.ClaimNames.Kind == gk.Kind) {
+			return xrd, true
+		}
+	}
+	return xpextv1.CompositeResourceDefinition{}, false
+}
+
+// render composes the resources of the supplied composite resource or claim
+// and returns them as a YAML stream. Composition Functions are run with the
+// supplied runner after the resources of the Composition are composed.
+func (p *pkg) render(ctx context.Context, r FunctionRunner, xrd xpextv1.CompositeResourceDefinition, u *unstructured.Unstructured) ([]byte, error) {
+	xr := &composite.Unstructured{Unstructured: *u.DeepCopy()}
+	if u.GetKind() != xrd.Spec.Names.Kind {
+		var err error
+		if xr, err = fromClaim(xrd, u); err != nil {
+			return nil, err
+		}
+	}
+
+	comp, err := p.composition(xrd, xr)
+	if err != nil {
+		return nil, err
+	}
+	cfg := icomposite.NewConfiguratorChain(icomposite.NewAPINamingConfigurator(), icomposite.NewAPIConfigurator())
+	if err := cfg.Configure(ctx, xr, comp); err != nil {
+		return nil, errors.Wrap(err, errConfigure)
+	}
+	cds, err := icomposite.NewPTComposer().Compose(ctx, xr, icomposite.CompositionRequest{Composition: comp})
+	if err != nil {
+		return nil, errors.Wrap(err, errCompose)
+	}
+
+	resources := make([]any, 0, len(cds))
+	for _, cd := range cds {
+		if cd.TemplateRenderErr != nil {
+			return nil, errors.Wrapf(cd.TemplateRenderErr, errFmtRenderResource, cd.ResourceName)
+		}
+		resources = append(resources, cd.Resource)
+	}
+	if len(comp.Spec.Functions) > 0 {
+		if resources, err = functionResources(ctx, r, comp, xr, cds); err != nil {
+			return nil, err
+		}
+	}
+
+	var out []byte
+	for _, res := range resources {
+		b, err := k8syaml.Marshal(res)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshal)
+		}
+		out = append(out, "---\n"...)
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// functionResources runs the function pipeline of the supplied Composition,
+// passing it the supplied composed resources as desired, and returns the
+// resources the pipeline desires.
+func functionResources(ctx context.Context, r FunctionRunner, comp *xpextv1.Composition, xr *composite.Unstructured, cds []icomposite.ComposedResourceState) ([]any, error) {
+	desired := make([]iov1alpha1.DesiredResource, 0, len(cds))
+	for _, cd := range cds {
+		raw, err := json.Marshal(cd.Resource)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshal)
+		}
+		desired = append(desired, iov1alpha1.DesiredResource{Name: cd.ResourceName, Resource: runtime.RawExtension{Raw: raw}})
+	}
+	desired, err := runFunctions(ctx, r, comp, xr, desired)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]any, 0, len(desired))
+	for _, d := range desired {
+		cd, err := renderDesired(xr, d)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, cd)
+	}
+	return resources, nil
+}
+
+// composition selects the Composition for the supplied composite resource in
+// the same order Crossplane does: by reference, by selector, by the XRD's
+// default, and finally by being the only compatible Composition.
+func (p *pkg) composition(xrd xpextv1.CompositeResourceDefinition, xr *composite.Unstructured) (*xpextv1.Composition, error) {
+	apiVersion, kind := xr.GroupVersionKind().ToAPIVersionAndKind()
+	compatible := []*xpextv1.Composition{}
+	for i := range p.comps {
+		ref := p.comps[i].Spec.CompositeTypeRef
+		if ref.APIVersion == apiVersion && ref.Kind == kind {
+			compatible = append(compatible, &p.comps[i])
+		}
+	}
+	sort.Slice(compatible, func(i, j int) bool { return compatible[i].GetName() < compatible[j].GetName() })
+
+	name := ""
+	switch {
+	case xr.GetCompositionReference() != nil:
+		name = xr.GetCompositionReference().Name
+	case xr.GetCompositionSelector() != nil:
+		sel := xr.GetCompositionSelector().MatchLabels
+		selected := []*xpextv1.Composition{}
+		for _, c := range compatible {
+			if matches(c.GetLabels(), sel) {
+				selected = append(selected, c)
+			}
+		}
+		compatible = selected
+	case xrd.Spec.DefaultCompositionRef != nil:
+		name = xrd.Spec.DefaultCompositionRef.Name
+	}
+
+	if name != "" {
+		for _, c := range compatible {
+			if c.GetName() == name {
+				return c, nil
+			}
+		}
+		return nil, errors.Errorf(errFmtCompositionNotFnd, name)
+	}
+	switch len(compatible) {
+	case 0:
+		return nil, errors.Errorf(errFmtNoComposition, kind)
+	case 1:
+		return compatible[0], nil
+	default:
+		return nil, errors.Errorf(errFmtManyCompositions, len(compatible), kind)
+	}
+}
+
+// fromClaim returns the composite resource Crossplane would create for the
+// supplied claim. The composite resource is named after the claim, rather than
+// with a random suffix, so that its composed resources render the same way
+// every time.
+func fromClaim(xrd xpextv1.CompositeResourceDefinition, cm *unstructured.Unstructured) (*composite.Unstructured, error) {
+	xr := composite.New(composite.WithGroupVersionKind(xrd.GetCompositeGroupVersionKind()))
+	xr.SetName(cm.GetName())
+	meta.AddLabels(xr, cm.GetLabels())
+	meta.AddLabels(xr, map[string]string{
+		xcrd.LabelKeyClaimName:      cm.GetName(),
+		xcrd.LabelKeyClaimNamespace: cm.GetNamespace(),
+	})
+	meta.AddAnnotations(xr, cm.GetAnnotations())
+
+	spec, ok, err := unstructured.NestedMap(cm.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// Claims refer to connection secrets in their own namespace, so
+		// this field does not carry over to the composite resource.
+		delete(spec, "writeConnectionSecretToRef")
+		delete(spec, "resourceRef")
+		xr.Object["spec"] = spec
+	}
+	return xr, nil
+}
+
+func matches(labels, sel map[string]string) bool {
+	for k, v := range sel {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func fromUnstructured(u *unstructured.Unstructured, obj any) error {
+	b, err := u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return k8syaml.Unmarshal(b, obj)
+}
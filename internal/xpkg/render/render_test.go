@@ -0,0 +1,401 @@
+// Copyright 2023 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var (
+	xrd = `apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xbuckets.example.org
+spec:
+  group: example.org
+  names:
+    kind: XBucket
+    plural: xbuckets
+  claimNames:
+    kind: Bucket
+    plural: buckets
+  versions:
+  - name: v1alpha1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+	composition = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xbuckets.aws.example.org
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1alpha1
+    kind: XBucket
+  resources:
+  - name: bucket
+    base:
+      apiVersion: s3.aws.upbound.io/v1beta1
+      kind: Bucket
+      spec:
+        forProvider:
+          region: us-east-1
+    patches:
+    - fromFieldPath: spec.region
+      toFieldPath: spec.forProvider.region
+`
+	claim = `apiVersion: example.org/v1alpha1
+kind: Bucket
+metadata:
+  name: my-bucket
+  namespace: default
+spec:
+  region: eu-west-1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: unrelated
+`
+	functions = `apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xbuckets.fn.example.org
+  labels:
+    provider: fn
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1alpha1
+    kind: XBucket
+  functions:
+  - name: render
+    type: Container
+    container:
+      image: example.org/render:v1
+    config:
+      apiVersion: example.org/v1alpha1
+      kind: Config
+      queue: my-queue
+`
+	fnGolden = `---
+apiVersion: sqs.aws.upbound.io/v1beta1
+kind: Queue
+metadata:
+  annotations:
+    crossplane.io/composition-resource-name: queue
+  generateName: my-bucket-
+  labels:
+    crossplane.io/claim-name: my-bucket
+    crossplane.io/claim-namespace: default
+    crossplane.io/composite: my-bucket
+  ownerReferences:
+  - apiVersion: example.org/v1alpha1
+    blockOwnerDeletion: true
+    controller: true
+    kind: XBucket
+    name: my-bucket
+    uid: ""
+spec:
+  forProvider:
+    name: my-queue
+    region: eu-west-1
+`
+	golden = `---
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  annotations:
+    crossplane.io/composition-resource-name: bucket
+  generateName: my-bucket-
+  labels:
+    crossplane.io/claim-name: my-bucket
+    crossplane.io/claim-namespace: default
+    crossplane.io/composite: my-bucket
+  ownerReferences:
+  - apiVersion: example.org/v1alpha1
+    blockOwnerDeletion: true
+    controller: true
+    kind: XBucket
+    name: my-bucket
+    uid: ""
+spec:
+  forProvider:
+    region: eu-west-1
+`
+)
+
+// queueFunction is a Composition Function that desires a queue named by its
+// config in the region of the composite resource.
+func queueFunction(_ context.Context, fn xpextv1.ContainerFunction, in []byte) ([]byte, error) {
+	if fn.Image != "example.org/render:v1" {
+		return nil, errors.Errorf("unexpected image %s", fn.Image)
+	}
+	fnio := &iov1alpha1.FunctionIO{}
+	if err := k8syaml.Unmarshal(in, fnio); err != nil {
+		return nil, err
+	}
+	cfg := map[string]any{}
+	if err := json.Unmarshal(fnio.Config.Raw, &cfg); err != nil {
+		return nil, err
+	}
+	xr := map[string]any{}
+	if err := json.Unmarshal(fnio.Observed.Composite.Resource.Raw, &xr); err != nil {
+		return nil, err
+	}
+	region := xr["spec"].(map[string]any)["region"]
+	raw := fmt.Sprintf(`{"apiVersion":"sqs.aws.upbound.io/v1beta1","kind":"Queue","spec":{"forProvider":{"name":%q,"region":%q}}}`, cfg["queue"], region)
+	fnio.Desired.Resources = append(fnio.Desired.Resources, iov1alpha1.DesiredResource{Name: "queue", Resource: runtime.RawExtension{Raw: []byte(raw)}})
+	return k8syaml.Marshal(fnio)
+}
+
+func TestRun(t *testing.T) {
+	type args struct {
+		files  map[string]string
+		update bool
+		runner FunctionRunner
+	}
+	type want struct {
+		results []Result
+		files   map[string]string
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Pass": {
+			reason: "A claim that renders the resources in its golden file should pass.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":         xrd,
+					"/pkg/apis/composition.yaml":        composition,
+					"/pkg/examples/bucket.yaml":         claim,
+					"/pkg/examples/bucket.golden.yaml":  golden,
+					"/pkg/examples/providerconfig.yaml": "apiVersion: aws.upbound.io/v1beta1\nkind: ProviderConfig\n",
+				},
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusPass,
+				}},
+			},
+		},
+		"Mismatch": {
+			reason: "A claim that renders different resources from its golden file should fail with a diff.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":        xrd,
+					"/pkg/apis/composition.yaml":       composition,
+					"/pkg/examples/bucket.yaml":        claim,
+					"/pkg/examples/bucket.golden.yaml": strings.Replace(golden, "eu-west-1", "us-west-2", 1),
+				},
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusFail,
+					Message: errGoldenMismatch,
+					Diff:    "@@ -18,4 +18,4 @@\n     uid: \"\"\n spec:\n   forProvider:\n-    region: us-west-2\n+    region: eu-west-1\n",
+				}},
+			},
+		},
+		"MissingGolden": {
+			reason: "A claim without a golden file should fail.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":  xrd,
+					"/pkg/apis/composition.yaml": composition,
+					"/pkg/examples/bucket.yaml":  claim,
+				},
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusFail,
+					Message: errGoldenMissing,
+				}},
+			},
+		},
+		"Update": {
+			reason: "Updating should write the rendered resources to the golden file.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":        xrd,
+					"/pkg/apis/composition.yaml":       composition,
+					"/pkg/examples/bucket.yaml":        claim,
+					"/pkg/examples/bucket.golden.yaml": "stale",
+				},
+				update: true,
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusUpdated,
+				}},
+				files: map[string]string{
+					"/pkg/examples/bucket.golden.yaml": golden,
+				},
+			},
+		},
+		"Functions": {
+			reason: "A claim should be rendered with the function pipeline of the Composition its selector matches.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":        xrd,
+					"/pkg/apis/composition.yaml":       composition + "---\n" + functions,
+					"/pkg/examples/bucket.yaml":        strings.Replace(claim, "  region: eu-west-1\n", "  region: eu-west-1\n  compositionSelector:\n    matchLabels:\n      provider: fn\n", 1),
+					"/pkg/examples/bucket.golden.yaml": fnGolden,
+				},
+				runner: FunctionRunnerFn(queueFunction),
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusPass,
+				}},
+			},
+		},
+		"FunctionFatal": {
+			reason: "A claim whose function pipeline returns a fatal result should fail.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":  xrd,
+					"/pkg/apis/composition.yaml": functions,
+					"/pkg/examples/bucket.yaml":  claim,
+				},
+				runner: FunctionRunnerFn(func(_ context.Context, _ xpextv1.ContainerFunction, in []byte) ([]byte, error) {
+					fnio := &iov1alpha1.FunctionIO{}
+					_ = k8syaml.Unmarshal(in, fnio)
+					fnio.Results = []iov1alpha1.Result{{Severity: iov1alpha1.SeverityFatal, Message: "no queue"}}
+					return k8syaml.Marshal(fnio)
+				}),
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusFail,
+					Message: "cannot render Bucket my-bucket: " + fmt.Sprintf(errFmtFunctionFatal, "render", "no queue"),
+				}},
+			},
+		},
+		"ManyCompositions": {
+			reason: "A claim that does not select one of several compatible Compositions should fail.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":  xrd,
+					"/pkg/apis/composition.yaml": composition + "---\n" + functions,
+					"/pkg/examples/bucket.yaml":  claim,
+				},
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusFail,
+					Message: "cannot render Bucket my-bucket: " + fmt.Sprintf(errFmtManyCompositions, 2, "XBucket"),
+				}},
+			},
+		},
+		"NoComposition": {
+			reason: "A claim without a compatible Composition should fail.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml": xrd,
+					"/pkg/examples/bucket.yaml": claim,
+				},
+			},
+			want: want{
+				results: []Result{{
+					Example: "examples/bucket.yaml",
+					Golden:  "examples/bucket.golden.yaml",
+					Status:  StatusFail,
+					Message: "cannot render Bucket my-bucket: " + fmt.Sprintf(errFmtNoComposition, "XBucket"),
+				}},
+			},
+		},
+		"NoExamples": {
+			reason: "A package without an examples directory should have no results.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml":  xrd,
+					"/pkg/apis/composition.yaml": composition,
+				},
+			},
+			want: want{
+				results: []Result{},
+			},
+		},
+		"InvalidYAML": {
+			reason: "A file that cannot be parsed should return an error.",
+			args: args{
+				files: map[string]string{
+					"/pkg/apis/definition.yaml": "data: [a\n",
+				},
+			},
+			want: want{
+				results: nil,
+				err:     errors.Wrapf(k8syaml.Unmarshal([]byte("data: [a\n"), &map[string]any{}), errFmtParseFile, "apis/definition.yaml"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for path, body := range tc.args.files {
+				if err := afero.WriteFile(fs, path, []byte(body), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			results, err := New("/pkg", "/pkg/examples", WithFS(fs), WithUpdate(tc.args.update), WithFunctionRunner(tc.args.runner)).Run(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.results, results); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			for path, body := range tc.want.files {
+				b, _ := afero.ReadFile(fs, path)
+				if diff := cmp.Diff(body, string(b)); diff != "" {
+					t.Errorf("\n%s\nRun(...): -want %s, +got %s:\n%s", tc.reason, path, path, diff)
+				}
+			}
+		})
+	}
+}